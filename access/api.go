@@ -31,10 +31,17 @@ type API interface {
 	GetTransactionResult(ctx context.Context, id flow.Identifier) (*TransactionResult, error)
 	GetTransactionResultByIndex(ctx context.Context, blockID flow.Identifier, index uint32) (*TransactionResult, error)
 	GetTransactionResultsByBlockID(ctx context.Context, blockID flow.Identifier) ([]*TransactionResult, error)
+	SimulateTransaction(ctx context.Context, blockID flow.Identifier, tx *flow.TransactionBody, skipSignatureVerification bool) (*TransactionSimulationResult, error)
 
 	GetAccount(ctx context.Context, address flow.Address) (*flow.Account, error)
 	GetAccountAtLatestBlock(ctx context.Context, address flow.Address) (*flow.Account, error)
 	GetAccountAtBlockHeight(ctx context.Context, address flow.Address, height uint64) (*flow.Account, error)
+	GetAccountBalanceAtLatestBlock(ctx context.Context, address flow.Address) (uint64, error)
+	GetAccountBalanceAtBlockHeight(ctx context.Context, address flow.Address, height uint64) (uint64, error)
+	GetAccountKeyAtLatestBlock(ctx context.Context, address flow.Address, keyIndex uint32) (*flow.AccountPublicKey, error)
+	GetAccountKeyAtBlockHeight(ctx context.Context, address flow.Address, keyIndex uint32, height uint64) (*flow.AccountPublicKey, error)
+	GetAccountKeysAtLatestBlock(ctx context.Context, address flow.Address) ([]flow.AccountPublicKey, error)
+	GetAccountKeysAtBlockHeight(ctx context.Context, address flow.Address, height uint64) ([]flow.AccountPublicKey, error)
 
 	ExecuteScriptAtLatestBlock(ctx context.Context, script []byte, arguments [][]byte) ([]byte, error)
 	ExecuteScriptAtBlockHeight(ctx context.Context, blockHeight uint64, script []byte, arguments [][]byte) ([]byte, error)
@@ -61,6 +68,20 @@ type TransactionResult struct {
 	BlockHeight   uint64
 }
 
+// TransactionSimulationResult is the outcome of a transaction executed against the execution state
+// at a block, without applying its state changes.
+type TransactionSimulationResult struct {
+	BlockID flow.Identifier
+	Events  []flow.Event
+	// ErrorMessage is empty if the transaction succeeded.
+	ErrorMessage    string
+	ComputationUsed uint64
+	MemoryEstimate  uint64
+	// RegistersRead and RegistersWritten are the registers touched by the transaction, sorted.
+	RegistersRead    []flow.RegisterID
+	RegistersWritten []flow.RegisterID
+}
+
 func TransactionResultToMessage(result *TransactionResult) *access.TransactionResultResponse {
 	return &access.TransactionResultResponse{
 		Status:        entities.TransactionStatus(result.Status),
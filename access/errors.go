@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/onflow/cadence"
+
 	"github.com/onflow/flow-go/model/flow"
 )
 
@@ -90,3 +92,38 @@ type InvalidTxByteSizeError struct {
 func (e InvalidTxByteSizeError) Error() string {
 	return fmt.Sprintf("transaction byte size (%d) exceeds the maximum byte size allowed for a transaction (%d)", e.Actual, e.Maximum)
 }
+
+// InvalidTxSignatureError indicates that a transaction signature could not be verified against
+// the account key at the latest sealed block.
+type InvalidTxSignatureError struct {
+	Address  flow.Address
+	KeyIndex uint64
+	Reason   string
+}
+
+func (e InvalidTxSignatureError) Error() string {
+	return fmt.Sprintf("invalid signature for key (address: %s, index: %d): %s", e.Address.String(), e.KeyIndex, e.Reason)
+}
+
+// InsufficientKeyWeightError indicates that the signatures of an authorizer or of the payer of a
+// transaction do not reach the key weight threshold.
+type InsufficientKeyWeightError struct {
+	Address   flow.Address
+	Weight    int
+	Threshold int
+}
+
+func (e InsufficientKeyWeightError) Error() string {
+	return fmt.Sprintf("account %s does not have sufficient signatures (%d < %d)", e.Address.String(), e.Weight, e.Threshold)
+}
+
+// InsufficientBalanceError indicates that the payer of a transaction does not have enough balance
+// to pay for the transaction at the latest sealed block.
+type InsufficientBalanceError struct {
+	Payer           flow.Address
+	RequiredBalance cadence.UFix64
+}
+
+func (e InsufficientBalanceError) Error() string {
+	return fmt.Sprintf("transaction payer (%s) has insufficient balance to pay transaction fee. Required balance: (%s)", e.Payer.String(), e.RequiredBalance.String())
+}
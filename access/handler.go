@@ -12,15 +12,21 @@ import (
 	"github.com/onflow/flow-go/consensus/hotstuff"
 	"github.com/onflow/flow-go/consensus/hotstuff/signature"
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	"github.com/onflow/flow-go/engine/common/rpc/extended"
 	"github.com/onflow/flow-go/model/flow"
 )
 
 type Handler struct {
+	extended.UnimplementedExtendedAccessAPIServer
+
 	api                  API
 	chain                flow.Chain
 	signerIndicesDecoder hotstuff.BlockSignerDecoder
 }
 
+var _ access.AccessAPIServer = (*Handler)(nil)
+var _ extended.ExtendedAccessAPIServer = (*Handler)(nil)
+
 // HandlerOption is used to hand over optional constructor parameters
 type HandlerOption func(*Handler)
 
@@ -350,6 +356,120 @@ func (h *Handler) GetAccountAtBlockHeight(
 	}, nil
 }
 
+// GetAccountBalanceAtLatestBlock returns the balance of an account at the latest sealed block.
+func (h *Handler) GetAccountBalanceAtLatestBlock(
+	ctx context.Context,
+	req *extended.GetAccountBalanceAtLatestBlockRequest,
+) (*extended.AccountBalanceResponse, error) {
+	address, err := convert.Address(req.GetAddress(), h.chain)
+	if err != nil {
+		return nil, err
+	}
+
+	balance, err := h.api.GetAccountBalanceAtLatestBlock(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &extended.AccountBalanceResponse{
+		Balance: balance,
+	}, nil
+}
+
+// GetAccountBalanceAtBlockHeight returns the balance of an account at the given block height.
+func (h *Handler) GetAccountBalanceAtBlockHeight(
+	ctx context.Context,
+	req *extended.GetAccountBalanceAtBlockHeightRequest,
+) (*extended.AccountBalanceResponse, error) {
+	address, err := convert.Address(req.GetAddress(), h.chain)
+	if err != nil {
+		return nil, err
+	}
+
+	balance, err := h.api.GetAccountBalanceAtBlockHeight(ctx, address, req.GetBlockHeight())
+	if err != nil {
+		return nil, err
+	}
+
+	return &extended.AccountBalanceResponse{
+		Balance: balance,
+	}, nil
+}
+
+// GetAccountKeyAtLatestBlock returns the public key with the given index of an account at the
+// latest sealed block.
+func (h *Handler) GetAccountKeyAtLatestBlock(
+	ctx context.Context,
+	req *extended.GetAccountKeyAtLatestBlockRequest,
+) (*extended.AccountKeyResponse, error) {
+	address, err := convert.Address(req.GetAddress(), h.chain)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := h.api.GetAccountKeyAtLatestBlock(ctx, address, req.GetIndex())
+	if err != nil {
+		return nil, err
+	}
+
+	return accountKeyResponse(key)
+}
+
+// GetAccountKeyAtBlockHeight returns the public key with the given index of an account at the
+// given block height.
+func (h *Handler) GetAccountKeyAtBlockHeight(
+	ctx context.Context,
+	req *extended.GetAccountKeyAtBlockHeightRequest,
+) (*extended.AccountKeyResponse, error) {
+	address, err := convert.Address(req.GetAddress(), h.chain)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := h.api.GetAccountKeyAtBlockHeight(ctx, address, req.GetIndex(), req.GetBlockHeight())
+	if err != nil {
+		return nil, err
+	}
+
+	return accountKeyResponse(key)
+}
+
+// GetAccountKeysAtLatestBlock returns the public keys of an account at the latest sealed block.
+func (h *Handler) GetAccountKeysAtLatestBlock(
+	ctx context.Context,
+	req *extended.GetAccountKeysAtLatestBlockRequest,
+) (*extended.AccountKeysResponse, error) {
+	address, err := convert.Address(req.GetAddress(), h.chain)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := h.api.GetAccountKeysAtLatestBlock(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	return accountKeysResponse(keys)
+}
+
+// GetAccountKeysAtBlockHeight returns the public keys of an account at the given block height.
+func (h *Handler) GetAccountKeysAtBlockHeight(
+	ctx context.Context,
+	req *extended.GetAccountKeysAtBlockHeightRequest,
+) (*extended.AccountKeysResponse, error) {
+	address, err := convert.Address(req.GetAddress(), h.chain)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := h.api.GetAccountKeysAtBlockHeight(ctx, address, req.GetBlockHeight())
+	if err != nil {
+		return nil, err
+	}
+
+	return accountKeysResponse(keys)
+}
+
 // ExecuteScriptAtLatestBlock executes a script at a the latest block.
 func (h *Handler) ExecuteScriptAtLatestBlock(
 	ctx context.Context,
@@ -563,6 +683,32 @@ func blockEventsToMessage(block flow.BlockEvents) (*access.EventsResponse_Result
 	}, nil
 }
 
+func accountKeyResponse(key *flow.AccountPublicKey) (*extended.AccountKeyResponse, error) {
+	keyMsg, err := convert.AccountKeyToMessage(*key)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &extended.AccountKeyResponse{
+		AccountKey: keyMsg,
+	}, nil
+}
+
+func accountKeysResponse(keys []flow.AccountPublicKey) (*extended.AccountKeysResponse, error) {
+	keyMsgs := make([]*entities.AccountKey, len(keys))
+	for i, key := range keys {
+		keyMsg, err := convert.AccountKeyToMessage(key)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		keyMsgs[i] = keyMsg
+	}
+
+	return &extended.AccountKeysResponse{
+		AccountKeys: keyMsgs,
+	}, nil
+}
+
 // WithBlockSignerDecoder configures the Handler to decode signer indices
 // via the provided hotstuff.BlockSignerDecoder
 func WithBlockSignerDecoder(signerIndicesDecoder hotstuff.BlockSignerDecoder) func(*Handler) {
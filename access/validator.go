@@ -1,18 +1,44 @@
 package access
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
-	"github.com/onflow/flow-go/crypto"
-	"github.com/onflow/flow-go/state"
-
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
 	"github.com/onflow/cadence/runtime/parser"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
+	"github.com/onflow/flow-go/crypto"
+	"github.com/onflow/flow-go/fvm/blueprints"
+	fvmcrypto "github.com/onflow/flow-go/fvm/crypto"
+	"github.com/onflow/flow-go/fvm/environment"
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/state"
 	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/utils/logging"
+)
+
+const (
+	// names of the validation checks which depend on the execution state, used in logs and metrics
+	checkSignatures   = "signatures"
+	checkPayerBalance = "payer_balance"
+
+	// accountKeyWeightThreshold is the total key weight the signatures of each authorizer and of
+	// the payer must reach, same as the threshold enforced by the FVM.
+	accountKeyWeightThreshold = 1000
 )
 
+// errSealedStateUnavailable indicates that the state required by a validation check could not be
+// read. Such checks are skipped, so transactions are not rejected because of unavailable
+// execution nodes.
+var errSealedStateUnavailable = errors.New("sealed state unavailable")
+
 type Blocks interface {
 	HeaderByID(id flow.Identifier) (*flow.Header, error)
 	FinalizedHeader() (*flow.Header, error)
@@ -43,6 +69,17 @@ func (b *ProtocolStateBlocks) FinalizedHeader() (*flow.Header, error) {
 	return b.state.Final().Head()
 }
 
+// SealedStateReader reads the execution state at the latest sealed block, for the validation
+// checks which depend on it. Both methods return a codes.NotFound status error if the account
+// does not exist.
+type SealedStateReader interface {
+	// GetAccountKeysAtLatestBlock returns the public keys of the account with the given address.
+	GetAccountKeysAtLatestBlock(ctx context.Context, address flow.Address) ([]flow.AccountPublicKey, error)
+
+	// ExecuteScriptAtLatestBlock executes the given script with the given arguments.
+	ExecuteScriptAtLatestBlock(ctx context.Context, script []byte, arguments [][]byte) ([]byte, error)
+}
+
 type TransactionValidationOptions struct {
 	Expiry                       uint
 	ExpiryBuffer                 uint
@@ -52,29 +89,80 @@ type TransactionValidationOptions struct {
 	CheckScriptsParse            bool
 	MaxTransactionByteSize       uint64
 	MaxCollectionByteSize        uint64
+
+	// CheckSignatures enables verifying the transaction signatures against the account keys at
+	// the latest sealed block. Requires a sealed state reader, see WithSealedState.
+	CheckSignatures bool
+	// CheckPayerBalance enables checking that the payer has enough balance to pay for the
+	// transaction at the latest sealed block. Requires a sealed state reader, see WithSealedState.
+	CheckPayerBalance bool
+	// StateChecksDryRun only logs and counts the transactions which fail the signature and payer
+	// balance checks, without rejecting them. Since these checks use the latest sealed state,
+	// transactions depending on account changes which are not sealed yet fail them.
+	StateChecksDryRun bool
 }
 
 type TransactionValidator struct {
-	blocks                Blocks     // for looking up blocks to check transaction expiry
-	chain                 flow.Chain // for checking validity of addresses
-	options               TransactionValidationOptions
-	serviceAccountAddress flow.Address
+	blocks                   Blocks     // for looking up blocks to check transaction expiry
+	chain                    flow.Chain // for checking validity of addresses
+	options                  TransactionValidationOptions
+	serviceAccountAddress    flow.Address
+	sealedState              SealedStateReader // for checking signatures and payer balances
+	verifyPayerBalanceScript []byte
+	metrics                  module.TransactionValidationMetrics
+	log                      zerolog.Logger
+}
+
+// TransactionValidatorOption configures optional dependencies of the transaction validator.
+type TransactionValidatorOption func(*TransactionValidator)
+
+// WithSealedState configures the validator to read the latest sealed state from the given reader,
+// for the checks enabled by the CheckSignatures and CheckPayerBalance options. Without it, these
+// checks are not performed.
+func WithSealedState(sealedState SealedStateReader) TransactionValidatorOption {
+	return func(v *TransactionValidator) {
+		v.sealedState = sealedState
+	}
+}
+
+// WithValidationMetrics configures the validator to track the checks which depend on the
+// execution state with the given metrics.
+func WithValidationMetrics(metrics module.TransactionValidationMetrics) TransactionValidatorOption {
+	return func(v *TransactionValidator) {
+		v.metrics = metrics
+	}
+}
+
+// WithValidationLogger configures the validator to log failed and skipped checks which depend on
+// the execution state with the given logger.
+func WithValidationLogger(log zerolog.Logger) TransactionValidatorOption {
+	return func(v *TransactionValidator) {
+		v.log = log
+	}
 }
 
 func NewTransactionValidator(
 	blocks Blocks,
 	chain flow.Chain,
 	options TransactionValidationOptions,
+	opts ...TransactionValidatorOption,
 ) *TransactionValidator {
-	return &TransactionValidator{
-		blocks:                blocks,
-		chain:                 chain,
-		options:               options,
-		serviceAccountAddress: chain.ServiceAddress(),
+	v := &TransactionValidator{
+		blocks:                   blocks,
+		chain:                    chain,
+		options:                  options,
+		serviceAccountAddress:    chain.ServiceAddress(),
+		verifyPayerBalanceScript: blueprints.VerifyPayerBalanceForTxExecution(environment.FlowFeesAddress(chain)),
+		metrics:                  metrics.NewNoopCollector(),
+		log:                      zerolog.Nop(),
 	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
 }
 
-func (v *TransactionValidator) Validate(tx *flow.TransactionBody) (err error) {
+func (v *TransactionValidator) Validate(ctx context.Context, tx *flow.TransactionBody) (err error) {
 	err = v.checkTxSizeLimit(tx)
 	if err != nil {
 		return err
@@ -115,11 +203,61 @@ func (v *TransactionValidator) Validate(tx *flow.TransactionBody) (err error) {
 		return err
 	}
 
-	// TODO replace checkSignatureFormat by verifying the account/payer signatures
+	if v.sealedState == nil {
+		return nil
+	}
+
+	if v.options.CheckSignatures {
+		err = v.runStateCheck(ctx, tx, checkSignatures, v.checkSignatures)
+		if err != nil {
+			return err
+		}
+	}
+
+	if v.options.CheckPayerBalance {
+		err = v.runStateCheck(ctx, tx, checkPayerBalance, v.checkSufficientBalance)
+		if err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
+// runStateCheck runs the given check which depends on the execution state. Transactions which
+// fail the check are rejected, unless in dry-run mode. The check is skipped if the state could
+// not be read.
+func (v *TransactionValidator) runStateCheck(
+	ctx context.Context,
+	tx *flow.TransactionBody,
+	name string,
+	check func(context.Context, *flow.TransactionBody) error,
+) error {
+	err := check(ctx, tx)
+	if err == nil {
+		return nil
+	}
+
+	log := v.log.With().
+		Hex("tx_id", logging.Entity(tx)).
+		Str("check", name).
+		Logger()
+
+	if errors.Is(err, errSealedStateUnavailable) {
+		v.metrics.TransactionValidationSkipped(name)
+		log.Warn().Err(err).Msg("skipped transaction validation check")
+		return nil
+	}
+
+	v.metrics.TransactionValidationFailed(name, v.options.StateChecksDryRun)
+	if v.options.StateChecksDryRun {
+		log.Info().Err(err).Msg("transaction failed validation check (dry run)")
+		return nil
+	}
+
+	return err
+}
+
 func (v *TransactionValidator) checkTxSizeLimit(tx *flow.TransactionBody) error {
 	txSize := uint64(tx.ByteSize())
 	// first check compatibility to collection byte size
@@ -293,6 +431,182 @@ func (v *TransactionValidator) checkSignatureFormat(tx *flow.TransactionBody) er
 	return nil
 }
 
+// checkSignatures verifies the transaction signatures against the account keys at the latest
+// sealed block, and checks that the proposal key signed the transaction, and that each authorizer
+// and the payer provided signatures with sufficient key weight.
+func (v *TransactionValidator) checkSignatures(ctx context.Context, tx *flow.TransactionBody) error {
+	keys := make(map[flow.Address][]flow.AccountPublicKey)
+
+	payloadWeights, err := v.verifySignatures(ctx, keys, tx.PayloadSignatures, tx.PayloadMessage())
+	if err != nil {
+		return err
+	}
+
+	envelopeWeights, err := v.verifySignatures(ctx, keys, tx.EnvelopeSignatures, tx.EnvelopeMessage())
+	if err != nil {
+		return err
+	}
+
+	if !hasSignature(tx.PayloadSignatures, tx.ProposalKey) && !hasSignature(tx.EnvelopeSignatures, tx.ProposalKey) {
+		return InvalidTxSignatureError{
+			Address:  tx.ProposalKey.Address,
+			KeyIndex: tx.ProposalKey.KeyIndex,
+			Reason:   "missing proposal key signature",
+		}
+	}
+
+	for _, address := range tx.Authorizers {
+		// the payer signs the envelope, which covers the payload
+		if address == tx.Payer {
+			continue
+		}
+		if payloadWeights[address] < accountKeyWeightThreshold {
+			return InsufficientKeyWeightError{
+				Address:   address,
+				Weight:    payloadWeights[address],
+				Threshold: accountKeyWeightThreshold,
+			}
+		}
+	}
+
+	if envelopeWeights[tx.Payer] < accountKeyWeightThreshold {
+		return InsufficientKeyWeightError{
+			Address:   tx.Payer,
+			Weight:    envelopeWeights[tx.Payer],
+			Threshold: accountKeyWeightThreshold,
+		}
+	}
+
+	return nil
+}
+
+// verifySignatures verifies the given signatures of the given message, and returns the total key
+// weight of the signatures of each account. The account keys are cached in the given map.
+func (v *TransactionValidator) verifySignatures(
+	ctx context.Context,
+	keys map[flow.Address][]flow.AccountPublicKey,
+	signatures []flow.TransactionSignature,
+	message []byte,
+) (map[flow.Address]int, error) {
+	weights := make(map[flow.Address]int)
+
+	for _, signature := range signatures {
+		accountKeys, ok := keys[signature.Address]
+		if !ok {
+			var err error
+			accountKeys, err = v.sealedState.GetAccountKeysAtLatestBlock(ctx, signature.Address)
+			if status.Code(err) == codes.NotFound {
+				return nil, InvalidTxSignatureError{
+					Address:  signature.Address,
+					KeyIndex: signature.KeyIndex,
+					Reason:   "account does not exist",
+				}
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%w: could not get keys of account %s: %v", errSealedStateUnavailable, signature.Address, err)
+			}
+			keys[signature.Address] = accountKeys
+		}
+
+		key, ok := findAccountKey(accountKeys, signature.KeyIndex)
+		if !ok {
+			return nil, InvalidTxSignatureError{
+				Address:  signature.Address,
+				KeyIndex: signature.KeyIndex,
+				Reason:   "account key does not exist",
+			}
+		}
+		if key.Revoked {
+			return nil, InvalidTxSignatureError{
+				Address:  signature.Address,
+				KeyIndex: signature.KeyIndex,
+				Reason:   "account key has been revoked",
+			}
+		}
+
+		valid, err := fvmcrypto.VerifySignatureFromTransaction(signature.Signature, message, key.PublicKey, key.HashAlgo)
+		if err != nil || !valid {
+			return nil, InvalidTxSignatureError{
+				Address:  signature.Address,
+				KeyIndex: signature.KeyIndex,
+				Reason:   "signature is not valid",
+			}
+		}
+
+		weights[signature.Address] += key.Weight
+	}
+
+	return weights, nil
+}
+
+// checkSufficientBalance checks that the payer has enough balance at the latest sealed block to
+// pay for the inclusion and the maximum execution effort of the transaction, the same way as the
+// FVM does before executing the transaction.
+func (v *TransactionValidator) checkSufficientBalance(ctx context.Context, tx *flow.TransactionBody) error {
+	payer, err := jsoncdc.Encode(cadence.BytesToAddress(tx.Payer.Bytes()))
+	if err != nil {
+		return fmt.Errorf("could not encode payer address: %w", err)
+	}
+	inclusionEffort, err := jsoncdc.Encode(cadence.UFix64(tx.InclusionEffort()))
+	if err != nil {
+		return fmt.Errorf("could not encode inclusion effort: %w", err)
+	}
+	maxExecutionEffort, err := jsoncdc.Encode(cadence.UFix64(tx.GasLimit))
+	if err != nil {
+		return fmt.Errorf("could not encode max execution effort: %w", err)
+	}
+
+	encoded, err := v.sealedState.ExecuteScriptAtLatestBlock(ctx, v.verifyPayerBalanceScript, [][]byte{payer, inclusionEffort, maxExecutionEffort})
+	if err != nil {
+		return fmt.Errorf("%w: could not execute payer balance check: %v", errSealedStateUnavailable, err)
+	}
+
+	value, err := jsoncdc.Decode(nil, encoded)
+	if err != nil {
+		return fmt.Errorf("%w: could not decode payer balance check result: %v", errSealedStateUnavailable, err)
+	}
+
+	// the result is a FlowFees.VerifyPayerBalanceResult struct
+	result, ok := value.(cadence.Struct)
+	if !ok || len(result.Fields) < 2 {
+		return fmt.Errorf("%w: unexpected payer balance check result: %v", errSealedStateUnavailable, value)
+	}
+	canExecute, okBool := result.Fields[0].(cadence.Bool)
+	requiredBalance, okBalance := result.Fields[1].(cadence.UFix64)
+	if !okBool || !okBalance {
+		return fmt.Errorf("%w: unexpected payer balance check result: %v", errSealedStateUnavailable, value)
+	}
+
+	if !canExecute {
+		return InsufficientBalanceError{
+			Payer:           tx.Payer,
+			RequiredBalance: requiredBalance,
+		}
+	}
+
+	return nil
+}
+
+// hasSignature returns whether the given signatures contain a signature of the given proposal key.
+func hasSignature(signatures []flow.TransactionSignature, proposalKey flow.ProposalKey) bool {
+	for _, signature := range signatures {
+		if signature.Address == proposalKey.Address && signature.KeyIndex == proposalKey.KeyIndex {
+			return true
+		}
+	}
+	return false
+}
+
+// findAccountKey returns the account key with the given index.
+func findAccountKey(keys []flow.AccountPublicKey, keyIndex uint64) (flow.AccountPublicKey, bool) {
+	for _, key := range keys {
+		if uint64(key.Index) == keyIndex {
+			return key, true
+		}
+	}
+	return flow.AccountPublicKey{}, false
+}
+
 func remove(s []string, r string) []string {
 	for i, v := range s {
 		if v == r {
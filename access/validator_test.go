@@ -0,0 +1,275 @@
+package access_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/access"
+	accessmock "github.com/onflow/flow-go/access/mock"
+	"github.com/onflow/flow-go/crypto/hash"
+	"github.com/onflow/flow-go/model/flow"
+	modulemock "github.com/onflow/flow-go/module/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+type TransactionValidatorSuite struct {
+	suite.Suite
+
+	chain       flow.Chain
+	sealedState *accessmock.SealedStateReader
+	metrics     *modulemock.TransactionValidationMetrics
+
+	payer         flow.Address
+	payerKey      *flow.AccountPrivateKey
+	authorizer    flow.Address
+	authorizerKey *flow.AccountPrivateKey
+}
+
+func TestTransactionValidator(t *testing.T) {
+	suite.Run(t, new(TransactionValidatorSuite))
+}
+
+func (s *TransactionValidatorSuite) SetupTest() {
+	s.chain = flow.Testnet.Chain()
+	s.sealedState = accessmock.NewSealedStateReader(s.T())
+	s.metrics = modulemock.NewTransactionValidationMetrics(s.T())
+
+	var err error
+	s.payer, err = s.chain.AddressAtIndex(10)
+	s.Require().NoError(err)
+	s.authorizer, err = s.chain.AddressAtIndex(11)
+	s.Require().NoError(err)
+	s.payerKey = s.accountKey()
+	s.authorizerKey = s.accountKey()
+}
+
+// accountKey returns a new account key.
+func (s *TransactionValidatorSuite) accountKey() *flow.AccountPrivateKey {
+	key, err := unittest.AccountKeyDefaultFixture()
+	s.Require().NoError(err)
+	// the signer requires the public key to be computed
+	key.PrivateKey.PublicKey()
+	return key
+}
+
+func (s *TransactionValidatorSuite) validator(options access.TransactionValidationOptions) *access.TransactionValidator {
+	options.AllowEmptyReferenceBlockID = true
+	options.MaxGasLimit = flow.DefaultMaxTransactionGasLimit
+	options.MaxTransactionByteSize = flow.DefaultMaxTransactionByteSize
+	options.MaxCollectionByteSize = flow.DefaultMaxCollectionByteSize
+
+	return access.NewTransactionValidator(
+		nil,
+		s.chain,
+		options,
+		access.WithSealedState(s.sealedState),
+		access.WithValidationMetrics(s.metrics),
+	)
+}
+
+// transaction returns a transaction proposed and paid by the payer, and authorized by the
+// authorizer, signed with the given keys.
+func (s *TransactionValidatorSuite) transaction(authorizerKey, payerKey *flow.AccountPrivateKey) *flow.TransactionBody {
+	tx := flow.NewTransactionBody().
+		SetScript([]byte("transaction { prepare(acc: AuthAccount) {} }")).
+		SetGasLimit(100).
+		SetProposalKey(s.payer, 0, 0).
+		SetPayer(s.payer).
+		AddAuthorizer(s.authorizer)
+
+	err := tx.SignPayload(s.authorizer, 0, authorizerKey.PrivateKey, hash.NewSHA3_256())
+	s.Require().NoError(err)
+	err = tx.SignEnvelope(s.payer, 0, payerKey.PrivateKey, hash.NewSHA3_256())
+	s.Require().NoError(err)
+
+	return tx
+}
+
+// mockAccountKeys mocks the keys of the payer and the authorizer, with the given weight.
+func (s *TransactionValidatorSuite) mockAccountKeys(weight int, revoked bool) {
+	payerKey := s.payerKey.PublicKey(weight)
+	authorizerKey := s.authorizerKey.PublicKey(weight)
+	authorizerKey.Revoked = revoked
+
+	s.sealedState.On("GetAccountKeysAtLatestBlock", mock.Anything, s.payer).
+		Return([]flow.AccountPublicKey{payerKey}, nil).Maybe()
+	s.sealedState.On("GetAccountKeysAtLatestBlock", mock.Anything, s.authorizer).
+		Return([]flow.AccountPublicKey{authorizerKey}, nil).Maybe()
+}
+
+// mockPayerBalanceResult mocks the result of the payer balance check script.
+func (s *TransactionValidatorSuite) mockPayerBalanceResult(canExecute bool) {
+	result := cadence.NewStruct([]cadence.Value{
+		cadence.NewBool(canExecute),
+		cadence.UFix64(100_000_000),
+		cadence.UFix64(1_000),
+	}).WithType(&cadence.StructType{
+		Location:            common.NewAddressLocation(nil, common.Address(s.chain.ServiceAddress()), "FlowFees"),
+		QualifiedIdentifier: "FlowFees.VerifyPayerBalanceResult",
+		Fields: []cadence.Field{
+			{Identifier: "canExecuteTransaction", Type: cadence.TheBoolType},
+			{Identifier: "requiredBalance", Type: cadence.TheUFix64Type},
+			{Identifier: "maximumTransactionFees", Type: cadence.TheUFix64Type},
+		},
+	})
+	encoded, err := jsoncdc.Encode(result)
+	s.Require().NoError(err)
+
+	s.sealedState.On("ExecuteScriptAtLatestBlock", mock.Anything, mock.Anything, mock.Anything).
+		Return(encoded, nil).Once()
+}
+
+// TestCheckSignatures_Valid tests that transactions with valid signatures are accepted.
+func (s *TransactionValidatorSuite) TestCheckSignatures_Valid() {
+	s.mockAccountKeys(1000, false)
+	v := s.validator(access.TransactionValidationOptions{CheckSignatures: true})
+
+	err := v.Validate(context.Background(), s.transaction(s.authorizerKey, s.payerKey))
+	s.Require().NoError(err)
+}
+
+// TestCheckSignatures_Invalid tests that transactions with invalid signatures are rejected.
+func (s *TransactionValidatorSuite) TestCheckSignatures_Invalid() {
+	s.mockAccountKeys(1000, false)
+	v := s.validator(access.TransactionValidationOptions{CheckSignatures: true})
+
+	s.metrics.On("TransactionValidationFailed", "signatures", false).Once()
+	err := v.Validate(context.Background(), s.transaction(s.accountKey(), s.payerKey))
+
+	var sigErr access.InvalidTxSignatureError
+	s.Require().ErrorAs(err, &sigErr)
+	s.Assert().Equal(s.authorizer, sigErr.Address)
+}
+
+// TestCheckSignatures_RevokedKey tests that transactions signed with revoked keys are rejected.
+func (s *TransactionValidatorSuite) TestCheckSignatures_RevokedKey() {
+	s.mockAccountKeys(1000, true)
+	v := s.validator(access.TransactionValidationOptions{CheckSignatures: true})
+
+	s.metrics.On("TransactionValidationFailed", "signatures", false).Once()
+	err := v.Validate(context.Background(), s.transaction(s.authorizerKey, s.payerKey))
+
+	var sigErr access.InvalidTxSignatureError
+	s.Require().ErrorAs(err, &sigErr)
+}
+
+// TestCheckSignatures_InsufficientWeight tests that transactions with signatures which don't
+// reach the key weight threshold are rejected.
+func (s *TransactionValidatorSuite) TestCheckSignatures_InsufficientWeight() {
+	s.mockAccountKeys(500, false)
+	v := s.validator(access.TransactionValidationOptions{CheckSignatures: true})
+
+	s.metrics.On("TransactionValidationFailed", "signatures", false).Once()
+	err := v.Validate(context.Background(), s.transaction(s.authorizerKey, s.payerKey))
+
+	var weightErr access.InsufficientKeyWeightError
+	s.Require().ErrorAs(err, &weightErr)
+	s.Assert().Equal(500, weightErr.Weight)
+}
+
+// TestCheckSignatures_UnknownAccount tests that transactions signed by unknown accounts are
+// rejected.
+func (s *TransactionValidatorSuite) TestCheckSignatures_UnknownAccount() {
+	s.sealedState.On("GetAccountKeysAtLatestBlock", mock.Anything, s.authorizer).
+		Return(nil, status.Error(codes.NotFound, "account not found")).Once()
+	v := s.validator(access.TransactionValidationOptions{CheckSignatures: true})
+
+	s.metrics.On("TransactionValidationFailed", "signatures", false).Once()
+	err := v.Validate(context.Background(), s.transaction(s.authorizerKey, s.payerKey))
+
+	var sigErr access.InvalidTxSignatureError
+	s.Require().ErrorAs(err, &sigErr)
+}
+
+// TestCheckSignatures_StateUnavailable tests that the check is skipped if the account keys can't
+// be read.
+func (s *TransactionValidatorSuite) TestCheckSignatures_StateUnavailable() {
+	s.sealedState.On("GetAccountKeysAtLatestBlock", mock.Anything, s.authorizer).
+		Return(nil, status.Error(codes.Unavailable, "no execution node available")).Once()
+	v := s.validator(access.TransactionValidationOptions{CheckSignatures: true})
+
+	s.metrics.On("TransactionValidationSkipped", "signatures").Once()
+	err := v.Validate(context.Background(), s.transaction(s.authorizerKey, s.payerKey))
+	s.Require().NoError(err)
+}
+
+// TestCheckSignatures_DryRun tests that transactions failing the check are accepted in dry-run
+// mode, and counted.
+func (s *TransactionValidatorSuite) TestCheckSignatures_DryRun() {
+	s.mockAccountKeys(1000, false)
+	v := s.validator(access.TransactionValidationOptions{CheckSignatures: true, StateChecksDryRun: true})
+
+	s.metrics.On("TransactionValidationFailed", "signatures", true).Once()
+	err := v.Validate(context.Background(), s.transaction(s.authorizerKey, s.accountKey()))
+	s.Require().NoError(err)
+}
+
+// TestCheckPayerBalance tests that transactions are rejected if the payer can't pay for them.
+func (s *TransactionValidatorSuite) TestCheckPayerBalance() {
+	v := s.validator(access.TransactionValidationOptions{CheckPayerBalance: true})
+	tx := s.transaction(s.authorizerKey, s.payerKey)
+
+	s.Run("sufficient balance", func() {
+		s.mockPayerBalanceResult(true)
+		err := v.Validate(context.Background(), tx)
+		s.Require().NoError(err)
+	})
+
+	s.Run("insufficient balance", func() {
+		s.mockPayerBalanceResult(false)
+		s.metrics.On("TransactionValidationFailed", "payer_balance", false).Once()
+
+		err := v.Validate(context.Background(), tx)
+
+		var balanceErr access.InsufficientBalanceError
+		s.Require().ErrorAs(err, &balanceErr)
+		s.Assert().Equal(s.payer, balanceErr.Payer)
+		s.Assert().Equal(cadence.UFix64(100_000_000), balanceErr.RequiredBalance)
+	})
+
+	s.Run("script execution failure", func() {
+		s.sealedState.On("ExecuteScriptAtLatestBlock", mock.Anything, mock.Anything, mock.Anything).
+			Return(nil, errors.New("script execution failed")).Once()
+		s.metrics.On("TransactionValidationSkipped", "payer_balance").Once()
+
+		err := v.Validate(context.Background(), tx)
+		s.Require().NoError(err)
+	})
+}
+
+// TestStateChecksDisabled tests that the state is not read if the checks are disabled.
+func TestStateChecksDisabled(t *testing.T) {
+	sealedState := accessmock.NewSealedStateReader(t)
+	chain := flow.Testnet.Chain()
+
+	v := access.NewTransactionValidator(
+		nil,
+		chain,
+		access.TransactionValidationOptions{
+			AllowEmptyReferenceBlockID: true,
+			MaxGasLimit:                flow.DefaultMaxTransactionGasLimit,
+			MaxTransactionByteSize:     flow.DefaultMaxTransactionByteSize,
+			MaxCollectionByteSize:      flow.DefaultMaxCollectionByteSize,
+		},
+		access.WithSealedState(sealedState),
+	)
+
+	tx := flow.NewTransactionBody().
+		SetScript([]byte("transaction {}")).
+		SetGasLimit(100).
+		SetProposalKey(chain.ServiceAddress(), 0, 0).
+		SetPayer(chain.ServiceAddress())
+
+	err := v.Validate(context.Background(), tx)
+	require.NoError(t, err)
+}
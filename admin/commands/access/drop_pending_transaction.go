@@ -0,0 +1,72 @@
+package access
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/engine/access/rpc/backend"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+var _ commands.AdminCommand = (*DropPendingTransactionCommand)(nil)
+
+// DropPendingTransactionCommand stops the access node from resubmitting a pending transaction to
+// collection nodes.
+type DropPendingTransactionCommand struct {
+	retry *backend.Retry
+}
+
+// NewDropPendingTransactionCommand creates a new DropPendingTransactionCommand object.
+func NewDropPendingTransactionCommand(retry *backend.Retry) *DropPendingTransactionCommand {
+	return &DropPendingTransactionCommand{
+		retry: retry,
+	}
+}
+
+func (d *DropPendingTransactionCommand) Handler(_ context.Context, req *admin.CommandRequest) (interface{}, error) {
+	txID := req.ValidatorData.(flow.Identifier)
+
+	if !d.retry.IsActive() {
+		return nil, fmt.Errorf("transaction retry is not enabled")
+	}
+
+	dropped, err := d.retry.Drop(txID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to drop pending transaction: %w", err)
+	}
+	if !dropped {
+		return nil, fmt.Errorf("transaction %v is not pending", txID)
+	}
+
+	return "OK", nil
+}
+
+// Validator validates the request.
+// Returns admin.InvalidAdminReqError for invalid/malformed requests.
+func (d *DropPendingTransactionCommand) Validator(req *admin.CommandRequest) error {
+	input, ok := req.Data.(map[string]interface{})
+	if !ok {
+		return admin.NewInvalidAdminReqFormatError("expected map[string]any")
+	}
+
+	id, ok := input["tx_id"]
+	if !ok {
+		return admin.NewInvalidAdminReqErrorf("missing required field 'tx_id'")
+	}
+
+	idStr, ok := id.(string)
+	if !ok {
+		return admin.NewInvalidAdminReqParameterError("tx_id", "must be a string", id)
+	}
+
+	txID, err := flow.HexStringToIdentifier(idStr)
+	if err != nil {
+		return admin.NewInvalidAdminReqParameterError("tx_id", "must be 64-char hex string", id)
+	}
+
+	req.ValidatorData = txID
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package access
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestDropPendingTransactionCommandParsing(t *testing.T) {
+	cmd := DropPendingTransactionCommand{}
+
+	t.Run("happy path", func(t *testing.T) {
+		txID := unittest.IdentifierFixture()
+		req := &admin.CommandRequest{
+			Data: map[string]interface{}{
+				"tx_id": txID.String(),
+			},
+		}
+
+		err := cmd.Validator(req)
+		require.NoError(t, err)
+		require.Equal(t, txID, req.ValidatorData.(flow.Identifier))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for name, data := range map[string]map[string]interface{}{
+			"missing tx id":    {},
+			"non string tx id": {"tx_id": float64(10)},
+			"non hex tx id":    {"tx_id": "xyz"},
+			"truncated tx id":  {"tx_id": unittest.IdentifierFixture().String()[:32]},
+		} {
+			err := cmd.Validator(&admin.CommandRequest{Data: data})
+			require.True(t, admin.IsInvalidAdminParameterError(err), name)
+		}
+	})
+}
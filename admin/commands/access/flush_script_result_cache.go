@@ -0,0 +1,43 @@
+package access
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/engine/access/rpc/backend"
+)
+
+var _ commands.AdminCommand = (*FlushScriptResultCacheCommand)(nil)
+
+// FlushScriptResultCacheCommand removes all cached script results.
+type FlushScriptResultCacheCommand struct {
+	cache *backend.ScriptResultCache
+}
+
+// NewFlushScriptResultCacheCommand creates a new FlushScriptResultCacheCommand object.
+// The cache may be nil if script result caching is not enabled.
+func NewFlushScriptResultCacheCommand(cache *backend.ScriptResultCache) *FlushScriptResultCacheCommand {
+	return &FlushScriptResultCacheCommand{
+		cache: cache,
+	}
+}
+
+// Handler flushes the cache, and returns the number of results removed.
+func (f *FlushScriptResultCacheCommand) Handler(_ context.Context, _ *admin.CommandRequest) (interface{}, error) {
+	if f.cache == nil {
+		return nil, fmt.Errorf("script result caching is not enabled")
+	}
+
+	flushed := f.cache.Clear()
+
+	return map[string]interface{}{
+		"flushed": float64(flushed),
+	}, nil
+}
+
+// Validator accepts any request, since the command has no inputs.
+func (f *FlushScriptResultCacheCommand) Validator(_ *admin.CommandRequest) error {
+	return nil
+}
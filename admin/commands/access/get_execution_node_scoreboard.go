@@ -0,0 +1,62 @@
+package access
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/engine/access/rpc/backend"
+)
+
+var _ commands.AdminCommand = (*GetExecutionNodeScoreboardCommand)(nil)
+
+// GetExecutionNodeScoreboardCommand reports the health of the execution nodes the access node
+// forwards requests to, as observed by the access node.
+type GetExecutionNodeScoreboardCommand struct {
+	selector *backend.ExecutionNodeSelector
+}
+
+// NewGetExecutionNodeScoreboardCommand creates a new GetExecutionNodeScoreboardCommand object.
+// The selector may be nil if health-aware execution node selection is not enabled.
+func NewGetExecutionNodeScoreboardCommand(selector *backend.ExecutionNodeSelector) *GetExecutionNodeScoreboardCommand {
+	return &GetExecutionNodeScoreboardCommand{
+		selector: selector,
+	}
+}
+
+// Handler returns the latency, error rate, suspension state and highest executed height of all
+// execution nodes requests were sent to or receipts were received from.
+func (g *GetExecutionNodeScoreboardCommand) Handler(_ context.Context, _ *admin.CommandRequest) (interface{}, error) {
+	if g.selector == nil {
+		return nil, fmt.Errorf("execution node health routing is not enabled")
+	}
+
+	now := g.selector.Now()
+	scores := g.selector.Scoreboard()
+	result := make([]interface{}, 0, len(scores))
+	for _, score := range scores {
+		var suspendedUntil interface{}
+		if score.Suspended(now) {
+			suspendedUntil = score.SuspendedUntil.UTC().String()
+		}
+
+		result = append(result, map[string]interface{}{
+			"node_id":              score.NodeID.String(),
+			"latency":              score.Latency.String(),
+			"error_rate":           score.ErrorRate,
+			"requests":             float64(score.Requests),
+			"failures":             float64(score.Failures),
+			"consecutive_failures": float64(score.ConsecutiveFailures),
+			"suspended_until":      suspendedUntil,
+			"executed_height":      float64(score.ExecutedHeight),
+		})
+	}
+
+	return result, nil
+}
+
+// Validator accepts any request, since the command has no inputs.
+func (g *GetExecutionNodeScoreboardCommand) Validator(_ *admin.CommandRequest) error {
+	return nil
+}
@@ -0,0 +1,49 @@
+package access
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/engine/access/rpc/backend"
+)
+
+var _ commands.AdminCommand = (*ListPendingTransactionsCommand)(nil)
+
+// ListPendingTransactionsCommand lists the transactions the access node resubmits to collection
+// nodes until they are included in a block or expire.
+type ListPendingTransactionsCommand struct {
+	retry *backend.Retry
+}
+
+// NewListPendingTransactionsCommand creates a new ListPendingTransactionsCommand object.
+func NewListPendingTransactionsCommand(retry *backend.Retry) *ListPendingTransactionsCommand {
+	return &ListPendingTransactionsCommand{
+		retry: retry,
+	}
+}
+
+// Handler returns the IDs and reference block heights of the pending transactions, ordered by
+// reference block height.
+func (l *ListPendingTransactionsCommand) Handler(_ context.Context, _ *admin.CommandRequest) (interface{}, error) {
+	if !l.retry.IsActive() {
+		return nil, fmt.Errorf("transaction retry is not enabled")
+	}
+
+	pending := l.retry.Pending()
+	result := make([]interface{}, 0, len(pending))
+	for _, tx := range pending {
+		result = append(result, map[string]interface{}{
+			"tx_id":            tx.TransactionID.String(),
+			"reference_height": float64(tx.ReferenceHeight),
+		})
+	}
+
+	return result, nil
+}
+
+// Validator accepts any request, since the command has no inputs.
+func (l *ListPendingTransactionsCommand) Validator(_ *admin.CommandRequest) error {
+	return nil
+}
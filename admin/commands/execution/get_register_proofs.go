@@ -0,0 +1,164 @@
+package execution
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+var _ commands.AdminCommand = (*GetRegisterProofsCommand)(nil)
+
+// MaxRegisterProofs is the maximum number of registers that can be proven by a single request
+const MaxRegisterProofs = 1000
+
+// LedgerProver reads register values and proofs of a ledger state.
+type LedgerProver interface {
+	Get(query *ledger.Query) ([]ledger.Value, error)
+	Prove(query *ledger.Query) (ledger.Proof, error)
+}
+
+// GetRegisterProofsCommand returns the values of registers at the end of a block, together with a
+// batch proof of the values against the state commitment of the block.
+// The output can be verified offline with the verify-register-proofs util command.
+type GetRegisterProofsCommand struct {
+	ledger  LedgerProver
+	commits storage.Commits
+}
+
+// NewGetRegisterProofsCommand creates a new GetRegisterProofsCommand object
+func NewGetRegisterProofsCommand(ledger LedgerProver, commits storage.Commits) *GetRegisterProofsCommand {
+	return &GetRegisterProofsCommand{
+		ledger:  ledger,
+		commits: commits,
+	}
+}
+
+type getRegisterProofsReq struct {
+	blockID   flow.Identifier
+	registers []flow.RegisterID
+}
+
+// Handler returns the values of the requested registers, empty for registers that are not set,
+// and the hex encoded batch proof of the values.
+func (c *GetRegisterProofsCommand) Handler(_ context.Context, req *admin.CommandRequest) (interface{}, error) {
+	data := req.ValidatorData.(*getRegisterProofsReq)
+
+	commit, err := c.commits.ByBlockID(data.blockID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get state commitment for block %v: %w", data.blockID, err)
+	}
+
+	keys := make([]ledger.Key, len(data.registers))
+	for i, register := range data.registers {
+		keys[i] = state.RegisterIDToKey(register)
+	}
+
+	query, err := ledger.NewQuery(ledger.State(commit), keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create query: %w", err)
+	}
+
+	values, err := c.ledger.Get(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get registers at state %v: %w", commit, err)
+	}
+
+	proof, err := c.ledger.Prove(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prove registers at state %v: %w", commit, err)
+	}
+
+	registers := make([]interface{}, len(data.registers))
+	for i, register := range data.registers {
+		registers[i] = map[string]interface{}{
+			"owner": hex.EncodeToString([]byte(register.Owner)),
+			"key":   register.Key,
+			"value": hex.EncodeToString(values[i]),
+		}
+	}
+
+	return map[string]interface{}{
+		"block_id":  data.blockID.String(),
+		"commit":    hex.EncodeToString(commit[:]),
+		"registers": registers,
+		"proof":     hex.EncodeToString(proof),
+	}, nil
+}
+
+// Validator checks the inputs for GetRegisterProofs command.
+// It expects the following fields in the Data field of the req object:
+//   - block_id, a hex encoded block ID
+//   - registers, a list of objects with an owner field, the hex encoded address of the account
+//     owning the register or an empty string for global registers, and a key field
+//
+// The following sentinel errors are expected during normal operations:
+// * `admin.InvalidAdminReqError` if any field is missing or in a wrong format
+func (c *GetRegisterProofsCommand) Validator(req *admin.CommandRequest) error {
+	input, ok := req.Data.(map[string]interface{})
+	if !ok {
+		return admin.NewInvalidAdminReqFormatError("expected map[string]any")
+	}
+
+	data := &getRegisterProofsReq{}
+
+	blockIn, ok := input["block_id"]
+	if !ok {
+		return admin.NewInvalidAdminReqErrorf("the \"block_id\" field is required")
+	}
+	block, ok := blockIn.(string)
+	if !ok {
+		return admin.NewInvalidAdminReqParameterError("block_id", "must be a 64 character long hex string", blockIn)
+	}
+	blockID, err := flow.HexStringToIdentifier(block)
+	if err != nil {
+		return admin.NewInvalidAdminReqParameterError("block_id", "must be a 64 character long hex string", blockIn)
+	}
+	data.blockID = blockID
+
+	registersIn, ok := input["registers"]
+	if !ok {
+		return admin.NewInvalidAdminReqErrorf("the \"registers\" field is required")
+	}
+	registers, ok := registersIn.([]interface{})
+	if !ok || len(registers) == 0 || len(registers) > MaxRegisterProofs {
+		return admin.NewInvalidAdminReqParameterError("registers", fmt.Sprintf("must be a list of 1 to %d registers", MaxRegisterProofs), registersIn)
+	}
+
+	data.registers = make([]flow.RegisterID, len(registers))
+	for i, registerIn := range registers {
+		register, ok := registerIn.(map[string]interface{})
+		if !ok {
+			return admin.NewInvalidAdminReqParameterError("registers", "each register must be an object with owner and key fields", registerIn)
+		}
+
+		ownerIn, ok := register["owner"]
+		if !ok {
+			return admin.NewInvalidAdminReqParameterError("registers", "each register must have an owner field", registerIn)
+		}
+		owner, err := parseHexField(ownerIn)
+		if err != nil || (len(owner) != 0 && len(owner) != flow.AddressLength) {
+			return admin.NewInvalidAdminReqParameterError("registers", "owner must be empty or a 16 character long hex string", ownerIn)
+		}
+
+		key, ok := register["key"].(string)
+		if !ok {
+			return admin.NewInvalidAdminReqParameterError("registers", "each register must have a string key field", registerIn)
+		}
+
+		data.registers[i] = flow.RegisterID{
+			Owner: string(owner),
+			Key:   key,
+		}
+	}
+
+	req.ValidatorData = data
+
+	return nil
+}
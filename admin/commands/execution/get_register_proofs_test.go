@@ -0,0 +1,140 @@
+package execution
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/proof"
+	"github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/wal/fixtures"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+	storagemock "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestGetRegisterProofsCommandParsing(t *testing.T) {
+	cmd := GetRegisterProofsCommand{}
+
+	blockID := unittest.IdentifierFixture()
+	owner := unittest.RandomAddressFixture()
+
+	t.Run("happy path", func(t *testing.T) {
+		req := &admin.CommandRequest{
+			Data: map[string]interface{}{
+				"block_id": blockID.String(),
+				"registers": []interface{}{
+					map[string]interface{}{"owner": "0x" + owner.Hex(), "key": "storage_used"},
+					map[string]interface{}{"owner": "", "key": "uuid"},
+				},
+			},
+		}
+
+		err := cmd.Validator(req)
+		require.NoError(t, err)
+
+		parsedReq := req.ValidatorData.(*getRegisterProofsReq)
+		require.Equal(t, blockID, parsedReq.blockID)
+		require.Equal(t, []flow.RegisterID{
+			flow.NewRegisterID(string(owner.Bytes()), "storage_used"),
+			{Owner: "", Key: "uuid"},
+		}, parsedReq.registers)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		validRegisters := []interface{}{map[string]interface{}{"owner": "", "key": "uuid"}}
+		tooManyRegisters := make([]interface{}, MaxRegisterProofs+1)
+		for i := range tooManyRegisters {
+			tooManyRegisters[i] = validRegisters[0]
+		}
+
+		for name, data := range map[string]map[string]interface{}{
+			"missing block ID":   {"registers": validRegisters},
+			"invalid block ID":   {"block_id": "abc", "registers": validRegisters},
+			"missing registers":  {"block_id": blockID.String()},
+			"empty registers":    {"block_id": blockID.String(), "registers": []interface{}{}},
+			"too many registers": {"block_id": blockID.String(), "registers": tooManyRegisters},
+			"invalid register":   {"block_id": blockID.String(), "registers": []interface{}{"uuid"}},
+			"missing owner":      {"block_id": blockID.String(), "registers": []interface{}{map[string]interface{}{"key": "uuid"}}},
+			"invalid owner":      {"block_id": blockID.String(), "registers": []interface{}{map[string]interface{}{"owner": "0102", "key": "uuid"}}},
+			"missing key":        {"block_id": blockID.String(), "registers": []interface{}{map[string]interface{}{"owner": ""}}},
+		} {
+			err := cmd.Validator(&admin.CommandRequest{Data: data})
+			require.True(t, admin.IsInvalidAdminParameterError(err), name)
+		}
+	})
+}
+
+func TestGetRegisterProofsCommandHandler(t *testing.T) {
+	led, err := complete.NewLedger(&fixtures.NoopWAL{}, 100, &metrics.NoopCollector{}, zerolog.Nop(), complete.DefaultPathFinderVersion)
+	require.NoError(t, err)
+
+	compactor := fixtures.NewNoopCompactor(led)
+	<-compactor.Ready()
+	defer func() {
+		<-led.Done()
+		<-compactor.Done()
+	}()
+
+	owner := unittest.RandomAddressFixture()
+	stored := flow.NewRegisterID(string(owner.Bytes()), "storage_used")
+	missing := flow.NewRegisterID(string(owner.Bytes()), "missing")
+
+	update, err := ledger.NewUpdate(
+		led.InitialState(),
+		[]ledger.Key{state.RegisterIDToKey(stored)},
+		[]ledger.Value{{1, 2}},
+	)
+	require.NoError(t, err)
+
+	newState, _, err := led.Set(update)
+	require.NoError(t, err)
+	commit := flow.StateCommitment(newState)
+
+	blockID := unittest.IdentifierFixture()
+	commits := storagemock.NewCommits(t)
+	commits.On("ByBlockID", blockID).Return(commit, nil)
+
+	cmd := NewGetRegisterProofsCommand(led, commits)
+
+	req := &admin.CommandRequest{
+		Data: map[string]interface{}{
+			"block_id": blockID.String(),
+			"registers": []interface{}{
+				map[string]interface{}{"owner": owner.Hex(), "key": stored.Key},
+				map[string]interface{}{"owner": owner.Hex(), "key": missing.Key},
+			},
+		},
+	}
+	require.NoError(t, cmd.Validator(req))
+
+	result, err := cmd.Handler(context.Background(), req)
+	require.NoError(t, err)
+
+	output := result.(map[string]interface{})
+	require.Equal(t, blockID.String(), output["block_id"])
+	require.Equal(t, hex.EncodeToString(commit[:]), output["commit"])
+	require.Equal(t, []interface{}{
+		map[string]interface{}{"owner": owner.Hex(), "key": stored.Key, "value": "0102"},
+		map[string]interface{}{"owner": owner.Hex(), "key": missing.Key, "value": ""},
+	}, output["registers"])
+
+	encodedProof, err := hex.DecodeString(output["proof"].(string))
+	require.NoError(t, err)
+
+	err = proof.VerifyEncodedBatchProof(
+		encodedProof,
+		[]ledger.Key{state.RegisterIDToKey(stored), state.RegisterIDToKey(missing)},
+		[]ledger.Value{{1, 2}, {}},
+		newState,
+		complete.DefaultPathFinderVersion,
+	)
+	require.NoError(t, err)
+}
@@ -0,0 +1,49 @@
+package execution
+
+import (
+	"context"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/engine/execution/ingestion"
+)
+
+var _ commands.AdminCommand = (*GetStopControlCommand)(nil)
+
+// GetStopControlCommand reports the state of the stop control, including the version
+// of the node software required to continue executing blocks, if any
+type GetStopControlCommand struct {
+	stopControl *ingestion.StopControl
+}
+
+// NewGetStopControlCommand creates a new GetStopControlCommand object
+func NewGetStopControlCommand(stopControl *ingestion.StopControl) *GetStopControlCommand {
+	return &GetStopControlCommand{
+		stopControl: stopControl,
+	}
+}
+
+// Handler returns the state of the stop control. The stop height and crash mode are only
+// meaningful if the state is not "off", and the required version is only set if the stop
+// height is a version boundary.
+func (s *GetStopControlCommand) Handler(_ context.Context, _ *admin.CommandRequest) (interface{}, error) {
+	height, crash := s.stopControl.GetStopHeight()
+
+	var requiredVersion interface{}
+	if boundary, ok := s.stopControl.GetVersionBoundary(); ok {
+		requiredVersion = boundary.Version
+	}
+
+	return map[string]interface{}{
+		"state":            s.stopControl.GetState().String(),
+		"stop_height":      float64(height),
+		"crash":            crash,
+		"required_version": requiredVersion,
+		"node_version":     s.stopControl.NodeVersion(),
+	}, nil
+}
+
+// Validator accepts any request, since the command has no inputs.
+func (s *GetStopControlCommand) Validator(_ *admin.CommandRequest) error {
+	return nil
+}
@@ -0,0 +1,202 @@
+package execution
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+var _ commands.AdminCommand = (*IterateRegistersCommand)(nil)
+
+const (
+	// DefaultIterateRegistersLimit is the number of registers returned if no limit is provided
+	DefaultIterateRegistersLimit = 100
+
+	// MaxIterateRegistersLimit is the maximum number of registers returned by a single request
+	MaxIterateRegistersLimit = 1000
+)
+
+// LedgerIterator iterates the registers of a ledger state.
+type LedgerIterator interface {
+	Iterate(query *ledger.IterateQuery) (*ledger.IterateResult, error)
+}
+
+// IterateRegistersCommand lists the registers of an account at an execution state, one page at
+// a time. It is meant for debugging, since finding the registers of an account requires visiting
+// all registers of the state in the worst case.
+type IterateRegistersCommand struct {
+	ledger  LedgerIterator
+	commits storage.Commits
+}
+
+// NewIterateRegistersCommand creates a new IterateRegistersCommand object
+func NewIterateRegistersCommand(ledger LedgerIterator, commits storage.Commits) *IterateRegistersCommand {
+	return &IterateRegistersCommand{
+		ledger:  ledger,
+		commits: commits,
+	}
+}
+
+type iterateRegistersReq struct {
+	commit     *flow.StateCommitment
+	blockID    flow.Identifier
+	prefix     ledger.Key
+	startAfter *ledger.Path
+	limit      int
+}
+
+// Handler returns the registers matching the request, and the path to start after to get the
+// next page, which is nil if all registers were returned.
+func (c *IterateRegistersCommand) Handler(_ context.Context, req *admin.CommandRequest) (interface{}, error) {
+	data := req.ValidatorData.(*iterateRegistersReq)
+
+	var commit flow.StateCommitment
+	if data.commit != nil {
+		commit = *data.commit
+	} else {
+		var err error
+		commit, err = c.commits.ByBlockID(data.blockID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get state commitment for block %v: %w", data.blockID, err)
+		}
+	}
+
+	query, err := ledger.NewIterateQuery(ledger.State(commit), data.prefix, data.startAfter, data.limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create iterate query: %w", err)
+	}
+
+	result, err := c.ledger.Iterate(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to iterate registers at state %v: %w", commit, err)
+	}
+
+	registers := make([]interface{}, len(result.Keys))
+	for i, key := range result.Keys {
+		if len(key.KeyParts) != 2 {
+			return nil, fmt.Errorf("unexpected key with %d parts: %s", len(key.KeyParts), key.String())
+		}
+		registers[i] = map[string]interface{}{
+			"owner": hex.EncodeToString(key.KeyParts[0].Value),
+			"key":   string(key.KeyParts[1].Value),
+			"value": hex.EncodeToString(result.Values[i]),
+		}
+	}
+
+	var nextStartAfter interface{}
+	if result.NextStartAfter != nil {
+		nextStartAfter = hex.EncodeToString(result.NextStartAfter[:])
+	}
+
+	return map[string]interface{}{
+		"commit":           hex.EncodeToString(commit[:]),
+		"registers":        registers,
+		"next_start_after": nextStartAfter,
+	}, nil
+}
+
+// Validator checks the inputs for IterateRegisters command.
+// It expects the following fields in the Data field of the req object:
+//   - either commit, a hex encoded state commitment, or block_id, a hex encoded block ID
+//   - owner, optional hex encoded address of the account owning the registers
+//   - key_prefix, optional prefix of the register keys, which requires owner to be set
+//   - start_after, optional hex encoded path returned as next_start_after by a previous request
+//   - limit, optional maximum number of registers to return
+//
+// The following sentinel errors are expected during normal operations:
+// * `admin.InvalidAdminReqError` if any field is missing or in a wrong format
+func (c *IterateRegistersCommand) Validator(req *admin.CommandRequest) error {
+	input, ok := req.Data.(map[string]interface{})
+	if !ok {
+		return admin.NewInvalidAdminReqFormatError("expected map[string]any")
+	}
+
+	data := &iterateRegistersReq{
+		limit: DefaultIterateRegistersLimit,
+	}
+
+	if commitIn, ok := input["commit"]; ok {
+		commitBytes, err := parseHexField(commitIn)
+		if err != nil || len(commitBytes) != len(flow.StateCommitment{}) {
+			return admin.NewInvalidAdminReqParameterError("commit", "must be a 64 character long hex string", commitIn)
+		}
+		var commit flow.StateCommitment
+		copy(commit[:], commitBytes)
+		data.commit = &commit
+	} else if blockIn, ok := input["block_id"]; ok {
+		block, ok := blockIn.(string)
+		if !ok {
+			return admin.NewInvalidAdminReqParameterError("block_id", "must be a 64 character long hex string", blockIn)
+		}
+		blockID, err := flow.HexStringToIdentifier(block)
+		if err != nil {
+			return admin.NewInvalidAdminReqParameterError("block_id", "must be a 64 character long hex string", blockIn)
+		}
+		data.blockID = blockID
+	} else {
+		return admin.NewInvalidAdminReqErrorf("either \"commit\" or \"block_id\" field is required")
+	}
+
+	if ownerIn, ok := input["owner"]; ok {
+		owner, err := parseHexField(ownerIn)
+		if err != nil || len(owner) != flow.AddressLength {
+			return admin.NewInvalidAdminReqParameterError("owner", "must be a 16 character long hex string", ownerIn)
+		}
+		parts := []ledger.KeyPart{ledger.NewKeyPart(state.KeyPartOwner, owner)}
+
+		if keyPrefixIn, ok := input["key_prefix"]; ok {
+			keyPrefix, ok := keyPrefixIn.(string)
+			if !ok {
+				return admin.NewInvalidAdminReqParameterError("key_prefix", "must be a string", keyPrefixIn)
+			}
+			parts = append(parts, ledger.NewKeyPart(state.KeyPartKey, []byte(keyPrefix)))
+		}
+		data.prefix = ledger.NewKey(parts)
+	} else if _, ok := input["key_prefix"]; ok {
+		return admin.NewInvalidAdminReqErrorf("\"key_prefix\" field requires the \"owner\" field")
+	}
+
+	if startAfterIn, ok := input["start_after"]; ok {
+		startAfter, err := parseHexField(startAfterIn)
+		if err != nil || len(startAfter) != ledger.PathLen {
+			return admin.NewInvalidAdminReqParameterError("start_after", "must be a 64 character long hex string", startAfterIn)
+		}
+		path, err := ledger.ToPath(startAfter)
+		if err != nil {
+			return admin.NewInvalidAdminReqParameterError("start_after", "must be a valid path", startAfterIn)
+		}
+		data.startAfter = &path
+	}
+
+	if limitIn, ok := input["limit"]; ok {
+		limit, ok := limitIn.(float64)
+		if !ok || limit < 1 || limit > MaxIterateRegistersLimit || math.Trunc(limit) != limit {
+			return admin.NewInvalidAdminReqParameterError("limit", fmt.Sprintf("must be an integer between 1 and %d", MaxIterateRegistersLimit), limitIn)
+		}
+		data.limit = int(limit)
+	}
+
+	req.ValidatorData = data
+
+	return nil
+}
+
+// parseHexField decodes a hex string field, with or without the 0x prefix
+func parseHexField(field interface{}) ([]byte, error) {
+	s, ok := field.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected string, got %T", field)
+	}
+	if len(s) >= 2 && s[:2] == "0x" {
+		s = s[2:]
+	}
+	return hex.DecodeString(s)
+}
@@ -0,0 +1,141 @@
+package execution
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/model/flow"
+	storagemock "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+type ledgerIteratorFunc func(query *ledger.IterateQuery) (*ledger.IterateResult, error)
+
+func (f ledgerIteratorFunc) Iterate(query *ledger.IterateQuery) (*ledger.IterateResult, error) {
+	return f(query)
+}
+
+func TestIterateRegistersCommandParsing(t *testing.T) {
+	cmd := IterateRegistersCommand{}
+
+	commit := unittest.StateCommitmentFixture()
+	owner := unittest.RandomAddressFixture()
+	var path ledger.Path
+	path[0] = 1
+
+	t.Run("happy path", func(t *testing.T) {
+		req := &admin.CommandRequest{
+			Data: map[string]interface{}{
+				"commit":      hex.EncodeToString(commit[:]),
+				"owner":       "0x" + owner.Hex(),
+				"key_prefix":  "public_key_",
+				"start_after": hex.EncodeToString(path[:]),
+				"limit":       float64(10), // raw json parses to float64
+			},
+		}
+
+		err := cmd.Validator(req)
+		require.NoError(t, err)
+
+		parsedReq := req.ValidatorData.(*iterateRegistersReq)
+		require.Equal(t, commit, *parsedReq.commit)
+		require.Equal(t, ledger.NewKey([]ledger.KeyPart{
+			ledger.NewKeyPart(state.KeyPartOwner, owner.Bytes()),
+			ledger.NewKeyPart(state.KeyPartKey, []byte("public_key_")),
+		}), parsedReq.prefix)
+		require.Equal(t, path, *parsedReq.startAfter)
+		require.Equal(t, 10, parsedReq.limit)
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		blockID := unittest.IdentifierFixture()
+		req := &admin.CommandRequest{
+			Data: map[string]interface{}{
+				"block_id": blockID.String(),
+			},
+		}
+
+		err := cmd.Validator(req)
+		require.NoError(t, err)
+
+		parsedReq := req.ValidatorData.(*iterateRegistersReq)
+		require.Nil(t, parsedReq.commit)
+		require.Equal(t, blockID, parsedReq.blockID)
+		require.Empty(t, parsedReq.prefix.KeyParts)
+		require.Nil(t, parsedReq.startAfter)
+		require.Equal(t, DefaultIterateRegistersLimit, parsedReq.limit)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		validCommit := hex.EncodeToString(commit[:])
+		for name, data := range map[string]map[string]interface{}{
+			"missing state":        {},
+			"invalid commit":       {"commit": "abc"},
+			"invalid block ID":     {"block_id": 1},
+			"invalid owner":        {"commit": validCommit, "owner": "0102"},
+			"key prefix only":      {"commit": validCommit, "key_prefix": "foo"},
+			"invalid start after":  {"commit": validCommit, "start_after": "0102"},
+			"limit too high":       {"commit": validCommit, "limit": float64(MaxIterateRegistersLimit + 1)},
+			"limit not an integer": {"commit": validCommit, "limit": 1.5},
+		} {
+			err := cmd.Validator(&admin.CommandRequest{Data: data})
+			require.True(t, admin.IsInvalidAdminParameterError(err), name)
+		}
+	})
+}
+
+func TestIterateRegistersCommandHandler(t *testing.T) {
+	blockID := unittest.IdentifierFixture()
+	commit := unittest.StateCommitmentFixture()
+	owner := unittest.RandomAddressFixture()
+
+	commits := storagemock.NewCommits(t)
+	commits.On("ByBlockID", blockID).Return(commit, nil)
+
+	var nextPath ledger.Path
+	nextPath[0] = 2
+	key := state.RegisterIDToKey(flow.NewRegisterID(string(owner.Bytes()), "storage"))
+
+	iterator := ledgerIteratorFunc(func(query *ledger.IterateQuery) (*ledger.IterateResult, error) {
+		assert.Equal(t, ledger.State(commit), query.State())
+		assert.Equal(t, 1, query.Limit())
+		return &ledger.IterateResult{
+			Keys:           []ledger.Key{key},
+			Values:         []ledger.Value{{1, 2}},
+			NextStartAfter: &nextPath,
+		}, nil
+	})
+
+	cmd := NewIterateRegistersCommand(iterator, commits)
+
+	req := &admin.CommandRequest{
+		Data: map[string]interface{}{
+			"block_id": blockID.String(),
+			"owner":    owner.Hex(),
+			"limit":    float64(1),
+		},
+	}
+	require.NoError(t, cmd.Validator(req))
+
+	result, err := cmd.Handler(context.Background(), req)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"commit": hex.EncodeToString(commit[:]),
+		"registers": []interface{}{
+			map[string]interface{}{
+				"owner": owner.Hex(),
+				"key":   "storage",
+				"value": "0102",
+			},
+		},
+		"next_start_after": hex.EncodeToString(nextPath[:]),
+	}, result)
+}
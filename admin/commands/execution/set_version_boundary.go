@@ -0,0 +1,98 @@
+package execution
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/engine/execution/ingestion"
+)
+
+var _ commands.AdminCommand = (*SetVersionBoundaryCommand)(nil)
+
+// SetVersionBoundaryCommand sets a version boundary: EN stops/crashes before executing
+// the given height, and does not execute it until it is upgraded to the given version
+type SetVersionBoundaryCommand struct {
+	stopControl *ingestion.StopControl
+}
+
+// NewSetVersionBoundaryCommand creates a new SetVersionBoundaryCommand object
+func NewSetVersionBoundaryCommand(stopControl *ingestion.StopControl) *SetVersionBoundaryCommand {
+	return &SetVersionBoundaryCommand{
+		stopControl: stopControl,
+	}
+}
+
+type SetVersionBoundaryReq struct {
+	height  uint64
+	version string
+	crash   bool
+}
+
+// Handler method sets the version boundary.
+// Errors only if setting of the version boundary fails.
+// Returns "ok" if successful.
+func (s *SetVersionBoundaryCommand) Handler(_ context.Context, req *admin.CommandRequest) (interface{}, error) {
+	vb := req.ValidatorData.(SetVersionBoundaryReq)
+
+	err := s.stopControl.SetVersionBoundary(vb.height, vb.version, vb.crash)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().Msgf("admintool: EN will stop at height %d until upgraded to version %s, crash: %t", vb.height, vb.version, vb.crash)
+
+	return "ok", nil
+}
+
+// Validator checks the inputs for SetVersionBoundary command.
+// It expects the following fields in the Data field of the req object:
+//   - height in a numeric format
+//   - version, the semantic version required to execute blocks at and above height
+//   - crash, an optional boolean, false by default
+//
+// Additionally, height must be a positive integer. If a float value is provided, only the integer part is used.
+// The following sentinel errors are expected during normal operations:
+// * `admin.InvalidAdminReqError` if any required field is missing or in a wrong format
+func (s *SetVersionBoundaryCommand) Validator(req *admin.CommandRequest) error {
+
+	input, ok := req.Data.(map[string]interface{})
+	if !ok {
+		return admin.NewInvalidAdminReqFormatError("expected map[string]any")
+	}
+	result, ok := input["height"]
+	if !ok {
+		return admin.NewInvalidAdminReqErrorf("missing required field: 'height'")
+	}
+	height, ok := result.(float64)
+	if !ok || height <= 0 {
+		return admin.NewInvalidAdminReqParameterError("height", "must be number >=0", result)
+	}
+
+	result, ok = input["version"]
+	if !ok {
+		return admin.NewInvalidAdminReqErrorf("missing required field: 'version'")
+	}
+	version, ok := result.(string)
+	if !ok || version == "" {
+		return admin.NewInvalidAdminReqParameterError("version", "must be a non-empty string", result)
+	}
+
+	crash := false
+	if result, ok = input["crash"]; ok {
+		crash, ok = result.(bool)
+		if !ok {
+			return admin.NewInvalidAdminReqParameterError("crash", "must be bool", result)
+		}
+	}
+
+	req.ValidatorData = SetVersionBoundaryReq{
+		height:  uint64(height),
+		version: version,
+		crash:   crash,
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package execution
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/engine/execution/ingestion"
+	"github.com/onflow/flow-go/model/flow"
+	storagemock "github.com/onflow/flow-go/storage/mock"
+)
+
+func TestSetVersionBoundaryCommandParsing(t *testing.T) {
+	cmd := SetVersionBoundaryCommand{}
+
+	t.Run("happy path", func(t *testing.T) {
+		req := &admin.CommandRequest{
+			Data: map[string]interface{}{
+				"height":  float64(21), // raw json parses to float64
+				"version": "v0.31.0",
+			},
+		}
+
+		err := cmd.Validator(req)
+		require.NoError(t, err)
+
+		parsedReq := req.ValidatorData.(SetVersionBoundaryReq)
+		require.Equal(t, uint64(21), parsedReq.height)
+		require.Equal(t, "v0.31.0", parsedReq.version)
+		require.False(t, parsedReq.crash)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for name, data := range map[string]map[string]interface{}{
+			"missing height":  {"version": "v0.31.0"},
+			"invalid height":  {"height": "21", "version": "v0.31.0"},
+			"missing version": {"height": float64(21)},
+			"empty version":   {"height": float64(21), "version": ""},
+			"invalid crash":   {"height": float64(21), "version": "v0.31.0", "crash": "true"},
+		} {
+			err := cmd.Validator(&admin.CommandRequest{Data: data})
+			require.True(t, admin.IsInvalidAdminParameterError(err), name)
+		}
+	})
+}
+
+func TestSetVersionBoundaryCommand(t *testing.T) {
+	stopBoundary := storagemock.NewStopBoundary(t)
+	stopBoundary.On("Store", flow.VersionBoundary{BlockHeight: 37, Version: "v0.31.0"}, true).Return(nil).Once()
+	stopControl := newTestStopControl(t, stopBoundary)
+
+	getCmd := NewGetStopControlCommand(stopControl)
+
+	status, err := getCmd.Handler(context.Background(), &admin.CommandRequest{})
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"state":            "off",
+		"stop_height":      float64(0),
+		"crash":            false,
+		"required_version": nil,
+		"node_version":     "v0.30.0",
+	}, status)
+
+	setCmd := NewSetVersionBoundaryCommand(stopControl)
+
+	req := &admin.CommandRequest{
+		ValidatorData: SetVersionBoundaryReq{
+			height:  37,
+			version: "v0.31.0",
+			crash:   true,
+		},
+	}
+	_, err = setCmd.Handler(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, ingestion.StopControlSet, stopControl.GetState())
+
+	status, err = getCmd.Handler(context.Background(), &admin.CommandRequest{})
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"state":            "set",
+		"stop_height":      float64(37),
+		"crash":            true,
+		"required_version": "v0.31.0",
+		"node_version":     "v0.30.0",
+	}, status)
+
+	// the node version already satisfies the boundary
+	req.ValidatorData = SetVersionBoundaryReq{height: 40, version: "v0.30.0"}
+	_, err = setCmd.Handler(context.Background(), req)
+	require.Error(t, err)
+}
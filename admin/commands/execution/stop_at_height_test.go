@@ -9,8 +9,20 @@ import (
 
 	"github.com/onflow/flow-go/admin"
 	"github.com/onflow/flow-go/engine/execution/ingestion"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	storagemock "github.com/onflow/flow-go/storage/mock"
 )
 
+// newTestStopControl creates a StopControl without a persisted version boundary
+func newTestStopControl(t *testing.T, stopBoundary *storagemock.StopBoundary) *ingestion.StopControl {
+	stopBoundary.On("Retrieve").Return(flow.VersionBoundary{}, false, storage.ErrNotFound)
+
+	stopControl, err := ingestion.NewStopControl(zerolog.Nop(), false, 0, "v0.30.0", stopBoundary)
+	require.NoError(t, err)
+	return stopControl
+}
+
 func TestCommandParsing(t *testing.T) {
 	cmd := StopAtHeightCommand{}
 
@@ -88,7 +100,7 @@ func TestCommandParsing(t *testing.T) {
 
 func TestCommandsSetsValues(t *testing.T) {
 
-	stopControl := ingestion.NewStopControl(zerolog.Nop(), false, 0)
+	stopControl := newTestStopControl(t, storagemock.NewStopBoundary(t))
 
 	cmd := NewStopAtHeightCommand(stopControl)
 
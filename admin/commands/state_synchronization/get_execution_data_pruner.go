@@ -0,0 +1,73 @@
+package state_synchronization
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/module/executiondatasync/pruner"
+)
+
+var _ commands.AdminCommand = (*GetExecutionDataPrunerCommand)(nil)
+
+// GetExecutionDataPrunerCommand reports the retention policy and progress of the execution
+// data pruner
+type GetExecutionDataPrunerCommand struct {
+	pruner *pruner.Pruner
+}
+
+// NewGetExecutionDataPrunerCommand creates a new GetExecutionDataPrunerCommand object.
+// The pruner may be nil if execution data pruning is not supported by the node.
+func NewGetExecutionDataPrunerCommand(pruner *pruner.Pruner) *GetExecutionDataPrunerCommand {
+	return &GetExecutionDataPrunerCommand{
+		pruner: pruner,
+	}
+}
+
+// Handler returns the retention policy, the last pruned and fulfilled heights, the lowest height
+// pinned by readers of the execution data, and the disk usage of the execution data if known.
+func (g *GetExecutionDataPrunerCommand) Handler(ctx context.Context, _ *admin.CommandRequest) (interface{}, error) {
+	if g.pruner == nil {
+		return nil, fmt.Errorf("execution data pruning is not enabled")
+	}
+
+	prunedHeight, fulfilledHeight := g.pruner.Heights()
+
+	var lowestPinnedHeight interface{}
+	if height, ok := g.pruner.Pins().Lowest(); ok {
+		lowestPinnedHeight = float64(height)
+	}
+
+	var diskUsage interface{}
+	usage, ok, err := g.pruner.DiskUsage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		diskUsage = float64(usage)
+	}
+
+	return map[string]interface{}{
+		"retention_policy":     retentionPolicyToMap(g.pruner.RetentionPolicy()),
+		"pruned_height":        float64(prunedHeight),
+		"fulfilled_height":     float64(fulfilledHeight),
+		"pins":                 float64(g.pruner.Pins().Count()),
+		"lowest_pinned_height": lowestPinnedHeight,
+		"disk_usage":           diskUsage,
+	}, nil
+}
+
+// Validator accepts any request, since the command has no inputs.
+func (g *GetExecutionDataPrunerCommand) Validator(_ *admin.CommandRequest) error {
+	return nil
+}
+
+func retentionPolicyToMap(policy pruner.RetentionPolicy) map[string]interface{} {
+	return map[string]interface{}{
+		"height_range_target": float64(policy.HeightRangeTarget),
+		"threshold":           float64(policy.Threshold),
+		"max_age":             policy.MaxAge.String(),
+		"disk_budget":         float64(policy.DiskBudget),
+	}
+}
@@ -0,0 +1,133 @@
+package state_synchronization
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/module/executiondatasync/pruner"
+)
+
+var _ commands.AdminCommand = (*SetExecutionDataRetentionCommand)(nil)
+
+// SetExecutionDataRetentionCommand updates the retention policy of the execution data pruner
+type SetExecutionDataRetentionCommand struct {
+	pruner *pruner.Pruner
+}
+
+// NewSetExecutionDataRetentionCommand creates a new SetExecutionDataRetentionCommand object.
+// The pruner may be nil if execution data pruning is not supported by the node.
+func NewSetExecutionDataRetentionCommand(pruner *pruner.Pruner) *SetExecutionDataRetentionCommand {
+	return &SetExecutionDataRetentionCommand{
+		pruner: pruner,
+	}
+}
+
+// setExecutionDataRetentionReq holds the fields of the retention policy to update.
+// Fields which are nil are left unchanged.
+type setExecutionDataRetentionReq struct {
+	heightRangeTarget *uint64
+	threshold         *uint64
+	maxAge            *time.Duration
+	diskBudget        *uint64
+}
+
+// Handler updates the given fields of the retention policy, and returns the new policy.
+// Errors if execution data pruning is not enabled, or if the node doesn't support the policy.
+func (s *SetExecutionDataRetentionCommand) Handler(_ context.Context, req *admin.CommandRequest) (interface{}, error) {
+	if s.pruner == nil {
+		return nil, fmt.Errorf("execution data pruning is not enabled")
+	}
+
+	data := req.ValidatorData.(*setExecutionDataRetentionReq)
+
+	policy := s.pruner.RetentionPolicy()
+	if data.heightRangeTarget != nil {
+		policy.HeightRangeTarget = *data.heightRangeTarget
+	}
+	if data.threshold != nil {
+		policy.Threshold = *data.threshold
+	}
+	if data.maxAge != nil {
+		policy.MaxAge = *data.maxAge
+	}
+	if data.diskBudget != nil {
+		policy.DiskBudget = *data.diskBudget
+	}
+
+	err := s.pruner.SetRetentionPolicy(policy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set retention policy: %w", err)
+	}
+
+	log.Info().
+		Uint64("height_range_target", policy.HeightRangeTarget).
+		Uint64("threshold", policy.Threshold).
+		Dur("max_age", policy.MaxAge).
+		Uint64("disk_budget", policy.DiskBudget).
+		Msg("admintool: execution data retention policy updated")
+
+	return retentionPolicyToMap(policy), nil
+}
+
+// Validator checks the inputs for SetExecutionDataRetention command.
+// It expects at least one of the following fields in the Data field of the req object:
+//   - height_range_target, the number of most recent heights to keep, 0 to disable
+//   - threshold, the number of heights the policy can be exceeded by before pruning
+//   - max_age, a duration string like "72h" for the maximum age of the kept blocks, "0s" to disable
+//   - disk_budget, the maximum number of bytes of execution data to keep, 0 to disable
+//
+// The following sentinel errors are expected during normal operations:
+// * `admin.InvalidAdminReqError` if no field is provided, or any field is in a wrong format
+func (s *SetExecutionDataRetentionCommand) Validator(req *admin.CommandRequest) error {
+	input, ok := req.Data.(map[string]interface{})
+	if !ok {
+		return admin.NewInvalidAdminReqFormatError("expected map[string]any")
+	}
+
+	data := &setExecutionDataRetentionReq{}
+
+	for _, field := range []struct {
+		name  string
+		value **uint64
+	}{
+		{"height_range_target", &data.heightRangeTarget},
+		{"threshold", &data.threshold},
+		{"disk_budget", &data.diskBudget},
+	} {
+		result, ok := input[field.name]
+		if !ok {
+			continue
+		}
+		value, ok := result.(float64)
+		if !ok || value < 0 {
+			return admin.NewInvalidAdminReqParameterError(field.name, "must be a number >= 0", result)
+		}
+		v := uint64(value)
+		*field.value = &v
+	}
+
+	if result, ok := input["max_age"]; ok {
+		str, ok := result.(string)
+		if !ok {
+			return admin.NewInvalidAdminReqParameterError("max_age", "must be a duration string", result)
+		}
+		maxAge, err := time.ParseDuration(str)
+		if err != nil || maxAge < 0 {
+			return admin.NewInvalidAdminReqParameterError("max_age", "must be a duration string >= 0", result)
+		}
+		data.maxAge = &maxAge
+	}
+
+	if data.heightRangeTarget == nil && data.threshold == nil && data.maxAge == nil && data.diskBudget == nil {
+		return admin.NewInvalidAdminReqErrorf("at least one of \"height_range_target\", \"threshold\", \"max_age\" or \"disk_budget\" is required")
+	}
+
+	req.ValidatorData = data
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package state_synchronization
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/module/executiondatasync/pruner"
+	mocktracker "github.com/onflow/flow-go/module/executiondatasync/tracker/mock"
+	"github.com/onflow/flow-go/module/irrecoverable"
+	"github.com/onflow/flow-go/module/metrics"
+)
+
+func TestSetExecutionDataRetentionCommandParsing(t *testing.T) {
+	cmd := SetExecutionDataRetentionCommand{}
+
+	t.Run("happy path", func(t *testing.T) {
+		req := &admin.CommandRequest{
+			Data: map[string]interface{}{
+				"height_range_target": float64(1000),
+				"max_age":             "72h",
+			},
+		}
+
+		err := cmd.Validator(req)
+		require.NoError(t, err)
+
+		parsedReq := req.ValidatorData.(*setExecutionDataRetentionReq)
+		require.Equal(t, uint64(1000), *parsedReq.heightRangeTarget)
+		require.Equal(t, 72*time.Hour, *parsedReq.maxAge)
+		require.Nil(t, parsedReq.threshold)
+		require.Nil(t, parsedReq.diskBudget)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for name, data := range map[string]map[string]interface{}{
+			"no fields":                 {},
+			"negative height range":     {"height_range_target": float64(-1)},
+			"non numeric threshold":     {"threshold": "10"},
+			"non numeric disk budget":   {"disk_budget": true},
+			"invalid max age":           {"max_age": "3 days"},
+			"negative max age":          {"max_age": "-1h"},
+			"non string max age":        {"max_age": float64(10)},
+			"invalid field among valid": {"height_range_target": float64(10), "max_age": "abc"},
+		} {
+			err := cmd.Validator(&admin.CommandRequest{Data: data})
+			require.True(t, admin.IsInvalidAdminParameterError(err), name)
+		}
+	})
+}
+
+func TestSetExecutionDataRetentionCommandHandler(t *testing.T) {
+	trackerStorage := new(mocktracker.Storage)
+	trackerStorage.On("GetFulfilledHeight").Return(uint64(0), nil).Once()
+	trackerStorage.On("GetPrunedHeight").Return(uint64(0), nil).Once()
+
+	executionDataPruner, err := pruner.NewPruner(
+		zerolog.Nop(),
+		metrics.NewNoopCollector(),
+		trackerStorage,
+		pruner.WithHeightRangeTarget(0),
+		pruner.WithThreshold(100),
+	)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() {
+		cancel()
+		<-executionDataPruner.Done()
+	}()
+	signalerCtx, _ := irrecoverable.WithSignaler(ctx)
+	executionDataPruner.Start(signalerCtx)
+
+	cmd := NewSetExecutionDataRetentionCommand(executionDataPruner)
+
+	t.Run("updates given fields", func(t *testing.T) {
+		req := &admin.CommandRequest{
+			Data: map[string]interface{}{
+				"height_range_target": float64(1000),
+			},
+		}
+		require.NoError(t, cmd.Validator(req))
+
+		result, err := cmd.Handler(context.Background(), req)
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{
+			"height_range_target": float64(1000),
+			"threshold":           float64(100),
+			"max_age":             "0s",
+			"disk_budget":         float64(0),
+		}, result)
+
+		require.Equal(t, pruner.RetentionPolicy{HeightRangeTarget: 1000, Threshold: 100}, executionDataPruner.RetentionPolicy())
+	})
+
+	t.Run("unsupported policy", func(t *testing.T) {
+		// the pruner was not configured with headers, so age based pruning is not supported
+		req := &admin.CommandRequest{
+			Data: map[string]interface{}{
+				"max_age": "72h",
+			},
+		}
+		require.NoError(t, cmd.Validator(req))
+
+		_, err := cmd.Handler(context.Background(), req)
+		require.Error(t, err)
+
+		require.Equal(t, pruner.RetentionPolicy{HeightRangeTarget: 1000, Threshold: 100}, executionDataPruner.RetentionPolicy())
+	})
+
+	t.Run("inspect", func(t *testing.T) {
+		pin := executionDataPruner.Pins().Pin(5)
+		defer pin.Release()
+
+		result, err := NewGetExecutionDataPrunerCommand(executionDataPruner).Handler(context.Background(), &admin.CommandRequest{})
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{
+			"retention_policy": map[string]interface{}{
+				"height_range_target": float64(1000),
+				"threshold":           float64(100),
+				"max_age":             "0s",
+				"disk_budget":         float64(0),
+			},
+			"pruned_height":        float64(0),
+			"fulfilled_height":     float64(0),
+			"pins":                 float64(1),
+			"lowest_pinned_height": float64(5),
+			"disk_usage":           nil,
+		}, result)
+	})
+
+	t.Run("pruning not enabled", func(t *testing.T) {
+		req := &admin.CommandRequest{
+			Data: map[string]interface{}{
+				"threshold": float64(10),
+			},
+		}
+		require.NoError(t, cmd.Validator(req))
+
+		_, err := NewSetExecutionDataRetentionCommand(nil).Handler(context.Background(), req)
+		require.Error(t, err)
+
+		_, err = NewGetExecutionDataPrunerCommand(nil).Handler(context.Background(), req)
+		require.Error(t, err)
+	})
+}
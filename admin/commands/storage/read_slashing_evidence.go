@@ -0,0 +1,119 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/slashing"
+	"github.com/onflow/flow-go/storage"
+)
+
+var _ commands.AdminCommand = (*ReadSlashingEvidenceCommand)(nil)
+
+type readSlashingEvidenceRequestType int
+
+const (
+	readSlashingEvidenceRequestAll readSlashingEvidenceRequestType = iota
+	readSlashingEvidenceRequestByID
+	readSlashingEvidenceRequestByOffender
+)
+
+type readSlashingEvidenceRequest struct {
+	requestType readSlashingEvidenceRequestType
+	value       flow.Identifier
+}
+
+// ReadSlashingEvidenceCommand exports the slashing evidence recorded by the node as a
+// self-contained evidence bundle, see slashing.EvidenceBundle.
+type ReadSlashingEvidenceCommand struct {
+	nodeID   flow.Identifier
+	evidence storage.SlashingEvidence
+}
+
+func NewReadSlashingEvidenceCommand(nodeID flow.Identifier, evidence storage.SlashingEvidence) commands.AdminCommand {
+	return &ReadSlashingEvidenceCommand{
+		nodeID:   nodeID,
+		evidence: evidence,
+	}
+}
+
+func (r *ReadSlashingEvidenceCommand) Handler(_ context.Context, req *admin.CommandRequest) (interface{}, error) {
+	data := req.ValidatorData.(*readSlashingEvidenceRequest)
+
+	var evidence []*flow.SlashingEvidence
+	switch data.requestType {
+	case readSlashingEvidenceRequestAll:
+		all, err := r.evidence.All()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get slashing evidence: %w", err)
+		}
+		evidence = all
+	case readSlashingEvidenceRequestByID:
+		e, err := r.evidence.ByID(data.value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get slashing evidence by ID: %w", err)
+		}
+		evidence = []*flow.SlashingEvidence{e}
+	case readSlashingEvidenceRequestByOffender:
+		byOffender, err := r.evidence.ByOffender(data.value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get slashing evidence by offender: %w", err)
+		}
+		evidence = byOffender
+	}
+
+	return commands.ConvertToMap(slashing.NewEvidenceBundle(r.nodeID, evidence))
+}
+
+// Validator validates the request.
+// Returns admin.InvalidAdminReqError for invalid/malformed requests.
+func (r *ReadSlashingEvidenceCommand) Validator(req *admin.CommandRequest) error {
+	data := &readSlashingEvidenceRequest{
+		requestType: readSlashingEvidenceRequestAll,
+	}
+	req.ValidatorData = data
+
+	// without input, all evidence is exported
+	if req.Data == nil {
+		return nil
+	}
+
+	input, ok := req.Data.(map[string]interface{})
+	if !ok {
+		return admin.NewInvalidAdminReqFormatError("expected map[string]any")
+	}
+
+	parseID := func(field string, value interface{}) (flow.Identifier, error) {
+		errInvalidValue := admin.NewInvalidAdminReqParameterError(field, "expected an ID represented as a 64 character long hex string", value)
+		s, ok := value.(string)
+		if !ok {
+			return flow.ZeroID, errInvalidValue
+		}
+		id, err := flow.HexStringToIdentifier(s)
+		if err != nil {
+			return flow.ZeroID, errInvalidValue
+		}
+		return id, nil
+	}
+
+	if idIn, ok := input["id"]; ok {
+		id, err := parseID("id", idIn)
+		if err != nil {
+			return err
+		}
+		data.requestType = readSlashingEvidenceRequestByID
+		data.value = id
+	} else if offenderIn, ok := input["offender"]; ok {
+		offenderID, err := parseID("offender", offenderIn)
+		if err != nil {
+			return err
+		}
+		data.requestType = readSlashingEvidenceRequestByOffender
+		data.value = offenderID
+	}
+
+	return nil
+}
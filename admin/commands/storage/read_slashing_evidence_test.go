@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/slashing"
+	storagemock "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestReadSlashingEvidence(t *testing.T) {
+	nodeID := unittest.IdentifierFixture()
+	evidence1 := unittest.SlashingEvidenceFixture()
+	evidence2 := unittest.SlashingEvidenceFixture()
+
+	// runCommand runs the command, and decodes its result as an evidence bundle
+	runCommand := func(t *testing.T, store *storagemock.SlashingEvidence, data interface{}) *slashing.EvidenceBundle {
+		command := NewReadSlashingEvidenceCommand(nodeID, store)
+
+		req := &admin.CommandRequest{
+			Data: data,
+		}
+		require.NoError(t, command.Validator(req))
+
+		result, err := command.Handler(context.Background(), req)
+		require.NoError(t, err)
+
+		encoded, err := json.Marshal(result)
+		require.NoError(t, err)
+
+		var bundle slashing.EvidenceBundle
+		require.NoError(t, json.Unmarshal(encoded, &bundle))
+
+		assert.Equal(t, uint(slashing.EvidenceBundleVersion), bundle.Version)
+		assert.Equal(t, nodeID, bundle.RecorderID)
+		return &bundle
+	}
+
+	evidenceIDs := func(bundle *slashing.EvidenceBundle) []flow.Identifier {
+		ids := make([]flow.Identifier, 0, len(bundle.Evidence))
+		for _, evidence := range bundle.Evidence {
+			ids = append(ids, evidence.ID())
+		}
+		return ids
+	}
+
+	t.Run("all", func(t *testing.T) {
+		store := storagemock.NewSlashingEvidence(t)
+		store.On("All").Return([]*flow.SlashingEvidence{evidence1, evidence2}, nil)
+
+		bundle := runCommand(t, store, nil)
+		assert.Equal(t, []flow.Identifier{evidence1.ID(), evidence2.ID()}, evidenceIDs(bundle))
+	})
+
+	t.Run("by ID", func(t *testing.T) {
+		store := storagemock.NewSlashingEvidence(t)
+		store.On("ByID", evidence1.ID()).Return(evidence1, nil)
+
+		bundle := runCommand(t, store, map[string]interface{}{
+			"id": evidence1.ID().String(),
+		})
+		assert.Equal(t, []flow.Identifier{evidence1.ID()}, evidenceIDs(bundle))
+	})
+
+	t.Run("by offender", func(t *testing.T) {
+		store := storagemock.NewSlashingEvidence(t)
+		store.On("ByOffender", evidence2.OffenderID).Return([]*flow.SlashingEvidence{evidence2}, nil)
+
+		bundle := runCommand(t, store, map[string]interface{}{
+			"offender": evidence2.OffenderID.String(),
+		})
+		assert.Equal(t, []flow.Identifier{evidence2.ID()}, evidenceIDs(bundle))
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		command := NewReadSlashingEvidenceCommand(nodeID, storagemock.NewSlashingEvidence(t))
+
+		for _, data := range []interface{}{
+			"not a map",
+			map[string]interface{}{"id": "not an ID"},
+			map[string]interface{}{"offender": 1},
+		} {
+			err := command.Validator(&admin.CommandRequest{Data: data})
+			assert.True(t, admin.IsInvalidAdminParameterError(err), "unexpected error for %v: %v", data, err)
+		}
+	})
+}
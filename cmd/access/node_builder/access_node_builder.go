@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ipfs/go-cid"
 	badger "github.com/ipfs/go-ds-badger2"
 	"github.com/libp2p/go-libp2p/core/host"
 	"github.com/libp2p/go-libp2p/core/routing"
@@ -22,6 +23,7 @@ import (
 	"github.com/onflow/go-bitswap"
 
 	"github.com/onflow/flow-go/admin/commands"
+	accessCommands "github.com/onflow/flow-go/admin/commands/access"
 	stateSyncCommands "github.com/onflow/flow-go/admin/commands/state_synchronization"
 	storageCommands "github.com/onflow/flow-go/admin/commands/storage"
 	"github.com/onflow/flow-go/cmd"
@@ -37,25 +39,34 @@ import (
 	"github.com/onflow/flow-go/crypto"
 	"github.com/onflow/flow-go/engine/access/ingestion"
 	pingeng "github.com/onflow/flow-go/engine/access/ping"
+	"github.com/onflow/flow-go/engine/access/rest"
 	"github.com/onflow/flow-go/engine/access/rpc"
 	"github.com/onflow/flow-go/engine/access/rpc/backend"
 	"github.com/onflow/flow-go/engine/access/state_stream"
 	followereng "github.com/onflow/flow-go/engine/common/follower"
 	"github.com/onflow/flow-go/engine/common/requester"
 	synceng "github.com/onflow/flow-go/engine/common/synchronization"
+	"github.com/onflow/flow-go/engine/execution/computation/query"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/complete/wal"
+	"github.com/onflow/flow-go/model/bootstrap"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/flow/filter"
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/module/blobs"
 	"github.com/onflow/flow-go/module/chainsync"
 	modulecompliance "github.com/onflow/flow-go/module/compliance"
+	"github.com/onflow/flow-go/module/execution"
 	"github.com/onflow/flow-go/module/executiondatasync/execution_data"
+	"github.com/onflow/flow-go/module/executiondatasync/pruner"
+	"github.com/onflow/flow-go/module/executiondatasync/tracker"
 	finalizer "github.com/onflow/flow-go/module/finalizer/consensus"
 	"github.com/onflow/flow-go/module/id"
 	"github.com/onflow/flow-go/module/mempool/stdmap"
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/module/metrics/unstaked"
 	"github.com/onflow/flow-go/module/state_synchronization"
+	"github.com/onflow/flow-go/module/state_synchronization/indexer"
 	edrequester "github.com/onflow/flow-go/module/state_synchronization/requester"
 	"github.com/onflow/flow-go/network"
 	netcache "github.com/onflow/flow-go/network/cache"
@@ -125,6 +136,16 @@ type AccessNodeConfig struct {
 	executionDataDir             string
 	executionDataStartHeight     uint64
 	executionDataConfig          edrequester.ExecutionDataConfig
+	executionDataRetention       pruner.RetentionPolicy
+	eventsIndexEnabled           bool
+	registersIndexEnabled        bool
+	registersIndexDir            string
+	registersIndexCheckpoint     string
+	scriptExecutionMode          string
+	txStateChecks                backend.TransactionStateChecks
+	enHealthRoutingEnabled       bool
+	enSelectorConfig             backend.ExecutionNodeSelectorConfig
+	scriptResultCacheSize        uint32
 	PublicNetworkConfig          PublicNetworkConfig
 }
 
@@ -156,6 +177,7 @@ func DefaultAccessNodeConfig() *AccessNodeConfig {
 			PreferredExecutionNodeIDs: nil,
 			FixedExecutionNodeIDs:     nil,
 			MaxMsgSize:                grpcutils.DefaultMaxMsgSize,
+			WebsocketConfig:           rest.DefaultWebsocketConfig,
 		},
 		stateStreamConf: state_stream.Config{
 			MaxExecutionDataMsgSize: grpcutils.DefaultMaxMsgSize,
@@ -183,6 +205,14 @@ func DefaultAccessNodeConfig() *AccessNodeConfig {
 		executionDataSyncEnabled: true,
 		executionDataDir:         filepath.Join(homedir, ".flow", "execution_data"),
 		executionDataStartHeight: 0,
+		eventsIndexEnabled:       false,
+		registersIndexEnabled:    false,
+		registersIndexDir:        filepath.Join(homedir, ".flow", "registers"),
+		registersIndexCheckpoint: "",
+		scriptExecutionMode:      backend.ScriptExecutionModeExecutionNodesOnly.String(),
+		enHealthRoutingEnabled:   false,
+		enSelectorConfig:         backend.DefaultExecutionNodeSelectorConfig(),
+		scriptResultCacheSize:    0,
 		executionDataConfig: edrequester.ExecutionDataConfig{
 			InitialBlockHeight: 0,
 			MaxSearchAhead:     edrequester.DefaultMaxSearchAhead,
@@ -191,6 +221,12 @@ func DefaultAccessNodeConfig() *AccessNodeConfig {
 			RetryDelay:         edrequester.DefaultRetryDelay,
 			MaxRetryDelay:      edrequester.DefaultMaxRetryDelay,
 		},
+		executionDataRetention: pruner.RetentionPolicy{
+			HeightRangeTarget: 0,
+			Threshold:         100_000,
+			MaxAge:            0,
+			DiskBudget:        0,
+		},
 	}
 }
 
@@ -223,6 +259,14 @@ type FlowAccessNodeBuilder struct {
 	ExecutionDataDownloader    execution_data.Downloader
 	ExecutionDataRequester     state_synchronization.ExecutionDataRequester
 	ExecutionDataStore         execution_data.ExecutionDataStore
+	ExecutionDataTracker       tracker.Storage
+	ExecutionDataPruner        *pruner.Pruner
+	ExecutionDataPins          *pruner.Pins
+	EventsIndexer              *indexer.EventsIndexer
+	RegistersIndexer           *indexer.RegistersIndexer
+	ScriptExecutor             *execution.Scripts
+	ExecutionNodeSelector      *backend.ExecutionNodeSelector
+	ScriptResultCache          *backend.ScriptResultCache
 
 	// The sync engine participants provider is the libp2p peer store for the access node
 	// which is not available until after the network has started.
@@ -440,6 +484,12 @@ func (builder *FlowAccessNodeBuilder) BuildExecutionDataRequester() *FlowAccessN
 		AdminCommand("read-execution-data", func(config *cmd.NodeConfig) commands.AdminCommand {
 			return stateSyncCommands.NewReadExecutionDataCommand(builder.ExecutionDataStore)
 		}).
+		AdminCommand("get-execution-data-pruner", func(config *cmd.NodeConfig) commands.AdminCommand {
+			return stateSyncCommands.NewGetExecutionDataPrunerCommand(builder.ExecutionDataPruner)
+		}).
+		AdminCommand("set-execution-data-retention", func(config *cmd.NodeConfig) commands.AdminCommand {
+			return stateSyncCommands.NewSetExecutionDataRetentionCommand(builder.ExecutionDataPruner)
+		}).
 		Module("execution data datastore and blobstore", func(node *cmd.NodeConfig) error {
 			datastoreDir := filepath.Join(builder.executionDataDir, "blobstore")
 			err := os.MkdirAll(datastoreDir, 0700)
@@ -461,6 +511,32 @@ func (builder *FlowAccessNodeBuilder) BuildExecutionDataRequester() *FlowAccessN
 
 			return nil
 		}).
+		Module("execution data tracker", func(node *cmd.NodeConfig) error {
+			// the tracker is bootstrapped at the height execution data is first synced from
+			trackerStartHeight, err := builder.executionDataRootHeight(node)
+			if err != nil {
+				return err
+			}
+			if builder.executionDataStartHeight > 0 {
+				trackerStartHeight = builder.executionDataStartHeight - 1
+			}
+
+			blobstore := blobs.NewBlobstore(ds)
+			trackerStorage, err := tracker.OpenStorage(
+				filepath.Join(builder.executionDataDir, "tracker"),
+				trackerStartHeight,
+				node.Logger,
+				tracker.WithPruneCallback(func(c cid.Cid) error {
+					return blobstore.DeleteBlob(context.TODO(), c)
+				}),
+			)
+			if err != nil {
+				return fmt.Errorf("could not open execution data tracker: %w", err)
+			}
+			builder.ExecutionDataTracker = trackerStorage
+			builder.ExecutionDataPins = pruner.NewPins()
+			return nil
+		}).
 		Module("processed block height consumer progress", func(node *cmd.NodeConfig) error {
 			// uses the datastore's DB
 			processedBlockHeight = bstorage.NewConsumerProgress(ds.DB, module.ConsumeProgressExecutionDataRequesterBlockHeight)
@@ -471,6 +547,118 @@ func (builder *FlowAccessNodeBuilder) BuildExecutionDataRequester() *FlowAccessN
 			processedNotifications = bstorage.NewConsumerProgress(ds.DB, module.ConsumeProgressExecutionDataRequesterNotification)
 			return nil
 		}).
+		Module("events indexer", func(node *cmd.NodeConfig) error {
+			if !builder.eventsIndexEnabled {
+				return nil
+			}
+
+			// events and the indexed height range are stored in the protocol DB
+			var err error
+			builder.EventsIndexer, err = indexer.NewEventsIndexer(
+				node.Logger,
+				node.DB,
+				node.Storage.Headers,
+				node.Storage.Events,
+				bstorage.NewConsumerProgress(node.DB, module.ConsumeProgressEventsIndexerLowestHeight),
+				bstorage.NewConsumerProgress(node.DB, module.ConsumeProgressEventsIndexerHighestHeight),
+			)
+			if err != nil {
+				return fmt.Errorf("could not create events indexer: %w", err)
+			}
+			return nil
+		}).
+		Module("registers indexer", func(node *cmd.NodeConfig) error {
+			if !builder.registersIndexEnabled {
+				return nil
+			}
+
+			err := os.MkdirAll(builder.registersIndexDir, 0700)
+			if err != nil {
+				return err
+			}
+
+			registersDS, err := badger.NewDatastore(builder.registersIndexDir, &badger.DefaultOptions)
+			if err != nil {
+				return fmt.Errorf("could not open registers index db: %w", err)
+			}
+
+			builder.ShutdownFunc(func() error {
+				if err := registersDS.Close(); err != nil {
+					return fmt.Errorf("could not close registers index db: %w", err)
+				}
+				return nil
+			})
+
+			builder.RegistersIndexer, err = indexer.NewRegistersIndexer(
+				node.Logger,
+				registersDS.DB,
+				node.Storage.Headers,
+				bstorage.NewRegisters(registersDS.DB),
+				bstorage.NewConsumerProgress(registersDS.DB, module.ConsumeProgressRegistersIndexerLowestHeight),
+				bstorage.NewConsumerProgress(registersDS.DB, module.ConsumeProgressRegistersIndexerHighestHeight),
+			)
+			if err != nil {
+				return fmt.Errorf("could not create registers indexer: %w", err)
+			}
+
+			if _, _, ok := builder.RegistersIndexer.IndexedHeightRange(); ok {
+				return nil
+			}
+
+			// the index is bootstrapped with the execution state of the sealed root block, so
+			// execution data must be synced starting from the sealed root block
+			sealedRootHeight, err := builder.sealedRootHeight(node)
+			if err != nil {
+				return err
+			}
+
+			checkpointFile := builder.registersIndexCheckpoint
+			if checkpointFile == "" {
+				checkpointFile = filepath.Join(node.BootstrapDir, bootstrap.PathRootCheckpoint)
+			}
+			checkpointDir, checkpointName := filepath.Split(checkpointFile)
+
+			node.Logger.Info().
+				Str("checkpoint_file", checkpointFile).
+				Uint64("height", sealedRootHeight).
+				Msg("bootstrapping registers index from checkpoint")
+
+			err = builder.RegistersIndexer.Bootstrap(sealedRootHeight, func(processPayload func(*ledger.Payload) error) error {
+				rootHash, err := wal.ReadPayloadsFromCheckpointV6(checkpointDir, checkpointName, &node.Logger, processPayload)
+				if err != nil {
+					return fmt.Errorf("could not read checkpoint: %w", err)
+				}
+				// the index is only marked as bootstrapped if the checkpoint is the sealed root state
+				if flow.StateCommitment(rootHash) != node.RootSeal.FinalState {
+					return fmt.Errorf("checkpoint state %v does not match the sealed root state %v", rootHash, node.RootSeal.FinalState)
+				}
+				return nil
+			})
+			if err != nil {
+				return fmt.Errorf("could not bootstrap registers index: %w", err)
+			}
+			return nil
+		}).
+		Module("script executor", func(node *cmd.NodeConfig) error {
+			if builder.RegistersIndexer == nil {
+				return nil
+			}
+
+			var err error
+			builder.ScriptExecutor, err = execution.NewScripts(
+				node.Logger,
+				metrics.NewNoopCollector(),
+				node.RootChainID,
+				node.FvmOptions,
+				query.NewDefaultConfig(),
+				node.Storage.Headers,
+				builder.RegistersIndexer,
+			)
+			if err != nil {
+				return fmt.Errorf("could not create script executor: %w", err)
+			}
+			return nil
+		}).
 		Module("blobservice peer manager dependencies", func(node *cmd.NodeConfig) error {
 			bsDependable = module.NewProxiedReadyDoneAware()
 			builder.PeerManagerDependencies.Add(bsDependable)
@@ -506,7 +694,10 @@ func (builder *FlowAccessNodeBuilder) BuildExecutionDataRequester() *FlowAccessN
 			// to be ready before starting
 			bsDependable.Init(bs)
 
-			builder.ExecutionDataDownloader = execution_data.NewDownloader(bs)
+			builder.ExecutionDataDownloader = execution_data.NewDownloader(
+				bs,
+				execution_data.WithExecutionDataTracker(builder.ExecutionDataTracker, node.Storage.Headers),
+			)
 
 			return builder.ExecutionDataDownloader, nil
 		}).
@@ -538,7 +729,11 @@ func (builder *FlowAccessNodeBuilder) BuildExecutionDataRequester() *FlowAccessN
 				// requester expects the initial last processed height, which is the first height - 1
 				builder.executionDataConfig.InitialBlockHeight = builder.executionDataStartHeight - 1
 			} else {
-				builder.executionDataConfig.InitialBlockHeight = builder.RootBlock.Header.Height
+				rootHeight, err := builder.executionDataRootHeight(node)
+				if err != nil {
+					return nil, err
+				}
+				builder.executionDataConfig.InitialBlockHeight = rootHeight
 			}
 
 			execDataDistributor = edrequester.NewExecutionDataDistributor()
@@ -559,7 +754,60 @@ func (builder *FlowAccessNodeBuilder) BuildExecutionDataRequester() *FlowAccessN
 			builder.FinalizationDistributor.AddOnBlockFinalizedConsumer(builder.ExecutionDataRequester.OnBlockFinalized)
 			builder.ExecutionDataRequester.AddOnExecutionDataReceivedConsumer(execDataDistributor.OnExecutionDataReceived)
 
+			if builder.EventsIndexer != nil {
+				execDataDistributor.AddOnExecutionDataReceivedConsumer(builder.EventsIndexer.OnExecutionData)
+			}
+			if builder.RegistersIndexer != nil {
+				execDataDistributor.AddOnExecutionDataReceivedConsumer(builder.RegistersIndexer.OnExecutionData)
+			}
+
 			return builder.ExecutionDataRequester, nil
+		}).
+		Component("execution data pruner", func(node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
+			var prunerMetrics module.ExecutionDataPrunerMetrics = metrics.NewNoopCollector()
+			if node.MetricsEnabled {
+				prunerMetrics = metrics.NewExecutionDataPrunerCollector()
+			}
+
+			// by default, pruning is disabled. the pruner is always created so that a retention
+			// policy can be enabled at runtime using the set-execution-data-retention admin command
+			var err error
+			builder.ExecutionDataPruner, err = pruner.NewPruner(
+				node.Logger,
+				prunerMetrics,
+				builder.ExecutionDataTracker,
+				pruner.WithPruneCallback(func(ctx context.Context) error {
+					return ds.CollectGarbage(ctx)
+				}),
+				pruner.WithHeightRangeTarget(builder.executionDataRetention.HeightRangeTarget),
+				pruner.WithThreshold(builder.executionDataRetention.Threshold),
+				pruner.WithMaxAge(builder.executionDataRetention.MaxAge),
+				pruner.WithDiskBudget(builder.executionDataRetention.DiskBudget),
+				pruner.WithHeaders(node.Storage.Headers),
+				pruner.WithDiskUsage(ds.DiskUsage),
+				pruner.WithPins(builder.ExecutionDataPins),
+			)
+			if err != nil {
+				return nil, fmt.Errorf("could not create execution data pruner: %w", err)
+			}
+
+			// execution data is received in height order, so the height of the latest received
+			// execution data is the fulfilled height
+			execDataDistributor.AddOnExecutionDataReceivedConsumer(func(executionData *execution_data.BlockExecutionDataEntity) {
+				header, err := node.Storage.Headers.ByBlockID(executionData.BlockID)
+				if err != nil {
+					node.Logger.Fatal().Err(err).Msg("could not get header for received execution data")
+				}
+
+				err = builder.ExecutionDataTracker.SetFulfilledHeight(header.Height)
+				if err != nil {
+					node.Logger.Fatal().Err(err).Msg("could not set execution data fulfilled height")
+				}
+
+				builder.ExecutionDataPruner.NotifyFulfilledHeight(header.Height)
+			})
+
+			return builder.ExecutionDataPruner, nil
 		})
 
 	if builder.stateStreamConf.ListenAddr != "" {
@@ -572,9 +820,14 @@ func (builder *FlowAccessNodeBuilder) BuildExecutionDataRequester() *FlowAccessN
 					builder.stateStreamConf.MaxAddresses = value
 				case "Contracts":
 					builder.stateStreamConf.MaxContracts = value
+				case "FieldFilters":
+					builder.stateStreamConf.MaxFieldFilters = value
+				case "FieldFilterValues":
+					builder.stateStreamConf.MaxFieldFilterValues = value
 				}
 			}
 			builder.stateStreamConf.RpcMetricsEnabled = builder.rpcMetricsEnabled
+			builder.rpcConf.WebsocketConfig.EventFilterConfig = builder.stateStreamConf.EventFilterConfig
 
 			var heroCacheCollector module.HeroCacheMetrics = metrics.NewNoopCollector()
 			if builder.HeroCacheMetricsEnable {
@@ -593,6 +846,7 @@ func (builder *FlowAccessNodeBuilder) BuildExecutionDataRequester() *FlowAccessN
 				builder.apiRatelimits,
 				builder.apiBurstlimits,
 				heroCacheCollector,
+				builder.ExecutionDataPins,
 			)
 			if err != nil {
 				return nil, fmt.Errorf("could not create state stream engine: %w", err)
@@ -600,6 +854,7 @@ func (builder *FlowAccessNodeBuilder) BuildExecutionDataRequester() *FlowAccessN
 			builder.StateStreamEng = stateStreamEng
 
 			execDataDistributor.AddOnExecutionDataReceivedConsumer(builder.StateStreamEng.OnExecutionData)
+			builder.FinalizationDistributor.AddOnBlockFinalizedConsumer(builder.StateStreamEng.OnFinalizedBlock)
 
 			return builder.StateStreamEng, nil
 		})
@@ -654,6 +909,15 @@ func (builder *FlowAccessNodeBuilder) extraFlags() {
 		flags.BoolVar(&builder.pingEnabled, "ping-enabled", defaultConfig.pingEnabled, "whether to enable the ping process that pings all other peers and report the connectivity to metrics")
 		flags.BoolVar(&builder.retryEnabled, "retry-enabled", defaultConfig.retryEnabled, "whether to enable the retry mechanism at the access node level")
 		flags.BoolVar(&builder.rpcMetricsEnabled, "rpc-metrics-enabled", defaultConfig.rpcMetricsEnabled, "whether to enable the rpc metrics")
+		flags.BoolVar(&builder.txStateChecks.CheckSignatures, "check-tx-signatures", defaultConfig.txStateChecks.CheckSignatures, "whether to verify the signatures of submitted transactions against the account keys at the latest sealed block")
+		flags.BoolVar(&builder.txStateChecks.CheckPayerBalance, "check-payer-balance", defaultConfig.txStateChecks.CheckPayerBalance, "whether to check that the payer of submitted transactions can pay the transaction fees at the latest sealed block")
+		flags.BoolVar(&builder.txStateChecks.DryRun, "tx-state-checks-dry-run", defaultConfig.txStateChecks.DryRun, "whether to only log and count transactions failing the signature and payer balance checks, instead of rejecting them")
+		flags.Uint32Var(&builder.scriptResultCacheSize, "script-result-cache-size", defaultConfig.scriptResultCacheSize, "number of results of scripts executed against sealed blocks to cache, 0 to disable caching. Results of scripts calling unsafeRandom, directly or through contracts, are not cached, nor are results from execution nodes which do not report the computation used by scripts")
+		flags.BoolVar(&builder.enHealthRoutingEnabled, "execution-node-health-routing-enabled", defaultConfig.enHealthRoutingEnabled, "whether to choose the execution nodes requests are sent to by their observed latency, error rate and executed height, instead of randomly")
+		flags.DurationVar(&builder.enSelectorConfig.HedgeDelay, "execution-node-hedge-delay", defaultConfig.enSelectorConfig.HedgeDelay, "time to wait for an execution node to respond to a script or account query before also sending it to the next one, 0 to disable hedging. requires --execution-node-health-routing-enabled")
+		flags.UintVar(&builder.enSelectorConfig.FailureThreshold, "execution-node-failure-threshold", defaultConfig.enSelectorConfig.FailureThreshold, "number of consecutive failed requests after which requests to an execution node are suspended, 0 to disable. requires --execution-node-health-routing-enabled")
+		flags.DurationVar(&builder.enSelectorConfig.SuspensionDuration, "execution-node-suspension-duration", defaultConfig.enSelectorConfig.SuspensionDuration, "time requests to a failing execution node are suspended for. requires --execution-node-health-routing-enabled")
+		flags.Uint64Var(&builder.enSelectorConfig.MaxHeightLag, "execution-node-max-height-lag", defaultConfig.enSelectorConfig.MaxHeightLag, "number of blocks an execution node may lag behind the others before it is only used as a last resort. requires --execution-node-health-routing-enabled")
 		flags.StringVarP(&builder.nodeInfoFile, "node-info-file", "", defaultConfig.nodeInfoFile, "full path to a json file which provides more details about nodes when reporting its reachability metrics")
 		flags.StringToIntVar(&builder.apiRatelimits, "api-rate-limits", defaultConfig.apiRatelimits, "per second rate limits for Access API methods e.g. Ping=300,GetTransaction=500 etc.")
 		flags.StringToIntVar(&builder.apiBurstlimits, "api-burst-limits", defaultConfig.apiBurstlimits, "burst limits for Access API methods e.g. Ping=100,GetTransaction=100 etc.")
@@ -668,7 +932,16 @@ func (builder *FlowAccessNodeBuilder) extraFlags() {
 		flags.DurationVar(&builder.executionDataConfig.FetchTimeout, "execution-data-fetch-timeout", defaultConfig.executionDataConfig.FetchTimeout, "initial timeout to use when fetching execution data from the network. timeout increases using an incremental backoff until execution-data-max-fetch-timeout. e.g. 30s")
 		flags.DurationVar(&builder.executionDataConfig.MaxFetchTimeout, "execution-data-max-fetch-timeout", defaultConfig.executionDataConfig.MaxFetchTimeout, "maximum timeout to use when fetching execution data from the network e.g. 300s")
 		flags.DurationVar(&builder.executionDataConfig.RetryDelay, "execution-data-retry-delay", defaultConfig.executionDataConfig.RetryDelay, "initial delay for exponential backoff when fetching execution data fails e.g. 10s")
+		flags.Uint64Var(&builder.executionDataRetention.HeightRangeTarget, "execution-data-height-range-target", defaultConfig.executionDataRetention.HeightRangeTarget, "target height range size used to limit the amount of Execution Data kept on disk. 0 disables height based pruning")
+		flags.Uint64Var(&builder.executionDataRetention.Threshold, "execution-data-height-range-threshold", defaultConfig.executionDataRetention.Threshold, "height threshold used to trigger Execution Data pruning")
+		flags.DurationVar(&builder.executionDataRetention.MaxAge, "execution-data-max-age", defaultConfig.executionDataRetention.MaxAge, "maximum age of the blocks to keep Execution Data on disk for, based on block timestamps. 0 disables age based pruning")
+		flags.Uint64Var(&builder.executionDataRetention.DiskBudget, "execution-data-disk-budget", defaultConfig.executionDataRetention.DiskBudget, "maximum number of bytes of Execution Data kept on disk. 0 disables disk budget based pruning")
 		flags.DurationVar(&builder.executionDataConfig.MaxRetryDelay, "execution-data-max-retry-delay", defaultConfig.executionDataConfig.MaxRetryDelay, "maximum delay for exponential backoff when fetching execution data fails e.g. 5m")
+		flags.BoolVar(&builder.eventsIndexEnabled, "execution-data-events-index-enabled", defaultConfig.eventsIndexEnabled, "whether to index events from downloaded execution data and serve event queries from the local index. requires execution-data-sync-enabled")
+		flags.BoolVar(&builder.registersIndexEnabled, "execution-data-registers-index-enabled", defaultConfig.registersIndexEnabled, "whether to index registers from downloaded execution data, allowing scripts to be executed locally. the index is bootstrapped from the root checkpoint, so execution data must be synced from the root block. requires execution-data-sync-enabled")
+		flags.StringVar(&builder.registersIndexDir, "execution-data-registers-index-dir", defaultConfig.registersIndexDir, "directory to use for the registers index database")
+		flags.StringVar(&builder.registersIndexCheckpoint, "execution-data-registers-index-checkpoint", defaultConfig.registersIndexCheckpoint, "checkpoint file with the execution state of the sealed root block of the bootstrap snapshot, used to bootstrap the registers index (defaults to the root checkpoint in the bootstrap dir)")
+		flags.StringVar(&builder.scriptExecutionMode, "script-execution-mode", defaultConfig.scriptExecutionMode, "where scripts are executed: execution-nodes-only, local-only, failover (local, falling back to execution nodes) or compare (execution nodes, comparing with local results). all modes except execution-nodes-only require execution-data-registers-index-enabled")
 
 		// Execution State Streaming API
 		flags.Uint32Var(&builder.stateStreamConf.ExecutionDataCacheSize, "execution-data-cache-size", defaultConfig.stateStreamConf.ExecutionDataCacheSize, "block execution data cache size")
@@ -676,11 +949,31 @@ func (builder *FlowAccessNodeBuilder) extraFlags() {
 		flags.UintVar(&builder.stateStreamConf.MaxExecutionDataMsgSize, "state-stream-max-message-size", defaultConfig.stateStreamConf.MaxExecutionDataMsgSize, "maximum size for a gRPC message containing block execution data")
 		flags.DurationVar(&builder.stateStreamConf.ClientSendTimeout, "state-stream-send-timeout", defaultConfig.stateStreamConf.ClientSendTimeout, "maximum wait before timing out while sending a response to a streaming client e.g. 30s")
 		flags.UintVar(&builder.stateStreamConf.ClientSendBufferSize, "state-stream-send-buffer-size", defaultConfig.stateStreamConf.ClientSendBufferSize, "maximum number of responses to buffer within a stream")
-		flags.StringToIntVar(&builder.stateStreamFilterConf, "state-stream-event-filter-limits", defaultConfig.stateStreamFilterConf, "event filter limits for ExecutionData SubscribeEvents API e.g. EventTypes=100,Addresses=100,Contracts=100 etc.")
+		flags.StringToIntVar(&builder.stateStreamFilterConf, "state-stream-event-filter-limits", defaultConfig.stateStreamFilterConf, "event filter limits for ExecutionData SubscribeEvents API e.g. EventTypes=100,Addresses=100,Contracts=100,FieldFilters=10,FieldFilterValues=100 etc.")
+
+		// REST websocket subscriptions
+		flags.Uint32Var(&builder.rpcConf.WebsocketConfig.MaxConnections, "rest-websocket-max-connections", defaultConfig.rpcConf.WebsocketConfig.MaxConnections, "maximum number of concurrent websocket connections on the REST server")
+		flags.Uint32Var(&builder.rpcConf.WebsocketConfig.MaxSubscriptionsPerConnection, "rest-websocket-max-subscriptions-per-connection", defaultConfig.rpcConf.WebsocketConfig.MaxSubscriptionsPerConnection, "maximum number of concurrent subscriptions on a single websocket connection")
+		flags.Float64Var(&builder.rpcConf.WebsocketConfig.MaxResponsesPerSecond, "rest-websocket-max-responses-per-second", defaultConfig.rpcConf.WebsocketConfig.MaxResponsesPerSecond, "maximum number of responses sent per second on a single websocket connection")
+		flags.DurationVar(&builder.rpcConf.WebsocketConfig.HeartbeatInterval, "rest-websocket-heartbeat-interval", defaultConfig.rpcConf.WebsocketConfig.HeartbeatInterval, "interval at which ping messages are sent to websocket clients e.g. 20s")
+		flags.DurationVar(&builder.rpcConf.WebsocketConfig.SendTimeout, "rest-websocket-send-timeout", defaultConfig.rpcConf.WebsocketConfig.SendTimeout, "maximum wait before timing out while sending a message to a websocket client e.g. 10s")
 	}).ValidateFlags(func() error {
 		if builder.supportsObserver && (builder.PublicNetworkConfig.BindAddress == cmd.NotSet || builder.PublicNetworkConfig.BindAddress == "") {
 			return errors.New("public-network-address must be set if supports-observer is true")
 		}
+		if builder.eventsIndexEnabled && !builder.executionDataSyncEnabled {
+			return errors.New("execution-data-sync-enabled must be set if execution-data-events-index-enabled is true")
+		}
+		if builder.registersIndexEnabled && !builder.executionDataSyncEnabled {
+			return errors.New("execution-data-sync-enabled must be set if execution-data-registers-index-enabled is true")
+		}
+		scriptExecMode, err := backend.ParseScriptExecutionMode(builder.scriptExecutionMode)
+		if err != nil {
+			return err
+		}
+		if scriptExecMode != backend.ScriptExecutionModeExecutionNodesOnly && !builder.registersIndexEnabled {
+			return fmt.Errorf("execution-data-registers-index-enabled must be set if script-execution-mode is %s", scriptExecMode)
+		}
 		if builder.executionDataSyncEnabled {
 			if builder.executionDataConfig.FetchTimeout <= 0 {
 				return errors.New("execution-data-fetch-timeout must be greater than 0")
@@ -698,6 +991,17 @@ func (builder *FlowAccessNodeBuilder) extraFlags() {
 				return errors.New("execution-data-max-search-ahead must be greater than 0")
 			}
 		}
+		if builder.rpcConf.RESTListenAddr != "" && builder.stateStreamConf.ListenAddr != "" {
+			if builder.rpcConf.WebsocketConfig.MaxResponsesPerSecond <= 0 {
+				return errors.New("rest-websocket-max-responses-per-second must be greater than 0")
+			}
+			if builder.rpcConf.WebsocketConfig.HeartbeatInterval <= 0 {
+				return errors.New("rest-websocket-heartbeat-interval must be greater than 0")
+			}
+			if builder.rpcConf.WebsocketConfig.SendTimeout <= 0 {
+				return errors.New("rest-websocket-send-timeout must be greater than 0")
+			}
+		}
 		if builder.stateStreamConf.ListenAddr != "" {
 			if builder.stateStreamConf.ExecutionDataCacheSize == 0 {
 				return errors.New("execution-data-cache-size must be greater than 0")
@@ -705,25 +1009,54 @@ func (builder *FlowAccessNodeBuilder) extraFlags() {
 			if builder.stateStreamConf.ClientSendBufferSize == 0 {
 				return errors.New("state-stream-send-buffer-size must be greater than 0")
 			}
-			if len(builder.stateStreamFilterConf) > 3 {
-				return errors.New("state-stream-event-filter-limits must have at most 3 keys (EventTypes, Addresses, Contracts)")
+			if len(builder.stateStreamFilterConf) > 5 {
+				return errors.New("state-stream-event-filter-limits must have at most 5 keys (EventTypes, Addresses, Contracts, FieldFilters, FieldFilterValues)")
 			}
 			for key, value := range builder.stateStreamFilterConf {
 				switch key {
-				case "EventTypes", "Addresses", "Contracts":
+				case "EventTypes", "Addresses", "Contracts", "FieldFilters", "FieldFilterValues":
 					if value <= 0 {
 						return fmt.Errorf("state-stream-event-filter-limits %s must be greater than 0", key)
 					}
 				default:
-					return errors.New("state-stream-event-filter-limits may only contain the keys EventTypes, Addresses, Contracts")
+					return errors.New("state-stream-event-filter-limits may only contain the keys EventTypes, Addresses, Contracts, FieldFilters, FieldFilterValues")
 				}
 			}
 		}
+		if builder.enHealthRoutingEnabled {
+			if builder.enSelectorConfig.HedgeDelay < 0 {
+				return errors.New("execution-node-hedge-delay must not be negative")
+			}
+			if builder.enSelectorConfig.FailureThreshold > 0 && builder.enSelectorConfig.SuspensionDuration <= 0 {
+				return errors.New("execution-node-suspension-duration must be greater than 0")
+			}
+		}
 
 		return nil
 	})
 }
 
+// sealedRootHeight returns the height of the sealed root block of the bootstrap snapshot, whose
+// execution state is contained in the root checkpoint. It is lower than the height of the root
+// block if the node was bootstrapped from a snapshot taken in the middle of a spork.
+func (builder *FlowAccessNodeBuilder) sealedRootHeight(node *cmd.NodeConfig) (uint64, error) {
+	sealedRoot, err := node.Storage.Headers.ByBlockID(node.RootSeal.BlockID)
+	if err != nil {
+		return 0, fmt.Errorf("could not get sealed root block: %w", err)
+	}
+	return sealedRoot.Height, nil
+}
+
+// executionDataRootHeight returns the height after which execution data is synced, unless a start
+// height is configured. If the registers index is enabled, it is bootstrapped with the execution
+// state of the sealed root block, so execution data is synced from the sealed root block onwards.
+func (builder *FlowAccessNodeBuilder) executionDataRootHeight(node *cmd.NodeConfig) (uint64, error) {
+	if !builder.registersIndexEnabled {
+		return builder.RootBlock.Header.Height, nil
+	}
+	return builder.sealedRootHeight(node)
+}
+
 // initNetwork creates the network.Network implementation with the given metrics, middleware, initial list of network
 // participants and topology used to choose peers from the list of participants. The list of participants can later be
 // updated by calling network.SetIDs.
@@ -824,6 +1157,20 @@ func (builder *FlowAccessNodeBuilder) Initialize() error {
 		return storageCommands.NewGetTransactionsCommand(conf.State, conf.Storage.Payloads, conf.Storage.Collections)
 	})
 
+	// admin commands are created after all components, so the RPC engine is available
+	builder.AdminCommand("list-pending-transactions", func(conf *cmd.NodeConfig) commands.AdminCommand {
+		return accessCommands.NewListPendingTransactionsCommand(builder.RpcEng.TransactionRetry())
+	})
+	builder.AdminCommand("drop-pending-transaction", func(conf *cmd.NodeConfig) commands.AdminCommand {
+		return accessCommands.NewDropPendingTransactionCommand(builder.RpcEng.TransactionRetry())
+	})
+	builder.AdminCommand("get-execution-node-scoreboard", func(conf *cmd.NodeConfig) commands.AdminCommand {
+		return accessCommands.NewGetExecutionNodeScoreboardCommand(builder.ExecutionNodeSelector)
+	})
+	builder.AdminCommand("flush-script-result-cache", func(conf *cmd.NodeConfig) commands.AdminCommand {
+		return accessCommands.NewFlushScriptResultCacheCommand(builder.ScriptResultCache)
+	})
+
 	// if this is an access node that supports public followers, enqueue the public network
 	if builder.supportsObserver {
 		builder.enqueuePublicNetworkInit()
@@ -946,7 +1293,15 @@ func (builder *FlowAccessNodeBuilder) Build() (cmd.Node, error) {
 			tlsConfig := grpcutils.DefaultServerTLSConfig(x509Certificate)
 			builder.rpcConf.TransportCredentials = credentials.NewTLS(tlsConfig)
 			return nil
-		}).
+		})
+
+	// the state stream engine must be created before the RPC engine, since the REST server
+	// serves websocket subscriptions through the state stream API
+	if builder.executionDataSyncEnabled {
+		builder.BuildExecutionDataRequester()
+	}
+
+	builder.
 		Component("RPC engine", func(node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
 			engineBuilder, err := rpc.NewBuilder(
 				node.Logger,
@@ -974,6 +1329,46 @@ func (builder *FlowAccessNodeBuilder) Build() (cmd.Node, error) {
 				return nil, err
 			}
 
+			if builder.StateStreamEng != nil {
+				engineBuilder.WithStateStreamAPI(builder.StateStreamEng.API())
+			}
+
+			if builder.EventsIndexer != nil {
+				engineBuilder.WithEventsIndex(builder.EventsIndexer)
+			}
+
+			if builder.ScriptExecutor != nil {
+				// the mode was validated when parsing flags
+				scriptExecMode, err := backend.ParseScriptExecutionMode(builder.scriptExecutionMode)
+				if err != nil {
+					return nil, err
+				}
+				engineBuilder.WithScriptExecutor(builder.ScriptExecutor, scriptExecMode)
+			}
+
+			if builder.txStateChecks.CheckSignatures || builder.txStateChecks.CheckPayerBalance {
+				engineBuilder.WithTransactionStateChecks(builder.txStateChecks, metrics.NewTransactionValidationCollector())
+			}
+
+			if builder.enHealthRoutingEnabled {
+				builder.ExecutionNodeSelector = backend.NewExecutionNodeSelector(builder.enSelectorConfig, metrics.NewExecutionNodeHealthCollector())
+				engineBuilder.WithExecutionNodeSelector(builder.ExecutionNodeSelector)
+			}
+
+			if builder.scriptResultCacheSize > 0 {
+				builder.ScriptResultCache = backend.NewScriptResultCache(
+					builder.scriptResultCacheSize,
+					node.Logger,
+					metrics.AccessNodeScriptResultCacheMetrics(builder.MetricsRegisterer),
+				)
+				engineBuilder.WithScriptResultCache(builder.ScriptResultCache)
+			}
+
+			if builder.retryEnabled {
+				// persist pending transactions, so they are still retried after a restart
+				engineBuilder.WithTransactionRetryStorage(bstorage.NewTransactionRetries(node.DB))
+			}
+
 			builder.RpcEng, err = engineBuilder.
 				WithLegacy().
 				WithBlockSignerDecoder(signature.NewBlockSignerDecoder(builder.Committee)).
@@ -982,6 +1377,11 @@ func (builder *FlowAccessNodeBuilder) Build() (cmd.Node, error) {
 				return nil, err
 			}
 
+			if builder.StateStreamEng != nil {
+				// transaction status subscriptions derive statuses using the Access API backend
+				builder.StateStreamEng.SetTransactionsAPI(builder.RpcEng.API())
+			}
+
 			return builder.RpcEng, nil
 		}).
 		Component("ingestion engine", func(node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
@@ -1054,10 +1454,6 @@ func (builder *FlowAccessNodeBuilder) Build() (cmd.Node, error) {
 		})
 	}
 
-	if builder.executionDataSyncEnabled {
-		builder.BuildExecutionDataRequester()
-	}
-
 	builder.Component("ping engine", func(node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
 		ping, err := pingeng.New(
 			node.Logger,
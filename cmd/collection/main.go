@@ -13,6 +13,7 @@ import (
 	modulecompliance "github.com/onflow/flow-go/module/compliance"
 	"github.com/onflow/flow-go/module/mempool/herocache"
 	"github.com/onflow/flow-go/module/mempool/queue"
+	"github.com/onflow/flow-go/module/mempool/stdmap"
 	"github.com/onflow/flow-go/utils/grpcutils"
 
 	sdkcrypto "github.com/onflow/flow-go-sdk/crypto"
@@ -65,6 +66,9 @@ func main() {
 		builderPayerRateLimitDryRun       bool
 		builderPayerRateLimit             float64
 		builderUnlimitedPayers            []string
+		builderPriorityOrdering           bool
+		builderPriorityPayers             []string
+		builderGasLimitPriority           bool
 		hotstuffMinTimeout                time.Duration
 		hotstuffTimeoutAdjustmentFactor   float64
 		hotstuffHappyPathMaxRoundFailures uint64
@@ -135,11 +139,17 @@ func main() {
 			"maximum byte size of the proposed collection")
 		flags.Uint64Var(&maxCollectionTotalGas, "builder-max-collection-total-gas", flow.DefaultMaxCollectionTotalGas,
 			"maximum total amount of maxgas of transactions in proposed collections")
+		flags.BoolVar(&builderPriorityOrdering, "builder-priority-ordering", false,
+			"whether to order transactions by inclusion priority, and to include dependent transactions in proposal key sequence number order")
+		flags.StringSliceVar(&builderPriorityPayers, "builder-priority-payers", []string{}, // no priority payers
+			"set of payer addresses whose transactions are included first, if priority ordering is enabled")
+		flags.BoolVar(&builderGasLimitPriority, "builder-gas-limit-priority", false,
+			"whether to prioritize transactions with a higher declared gas limit, if priority ordering is enabled")
 		flags.DurationVar(&hotstuffMinTimeout, "hotstuff-min-timeout", 2500*time.Millisecond,
 			"the lower timeout bound for the hotstuff pacemaker, this is also used as initial timeout")
-		flags.Float64Var(&hotstuffTimeoutAdjustmentFactor, "hotstuff-timeout-adjustment-factor", timeout.DefaultConfig.TimeoutAdjustmentFactor,
+		flags.Float64Var(&hotstuffTimeoutAdjustmentFactor, "hotstuff-timeout-adjustment-factor", timeout.DefaultConfig.TimeoutAdjustmentFactor.Load(),
 			"adjustment of timeout duration in case of time out event")
-		flags.Uint64Var(&hotstuffHappyPathMaxRoundFailures, "hotstuff-happy-path-max-round-failures", timeout.DefaultConfig.HappyPathMaxRoundFailures,
+		flags.Uint64Var(&hotstuffHappyPathMaxRoundFailures, "hotstuff-happy-path-max-round-failures", timeout.DefaultConfig.HappyPathMaxRoundFailures.Load(),
 			"number of failed rounds before first timeout increase")
 		flags.DurationVar(&blockRateDelay, "block-rate-delay", 250*time.Millisecond,
 			"the delay to broadcast block proposal in order to control block production rate")
@@ -195,7 +205,17 @@ func main() {
 			return err
 		}).
 		Module("transactions mempool", func(node *cmd.NodeConfig) error {
+			// the builder config which determines the inclusion priority, if priority ordering is enabled
+			priorityConf := builder.DefaultConfig()
+			for _, apply := range builderPriorityOpts(builderPriorityOrdering, builderPriorityPayers, builderGasLimitPriority) {
+				apply(&priorityConf)
+			}
+
 			create := func(epoch uint64) mempool.Transactions {
+				if builderPriorityOrdering {
+					return stdmap.NewPriorityTransactions(txLimit, builder.TransactionPriority(priorityConf))
+				}
+
 				var heroCacheMetricsCollector module.HeroCacheMetrics = metrics.NewNoopCollector()
 				if node.BaseConfig.HeroCacheMetricsEnable {
 					heroCacheMetricsCollector = metrics.CollectionNodeTransactionsCacheMetrics(node.MetricsRegisterer, epoch)
@@ -448,13 +468,7 @@ func main() {
 				unlimitedPayers = append(unlimitedPayers, payerAddr)
 			}
 
-			builderFactory, err := factories.NewBuilderFactory(
-				node.DB,
-				node.Storage.Headers,
-				node.Tracer,
-				colMetrics,
-				push,
-				node.Logger,
+			builderOpts := []builder.Opt{
 				builder.WithMaxCollectionSize(maxCollectionSize),
 				builder.WithMaxCollectionByteSize(maxCollectionByteSize),
 				builder.WithMaxCollectionTotalGas(maxCollectionTotalGas),
@@ -462,6 +476,17 @@ func main() {
 				builder.WithRateLimitDryRun(builderPayerRateLimitDryRun),
 				builder.WithMaxPayerTransactionRate(builderPayerRateLimit),
 				builder.WithUnlimitedPayers(unlimitedPayers...),
+			}
+			builderOpts = append(builderOpts, builderPriorityOpts(builderPriorityOrdering, builderPriorityPayers, builderGasLimitPriority)...)
+
+			builderFactory, err := factories.NewBuilderFactory(
+				node.DB,
+				node.Storage.Headers,
+				node.Tracer,
+				colMetrics,
+				push,
+				node.Logger,
+				builderOpts...,
 			)
 			if err != nil {
 				return nil, err
@@ -638,3 +663,22 @@ func createQCContractClients(node *cmd.NodeConfig, machineAccountInfo *bootstrap
 	}
 	return qcClients, nil
 }
+
+// builderPriorityOpts returns the collection builder options for transaction priority ordering.
+func builderPriorityOpts(enabled bool, priorityPayers []string, gasLimitPriority bool) []builder.Opt {
+	if !enabled {
+		return nil
+	}
+
+	// convert hex string flag values to addresses
+	payers := make([]flow.Address, 0, len(priorityPayers))
+	for _, payerStr := range priorityPayers {
+		payers = append(payers, flow.HexToAddress(payerStr))
+	}
+
+	return []builder.Opt{
+		builder.WithPriorityPayers(payers...),
+		builder.WithGasLimitPriority(gasLimitPriority),
+		builder.WithSequenceNumberOrdering(true),
+	}
+}
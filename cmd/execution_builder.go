@@ -28,6 +28,7 @@ import (
 	stateSyncCommands "github.com/onflow/flow-go/admin/commands/state_synchronization"
 	storageCommands "github.com/onflow/flow-go/admin/commands/storage"
 	uploaderCommands "github.com/onflow/flow-go/admin/commands/uploader"
+	"github.com/onflow/flow-go/cmd/build"
 	"github.com/onflow/flow-go/consensus"
 	"github.com/onflow/flow-go/consensus/hotstuff"
 	"github.com/onflow/flow-go/consensus/hotstuff/committees"
@@ -53,9 +54,11 @@ import (
 	"github.com/onflow/flow-go/fvm"
 	fvmState "github.com/onflow/flow-go/fvm/state"
 	"github.com/onflow/flow-go/fvm/systemcontracts"
+	led "github.com/onflow/flow-go/ledger"
 	"github.com/onflow/flow-go/ledger/common/pathfinder"
 	ledger "github.com/onflow/flow-go/ledger/complete"
 	"github.com/onflow/flow-go/ledger/complete/wal"
+	"github.com/onflow/flow-go/ledger/disk"
 	bootstrapFilenames "github.com/onflow/flow-go/model/bootstrap"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/flow/filter"
@@ -104,6 +107,13 @@ func (builder *ExecutionNodeBuilder) LoadFlags() {
 		ValidateFlags(builder.exeConf.ValidateFlags)
 }
 
+// executionLedger is the ledger holding the execution state, either the in-memory complete
+// ledger, or the disk ledger.
+type executionLedger interface {
+	led.Ledger
+	Iterate(query *led.IterateQuery) (*led.IterateResult, error)
+}
+
 // ExecutionNode contains the running modules and their loading code.
 type ExecutionNode struct {
 	builder *FlowNodeBuilder // This is needed for accessing the ShutdownFunc
@@ -113,7 +123,8 @@ type ExecutionNode struct {
 	executionState          state.ExecutionState
 	followerState           protocol.FollowerState
 	committee               hotstuff.DynamicCommittee
-	ledgerStorage           *ledger.Ledger
+	ledgerStorage           executionLedger
+	completeLedger          *ledger.Ledger // nil if the disk ledger is used
 	events                  *storage.Events
 	serviceEvents           *storage.ServiceEvents
 	txResults               *storage.TransactionResults
@@ -156,12 +167,30 @@ func (builder *ExecutionNodeBuilder) LoadComponentsAndModules() {
 		AdminCommand("read-execution-data", func(config *NodeConfig) commands.AdminCommand {
 			return stateSyncCommands.NewReadExecutionDataCommand(exeNode.executionDataStore)
 		}).
+		AdminCommand("get-execution-data-pruner", func(config *NodeConfig) commands.AdminCommand {
+			return stateSyncCommands.NewGetExecutionDataPrunerCommand(exeNode.executionDataPruner)
+		}).
+		AdminCommand("set-execution-data-retention", func(config *NodeConfig) commands.AdminCommand {
+			return stateSyncCommands.NewSetExecutionDataRetentionCommand(exeNode.executionDataPruner)
+		}).
 		AdminCommand("trigger-checkpoint", func(config *NodeConfig) commands.AdminCommand {
 			return executionCommands.NewTriggerCheckpointCommand(exeNode.toTriggerCheckpoint)
 		}).
 		AdminCommand("stop-at-height", func(config *NodeConfig) commands.AdminCommand {
 			return executionCommands.NewStopAtHeightCommand(exeNode.stopControl)
 		}).
+		AdminCommand("set-version-boundary", func(config *NodeConfig) commands.AdminCommand {
+			return executionCommands.NewSetVersionBoundaryCommand(exeNode.stopControl)
+		}).
+		AdminCommand("get-stop-control", func(config *NodeConfig) commands.AdminCommand {
+			return executionCommands.NewGetStopControlCommand(exeNode.stopControl)
+		}).
+		AdminCommand("iterate-registers", func(config *NodeConfig) commands.AdminCommand {
+			return executionCommands.NewIterateRegistersCommand(exeNode.ledgerStorage, config.Storage.Commits)
+		}).
+		AdminCommand("get-register-proofs", func(config *NodeConfig) commands.AdminCommand {
+			return executionCommands.NewGetRegisterProofsCommand(exeNode.ledgerStorage, config.Storage.Commits)
+		}).
 		AdminCommand("set-uploader-enabled", func(config *NodeConfig) commands.AdminCommand {
 			return uploaderCommands.NewToggleUploaderCommand(exeNode.blockDataUploader)
 		}).
@@ -658,10 +687,16 @@ func (exeNode *ExecutionNode) LoadStopControl(
 		return nil, fmt.Errorf("cannot get the latest executed block height for stop control: %w", err)
 	}
 
-	exeNode.stopControl = ingestion.NewStopControl(
+	exeNode.stopControl, err = ingestion.NewStopControl(
 		exeNode.builder.Logger.With().Str("compontent", "stop_control").Logger(),
 		exeNode.exeConf.pauseExecution,
-		lastExecutedHeight)
+		lastExecutedHeight,
+		build.Semver(),
+		storage.NewStopBoundary(node.DB),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create stop control: %w", err)
+	}
 
 	return &module.NoopReadyDoneAware{}, nil
 }
@@ -672,6 +707,10 @@ func (exeNode *ExecutionNode) LoadExecutionStateLedger(
 	module.ReadyDoneAware,
 	error,
 ) {
+	if exeNode.exeConf.diskLedger {
+		return exeNode.loadDiskLedger(node)
+	}
+
 	// DiskWal is a dependent component because we need to ensure
 	// that all WAL updates are completed before closing opened WAL segment.
 	var err error
@@ -681,9 +720,56 @@ func (exeNode *ExecutionNode) LoadExecutionStateLedger(
 		return nil, fmt.Errorf("failed to initialize wal: %w", err)
 	}
 
-	exeNode.ledgerStorage, err = ledger.NewLedger(exeNode.diskWAL, int(exeNode.exeConf.mTrieCacheSize), exeNode.collector, node.Logger.With().Str("subcomponent",
+	exeNode.completeLedger, err = ledger.NewLedger(exeNode.diskWAL, int(exeNode.exeConf.mTrieCacheSize), exeNode.collector, node.Logger.With().Str("subcomponent",
 		"ledger").Logger(), ledger.DefaultPathFinderVersion)
-	return exeNode.ledgerStorage, err
+	if err != nil {
+		return nil, err
+	}
+	exeNode.ledgerStorage = exeNode.completeLedger
+	return exeNode.completeLedger, nil
+}
+
+// loadDiskLedger opens the disk ledger, and imports the root checkpoint into it on the first start.
+func (exeNode *ExecutionNode) loadDiskLedger(
+	node *NodeConfig,
+) (
+	module.ReadyDoneAware,
+	error,
+) {
+	logger := node.Logger.With().Str("subcomponent", "ledger").Logger()
+
+	// like the forest of the complete ledger, keep the states of the most recently executed blocks
+	diskLedger, err := disk.NewLedger(
+		exeNode.exeConf.diskLedgerDir,
+		int(exeNode.exeConf.diskLedgerCacheSize),
+		int(exeNode.exeConf.mTrieCacheSize),
+		exeNode.collector,
+		logger,
+		ledger.DefaultPathFinderVersion,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not open disk ledger: %w", err)
+	}
+
+	rootState := led.State(node.RootSeal.FinalState)
+	if !diskLedger.HasState(rootState) {
+		logger.Info().
+			Str("checkpoint", path.Join(exeNode.exeConf.triedir, bootstrapFilenames.FilenameWALRootCheckpoint)).
+			Msg("importing root checkpoint into disk ledger")
+
+		// the checkpoint is streamed into the ledger, so its tries are never held in memory
+		err = diskLedger.ImportCheckpoint(exeNode.exeConf.triedir, bootstrapFilenames.FilenameWALRootCheckpoint)
+		if err != nil {
+			return nil, fmt.Errorf("could not import root checkpoint: %w", err)
+		}
+
+		if !diskLedger.HasState(rootState) {
+			return nil, fmt.Errorf("root checkpoint does not contain the root state %v", rootState)
+		}
+	}
+
+	exeNode.ledgerStorage = diskLedger
+	return diskLedger, nil
 }
 
 func (exeNode *ExecutionNode) LoadExecutionStateLedgerWALCompactor(
@@ -692,14 +778,20 @@ func (exeNode *ExecutionNode) LoadExecutionStateLedgerWALCompactor(
 	module.ReadyDoneAware,
 	error,
 ) {
+	if exeNode.exeConf.diskLedger {
+		// the disk ledger stores every update directly, so it has no WAL to compact
+		return &module.NoopReadyDoneAware{}, nil
+	}
+
 	return ledger.NewCompactor(
-		exeNode.ledgerStorage,
+		exeNode.completeLedger,
 		exeNode.diskWAL,
 		node.Logger.With().Str("subcomponent", "checkpointer").Logger(),
 		uint(exeNode.exeConf.mTrieCacheSize),
 		exeNode.exeConf.checkpointDistance,
 		exeNode.exeConf.checkpointsToKeep,
 		exeNode.toTriggerCheckpoint, // compactor will listen to the signal from admin tool for force triggering checkpointing
+		ledger.WithDeltaCheckpoints(exeNode.exeConf.fullCheckpointInterval),
 	)
 }
 
@@ -728,11 +820,8 @@ func (exeNode *ExecutionNode) LoadExecutionDataPruner(
 		return nil, err
 	}
 
-	// by default, pruning is disabled
-	if exeNode.exeConf.executionDataPrunerHeightRangeTarget == 0 {
-		return &module.NoopReadyDoneAware{}, nil
-	}
-
+	// by default, pruning is disabled. the pruner is always created so that a retention policy
+	// can be enabled at runtime using the set-execution-data-retention admin command
 	var prunerMetrics module.ExecutionDataPrunerMetrics = metrics.NewNoopCollector()
 	if node.MetricsEnabled {
 		prunerMetrics = metrics.NewExecutionDataPrunerCollector()
@@ -747,6 +836,10 @@ func (exeNode *ExecutionNode) LoadExecutionDataPruner(
 		}),
 		pruner.WithHeightRangeTarget(exeNode.exeConf.executionDataPrunerHeightRangeTarget),
 		pruner.WithThreshold(exeNode.exeConf.executionDataPrunerThreshold),
+		pruner.WithMaxAge(exeNode.exeConf.executionDataPrunerMaxAge),
+		pruner.WithDiskBudget(exeNode.exeConf.executionDataPrunerDiskBudget),
+		pruner.WithHeaders(node.Storage.Headers),
+		pruner.WithDiskUsage(exeNode.executionDataDatastore.DiskUsage),
 	)
 	return exeNode.executionDataPruner, err
 }
@@ -1032,6 +1125,7 @@ func (exeNode *ExecutionNode) LoadGrpcServer(
 		exeNode.results,
 		exeNode.txResults,
 		node.Storage.Commits,
+		exeNode.ledgerStorage,
 		node.RootChainID,
 		signature.NewBlockSignerDecoder(exeNode.committee),
 		exeNode.exeConf.apiRatelimits,
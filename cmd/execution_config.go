@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"time"
 
@@ -12,11 +13,13 @@ import (
 	"github.com/onflow/flow-go/engine/common/provider"
 	"github.com/onflow/flow-go/engine/execution/computation/query"
 	exeprovider "github.com/onflow/flow-go/engine/execution/provider"
+	"github.com/onflow/flow-go/ledger/disk"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module/mempool"
 	"github.com/onflow/flow-go/utils/grpcutils"
 
 	"github.com/onflow/flow-go/engine/execution/computation"
+	"github.com/onflow/flow-go/engine/execution/computation/computer"
 	"github.com/onflow/flow-go/engine/execution/rpc"
 	"github.com/onflow/flow-go/fvm/storage/derived"
 	storage "github.com/onflow/flow-go/storage/badger"
@@ -28,9 +31,13 @@ type ExecutionConfig struct {
 	triedir                              string
 	executionDataDir                     string
 	mTrieCacheSize                       uint32
+	diskLedger                           bool
+	diskLedgerDir                        string
+	diskLedgerCacheSize                  uint
 	transactionResultsCacheSize          uint
 	checkpointDistance                   uint
 	checkpointsToKeep                    uint
+	fullCheckpointInterval               uint
 	chunkDataPackCacheSize               uint
 	chunkDataPackRequestsCacheSize       uint32
 	requestInterval                      time.Duration
@@ -46,9 +53,12 @@ type ExecutionConfig struct {
 	executionDataAllowedPeers            string
 	executionDataPrunerHeightRangeTarget uint64
 	executionDataPrunerThreshold         uint64
+	executionDataPrunerMaxAge            time.Duration
+	executionDataPrunerDiskBudget        uint64
 	blobstoreRateLimit                   int
 	blobstoreBurstLimit                  int
 	chunkDataPackRequestWorkers          uint
+	transactionExecutionMode             string
 
 	computationConfig        computation.ComputationConfig
 	receiptRequestWorkers    uint   // common provider engine workers
@@ -64,9 +74,13 @@ func (exeConf *ExecutionConfig) SetupFlags(flags *pflag.FlagSet) {
 	flags.BoolVar(&exeConf.rpcConf.RpcMetricsEnabled, "rpc-metrics-enabled", false, "whether to enable the rpc metrics")
 	flags.StringVar(&exeConf.triedir, "triedir", datadir, "directory to store the execution State")
 	flags.StringVar(&exeConf.executionDataDir, "execution-data-dir", filepath.Join(homedir, ".flow", "execution_data"), "directory to use for storing Execution Data")
-	flags.Uint32Var(&exeConf.mTrieCacheSize, "mtrie-cache-size", 500, "cache size for MTrie")
+	flags.Uint32Var(&exeConf.mTrieCacheSize, "mtrie-cache-size", 500, "cache size for MTrie, which is also the number of states kept if the disk ledger is enabled")
+	flags.BoolVar(&exeConf.diskLedger, "disk-ledger", false, "whether to keep the execution state in an on-disk store instead of in memory. Checkpoints and WAL segments are not used in this mode")
+	flags.StringVar(&exeConf.diskLedgerDir, "disk-ledger-dir", filepath.Join(datadir, "disk-ledger"), "directory of the on-disk execution state store, if the disk ledger is enabled")
+	flags.UintVar(&exeConf.diskLedgerCacheSize, "disk-ledger-cache-size", disk.DefaultCacheSize, "number of trie nodes cached in memory, if the disk ledger is enabled")
 	flags.UintVar(&exeConf.checkpointDistance, "checkpoint-distance", 20, "number of WAL segments between checkpoints")
 	flags.UintVar(&exeConf.checkpointsToKeep, "checkpoints-to-keep", 5, "number of recent checkpoints to keep (0 to keep all)")
+	flags.UintVar(&exeConf.fullCheckpointInterval, "full-checkpoint-interval", 0, "number of checkpoints between full checkpoints, with delta checkpoints of the trie nodes created since the previous checkpoint in between (0 to only create full checkpoints)")
 	flags.UintVar(&exeConf.computationConfig.DerivedDataCacheSize, "cadence-execution-cache", derived.DefaultDerivedDataCacheSize,
 		"cache size for Cadence execution")
 	flags.BoolVar(&exeConf.computationConfig.ExtensiveTracing, "extensive-tracing", false, "adds high-overhead tracing to execution")
@@ -76,6 +90,10 @@ func (exeConf *ExecutionConfig) SetupFlags(flags *pflag.FlagSet) {
 	flags.DurationVar(&exeConf.requestInterval, "request-interval", 60*time.Second, "the interval between requests for the requester engine")
 	flags.Uint32Var(&exeConf.receiptRequestsCacheSize, "receipt-request-cache", provider.DefaultEntityRequestCacheSize, "queue size for entity requests at common provider engine")
 	flags.UintVar(&exeConf.receiptRequestWorkers, "receipt-request-workers", provider.DefaultRequestProviderWorkers, "number of workers for entity requests at common provider engine")
+	flags.StringVar(&exeConf.transactionExecutionMode, "transaction-execution-mode", computer.TransactionExecutionModeSequential.String(),
+		"how the transactions of a block are executed: sequential, parallel (optimistically in parallel, re-executing conflicting transactions), or compare (both, logging any differences, using the sequential results)")
+	flags.IntVar(&exeConf.computationConfig.ParallelExecutionWorkers, "parallel-execution-workers", runtime.NumCPU(),
+		"maximum number of transactions executed concurrently, if transactions are executed in parallel")
 	flags.DurationVar(&exeConf.computationConfig.QueryConfig.LogTimeThreshold, "script-log-threshold", query.DefaultLogTimeThreshold,
 		"threshold for logging script execution")
 	flags.DurationVar(&exeConf.computationConfig.QueryConfig.ExecutionTimeLimit, "script-execution-time-limit", query.DefaultExecutionTimeLimit,
@@ -93,6 +111,8 @@ func (exeConf *ExecutionConfig) SetupFlags(flags *pflag.FlagSet) {
 	flags.StringVar(&exeConf.executionDataAllowedPeers, "execution-data-allowed-requesters", "", "comma separated list of Access node IDs that are allowed to request Execution Data. an empty list allows all peers")
 	flags.Uint64Var(&exeConf.executionDataPrunerHeightRangeTarget, "execution-data-height-range-target", 0, "target height range size used to limit the amount of Execution Data kept on disk")
 	flags.Uint64Var(&exeConf.executionDataPrunerThreshold, "execution-data-height-range-threshold", 100_000, "height threshold used to trigger Execution Data pruning")
+	flags.DurationVar(&exeConf.executionDataPrunerMaxAge, "execution-data-max-age", 0, "maximum age of the blocks to keep Execution Data on disk for, based on block timestamps. 0 disables age based pruning")
+	flags.Uint64Var(&exeConf.executionDataPrunerDiskBudget, "execution-data-disk-budget", 0, "maximum number of bytes of Execution Data kept on disk. 0 disables disk budget based pruning")
 	flags.StringToIntVar(&exeConf.apiRatelimits, "api-rate-limits", map[string]int{}, "per second rate limits for GRPC API methods e.g. Ping=300,ExecuteScriptAtBlockID=500 etc. note limits apply globally to all clients.")
 	flags.StringToIntVar(&exeConf.apiBurstlimits, "api-burst-limits", map[string]int{}, "burst limits for gRPC API methods e.g. Ping=100,ExecuteScriptAtBlockID=100 etc. note limits apply globally to all clients.")
 	flags.IntVar(&exeConf.blobstoreRateLimit, "blobstore-rate-limit", 0, "per second outgoing rate limit for Execution Data blobstore")
@@ -100,6 +120,15 @@ func (exeConf *ExecutionConfig) SetupFlags(flags *pflag.FlagSet) {
 }
 
 func (exeConf *ExecutionConfig) ValidateFlags() error {
+	mode, err := computer.ParseTransactionExecutionMode(exeConf.transactionExecutionMode)
+	if err != nil {
+		return fmt.Errorf("invalid flag. transaction-execution-mode: %w", err)
+	}
+	exeConf.computationConfig.TransactionExecutionMode = mode
+	if mode != computer.TransactionExecutionModeSequential && exeConf.computationConfig.ParallelExecutionWorkers < 1 {
+		return fmt.Errorf("invalid flag. parallel-execution-workers must be at least 1")
+	}
+
 	if exeConf.enableBlockDataUpload {
 		if exeConf.gcpBucketName == "" && exeConf.s3BucketName == "" {
 			return fmt.Errorf("invalid flag. gcp-bucket-name or s3-bucket-name required when blockdata-uploader is enabled")
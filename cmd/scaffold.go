@@ -458,7 +458,12 @@ func (fnb *FlowNodeBuilder) InitFlowNetworkWithConduitFactory(node *NodeConfig,
 		mwOpts = append(mwOpts, middleware.WithPeerManagerFilters(peerManagerFilters))
 	}
 
-	slashingViolationsConsumer := slashing.NewSlashingViolationsConsumer(fnb.Logger, fnb.Metrics.Network)
+	slashingViolationsConsumer := slashing.NewEvidenceConsumer(
+		fnb.Logger,
+		slashing.NewSlashingViolationsConsumer(fnb.Logger, fnb.Metrics.Network),
+		fnb.State,
+		fnb.Storage.SlashingEvidence,
+	)
 	mw := middleware.NewMiddleware(
 		fnb.Logger,
 		fnb.LibP2PNode,
@@ -987,6 +992,7 @@ func (fnb *FlowNodeBuilder) initStorage() error {
 	epochCommits := bstorage.NewEpochCommits(fnb.Metrics.Cache, fnb.DB)
 	statuses := bstorage.NewEpochStatuses(fnb.Metrics.Cache, fnb.DB)
 	commits := bstorage.NewCommits(fnb.Metrics.Cache, fnb.DB)
+	slashingEvidence := bstorage.NewSlashingEvidence(fnb.DB)
 
 	fnb.Storage = Storage{
 		Headers:            headers,
@@ -1004,6 +1010,7 @@ func (fnb *FlowNodeBuilder) initStorage() error {
 		EpochCommits:       epochCommits,
 		Statuses:           statuses,
 		Commits:            commits,
+		SlashingEvidence:   slashingEvidence,
 	}
 
 	return nil
@@ -1745,6 +1752,8 @@ func (fnb *FlowNodeBuilder) RegisterDefaultAdminCommands() {
 		return storageCommands.NewReadSealsCommand(config.State, config.Storage.Seals, config.Storage.Index)
 	}).AdminCommand("get-latest-identity", func(config *NodeConfig) commands.AdminCommand {
 		return common.NewGetIdentityCommand(config.IdentityProvider)
+	}).AdminCommand("read-slashing-evidence", func(config *NodeConfig) commands.AdminCommand {
+		return storageCommands.NewReadSlashingEvidenceCommand(config.NodeID, config.Storage.SlashingEvidence)
 	})
 }
 
@@ -0,0 +1,194 @@
+package debug_transaction_trace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	accessproto "github.com/onflow/flow/protobuf/go/flow/access"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	"github.com/onflow/flow-go/fvm/environment"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/debug"
+)
+
+var (
+	flagChain            string
+	flagExecutionAddress string
+	flagAccessAddress    string
+	flagTransactionID    string
+	flagTransaction      string
+	flagBlockID          string
+	flagRegisterCache    string
+	flagOutput           string
+)
+
+var Cmd = &cobra.Command{
+	Use:   "debug-transaction-trace",
+	Short: "Re-runs a transaction against the registers of a live execution node and dumps the trace of its execution as JSON",
+	Run:   run,
+}
+
+func init() {
+
+	Cmd.Flags().StringVar(&flagChain, "chain", "", "Chain name")
+	_ = Cmd.MarkFlagRequired("chain")
+
+	Cmd.Flags().StringVar(&flagExecutionAddress, "execution-address", "",
+		"address of the gRPC API of the execution node the registers are read from")
+	_ = Cmd.MarkFlagRequired("execution-address")
+
+	Cmd.Flags().StringVar(&flagAccessAddress, "access-address", "",
+		"address of the gRPC API of the access node the transaction is fetched from, used with --transaction-id")
+
+	Cmd.Flags().StringVar(&flagTransactionID, "transaction-id", "",
+		"ID of the transaction to trace, fetched from the access node")
+
+	Cmd.Flags().StringVar(&flagTransaction, "transaction", "",
+		"JSON file with the body of the transaction to trace, instead of fetching it from an access node")
+
+	Cmd.Flags().StringVar(&flagBlockID, "block-id", "",
+		"ID of the block whose execution state the transaction is run against (default: latest sealed block)")
+
+	Cmd.Flags().StringVar(&flagRegisterCache, "register-cache", "",
+		"file to cache the registers read from the execution node in, so the transaction can be re-run offline")
+
+	Cmd.Flags().StringVar(&flagOutput, "output", "",
+		"file to write the trace to (default: stdout)")
+}
+
+// transactionTrace is the output of the command
+type transactionTrace struct {
+	TransactionID string                           `json:"transaction_id"`
+	BlockID       string                           `json:"block_id,omitempty"`
+	Error         string                           `json:"error,omitempty"`
+	Steps         []environment.ExecutionTraceStep `json:"steps"`
+}
+
+func run(*cobra.Command, []string) {
+
+	chain, err := getChain(flagChain)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid chain")
+	}
+
+	var blockID flow.Identifier
+	if flagBlockID != "" {
+		blockID, err = flow.HexStringToIdentifier(flagBlockID)
+		if err != nil {
+			log.Fatal().Err(err).Msg("invalid block ID")
+		}
+	}
+
+	txBody, err := readTransaction(chain)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not read transaction")
+	}
+
+	log.Info().Msgf("tracing transaction %v", txBody.ID())
+
+	debugger := debug.NewRemoteDebugger(flagExecutionAddress, chain, log.Logger)
+	trace, txErr, err := debugger.RunTransactionWithTrace(txBody, blockID, flagRegisterCache)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not run transaction")
+	}
+
+	output := transactionTrace{
+		TransactionID: txBody.ID().String(),
+		Steps:         trace.Steps,
+	}
+	if blockID != flow.ZeroID {
+		output.BlockID = blockID.String()
+	}
+	if txErr != nil {
+		output.Error = txErr.Error()
+	}
+
+	err = writeTrace(output)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not write trace")
+	}
+
+	log.Info().Msgf("traced %d steps of transaction %v", len(trace.Steps), txBody.ID())
+}
+
+// readTransaction reads the transaction body either from the transaction file, or from the access node.
+func readTransaction(chain flow.Chain) (*flow.TransactionBody, error) {
+	if (flagTransaction == "") == (flagTransactionID == "") {
+		return nil, fmt.Errorf("exactly one of --transaction or --transaction-id must be provided")
+	}
+
+	if flagTransaction != "" {
+		data, err := os.ReadFile(flagTransaction)
+		if err != nil {
+			return nil, err
+		}
+
+		var txBody flow.TransactionBody
+		err = json.Unmarshal(data, &txBody)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode transaction: %w", err)
+		}
+		return &txBody, nil
+	}
+
+	if flagAccessAddress == "" {
+		return nil, fmt.Errorf("--access-address must be provided with --transaction-id")
+	}
+
+	txID, err := flow.HexStringToIdentifier(flagTransactionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transaction ID: %w", err)
+	}
+
+	conn, err := grpc.Dial(flagAccessAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to access node: %w", err)
+	}
+	defer conn.Close()
+
+	resp, err := accessproto.NewAccessAPIClient(conn).GetTransaction(
+		context.Background(),
+		&accessproto.GetTransactionRequest{Id: txID[:]})
+	if err != nil {
+		return nil, fmt.Errorf("could not get transaction: %w", err)
+	}
+
+	txBody, err := convert.MessageToTransaction(resp.GetTransaction(), chain)
+	if err != nil {
+		return nil, fmt.Errorf("could not convert transaction: %w", err)
+	}
+	return &txBody, nil
+}
+
+func writeTrace(trace transactionTrace) error {
+	out := os.Stdout
+	if flagOutput != "" {
+		file, err := os.Create(flagOutput)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(trace)
+}
+
+func getChain(chainName string) (chain flow.Chain, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid chain: %s", r)
+		}
+	}()
+	chain = flow.ChainID(chainName).Chain()
+	return
+}
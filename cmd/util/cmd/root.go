@@ -11,6 +11,7 @@ import (
 
 	checkpoint_collect_stats "github.com/onflow/flow-go/cmd/util/cmd/checkpoint-collect-stats"
 	checkpoint_list_tries "github.com/onflow/flow-go/cmd/util/cmd/checkpoint-list-tries"
+	debug_transaction_trace "github.com/onflow/flow-go/cmd/util/cmd/debug-transaction-trace"
 	epochs "github.com/onflow/flow-go/cmd/util/cmd/epochs/cmd"
 	export "github.com/onflow/flow-go/cmd/util/cmd/exec-data-json-export"
 	edbs "github.com/onflow/flow-go/cmd/util/cmd/execution-data-blobstore/cmd"
@@ -23,8 +24,11 @@ import (
 	read_protocol_state "github.com/onflow/flow-go/cmd/util/cmd/read-protocol-state/cmd"
 	index_er "github.com/onflow/flow-go/cmd/util/cmd/reindex/cmd"
 	rollback_executed_height "github.com/onflow/flow-go/cmd/util/cmd/rollback-executed-height/cmd"
+	slashing_evidence "github.com/onflow/flow-go/cmd/util/cmd/slashing-evidence/cmd"
 	"github.com/onflow/flow-go/cmd/util/cmd/snapshot"
 	truncate_database "github.com/onflow/flow-go/cmd/util/cmd/truncate-database"
+	verify_chunks "github.com/onflow/flow-go/cmd/util/cmd/verify-chunks"
+	verify_register_proofs "github.com/onflow/flow-go/cmd/util/cmd/verify-register-proofs"
 )
 
 var (
@@ -76,6 +80,10 @@ func addCommands() {
 	rootCmd.AddCommand(snapshot.Cmd)
 	rootCmd.AddCommand(export_json_transactions.Cmd)
 	rootCmd.AddCommand(read_hotstuff.RootCmd)
+	rootCmd.AddCommand(verify_register_proofs.Cmd)
+	rootCmd.AddCommand(debug_transaction_trace.Cmd)
+	rootCmd.AddCommand(slashing_evidence.RootCmd)
+	rootCmd.AddCommand(verify_chunks.Cmd)
 }
 
 func initConfig() {
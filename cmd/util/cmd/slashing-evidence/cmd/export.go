@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/cmd/util/cmd/common"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/slashing"
+	"github.com/onflow/flow-go/storage/badger"
+)
+
+var (
+	flagDatadir    string
+	flagNodeID     string
+	flagEvidenceID string
+	flagOffenderID string
+	flagOutput     string
+)
+
+var ExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "export the slashing evidence recorded in a protocol database as a self-contained bundle",
+	Run:   runExport,
+}
+
+func init() {
+	rootCmd.AddCommand(ExportCmd)
+
+	ExportCmd.Flags().StringVarP(&flagDatadir, "datadir", "d", "/var/flow/data/protocol", "directory to the badger dababase")
+	_ = ExportCmd.MarkFlagRequired("datadir")
+
+	ExportCmd.Flags().StringVar(&flagNodeID, "node-id", "",
+		"ID of the node which recorded the evidence, included in the bundle")
+	ExportCmd.Flags().StringVar(&flagEvidenceID, "id", "",
+		"ID of the evidence to export (default: all evidence)")
+	ExportCmd.Flags().StringVar(&flagOffenderID, "offender", "",
+		"ID of the offender to export the evidence of (default: all evidence)")
+	ExportCmd.Flags().StringVar(&flagOutput, "output", "",
+		"file to write the bundle to (default: stdout)")
+}
+
+func runExport(*cobra.Command, []string) {
+	if flagEvidenceID != "" && flagOffenderID != "" {
+		log.Fatal().Msg("only one of --id and --offender can be provided")
+	}
+
+	var nodeID flow.Identifier
+	if flagNodeID != "" {
+		var err error
+		nodeID, err = flow.HexStringToIdentifier(flagNodeID)
+		if err != nil {
+			log.Fatal().Err(err).Msg("invalid node ID")
+		}
+	}
+
+	db := common.InitStorage(flagDatadir)
+	defer db.Close()
+
+	store := badger.NewSlashingEvidence(db)
+
+	var evidence []*flow.SlashingEvidence
+	switch {
+	case flagEvidenceID != "":
+		evidenceID, err := flow.HexStringToIdentifier(flagEvidenceID)
+		if err != nil {
+			log.Fatal().Err(err).Msg("invalid evidence ID")
+		}
+		e, err := store.ByID(evidenceID)
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not get slashing evidence")
+		}
+		evidence = []*flow.SlashingEvidence{e}
+
+	case flagOffenderID != "":
+		offenderID, err := flow.HexStringToIdentifier(flagOffenderID)
+		if err != nil {
+			log.Fatal().Err(err).Msg("invalid offender ID")
+		}
+		evidence, err = store.ByOffender(offenderID)
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not get slashing evidence")
+		}
+
+	default:
+		var err error
+		evidence, err = store.All()
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not get slashing evidence")
+		}
+	}
+
+	bundle := slashing.NewEvidenceBundle(nodeID, evidence)
+
+	out := os.Stdout
+	if flagOutput != "" {
+		file, err := os.Create(flagOutput)
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not create output file")
+		}
+		defer file.Close()
+		out = file
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(bundle)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not write bundle")
+	}
+
+	log.Info().Msgf("exported %d slashing evidence", len(evidence))
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "slashing-evidence",
+	Short: "export and verify the evidence of slashable offenses recorded by a node",
+}
+
+var RootCmd = rootCmd
+
+func Execute() {
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Println("error", err)
+		os.Exit(1)
+	}
+}
+
+func init() {
+	cobra.OnInitialize(initConfig)
+}
+
+func initConfig() {
+	viper.AutomaticEnv()
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/cmd/util/cmd/common"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/slashing"
+)
+
+var flagBundle string
+
+var VerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "verify the slashing evidence of a bundle, without access to the protocol state",
+	Run:   runVerify,
+}
+
+func init() {
+	rootCmd.AddCommand(VerifyCmd)
+
+	VerifyCmd.Flags().StringVar(&flagBundle, "bundle", "",
+		"JSON file with the evidence bundle, as exported by the export command or the read-slashing-evidence admin command")
+	_ = VerifyCmd.MarkFlagRequired("bundle")
+}
+
+// verificationResult is the output of the command for a single evidence
+type verificationResult struct {
+	EvidenceID string                `json:"evidence_id"`
+	Offense    flow.SlashableOffense `json:"offense"`
+	OffenderID string                `json:"offender_id"`
+	Result     string                `json:"result"`
+	Error      string                `json:"error,omitempty"`
+}
+
+const (
+	resultValid        = "valid"
+	resultUnverifiable = "unverifiable"
+	resultInvalid      = "invalid"
+)
+
+func runVerify(*cobra.Command, []string) {
+	bundle, err := readBundle(flagBundle)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not read bundle")
+	}
+
+	if bundle.Version != slashing.EvidenceBundleVersion {
+		log.Fatal().Msgf("unsupported bundle version %d", bundle.Version)
+	}
+
+	results := make([]verificationResult, 0, len(bundle.Evidence))
+	invalid := 0
+	for _, evidence := range bundle.Evidence {
+		result := verificationResult{
+			EvidenceID: evidence.ID().String(),
+			Offense:    evidence.Offense,
+			OffenderID: evidence.OffenderID.String(),
+			Result:     resultValid,
+		}
+
+		err := slashing.VerifyEvidence(evidence)
+		if err != nil {
+			result.Error = err.Error()
+			if errors.Is(err, slashing.ErrUnverifiableEvidence) {
+				result.Result = resultUnverifiable
+			} else {
+				result.Result = resultInvalid
+				invalid++
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	common.PrettyPrint(results)
+
+	if invalid > 0 {
+		log.Fatal().Msgf("%d of %d evidence is invalid", invalid, len(bundle.Evidence))
+	}
+	log.Info().Msgf("verified %d evidence", len(bundle.Evidence))
+}
+
+// readBundle reads the bundle from the given file. The response of the admin command, which
+// wraps the bundle in its output, is accepted as well.
+func readBundle(path string) (*slashing.EvidenceBundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var adminResponse struct {
+		Output *slashing.EvidenceBundle `json:"output"`
+	}
+	err = json.Unmarshal(data, &adminResponse)
+	if err == nil && adminResponse.Output != nil {
+		return adminResponse.Output, nil
+	}
+
+	var bundle slashing.EvidenceBundle
+	err = json.Unmarshal(data, &bundle)
+	if err != nil {
+		return nil, err
+	}
+	return &bundle, nil
+}
@@ -0,0 +1,219 @@
+package verify_chunks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	badger "github.com/ipfs/go-ds-badger2"
+
+	"github.com/onflow/flow-go/engine/verification/fetcher"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/blobs"
+	"github.com/onflow/flow-go/module/executiondatasync/execution_data"
+	"github.com/onflow/flow-go/storage"
+)
+
+// executedChunk is a chunk of an execution result, together with the outputs of its execution
+// according to the execution node, which the outputs of re-executing the chunk are compared with.
+type executedChunk struct {
+	Index         uint64
+	ChunkDataPack *flow.ChunkDataPack
+	// Events are the events emitted by the transactions of the chunk.
+	Events flow.EventsList
+	// TrieUpdate is the update of the execution state by the chunk, taken from the execution
+	// data of the block. It is nil if the execution data is unavailable.
+	TrieUpdate *ledger.TrieUpdate
+}
+
+// chunkBundle contains everything needed to re-verify chunks of an execution result without
+// access to the database of an execution node. Execution nodes do not serve chunk data packs
+// to anyone but verification nodes, so bundles exported from the database of an execution
+// node stand in for an API serving them.
+type chunkBundle struct {
+	Header *flow.Header
+	Result *flow.ExecutionResult
+	Chunks []*executedChunk
+}
+
+// readBundleFromDatabase reads the chunks with the given indices of an execution result from
+// the database of an execution node. The result is identified either by its ID, or by the ID
+// of the executed block, in which case the result of the execution node is used.
+// If executionDataDir is not empty, the trie updates of the chunks are read from the execution
+// data of the block.
+func readBundleFromDatabase(
+	storages *storage.All,
+	executionDataDir string,
+	blockID flow.Identifier,
+	resultID flow.Identifier,
+	chunkIndices []uint64,
+) (*chunkBundle, error) {
+	var result *flow.ExecutionResult
+	var err error
+	if resultID != flow.ZeroID {
+		result, err = storages.Results.ByID(resultID)
+	} else {
+		result, err = storages.Results.ByBlockID(blockID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not get execution result: %w", err)
+	}
+
+	header, err := storages.Headers.ByBlockID(result.BlockID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get header of block %v: %w", result.BlockID, err)
+	}
+
+	indices, err := chunkIndicesOf(result, chunkIndices)
+	if err != nil {
+		return nil, err
+	}
+
+	blockEvents, err := storages.Events.ByBlockID(result.BlockID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get events of block %v: %w", result.BlockID, err)
+	}
+
+	var executionData *execution_data.BlockExecutionData
+	if executionDataDir != "" {
+		executionData, err = readExecutionData(executionDataDir, result.ExecutionDataID)
+		if err != nil {
+			return nil, fmt.Errorf("could not read execution data of block %v: %w", result.BlockID, err)
+		}
+	}
+
+	bundle := &chunkBundle{
+		Header: header,
+		Result: result,
+	}
+	for _, index := range indices {
+		chunk := result.Chunks[index]
+
+		chunkDataPack, err := storages.ChunkDataPacks.ByChunkID(chunk.ID())
+		if err != nil {
+			return nil, fmt.Errorf("could not get chunk data pack of chunk %d: %w", index, err)
+		}
+
+		offset, err := fetcher.TransactionOffsetForChunk(result.Chunks, index)
+		if err != nil {
+			return nil, fmt.Errorf("could not get transaction offset of chunk %d: %w", index, err)
+		}
+
+		executed := &executedChunk{
+			Index:         index,
+			ChunkDataPack: chunkDataPack,
+			Events:        eventsInRange(blockEvents, offset, offset+uint32(chunk.NumberOfTransactions)),
+		}
+		if executionData != nil {
+			if index >= uint64(len(executionData.ChunkExecutionDatas)) {
+				return nil, fmt.Errorf("execution data of block %v has no data for chunk %d", result.BlockID, index)
+			}
+			executed.TrieUpdate = executionData.ChunkExecutionDatas[index].TrieUpdate
+		}
+
+		bundle.Chunks = append(bundle.Chunks, executed)
+	}
+
+	return bundle, nil
+}
+
+// readBundleFile reads a bundle previously written by writeBundleFile, and returns the chunks
+// with the given indices.
+func readBundleFile(path string, chunkIndices []uint64) (*chunkBundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var bundle chunkBundle
+	err = json.Unmarshal(data, &bundle)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode chunk bundle: %w", err)
+	}
+	if bundle.Header == nil || bundle.Result == nil {
+		return nil, fmt.Errorf("chunk bundle is missing the header or the execution result")
+	}
+	if bundle.Header.ID() != bundle.Result.BlockID {
+		return nil, fmt.Errorf("header %v of chunk bundle does not match executed block %v", bundle.Header.ID(), bundle.Result.BlockID)
+	}
+
+	indices, err := chunkIndicesOf(bundle.Result, chunkIndices)
+	if err != nil {
+		return nil, err
+	}
+
+	byIndex := make(map[uint64]*executedChunk, len(bundle.Chunks))
+	for _, chunk := range bundle.Chunks {
+		byIndex[chunk.Index] = chunk
+	}
+
+	chunks := make([]*executedChunk, 0, len(indices))
+	for _, index := range indices {
+		chunk, ok := byIndex[index]
+		if !ok {
+			return nil, fmt.Errorf("chunk bundle does not contain chunk %d", index)
+		}
+		chunks = append(chunks, chunk)
+	}
+	bundle.Chunks = chunks
+
+	return &bundle, nil
+}
+
+// writeBundleFile writes the bundle as JSON to the given file.
+func writeBundleFile(path string, bundle *chunkBundle) error {
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not encode chunk bundle: %w", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// chunkIndicesOf returns the given chunk indices after checking they exist in the result,
+// or the indices of all chunks of the result if none are given.
+func chunkIndicesOf(result *flow.ExecutionResult, chunkIndices []uint64) ([]uint64, error) {
+	if len(chunkIndices) == 0 {
+		return result.Chunks.Indices(), nil
+	}
+	for _, index := range chunkIndices {
+		if _, ok := result.Chunks.ByIndex(index); !ok {
+			return nil, fmt.Errorf("execution result %v has no chunk %d (number of chunks: %d)", result.ID(), index, result.Chunks.Len())
+		}
+	}
+	return chunkIndices, nil
+}
+
+// eventsInRange returns the events of the transactions with indices in [from, to), in the
+// order they were emitted. Events are stored by transaction ID, so they need to be sorted.
+func eventsInRange(events []flow.Event, from uint32, to uint32) flow.EventsList {
+	inRange := make(flow.EventsList, 0)
+	for _, event := range events {
+		if event.TransactionIndex >= from && event.TransactionIndex < to {
+			inRange = append(inRange, event)
+		}
+	}
+	sort.Slice(inRange, func(i, j int) bool {
+		if inRange[i].TransactionIndex != inRange[j].TransactionIndex {
+			return inRange[i].TransactionIndex < inRange[j].TransactionIndex
+		}
+		return inRange[i].EventIndex < inRange[j].EventIndex
+	})
+	return inRange
+}
+
+// readExecutionData reads the execution data with the given ID from the execution data
+// blobstore in the execution data directory of an execution node.
+func readExecutionData(executionDataDir string, executionDataID flow.Identifier) (*execution_data.BlockExecutionData, error) {
+	datastore, err := badger.NewDatastore(filepath.Join(executionDataDir, "blobstore"), &badger.DefaultOptions)
+	if err != nil {
+		return nil, fmt.Errorf("could not open execution data blobstore: %w", err)
+	}
+	defer datastore.Close()
+
+	eds := execution_data.NewExecutionDataStore(blobs.NewBlobstore(datastore), execution_data.DefaultSerializer)
+	return eds.GetExecutionData(context.Background(), executionDataID)
+}
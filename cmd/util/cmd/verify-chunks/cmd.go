@@ -0,0 +1,261 @@
+package verify_chunks
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/cmd/util/cmd/common"
+	"github.com/onflow/flow-go/engine/verification/fetcher"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/environment"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/verification"
+	"github.com/onflow/flow-go/module/chunks"
+)
+
+var (
+	flagChain            string
+	flagDatadir          string
+	flagExecutionDataDir string
+	flagBundle           string
+	flagExportBundle     string
+	flagBlockID          string
+	flagResultID         string
+	flagChunks           []uint
+	flagOutput           string
+)
+
+var Cmd = &cobra.Command{
+	Use:   "verify-chunks",
+	Short: "Re-verifies chunks of a historical execution result and reports how the re-execution diverges from the execution node",
+	Run:   run,
+}
+
+func init() {
+
+	Cmd.Flags().StringVar(&flagChain, "chain", "", "Chain name")
+	_ = Cmd.MarkFlagRequired("chain")
+
+	Cmd.Flags().StringVar(&flagDatadir, "datadir", "",
+		"directory of the protocol database of the execution node to read chunk data packs from")
+
+	Cmd.Flags().StringVar(&flagExecutionDataDir, "execution-data-dir", "",
+		"execution data directory of the execution node, to compare the updated registers with (optional, used with --datadir)")
+
+	Cmd.Flags().StringVar(&flagBundle, "bundle", "",
+		"JSON file with the chunks to verify, written with --export-bundle, instead of reading them from --datadir")
+
+	Cmd.Flags().StringVar(&flagExportBundle, "export-bundle", "",
+		"file to write the chunks read from --datadir to, so they can be verified elsewhere with --bundle")
+
+	Cmd.Flags().StringVar(&flagBlockID, "block-id", "",
+		"ID of the block whose execution result by the execution node is verified, used with --datadir")
+
+	Cmd.Flags().StringVar(&flagResultID, "result-id", "",
+		"ID of the execution result to verify, used with --datadir")
+
+	Cmd.Flags().UintSliceVar(&flagChunks, "chunks", nil,
+		"comma separated indices of the chunks to verify (default: all chunks)")
+
+	Cmd.Flags().StringVar(&flagOutput, "output", "",
+		"file to write the report to (default: stdout)")
+}
+
+func run(*cobra.Command, []string) {
+
+	chain, err := getChain(flagChain)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid chain")
+	}
+
+	chunkIndices := make([]uint64, 0, len(flagChunks))
+	for _, index := range flagChunks {
+		chunkIndices = append(chunkIndices, uint64(index))
+	}
+
+	fvmOptions := []fvm.Option{
+		fvm.WithLogger(log.Logger),
+		fvm.WithChain(chain),
+		fvm.WithAccountStorageLimit(true),
+	}
+	if chain.ChainID() == flow.Testnet || chain.ChainID() == flow.Sandboxnet || chain.ChainID() == flow.Mainnet {
+		fvmOptions = append(fvmOptions, fvm.WithTransactionFeesEnabled(true))
+	}
+	if chain.ChainID() == flow.Testnet || chain.ChainID() == flow.Sandboxnet || chain.ChainID() == flow.Localnet || chain.ChainID() == flow.Benchnet {
+		fvmOptions = append(fvmOptions, fvm.WithContractDeploymentRestricted(false))
+	}
+
+	var bundle *chunkBundle
+	switch {
+	case flagBundle != "" && flagDatadir != "":
+		log.Fatal().Msg("only one of --bundle or --datadir can be provided")
+	case flagBundle != "":
+		// without a database, the headers of other blocks are unavailable to transactions
+		log.Warn().Msg("verifying chunks from a bundle, transactions looking up blocks other than the executed block will fail")
+
+		bundle, err = readBundleFile(flagBundle, chunkIndices)
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not read chunk bundle")
+		}
+	case flagDatadir != "":
+		blockID, resultID, err := parseIDs()
+		if err != nil {
+			log.Fatal().Err(err).Msg("invalid block or result ID")
+		}
+
+		db := common.InitStorage(flagDatadir)
+		defer db.Close()
+		storages := common.InitStorages(db)
+
+		bundle, err = readBundleFromDatabase(storages, flagExecutionDataDir, blockID, resultID, chunkIndices)
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not read chunks from database")
+		}
+
+		fvmOptions = append(fvmOptions, fvm.WithBlocks(environment.NewBlockFinder(storages.Headers)))
+	default:
+		log.Fatal().Msg("one of --bundle or --datadir must be provided")
+	}
+
+	if flagExportBundle != "" {
+		err = writeBundleFile(flagExportBundle, bundle)
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not write chunk bundle")
+		}
+		log.Info().Msgf("exported %d chunks to %s", len(bundle.Chunks), flagExportBundle)
+	}
+
+	verifier := chunks.NewChunkVerifier(fvm.NewVirtualMachine(), fvm.NewContext(fvmOptions...), log.Logger)
+
+	report, err := verifyBundle(verifier, bundle)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not verify chunks")
+	}
+
+	err = writeReport(report)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not write report")
+	}
+
+	invalid := 0
+	for _, chunk := range report.Chunks {
+		if !chunk.Valid {
+			invalid++
+		}
+	}
+	if invalid > 0 {
+		log.Fatal().Msgf("%d of %d chunks of result %v failed verification", invalid, len(report.Chunks), report.ResultID)
+	}
+
+	log.Info().Msgf("all %d chunks of result %v passed verification", len(report.Chunks), report.ResultID)
+}
+
+// verifyBundle re-verifies all chunks of the bundle, and reports how the re-execution diverges
+// from the execution node.
+func verifyBundle(verifier *chunks.ChunkVerifier, bundle *chunkBundle) (*verificationReport, error) {
+	result := bundle.Result
+
+	report := &verificationReport{
+		BlockID:  result.BlockID,
+		ResultID: result.ID(),
+	}
+
+	for _, executed := range bundle.Chunks {
+		chunk := result.Chunks[executed.Index]
+		systemChunk := fetcher.IsSystemChunk(chunk.Index, result)
+
+		endState, err := fetcher.EndStateCommitment(result, chunk.Index, systemChunk)
+		if err != nil {
+			return nil, fmt.Errorf("could not get end state of chunk %d: %w", chunk.Index, err)
+		}
+
+		offset, err := fetcher.TransactionOffsetForChunk(result.Chunks, chunk.Index)
+		if err != nil {
+			return nil, fmt.Errorf("could not get transaction offset of chunk %d: %w", chunk.Index, err)
+		}
+
+		vc := &verification.VerifiableChunkData{
+			IsSystemChunk:     systemChunk,
+			Chunk:             chunk,
+			Header:            bundle.Header,
+			Result:            result,
+			ChunkDataPack:     executed.ChunkDataPack,
+			EndState:          endState,
+			TransactionOffset: offset,
+		}
+
+		log.Info().Uint64("chunk_index", chunk.Index).Msg("verifying chunk")
+
+		execution, _, fault, err := verifier.VerifyWithExecution(vc)
+		if err != nil {
+			// the chunk could not be re-executed, which does not prove the result wrong
+			report.Chunks = append(report.Chunks, &chunkReport{
+				Index:            chunk.Index,
+				ChunkID:          chunk.ID(),
+				SystemChunk:      systemChunk,
+				ExpectedEndState: endState,
+				Error:            err.Error(),
+			})
+			continue
+		}
+
+		chunkReport, err := newChunkReport(chunk, systemChunk, endState, executed, execution, fault)
+		if err != nil {
+			return nil, err
+		}
+		report.Chunks = append(report.Chunks, chunkReport)
+	}
+
+	return report, nil
+}
+
+// parseIDs parses the block or result ID flags, exactly one of which must be provided.
+func parseIDs() (flow.Identifier, flow.Identifier, error) {
+	if (flagBlockID == "") == (flagResultID == "") {
+		return flow.ZeroID, flow.ZeroID, fmt.Errorf("exactly one of --block-id or --result-id must be provided")
+	}
+
+	if flagBlockID != "" {
+		blockID, err := flow.HexStringToIdentifier(flagBlockID)
+		if err != nil {
+			return flow.ZeroID, flow.ZeroID, fmt.Errorf("invalid block ID: %w", err)
+		}
+		return blockID, flow.ZeroID, nil
+	}
+
+	resultID, err := flow.HexStringToIdentifier(flagResultID)
+	if err != nil {
+		return flow.ZeroID, flow.ZeroID, fmt.Errorf("invalid result ID: %w", err)
+	}
+	return flow.ZeroID, resultID, nil
+}
+
+func writeReport(report *verificationReport) error {
+	out := os.Stdout
+	if flagOutput != "" {
+		file, err := os.Create(flagOutput)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+func getChain(chainName string) (chain flow.Chain, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid chain: %s", r)
+		}
+	}()
+	chain = flow.ChainID(chainName).Chain()
+	return
+}
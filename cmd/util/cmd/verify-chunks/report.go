@@ -0,0 +1,210 @@
+package verify_chunks
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/onflow/flow-go/cmd/util/ledger/migrations"
+	"github.com/onflow/flow-go/ledger"
+	chmodels "github.com/onflow/flow-go/model/chunks"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/chunks"
+)
+
+// verificationReport is the output of the command
+type verificationReport struct {
+	BlockID  flow.Identifier `json:"block_id"`
+	ResultID flow.Identifier `json:"result_id"`
+	Chunks   []*chunkReport  `json:"chunks"`
+}
+
+// chunkReport is the outcome of re-verifying a single chunk. Besides the verification fault,
+// it details how the re-execution diverges from the execution by the execution node.
+type chunkReport struct {
+	Index       uint64          `json:"index"`
+	ChunkID     flow.Identifier `json:"chunk_id"`
+	SystemChunk bool            `json:"system_chunk"`
+	Valid       bool            `json:"valid"`
+	Fault       string          `json:"fault,omitempty"`
+	Error       string          `json:"error,omitempty"`
+
+	// ExpectedEndState is the end state of the chunk according to the execution result.
+	ExpectedEndState flow.StateCommitment `json:"expected_end_state"`
+	// ComputedEndState is the end state computed by re-executing the chunk. It is omitted
+	// if verification failed before computing it (e.g. because of mismatching events).
+	ComputedEndState *flow.StateCommitment `json:"computed_end_state,omitempty"`
+	EndStateMismatch bool                  `json:"end_state_mismatch"`
+
+	EventDiffs []eventDiff `json:"event_diffs,omitempty"`
+
+	// RegistersCompared is false if the registers updated by the execution node are unknown,
+	// because its execution data is unavailable.
+	RegistersCompared bool           `json:"registers_compared"`
+	RegisterDiffs     []registerDiff `json:"register_diffs,omitempty"`
+}
+
+// eventDiff is a mismatching event at the given position of the events of the chunk.
+// An event is omitted if the respective events list is shorter.
+type eventDiff struct {
+	Position int           `json:"position"`
+	Executed *eventSummary `json:"executed,omitempty"`
+	Verified *eventSummary `json:"verified,omitempty"`
+}
+
+type eventSummary struct {
+	ID               flow.Identifier `json:"id"`
+	Type             flow.EventType  `json:"type"`
+	TransactionID    flow.Identifier `json:"transaction_id"`
+	TransactionIndex uint32          `json:"transaction_index"`
+	EventIndex       uint32          `json:"event_index"`
+	Payload          string          `json:"payload"`
+}
+
+// registerDiff is a register updated differently by the execution node and by re-executing
+// the chunk. A value is omitted if the register was not updated.
+type registerDiff struct {
+	Register string  `json:"register"`
+	Owner    string  `json:"owner"`
+	Key      string  `json:"key"`
+	Executed *string `json:"executed,omitempty"`
+	Verified *string `json:"verified,omitempty"`
+}
+
+// newChunkReport creates the report of re-verifying the chunk, given the outcome of the
+// verification, and the outputs of executing the chunk according to the execution node.
+func newChunkReport(
+	chunk *flow.Chunk,
+	systemChunk bool,
+	endState flow.StateCommitment,
+	executed *executedChunk,
+	execution *chunks.ChunkExecution,
+	fault chmodels.ChunkFault,
+) (*chunkReport, error) {
+	report := &chunkReport{
+		Index:            chunk.Index,
+		ChunkID:          chunk.ID(),
+		SystemChunk:      systemChunk,
+		Valid:            fault == nil,
+		ExpectedEndState: endState,
+		EventDiffs:       diffEvents(executed.Events, execution.Events),
+	}
+	if fault != nil {
+		report.Fault = fault.String()
+	}
+
+	if execution.EndState != (flow.StateCommitment{}) {
+		computed := execution.EndState
+		report.ComputedEndState = &computed
+		report.EndStateMismatch = computed != endState
+	}
+
+	if executed.TrieUpdate != nil {
+		registerDiffs, err := diffRegisters(executed.TrieUpdate, execution.UpdatedRegisters)
+		if err != nil {
+			return nil, fmt.Errorf("could not compare registers of chunk %d: %w", chunk.Index, err)
+		}
+		report.RegistersCompared = true
+		report.RegisterDiffs = registerDiffs
+	}
+
+	return report, nil
+}
+
+// diffEvents compares the events emitted according to the execution node with the events
+// emitted by re-executing the chunk, position by position.
+func diffEvents(executed flow.EventsList, verified flow.EventsList) []eventDiff {
+	var diffs []eventDiff
+	for i := 0; i < len(executed) || i < len(verified); i++ {
+		// event IDs only cover the transaction ID and event index, so the events are compared
+		// by their fingerprint
+		if i < len(executed) && i < len(verified) && bytes.Equal(executed[i].Fingerprint(), verified[i].Fingerprint()) {
+			continue
+		}
+
+		diff := eventDiff{Position: i}
+		if i < len(executed) {
+			diff.Executed = summarizeEvent(executed[i])
+		}
+		if i < len(verified) {
+			diff.Verified = summarizeEvent(verified[i])
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs
+}
+
+func summarizeEvent(event flow.Event) *eventSummary {
+	return &eventSummary{
+		ID:               event.ID(),
+		Type:             event.Type,
+		TransactionID:    event.TransactionID,
+		TransactionIndex: event.TransactionIndex,
+		EventIndex:       event.EventIndex,
+		Payload:          string(event.Payload),
+	}
+}
+
+// diffRegisters compares the registers updated according to the trie update of the execution
+// node with the registers updated by re-executing the chunk.
+// No errors are expected, unless the trie update contains malformed keys.
+func diffRegisters(executed *ledger.TrieUpdate, verified flow.RegisterEntries) ([]registerDiff, error) {
+	executedValues := make(map[flow.RegisterID]flow.RegisterValue, len(executed.Payloads))
+	for _, payload := range executed.Payloads {
+		key, err := payload.Key()
+		if err != nil {
+			return nil, fmt.Errorf("could not decode key of payload: %w", err)
+		}
+		id, err := migrations.KeyToRegisterID(key)
+		if err != nil {
+			return nil, err
+		}
+		executedValues[id] = payload.Value()
+	}
+
+	verifiedValues := make(map[flow.RegisterID]flow.RegisterValue, len(verified))
+	for _, entry := range verified {
+		verifiedValues[entry.Key] = entry.Value
+	}
+
+	var diffs []registerDiff
+	addDiff := func(id flow.RegisterID, executedValue flow.RegisterValue, executedOk bool, verifiedValue flow.RegisterValue, verifiedOk bool) {
+		if executedOk && verifiedOk && bytes.Equal(executedValue, verifiedValue) {
+			return
+		}
+		diff := registerDiff{
+			Register: id.String(),
+			Owner:    hex.EncodeToString([]byte(id.Owner)),
+			Key:      hex.EncodeToString([]byte(id.Key)),
+		}
+		if executedOk {
+			value := hex.EncodeToString(executedValue)
+			diff.Executed = &value
+		}
+		if verifiedOk {
+			value := hex.EncodeToString(verifiedValue)
+			diff.Verified = &value
+		}
+		diffs = append(diffs, diff)
+	}
+
+	for id, executedValue := range executedValues {
+		verifiedValue, ok := verifiedValues[id]
+		addDiff(id, executedValue, true, verifiedValue, ok)
+	}
+	for id, verifiedValue := range verifiedValues {
+		if _, ok := executedValues[id]; !ok {
+			addDiff(id, nil, false, verifiedValue, true)
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		if diffs[i].Owner != diffs[j].Owner {
+			return diffs[i].Owner < diffs[j].Owner
+		}
+		return diffs[i].Key < diffs[j].Key
+	})
+
+	return diffs, nil
+}
@@ -0,0 +1,107 @@
+package verify_chunks
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestDiffEvents(t *testing.T) {
+	txID := unittest.IdentifierFixture()
+	events := flow.EventsList{
+		unittest.EventFixture(flow.EventAccountCreated, 0, 0, txID, 0),
+		unittest.EventFixture(flow.EventAccountCreated, 0, 1, txID, 0),
+		unittest.EventFixture(flow.EventAccountCreated, 0, 2, txID, 0),
+	}
+
+	t.Run("matching", func(t *testing.T) {
+		assert.Empty(t, diffEvents(events, events))
+	})
+
+	t.Run("mismatching", func(t *testing.T) {
+		verified := flow.EventsList{events[0], unittest.EventFixture(flow.EventAccountUpdated, 0, 1, txID, 0)}
+
+		diffs := diffEvents(events, verified)
+		require.Len(t, diffs, 2)
+
+		// the second event differs
+		assert.Equal(t, 1, diffs[0].Position)
+		assert.Equal(t, events[1].Type, diffs[0].Executed.Type)
+		assert.Equal(t, verified[1].Type, diffs[0].Verified.Type)
+
+		// the third event is missing in the re-execution
+		assert.Equal(t, 2, diffs[1].Position)
+		assert.Equal(t, events[2].ID(), diffs[1].Executed.ID)
+		assert.Nil(t, diffs[1].Verified)
+	})
+}
+
+func TestDiffRegisters(t *testing.T) {
+	owner := unittest.RandomAddressFixture()
+	same := flow.NewRegisterID(string(owner.Bytes()), "same")
+	changed := flow.NewRegisterID(string(owner.Bytes()), "changed")
+	executedOnly := flow.NewRegisterID(string(owner.Bytes()), "executed")
+	verifiedOnly := flow.NewRegisterID(string(owner.Bytes()), "verified")
+
+	executed := flow.RegisterEntries{
+		{Key: same, Value: []byte{1}},
+		{Key: changed, Value: []byte{2}},
+		{Key: executedOnly, Value: []byte{3}},
+	}
+	trieUpdate := &ledger.TrieUpdate{}
+	for _, entry := range executed {
+		trieUpdate.Payloads = append(trieUpdate.Payloads, ledger.NewPayload(state.RegisterIDToKey(entry.Key), entry.Value))
+	}
+
+	verified := flow.RegisterEntries{
+		{Key: same, Value: []byte{1}},
+		{Key: changed, Value: []byte{4}},
+		{Key: verifiedOnly, Value: []byte{5}},
+	}
+
+	diffs, err := diffRegisters(trieUpdate, verified)
+	require.NoError(t, err)
+	require.Len(t, diffs, 3)
+
+	value := func(v byte) *string {
+		s := hex.EncodeToString([]byte{v})
+		return &s
+	}
+
+	// diffs are sorted by owner and key
+	assert.Equal(t, hex.EncodeToString([]byte("changed")), diffs[0].Key)
+	assert.Equal(t, value(2), diffs[0].Executed)
+	assert.Equal(t, value(4), diffs[0].Verified)
+
+	assert.Equal(t, hex.EncodeToString([]byte("executed")), diffs[1].Key)
+	assert.Equal(t, value(3), diffs[1].Executed)
+	assert.Nil(t, diffs[1].Verified)
+
+	assert.Equal(t, hex.EncodeToString([]byte("verified")), diffs[2].Key)
+	assert.Nil(t, diffs[2].Executed)
+	assert.Equal(t, value(5), diffs[2].Verified)
+}
+
+func TestEventsInRange(t *testing.T) {
+	txID := unittest.IdentifierFixture()
+	events := []flow.Event{
+		unittest.EventFixture(flow.EventAccountCreated, 2, 1, txID, 0),
+		unittest.EventFixture(flow.EventAccountCreated, 0, 0, txID, 0),
+		unittest.EventFixture(flow.EventAccountCreated, 1, 0, txID, 0),
+		unittest.EventFixture(flow.EventAccountCreated, 2, 0, txID, 0),
+		unittest.EventFixture(flow.EventAccountCreated, 3, 0, txID, 0),
+	}
+
+	inRange := eventsInRange(events, 1, 3)
+	require.Len(t, inRange, 3)
+	assert.Equal(t, events[2].ID(), inRange[0].ID())
+	assert.Equal(t, events[3].ID(), inRange[1].ID())
+	assert.Equal(t, events[0].ID(), inRange[2].ID())
+}
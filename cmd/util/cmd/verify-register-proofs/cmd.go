@@ -0,0 +1,168 @@
+package verify_register_proofs
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/proof"
+	"github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+var (
+	flagProofs          string
+	flagStateCommitment string
+	flagExecutionResult string
+)
+
+var Cmd = &cobra.Command{
+	Use:   "verify-register-proofs",
+	Short: "Verifies register values and proofs returned by the get-register-proofs admin command of an execution node",
+	Run:   run,
+}
+
+func init() {
+
+	Cmd.Flags().StringVar(&flagProofs, "proofs", "",
+		"JSON file with the output of the get-register-proofs admin command")
+	_ = Cmd.MarkFlagRequired("proofs")
+
+	Cmd.Flags().StringVar(&flagStateCommitment, "state-commitment", "",
+		"hex encoded state commitment to verify the proofs against")
+
+	Cmd.Flags().StringVar(&flagExecutionResult, "execution-result", "",
+		"JSON file with the sealed execution result of the block, whose final state commitment the proofs are verified against")
+}
+
+// registerProofs is the output of the get-register-proofs admin command
+type registerProofs struct {
+	BlockID   string `json:"block_id"`
+	Commit    string `json:"commit"`
+	Registers []struct {
+		Owner string `json:"owner"`
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	} `json:"registers"`
+	Proof string `json:"proof"`
+}
+
+func run(*cobra.Command, []string) {
+
+	if (flagStateCommitment == "") == (flagExecutionResult == "") {
+		log.Fatal().Msg("exactly one of --state-commitment or --execution-result must be provided")
+	}
+
+	proofs, err := readProofs(flagProofs)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not read proofs")
+	}
+
+	var commit flow.StateCommitment
+	if flagStateCommitment != "" {
+		commit, err = flow.ToStateCommitment(decodeHex(flagStateCommitment))
+		if err != nil {
+			log.Fatal().Err(err).Msg("invalid state commitment")
+		}
+	} else {
+		commit, err = readFinalStateCommitment(flagExecutionResult, proofs.BlockID)
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not read execution result")
+		}
+	}
+
+	if proofs.Commit != "" && proofs.Commit != hex.EncodeToString(commit[:]) {
+		log.Fatal().Msgf("proofs were generated for state commitment %s, but are verified against %x", proofs.Commit, commit)
+	}
+
+	keys := make([]ledger.Key, len(proofs.Registers))
+	values := make([]ledger.Value, len(proofs.Registers))
+	for i, register := range proofs.Registers {
+		owner, err := hex.DecodeString(strings.TrimPrefix(register.Owner, "0x"))
+		if err != nil {
+			log.Fatal().Err(err).Msgf("invalid owner of register %d", i)
+		}
+		value, err := hex.DecodeString(register.Value)
+		if err != nil {
+			log.Fatal().Err(err).Msgf("invalid value of register %d", i)
+		}
+
+		keys[i] = state.RegisterIDToKey(flow.RegisterID{Owner: string(owner), Key: register.Key})
+		values[i] = value
+	}
+
+	encodedProof, err := hex.DecodeString(proofs.Proof)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid proof encoding")
+	}
+
+	err = proof.VerifyEncodedBatchProof(encodedProof, keys, values, ledger.State(commit), complete.DefaultPathFinderVersion)
+	if err != nil {
+		log.Fatal().Err(err).Msg("proof verification failed")
+	}
+
+	log.Info().Msgf("verified %d register values against state commitment %x", len(keys), commit)
+}
+
+// readProofs reads the output of the get-register-proofs admin command, either as returned by the
+// admin server, or only its output field.
+func readProofs(path string) (*registerProofs, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var wrapped struct {
+		Output *registerProofs `json:"output"`
+	}
+	err = json.Unmarshal(data, &wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode proofs: %w", err)
+	}
+	if wrapped.Output != nil {
+		return wrapped.Output, nil
+	}
+
+	var proofs registerProofs
+	err = json.Unmarshal(data, &proofs)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode proofs: %w", err)
+	}
+	return &proofs, nil
+}
+
+// readFinalStateCommitment reads an execution result and returns its final state commitment,
+// after checking that the result is for the block of the proofs.
+func readFinalStateCommitment(path string, blockID string) (flow.StateCommitment, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return flow.DummyStateCommitment, err
+	}
+
+	var result flow.ExecutionResult
+	err = json.Unmarshal(data, &result)
+	if err != nil {
+		return flow.DummyStateCommitment, fmt.Errorf("could not decode execution result: %w", err)
+	}
+
+	if blockID != "" && result.BlockID.String() != blockID {
+		return flow.DummyStateCommitment, fmt.Errorf("execution result is for block %v, but proofs are for block %s", result.BlockID, blockID)
+	}
+
+	return result.FinalStateCommitment()
+}
+
+func decodeHex(s string) []byte {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		log.Fatal().Err(err).Msgf("invalid hex string %s", s)
+	}
+	return b
+}
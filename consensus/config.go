@@ -39,10 +39,10 @@ type ParticipantConfig struct {
 func DefaultParticipantConfig() ParticipantConfig {
 	defTimeout := timeout.DefaultConfig
 	cfg := ParticipantConfig{
-		TimeoutMinimum:                      time.Duration(defTimeout.MinReplicaTimeout) * time.Millisecond,
-		TimeoutMaximum:                      time.Duration(defTimeout.MaxReplicaTimeout) * time.Millisecond,
-		TimeoutAdjustmentFactor:             defTimeout.TimeoutAdjustmentFactor,
-		HappyPathMaxRoundFailures:           defTimeout.HappyPathMaxRoundFailures,
+		TimeoutMinimum:                      defTimeout.GetMinReplicaTimeout(),
+		TimeoutMaximum:                      defTimeout.GetMaxReplicaTimeout(),
+		TimeoutAdjustmentFactor:             defTimeout.GetTimeoutAdjustmentFactor(),
+		HappyPathMaxRoundFailures:           defTimeout.HappyPathMaxRoundFailures.Load(),
 		BlockRateDelay:                      defTimeout.GetBlockRateDelay(),
 		MaxTimeoutObjectRebroadcastInterval: time.Duration(defTimeout.MaxTimeoutObjectRebroadcastInterval) * time.Millisecond,
 		Registrar:                           nil,
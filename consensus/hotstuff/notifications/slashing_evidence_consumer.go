@@ -0,0 +1,211 @@
+package notifications
+
+import (
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/consensus/hotstuff"
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/logging"
+)
+
+// SlashingEvidenceConsumer is an implementation of the notifications consumer that persists the
+// evidence of slashable offenses committed in the main consensus, see storage.SlashingEvidence.
+// Together with the offending signed messages, it records the identity and keys of the offender
+// at the time of the offense, so the evidence can be verified without the protocol state.
+//
+// Recording evidence is best-effort: failures are logged, but do not interrupt consensus.
+type SlashingEvidenceConsumer struct {
+	NoopConsumer
+	log       zerolog.Logger
+	chainID   flow.ChainID
+	committee hotstuff.Replicas
+	epochs    module.EpochLookup
+	headers   storage.Headers
+	evidence  storage.SlashingEvidence
+}
+
+var _ hotstuff.Consumer = (*SlashingEvidenceConsumer)(nil)
+
+func NewSlashingEvidenceConsumer(
+	log zerolog.Logger,
+	chainID flow.ChainID,
+	committee hotstuff.Replicas,
+	epochs module.EpochLookup,
+	headers storage.Headers,
+	evidence storage.SlashingEvidence,
+) *SlashingEvidenceConsumer {
+	return &SlashingEvidenceConsumer{
+		log:       log.With().Str("module", "slashing_evidence").Logger(),
+		chainID:   chainID,
+		committee: committee,
+		epochs:    epochs,
+		headers:   headers,
+		evidence:  evidence,
+	}
+}
+
+func (c *SlashingEvidenceConsumer) OnDoubleVotingDetected(vote1 *model.Vote, vote2 *model.Vote) {
+	c.record(&flow.SlashingEvidence{
+		Offense:    flow.OffenseDoubleVote,
+		OffenderID: vote1.SignerID,
+		View:       vote1.View,
+		Messages:   []flow.SignedConsensusMessage{voteMessage(vote1), voteMessage(vote2)},
+	})
+}
+
+func (c *SlashingEvidenceConsumer) OnInvalidVoteDetected(err model.InvalidVoteError) {
+	c.record(&flow.SlashingEvidence{
+		Offense:    flow.OffenseInvalidVote,
+		OffenderID: err.Vote.SignerID,
+		View:       err.Vote.View,
+		Messages:   []flow.SignedConsensusMessage{voteMessage(err.Vote)},
+		Reason:     err.Error(),
+	})
+}
+
+func (c *SlashingEvidenceConsumer) OnVoteForInvalidBlockDetected(vote *model.Vote, proposal *model.Proposal) {
+	c.record(&flow.SlashingEvidence{
+		Offense:    flow.OffenseVoteForInvalidBlock,
+		OffenderID: vote.SignerID,
+		View:       vote.View,
+		Messages: []flow.SignedConsensusMessage{
+			voteMessage(vote),
+			c.proposalMessage(proposal.Block, proposal.SigData),
+		},
+	})
+}
+
+func (c *SlashingEvidenceConsumer) OnDoubleTimeoutDetected(timeout1 *model.TimeoutObject, timeout2 *model.TimeoutObject) {
+	c.record(&flow.SlashingEvidence{
+		Offense:    flow.OffenseDoubleTimeout,
+		OffenderID: timeout1.SignerID,
+		View:       timeout1.View,
+		Messages:   []flow.SignedConsensusMessage{timeoutMessage(timeout1), timeoutMessage(timeout2)},
+	})
+}
+
+func (c *SlashingEvidenceConsumer) OnInvalidTimeoutDetected(err model.InvalidTimeoutError) {
+	c.record(&flow.SlashingEvidence{
+		Offense:    flow.OffenseInvalidTimeout,
+		OffenderID: err.Timeout.SignerID,
+		View:       err.Timeout.View,
+		Messages:   []flow.SignedConsensusMessage{timeoutMessage(err.Timeout)},
+		Reason:     err.Error(),
+	})
+}
+
+func (c *SlashingEvidenceConsumer) OnDoubleProposeDetected(block1 *model.Block, block2 *model.Block) {
+	c.record(&flow.SlashingEvidence{
+		Offense:    flow.OffenseDoubleProposal,
+		OffenderID: block1.ProposerID,
+		View:       block1.View,
+		Messages: []flow.SignedConsensusMessage{
+			c.proposalMessage(block1, nil),
+			c.proposalMessage(block2, nil),
+		},
+	})
+}
+
+// record adds the context of the offense to the evidence, and stores it.
+func (c *SlashingEvidenceConsumer) record(evidence *flow.SlashingEvidence) {
+	log := c.log.With().
+		Str("offense", string(evidence.Offense)).
+		Hex("offender_id", evidence.OffenderID[:]).
+		Uint64("view", evidence.View).
+		Logger()
+
+	evidence.ChainID = c.chainID
+	evidence.DetectedAt = time.Now().UTC()
+
+	epochCounter, err := c.epochs.EpochForViewWithFallback(evidence.View)
+	if err != nil {
+		log.Warn().Err(err).Msg("could not determine epoch of offense")
+	} else {
+		evidence.EpochCounter = epochCounter
+	}
+
+	// the offender might not be a consensus participant, e.g. for invalid votes
+	offender, err := c.committee.IdentityByEpoch(evidence.View, evidence.OffenderID)
+	if err != nil {
+		log.Warn().Err(err).Msg("could not determine identity of offender")
+	} else {
+		evidence.Offender = offender
+	}
+
+	dkg, err := c.committee.DKG(evidence.View)
+	if err == nil {
+		// the offender might not be a random beacon participant
+		beaconKey, err := dkg.KeyShare(evidence.OffenderID)
+		if err == nil {
+			evidence.RandomBeaconPubKey = beaconKey.Encode()
+		}
+	}
+
+	stored, err := c.evidence.Store(evidence)
+	if err != nil {
+		log.Error().Err(err).Msg("could not store slashing evidence")
+		return
+	}
+	if stored {
+		log.Warn().
+			Hex("evidence_id", logging.ID(evidence.ID())).
+			Bool(logging.KeySuspicious, true).
+			Msg("recorded slashing evidence")
+	}
+}
+
+// proposalMessage returns the signed message of the proposal of the given block. The header of
+// the block is included if the block is stored. If the proposer signature is not given, it is
+// taken from the header.
+func (c *SlashingEvidenceConsumer) proposalMessage(block *model.Block, sigData []byte) flow.SignedConsensusMessage {
+	message := flow.SignedConsensusMessage{
+		Type:     flow.SignedMessageProposal,
+		SignerID: block.ProposerID,
+		View:     block.View,
+		BlockID:  block.BlockID,
+		SigData:  sigData,
+	}
+
+	header, err := c.headers.ByBlockID(block.BlockID)
+	if err != nil {
+		c.log.Warn().Err(err).
+			Hex("block_id", block.BlockID[:]).
+			Msg("could not retrieve header of proposed block for slashing evidence")
+		return message
+	}
+
+	message.Header = header
+	if message.SigData == nil {
+		message.SigData = header.ProposerSigData
+	}
+	return message
+}
+
+func voteMessage(vote *model.Vote) flow.SignedConsensusMessage {
+	return flow.SignedConsensusMessage{
+		Type:     flow.SignedMessageVote,
+		SignerID: vote.SignerID,
+		View:     vote.View,
+		BlockID:  vote.BlockID,
+		SigData:  vote.SigData,
+	}
+}
+
+func timeoutMessage(timeout *model.TimeoutObject) flow.SignedConsensusMessage {
+	message := flow.SignedConsensusMessage{
+		Type:     flow.SignedMessageTimeout,
+		SignerID: timeout.SignerID,
+		View:     timeout.View,
+		SigData:  timeout.SigData,
+	}
+	// invalid timeouts might not include a QC
+	if timeout.NewestQC != nil {
+		message.NewestQCView = timeout.NewestQC.View
+	}
+	return message
+}
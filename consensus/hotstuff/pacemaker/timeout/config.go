@@ -2,6 +2,7 @@ package timeout
 
 import (
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/rs/zerolog/log"
@@ -16,21 +17,28 @@ import (
 //   - On timeout: increase timeout by multiplicative factor `TimeoutAdjustmentFactor`. This
 //     results in exponentially growing timeout duration on multiple subsequent timeouts.
 //   - On progress: decrease timeout by multiplicative factor `TimeoutAdjustmentFactor.
+//
+// All parameters except MaxTimeoutObjectRebroadcastInterval can be updated while HotStuff is
+// running, through the setter methods. Copies of a Config share the updatable parameters.
 type Config struct {
 	// MinReplicaTimeout is the minimum the timeout can decrease to [MILLISECONDS]
-	MinReplicaTimeout float64
+	MinReplicaTimeout *atomic.Float64
 	// MaxReplicaTimeout is the maximum value the timeout can increase to [MILLISECONDS]
-	MaxReplicaTimeout float64
+	MaxReplicaTimeout *atomic.Float64
 	// TimeoutAdjustmentFactor: MULTIPLICATIVE factor for increasing timeout when view
 	// change was triggered by a TC (unhappy path) or decreasing the timeout on progress
-	TimeoutAdjustmentFactor float64
+	TimeoutAdjustmentFactor *atomic.Float64
 	// HappyPathMaxRoundFailures is the number of rounds without progress where we still consider being
 	// on hot path of execution. After exceeding this value we will start increasing timeout values.
-	HappyPathMaxRoundFailures uint64
+	HappyPathMaxRoundFailures *atomic.Uint64
 	// BlockRateDelayMS is a delay to broadcast the proposal in order to control block production rate [MILLISECONDS]
 	BlockRateDelayMS *atomic.Float64
 	// MaxTimeoutObjectRebroadcastInterval is the maximum value for timeout object rebroadcast interval [MILLISECONDS]
 	MaxTimeoutObjectRebroadcastInterval float64
+
+	// updateLock serializes updates, so that consistency requirements involving multiple
+	// parameters (minReplicaTimeout <= maxReplicaTimeout) are validated against current values.
+	updateLock *sync.Mutex
 }
 
 var DefaultConfig = NewDefaultConfig()
@@ -87,14 +95,11 @@ func NewConfig(
 	blockRateDelay time.Duration,
 	maxRebroadcastInterval time.Duration,
 ) (Config, error) {
-	if minReplicaTimeout <= 0 {
-		return Config{}, model.NewConfigurationErrorf("minReplicaTimeout must be a positive number[milliseconds]")
-	}
-	if maxReplicaTimeout < minReplicaTimeout {
-		return Config{}, model.NewConfigurationErrorf("maxReplicaTimeout cannot be smaller than minReplicaTimeout")
+	if err := validReplicaTimeouts(minReplicaTimeout, maxReplicaTimeout); err != nil {
+		return Config{}, err
 	}
-	if timeoutAdjustmentFactor <= 1 {
-		return Config{}, model.NewConfigurationErrorf("timeoutAdjustmentFactor must be strictly bigger than 1")
+	if err := validTimeoutAdjustmentFactor(timeoutAdjustmentFactor); err != nil {
+		return Config{}, err
 	}
 	if err := validBlockRateDelay(blockRateDelay); err != nil {
 		return Config{}, err
@@ -104,16 +109,38 @@ func NewConfig(
 	}
 
 	tc := Config{
-		MinReplicaTimeout:                   float64(minReplicaTimeout.Milliseconds()),
-		MaxReplicaTimeout:                   float64(maxReplicaTimeout.Milliseconds()),
-		TimeoutAdjustmentFactor:             timeoutAdjustmentFactor,
-		HappyPathMaxRoundFailures:           happyPathMaxRoundFailures,
+		MinReplicaTimeout:                   atomic.NewFloat64(float64(minReplicaTimeout.Milliseconds())),
+		MaxReplicaTimeout:                   atomic.NewFloat64(float64(maxReplicaTimeout.Milliseconds())),
+		TimeoutAdjustmentFactor:             atomic.NewFloat64(timeoutAdjustmentFactor),
+		HappyPathMaxRoundFailures:           atomic.NewUint64(happyPathMaxRoundFailures),
 		MaxTimeoutObjectRebroadcastInterval: float64(maxRebroadcastInterval.Milliseconds()),
 		BlockRateDelayMS:                    atomic.NewFloat64(float64(blockRateDelay.Milliseconds())),
+		updateLock:                          new(sync.Mutex),
 	}
 	return tc, nil
 }
 
+// validReplicaTimeouts validates a pair of minimum and maximum replica timeouts.
+// Returns model.ConfigurationError for invalid config inputs.
+func validReplicaTimeouts(minReplicaTimeout time.Duration, maxReplicaTimeout time.Duration) error {
+	if minReplicaTimeout <= 0 {
+		return model.NewConfigurationErrorf("minReplicaTimeout must be a positive number[milliseconds]")
+	}
+	if maxReplicaTimeout < minReplicaTimeout {
+		return model.NewConfigurationErrorf("maxReplicaTimeout cannot be smaller than minReplicaTimeout")
+	}
+	return nil
+}
+
+// validTimeoutAdjustmentFactor validates a timeout adjustment factor config.
+// Returns model.ConfigurationError for invalid config inputs.
+func validTimeoutAdjustmentFactor(timeoutAdjustmentFactor float64) error {
+	if timeoutAdjustmentFactor <= 1 {
+		return model.NewConfigurationErrorf("timeoutAdjustmentFactor must be strictly bigger than 1")
+	}
+	return nil
+}
+
 // validBlockRateDelay validates a block rate delay config.
 // Returns model.ConfigurationError for invalid config inputs.
 func validBlockRateDelay(blockRateDelay time.Duration) error {
@@ -134,6 +161,9 @@ func (c *Config) GetBlockRateDelay() time.Duration {
 // value while HotStuff is running.
 // Returns updatable_configs.ValidationError if the new value is invalid.
 func (c *Config) SetBlockRateDelay(delay time.Duration) error {
+	c.updateLock.Lock()
+	defer c.updateLock.Unlock()
+
 	if err := validBlockRateDelay(delay); err != nil {
 		if model.IsConfigurationError(err) {
 			return updatable_configs.NewValidationErrorf("invalid block rate delay: %w", err)
@@ -143,9 +173,97 @@ func (c *Config) SetBlockRateDelay(delay time.Duration) error {
 	// sanity check: log a warning if we set block rate delay above min timeout
 	// it is valid to want to do this, to significantly slow the block rate, but
 	// only in edge cases
-	if c.MinReplicaTimeout < float64(delay.Milliseconds()) {
-		log.Warn().Msgf("CAUTION: setting block rate delay to %s, above min timeout %dms - this will degrade performance!", delay.String(), int64(c.MinReplicaTimeout))
+	if c.MinReplicaTimeout.Load() < float64(delay.Milliseconds()) {
+		log.Warn().Msgf("CAUTION: setting block rate delay to %s, above min timeout %dms - this will degrade performance!", delay.String(), int64(c.MinReplicaTimeout.Load()))
 	}
 	c.BlockRateDelayMS.Store(float64(delay.Milliseconds()))
 	return nil
 }
+
+// GetMinReplicaTimeout returns the minimum replica timeout as a Duration. This is used by
+// the dynamic config manager.
+func (c *Config) GetMinReplicaTimeout() time.Duration {
+	return time.Millisecond * time.Duration(c.MinReplicaTimeout.Load())
+}
+
+// SetMinReplicaTimeout sets the minimum replica timeout. It is used to modify this config
+// value while HotStuff is running.
+// Returns updatable_configs.ValidationError if the new value is invalid, in particular if it
+// is larger than the current maximum replica timeout.
+func (c *Config) SetMinReplicaTimeout(timeout time.Duration) error {
+	c.updateLock.Lock()
+	defer c.updateLock.Unlock()
+
+	if err := validReplicaTimeouts(timeout, c.GetMaxReplicaTimeout()); err != nil {
+		if model.IsConfigurationError(err) {
+			return updatable_configs.NewValidationErrorf("invalid min replica timeout: %w", err)
+		}
+		return fmt.Errorf("unexpected error validating min replica timeout: %w", err)
+	}
+	c.MinReplicaTimeout.Store(float64(timeout.Milliseconds()))
+	return nil
+}
+
+// GetMaxReplicaTimeout returns the maximum replica timeout as a Duration. This is used by
+// the dynamic config manager.
+func (c *Config) GetMaxReplicaTimeout() time.Duration {
+	return time.Millisecond * time.Duration(c.MaxReplicaTimeout.Load())
+}
+
+// SetMaxReplicaTimeout sets the maximum replica timeout. It is used to modify this config
+// value while HotStuff is running.
+// Returns updatable_configs.ValidationError if the new value is invalid, in particular if it
+// is smaller than the current minimum replica timeout.
+func (c *Config) SetMaxReplicaTimeout(timeout time.Duration) error {
+	c.updateLock.Lock()
+	defer c.updateLock.Unlock()
+
+	if err := validReplicaTimeouts(c.GetMinReplicaTimeout(), timeout); err != nil {
+		if model.IsConfigurationError(err) {
+			return updatable_configs.NewValidationErrorf("invalid max replica timeout: %w", err)
+		}
+		return fmt.Errorf("unexpected error validating max replica timeout: %w", err)
+	}
+	c.MaxReplicaTimeout.Store(float64(timeout.Milliseconds()))
+	return nil
+}
+
+// GetTimeoutAdjustmentFactor returns the timeout adjustment factor. This is used by
+// the dynamic config manager.
+func (c *Config) GetTimeoutAdjustmentFactor() float64 {
+	return c.TimeoutAdjustmentFactor.Load()
+}
+
+// SetTimeoutAdjustmentFactor sets the timeout adjustment factor. It is used to modify this
+// config value while HotStuff is running.
+// Returns updatable_configs.ValidationError if the new value is invalid.
+func (c *Config) SetTimeoutAdjustmentFactor(factor float64) error {
+	c.updateLock.Lock()
+	defer c.updateLock.Unlock()
+
+	if err := validTimeoutAdjustmentFactor(factor); err != nil {
+		if model.IsConfigurationError(err) {
+			return updatable_configs.NewValidationErrorf("invalid timeout adjustment factor: %w", err)
+		}
+		return fmt.Errorf("unexpected error validating timeout adjustment factor: %w", err)
+	}
+	c.TimeoutAdjustmentFactor.Store(factor)
+	return nil
+}
+
+// GetHappyPathMaxRoundFailures returns the number of failed rounds after which timeouts are
+// increased. This is used by the dynamic config manager.
+func (c *Config) GetHappyPathMaxRoundFailures() uint {
+	return uint(c.HappyPathMaxRoundFailures.Load())
+}
+
+// SetHappyPathMaxRoundFailures sets the number of failed rounds after which timeouts are
+// increased. It is used to modify this config value while HotStuff is running. All values
+// are valid, hence no error is returned.
+func (c *Config) SetHappyPathMaxRoundFailures(failures uint) error {
+	c.updateLock.Lock()
+	defer c.updateLock.Unlock()
+
+	c.HappyPathMaxRoundFailures.Store(uint64(failures))
+	return nil
+}
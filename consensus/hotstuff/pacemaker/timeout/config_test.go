@@ -7,16 +7,17 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/module/updatable_configs"
 )
 
 // TestConstructor tests that constructor performs needed checks and returns expected values depending on different inputs.
 func TestConstructor(t *testing.T) {
 	c, err := NewConfig(1200*time.Millisecond, 2000*time.Millisecond, 1.5, 3, time.Second, 2000*time.Millisecond)
 	require.NoError(t, err)
-	require.Equal(t, float64(1200), c.MinReplicaTimeout)
-	require.Equal(t, float64(2000), c.MaxReplicaTimeout)
-	require.Equal(t, float64(1.5), c.TimeoutAdjustmentFactor)
-	require.Equal(t, uint64(3), c.HappyPathMaxRoundFailures)
+	require.Equal(t, float64(1200), c.MinReplicaTimeout.Load())
+	require.Equal(t, float64(2000), c.MaxReplicaTimeout.Load())
+	require.Equal(t, float64(1.5), c.TimeoutAdjustmentFactor.Load())
+	require.Equal(t, uint64(3), c.HappyPathMaxRoundFailures.Load())
 	require.Equal(t, float64(1000), c.BlockRateDelayMS.Load())
 	require.Equal(t, float64(2000), c.MaxTimeoutObjectRebroadcastInterval)
 
@@ -49,8 +50,54 @@ func TestConstructor(t *testing.T) {
 func TestDefaultConfig(t *testing.T) {
 	c := NewDefaultConfig()
 
-	require.Equal(t, float64(3000), c.MinReplicaTimeout)
-	require.Equal(t, 1.2, c.TimeoutAdjustmentFactor)
-	require.Equal(t, uint64(6), c.HappyPathMaxRoundFailures)
+	require.Equal(t, float64(3000), c.MinReplicaTimeout.Load())
+	require.Equal(t, 1.2, c.TimeoutAdjustmentFactor.Load())
+	require.Equal(t, uint64(6), c.HappyPathMaxRoundFailures.Load())
 	require.Equal(t, float64(0), c.BlockRateDelayMS.Load())
 }
+
+// TestUpdates tests that the updatable config values are validated, and shared between
+// copies of the config.
+func TestUpdates(t *testing.T) {
+	c, err := NewConfig(1200*time.Millisecond, 2000*time.Millisecond, 1.5, 3, time.Second, 2000*time.Millisecond)
+	require.NoError(t, err)
+	copied := c
+
+	t.Run("min replica timeout", func(t *testing.T) {
+		require.NoError(t, c.SetMinReplicaTimeout(1500*time.Millisecond))
+		require.Equal(t, 1500*time.Millisecond, copied.GetMinReplicaTimeout())
+
+		// should not allow 0 minReplicaTimeout
+		err := c.SetMinReplicaTimeout(0)
+		require.True(t, updatable_configs.IsValidationError(err))
+		// should not allow minReplicaTimeout > maxReplicaTimeout
+		err = c.SetMinReplicaTimeout(2001 * time.Millisecond)
+		require.True(t, updatable_configs.IsValidationError(err))
+		require.Equal(t, 1500*time.Millisecond, c.GetMinReplicaTimeout())
+	})
+
+	t.Run("max replica timeout", func(t *testing.T) {
+		require.NoError(t, c.SetMaxReplicaTimeout(3000*time.Millisecond))
+		require.Equal(t, 3000*time.Millisecond, copied.GetMaxReplicaTimeout())
+
+		// should not allow maxReplicaTimeout < minReplicaTimeout
+		err := c.SetMaxReplicaTimeout(1499 * time.Millisecond)
+		require.True(t, updatable_configs.IsValidationError(err))
+		require.Equal(t, 3000*time.Millisecond, c.GetMaxReplicaTimeout())
+	})
+
+	t.Run("timeout adjustment factor", func(t *testing.T) {
+		require.NoError(t, c.SetTimeoutAdjustmentFactor(2))
+		require.Equal(t, float64(2), copied.GetTimeoutAdjustmentFactor())
+
+		// should not allow timeoutAdjustmentFactor to be 1.0 or smaller
+		err := c.SetTimeoutAdjustmentFactor(1)
+		require.True(t, updatable_configs.IsValidationError(err))
+		require.Equal(t, float64(2), c.GetTimeoutAdjustmentFactor())
+	})
+
+	t.Run("happy path max round failures", func(t *testing.T) {
+		require.NoError(t, c.SetHappyPathMaxRoundFailures(10))
+		require.Equal(t, uint(10), copied.GetHappyPathMaxRoundFailures())
+	})
+}
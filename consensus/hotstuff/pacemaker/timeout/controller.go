@@ -34,8 +34,7 @@ type Controller struct {
 	cfg            Config
 	timeoutChannel chan time.Time
 	stopTicker     context.CancelFunc
-	maxExponent    float64 // max exponent for exponential function, derived from maximum round duration
-	r              uint64  // failed rounds counter, higher value results in longer round duration
+	r              uint64 // failed rounds counter, higher value results in longer round duration
 }
 
 // NewController creates a new Controller.
@@ -45,17 +44,10 @@ func NewController(timeoutConfig Config) *Controller {
 	startChannel := make(chan time.Time)
 	close(startChannel)
 
-	// we need to calculate log_b(t_max/t_min), golang doesn't support logarithm with custom base
-	// we will apply change of base logarithm transformation to get around this:
-	// log_b(x) = log_e(x) / log_e(b)
-	maxExponent := math.Log(timeoutConfig.MaxReplicaTimeout/timeoutConfig.MinReplicaTimeout) /
-		math.Log(timeoutConfig.TimeoutAdjustmentFactor)
-
 	tc := Controller{
 		cfg:            timeoutConfig,
 		timeoutChannel: startChannel,
 		stopTicker:     func() {},
-		maxExponent:    maxExponent,
 	}
 	return &tc
 }
@@ -118,22 +110,34 @@ func tickAfterTimeout(ctx context.Context, duration time.Duration, tickInterval
 	}
 }
 
+// maxExponent returns the max exponent for the exponential function, derived from the maximum
+// round duration. As the timeout config can be updated while HotStuff is running, it is
+// derived from the current config values.
+func (t *Controller) maxExponent() float64 {
+	// we need to calculate log_b(t_max/t_min), golang doesn't support logarithm with custom base
+	// we will apply change of base logarithm transformation to get around this:
+	// log_b(x) = log_e(x) / log_e(b)
+	return math.Log(t.cfg.MaxReplicaTimeout.Load()/t.cfg.MinReplicaTimeout.Load()) /
+		math.Log(t.cfg.TimeoutAdjustmentFactor.Load())
+}
+
 // replicaTimeout returns the duration of the current view in milliseconds before we time out
 func (t *Controller) replicaTimeout() float64 {
-	if t.r <= t.cfg.HappyPathMaxRoundFailures {
-		return t.cfg.MinReplicaTimeout
+	happyPathMaxRoundFailures := t.cfg.HappyPathMaxRoundFailures.Load()
+	if t.r <= happyPathMaxRoundFailures {
+		return t.cfg.MinReplicaTimeout.Load()
 	}
-	r := float64(t.r - t.cfg.HappyPathMaxRoundFailures)
-	if r >= t.maxExponent {
-		return t.cfg.MaxReplicaTimeout
+	r := float64(t.r - happyPathMaxRoundFailures)
+	if r >= t.maxExponent() {
+		return t.cfg.MaxReplicaTimeout.Load()
 	}
 	// compute timeout duration [in milliseconds]:
-	return t.cfg.MinReplicaTimeout * math.Pow(t.cfg.TimeoutAdjustmentFactor, r)
+	return t.cfg.MinReplicaTimeout.Load() * math.Pow(t.cfg.TimeoutAdjustmentFactor.Load(), r)
 }
 
 // OnTimeout indicates to the Controller that a view change was triggered by a TC (unhappy path).
 func (t *Controller) OnTimeout() {
-	if float64(t.r) >= t.maxExponent+float64(t.cfg.HappyPathMaxRoundFailures) {
+	if float64(t.r) >= t.maxExponent()+float64(t.cfg.HappyPathMaxRoundFailures.Load()) {
 		return
 	}
 	t.r++
@@ -141,6 +145,12 @@ func (t *Controller) OnTimeout() {
 
 // OnProgressBeforeTimeout indicates to the Controller that progress was made _before_ the timeout was reached
 func (t *Controller) OnProgressBeforeTimeout() {
+	// the timeout config might have been updated, such that fewer failed rounds are needed to
+	// reach the maximum timeout - we discard the surplus rounds, so timeouts decrease right away
+	maxRounds := math.Ceil(t.maxExponent() + float64(t.cfg.HappyPathMaxRoundFailures.Load()))
+	if float64(t.r) > maxRounds {
+		t.r = uint64(math.Max(maxRounds, 0))
+	}
 	if t.r > 0 {
 		t.r--
 	}
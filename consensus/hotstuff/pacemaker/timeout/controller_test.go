@@ -128,7 +128,7 @@ func Test_CombinedIncreaseDecreaseDynamics(t *testing.T) {
 	increase, decrease := true, false
 	testDynamicSequence := func(seq []bool) {
 		tc := initTimeoutController(t)
-		tc.cfg.HappyPathMaxRoundFailures = 0 // set happy path rounds to zero to simplify calculation
+		tc.cfg.HappyPathMaxRoundFailures.Store(0) // set happy path rounds to zero to simplify calculation
 		numberIncreases, numberDecreases := 0, 0
 		for _, increase := range seq {
 			if increase {
@@ -166,3 +166,32 @@ func Test_BlockRateDelay(t *testing.T) {
 	tc := NewController(c)
 	assert.Equal(t, time.Second, tc.BlockRateDelay())
 }
+
+// Test_ConfigUpdates verifies that updates of the timeout config take effect while the
+// controller is running
+func Test_ConfigUpdates(t *testing.T) {
+	tc := initTimeoutController(t)
+
+	// increasing the min timeout increases the timeout on the happy path
+	require.NoError(t, tc.cfg.SetMinReplicaTimeout(2*time.Duration(minRepTimeout)*time.Millisecond))
+	assert.Equal(t, 2*minRepTimeout, tc.replicaTimeout())
+
+	// leave the happy path, and time out until the max timeout is reached
+	for r := uint64(0); r < happyPathMaxRoundFailures+100; r++ {
+		tc.OnTimeout()
+	}
+	assert.Equal(t, maxRepTimeout, tc.replicaTimeout())
+
+	// decreasing the max timeout takes effect right away
+	require.NoError(t, tc.cfg.SetMaxReplicaTimeout(4*time.Duration(minRepTimeout)*time.Millisecond))
+	assert.Equal(t, 4*minRepTimeout, tc.replicaTimeout())
+
+	// after progress, the timeout decreases right away from the new max timeout
+	tc.OnProgressBeforeTimeout()
+	assert.Less(t, tc.replicaTimeout(), 4*minRepTimeout)
+	assert.Greater(t, tc.replicaTimeout(), 2*minRepTimeout)
+
+	// increasing the happy path rounds returns to the min timeout
+	require.NoError(t, tc.cfg.SetHappyPathMaxRoundFailures(100))
+	assert.Equal(t, 2*minRepTimeout, tc.replicaTimeout())
+}
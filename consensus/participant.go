@@ -2,6 +2,7 @@ package consensus
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/rs/zerolog"
 
@@ -20,6 +21,7 @@ import (
 	"github.com/onflow/flow-go/consensus/recovery"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/updatable_configs"
 	"github.com/onflow/flow-go/storage"
 )
 
@@ -64,6 +66,7 @@ func NewParticipant(
 	if err != nil {
 		return nil, fmt.Errorf("could not initialize timeout config: %w", err)
 	}
+	reportTimeoutConfig(metrics, timeoutConfig)
 
 	// initialize the pacemaker
 	controller := timeout.NewController(timeoutConfig)
@@ -111,15 +114,93 @@ func NewParticipant(
 
 	// register dynamically updatable configs
 	if cfg.Registrar != nil {
-		err = cfg.Registrar.RegisterDurationConfig("hotstuff-block-rate-delay", timeoutConfig.GetBlockRateDelay, timeoutConfig.SetBlockRateDelay)
+		err = registerTimeoutConfigs(log, metrics, cfg.Registrar, timeoutConfig)
 		if err != nil {
-			return nil, fmt.Errorf("failed to register block rate delay config: %w", err)
+			return nil, fmt.Errorf("failed to register timeout configs: %w", err)
 		}
 	}
 
 	return loop, nil
 }
 
+// registerTimeoutConfigs registers the parameters of the pacemaker's timeout as dynamically
+// updatable configs. Validation is enforced by the setters of timeout.Config. All updates are
+// logged and reported to the metrics, so the timeline of updates can be reconstructed.
+func registerTimeoutConfigs(log zerolog.Logger, metrics module.HotstuffMetrics, registrar updatable_configs.Registrar, timeoutConfig timeout.Config) error {
+	onUpdate := func(name string, value interface{}) {
+		log.Info().Str("config", name).Interface("value", value).Msg("updated pacemaker timeout config")
+		reportTimeoutConfig(metrics, timeoutConfig)
+	}
+	durationSetter := func(name string, set updatable_configs.SetDurationConfigFunc) updatable_configs.SetDurationConfigFunc {
+		return func(value time.Duration) error {
+			err := set(value)
+			if err != nil {
+				return err
+			}
+			onUpdate(name, value.String())
+			return nil
+		}
+	}
+
+	err := registrar.RegisterDurationConfig("hotstuff-block-rate-delay",
+		timeoutConfig.GetBlockRateDelay,
+		durationSetter("hotstuff-block-rate-delay", timeoutConfig.SetBlockRateDelay))
+	if err != nil {
+		return fmt.Errorf("failed to register block rate delay config: %w", err)
+	}
+	err = registrar.RegisterDurationConfig("hotstuff-min-timeout",
+		timeoutConfig.GetMinReplicaTimeout,
+		durationSetter("hotstuff-min-timeout", timeoutConfig.SetMinReplicaTimeout))
+	if err != nil {
+		return fmt.Errorf("failed to register min timeout config: %w", err)
+	}
+	err = registrar.RegisterDurationConfig("hotstuff-max-timeout",
+		timeoutConfig.GetMaxReplicaTimeout,
+		durationSetter("hotstuff-max-timeout", timeoutConfig.SetMaxReplicaTimeout))
+	if err != nil {
+		return fmt.Errorf("failed to register max timeout config: %w", err)
+	}
+	err = registrar.RegisterFloatConfig("hotstuff-timeout-adjustment-factor",
+		timeoutConfig.GetTimeoutAdjustmentFactor,
+		func(factor float64) error {
+			err := timeoutConfig.SetTimeoutAdjustmentFactor(factor)
+			if err != nil {
+				return err
+			}
+			onUpdate("hotstuff-timeout-adjustment-factor", factor)
+			return nil
+		})
+	if err != nil {
+		return fmt.Errorf("failed to register timeout adjustment factor config: %w", err)
+	}
+	err = registrar.RegisterUintConfig("hotstuff-happy-path-max-round-failures",
+		timeoutConfig.GetHappyPathMaxRoundFailures,
+		func(failures uint) error {
+			err := timeoutConfig.SetHappyPathMaxRoundFailures(failures)
+			if err != nil {
+				return err
+			}
+			onUpdate("hotstuff-happy-path-max-round-failures", failures)
+			return nil
+		})
+	if err != nil {
+		return fmt.Errorf("failed to register happy path max round failures config: %w", err)
+	}
+
+	return nil
+}
+
+// reportTimeoutConfig reports the current parameters of the pacemaker's timeout to the metrics.
+func reportTimeoutConfig(metrics module.HotstuffMetrics, timeoutConfig timeout.Config) {
+	metrics.SetTimeoutConfig(
+		timeoutConfig.GetMinReplicaTimeout(),
+		timeoutConfig.GetMaxReplicaTimeout(),
+		timeoutConfig.GetTimeoutAdjustmentFactor(),
+		timeoutConfig.HappyPathMaxRoundFailures.Load(),
+		timeoutConfig.GetBlockRateDelay(),
+	)
+}
+
 // NewValidator creates new instance of hotstuff validator needed for votes & proposal validation
 func NewValidator(metrics module.HotstuffMetrics, committee hotstuff.DynamicCommittee) hotstuff.Validator {
 	packer := signature.NewConsensusSigDataPacker(committee)
@@ -490,6 +490,7 @@ func (e *Engine) trackExecutionReceiptMetrics(r *flow.ExecutionReceipt) {
 	}
 
 	e.transactionMetrics.UpdateExecutionReceiptMaxHeight(b.Header.Height)
+	e.rpcEngine.NotifyExecutedHeight(r.ExecutorID, b.Header.Height)
 
 	e.trackExecutedMetricForBlock(b, now)
 }
@@ -174,3 +174,137 @@ func accountFixture(t *testing.T) *flow.Account {
 	require.NoError(t, err)
 	return account
 }
+
+func TestGetAccountBalance(t *testing.T) {
+	backend := &mock.API{}
+
+	t.Run("get by address at latest sealed block", func(t *testing.T) {
+		account := accountFixture(t)
+		var height uint64 = 100
+		block := unittest.BlockHeaderFixture(unittest.WithHeaderHeight(height))
+
+		req := getAccountSubResourceRequest(t, account.Address.String(), "balance", sealedHeightQueryParam)
+
+		backend.Mock.
+			On("GetLatestBlockHeader", mocktestify.Anything, true).
+			Return(block, flow.BlockStatusSealed, nil).
+			Once()
+		backend.Mock.
+			On("GetAccountBalanceAtBlockHeight", mocktestify.Anything, account.Address, height).
+			Return(account.Balance, nil).
+			Once()
+
+		assertOKResponse(t, req, `{"balance":"100"}`, backend)
+		mocktestify.AssertExpectationsForObjects(t, backend)
+	})
+
+	t.Run("get by address at height", func(t *testing.T) {
+		account := accountFixture(t)
+		var height uint64 = 1337
+
+		req := getAccountSubResourceRequest(t, account.Address.String(), "balance", fmt.Sprintf("%d", height))
+
+		backend.Mock.
+			On("GetAccountBalanceAtBlockHeight", mocktestify.Anything, account.Address, height).
+			Return(account.Balance, nil).
+			Once()
+
+		assertOKResponse(t, req, `{"balance":"100"}`, backend)
+		mocktestify.AssertExpectationsForObjects(t, backend)
+	})
+
+	t.Run("get invalid", func(t *testing.T) {
+		req := getAccountSubResourceRequest(t, "123", "balance", "")
+		rr, err := executeRequest(req, backend)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.JSONEq(t, `{"code":400, "message":"invalid address"}`, rr.Body.String())
+	})
+}
+
+func TestGetAccountKeys(t *testing.T) {
+	backend := &mock.API{}
+
+	t.Run("get by address at latest finalized block", func(t *testing.T) {
+		account := accountFixture(t)
+		var height uint64 = 100
+		block := unittest.BlockHeaderFixture(unittest.WithHeaderHeight(height))
+
+		req := getAccountSubResourceRequest(t, account.Address.String(), "keys", finalHeightQueryParam)
+
+		backend.Mock.
+			On("GetLatestBlockHeader", mocktestify.Anything, false).
+			Return(block, flow.BlockStatusFinalized, nil).
+			Once()
+		backend.Mock.
+			On("GetAccountKeysAtBlockHeight", mocktestify.Anything, account.Address, height).
+			Return(account.Keys, nil).
+			Once()
+
+		assertOKResponse(t, req, fmt.Sprintf("[%s]", expectedAccountKeyResponse(account)), backend)
+		mocktestify.AssertExpectationsForObjects(t, backend)
+	})
+
+	t.Run("get by address and index at height", func(t *testing.T) {
+		account := accountFixture(t)
+		var height uint64 = 1337
+
+		req := getAccountSubResourceRequest(t, account.Address.String(), "keys/0", fmt.Sprintf("%d", height))
+
+		backend.Mock.
+			On("GetAccountKeyAtBlockHeight", mocktestify.Anything, account.Address, uint32(0), height).
+			Return(&account.Keys[0], nil).
+			Once()
+
+		assertOKResponse(t, req, expectedAccountKeyResponse(account), backend)
+		mocktestify.AssertExpectationsForObjects(t, backend)
+	})
+
+	t.Run("get invalid", func(t *testing.T) {
+		address := unittest.AddressFixture().String()
+		tests := []struct {
+			req *http.Request
+			out string
+		}{
+			{getAccountSubResourceRequest(t, "123", "keys", ""), `{"code":400, "message":"invalid address"}`},
+			{getAccountSubResourceRequest(t, address, "keys", "foo"), `{"code":400, "message":"invalid height format"}`},
+			{getAccountSubResourceRequest(t, address, "keys/foo", ""), `{"code":400, "message":"invalid key index format"}`},
+		}
+
+		for i, test := range tests {
+			rr, err := executeRequest(test.req, backend)
+			assert.NoError(t, err)
+
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+			assert.JSONEq(t, test.out, rr.Body.String(), fmt.Sprintf("test #%d failed: %v", i, test))
+		}
+	})
+}
+
+func expectedAccountKeyResponse(account *flow.Account) string {
+	return fmt.Sprintf(`{
+			  "index":"0",
+			  "public_key":"%s",
+			  "signing_algorithm":"ECDSA_P256",
+			  "hashing_algorithm":"SHA3_256",
+			  "sequence_number":"0",
+			  "weight":"1000",
+			  "revoked":false
+			}`, account.Keys[0].PublicKey.String())
+}
+
+func getAccountSubResourceRequest(t *testing.T, address string, resource string, height string) *http.Request {
+	u, err := url.ParseRequestURI(fmt.Sprintf("/v1/accounts/%s/%s", address, resource))
+	require.NoError(t, err)
+
+	if height != "" {
+		q := u.Query()
+		q.Add("block_height", height)
+		u.RawQuery = q.Encode()
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	require.NoError(t, err)
+	return req
+}
@@ -0,0 +1,22 @@
+/*
+ * Access API
+ *
+ * No description provided (generated by Swagger Codegen https://github.com/swagger-api/swagger-codegen)
+ *
+ * API version: 1.0.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+package models
+
+type ScriptExecution struct {
+	// Base64 encoded result of the script.
+	Value string `json:"value"`
+	// Computation used by the script, omitted if the usage was not reported by the execution node.
+	ComputationUsed string `json:"computation_used,omitempty"`
+	// Estimate of the memory used by the script, omitted if the usage was not reported by the execution node.
+	MemoryEstimate string `json:"memory_estimate,omitempty"`
+	// Computation intensities by computation kind.
+	ComputationIntensities map[string]string `json:"computation_intensities,omitempty"`
+	// Memory intensities by memory kind.
+	MemoryIntensities map[string]string `json:"memory_intensities,omitempty"`
+}
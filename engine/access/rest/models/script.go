@@ -0,0 +1,42 @@
+package models
+
+import (
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/onflow/flow-go/engine/access/rest/util"
+	"github.com/onflow/flow-go/engine/common/rpc"
+)
+
+// Build builds the script execution from the script result and the computation and memory used
+// by the script, in the format of the gRPC response trailer.
+func (s *ScriptExecution) Build(value []byte, usage metadata.MD) {
+	s.Value = util.ToBase64(value)
+
+	if computationUsed := usage.Get(rpc.ComputationUsedTrailer); len(computationUsed) > 0 {
+		s.ComputationUsed = computationUsed[0]
+	}
+	if memoryEstimate := usage.Get(rpc.MemoryEstimateTrailer); len(memoryEstimate) > 0 {
+		s.MemoryEstimate = memoryEstimate[0]
+	}
+	s.ComputationIntensities = intensitiesByKind(usage.Get(rpc.ComputationIntensitiesTrailer))
+	s.MemoryIntensities = intensitiesByKind(usage.Get(rpc.MemoryIntensitiesTrailer))
+}
+
+// intensitiesByKind returns the intensities of the given "<kind>=<intensity>" values by kind.
+func intensitiesByKind(values []string) map[string]string {
+	if len(values) == 0 {
+		return nil
+	}
+
+	intensities := make(map[string]string, len(values))
+	for _, value := range values {
+		i := strings.LastIndexByte(value, '=')
+		if i < 0 {
+			continue
+		}
+		intensities[value[:i]] = value[i+1:]
+	}
+	return intensities
+}
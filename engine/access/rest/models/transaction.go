@@ -109,6 +109,28 @@ func (t *TransactionResult) Build(txr *access.TransactionResult, txID flow.Ident
 	t.Links = self
 }
 
+func (t *TransactionSimulation) Build(result *access.TransactionSimulationResult) {
+	var events Events
+	events.Build(result.Events)
+
+	registersRead := make([]string, len(result.RegistersRead))
+	for i, id := range result.RegistersRead {
+		registersRead[i] = id.String()
+	}
+	registersWritten := make([]string, len(result.RegistersWritten))
+	for i, id := range result.RegistersWritten {
+		registersWritten[i] = id.String()
+	}
+
+	t.BlockId = result.BlockID.String()
+	t.ErrorMessage = result.ErrorMessage
+	t.ComputationUsed = util.FromUint64(result.ComputationUsed)
+	t.MemoryEstimate = util.FromUint64(result.MemoryEstimate)
+	t.Events = events
+	t.RegistersRead = registersRead
+	t.RegistersWritten = registersWritten
+}
+
 func (t *TransactionStatus) Build(status flow.TransactionStatus) {
 	switch status {
 	case flow.TransactionStatusExpired:
@@ -0,0 +1,11 @@
+package models
+
+// WebsocketResponse is a message sent by the server over a websocket connection. It either
+// acknowledges a client action, carries a subscription payload, or reports an error.
+type WebsocketResponse struct {
+	SubscriptionId string      `json:"subscription_id,omitempty"`
+	Topic          string      `json:"topic,omitempty"`
+	Action         string      `json:"action,omitempty"`
+	Payload        interface{} `json:"payload,omitempty"`
+	Error          *ModelError `json:"error,omitempty"`
+}
@@ -3,27 +3,31 @@ package request
 import (
 	"fmt"
 	"io"
+	"strconv"
 
 	"github.com/onflow/flow-go/model/flow"
 )
 
 const blockIDQuery = "block_id"
+const includeUsageQuery = "include_usage"
 
 type GetScript struct {
-	BlockID     flow.Identifier
-	BlockHeight uint64
-	Script      Script
+	BlockID      flow.Identifier
+	BlockHeight  uint64
+	IncludeUsage bool
+	Script       Script
 }
 
 func (g *GetScript) Build(r *Request) error {
 	return g.Parse(
 		r.GetQueryParam(blockHeightQuery),
 		r.GetQueryParam(blockIDQuery),
+		r.GetQueryParam(includeUsageQuery),
 		r.Body,
 	)
 }
 
-func (g *GetScript) Parse(rawHeight string, rawID string, rawScript io.Reader) error {
+func (g *GetScript) Parse(rawHeight string, rawID string, rawIncludeUsage string, rawScript io.Reader) error {
 	var height Height
 	err := height.Parse(rawHeight)
 	if err != nil {
@@ -38,6 +42,13 @@ func (g *GetScript) Parse(rawHeight string, rawID string, rawScript io.Reader) e
 	}
 	g.BlockID = id.Flow()
 
+	if rawIncludeUsage != "" {
+		g.IncludeUsage, err = strconv.ParseBool(rawIncludeUsage)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %s", includeUsageQuery, rawIncludeUsage)
+		}
+	}
+
 	var script Script
 	err = script.Parse(rawScript)
 	if err != nil {
@@ -15,20 +15,22 @@ func TestGetScript_InvalidParse(t *testing.T) {
 
 	validScript := fmt.Sprintf(`{ "script": "%s", "arguments": [] }`, util.ToBase64([]byte(`pub fun main() {}`)))
 	tests := []struct {
-		height string
-		id     string
-		script string
-		err    string
+		height       string
+		id           string
+		includeUsage string
+		script       string
+		err          string
 	}{
-		{"", "", "", "request body must not be empty"},
-		{"1", "7bc42fe85d32ca513769a74f97f7e1a7bad6c9407f0d934c2aa645ef9cf613c7", validScript, "can not provide both block ID and block height"},
-		{"final", "7bc42fe85d32ca513769a74f97f7e1a7bad6c9407f0d934c2aa645ef9cf613c7", validScript, "can not provide both block ID and block height"},
-		{"", "2", validScript, "invalid ID format"},
-		{"1", "", `{ "foo": "zoo" }`, `request body contains unknown field "foo"`},
+		{"", "", "", "", "request body must not be empty"},
+		{"1", "7bc42fe85d32ca513769a74f97f7e1a7bad6c9407f0d934c2aa645ef9cf613c7", "", validScript, "can not provide both block ID and block height"},
+		{"final", "7bc42fe85d32ca513769a74f97f7e1a7bad6c9407f0d934c2aa645ef9cf613c7", "", validScript, "can not provide both block ID and block height"},
+		{"", "2", "", validScript, "invalid ID format"},
+		{"1", "", "", `{ "foo": "zoo" }`, `request body contains unknown field "foo"`},
+		{"1", "", "maybe", validScript, "invalid value for include_usage: maybe"},
 	}
 
 	for i, test := range tests {
-		err := getScript.Parse(test.height, test.id, strings.NewReader(test.script))
+		err := getScript.Parse(test.height, test.id, test.includeUsage, strings.NewReader(test.script))
 		assert.EqualError(t, err, test.err, fmt.Sprintf("test #%d failed", i))
 	}
 }
@@ -39,13 +41,15 @@ func TestGetScript_ValidParse(t *testing.T) {
 	source := "pub fun main() {}"
 	validScript := strings.NewReader(fmt.Sprintf(`{ "script": "%s", "arguments": [] }`, util.ToBase64([]byte(source))))
 
-	err := getScript.Parse("1", "", validScript)
+	err := getScript.Parse("1", "", "", validScript)
 	assert.NoError(t, err)
 	assert.Equal(t, getScript.BlockHeight, uint64(1))
 	assert.Equal(t, string(getScript.Script.Source), source)
+	assert.False(t, getScript.IncludeUsage)
 
 	validScript1 := strings.NewReader(fmt.Sprintf(`{ "script": "%s", "arguments": [] }`, util.ToBase64([]byte(source))))
-	err = getScript.Parse("", "", validScript1)
+	err = getScript.Parse("", "", "true", validScript1)
 	assert.NoError(t, err)
 	assert.Equal(t, getScript.BlockHeight, SealedHeight)
+	assert.True(t, getScript.IncludeUsage)
 }
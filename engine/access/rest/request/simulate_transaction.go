@@ -0,0 +1,80 @@
+package request
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+const skipSignatureVerificationQuery = "skip_signature_verification"
+
+type SimulateTransaction struct {
+	BlockID                   flow.Identifier
+	BlockHeight               uint64
+	SkipSignatureVerification bool
+	Transaction               flow.TransactionBody
+}
+
+func (s *SimulateTransaction) Build(r *Request) error {
+	return s.Parse(
+		r.GetQueryParam(blockHeightQuery),
+		r.GetQueryParam(blockIDQuery),
+		r.GetQueryParam(skipSignatureVerificationQuery),
+		r.Body,
+		r.Chain,
+	)
+}
+
+func (s *SimulateTransaction) Parse(
+	rawHeight string,
+	rawID string,
+	rawSkipSignatureVerification string,
+	rawTransaction io.Reader,
+	chain flow.Chain,
+) error {
+	var height Height
+	err := height.Parse(rawHeight)
+	if err != nil {
+		return err
+	}
+	s.BlockHeight = height.Flow()
+
+	var id ID
+	err = id.Parse(rawID)
+	if err != nil {
+		return err
+	}
+	s.BlockID = id.Flow()
+
+	if rawSkipSignatureVerification != "" {
+		s.SkipSignatureVerification, err = strconv.ParseBool(rawSkipSignatureVerification)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %s", skipSignatureVerificationQuery, rawSkipSignatureVerification)
+		}
+	}
+
+	// transactions simulated without verifying signatures need not be signed
+	var tx Transaction
+	if s.SkipSignatureVerification {
+		err = tx.ParseUnsigned(rawTransaction, chain)
+	} else {
+		err = tx.Parse(rawTransaction, chain)
+	}
+	if err != nil {
+		return err
+	}
+	s.Transaction = tx.Flow()
+
+	// default to last sealed block
+	if s.BlockHeight == EmptyHeight && s.BlockID == flow.ZeroID {
+		s.BlockHeight = SealedHeight
+	}
+
+	if s.BlockID != flow.ZeroID && s.BlockHeight != EmptyHeight {
+		return fmt.Errorf("can not provide both block ID and block height")
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package request
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+func TestSimulateTransaction_InvalidParse(t *testing.T) {
+	var simulate SimulateTransaction
+
+	unsigned := buildTransaction()
+	delete(unsigned, "envelope_signatures")
+
+	tests := []struct {
+		height string
+		id     string
+		skip   string
+		tx     map[string]interface{}
+		err    string
+	}{
+		{"1", "7bc42fe85d32ca513769a74f97f7e1a7bad6c9407f0d934c2aa645ef9cf613c7", "", buildTransaction(), "can not provide both block ID and block height"},
+		{"", "2", "", buildTransaction(), "invalid ID format"},
+		{"", "", "yes please", buildTransaction(), "invalid value for skip_signature_verification: yes please"},
+		{"", "", "", unsigned, "envelope signatures not provided"},
+		{"", "", "false", unsigned, "envelope signatures not provided"},
+	}
+
+	for i, test := range tests {
+		err := simulate.Parse(test.height, test.id, test.skip, transactionToReader(test.tx), flow.Testnet.Chain())
+		assert.EqualError(t, err, test.err, fmt.Sprintf("test #%d failed", i))
+	}
+}
+
+func TestSimulateTransaction_ValidParse(t *testing.T) {
+	var simulate SimulateTransaction
+
+	err := simulate.Parse("", "", "", transactionToReader(buildTransaction()), flow.Testnet.Chain())
+	assert.NoError(t, err)
+	assert.Equal(t, SealedHeight, simulate.BlockHeight)
+	assert.False(t, simulate.SkipSignatureVerification)
+
+	unsigned := buildTransaction()
+	delete(unsigned, "envelope_signatures")
+
+	err = simulate.Parse("5", "", "true", transactionToReader(unsigned), flow.Testnet.Chain())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(5), simulate.BlockHeight)
+	assert.True(t, simulate.SkipSignatureVerification)
+	assert.Empty(t, simulate.Transaction.EnvelopeSignatures)
+}
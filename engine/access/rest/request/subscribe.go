@@ -0,0 +1,291 @@
+package request
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/onflow/flow-go/engine/access/state_stream"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// websocket actions supported by the subscription endpoint
+const (
+	SubscribeAction   = "subscribe"
+	UnsubscribeAction = "unsubscribe"
+)
+
+// websocket block status values for block header subscriptions
+const (
+	blockStatusFinalized = "finalized"
+	blockStatusSealed    = "sealed"
+)
+
+// WebsocketMessage is a message sent by the client over a websocket connection to manage
+// its subscriptions.
+type WebsocketMessage struct {
+	Action         string
+	Topic          string
+	SubscriptionID string
+	Arguments      json.RawMessage
+}
+
+type websocketMessageBody struct {
+	Action         string          `json:"action"`
+	Topic          string          `json:"topic,omitempty"`
+	SubscriptionID string          `json:"subscription_id,omitempty"`
+	Arguments      json.RawMessage `json:"arguments,omitempty"`
+}
+
+func (w *WebsocketMessage) Parse(raw []byte) error {
+	var body websocketMessageBody
+	err := parseBody(bytes.NewReader(raw), &body)
+	if err != nil {
+		return err
+	}
+
+	switch body.Action {
+	case SubscribeAction:
+		if body.Topic == "" {
+			return fmt.Errorf("topic must be provided")
+		}
+	case UnsubscribeAction:
+		if body.SubscriptionID == "" {
+			return fmt.Errorf("subscription ID must be provided")
+		}
+	default:
+		return fmt.Errorf("invalid action: %q", body.Action)
+	}
+
+	w.Action = body.Action
+	w.Topic = body.Topic
+	w.SubscriptionID = body.SubscriptionID
+	w.Arguments = body.Arguments
+	return nil
+}
+
+// SubscribeStart contains the start point of a subscription. At most one of StartBlockID and
+// StartHeight is set, if neither is set the subscription starts at the latest sealed block.
+type SubscribeStart struct {
+	StartBlockID flow.Identifier
+	StartHeight  uint64
+}
+
+func (s *SubscribeStart) Parse(rawStartBlockID string, rawStartHeight string) error {
+	var startBlockID ID
+	err := startBlockID.Parse(rawStartBlockID)
+	if err != nil {
+		return fmt.Errorf("invalid start block ID: %w", err)
+	}
+	s.StartBlockID = startBlockID.Flow()
+
+	var height Height
+	err = height.Parse(rawStartHeight)
+	if err != nil {
+		return fmt.Errorf("invalid start height: %w", err)
+	}
+
+	switch height.Flow() {
+	case EmptyHeight:
+		s.StartHeight = 0
+	case SealedHeight, FinalHeight:
+		return fmt.Errorf("invalid start height: special height values are not supported")
+	default:
+		s.StartHeight = height.Flow()
+	}
+
+	if s.StartBlockID != flow.ZeroID && s.StartHeight > 0 {
+		return fmt.Errorf("can only provide either start block ID or start height")
+	}
+
+	return nil
+}
+
+type SubscribeEvents struct {
+	SubscribeStart
+	EventTypes []string
+	Addresses  []string
+	Contracts  []string
+	// FieldFilters are predicates on the fields of the decoded event payload, which all must match
+	// for an event selected by the other filters to be sent.
+	FieldFilters []state_stream.EventFieldFilter
+	// HeartbeatInterval is the number of blocks after which a response is sent even if no events
+	// matched the filter. 0 means that blocks without matching events are never sent.
+	HeartbeatInterval uint64
+}
+
+type subscribeEventsBody struct {
+	StartBlockID      string                 `json:"start_block_id,omitempty"`
+	StartHeight       string                 `json:"start_height,omitempty"`
+	EventTypes        []string               `json:"event_types,omitempty"`
+	Addresses         []string               `json:"addresses,omitempty"`
+	Contracts         []string               `json:"contracts,omitempty"`
+	FieldFilters      []eventFieldFilterBody `json:"field_filters,omitempty"`
+	HeartbeatInterval string                 `json:"heartbeat_interval,omitempty"`
+}
+
+type eventFieldFilterBody struct {
+	Field    string   `json:"field"`
+	Operator string   `json:"operator"`
+	Values   []string `json:"values"`
+}
+
+func (s *SubscribeEvents) Build(arguments json.RawMessage) error {
+	var body subscribeEventsBody
+	err := parseArguments(arguments, &body)
+	if err != nil {
+		return err
+	}
+
+	return s.Parse(
+		body.StartBlockID,
+		body.StartHeight,
+		body.EventTypes,
+		body.Addresses,
+		body.Contracts,
+		body.FieldFilters,
+		body.HeartbeatInterval,
+	)
+}
+
+func (s *SubscribeEvents) Parse(
+	rawStartBlockID string,
+	rawStartHeight string,
+	eventTypes []string,
+	addresses []string,
+	contracts []string,
+	fieldFilters []eventFieldFilterBody,
+	rawHeartbeatInterval string,
+) error {
+	err := s.SubscribeStart.Parse(rawStartBlockID, rawStartHeight)
+	if err != nil {
+		return err
+	}
+
+	// event types, addresses and contracts are validated when the event filter is created
+	s.EventTypes = eventTypes
+	s.Addresses = addresses
+	s.Contracts = contracts
+
+	// field filters are validated when the event filter is created as well
+	s.FieldFilters = make([]state_stream.EventFieldFilter, len(fieldFilters))
+	for i, fieldFilter := range fieldFilters {
+		s.FieldFilters[i] = state_stream.EventFieldFilter{
+			Field:    fieldFilter.Field,
+			Operator: state_stream.FieldFilterOperator(fieldFilter.Operator),
+			Values:   fieldFilter.Values,
+		}
+	}
+
+	if rawHeartbeatInterval != "" {
+		s.HeartbeatInterval, err = strconv.ParseUint(rawHeartbeatInterval, 0, 64)
+		if err != nil {
+			return fmt.Errorf("invalid heartbeat interval format")
+		}
+	}
+
+	return nil
+}
+
+type SubscribeBlockHeaders struct {
+	SubscribeStart
+	BlockStatus flow.BlockStatus
+}
+
+type subscribeBlockHeadersBody struct {
+	StartBlockID string `json:"start_block_id,omitempty"`
+	StartHeight  string `json:"start_height,omitempty"`
+	BlockStatus  string `json:"block_status,omitempty"`
+}
+
+func (s *SubscribeBlockHeaders) Build(arguments json.RawMessage) error {
+	var body subscribeBlockHeadersBody
+	err := parseArguments(arguments, &body)
+	if err != nil {
+		return err
+	}
+
+	return s.Parse(body.StartBlockID, body.StartHeight, body.BlockStatus)
+}
+
+func (s *SubscribeBlockHeaders) Parse(rawStartBlockID string, rawStartHeight string, rawBlockStatus string) error {
+	err := s.SubscribeStart.Parse(rawStartBlockID, rawStartHeight)
+	if err != nil {
+		return err
+	}
+
+	switch rawBlockStatus {
+	case "", blockStatusFinalized: // default to finalized
+		s.BlockStatus = flow.BlockStatusFinalized
+	case blockStatusSealed:
+		s.BlockStatus = flow.BlockStatusSealed
+	default:
+		return fmt.Errorf("invalid block status: must be either %q or %q", blockStatusFinalized, blockStatusSealed)
+	}
+
+	return nil
+}
+
+type SubscribeTransactionStatuses struct {
+	TransactionID flow.Identifier
+}
+
+type subscribeTransactionStatusesBody struct {
+	TransactionID string `json:"transaction_id"`
+}
+
+func (s *SubscribeTransactionStatuses) Build(arguments json.RawMessage) error {
+	var body subscribeTransactionStatusesBody
+	err := parseArguments(arguments, &body)
+	if err != nil {
+		return err
+	}
+
+	return s.Parse(body.TransactionID)
+}
+
+func (s *SubscribeTransactionStatuses) Parse(rawTransactionID string) error {
+	var id ID
+	err := id.Parse(rawTransactionID)
+	if err != nil {
+		return fmt.Errorf("invalid transaction ID: %w", err)
+	}
+
+	if id.Flow() == flow.ZeroID {
+		return fmt.Errorf("transaction ID must be provided")
+	}
+
+	s.TransactionID = id.Flow()
+	return nil
+}
+
+// SendAndSubscribeTransactionStatuses contains the transaction to send, which is provided as
+// subscription arguments in the same format as the body of a create transaction request.
+type SendAndSubscribeTransactionStatuses struct {
+	Transaction flow.TransactionBody
+}
+
+func (s *SendAndSubscribeTransactionStatuses) Build(arguments json.RawMessage, chain flow.Chain) error {
+	if len(arguments) == 0 {
+		return fmt.Errorf("transaction must be provided")
+	}
+
+	var tx Transaction
+	err := tx.Parse(bytes.NewReader(arguments), chain)
+	if err != nil {
+		return err
+	}
+
+	s.Transaction = tx.Flow()
+	return nil
+}
+
+// parseArguments decodes subscription arguments into dst. Missing arguments are treated as an
+// empty object.
+func parseArguments(arguments json.RawMessage, dst interface{}) error {
+	if len(arguments) == 0 {
+		return nil
+	}
+	return parseBody(bytes.NewReader(arguments), dst)
+}
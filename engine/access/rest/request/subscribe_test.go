@@ -0,0 +1,117 @@
+package request
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-go/engine/access/state_stream"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+func TestWebsocketMessage_InvalidParse(t *testing.T) {
+	var msg WebsocketMessage
+
+	tests := []struct {
+		raw string
+		err string
+	}{
+		{`{"action": "subscribe"}`, "topic must be provided"},
+		{`{"action": "unsubscribe"}`, "subscription ID must be provided"},
+		{`{"action": "foo"}`, `invalid action: "foo"`},
+		{`{"action": "subscribe", "topic": "events", "foo": 1}`, `request body contains unknown field "foo"`},
+	}
+
+	for i, test := range tests {
+		err := msg.Parse([]byte(test.raw))
+		assert.EqualError(t, err, test.err, fmt.Sprintf("test #%d failed", i))
+	}
+}
+
+func TestSubscribeEvents_InvalidParse(t *testing.T) {
+	var subscribeEvents SubscribeEvents
+
+	tests := []struct {
+		startBlockID string
+		startHeight  string
+		heartbeat    string
+		err          string
+	}{
+		{"foo", "", "", "invalid start block ID: invalid ID format"},
+		{"", "foo", "", "invalid start height: invalid height format"},
+		{"", "sealed", "", "invalid start height: special height values are not supported"},
+		{"7bc42fe85d32ca513769a74f97f7e1a7bad6c9407f0d934c2aa645ef9cf613c7", "10", "", "can only provide either start block ID or start height"},
+		{"", "10", "foo", "invalid heartbeat interval format"},
+	}
+
+	for i, test := range tests {
+		err := subscribeEvents.Parse(test.startBlockID, test.startHeight, nil, nil, nil, nil, test.heartbeat)
+		assert.EqualError(t, err, test.err, fmt.Sprintf("test #%d failed", i))
+	}
+}
+
+func TestSubscribeEvents_ValidParse(t *testing.T) {
+	var subscribeEvents SubscribeEvents
+
+	err := subscribeEvents.Build([]byte(`{"start_height": "10", "event_types": ["flow.AccountCreated"], "heartbeat_interval": "5"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, flow.ZeroID, subscribeEvents.StartBlockID)
+	assert.Equal(t, uint64(10), subscribeEvents.StartHeight)
+	assert.Equal(t, []string{"flow.AccountCreated"}, subscribeEvents.EventTypes)
+	assert.Equal(t, uint64(5), subscribeEvents.HeartbeatInterval)
+	assert.Empty(t, subscribeEvents.FieldFilters)
+
+	err = subscribeEvents.Build([]byte(`{"field_filters": [{"field": "to", "operator": "in", "values": ["0x01", "0x02"]}]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []state_stream.EventFieldFilter{{
+		Field:    "to",
+		Operator: state_stream.FieldFilterIn,
+		Values:   []string{"0x01", "0x02"},
+	}}, subscribeEvents.FieldFilters)
+
+	// missing arguments start from the latest sealed block
+	err = subscribeEvents.Build(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), subscribeEvents.StartHeight)
+}
+
+func TestSubscribeBlockHeaders_Parse(t *testing.T) {
+	var subscribeBlockHeaders SubscribeBlockHeaders
+
+	err := subscribeBlockHeaders.Parse("", "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, flow.BlockStatusFinalized, subscribeBlockHeaders.BlockStatus)
+
+	err = subscribeBlockHeaders.Parse("", "", "sealed")
+	assert.NoError(t, err)
+	assert.Equal(t, flow.BlockStatusSealed, subscribeBlockHeaders.BlockStatus)
+
+	err = subscribeBlockHeaders.Parse("", "", "executed")
+	assert.EqualError(t, err, `invalid block status: must be either "finalized" or "sealed"`)
+}
+
+func TestSubscribeTransactionStatuses_Parse(t *testing.T) {
+	var subscribeTransactionStatuses SubscribeTransactionStatuses
+
+	err := subscribeTransactionStatuses.Parse("")
+	assert.EqualError(t, err, "transaction ID must be provided")
+
+	err = subscribeTransactionStatuses.Parse("foo")
+	assert.EqualError(t, err, "invalid transaction ID: invalid ID format")
+
+	id := "7bc42fe85d32ca513769a74f97f7e1a7bad6c9407f0d934c2aa645ef9cf613c7"
+	err = subscribeTransactionStatuses.Parse(id)
+	assert.NoError(t, err)
+	assert.Equal(t, id, subscribeTransactionStatuses.TransactionID.String())
+}
+
+func TestSendAndSubscribeTransactionStatuses_Build(t *testing.T) {
+	var sendAndSubscribe SendAndSubscribeTransactionStatuses
+
+	err := sendAndSubscribe.Build(nil, flow.Testnet.Chain())
+	assert.EqualError(t, err, "transaction must be provided")
+
+	err = sendAndSubscribe.Build([]byte(`{"script": "foo"}`), flow.Testnet.Chain())
+	assert.EqualError(t, err, "proposal key not provided")
+}
@@ -9,10 +9,17 @@ import (
 	"github.com/onflow/flow-go/access"
 	"github.com/onflow/flow-go/engine/access/rest/middleware"
 	"github.com/onflow/flow-go/engine/access/rest/models"
+	"github.com/onflow/flow-go/engine/access/state_stream"
 	"github.com/onflow/flow-go/model/flow"
 )
 
-func newRouter(backend access.API, logger zerolog.Logger, chain flow.Chain) (*mux.Router, error) {
+func newRouter(
+	backend access.API,
+	logger zerolog.Logger,
+	chain flow.Chain,
+	stateStreamApi state_stream.API,
+	wsConfig WebsocketConfig,
+) (*mux.Router, error) {
 	router := mux.NewRouter().StrictSlash(true)
 	v1SubRouter := router.PathPrefix("/v1").Subrouter()
 
@@ -32,6 +39,16 @@ func newRouter(backend access.API, logger zerolog.Logger, chain flow.Chain) (*mu
 			Name(r.Name).
 			Handler(h)
 	}
+
+	// websocket subscriptions are only available if the state stream API is enabled
+	if stateStreamApi != nil {
+		v1SubRouter.
+			Methods(http.MethodGet).
+			Path("/subscribe").
+			Name("subscribe").
+			Handler(NewWebsocketHandler(logger, backend, stateStreamApi, linkGenerator, chain, wsConfig))
+	}
+
 	return router, nil
 }
 
@@ -52,6 +69,11 @@ var Routes = []route{{
 	Pattern: "/transactions",
 	Name:    "createTransaction",
 	Handler: CreateTransaction,
+}, {
+	Method:  http.MethodPost,
+	Pattern: "/transactions/simulate",
+	Name:    "simulateTransaction",
+	Handler: SimulateTransaction,
 }, {
 	Method:  http.MethodGet,
 	Pattern: "/transaction_results/{id}",
@@ -97,6 +119,21 @@ var Routes = []route{{
 	Pattern: "/accounts/{address}",
 	Name:    "getAccount",
 	Handler: GetAccount,
+}, {
+	Method:  http.MethodGet,
+	Pattern: "/accounts/{address}/balance",
+	Name:    "getAccountBalance",
+	Handler: GetAccountBalance,
+}, {
+	Method:  http.MethodGet,
+	Pattern: "/accounts/{address}/keys",
+	Name:    "getAccountKeys",
+	Handler: GetAccountKeys,
+}, {
+	Method:  http.MethodGet,
+	Pattern: "/accounts/{address}/keys/{index}",
+	Name:    "getAccountKeyByIndex",
+	Handler: GetAccountKeyByIndex,
 }, {
 	Method:  http.MethodGet,
 	Pattern: "/events",
@@ -108,3 +145,12 @@ var Routes = []route{{
 	Name:    "getNetworkParameters",
 	Handler: GetNetworkParameters,
 }}
+
+// WebsocketTopics maps the topics clients can subscribe to over the websocket endpoint to
+// the functions starting the subscriptions.
+var WebsocketTopics = map[string]SubscribeHandlerFunc{
+	"events":               SubscribeEvents,
+	"block_headers":        SubscribeBlockHeaders,
+	"transaction_statuses": SubscribeTransactionStatuses,
+	"send_and_subscribe_transaction_statuses": SendAndSubscribeTransactionStatuses,
+}
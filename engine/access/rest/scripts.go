@@ -1,36 +1,58 @@
 package rest
 
 import (
+	"context"
+
 	"github.com/onflow/flow-go/engine/access/rest/models"
 	"github.com/onflow/flow-go/engine/access/rest/request"
+	"github.com/onflow/flow-go/engine/common/rpc"
 	"github.com/onflow/flow-go/model/flow"
 
 	"github.com/onflow/flow-go/access"
 )
 
 // ExecuteScript handler sends the script from the request to be executed.
+// If the usage is requested, the result is returned together with the computation and memory
+// used by the script.
 func ExecuteScript(r *request.Request, backend access.API, _ models.LinkGenerator) (interface{}, error) {
 	req, err := r.GetScriptRequest()
 	if err != nil {
 		return nil, NewBadRequestError(err)
 	}
 
+	ctx := r.Context()
+	if !req.IncludeUsage {
+		return executeScript(ctx, req, backend)
+	}
+
+	ctx, recorder := rpc.WithScriptUsageRecorder(ctx)
+	value, err := executeScript(ctx, req, backend)
+	if err != nil {
+		return nil, err
+	}
+
+	var response models.ScriptExecution
+	response.Build(value, recorder.Usage())
+	return response, nil
+}
+
+func executeScript(ctx context.Context, req request.GetScript, backend access.API) ([]byte, error) {
 	if req.BlockID != flow.ZeroID {
-		return backend.ExecuteScriptAtBlockID(r.Context(), req.BlockID, req.Script.Source, req.Script.Args)
+		return backend.ExecuteScriptAtBlockID(ctx, req.BlockID, req.Script.Source, req.Script.Args)
 	}
 
 	// default to sealed height
 	if req.BlockHeight == request.SealedHeight || req.BlockHeight == request.EmptyHeight {
-		return backend.ExecuteScriptAtLatestBlock(r.Context(), req.Script.Source, req.Script.Args)
+		return backend.ExecuteScriptAtLatestBlock(ctx, req.Script.Source, req.Script.Args)
 	}
 
 	if req.BlockHeight == request.FinalHeight {
-		finalBlock, _, err := backend.GetLatestBlockHeader(r.Context(), false)
+		finalBlock, _, err := backend.GetLatestBlockHeader(ctx, false)
 		if err != nil {
 			return nil, err
 		}
 		req.BlockHeight = finalBlock.Height
 	}
 
-	return backend.ExecuteScriptAtBlockHeight(r.Context(), req.BlockHeight, req.Script.Source, req.Script.Args)
+	return backend.ExecuteScriptAtBlockHeight(ctx, req.BlockHeight, req.Script.Source, req.Script.Args)
 }
@@ -2,6 +2,7 @@ package rest
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -11,11 +12,15 @@ import (
 
 	"github.com/onflow/flow-go/engine/access/rest/util"
 
+	"github.com/onflow/cadence/runtime/common"
 	mocks "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	"github.com/onflow/flow-go/access/mock"
+	"github.com/onflow/flow-go/engine/common/rpc"
+	"github.com/onflow/flow-go/fvm/meter"
 	"github.com/onflow/flow-go/model/flow"
 )
 
@@ -89,6 +94,60 @@ func TestScripts(t *testing.T) {
 		), backend)
 	})
 
+	t.Run("get with usage", func(t *testing.T) {
+		backend := &mock.API{}
+		id, _ := flow.HexStringToIdentifier("222dc5dd51b9e4910f687e475f892f495f3352362ba318b53e318b4d78131312")
+		backend.Mock.
+			On("ExecuteScriptAtBlockID", mocks.Anything, id, validCode, [][]byte{validArgs}).
+			Run(func(args mocks.Arguments) {
+				ctx := args.Get(0).(context.Context)
+				require.True(t, rpc.ScriptUsageRequested(ctx))
+				require.True(t, rpc.RecordScriptUsage(ctx, rpc.ScriptUsageTrailer(meter.Usage{
+					ComputationUsed:  12,
+					ComputationLimit: 9999,
+					ComputationIntensities: meter.MeteredComputationIntensities{
+						common.ComputationKindStatement: 3,
+						common.ComputationKindLoop:      5,
+					},
+					MemoryEstimate: 2048,
+					MemoryIntensities: meter.MeteredMemoryIntensities{
+						common.MemoryKindStringValue: 2,
+					},
+				})))
+			}).
+			Return([]byte("hello world"), nil)
+
+		req := scriptReq(id.String(), "", validBody)
+		query := req.URL.Query()
+		query.Add("include_usage", "true")
+		req.URL.RawQuery = query.Encode()
+
+		assertOKResponse(t, req, fmt.Sprintf(`{
+			"value": "%s",
+			"computation_used": "12",
+			"memory_estimate": "2048",
+			"computation_intensities": {"Loop": "5", "Statement": "3"},
+			"memory_intensities": {"StringValue": "2"}
+		}`, base64.StdEncoding.EncodeToString([]byte(`hello world`))), backend)
+	})
+
+	t.Run("get with usage not reported", func(t *testing.T) {
+		backend := &mock.API{}
+		backend.Mock.
+			On("ExecuteScriptAtLatestBlock", mocks.Anything, validCode, [][]byte{validArgs}).
+			Return([]byte("hello world"), nil)
+
+		req := scriptReq("", sealedHeightQueryParam, validBody)
+		query := req.URL.Query()
+		query.Add("include_usage", "true")
+		req.URL.RawQuery = query.Encode()
+
+		assertOKResponse(t, req, fmt.Sprintf(
+			`{"value": "%s"}`,
+			base64.StdEncoding.EncodeToString([]byte(`hello world`)),
+		), backend)
+	})
+
 	t.Run("get error", func(t *testing.T) {
 		backend := &mock.API{}
 		backend.Mock.
@@ -128,5 +187,11 @@ func TestScripts(t *testing.T) {
 			req := scriptReq(test.id, test.height, test.body)
 			assertResponse(t, req, http.StatusBadRequest, test.out, backend)
 		}
+
+		req := scriptReq("", "1337", validBody)
+		query := req.URL.Query()
+		query.Add("include_usage", "maybe")
+		req.URL.RawQuery = query.Encode()
+		assertResponse(t, req, http.StatusBadRequest, `{"code":400,"message":"invalid value for include_usage: maybe"}`, backend)
 	})
 }
@@ -8,13 +8,22 @@ import (
 	"github.com/rs/zerolog"
 
 	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/engine/access/state_stream"
 	"github.com/onflow/flow-go/model/flow"
 )
 
-// NewServer returns an HTTP server initialized with the REST API handler
-func NewServer(backend access.API, listenAddress string, logger zerolog.Logger, chain flow.Chain) (*http.Server, error) {
+// NewServer returns an HTTP server initialized with the REST API handler.
+// Websocket subscriptions are served only if a state stream API is provided.
+func NewServer(
+	backend access.API,
+	listenAddress string,
+	logger zerolog.Logger,
+	chain flow.Chain,
+	stateStreamApi state_stream.API,
+	wsConfig WebsocketConfig,
+) (*http.Server, error) {
 
-	router, err := newRouter(backend, logger, chain)
+	router, err := newRouter(backend, logger, chain, stateStreamApi, wsConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,44 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/engine/access/rest/models"
+	"github.com/onflow/flow-go/engine/access/rest/request"
+	"github.com/onflow/flow-go/engine/access/state_stream"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// SubscribeBlockHeaders subscribes to the headers of finalized or sealed blocks, starting at the
+// provided block.
+func SubscribeBlockHeaders(
+	ctx context.Context,
+	arguments json.RawMessage,
+	_ access.API,
+	stateStreamApi state_stream.API,
+	_ models.LinkGenerator,
+	_ flow.Chain,
+	_ WebsocketConfig,
+) (state_stream.Subscription, SubscriptionResponseFunc, error) {
+	var req request.SubscribeBlockHeaders
+	err := req.Build(arguments)
+	if err != nil {
+		return nil, nil, NewBadRequestError(err)
+	}
+
+	sub := stateStreamApi.SubscribeBlockHeaders(ctx, req.StartBlockID, req.StartHeight, req.BlockStatus)
+
+	return sub, func(response interface{}) (interface{}, error) {
+		header, ok := response.(*flow.Header)
+		if !ok {
+			return nil, fmt.Errorf("unexpected response type: %T", response)
+		}
+
+		var blockHeader models.BlockHeader
+		blockHeader.Build(header)
+		return blockHeader, nil
+	}, nil
+}
@@ -0,0 +1,68 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/engine/access/rest/models"
+	"github.com/onflow/flow-go/engine/access/rest/request"
+	"github.com/onflow/flow-go/engine/access/state_stream"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// SubscribeEvents subscribes to events matching the provided filter, starting at the provided block.
+// Blocks without matching events are skipped, unless the heartbeat interval is reached.
+func SubscribeEvents(
+	ctx context.Context,
+	arguments json.RawMessage,
+	_ access.API,
+	stateStreamApi state_stream.API,
+	_ models.LinkGenerator,
+	chain flow.Chain,
+	config WebsocketConfig,
+) (state_stream.Subscription, SubscriptionResponseFunc, error) {
+	var req request.SubscribeEvents
+	err := req.Build(arguments)
+	if err != nil {
+		return nil, nil, NewBadRequestError(err)
+	}
+
+	filter, err := state_stream.NewEventFilter(
+		config.EventFilterConfig,
+		chain,
+		req.EventTypes,
+		req.Addresses,
+		req.Contracts,
+		req.FieldFilters,
+	)
+	if err != nil {
+		return nil, nil, NewBadRequestError(fmt.Errorf("invalid event filter: %w", err))
+	}
+
+	sub := stateStreamApi.SubscribeEvents(ctx, req.StartBlockID, req.StartHeight, filter)
+
+	blocksSinceLastResponse := uint64(0)
+	return sub, func(response interface{}) (interface{}, error) {
+		resp, ok := response.(*state_stream.EventsResponse)
+		if !ok {
+			return nil, fmt.Errorf("unexpected response type: %T", response)
+		}
+
+		blocksSinceLastResponse++
+		if len(resp.Events) == 0 && (req.HeartbeatInterval == 0 || blocksSinceLastResponse < req.HeartbeatInterval) {
+			return nil, nil
+		}
+		blocksSinceLastResponse = 0
+
+		var blockEvents models.BlockEvents
+		blockEvents.Build(flow.BlockEvents{
+			BlockID:        resp.BlockID,
+			BlockHeight:    resp.Height,
+			BlockTimestamp: resp.BlockTimestamp,
+			Events:         resp.Events,
+		})
+		return blockEvents, nil
+	}, nil
+}
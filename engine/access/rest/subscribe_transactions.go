@@ -0,0 +1,78 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/engine/access/rest/models"
+	"github.com/onflow/flow-go/engine/access/rest/request"
+	"github.com/onflow/flow-go/engine/access/state_stream"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// SubscribeTransactionStatuses subscribes to the status changes of a transaction. A response is
+// sent every time the status changes, and the subscription completes once the transaction is
+// either sealed or expired.
+func SubscribeTransactionStatuses(
+	ctx context.Context,
+	arguments json.RawMessage,
+	_ access.API,
+	stateStreamApi state_stream.API,
+	link models.LinkGenerator,
+	_ flow.Chain,
+	_ WebsocketConfig,
+) (state_stream.Subscription, SubscriptionResponseFunc, error) {
+	var req request.SubscribeTransactionStatuses
+	err := req.Build(arguments)
+	if err != nil {
+		return nil, nil, NewBadRequestError(err)
+	}
+
+	sub := stateStreamApi.SubscribeTransactionStatuses(ctx, req.TransactionID)
+
+	return sub, func(response interface{}) (interface{}, error) {
+		txr, ok := response.(*access.TransactionResult)
+		if !ok {
+			return nil, fmt.Errorf("unexpected response type: %T", response)
+		}
+
+		var result models.TransactionResult
+		result.Build(txr, req.TransactionID, link)
+		return result, nil
+	}, nil
+}
+
+// SendAndSubscribeTransactionStatuses sends a transaction and subscribes to its status changes,
+// like SubscribeTransactionStatuses. The arguments are the transaction, in the same format as the
+// body of a create transaction request.
+func SendAndSubscribeTransactionStatuses(
+	ctx context.Context,
+	arguments json.RawMessage,
+	_ access.API,
+	stateStreamApi state_stream.API,
+	link models.LinkGenerator,
+	chain flow.Chain,
+	_ WebsocketConfig,
+) (state_stream.Subscription, SubscriptionResponseFunc, error) {
+	var req request.SendAndSubscribeTransactionStatuses
+	err := req.Build(arguments, chain)
+	if err != nil {
+		return nil, nil, NewBadRequestError(err)
+	}
+
+	txID := req.Transaction.ID()
+	sub := stateStreamApi.SendAndSubscribeTransactionStatuses(ctx, &req.Transaction)
+
+	return sub, func(response interface{}) (interface{}, error) {
+		txr, ok := response.(*access.TransactionResult)
+		if !ok {
+			return nil, fmt.Errorf("unexpected response type: %T", response)
+		}
+
+		var result models.TransactionResult
+		result.Build(txr, txID, link)
+		return result, nil
+	}, nil
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/onflow/flow-go/access"
 	"github.com/onflow/flow-go/engine/access/rest/models"
 	"github.com/onflow/flow-go/engine/access/rest/request"
+	"github.com/onflow/flow-go/model/flow"
 )
 
 // GetTransactionByID gets a transaction by requested ID.
@@ -65,3 +66,35 @@ func CreateTransaction(r *request.Request, backend access.API, link models.LinkG
 	response.Build(&req.Transaction, nil, link)
 	return response, nil
 }
+
+// SimulateTransaction executes the transaction from the provided payload at the requested block,
+// without applying its state changes.
+func SimulateTransaction(r *request.Request, backend access.API, _ models.LinkGenerator) (interface{}, error) {
+	req, err := r.SimulateTransactionRequest()
+	if err != nil {
+		return nil, NewBadRequestError(err)
+	}
+
+	blockID := req.BlockID
+	if blockID == flow.ZeroID {
+		height, err := resolveHeight(r.Context(), backend, req.BlockHeight)
+		if err != nil {
+			return nil, err
+		}
+
+		header, _, err := backend.GetBlockHeaderByHeight(r.Context(), height)
+		if err != nil {
+			return nil, err
+		}
+		blockID = header.ID()
+	}
+
+	result, err := backend.SimulateTransaction(r.Context(), blockID, &req.Transaction, req.SkipSignatureVerification)
+	if err != nil {
+		return nil, err
+	}
+
+	var response models.TransactionSimulation
+	response.Build(result)
+	return response, nil
+}
@@ -47,6 +47,22 @@ func createTransactionReq(body interface{}) *http.Request {
 	return req
 }
 
+func simulateTransactionReq(body interface{}, blockID string, skipSignatureVerification string) *http.Request {
+	u, _ := url.Parse("/v1/transactions/simulate")
+	q := u.Query()
+	if blockID != "" {
+		q.Add("block_id", blockID)
+	}
+	if skipSignatureVerification != "" {
+		q.Add("skip_signature_verification", skipSignatureVerification)
+	}
+	u.RawQuery = q.Encode()
+
+	jsonBody, _ := json.Marshal(body)
+	req, _ := http.NewRequest("POST", u.String(), bytes.NewBuffer(jsonBody))
+	return req
+}
+
 func validCreateBody(tx flow.TransactionBody) map[string]interface{} {
 	tx.Arguments = [][]uint8{} // fix how fixture creates nil values
 	auth := make([]string, len(tx.Authorizers))
@@ -404,6 +420,101 @@ func TestCreateTransaction(t *testing.T) {
 	})
 }
 
+func TestSimulateTransaction(t *testing.T) {
+	blockID := unittest.IdentifierFixture()
+	register := flow.NewRegisterID("01", "foo")
+
+	expected := fmt.Sprintf(`{
+		"block_id": "%s",
+		"error_message": "",
+		"computation_used": "12",
+		"memory_estimate": "300",
+		"events": [],
+		"registers_read": ["%s"],
+		"registers_written": ["%s"]
+	}`, blockID, register, register)
+
+	result := &access.TransactionSimulationResult{
+		BlockID:          blockID,
+		Events:           []flow.Event{},
+		ComputationUsed:  12,
+		MemoryEstimate:   300,
+		RegistersRead:    []flow.RegisterID{register},
+		RegistersWritten: []flow.RegisterID{register},
+	}
+
+	t.Run("simulate at block ID", func(t *testing.T) {
+		backend := &mock.API{}
+		tx := unittest.TransactionBodyFixture()
+		tx.PayloadSignatures = []flow.TransactionSignature{unittest.TransactionSignatureFixture()}
+		tx.Arguments = [][]uint8{}
+		req := simulateTransactionReq(validCreateBody(tx), blockID.String(), "")
+
+		backend.Mock.
+			On("SimulateTransaction", mocks.Anything, blockID, &tx, false).
+			Return(result, nil)
+
+		assertOKResponse(t, req, expected, backend)
+	})
+
+	t.Run("simulate unsigned transaction at latest sealed block", func(t *testing.T) {
+		backend := &mock.API{}
+		tx := unittest.TransactionBodyFixture()
+		tx.PayloadSignatures = []flow.TransactionSignature{unittest.TransactionSignatureFixture()}
+		body := validCreateBody(tx)
+		delete(body, "payload_signatures")
+		delete(body, "envelope_signatures")
+		req := simulateTransactionReq(body, "", "true")
+
+		header := unittest.BlockHeaderFixture()
+		header.Height = 5
+		result := *result
+		result.BlockID = header.ID()
+
+		backend.Mock.
+			On("GetLatestBlockHeader", mocks.Anything, true).
+			Return(header, flow.BlockStatusSealed, nil)
+		backend.Mock.
+			On("GetBlockHeaderByHeight", mocks.Anything, header.Height).
+			Return(header, flow.BlockStatusSealed, nil)
+		backend.Mock.
+			On("SimulateTransaction", mocks.Anything, header.ID(), mocks.Anything, true).
+			Return(&result, nil)
+
+		expected := fmt.Sprintf(`{
+			"block_id": "%s",
+			"error_message": "",
+			"computation_used": "12",
+			"memory_estimate": "300",
+			"events": [],
+			"registers_read": ["%s"],
+			"registers_written": ["%s"]
+		}`, header.ID(), register, register)
+
+		assertOKResponse(t, req, expected, backend)
+	})
+
+	t.Run("simulate unsigned transaction without skipping verification", func(t *testing.T) {
+		backend := &mock.API{}
+		tx := unittest.TransactionBodyFixture()
+		tx.PayloadSignatures = []flow.TransactionSignature{unittest.TransactionSignatureFixture()}
+		body := validCreateBody(tx)
+		delete(body, "envelope_signatures")
+		req := simulateTransactionReq(body, blockID.String(), "")
+
+		assertResponse(t, req, http.StatusBadRequest, `{"code":400, "message":"envelope signatures not provided"}`, backend)
+	})
+
+	t.Run("invalid skip signature verification value", func(t *testing.T) {
+		backend := &mock.API{}
+		tx := unittest.TransactionBodyFixture()
+		tx.PayloadSignatures = []flow.TransactionSignature{unittest.TransactionSignatureFixture()}
+		req := simulateTransactionReq(validCreateBody(tx), blockID.String(), "maybe")
+
+		assertResponse(t, req, http.StatusBadRequest, `{"code":400, "message":"invalid value for skip_signature_verification: maybe"}`, backend)
+	})
+}
+
 func transactionResultFixture(tx flow.Transaction) *access.TransactionResult {
 	return &access.TransactionResult{
 		Status:     flow.TransactionStatusSealed,
@@ -0,0 +1,486 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/engine/access/rest/models"
+	"github.com/onflow/flow-go/engine/access/rest/request"
+	"github.com/onflow/flow-go/engine/access/state_stream"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+const (
+	// DefaultMaxWebsocketConnections is the default max number of websocket connections that can be
+	// open at the same time.
+	DefaultMaxWebsocketConnections = 1000
+
+	// DefaultMaxSubscriptionsPerConnection is the default max number of subscriptions a single
+	// websocket connection may have open at the same time.
+	DefaultMaxSubscriptionsPerConnection = 20
+
+	// DefaultMaxResponsesPerSecond is the default max number of responses sent per second on a
+	// single websocket connection.
+	DefaultMaxResponsesPerSecond = 100
+
+	// DefaultWebsocketHeartbeatInterval is the default interval at which ping messages are sent
+	// to the client to keep the connection alive and detect dead peers.
+	DefaultWebsocketHeartbeatInterval = 20 * time.Second
+
+	// DefaultWebsocketSendTimeout is the default timeout for writing a message to the client.
+	// After the timeout expires, the connection is closed.
+	DefaultWebsocketSendTimeout = 10 * time.Second
+
+	// maxWebsocketMessageSize is the max size of a message received from the client.
+	maxWebsocketMessageSize = 64 << 10 // 64KB
+)
+
+// WebsocketConfig defines the configurable options for websocket subscriptions.
+type WebsocketConfig struct {
+	state_stream.EventFilterConfig
+
+	// MaxConnections is the max number of websocket connections that can be open at the same time.
+	MaxConnections uint32
+
+	// MaxSubscriptionsPerConnection is the max number of subscriptions a single connection may
+	// have open at the same time.
+	MaxSubscriptionsPerConnection uint32
+
+	// MaxResponsesPerSecond is the max number of responses sent per second on a single connection.
+	MaxResponsesPerSecond float64
+
+	// HeartbeatInterval is the interval at which ping messages are sent to the client. Connections
+	// that don't respond to a ping within the interval are closed.
+	HeartbeatInterval time.Duration
+
+	// SendTimeout is the timeout for writing a message to the client.
+	SendTimeout time.Duration
+}
+
+// DefaultWebsocketConfig is the default configuration for websocket subscriptions.
+var DefaultWebsocketConfig = WebsocketConfig{
+	EventFilterConfig:             state_stream.DefaultEventFilterConfig,
+	MaxConnections:                DefaultMaxWebsocketConnections,
+	MaxSubscriptionsPerConnection: DefaultMaxSubscriptionsPerConnection,
+	MaxResponsesPerSecond:         DefaultMaxResponsesPerSecond,
+	HeartbeatInterval:             DefaultWebsocketHeartbeatInterval,
+	SendTimeout:                   DefaultWebsocketSendTimeout,
+}
+
+// SubscribeHandlerFunc is a function that contains the logic for starting a subscription on a
+// websocket topic. It parses the subscription arguments and returns the subscription along with
+// a function converting subscription responses into response models.
+type SubscribeHandlerFunc func(
+	ctx context.Context,
+	arguments json.RawMessage,
+	backend access.API,
+	stateStreamApi state_stream.API,
+	generator models.LinkGenerator,
+	chain flow.Chain,
+	config WebsocketConfig,
+) (state_stream.Subscription, SubscriptionResponseFunc, error)
+
+// SubscriptionResponseFunc converts a response received from a subscription into the payload
+// sent to the client. A nil payload means that nothing is sent for the response.
+type SubscriptionResponseFunc func(response interface{}) (interface{}, error)
+
+// WebsocketHandler upgrades HTTP requests to websocket connections and manages the subscriptions
+// opened by clients on each connection.
+type WebsocketHandler struct {
+	logger         zerolog.Logger
+	backend        access.API
+	stateStreamApi state_stream.API
+	linkGenerator  models.LinkGenerator
+	chain          flow.Chain
+	config         WebsocketConfig
+	upgrader       websocket.Upgrader
+
+	connectionCount atomic.Int32
+}
+
+func NewWebsocketHandler(
+	logger zerolog.Logger,
+	backend access.API,
+	stateStreamApi state_stream.API,
+	generator models.LinkGenerator,
+	chain flow.Chain,
+	config WebsocketConfig,
+) *WebsocketHandler {
+	return &WebsocketHandler{
+		logger:         logger.With().Str("component", "websocket_handler").Logger(),
+		backend:        backend,
+		stateStreamApi: stateStreamApi,
+		linkGenerator:  generator,
+		chain:          chain,
+		config:         config,
+		upgrader: websocket.Upgrader{
+			// CORS is handled by the server for all routes
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+}
+
+// ServeHTTP upgrades the request to a websocket connection and serves it until either the
+// client disconnects or the connection fails.
+func (h *WebsocketHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	errLog := h.logger.With().Str("request_url", r.URL.String()).Logger()
+
+	// reserve a connection slot before checking the limit, so that concurrent requests can't
+	// exceed it
+	if h.connectionCount.Add(1) > int32(h.config.MaxConnections) {
+		h.connectionCount.Add(-1)
+		h.errorResponse(w, http.StatusServiceUnavailable, "maximum number of websocket connections reached", errLog)
+		return
+	}
+	defer h.connectionCount.Add(-1)
+
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// the upgrader already responded to the client
+		errLog.Debug().Err(err).Msg("could not upgrade websocket connection")
+		return
+	}
+
+	newWebsocketConnection(h, conn, errLog).serve(r.Context())
+}
+
+func (h *WebsocketHandler) errorResponse(w http.ResponseWriter, returnCode int, responseMessage string, logger zerolog.Logger) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(returnCode)
+
+	err := json.NewEncoder(w).Encode(models.ModelError{
+		Code:    int32(returnCode),
+		Message: responseMessage,
+	})
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to write http response")
+	}
+}
+
+// websocketConnection serves a single websocket connection. All writes to the connection are
+// done by the write loop, which consumes the responses queued by the read loop and the
+// subscription streams.
+type websocketConnection struct {
+	*WebsocketHandler
+
+	log       zerolog.Logger
+	conn      *websocket.Conn
+	responses chan models.WebsocketResponse
+	limiter   *rate.Limiter
+
+	mu            sync.Mutex
+	subscriptions map[string]*activeSubscription
+	wg            sync.WaitGroup
+}
+
+// activeSubscription tracks a subscription streaming on a connection
+type activeSubscription struct {
+	cancel context.CancelFunc
+}
+
+func newWebsocketConnection(h *WebsocketHandler, conn *websocket.Conn, log zerolog.Logger) *websocketConnection {
+	return &websocketConnection{
+		WebsocketHandler: h,
+		log:              log.With().Str("remote_addr", conn.RemoteAddr().String()).Logger(),
+		conn:             conn,
+		responses:        make(chan models.WebsocketResponse, state_stream.DefaultSendBufferSize),
+		limiter:          rate.NewLimiter(rate.Limit(h.config.MaxResponsesPerSecond), 1),
+		subscriptions:    make(map[string]*activeSubscription),
+	}
+}
+
+// serve is a blocking method that reads messages from the client until the connection is closed,
+// and then stops all subscriptions of the connection.
+func (c *websocketConnection) serve(ctx context.Context) {
+	c.log.Debug().Msg("websocket connection opened")
+	defer c.log.Debug().Msg("websocket connection closed")
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	writerDone := make(chan struct{})
+	go func() {
+		defer close(writerDone)
+		c.writeLoop(ctx)
+
+		// closing the connection unblocks the read loop if the write loop failed
+		cancel()
+		err := c.conn.Close()
+		if err != nil {
+			c.log.Debug().Err(err).Msg("error closing websocket connection")
+		}
+	}()
+
+	c.readLoop(ctx)
+
+	// stop all subscriptions and the write loop
+	cancel()
+	c.wg.Wait()
+	<-writerDone
+}
+
+// readLoop reads and handles messages from the client until the connection is closed or the
+// client stops responding to pings.
+func (c *websocketConnection) readLoop(ctx context.Context) {
+	c.conn.SetReadLimit(maxWebsocketMessageSize)
+
+	pongWait := 2 * c.config.HeartbeatInterval
+	_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, raw, err := c.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				c.log.Debug().Err(err).Msg("websocket connection closed unexpectedly")
+			}
+			return
+		}
+
+		var msg request.WebsocketMessage
+		err = msg.Parse(raw)
+		if err != nil {
+			c.sendError(ctx, "", NewBadRequestError(err))
+			continue
+		}
+
+		switch msg.Action {
+		case request.SubscribeAction:
+			c.subscribe(ctx, msg)
+		case request.UnsubscribeAction:
+			c.unsubscribe(ctx, msg)
+		}
+	}
+}
+
+// writeLoop writes queued responses and periodic pings to the client until the context is
+// cancelled or a write fails.
+func (c *websocketConnection) writeLoop(ctx context.Context) {
+	ticker := time.NewTicker(c.config.HeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			_ = c.conn.WriteControl(
+				websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+				time.Now().Add(c.config.SendTimeout),
+			)
+			return
+		case <-ticker.C:
+			err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(c.config.SendTimeout))
+			if err != nil {
+				c.log.Debug().Err(err).Msg("could not send ping")
+				return
+			}
+		case resp := <-c.responses:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(c.config.SendTimeout))
+			err := c.conn.WriteJSON(resp)
+			if err != nil {
+				c.log.Debug().Err(err).Msg("could not write response")
+				return
+			}
+		}
+	}
+}
+
+// subscribe starts a new subscription for the requested topic and streams its responses to
+// the client.
+func (c *websocketConnection) subscribe(ctx context.Context, msg request.WebsocketMessage) {
+	handlerFunc, ok := WebsocketTopics[msg.Topic]
+	if !ok {
+		c.sendError(ctx, msg.SubscriptionID, NewBadRequestError(fmt.Errorf("unknown topic: %q", msg.Topic)))
+		return
+	}
+
+	subscriptionID := msg.SubscriptionID
+	if subscriptionID == "" {
+		subscriptionID = uuid.New().String()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.subscriptions[subscriptionID]; ok {
+		c.sendError(ctx, subscriptionID, NewBadRequestError(fmt.Errorf("subscription already exists")))
+		return
+	}
+
+	if len(c.subscriptions) >= int(c.config.MaxSubscriptionsPerConnection) {
+		err := fmt.Errorf("maximum number of subscriptions per connection reached")
+		c.sendError(ctx, subscriptionID, NewRestError(http.StatusTooManyRequests, err.Error(), err))
+		return
+	}
+
+	subCtx, subCancel := context.WithCancel(ctx)
+	sub, convert, err := handlerFunc(subCtx, msg.Arguments, c.backend, c.stateStreamApi, c.linkGenerator, c.chain, c.config)
+	if err != nil {
+		subCancel()
+		c.sendError(ctx, subscriptionID, err)
+		return
+	}
+
+	active := &activeSubscription{cancel: subCancel}
+	c.subscriptions[subscriptionID] = active
+	c.send(ctx, models.WebsocketResponse{
+		SubscriptionId: subscriptionID,
+		Topic:          msg.Topic,
+		Action:         request.SubscribeAction,
+	})
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.stream(subCtx, active, subscriptionID, msg.Topic, sub, convert)
+	}()
+}
+
+// unsubscribe stops the subscription with the requested ID.
+func (c *websocketConnection) unsubscribe(ctx context.Context, msg request.WebsocketMessage) {
+	c.mu.Lock()
+	active, ok := c.subscriptions[msg.SubscriptionID]
+	delete(c.subscriptions, msg.SubscriptionID)
+	c.mu.Unlock()
+
+	if !ok {
+		c.sendError(ctx, msg.SubscriptionID, NewNotFoundError("subscription not found", fmt.Errorf("subscription %s not found", msg.SubscriptionID)))
+		return
+	}
+
+	active.cancel()
+	c.send(ctx, models.WebsocketResponse{
+		SubscriptionId: msg.SubscriptionID,
+		Action:         request.UnsubscribeAction,
+	})
+}
+
+// stream forwards all responses of the subscription to the client until either the subscription
+// ends or the context is cancelled.
+func (c *websocketConnection) stream(
+	ctx context.Context,
+	active *activeSubscription,
+	subscriptionID string,
+	topic string,
+	sub state_stream.Subscription,
+	convert SubscriptionResponseFunc,
+) {
+	defer func() {
+		active.cancel()
+
+		// the ID may have been reused by a new subscription after this one was unsubscribed
+		c.mu.Lock()
+		if c.subscriptions[subscriptionID] == active {
+			delete(c.subscriptions, subscriptionID)
+		}
+		c.mu.Unlock()
+	}()
+
+	for {
+		v, ok := <-sub.Channel()
+		if !ok {
+			// subscriptions cancelled by the client or by closing the connection end without
+			// notifying the client
+			if ctx.Err() != nil {
+				return
+			}
+
+			if sub.Err() != nil {
+				c.sendError(ctx, subscriptionID, sub.Err())
+				return
+			}
+
+			// the subscription completed gracefully
+			c.send(ctx, models.WebsocketResponse{
+				SubscriptionId: subscriptionID,
+				Topic:          topic,
+				Action:         request.UnsubscribeAction,
+			})
+			return
+		}
+
+		payload, err := convert(v)
+		if err != nil {
+			c.sendError(ctx, subscriptionID, err)
+			return
+		}
+
+		// nothing to send for this response
+		if payload == nil {
+			continue
+		}
+
+		err = c.limiter.Wait(ctx)
+		if err != nil {
+			return
+		}
+
+		c.send(ctx, models.WebsocketResponse{
+			SubscriptionId: subscriptionID,
+			Topic:          topic,
+			Payload:        payload,
+		})
+	}
+}
+
+// send queues a response for the write loop. Responses are dropped once the connection is closing.
+func (c *websocketConnection) send(ctx context.Context, resp models.WebsocketResponse) {
+	select {
+	case <-ctx.Done():
+	case c.responses <- resp:
+	}
+}
+
+// sendError converts the error into an error response and queues it for the write loop.
+func (c *websocketConnection) sendError(ctx context.Context, subscriptionID string, err error) {
+	code, msg := c.errorStatus(err)
+	c.send(ctx, models.WebsocketResponse{
+		SubscriptionId: subscriptionID,
+		Error: &models.ModelError{
+			Code:    int32(code),
+			Message: msg,
+		},
+	})
+}
+
+// errorStatus returns the HTTP status code and user message for the error, following the same
+// conventions as the request/response handler.
+func (c *websocketConnection) errorStatus(err error) (int, string) {
+	// rest status type error should be returned with status and user message provided
+	var statusErr StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Status(), statusErr.UserMessage()
+	}
+
+	// handle grpc status error returned from the backend calls, we are forwarding the message to the client
+	if se, ok := status.FromError(err); ok {
+		switch se.Code() {
+		case codes.NotFound:
+			return http.StatusNotFound, fmt.Sprintf("Flow resource not found: %s", se.Message())
+		case codes.InvalidArgument:
+			return http.StatusBadRequest, fmt.Sprintf("Invalid Flow argument: %s", se.Message())
+		case codes.ResourceExhausted:
+			return http.StatusTooManyRequests, fmt.Sprintf("Resource exhausted: %s", se.Message())
+		}
+	}
+
+	// stop going further - catch all error
+	msg := "internal server error"
+	c.log.Error().Err(err).Msg(msg)
+	return http.StatusInternalServerError, msg
+}
@@ -0,0 +1,447 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	mocks "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/access"
+	accessmock "github.com/onflow/flow-go/access/mock"
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/engine/access/rest/models"
+	"github.com/onflow/flow-go/engine/access/state_stream"
+	ssmock "github.com/onflow/flow-go/engine/access/state_stream/mock"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// newWebsocketTestServer starts a REST server with websocket subscriptions enabled and returns a
+// client connected to the websocket endpoint.
+func newWebsocketTestServer(t *testing.T, stateStreamApi *ssmock.API, config WebsocketConfig) *websocket.Conn {
+	var b bytes.Buffer
+	logger := zerolog.New(&b)
+
+	router, err := newRouter(&accessmock.API{}, logger, flow.Testnet.Chain(), stateStreamApi, config)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/v1/subscribe"
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func readWebsocketResponse(t *testing.T, conn *websocket.Conn) models.WebsocketResponse {
+	var resp models.WebsocketResponse
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	require.NoError(t, conn.ReadJSON(&resp))
+	return resp
+}
+
+func TestWebsocketSubscribeEvents(t *testing.T) {
+	stateStreamApi := &ssmock.API{}
+	conn := newWebsocketTestServer(t, stateStreamApi, DefaultWebsocketConfig)
+
+	block := unittest.BlockFixture()
+	blockEvents := unittest.BlockEventsFixture(block.Header, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sub := state_stream.NewSubscription(state_stream.DefaultSendBufferSize)
+	stateStreamApi.
+		On("SubscribeEvents", mocks.Anything, flow.ZeroID, block.Header.Height, mocks.Anything).
+		Return(sub)
+
+	err := conn.WriteJSON(map[string]interface{}{
+		"action":          "subscribe",
+		"topic":           "events",
+		"subscription_id": "sub1",
+		"arguments": map[string]interface{}{
+			"start_height":       fmt.Sprintf("%d", block.Header.Height),
+			"heartbeat_interval": "2",
+		},
+	})
+	require.NoError(t, err)
+
+	resp := readWebsocketResponse(t, conn)
+	assert.Equal(t, "sub1", resp.SubscriptionId)
+	assert.Equal(t, "subscribe", resp.Action)
+	assert.Nil(t, resp.Error)
+
+	// blocks without events are skipped until the heartbeat interval is reached
+	emptyResponse := &state_stream.EventsResponse{BlockID: unittest.IdentifierFixture(), Height: block.Header.Height}
+	require.NoError(t, sub.Send(ctx, emptyResponse, time.Second))
+	require.NoError(t, sub.Send(ctx, &state_stream.EventsResponse{
+		BlockID:        block.ID(),
+		Height:         block.Header.Height + 1,
+		BlockTimestamp: block.Header.Timestamp,
+		Events:         blockEvents.Events,
+	}, time.Second))
+	require.NoError(t, sub.Send(ctx, emptyResponse, time.Second))
+	require.NoError(t, sub.Send(ctx, emptyResponse, time.Second))
+
+	resp = readWebsocketResponse(t, conn)
+	assert.Equal(t, "sub1", resp.SubscriptionId)
+	assert.Equal(t, "events", resp.Topic)
+
+	payload, err := json.Marshal(resp.Payload)
+	require.NoError(t, err)
+	var received models.BlockEvents
+	require.NoError(t, json.Unmarshal(payload, &received))
+	assert.Equal(t, block.ID().String(), received.BlockId)
+	assert.Equal(t, fmt.Sprintf("%d", block.Header.Height+1), received.BlockHeight)
+	assert.Len(t, received.Events, len(blockEvents.Events))
+
+	// heartbeat after 2 blocks without events
+	resp = readWebsocketResponse(t, conn)
+	payload, err = json.Marshal(resp.Payload)
+	require.NoError(t, err)
+	var heartbeat models.BlockEvents
+	require.NoError(t, json.Unmarshal(payload, &heartbeat))
+	assert.Equal(t, fmt.Sprintf("%d", block.Header.Height), heartbeat.BlockHeight)
+	assert.Empty(t, heartbeat.Events)
+
+	// unsubscribe
+	err = conn.WriteJSON(map[string]interface{}{
+		"action":          "unsubscribe",
+		"subscription_id": "sub1",
+	})
+	require.NoError(t, err)
+
+	resp = readWebsocketResponse(t, conn)
+	assert.Equal(t, "sub1", resp.SubscriptionId)
+	assert.Equal(t, "unsubscribe", resp.Action)
+}
+
+func TestWebsocketSubscribeBlockHeaders(t *testing.T) {
+	stateStreamApi := &ssmock.API{}
+	conn := newWebsocketTestServer(t, stateStreamApi, DefaultWebsocketConfig)
+
+	block := unittest.BlockFixture()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sub := state_stream.NewSubscription(state_stream.DefaultSendBufferSize)
+	stateStreamApi.
+		On("SubscribeBlockHeaders", mocks.Anything, block.ID(), uint64(0), flow.BlockStatusSealed).
+		Return(sub)
+
+	err := conn.WriteJSON(map[string]interface{}{
+		"action": "subscribe",
+		"topic":  "block_headers",
+		"arguments": map[string]interface{}{
+			"start_block_id": block.ID().String(),
+			"block_status":   "sealed",
+		},
+	})
+	require.NoError(t, err)
+
+	resp := readWebsocketResponse(t, conn)
+	require.Nil(t, resp.Error)
+	assert.NotEmpty(t, resp.SubscriptionId)
+
+	require.NoError(t, sub.Send(ctx, block.Header, time.Second))
+
+	resp = readWebsocketResponse(t, conn)
+	payload, err := json.Marshal(resp.Payload)
+	require.NoError(t, err)
+	var received models.BlockHeader
+	require.NoError(t, json.Unmarshal(payload, &received))
+	assert.Equal(t, block.ID().String(), received.Id)
+
+	// subscriptions completed by the backend are reported to the client
+	sub.Close()
+	resp = readWebsocketResponse(t, conn)
+	assert.Equal(t, "unsubscribe", resp.Action)
+}
+
+func TestWebsocketSubscribeTransactionStatuses(t *testing.T) {
+	stateStreamApi := &ssmock.API{}
+	conn := newWebsocketTestServer(t, stateStreamApi, DefaultWebsocketConfig)
+
+	txID := unittest.IdentifierFixture()
+	blockID := unittest.IdentifierFixture()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sub := state_stream.NewSubscription(state_stream.DefaultSendBufferSize)
+	stateStreamApi.
+		On("SubscribeTransactionStatuses", mocks.Anything, txID).
+		Return(sub)
+
+	err := conn.WriteJSON(map[string]interface{}{
+		"action": "subscribe",
+		"topic":  "transaction_statuses",
+		"arguments": map[string]interface{}{
+			"transaction_id": txID.String(),
+		},
+	})
+	require.NoError(t, err)
+
+	resp := readWebsocketResponse(t, conn)
+	require.Nil(t, resp.Error)
+	assert.NotEmpty(t, resp.SubscriptionId)
+
+	statuses := map[flow.TransactionStatus]models.TransactionStatus{
+		flow.TransactionStatusFinalized: models.FINALIZED_TransactionStatus,
+		flow.TransactionStatusSealed:    models.SEALED_TransactionStatus,
+	}
+	for _, txStatus := range []flow.TransactionStatus{flow.TransactionStatusFinalized, flow.TransactionStatusSealed} {
+		require.NoError(t, sub.Send(ctx, &access.TransactionResult{
+			Status:        txStatus,
+			BlockID:       blockID,
+			TransactionID: txID,
+		}, time.Second))
+
+		resp = readWebsocketResponse(t, conn)
+		assert.Equal(t, "transaction_statuses", resp.Topic)
+
+		payload, err := json.Marshal(resp.Payload)
+		require.NoError(t, err)
+		var received models.TransactionResult
+		require.NoError(t, json.Unmarshal(payload, &received))
+		assert.Equal(t, blockID.String(), received.BlockId)
+		require.NotNil(t, received.Status)
+		assert.Equal(t, statuses[txStatus], *received.Status)
+	}
+
+	// the backend completes the subscription once the transaction is sealed
+	sub.Close()
+	resp = readWebsocketResponse(t, conn)
+	assert.Equal(t, "unsubscribe", resp.Action)
+}
+
+// TestWebsocketSendAndSubscribeTransactionStatuses tests sending a transaction and streaming its
+// status changes end to end, from the websocket connection through the state stream backend to
+// the Access API.
+func TestWebsocketSendAndSubscribeTransactionStatuses(t *testing.T) {
+	blockBroadcaster := engine.NewBroadcaster()
+	config := state_stream.Config{
+		ClientSendTimeout:    state_stream.DefaultSendTimeout,
+		ClientSendBufferSize: state_stream.DefaultSendBufferSize,
+	}
+	stateStreamBackend, err := state_stream.New(zerolog.Nop(), config, nil, nil, nil, nil, nil, nil, engine.NewBroadcaster(), blockBroadcaster, nil)
+	require.NoError(t, err)
+
+	transactions := accessmock.NewAPI(t)
+	stateStreamBackend.SetTransactionsAPI(transactions)
+
+	router, err := newRouter(&accessmock.API{}, zerolog.Nop(), flow.Testnet.Chain(), stateStreamBackend, DefaultWebsocketConfig)
+	require.NoError(t, err)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/v1/subscribe", nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	tx := unittest.TransactionBodyFixture()
+	tx.PayloadSignatures = []flow.TransactionSignature{unittest.TransactionSignatureFixture()}
+	tx.Arguments = [][]byte{}
+	txID := tx.ID()
+	blockID := unittest.IdentifierFixture()
+
+	// the transaction is unknown until it is pending
+	var currentStatus atomic.Int32
+	currentStatus.Store(-1)
+
+	transactions.
+		On("SendTransaction", mocks.Anything, &tx).
+		Return(nil).
+		Once()
+	transactions.
+		On("GetTransactionResult", mocks.Anything, txID).
+		Return(func(context.Context, flow.Identifier) (*access.TransactionResult, error) {
+			txStatus := currentStatus.Load()
+			if txStatus < 0 {
+				return nil, status.Errorf(codes.NotFound, "transaction not found")
+			}
+			return &access.TransactionResult{
+				Status:        flow.TransactionStatus(txStatus),
+				BlockID:       blockID,
+				TransactionID: txID,
+			}, nil
+		})
+
+	err = conn.WriteJSON(map[string]interface{}{
+		"action":          "subscribe",
+		"topic":           "send_and_subscribe_transaction_statuses",
+		"subscription_id": "sub1",
+		"arguments":       validCreateBody(tx),
+	})
+	require.NoError(t, err)
+
+	resp := readWebsocketResponse(t, conn)
+	require.Nil(t, resp.Error)
+	assert.Equal(t, "sub1", resp.SubscriptionId)
+
+	statuses := map[flow.TransactionStatus]models.TransactionStatus{
+		flow.TransactionStatusPending:   models.PENDING_TransactionStatus,
+		flow.TransactionStatusFinalized: models.FINALIZED_TransactionStatus,
+		flow.TransactionStatusExecuted:  models.EXECUTED_TransactionStatus,
+		flow.TransactionStatusSealed:    models.SEALED_TransactionStatus,
+	}
+	for _, txStatus := range []flow.TransactionStatus{
+		flow.TransactionStatusPending,
+		flow.TransactionStatusFinalized,
+		flow.TransactionStatusExecuted,
+		flow.TransactionStatusSealed,
+	} {
+		currentStatus.Store(int32(txStatus))
+		blockBroadcaster.Publish()
+
+		resp = readWebsocketResponse(t, conn)
+		require.Nil(t, resp.Error)
+		assert.Equal(t, "send_and_subscribe_transaction_statuses", resp.Topic)
+		assert.Equal(t, "sub1", resp.SubscriptionId)
+
+		payload, err := json.Marshal(resp.Payload)
+		require.NoError(t, err)
+		var received models.TransactionResult
+		require.NoError(t, json.Unmarshal(payload, &received))
+		assert.Equal(t, blockID.String(), received.BlockId)
+		require.NotNil(t, received.Status)
+		assert.Equal(t, statuses[txStatus], *received.Status)
+	}
+
+	// the subscription completes once the transaction is sealed
+	resp = readWebsocketResponse(t, conn)
+	assert.Equal(t, "unsubscribe", resp.Action)
+	assert.Equal(t, "sub1", resp.SubscriptionId)
+}
+
+// TestWebsocketMaxConnections tests that concurrent connection requests can't exceed the max
+// number of connections.
+func TestWebsocketMaxConnections(t *testing.T) {
+	config := DefaultWebsocketConfig
+	config.MaxConnections = 2
+
+	router, err := newRouter(&accessmock.API{}, zerolog.Nop(), flow.Testnet.Chain(), &ssmock.API{}, config)
+	require.NoError(t, err)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/v1/subscribe"
+
+	var connected atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			conn, resp, err := websocket.DefaultDialer.Dial(url, nil)
+			if err != nil {
+				assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+				return
+			}
+			connected.Add(1)
+			t.Cleanup(func() { conn.Close() })
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(config.MaxConnections), connected.Load())
+}
+
+func TestWebsocketErrors(t *testing.T) {
+	config := DefaultWebsocketConfig
+	config.MaxSubscriptionsPerConnection = 1
+
+	stateStreamApi := &ssmock.API{}
+	conn := newWebsocketTestServer(t, stateStreamApi, config)
+
+	stateStreamApi.
+		On("SubscribeBlockHeaders", mocks.Anything, flow.ZeroID, uint64(0), flow.BlockStatusFinalized).
+		Return(state_stream.NewSubscription(state_stream.DefaultSendBufferSize))
+
+	tests := []struct {
+		description string
+		message     string
+		code        int32
+		contains    string
+	}{
+		{
+			description: "invalid message",
+			message:     `{"action": "subscribe", "topic": "events"`,
+			code:        400,
+			contains:    "badly-formed JSON",
+		},
+		{
+			description: "invalid action",
+			message:     `{"action": "list"}`,
+			code:        400,
+			contains:    "invalid action",
+		},
+		{
+			description: "unknown topic",
+			message:     `{"action": "subscribe", "topic": "foo"}`,
+			code:        400,
+			contains:    "unknown topic",
+		},
+		{
+			description: "invalid arguments",
+			message:     `{"action": "subscribe", "topic": "events", "arguments": {"start_height": "sealed"}}`,
+			code:        400,
+			contains:    "invalid start height",
+		},
+		{
+			description: "invalid event filter",
+			message:     `{"action": "subscribe", "topic": "events", "arguments": {"event_types": ["foo"]}}`,
+			code:        400,
+			contains:    "invalid event filter",
+		},
+		{
+			description: "unknown subscription",
+			message:     `{"action": "unsubscribe", "subscription_id": "foo"}`,
+			code:        404,
+			contains:    "subscription not found",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(test.message)))
+
+			resp := readWebsocketResponse(t, conn)
+			require.NotNil(t, resp.Error)
+			assert.Equal(t, test.code, resp.Error.Code)
+			assert.Contains(t, resp.Error.Message, test.contains)
+		})
+	}
+
+	t.Run("max subscriptions per connection", func(t *testing.T) {
+		msg := `{"action": "subscribe", "topic": "block_headers"}`
+
+		require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(msg)))
+		resp := readWebsocketResponse(t, conn)
+		require.Nil(t, resp.Error)
+
+		require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(msg)))
+		resp = readWebsocketResponse(t, conn)
+		require.NotNil(t, resp.Error)
+		assert.Equal(t, int32(429), resp.Error.Code)
+	})
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/flow/filter"
 	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/execution"
 	"github.com/onflow/flow-go/state/protocol"
 	"github.com/onflow/flow-go/storage"
 )
@@ -183,6 +184,92 @@ func New(
 	return b
 }
 
+// SetEventsIndex configures the backend to serve events for heights which are indexed locally
+// from the given events index. Events for all other heights are still requested from execution
+// nodes. Must be called before the backend starts serving requests.
+func (b *Backend) SetEventsIndex(eventsIndex EventsIndex) {
+	b.backendEvents.eventsIndex = eventsIndex
+}
+
+// SetScriptExecutor configures the backend to execute scripts locally with the given script
+// executor, according to the given mode. Account balances and keys are read locally according to
+// the same mode, and transactions are simulated locally. Must be called before the backend starts
+// serving requests.
+func (b *Backend) SetScriptExecutor(scriptExecutor execution.ScriptExecutor, mode ScriptExecutionMode) {
+	b.backendScripts.scriptExecutor = scriptExecutor
+	b.backendScripts.scriptExecMode = mode
+	b.backendAccounts.scriptExecutor = scriptExecutor
+	b.backendAccounts.scriptExecMode = mode
+	b.backendTransactions.scriptExecutor = scriptExecutor
+}
+
+// SetTransactionRetryStorage configures the backend to persist the transactions pending in the
+// retry mechanism in the given storage, and resumes retrying the transactions persisted previously.
+// Must be called before the backend starts serving requests.
+// No errors are expected during normal operations.
+func (b *Backend) SetTransactionRetryStorage(store storage.TransactionRetries) error {
+	return b.retry.SetStorage(store)
+}
+
+// TransactionRetry returns the retry mechanism for submitted transactions.
+func (b *Backend) TransactionRetry() *Retry {
+	return b.retry
+}
+
+// SetExecutionNodeSelector configures the backend to choose the execution nodes requests are sent
+// to by their health, as tracked by the given selector, instead of randomly. Must be called before
+// the backend starts serving requests.
+func (b *Backend) SetExecutionNodeSelector(selector *ExecutionNodeSelector) {
+	b.backendScripts.nodeSelector = selector
+	b.backendAccounts.nodeSelector = selector
+	b.backendEvents.nodeSelector = selector
+	b.backendTransactions.nodeSelector = selector
+}
+
+// SetScriptResultCache configures the backend to cache the results of scripts executed against
+// sealed blocks in the given cache. Must be called before the backend starts serving requests.
+func (b *Backend) SetScriptResultCache(cache *ScriptResultCache) {
+	b.backendScripts.resultCache = cache
+}
+
+// NotifyExecutedHeight reports the height of a block the given execution node sent a receipt for.
+func (b *Backend) NotifyExecutedHeight(executorID flow.Identifier, height uint64) {
+	if b.backendScripts.nodeSelector != nil {
+		b.backendScripts.nodeSelector.ReportExecutedHeight(executorID, height)
+	}
+}
+
+// TransactionStateChecks configures the optional transaction validation checks which depend on the
+// execution state at the latest sealed block.
+type TransactionStateChecks struct {
+	// CheckSignatures enables verifying transaction signatures against the account keys.
+	CheckSignatures bool
+	// CheckPayerBalance enables checking that the payer can pay for the transaction.
+	CheckPayerBalance bool
+	// DryRun only logs and counts transactions failing the checks, without rejecting them.
+	DryRun bool
+}
+
+// SetTransactionStateChecks enables the given transaction validation checks which depend on the
+// execution state. Account keys are read and the payer balance check script is executed the same
+// way as for the Access API, from execution nodes or the local register index depending on the
+// script execution mode. Must be called before the backend starts serving requests.
+func (b *Backend) SetTransactionStateChecks(checks TransactionStateChecks, metrics module.TransactionValidationMetrics) {
+	options := defaultTransactionValidationOptions()
+	options.CheckSignatures = checks.CheckSignatures
+	options.CheckPayerBalance = checks.CheckPayerBalance
+	options.StateChecksDryRun = checks.DryRun
+
+	b.backendTransactions.transactionValidator = access.NewTransactionValidator(
+		access.NewProtocolStateBlocks(b.state),
+		b.chainID.Chain(),
+		options,
+		access.WithSealedState(b),
+		access.WithValidationMetrics(metrics),
+		access.WithValidationLogger(b.backendTransactions.log),
+	)
+}
+
 func identifierList(ids []string) (flow.IdentifierList, error) {
 	idList := make(flow.IdentifierList, len(ids))
 	for i, idStr := range ids {
@@ -199,19 +286,23 @@ func configureTransactionValidator(state protocol.State, chainID flow.ChainID) *
 	return access.NewTransactionValidator(
 		access.NewProtocolStateBlocks(state),
 		chainID.Chain(),
-		access.TransactionValidationOptions{
-			Expiry:                       flow.DefaultTransactionExpiry,
-			ExpiryBuffer:                 flow.DefaultTransactionExpiryBuffer,
-			AllowEmptyReferenceBlockID:   false,
-			AllowUnknownReferenceBlockID: false,
-			CheckScriptsParse:            false,
-			MaxGasLimit:                  flow.DefaultMaxTransactionGasLimit,
-			MaxTransactionByteSize:       flow.DefaultMaxTransactionByteSize,
-			MaxCollectionByteSize:        flow.DefaultMaxCollectionByteSize,
-		},
+		defaultTransactionValidationOptions(),
 	)
 }
 
+func defaultTransactionValidationOptions() access.TransactionValidationOptions {
+	return access.TransactionValidationOptions{
+		Expiry:                       flow.DefaultTransactionExpiry,
+		ExpiryBuffer:                 flow.DefaultTransactionExpiryBuffer,
+		AllowEmptyReferenceBlockID:   false,
+		AllowUnknownReferenceBlockID: false,
+		CheckScriptsParse:            false,
+		MaxGasLimit:                  flow.DefaultMaxTransactionGasLimit,
+		MaxTransactionByteSize:       flow.DefaultMaxTransactionByteSize,
+		MaxCollectionByteSize:        flow.DefaultMaxCollectionByteSize,
+	}
+}
+
 // Ping responds to requests when the server is up.
 func (b *Backend) Ping(ctx context.Context) error {
 
@@ -259,14 +350,16 @@ func (b *Backend) GetLatestProtocolStateSnapshot(_ context.Context) ([]byte, err
 	return convert.SnapshotToBytes(validSnapshot)
 }
 
-// executionNodesForBlockID returns upto maxExecutionNodesCnt number of randomly chosen execution node identities
-// which have executed the given block ID.
+// executionNodesForBlockID returns upto maxExecutionNodesCnt number of execution node identities
+// which have executed the given block ID. If a selector is given, the healthiest execution nodes are
+// chosen, ordered by health, otherwise they are chosen randomly.
 // If no such execution node is found, an InsufficientExecutionReceipts error is returned.
 func executionNodesForBlockID(
 	ctx context.Context,
 	blockID flow.Identifier,
 	executionReceipts storage.ExecutionReceipts,
 	state protocol.State,
+	selector *ExecutionNodeSelector,
 	log zerolog.Logger) (flow.IdentityList, error) {
 
 	var executorIDs flow.IdentifierList
@@ -330,14 +423,20 @@ func executionNodesForBlockID(
 		return nil, fmt.Errorf("failed to retreive execution IDs for block ID %v: %w", blockID, err)
 	}
 
-	// randomly choose upto maxExecutionNodesCnt identities
-	executionIdentitiesRandom := subsetENs.Sample(maxExecutionNodesCnt)
+	var executionIdentities flow.IdentityList
+	if selector != nil {
+		// choose upto maxExecutionNodesCnt of the healthiest identities
+		executionIdentities = selector.Select(subsetENs, maxExecutionNodesCnt)
+	} else {
+		// randomly choose upto maxExecutionNodesCnt identities
+		executionIdentities = subsetENs.Sample(maxExecutionNodesCnt)
+	}
 
-	if len(executionIdentitiesRandom) == 0 {
+	if len(executionIdentities) == 0 {
 		return nil, fmt.Errorf("no matching execution node found for block ID %v", blockID)
 	}
 
-	return executionIdentitiesRandom, nil
+	return executionIdentities, nil
 }
 
 // findAllExecutionNodes find all the execution nodes ids from the execution receipts that have been received for the
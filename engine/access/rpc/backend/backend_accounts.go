@@ -2,9 +2,9 @@ package backend
 
 import (
 	"context"
+	"errors"
 	"time"
 
-	"github.com/hashicorp/go-multierror"
 	execproto "github.com/onflow/flow/protobuf/go/flow/execution"
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc/codes"
@@ -12,7 +12,10 @@ import (
 
 	"github.com/onflow/flow-go/engine/common/rpc"
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	"github.com/onflow/flow-go/engine/common/rpc/extended"
+	fvmerrors "github.com/onflow/flow-go/fvm/errors"
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/execution"
 	"github.com/onflow/flow-go/state/protocol"
 	"github.com/onflow/flow-go/storage"
 )
@@ -23,6 +26,9 @@ type backendAccounts struct {
 	executionReceipts storage.ExecutionReceipts
 	connFactory       ConnectionFactory
 	log               zerolog.Logger
+	scriptExecutor    execution.ScriptExecutor
+	scriptExecMode    ScriptExecutionMode
+	nodeSelector      *ExecutionNodeSelector // optional, execution nodes are chosen randomly if nil
 }
 
 func (b *backendAccounts) GetAccount(ctx context.Context, address flow.Address) (*flow.Account, error) {
@@ -71,6 +77,364 @@ func (b *backendAccounts) GetAccountAtBlockHeight(
 	return account, nil
 }
 
+// GetAccountBalanceAtLatestBlock returns the balance of the account at the latest sealed block.
+func (b *backendAccounts) GetAccountBalanceAtLatestBlock(ctx context.Context, address flow.Address) (uint64, error) {
+	header, err := b.state.Sealed().Head()
+	if err != nil {
+		return 0, status.Errorf(codes.Internal, "failed to get latest sealed header: %v", err)
+	}
+
+	return b.getAccountBalance(ctx, address, header)
+}
+
+// GetAccountBalanceAtBlockHeight returns the balance of the account at the given height.
+func (b *backendAccounts) GetAccountBalanceAtBlockHeight(
+	ctx context.Context,
+	address flow.Address,
+	height uint64,
+) (uint64, error) {
+	header, err := b.headers.ByHeight(height)
+	if err != nil {
+		return 0, rpc.ConvertStorageError(err)
+	}
+
+	return b.getAccountBalance(ctx, address, header)
+}
+
+// GetAccountKeyAtLatestBlock returns the public key with the given index of the account at the
+// latest sealed block.
+func (b *backendAccounts) GetAccountKeyAtLatestBlock(
+	ctx context.Context,
+	address flow.Address,
+	keyIndex uint32,
+) (*flow.AccountPublicKey, error) {
+	header, err := b.state.Sealed().Head()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get latest sealed header: %v", err)
+	}
+
+	return b.getAccountKey(ctx, address, keyIndex, header)
+}
+
+// GetAccountKeyAtBlockHeight returns the public key with the given index of the account at the
+// given height.
+func (b *backendAccounts) GetAccountKeyAtBlockHeight(
+	ctx context.Context,
+	address flow.Address,
+	keyIndex uint32,
+	height uint64,
+) (*flow.AccountPublicKey, error) {
+	header, err := b.headers.ByHeight(height)
+	if err != nil {
+		return nil, rpc.ConvertStorageError(err)
+	}
+
+	return b.getAccountKey(ctx, address, keyIndex, header)
+}
+
+// GetAccountKeysAtLatestBlock returns the public keys of the account at the latest sealed block.
+func (b *backendAccounts) GetAccountKeysAtLatestBlock(
+	ctx context.Context,
+	address flow.Address,
+) ([]flow.AccountPublicKey, error) {
+	header, err := b.state.Sealed().Head()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get latest sealed header: %v", err)
+	}
+
+	return b.getAccountKeys(ctx, address, header)
+}
+
+// GetAccountKeysAtBlockHeight returns the public keys of the account at the given height.
+func (b *backendAccounts) GetAccountKeysAtBlockHeight(
+	ctx context.Context,
+	address flow.Address,
+	height uint64,
+) ([]flow.AccountPublicKey, error) {
+	header, err := b.headers.ByHeight(height)
+	if err != nil {
+		return nil, rpc.ConvertStorageError(err)
+	}
+
+	return b.getAccountKeys(ctx, address, header)
+}
+
+// getAccountBalance returns the balance of the account at the given block. Only the registers
+// required to compute the balance are read, from the local register index if local script
+// execution is enabled, otherwise by execution nodes.
+func (b *backendAccounts) getAccountBalance(
+	ctx context.Context,
+	address flow.Address,
+	header *flow.Header,
+) (uint64, error) {
+	if b.readAccountsLocally() {
+		balance, err := b.getAccountBalanceLocally(ctx, address, header)
+		if err == nil || b.scriptExecMode == ScriptExecutionModeLocalOnly {
+			return balance, err
+		}
+		b.logLocalAccountFailure(err, address, header)
+	}
+
+	blockID := header.ID()
+	resp, err := getAccountDataFromAnyExeNode(ctx, b, address, blockID,
+		func(ctx context.Context, client extended.ExtendedExecutionAPIClient) (*extended.AccountBalanceResponse, error) {
+			return client.GetAccountBalanceAtBlockID(ctx, &extended.GetAccountBalanceAtBlockIDRequest{
+				Address: address.Bytes(),
+				BlockId: blockID[:],
+			})
+		},
+	)
+	if status.Code(err) == codes.Unimplemented {
+		account, err := b.getAccountAtBlockID(ctx, address, blockID)
+		if err != nil {
+			return 0, err
+		}
+		return account.Balance, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return resp.GetBalance(), nil
+}
+
+// getAccountKey returns the public key with the given index of the account at the given block.
+// Only the register storing the key is read, from the local register index if local script
+// execution is enabled, otherwise by execution nodes.
+func (b *backendAccounts) getAccountKey(
+	ctx context.Context,
+	address flow.Address,
+	keyIndex uint32,
+	header *flow.Header,
+) (*flow.AccountPublicKey, error) {
+	if b.readAccountsLocally() {
+		key, err := b.getAccountKeyLocally(ctx, address, keyIndex, header)
+		if err == nil || b.scriptExecMode == ScriptExecutionModeLocalOnly {
+			return key, err
+		}
+		b.logLocalAccountFailure(err, address, header)
+	}
+
+	blockID := header.ID()
+	resp, err := getAccountDataFromAnyExeNode(ctx, b, address, blockID,
+		func(ctx context.Context, client extended.ExtendedExecutionAPIClient) (*extended.AccountKeyResponse, error) {
+			return client.GetAccountKeyAtBlockID(ctx, &extended.GetAccountKeyAtBlockIDRequest{
+				Address: address.Bytes(),
+				Index:   keyIndex,
+				BlockId: blockID[:],
+			})
+		},
+	)
+	if status.Code(err) == codes.Unimplemented {
+		return b.getAccountKeyFromAccount(ctx, address, keyIndex, blockID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := convert.MessageToAccountKey(resp.GetAccountKey())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to convert account key message: %v", err)
+	}
+
+	return key, nil
+}
+
+// getAccountKeyFromAccount returns the public key with the given index of the account at the given
+// block, taken from the whole account returned by execution nodes.
+func (b *backendAccounts) getAccountKeyFromAccount(
+	ctx context.Context,
+	address flow.Address,
+	keyIndex uint32,
+	blockID flow.Identifier,
+) (*flow.AccountPublicKey, error) {
+	account, err := b.getAccountAtBlockID(ctx, address, blockID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range account.Keys {
+		if key.Index == int(keyIndex) {
+			return &key, nil
+		}
+	}
+
+	return nil, status.Errorf(codes.NotFound, "failed to find key %d of account %s", keyIndex, address)
+}
+
+// getAccountKeys returns the public keys of the account at the given block. Only the registers
+// storing the keys are read, from the local register index if local script execution is enabled,
+// otherwise by execution nodes.
+func (b *backendAccounts) getAccountKeys(
+	ctx context.Context,
+	address flow.Address,
+	header *flow.Header,
+) ([]flow.AccountPublicKey, error) {
+	if b.readAccountsLocally() {
+		keys, err := b.getAccountKeysLocally(ctx, address, header)
+		if err == nil || b.scriptExecMode == ScriptExecutionModeLocalOnly {
+			return keys, err
+		}
+		b.logLocalAccountFailure(err, address, header)
+	}
+
+	blockID := header.ID()
+	resp, err := getAccountDataFromAnyExeNode(ctx, b, address, blockID,
+		func(ctx context.Context, client extended.ExtendedExecutionAPIClient) (*extended.AccountKeysResponse, error) {
+			return client.GetAccountKeysAtBlockID(ctx, &extended.GetAccountKeysAtBlockIDRequest{
+				Address: address.Bytes(),
+				BlockId: blockID[:],
+			})
+		},
+	)
+	if status.Code(err) == codes.Unimplemented {
+		account, err := b.getAccountAtBlockID(ctx, address, blockID)
+		if err != nil {
+			return nil, err
+		}
+		return account.Keys, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]flow.AccountPublicKey, len(resp.GetAccountKeys()))
+	for i, keyMsg := range resp.GetAccountKeys() {
+		key, err := convert.MessageToAccountKey(keyMsg)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to convert account key message: %v", err)
+		}
+		keys[i] = *key
+	}
+
+	return keys, nil
+}
+
+// readAccountsLocally returns true if account data is read from the local register index.
+// In compare mode, account data is served by execution nodes only.
+func (b *backendAccounts) readAccountsLocally() bool {
+	return b.scriptExecMode == ScriptExecutionModeLocalOnly || b.scriptExecMode == ScriptExecutionModeFailover
+}
+
+func (b *backendAccounts) getAccountBalanceLocally(
+	ctx context.Context,
+	address flow.Address,
+	header *flow.Header,
+) (uint64, error) {
+	if b.scriptExecutor == nil {
+		return 0, status.Errorf(codes.Unimplemented, "local script execution is not enabled")
+	}
+
+	balance, err := b.scriptExecutor.GetAccountBalance(ctx, address, header.Height)
+	if err != nil {
+		return 0, convertLocalAccountError(err, "failed to get account balance locally")
+	}
+
+	return balance, nil
+}
+
+func (b *backendAccounts) getAccountKeyLocally(
+	ctx context.Context,
+	address flow.Address,
+	keyIndex uint32,
+	header *flow.Header,
+) (*flow.AccountPublicKey, error) {
+	if b.scriptExecutor == nil {
+		return nil, status.Errorf(codes.Unimplemented, "local script execution is not enabled")
+	}
+
+	key, err := b.scriptExecutor.GetAccountKey(ctx, address, uint64(keyIndex), header.Height)
+	if err != nil {
+		return nil, convertLocalAccountError(err, "failed to get account key locally")
+	}
+
+	return key, nil
+}
+
+func (b *backendAccounts) getAccountKeysLocally(
+	ctx context.Context,
+	address flow.Address,
+	header *flow.Header,
+) ([]flow.AccountPublicKey, error) {
+	if b.scriptExecutor == nil {
+		return nil, status.Errorf(codes.Unimplemented, "local script execution is not enabled")
+	}
+
+	keys, err := b.scriptExecutor.GetAccountKeys(ctx, address, header.Height)
+	if err != nil {
+		return nil, convertLocalAccountError(err, "failed to get account keys locally")
+	}
+
+	return keys, nil
+}
+
+// logLocalAccountFailure logs that account data could not be read locally, before falling back
+// to execution nodes.
+func (b *backendAccounts) logLocalAccountFailure(err error, address flow.Address, header *flow.Header) {
+	blockID := header.ID()
+	b.log.Debug().Err(err).
+		Hex("block_id", blockID[:]).
+		Str("address", address.String()).
+		Msg("failed to read account locally, falling back to execution nodes")
+}
+
+// getAccountDataFromAnyExeNode reads parts of the account at the given block through the extended
+// Execution API of the execution nodes holding the state of the block, until one of them responds.
+// If none of the execution nodes serve the extended Execution API, the returned error has the
+// codes.Unimplemented code, so that the whole account can be requested instead.
+func getAccountDataFromAnyExeNode[T any](
+	ctx context.Context,
+	b *backendAccounts,
+	address flow.Address,
+	blockID flow.Identifier,
+	request func(context.Context, extended.ExtendedExecutionAPIClient) (T, error),
+) (T, error) {
+	var zero T
+
+	execNodes, err := executionNodesForBlockID(ctx, blockID, b.executionReceipts, b.state, b.nodeSelector, b.log)
+	if err != nil {
+		return zero, rpc.ConvertError(err, "failed to get account from the execution node", codes.Internal)
+	}
+
+	resp, _, err := executeHedged(ctx, b.nodeSelector, execNodes,
+		func(ctx context.Context, execNode *flow.Identity) (T, error) {
+			start := time.Now()
+
+			resp, err := tryExtendedExecutionAPI(ctx, b.connFactory, execNode, request)
+			if err != nil && status.Code(err) != codes.Canceled {
+				b.log.Error().
+					Str("execution_node", execNode.String()).
+					Hex("block_id", blockID[:]).
+					Str("address", address.String()).
+					Int64("rtt_ms", time.Since(start).Milliseconds()).
+					Err(err).
+					Msg("failed to get account data")
+			}
+			return resp, err
+		},
+		// any EN failing to return the account may be faulty, so try all of them
+		func(error) bool { return false },
+	)
+	if err != nil {
+		return zero, rpc.ConvertError(err, "failed to get account from the execution node", codes.Internal)
+	}
+
+	return resp, nil
+}
+
+// convertLocalAccountError converts an error returned when reading account data from the local
+// register index into a grpc status error.
+func convertLocalAccountError(err error, msg string) error {
+	switch {
+	case errors.Is(err, execution.ErrDataNotAvailable):
+		return status.Errorf(codes.OutOfRange, "%s: %v", msg, err)
+	case fvmerrors.IsAccountNotFoundError(err), fvmerrors.IsAccountPublicKeyNotFoundError(err):
+		return status.Errorf(codes.NotFound, "%s: %v", msg, err)
+	default:
+		return status.Errorf(codes.Internal, "%s: %v", msg, err)
+	}
+}
+
 func (b *backendAccounts) getAccountAtBlockID(
 	ctx context.Context,
 	address flow.Address,
@@ -82,7 +446,7 @@ func (b *backendAccounts) getAccountAtBlockID(
 		BlockId: blockID[:],
 	}
 
-	execNodes, err := executionNodesForBlockID(ctx, blockID, b.executionReceipts, b.state, b.log)
+	execNodes, err := executionNodesForBlockID(ctx, blockID, b.executionReceipts, b.state, b.nodeSelector, b.log)
 	if err != nil {
 		return nil, rpc.ConvertError(err, "failed to get account from the execution node", codes.Internal)
 	}
@@ -103,38 +467,43 @@ func (b *backendAccounts) getAccountAtBlockID(
 }
 
 // getAccountFromAnyExeNode retrieves the given account from any EN in `execNodes`.
-// We attempt querying each EN in sequence. If any EN returns a valid response, then errors from
+// We attempt querying each EN in sequence, or if hedging is enabled, also query the next EN if the
+// previous one does not respond in time. If any EN returns a valid response, then errors from
 // other ENs are logged and swallowed. If all ENs fail to return a valid response, then an
 // error aggregating all failures is returned.
 func (b *backendAccounts) getAccountFromAnyExeNode(ctx context.Context, execNodes flow.IdentityList, req *execproto.GetAccountAtBlockIDRequest) (*execproto.GetAccountAtBlockIDResponse, error) {
-	var errors *multierror.Error
-	for _, execNode := range execNodes {
-		// TODO: use the GRPC Client interceptor
-		start := time.Now()
-
-		resp, err := b.tryGetAccount(ctx, execNode, req)
-		duration := time.Since(start)
-		if err == nil {
-			// return if any execution node replied successfully
-			b.log.Debug().
-				Str("execution_node", execNode.String()).
-				Hex("block_id", req.GetBlockId()).
-				Hex("address", req.GetAddress()).
-				Int64("rtt_ms", duration.Milliseconds()).
-				Msg("Successfully got account info")
-			return resp, nil
-		}
-		b.log.Error().
-			Str("execution_node", execNode.String()).
-			Hex("block_id", req.GetBlockId()).
-			Hex("address", req.GetAddress()).
-			Int64("rtt_ms", duration.Milliseconds()).
-			Err(err).
-			Msg("failed to execute GetAccount")
-		errors = multierror.Append(errors, err)
-	}
+	resp, _, err := executeHedged(ctx, b.nodeSelector, execNodes,
+		func(ctx context.Context, execNode *flow.Identity) (*execproto.GetAccountAtBlockIDResponse, error) {
+			// TODO: use the GRPC Client interceptor
+			start := time.Now()
+
+			resp, err := b.tryGetAccount(ctx, execNode, req)
+			duration := time.Since(start)
+			if err == nil {
+				b.log.Debug().
+					Str("execution_node", execNode.String()).
+					Hex("block_id", req.GetBlockId()).
+					Hex("address", req.GetAddress()).
+					Int64("rtt_ms", duration.Milliseconds()).
+					Msg("Successfully got account info")
+				return resp, nil
+			}
+			if status.Code(err) != codes.Canceled {
+				b.log.Error().
+					Str("execution_node", execNode.String()).
+					Hex("block_id", req.GetBlockId()).
+					Hex("address", req.GetAddress()).
+					Int64("rtt_ms", duration.Milliseconds()).
+					Err(err).
+					Msg("failed to execute GetAccount")
+			}
+			return nil, err
+		},
+		// any EN failing to return the account may be faulty, so try all of them
+		func(error) bool { return false },
+	)
 
-	return nil, errors.ErrorOrNil()
+	return resp, err
 }
 
 func (b *backendAccounts) tryGetAccount(ctx context.Context, execNode *flow.Identity, req *execproto.GetAccountAtBlockIDRequest) (*execproto.GetAccountAtBlockIDResponse, error) {
@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
@@ -20,6 +21,17 @@ import (
 	"github.com/onflow/flow-go/storage"
 )
 
+// EventsIndex provides access to events which were indexed locally from execution data.
+type EventsIndex interface {
+	// IndexedHeightRange returns the range of consecutive heights for which events are indexed
+	// (inclusive on both ends). ok is false if no height was indexed yet.
+	IndexedHeightRange() (lowest uint64, highest uint64, ok bool)
+
+	// ByBlockIDEventType returns the indexed events of the given type for the given block.
+	// No errors are expected during normal operation.
+	ByBlockIDEventType(blockID flow.Identifier, eventType flow.EventType) ([]flow.Event, error)
+}
+
 type backendEvents struct {
 	headers           storage.Headers
 	executionReceipts storage.ExecutionReceipts
@@ -27,6 +39,8 @@ type backendEvents struct {
 	connFactory       ConnectionFactory
 	log               zerolog.Logger
 	maxHeightRange    uint
+	eventsIndex       EventsIndex            // optional, events are requested from execution nodes if nil
+	nodeSelector      *ExecutionNodeSelector // optional, execution nodes are chosen randomly if nil
 }
 
 // GetEventsForHeightRange retrieves events for all sealed blocks between the start block height and
@@ -76,7 +90,7 @@ func (b *backendEvents) GetEventsForHeightRange(
 		blockHeaders = append(blockHeaders, header)
 	}
 
-	return b.getBlockEventsFromExecutionNode(ctx, blockHeaders, eventType)
+	return b.getBlockEvents(ctx, blockHeaders, eventType)
 }
 
 // GetEventsForBlockIDs retrieves events for all the specified block IDs that have the given type
@@ -101,8 +115,92 @@ func (b *backendEvents) GetEventsForBlockIDs(
 		blockHeaders = append(blockHeaders, header)
 	}
 
-	// forward the request to the execution node
-	return b.getBlockEventsFromExecutionNode(ctx, blockHeaders, eventType)
+	return b.getBlockEvents(ctx, blockHeaders, eventType)
+}
+
+// getBlockEvents returns the events of the given type for the given blocks. Events for blocks
+// which are indexed locally are read from the events index, all other blocks are forwarded to
+// the execution nodes. Results are returned in the same order as the provided headers.
+func (b *backendEvents) getBlockEvents(
+	ctx context.Context,
+	blockHeaders []*flow.Header,
+	eventType string,
+) ([]flow.BlockEvents, error) {
+	if b.eventsIndex == nil {
+		return b.getBlockEventsFromExecutionNode(ctx, blockHeaders, eventType)
+	}
+
+	lowest, highest, ok := b.eventsIndex.IndexedHeightRange()
+	if !ok {
+		return b.getBlockEventsFromExecutionNode(ctx, blockHeaders, eventType)
+	}
+
+	results := make([]flow.BlockEvents, len(blockHeaders))
+	missingHeaders := make([]*flow.Header, 0)
+	missingPositions := make([]int, 0)
+
+	for i, header := range blockHeaders {
+		if header.Height < lowest || header.Height > highest {
+			missingHeaders = append(missingHeaders, header)
+			missingPositions = append(missingPositions, i)
+			continue
+		}
+
+		blockID := header.ID()
+		events, err := b.eventsIndex.ByBlockIDEventType(blockID, flow.EventType(eventType))
+		if err != nil {
+			return nil, rpc.ConvertStorageError(fmt.Errorf("failed to get events from local index: %w", err))
+		}
+
+		if events == nil {
+			events = []flow.Event{}
+		}
+
+		// events are returned in execution order, matching the results from execution nodes
+		sort.Slice(events, func(x, y int) bool {
+			if events[x].TransactionIndex != events[y].TransactionIndex {
+				return events[x].TransactionIndex < events[y].TransactionIndex
+			}
+			return events[x].EventIndex < events[y].EventIndex
+		})
+
+		results[i] = flow.BlockEvents{
+			BlockID:        blockID,
+			BlockHeight:    header.Height,
+			BlockTimestamp: header.Timestamp,
+			Events:         events,
+		}
+	}
+
+	if len(missingHeaders) == 0 {
+		return results, nil
+	}
+
+	b.log.Trace().
+		Int("indexed_blocks", len(blockHeaders)-len(missingHeaders)).
+		Int("execution_node_blocks", len(missingHeaders)).
+		Msg("requesting events for blocks which are not indexed from execution nodes")
+
+	enResults, err := b.getBlockEventsFromExecutionNode(ctx, missingHeaders, eventType)
+	if err != nil {
+		return nil, err
+	}
+
+	// verifyAndConvertToAccessEvents returns results in the order provided by the execution node,
+	// so they are matched to the requested blocks by ID
+	enResultsByID := make(map[flow.Identifier]flow.BlockEvents, len(enResults))
+	for _, result := range enResults {
+		enResultsByID[result.BlockID] = result
+	}
+	for i, header := range missingHeaders {
+		result, ok := enResultsByID[header.ID()]
+		if !ok {
+			return nil, status.Errorf(codes.Internal, "missing events for block %v from execution node", header.ID())
+		}
+		results[missingPositions[i]] = result
+	}
+
+	return results, nil
 }
 
 func (b *backendEvents) getBlockEventsFromExecutionNode(
@@ -129,7 +227,7 @@ func (b *backendEvents) getBlockEventsFromExecutionNode(
 	// choose the last block ID to find the list of execution nodes
 	lastBlockID := blockIDs[len(blockIDs)-1]
 
-	execNodes, err := executionNodesForBlockID(ctx, lastBlockID, b.executionReceipts, b.state, b.log)
+	execNodes, err := executionNodesForBlockID(ctx, lastBlockID, b.executionReceipts, b.state, b.nodeSelector, b.log)
 	if err != nil {
 		b.log.Error().Err(err).Msg("failed to retrieve events from execution node")
 		return nil, rpc.ConvertError(err, "failed to retrieve events from execution node", codes.Internal)
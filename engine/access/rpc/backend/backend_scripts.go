@@ -1,21 +1,26 @@
 package backend
 
 import (
+	"bytes"
 	"context"
 	"crypto/md5" //nolint:gosec
+	"errors"
+	"fmt"
 	"time"
 
 	lru "github.com/hashicorp/golang-lru"
 
-	"github.com/hashicorp/go-multierror"
 	execproto "github.com/onflow/flow/protobuf/go/flow/execution"
 	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
 	"github.com/onflow/flow-go/engine/common/rpc"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/execution"
 	"github.com/onflow/flow-go/state/protocol"
 	"github.com/onflow/flow-go/storage"
 )
@@ -23,6 +28,57 @@ import (
 // uniqueScriptLoggingTimeWindow is the duration for checking the uniqueness of scripts sent for execution
 const uniqueScriptLoggingTimeWindow = 10 * time.Minute
 
+// ScriptExecutionMode determines where scripts are executed.
+type ScriptExecutionMode int
+
+const (
+	// ScriptExecutionModeExecutionNodesOnly executes scripts on execution nodes only.
+	ScriptExecutionModeExecutionNodesOnly ScriptExecutionMode = iota
+
+	// ScriptExecutionModeLocalOnly executes scripts locally only, against the register index.
+	// Scripts for heights which are not indexed fail.
+	ScriptExecutionModeLocalOnly
+
+	// ScriptExecutionModeFailover executes scripts locally, and falls back to execution nodes if
+	// local execution fails, e.g. because the height is not indexed.
+	ScriptExecutionModeFailover
+
+	// ScriptExecutionModeCompare executes scripts both on execution nodes and locally, and logs
+	// any differences. Results from execution nodes are returned.
+	ScriptExecutionModeCompare
+)
+
+// ParseScriptExecutionMode parses the string representation of a ScriptExecutionMode.
+func ParseScriptExecutionMode(s string) (ScriptExecutionMode, error) {
+	switch s {
+	case ScriptExecutionModeExecutionNodesOnly.String():
+		return ScriptExecutionModeExecutionNodesOnly, nil
+	case ScriptExecutionModeLocalOnly.String():
+		return ScriptExecutionModeLocalOnly, nil
+	case ScriptExecutionModeFailover.String():
+		return ScriptExecutionModeFailover, nil
+	case ScriptExecutionModeCompare.String():
+		return ScriptExecutionModeCompare, nil
+	default:
+		return 0, fmt.Errorf("invalid script execution mode: %s", s)
+	}
+}
+
+func (m ScriptExecutionMode) String() string {
+	switch m {
+	case ScriptExecutionModeExecutionNodesOnly:
+		return "execution-nodes-only"
+	case ScriptExecutionModeLocalOnly:
+		return "local-only"
+	case ScriptExecutionModeFailover:
+		return "failover"
+	case ScriptExecutionModeCompare:
+		return "compare"
+	default:
+		return ""
+	}
+}
+
 type backendScripts struct {
 	headers           storage.Headers
 	executionReceipts storage.ExecutionReceipts
@@ -31,6 +87,10 @@ type backendScripts struct {
 	log               zerolog.Logger
 	metrics           module.BackendScriptsMetrics
 	loggedScripts     *lru.Cache
+	scriptExecutor    execution.ScriptExecutor // optional, only required for modes executing scripts locally
+	scriptExecMode    ScriptExecutionMode
+	nodeSelector      *ExecutionNodeSelector // optional, execution nodes are chosen randomly if nil
+	resultCache       *ScriptResultCache     // optional, results are not cached if nil
 }
 
 func (b *backendScripts) ExecuteScriptAtLatestBlock(
@@ -45,11 +105,7 @@ func (b *backendScripts) ExecuteScriptAtLatestBlock(
 		return nil, status.Errorf(codes.Internal, "failed to get latest sealed header: %v", err)
 	}
 
-	// get the block id of the latest sealed header
-	latestBlockID := latestHeader.ID()
-
-	// execute script on the execution node at that block id
-	return b.executeScriptOnExecutionNode(ctx, latestBlockID, script, arguments)
+	return b.executeScript(ctx, latestHeader, script, arguments)
 }
 
 func (b *backendScripts) ExecuteScriptAtBlockID(
@@ -58,8 +114,22 @@ func (b *backendScripts) ExecuteScriptAtBlockID(
 	script []byte,
 	arguments [][]byte,
 ) ([]byte, error) {
-	// execute script on the execution node at that block id
-	return b.executeScriptOnExecutionNode(ctx, blockID, script, arguments)
+	if b.scriptExecMode == ScriptExecutionModeExecutionNodesOnly && b.resultCache == nil {
+		// avoid the header lookup, which is only needed to execute scripts locally or cache results
+		result, usage, err := b.executeScriptOnExecutionNode(ctx, blockID, script, arguments, rpc.ScriptUsageRequested(ctx))
+		if err != nil {
+			return nil, err
+		}
+		b.sendScriptUsage(ctx, usage)
+		return result, nil
+	}
+
+	header, err := b.headers.ByBlockID(blockID)
+	if err != nil {
+		return nil, rpc.ConvertStorageError(err)
+	}
+
+	return b.executeScript(ctx, header, script, arguments)
 }
 
 func (b *backendScripts) ExecuteScriptAtBlockHeight(
@@ -75,20 +145,204 @@ func (b *backendScripts) ExecuteScriptAtBlockHeight(
 		return nil, err
 	}
 
+	return b.executeScript(ctx, header, script, arguments)
+}
+
+// executeScript executes the script at the given block, either locally or on execution nodes
+// depending on the configured script execution mode. If a result cache is configured, results of
+// scripts executed at sealed blocks are cached, unless the computation used by the script shows it
+// is not deterministic. If the client requested the computation and memory used by the script,
+// they are sent in the response trailer, and the cache is bypassed.
+func (b *backendScripts) executeScript(
+	ctx context.Context,
+	header *flow.Header,
+	script []byte,
+	arguments [][]byte,
+) ([]byte, error) {
 	blockID := header.ID()
+	// results at unsealed blocks may change, e.g. if the execution result turns out to be wrong
+	cacheable := b.resultCache != nil && b.isSealed(header)
+	usageRequested := rpc.ScriptUsageRequested(ctx)
+	if cacheable && !usageRequested {
+		if result, ok := b.resultCache.Get(blockID, script, arguments); ok {
+			return result, nil
+		}
+	}
+
+	// the usage is also needed to tell whether the result can be cached
+	result, usage, err := b.executeScriptWithMode(ctx, header, script, arguments, usageRequested || cacheable)
+	if err != nil {
+		return nil, err
+	}
+
+	if cacheable {
+		b.resultCache.Add(blockID, script, arguments, result, usage)
+	}
+	if usageRequested {
+		b.sendScriptUsage(ctx, usage)
+	}
+
+	return result, nil
+}
+
+// sendScriptUsage sends the computation and memory used by a script in the response trailer, if
+// there is a usage. The API messages have no fields for it. Requests which are not received over
+// gRPC, e.g. through the REST API, record the usage in the context instead.
+func (b *backendScripts) sendScriptUsage(ctx context.Context, usage metadata.MD) {
+	if usage == nil {
+		return
+	}
+	if rpc.RecordScriptUsage(ctx, usage) {
+		return
+	}
+	err := grpc.SetTrailer(ctx, usage)
+	if err != nil {
+		b.log.Debug().Err(err).Msg("failed to send script usage")
+	}
+}
 
-	// execute script on the execution node at that block id
-	return b.executeScriptOnExecutionNode(ctx, blockID, script, arguments)
+// isSealed returns whether the given block is sealed.
+func (b *backendScripts) isSealed(header *flow.Header) bool {
+	sealed, err := b.state.Sealed().Head()
+	if err != nil || header.Height > sealed.Height {
+		return false
+	}
+
+	// the block may be at a sealed height, yet on an abandoned fork
+	finalizedID, err := b.headers.BlockIDByHeight(header.Height)
+	if err != nil {
+		return false
+	}
+	return finalizedID == header.ID()
+}
+
+// executeScriptWithMode executes the script at the given block, either locally or on execution
+// nodes depending on the configured script execution mode. Returns the computation and memory used
+// by the script as a response trailer if requested, or nil otherwise.
+func (b *backendScripts) executeScriptWithMode(
+	ctx context.Context,
+	header *flow.Header,
+	script []byte,
+	arguments [][]byte,
+	requestUsage bool,
+) ([]byte, metadata.MD, error) {
+	blockID := header.ID()
+
+	switch b.scriptExecMode {
+	case ScriptExecutionModeLocalOnly:
+		return b.executeScriptLocally(ctx, header, script, arguments, requestUsage)
+
+	case ScriptExecutionModeFailover:
+		result, usage, err := b.executeScriptLocally(ctx, header, script, arguments, requestUsage)
+		if err == nil {
+			return result, usage, nil
+		}
+
+		// local execution may fail because of missing data or a local fault, in both cases the
+		// execution nodes are the source of truth
+		b.log.Debug().Err(err).
+			Hex("block_id", blockID[:]).
+			Msg("failed to execute script locally, falling back to execution nodes")
+
+		return b.executeScriptOnExecutionNode(ctx, blockID, script, arguments, requestUsage)
+
+	case ScriptExecutionModeCompare:
+		enResult, enUsage, enErr := b.executeScriptOnExecutionNode(ctx, blockID, script, arguments, requestUsage)
+		localResult, _, localErr := b.executeScriptLocally(ctx, header, script, arguments, false)
+		b.compareScriptResults(blockID, script, enResult, enErr, localResult, localErr)
+
+		return enResult, enUsage, enErr
+
+	default:
+		return b.executeScriptOnExecutionNode(ctx, blockID, script, arguments, requestUsage)
+	}
+}
+
+// executeScriptLocally executes the script against the locally indexed execution state.
+// Returns the computation and memory used by the script as a response trailer if requested, or nil
+// otherwise.
+func (b *backendScripts) executeScriptLocally(
+	ctx context.Context,
+	header *flow.Header,
+	script []byte,
+	arguments [][]byte,
+	requestUsage bool,
+) ([]byte, metadata.MD, error) {
+	if b.scriptExecutor == nil {
+		return nil, nil, status.Errorf(codes.Unimplemented, "local script execution is not enabled")
+	}
+
+	execStartTime := time.Now()
+	result, usage, err := b.scriptExecutor.ExecuteAtBlockHeight(ctx, script, arguments, header.Height)
+	if err != nil {
+		if errors.Is(err, execution.ErrDataNotAvailable) {
+			return nil, nil, status.Errorf(codes.OutOfRange, "failed to execute script locally: %v", err)
+		}
+		// consistent with execution nodes, which report all failures as invalid argument
+		return nil, nil, status.Errorf(codes.InvalidArgument, "failed to execute script locally: %v", err)
+	}
+
+	b.metrics.ScriptExecuted(
+		time.Since(execStartTime),
+		len(script),
+	)
+
+	if !requestUsage {
+		return result, nil, nil
+	}
+	return result, rpc.ScriptUsageTrailer(usage), nil
+}
+
+// compareScriptResults logs differences between the results of a script executed on the
+// execution nodes and locally.
+func (b *backendScripts) compareScriptResults(
+	blockID flow.Identifier,
+	script []byte,
+	enResult []byte,
+	enErr error,
+	localResult []byte,
+	localErr error,
+) {
+	// CAUTION: cryptographically insecure md5 is used here, but only to identify the script in logs.
+	insecureScriptHash := md5.Sum(script) //nolint:gosec
+
+	lg := b.log.With().
+		Hex("block_id", blockID[:]).
+		Hex("script_hash", insecureScriptHash[:]).
+		Logger()
+
+	switch {
+	case enErr != nil && localErr != nil:
+		lg.Debug().
+			AnErr("execution_node_error", enErr).
+			AnErr("local_error", localErr).
+			Msg("script failed on both execution nodes and locally")
+
+	case enErr != nil || localErr != nil:
+		lg.Warn().
+			AnErr("execution_node_error", enErr).
+			AnErr("local_error", localErr).
+			Msg("script execution outcome differs between execution nodes and local execution")
+
+	case !bytes.Equal(enResult, localResult):
+		lg.Warn().
+			Hex("execution_node_result", enResult).
+			Hex("local_result", localResult).
+			Msg("script result differs between execution nodes and local execution")
+	}
 }
 
 // executeScriptOnExecutionNode forwards the request to the execution node using the execution node
-// grpc client and converts the response back to the access node api response format
+// grpc client and converts the response back to the access node api response format.
+// Returns the computation and memory used by the script as a response trailer if requested, or nil
+// otherwise.
 func (b *backendScripts) executeScriptOnExecutionNode(
 	ctx context.Context,
 	blockID flow.Identifier,
 	script []byte,
 	arguments [][]byte,
-) ([]byte, error) {
+	requestUsage bool,
+) ([]byte, metadata.MD, error) {
 
 	execReq := &execproto.ExecuteScriptAtBlockIDRequest{
 		BlockId:   blockID[:],
@@ -97,62 +351,61 @@ func (b *backendScripts) executeScriptOnExecutionNode(
 	}
 
 	// find few execution nodes which have executed the block earlier and provided an execution receipt for it
-	execNodes, err := executionNodesForBlockID(ctx, blockID, b.executionReceipts, b.state, b.log)
+	execNodes, err := executionNodesForBlockID(ctx, blockID, b.executionReceipts, b.state, b.nodeSelector, b.log)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to find execution nodes at blockId %v: %v", blockID.String(), err)
+		return nil, nil, status.Errorf(codes.Internal, "failed to find execution nodes at blockId %v: %v", blockID.String(), err)
 	}
 	// encode to MD5 as low compute/memory lookup key
 	// CAUTION: cryptographically insecure md5 is used here, but only to de-duplicate logs.
 	// *DO NOT* use this hash for any protocol-related or cryptographic functions.
 	insecureScriptHash := md5.Sum(script) //nolint:gosec
 
-	// try each of the execution nodes found
-	var errors *multierror.Error
-	// try to execute the script on one of the execution nodes
-	for _, execNode := range execNodes {
-		execStartTime := time.Now() // record start time
-		result, err := b.tryExecuteScript(ctx, execNode, execReq)
-		if err == nil {
-			if b.log.GetLevel() == zerolog.DebugLevel {
-				executionTime := time.Now()
-				if b.shouldLogScript(executionTime, insecureScriptHash) {
-					b.log.Debug().
-						Str("execution_node", execNode.String()).
-						Hex("block_id", blockID[:]).
-						Hex("script_hash", insecureScriptHash[:]).
-						Str("script", string(script)).
-						Msg("Successfully executed script")
-					b.loggedScripts.Add(insecureScriptHash, executionTime)
-				}
+	// try to execute the script on one of the execution nodes found, hedging if enabled
+	response, execNode, err := executeHedged(ctx, b.nodeSelector, execNodes,
+		func(ctx context.Context, execNode *flow.Identity) (scriptResponse, error) {
+			execStartTime := time.Now() // record start time
+			response, err := b.tryExecuteScript(ctx, execNode, execReq, requestUsage)
+			if err == nil {
+				// log execution time
+				b.metrics.ScriptExecuted(
+					time.Since(execStartTime),
+					len(script),
+				)
 			}
-
-			// log execution time
-			b.metrics.ScriptExecuted(
-				time.Since(execStartTime),
-				len(script),
-			)
-
-			return result, nil
-		}
+			return response, err
+		},
 		// return if it's just a script failure as opposed to an EN failure and skip trying other ENs
-		if status.Code(err) == codes.InvalidArgument {
-			b.log.Debug().Err(err).
-				Str("execution_node", execNode.String()).
-				Hex("block_id", blockID[:]).
-				Hex("script_hash", insecureScriptHash[:]).
-				Str("script", string(script)).
-				Msg("script failed to execute on the execution node")
-			return nil, err
+		func(err error) bool { return status.Code(err) == codes.InvalidArgument },
+	)
+	if err == nil {
+		if b.log.GetLevel() == zerolog.DebugLevel {
+			executionTime := time.Now()
+			if b.shouldLogScript(executionTime, insecureScriptHash) {
+				b.log.Debug().
+					Str("execution_node", execNode.String()).
+					Hex("block_id", blockID[:]).
+					Hex("script_hash", insecureScriptHash[:]).
+					Str("script", string(script)).
+					Msg("Successfully executed script")
+				b.loggedScripts.Add(insecureScriptHash, executionTime)
+			}
 		}
-		errors = multierror.Append(errors, err)
-	}
 
-	errToReturn := errors.ErrorOrNil()
-	if errToReturn != nil {
-		b.log.Error().Err(err).Msg("script execution failed for execution node internal reasons")
+		return response.value, response.usage, nil
 	}
+	if status.Code(err) == codes.InvalidArgument {
+		b.log.Debug().Err(err).
+			Str("execution_node", execNode.String()).
+			Hex("block_id", blockID[:]).
+			Hex("script_hash", insecureScriptHash[:]).
+			Str("script", string(script)).
+			Msg("script failed to execute on the execution node")
+		return nil, nil, err
+	}
+
+	b.log.Error().Err(err).Msg("script execution failed for execution node internal reasons")
 
-	return nil, rpc.ConvertMultiError(errors, "failed to execute script on execution nodes", codes.Internal)
+	return nil, nil, rpc.ConvertError(err, "failed to execute script on execution nodes", codes.Internal)
 }
 
 // shouldLogScript checks if the script hash is unique in the time window
@@ -167,19 +420,44 @@ func (b *backendScripts) shouldLogScript(execTime time.Time, scriptHash [16]byte
 	}
 }
 
-func (b *backendScripts) tryExecuteScript(ctx context.Context, execNode *flow.Identity, req *execproto.ExecuteScriptAtBlockIDRequest) ([]byte, error) {
+// scriptResponse is the response of an execution node to a script execution request.
+type scriptResponse struct {
+	value []byte
+	// usage is the computation and memory used by the script, nil if it was not requested
+	usage metadata.MD
+}
+
+func (b *backendScripts) tryExecuteScript(
+	ctx context.Context,
+	execNode *flow.Identity,
+	req *execproto.ExecuteScriptAtBlockIDRequest,
+	requestUsage bool,
+) (scriptResponse, error) {
 	execRPCClient, closer, err := b.connFactory.GetExecutionAPIClient(execNode.Address)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to create client for execution node %s: %v", execNode.String(), err)
+		return scriptResponse{}, status.Errorf(codes.Internal, "failed to create client for execution node %s: %v", execNode.String(), err)
 	}
 	defer closer.Close()
 
-	execResp, err := execRPCClient.ExecuteScriptAtBlockID(ctx, req)
+	// the execution node sends the usage in the response trailer if requested
+	var opts []grpc.CallOption
+	var trailer metadata.MD
+	if requestUsage {
+		ctx = rpc.WithScriptUsageRequested(ctx)
+		opts = append(opts, grpc.Trailer(&trailer))
+	}
+
+	execResp, err := execRPCClient.ExecuteScriptAtBlockID(ctx, req, opts...)
 	if err != nil {
 		if status.Code(err) == codes.Unavailable {
 			b.connFactory.InvalidateExecutionAPIClient(execNode.Address)
 		}
-		return nil, status.Errorf(status.Code(err), "failed to execute the script on the execution node %s: %v", execNode.String(), err)
+		return scriptResponse{}, status.Errorf(status.Code(err), "failed to execute the script on the execution node %s: %v", execNode.String(), err)
+	}
+
+	response := scriptResponse{value: execResp.GetValue()}
+	if requestUsage {
+		response.usage = rpc.FilterScriptUsageTrailer(trailer)
 	}
-	return execResp.GetValue(), nil
+	return response, nil
 }
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/dgraph-io/badger/v2"
+	"github.com/onflow/cadence/runtime/common"
 	accessproto "github.com/onflow/flow/protobuf/go/flow/access"
 	entitiesproto "github.com/onflow/flow/protobuf/go/flow/entities"
 	execproto "github.com/onflow/flow/protobuf/go/flow/execution"
@@ -16,13 +17,24 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
 	access "github.com/onflow/flow-go/engine/access/mock"
 	backendmock "github.com/onflow/flow-go/engine/access/rpc/backend/mock"
+	"github.com/onflow/flow-go/engine/common/rpc"
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	"github.com/onflow/flow-go/engine/common/rpc/extended"
+	extendedmock "github.com/onflow/flow-go/engine/common/rpc/extended/mock"
+	"github.com/onflow/flow-go/engine/execution/computation/query"
+	"github.com/onflow/flow-go/fvm/environment"
+	fvmerrors "github.com/onflow/flow-go/fvm/errors"
+	"github.com/onflow/flow-go/fvm/meter"
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/execution"
+	execmock "github.com/onflow/flow-go/module/execution/mock"
 	"github.com/onflow/flow-go/module/metrics"
 	bprotocol "github.com/onflow/flow-go/state/protocol/badger"
 	protocol "github.com/onflow/flow-go/state/protocol/mock"
@@ -47,6 +59,7 @@ type Suite struct {
 	results                *storagemock.ExecutionResults
 	colClient              *access.AccessAPIClient
 	execClient             *access.ExecutionAPIClient
+	extendedExecClient     *extendedmock.ExtendedExecutionAPIClient
 	historicalAccessClient *access.AccessAPIClient
 	connectionFactory      *backendmock.ConnectionFactory
 	chainID                flow.ChainID
@@ -74,6 +87,7 @@ func (suite *Suite) SetupTest() {
 	suite.results = new(storagemock.ExecutionResults)
 	suite.colClient = new(access.AccessAPIClient)
 	suite.execClient = new(access.ExecutionAPIClient)
+	suite.extendedExecClient = new(extendedmock.ExtendedExecutionAPIClient)
 	suite.chainID = flow.Testnet
 	suite.historicalAccessClient = new(access.AccessAPIClient)
 	suite.connectionFactory = new(backendmock.ConnectionFactory)
@@ -1604,6 +1618,7 @@ func (suite *Suite) TestGetEventsForHeightRange() {
 		var enIDs flow.IdentityList
 		for i := min; i <= max; i++ {
 			block := unittest.BlockFixture()
+			block.Header.Height = i
 			header := block.Header
 			headersDB[i] = header
 			headers = append(headers, header)
@@ -1831,6 +1846,77 @@ func (suite *Suite) TestGetEventsForHeightRange() {
 		_, err := backend.GetEventsForHeightRange(ctx, string(flow.EventAccountCreated), minHeight, maxHeight)
 		suite.Require().Error(err)
 	})
+	suite.Run("valid request with events served from the local index", func() {
+		headHeight = maxHeight + 1
+
+		// setup mocks
+		setupHeadHeight(headHeight)
+		var allHeaders []*flow.Header
+		allHeaders, _, nodeIdentities = setupStorage(minHeight, maxHeight)
+		fixedENIdentifiersStr := flow.IdentifierList(nodeIdentities.NodeIDs()).Strings()
+
+		// heights up to indexedHeight are served from the local index, the rest from execution nodes
+		indexedHeight := minHeight + 2
+		eventsIndex := backendmock.NewEventsIndex(suite.T())
+		eventsIndex.On("IndexedHeightRange").Return(minHeight, indexedHeight, true)
+
+		var expectedResp []flow.BlockEvents
+		for _, header := range allHeaders[:indexedHeight-minHeight+1] {
+			// events from the index are not guaranteed to be in execution order
+			indexedEvents := []flow.Event{
+				{Type: flow.EventAccountCreated, TransactionIndex: 1},
+				{Type: flow.EventAccountCreated, TransactionIndex: 0},
+			}
+			eventsIndex.
+				On("ByBlockIDEventType", header.ID(), flow.EventAccountCreated).
+				Return(indexedEvents, nil).
+				Once()
+
+			expectedResp = append(expectedResp, flow.BlockEvents{
+				BlockID:        header.ID(),
+				BlockHeight:    header.Height,
+				BlockTimestamp: header.Timestamp,
+				Events: []flow.Event{
+					{Type: flow.EventAccountCreated, TransactionIndex: 0},
+					{Type: flow.EventAccountCreated, TransactionIndex: 1},
+				},
+			})
+		}
+
+		blockHeaders = allHeaders[indexedHeight-minHeight+1:]
+		expectedResp = append(expectedResp, setupExecClient()...)
+
+		// create handler
+		backend := New(
+			state,
+			nil,
+			nil,
+			suite.blocks,
+			suite.headers,
+			nil,
+			nil,
+			suite.receipts,
+			suite.results,
+			suite.chainID,
+			metrics.NewNoopCollector(),
+			connFactory, // the connection factory should be used to get the execution node client
+			false,
+			DefaultMaxHeightRange,
+			nil,
+			fixedENIdentifiersStr,
+			suite.log,
+			DefaultSnapshotHistoryLimit,
+		)
+		backend.SetEventsIndex(eventsIndex)
+
+		// execute request
+		actualResp, err := backend.GetEventsForHeightRange(ctx, string(flow.EventAccountCreated), minHeight, maxHeight)
+
+		// check response
+		suite.checkResponse(actualResp, err)
+		suite.assertAllExpectations()
+		suite.Require().Equal(expectedResp, actualResp)
+	})
 
 }
 
@@ -1871,7 +1957,7 @@ func (suite *Suite) TestGetAccount() {
 
 	// setup the execution client mock
 	suite.execClient.
-		On("GetAccountAtBlockID", ctx, exeReq).
+		On("GetAccountAtBlockID", mock.Anything, exeReq).
 		Return(exeResp, nil).
 		Once()
 
@@ -1958,7 +2044,7 @@ func (suite *Suite) TestGetAccountAtBlockHeight() {
 
 	// setup the execution client mock
 	suite.execClient.
-		On("GetAccountAtBlockID", ctx, exeReq).
+		On("GetAccountAtBlockID", mock.Anything, exeReq).
 		Return(exeResp, nil).
 		Once()
 
@@ -2087,7 +2173,7 @@ func (suite *Suite) TestExecutionNodesForBlockID() {
 		if fixedENs != nil {
 			fixedENIdentifiers = fixedENs.NodeIDs()
 		}
-		actualList, err := executionNodesForBlockID(context.Background(), block.ID(), suite.receipts, suite.state, suite.log)
+		actualList, err := executionNodesForBlockID(context.Background(), block.ID(), suite.receipts, suite.state, nil, suite.log)
 		require.NoError(suite.T(), err)
 		if expectedENs == nil {
 			expectedENs = flow.IdentityList{}
@@ -2107,7 +2193,7 @@ func (suite *Suite) TestExecutionNodesForBlockID() {
 		attempt2Receipts = flow.ExecutionReceiptList{}
 		attempt3Receipts = flow.ExecutionReceiptList{}
 		suite.state.On("AtBlockID", mock.Anything).Return(suite.snapshot)
-		actualList, err := executionNodesForBlockID(context.Background(), block.ID(), suite.receipts, suite.state, suite.log)
+		actualList, err := executionNodesForBlockID(context.Background(), block.ID(), suite.receipts, suite.state, nil, suite.log)
 		require.NoError(suite.T(), err)
 		require.Equal(suite.T(), len(actualList), maxExecutionNodesCnt)
 	})
@@ -2217,15 +2303,36 @@ func (suite *Suite) TestExecuteScriptOnExecutionNode() {
 
 	suite.Run("happy path script execution success", func() {
 		suite.execClient.On("ExecuteScriptAtBlockID", ctx, execReq).Return(execRes, nil).Once()
-		res, err := backend.tryExecuteScript(ctx, executionNode, execReq)
+		res, err := backend.tryExecuteScript(ctx, executionNode, execReq, false)
 		suite.execClient.AssertExpectations(suite.T())
-		suite.checkResponse(res, err)
+		suite.checkResponse(res.value, err)
+		suite.Require().Nil(res.usage)
+	})
+
+	suite.Run("happy path script execution with usage requested", func() {
+		suite.execClient.On("ExecuteScriptAtBlockID", mock.Anything, execReq, mock.Anything).
+			Run(func(args mock.Arguments) {
+				// the usage is requested from the execution node
+				md, _ := metadata.FromOutgoingContext(args.Get(0).(context.Context))
+				suite.Require().Equal([]string{"true"}, md.Get(rpc.ScriptUsageHeader))
+
+				trailer := args.Get(2).(grpc.TrailerCallOption)
+				*trailer.TrailerAddr = metadata.Pairs(
+					rpc.ComputationUsedTrailer, "12",
+					"unrelated", "value",
+				)
+			}).
+			Return(execRes, nil).Once()
+		res, err := backend.tryExecuteScript(ctx, executionNode, execReq, true)
+		suite.execClient.AssertExpectations(suite.T())
+		suite.checkResponse(res.value, err)
+		suite.Require().Equal(metadata.Pairs(rpc.ComputationUsedTrailer, "12"), res.usage)
 	})
 
 	suite.Run("script execution failure returns status OK", func() {
 		suite.execClient.On("ExecuteScriptAtBlockID", ctx, execReq).
 			Return(nil, status.Error(codes.InvalidArgument, "execution failure!")).Once()
-		_, err := backend.tryExecuteScript(ctx, executionNode, execReq)
+		_, err := backend.tryExecuteScript(ctx, executionNode, execReq, false)
 		suite.execClient.AssertExpectations(suite.T())
 		suite.Require().Error(err)
 		suite.Require().Equal(status.Code(err), codes.InvalidArgument)
@@ -2234,13 +2341,580 @@ func (suite *Suite) TestExecuteScriptOnExecutionNode() {
 	suite.Run("execution node internal failure returns status code Internal", func() {
 		suite.execClient.On("ExecuteScriptAtBlockID", ctx, execReq).
 			Return(nil, status.Error(codes.Internal, "execution node internal error!")).Once()
-		_, err := backend.tryExecuteScript(ctx, executionNode, execReq)
+		_, err := backend.tryExecuteScript(ctx, executionNode, execReq, false)
 		suite.execClient.AssertExpectations(suite.T())
 		suite.Require().Error(err)
 		suite.Require().Equal(status.Code(err), codes.Internal)
 	})
 }
 
+// TestExecuteScriptLocally tests script execution in the modes which execute scripts locally.
+func (suite *Suite) TestExecuteScriptLocally() {
+	ctx := context.Background()
+	block := unittest.BlockFixture()
+	blockID := block.ID()
+	script := []byte("dummy script")
+	arguments := [][]byte(nil)
+	localResult := []byte{1, 2, 3}
+
+	suite.state.On("Sealed").Return(suite.snapshot, nil).Maybe()
+	suite.state.On("Final").Return(suite.snapshot, nil).Maybe()
+	suite.headers.On("ByBlockID", blockID).Return(block.Header, nil)
+
+	_, ids := suite.setupReceipts(&block)
+	suite.snapshot.On("Identities", mock.Anything).Return(ids, nil)
+
+	newBackend := func(scriptExecutor *execmock.ScriptExecutor, mode ScriptExecutionMode) *Backend {
+		backend := New(
+			suite.state,
+			nil,
+			nil,
+			nil,
+			suite.headers,
+			nil,
+			nil,
+			suite.receipts,
+			suite.results,
+			flow.Mainnet,
+			metrics.NewNoopCollector(),
+			suite.setupConnectionFactory(),
+			false,
+			DefaultMaxHeightRange,
+			nil,
+			ids.NodeIDs().Strings(),
+			suite.log,
+			DefaultSnapshotHistoryLimit,
+		)
+		backend.SetScriptExecutor(scriptExecutor, mode)
+		return backend
+	}
+
+	execReq := &execproto.ExecuteScriptAtBlockIDRequest{
+		BlockId:   blockID[:],
+		Script:    script,
+		Arguments: arguments,
+	}
+	execRes := &execproto.ExecuteScriptAtBlockIDResponse{
+		Value: []byte{4, 5, 6},
+	}
+
+	suite.Run("local only", func() {
+		scriptExecutor := execmock.NewScriptExecutor(suite.T())
+		scriptExecutor.
+			On("ExecuteAtBlockHeight", ctx, script, arguments, block.Header.Height).
+			Return(localResult, meter.Usage{}, nil).
+			Once()
+
+		backend := newBackend(scriptExecutor, ScriptExecutionModeLocalOnly)
+
+		res, err := backend.ExecuteScriptAtBlockID(ctx, blockID, script, arguments)
+		suite.Require().NoError(err)
+		suite.Require().Equal(localResult, res)
+	})
+
+	suite.Run("local only with usage requested", func() {
+		usage := meter.Usage{ComputationUsed: 12, ComputationLimit: 9999}
+		stream := &mockServerTransportStream{}
+		usageCtx := metadata.NewIncomingContext(ctx, metadata.Pairs(rpc.ScriptUsageHeader, "true"))
+		usageCtx = grpc.NewContextWithServerTransportStream(usageCtx, stream)
+
+		scriptExecutor := execmock.NewScriptExecutor(suite.T())
+		scriptExecutor.
+			On("ExecuteAtBlockHeight", usageCtx, script, arguments, block.Header.Height).
+			Return(localResult, usage, nil).
+			Once()
+
+		backend := newBackend(scriptExecutor, ScriptExecutionModeLocalOnly)
+
+		res, err := backend.ExecuteScriptAtBlockID(usageCtx, blockID, script, arguments)
+		suite.Require().NoError(err)
+		suite.Require().Equal(localResult, res)
+		suite.Require().Equal([]string{"12"}, stream.trailer.Get(rpc.ComputationUsedTrailer))
+		suite.Require().Equal([]string{"9999"}, stream.trailer.Get(rpc.ComputationLimitTrailer))
+	})
+
+	suite.Run("local only with usage recorded", func() {
+		// requests which are not received over gRPC, e.g. through the REST API
+		usage := meter.Usage{ComputationUsed: 12, ComputationLimit: 9999}
+		usageCtx, recorder := rpc.WithScriptUsageRecorder(ctx)
+
+		scriptExecutor := execmock.NewScriptExecutor(suite.T())
+		scriptExecutor.
+			On("ExecuteAtBlockHeight", usageCtx, script, arguments, block.Header.Height).
+			Return(localResult, usage, nil).
+			Once()
+
+		backend := newBackend(scriptExecutor, ScriptExecutionModeLocalOnly)
+
+		res, err := backend.ExecuteScriptAtBlockID(usageCtx, blockID, script, arguments)
+		suite.Require().NoError(err)
+		suite.Require().Equal(localResult, res)
+		suite.Require().Equal(rpc.ScriptUsageTrailer(usage), recorder.Usage())
+	})
+
+	suite.Run("local only with height not indexed", func() {
+		scriptExecutor := execmock.NewScriptExecutor(suite.T())
+		scriptExecutor.
+			On("ExecuteAtBlockHeight", ctx, script, arguments, block.Header.Height).
+			Return(nil, meter.Usage{}, execution.ErrDataNotAvailable).
+			Once()
+
+		backend := newBackend(scriptExecutor, ScriptExecutionModeLocalOnly)
+
+		_, err := backend.ExecuteScriptAtBlockID(ctx, blockID, script, arguments)
+		suite.Require().Error(err)
+		suite.Require().Equal(codes.OutOfRange, status.Code(err))
+	})
+
+	suite.Run("failover to execution nodes", func() {
+		scriptExecutor := execmock.NewScriptExecutor(suite.T())
+		scriptExecutor.
+			On("ExecuteAtBlockHeight", ctx, script, arguments, block.Header.Height).
+			Return(nil, meter.Usage{}, execution.ErrDataNotAvailable).
+			Once()
+		suite.execClient.On("ExecuteScriptAtBlockID", mock.Anything, execReq).Return(execRes, nil).Once()
+
+		backend := newBackend(scriptExecutor, ScriptExecutionModeFailover)
+
+		res, err := backend.ExecuteScriptAtBlockID(ctx, blockID, script, arguments)
+		suite.Require().NoError(err)
+		suite.Require().Equal(execRes.GetValue(), res)
+		suite.execClient.AssertExpectations(suite.T())
+	})
+
+	suite.Run("compare returns execution node result", func() {
+		scriptExecutor := execmock.NewScriptExecutor(suite.T())
+		scriptExecutor.
+			On("ExecuteAtBlockHeight", ctx, script, arguments, block.Header.Height).
+			Return(localResult, meter.Usage{}, nil).
+			Once()
+		suite.execClient.On("ExecuteScriptAtBlockID", mock.Anything, execReq).Return(execRes, nil).Once()
+
+		backend := newBackend(scriptExecutor, ScriptExecutionModeCompare)
+
+		res, err := backend.ExecuteScriptAtBlockID(ctx, blockID, script, arguments)
+		suite.Require().NoError(err)
+		suite.Require().Equal(execRes.GetValue(), res)
+		suite.execClient.AssertExpectations(suite.T())
+	})
+
+	// the block is sealed and finalized, so its script results can be cached
+	suite.snapshot.On("Head").Return(block.Header, nil).Maybe()
+	suite.headers.On("BlockIDByHeight", block.Header.Height).Return(blockID, nil).Maybe()
+
+	newCachingBackend := func(scriptExecutor *execmock.ScriptExecutor, mode ScriptExecutionMode) *Backend {
+		backend := newBackend(scriptExecutor, mode)
+		backend.SetScriptResultCache(NewScriptResultCache(10, suite.log, metrics.NewNoopCollector()))
+		return backend
+	}
+
+	suite.Run("deterministic results are cached", func() {
+		scriptExecutor := execmock.NewScriptExecutor(suite.T())
+		scriptExecutor.
+			On("ExecuteAtBlockHeight", ctx, script, arguments, block.Header.Height).
+			Return(localResult, meter.Usage{ComputationUsed: 12}, nil).
+			Once()
+
+		backend := newCachingBackend(scriptExecutor, ScriptExecutionModeLocalOnly)
+
+		for i := 0; i < 2; i++ {
+			res, err := backend.ExecuteScriptAtBlockID(ctx, blockID, script, arguments)
+			suite.Require().NoError(err)
+			suite.Require().Equal(localResult, res)
+		}
+	})
+
+	suite.Run("results of scripts calling unsafeRandom are not cached", func() {
+		usage := meter.Usage{
+			ComputationUsed: 12,
+			ComputationIntensities: meter.MeteredComputationIntensities{
+				common.ComputationKind(environment.ComputationKindUnsafeRandom): 1,
+			},
+		}
+		scriptExecutor := execmock.NewScriptExecutor(suite.T())
+		scriptExecutor.
+			On("ExecuteAtBlockHeight", ctx, script, arguments, block.Header.Height).
+			Return(localResult, usage, nil).
+			Twice()
+
+		backend := newCachingBackend(scriptExecutor, ScriptExecutionModeLocalOnly)
+
+		for i := 0; i < 2; i++ {
+			res, err := backend.ExecuteScriptAtBlockID(ctx, blockID, script, arguments)
+			suite.Require().NoError(err)
+			suite.Require().Equal(localResult, res)
+		}
+	})
+
+	suite.Run("usage is requested from execution nodes to cache results", func() {
+		suite.execClient.On("ExecuteScriptAtBlockID", mock.Anything, execReq, mock.Anything).
+			Run(func(args mock.Arguments) {
+				md, _ := metadata.FromOutgoingContext(args.Get(0).(context.Context))
+				suite.Require().Equal([]string{"true"}, md.Get(rpc.ScriptUsageHeader))
+
+				trailer := args.Get(2).(grpc.TrailerCallOption)
+				*trailer.TrailerAddr = rpc.ScriptUsageTrailer(meter.Usage{ComputationUsed: 12})
+			}).
+			Return(execRes, nil).
+			Once()
+
+		backend := newCachingBackend(nil, ScriptExecutionModeExecutionNodesOnly)
+
+		for i := 0; i < 2; i++ {
+			res, err := backend.ExecuteScriptAtBlockID(ctx, blockID, script, arguments)
+			suite.Require().NoError(err)
+			suite.Require().Equal(execRes.GetValue(), res)
+		}
+		suite.execClient.AssertExpectations(suite.T())
+	})
+}
+
+func (suite *Suite) TestSimulateTransaction() {
+	ctx := context.Background()
+	block := unittest.BlockFixture()
+	blockID := block.ID()
+	tx := unittest.TransactionBodyFixture()
+
+	suite.blocks.On("ByID", blockID).Return(&block, nil)
+	suite.state.On("Final").Return(suite.snapshot, nil).Maybe()
+
+	_, ids := suite.setupReceipts(&block)
+	suite.snapshot.On("Identities", mock.Anything).Return(ids, nil)
+
+	newBackend := func(scriptExecutor *execmock.ScriptExecutor) *Backend {
+		backend := New(
+			suite.state,
+			nil,
+			nil,
+			suite.blocks,
+			suite.headers,
+			nil,
+			nil,
+			suite.receipts,
+			suite.results,
+			flow.Mainnet,
+			metrics.NewNoopCollector(),
+			suite.setupConnectionFactory(),
+			false,
+			DefaultMaxHeightRange,
+			nil,
+			ids.NodeIDs().Strings(),
+			suite.log,
+			DefaultSnapshotHistoryLimit,
+		)
+		if scriptExecutor != nil {
+			backend.SetScriptExecutor(scriptExecutor, ScriptExecutionModeLocalOnly)
+		}
+		return backend
+	}
+
+	execReq := func(skipSignatureVerification bool) *extended.SimulateTransactionAtBlockIDRequest {
+		return &extended.SimulateTransactionAtBlockIDRequest{
+			BlockId:                   blockID[:],
+			Transaction:               convert.TransactionToMessage(tx),
+			SkipSignatureVerification: skipSignatureVerification,
+		}
+	}
+
+	suite.Run("simulated on execution nodes", func() {
+		events := []flow.Event{unittest.EventFixture(flow.EventAccountCreated, 0, 0, tx.ID(), 0)}
+		register := flow.NewRegisterID("01", "foo")
+		suite.extendedExecClient.
+			On("SimulateTransactionAtBlockID", mock.Anything, execReq(true)).
+			Return(&extended.SimulateTransactionResponse{
+				Events:          convert.EventsToMessages(events),
+				ErrorMessage:    "out of gas",
+				ComputationUsed: 12,
+				MemoryEstimate:  300,
+				RegistersRead: []*extended.RegisterID{
+					{Owner: []byte(register.Owner), Key: []byte(register.Key)},
+				},
+			}, nil).
+			Once()
+
+		backend := newBackend(nil)
+
+		res, err := backend.SimulateTransaction(ctx, blockID, &tx, true)
+		suite.Require().NoError(err)
+		suite.Require().Equal(blockID, res.BlockID)
+		suite.Require().Equal(events, res.Events)
+		suite.Require().Equal("out of gas", res.ErrorMessage)
+		suite.Require().Equal(uint64(12), res.ComputationUsed)
+		suite.Require().Equal(uint64(300), res.MemoryEstimate)
+		suite.Require().Equal([]flow.RegisterID{register}, res.RegistersRead)
+		suite.Require().Empty(res.RegistersWritten)
+		suite.extendedExecClient.AssertExpectations(suite.T())
+	})
+
+	suite.Run("invalid transaction on execution nodes", func() {
+		// the transaction is rejected by the first execution node, without trying the other one
+		suite.extendedExecClient.
+			On("SimulateTransactionAtBlockID", mock.Anything, execReq(false)).
+			Return(nil, status.Error(codes.InvalidArgument, "invalid transaction")).
+			Once()
+
+		backend := newBackend(nil)
+
+		_, err := backend.SimulateTransaction(ctx, blockID, &tx, false)
+		suite.Require().Equal(codes.InvalidArgument, status.Code(err))
+		suite.extendedExecClient.AssertExpectations(suite.T())
+	})
+
+	suite.Run("simulated locally", func() {
+		simulation := &query.TransactionSimulation{
+			Events:           flow.EventsList{unittest.EventFixture(flow.EventAccountCreated, 0, 0, tx.ID(), 0)},
+			ComputationUsed:  12,
+			MemoryEstimate:   300,
+			RegistersRead:    flow.RegisterIDs{flow.NewRegisterID("01", "foo")},
+			RegistersWritten: flow.RegisterIDs{},
+		}
+
+		scriptExecutor := execmock.NewScriptExecutor(suite.T())
+		scriptExecutor.
+			On("SimulateTransactionAtBlockHeight", ctx, &tx, block.Header.Height, true).
+			Return(simulation, nil).
+			Once()
+
+		backend := newBackend(scriptExecutor)
+
+		res, err := backend.SimulateTransaction(ctx, blockID, &tx, true)
+		suite.Require().NoError(err)
+		suite.Require().Equal(blockID, res.BlockID)
+		suite.Require().Equal([]flow.Event(simulation.Events), res.Events)
+		suite.Require().Equal(simulation.ComputationUsed, res.ComputationUsed)
+		suite.Require().Equal(simulation.MemoryEstimate, res.MemoryEstimate)
+		suite.Require().Equal([]flow.RegisterID(simulation.RegistersRead), res.RegistersRead)
+	})
+
+	suite.Run("height not indexed", func() {
+		scriptExecutor := execmock.NewScriptExecutor(suite.T())
+		scriptExecutor.
+			On("SimulateTransactionAtBlockHeight", ctx, &tx, block.Header.Height, false).
+			Return(nil, execution.ErrDataNotAvailable).
+			Once()
+
+		backend := newBackend(scriptExecutor)
+
+		_, err := backend.SimulateTransaction(ctx, blockID, &tx, false)
+		suite.Require().Error(err)
+		suite.Require().Equal(codes.OutOfRange, status.Code(err))
+	})
+}
+
+func (suite *Suite) TestGetAccountKeysAndBalanceLocally() {
+	ctx := context.Background()
+	block := unittest.BlockFixture()
+	blockID := block.ID()
+	height := block.Header.Height
+
+	account, err := unittest.AccountFixture()
+	suite.Require().NoError(err)
+	accountMsg, err := convert.AccountToMessage(account)
+	suite.Require().NoError(err)
+
+	suite.state.On("Sealed").Return(suite.snapshot, nil).Maybe()
+	suite.state.On("Final").Return(suite.snapshot, nil).Maybe()
+	suite.headers.On("ByHeight", height).Return(block.Header, nil)
+
+	_, ids := suite.setupReceipts(&block)
+	suite.snapshot.On("Identities", mock.Anything).Return(ids, nil)
+
+	newBackend := func(scriptExecutor *execmock.ScriptExecutor, mode ScriptExecutionMode) *Backend {
+		backend := New(
+			suite.state,
+			nil,
+			nil,
+			nil,
+			suite.headers,
+			nil,
+			nil,
+			suite.receipts,
+			suite.results,
+			flow.Mainnet,
+			metrics.NewNoopCollector(),
+			suite.setupConnectionFactory(),
+			false,
+			DefaultMaxHeightRange,
+			nil,
+			ids.NodeIDs().Strings(),
+			suite.log,
+			DefaultSnapshotHistoryLimit,
+		)
+		backend.SetScriptExecutor(scriptExecutor, mode)
+		return backend
+	}
+
+	execReq := &execproto.GetAccountAtBlockIDRequest{
+		BlockId: blockID[:],
+		Address: account.Address.Bytes(),
+	}
+	execRes := &execproto.GetAccountAtBlockIDResponse{
+		Account: accountMsg,
+	}
+
+	suite.Run("local only", func() {
+		scriptExecutor := execmock.NewScriptExecutor(suite.T())
+		scriptExecutor.
+			On("GetAccountBalance", ctx, account.Address, height).
+			Return(account.Balance, nil).
+			Once()
+		scriptExecutor.
+			On("GetAccountKeys", ctx, account.Address, height).
+			Return(account.Keys, nil).
+			Once()
+		scriptExecutor.
+			On("GetAccountKey", ctx, account.Address, uint64(0), height).
+			Return(&account.Keys[0], nil).
+			Once()
+
+		backend := newBackend(scriptExecutor, ScriptExecutionModeLocalOnly)
+
+		balance, err := backend.GetAccountBalanceAtBlockHeight(ctx, account.Address, height)
+		suite.Require().NoError(err)
+		suite.Require().Equal(account.Balance, balance)
+
+		keys, err := backend.GetAccountKeysAtBlockHeight(ctx, account.Address, height)
+		suite.Require().NoError(err)
+		suite.Require().Equal(account.Keys, keys)
+
+		key, err := backend.GetAccountKeyAtBlockHeight(ctx, account.Address, 0, height)
+		suite.Require().NoError(err)
+		suite.Require().Equal(account.Keys[0], *key)
+	})
+
+	suite.Run("local only errors", func() {
+		scriptExecutor := execmock.NewScriptExecutor(suite.T())
+		scriptExecutor.
+			On("GetAccountBalance", ctx, account.Address, height).
+			Return(uint64(0), execution.ErrDataNotAvailable).
+			Once()
+		scriptExecutor.
+			On("GetAccountKeys", ctx, account.Address, height).
+			Return(nil, fvmerrors.NewAccountNotFoundError(account.Address)).
+			Once()
+		scriptExecutor.
+			On("GetAccountKey", ctx, account.Address, uint64(1), height).
+			Return(nil, fvmerrors.NewAccountPublicKeyNotFoundError(account.Address, 1)).
+			Once()
+
+		backend := newBackend(scriptExecutor, ScriptExecutionModeLocalOnly)
+
+		_, err := backend.GetAccountBalanceAtBlockHeight(ctx, account.Address, height)
+		suite.Require().Equal(codes.OutOfRange, status.Code(err))
+
+		_, err = backend.GetAccountKeysAtBlockHeight(ctx, account.Address, height)
+		suite.Require().Equal(codes.NotFound, status.Code(err))
+
+		_, err = backend.GetAccountKeyAtBlockHeight(ctx, account.Address, 1, height)
+		suite.Require().Equal(codes.NotFound, status.Code(err))
+	})
+
+	keyMsgs := make([]*entitiesproto.AccountKey, len(account.Keys))
+	for i, key := range account.Keys {
+		keyMsgs[i], err = convert.AccountKeyToMessage(key)
+		suite.Require().NoError(err)
+	}
+
+	suite.Run("failover to execution nodes", func() {
+		scriptExecutor := execmock.NewScriptExecutor(suite.T())
+		scriptExecutor.
+			On("GetAccountKey", ctx, account.Address, uint64(0), height).
+			Return(nil, execution.ErrDataNotAvailable).
+			Once()
+		suite.extendedExecClient.
+			On("GetAccountKeyAtBlockID", mock.Anything, &extended.GetAccountKeyAtBlockIDRequest{
+				Address: account.Address.Bytes(),
+				Index:   0,
+				BlockId: blockID[:],
+			}).
+			Return(&extended.AccountKeyResponse{AccountKey: keyMsgs[0]}, nil).
+			Once()
+
+		backend := newBackend(scriptExecutor, ScriptExecutionModeFailover)
+
+		key, err := backend.GetAccountKeyAtBlockHeight(ctx, account.Address, 0, height)
+		suite.Require().NoError(err)
+		suite.requireAccountKey(account.Keys[0], *key)
+		suite.extendedExecClient.AssertExpectations(suite.T())
+	})
+
+	suite.Run("execution nodes only", func() {
+		suite.extendedExecClient.
+			On("GetAccountBalanceAtBlockID", mock.Anything, &extended.GetAccountBalanceAtBlockIDRequest{
+				Address: account.Address.Bytes(),
+				BlockId: blockID[:],
+			}).
+			Return(&extended.AccountBalanceResponse{Balance: account.Balance}, nil).
+			Once()
+		suite.extendedExecClient.
+			On("GetAccountKeysAtBlockID", mock.Anything, &extended.GetAccountKeysAtBlockIDRequest{
+				Address: account.Address.Bytes(),
+				BlockId: blockID[:],
+			}).
+			Return(&extended.AccountKeysResponse{AccountKeys: keyMsgs}, nil).
+			Once()
+		suite.extendedExecClient.
+			On("GetAccountKeyAtBlockID", mock.Anything, &extended.GetAccountKeyAtBlockIDRequest{
+				Address: account.Address.Bytes(),
+				Index:   uint32(len(account.Keys)),
+				BlockId: blockID[:],
+			}).
+			Return(nil, status.Error(codes.NotFound, "key not found"))
+
+		backend := newBackend(nil, ScriptExecutionModeExecutionNodesOnly)
+
+		// the whole account is not requested from execution nodes
+		balance, err := backend.GetAccountBalanceAtBlockHeight(ctx, account.Address, height)
+		suite.Require().NoError(err)
+		suite.Require().Equal(account.Balance, balance)
+
+		keys, err := backend.GetAccountKeysAtBlockHeight(ctx, account.Address, height)
+		suite.Require().NoError(err)
+		suite.Require().Len(keys, len(account.Keys))
+		for i := range keys {
+			suite.requireAccountKey(account.Keys[i], keys[i])
+		}
+
+		// keys that do not exist are not found
+		_, err = backend.GetAccountKeyAtBlockHeight(ctx, account.Address, uint32(len(account.Keys)), height)
+		suite.Require().Equal(codes.NotFound, status.Code(err))
+		suite.extendedExecClient.AssertExpectations(suite.T())
+		suite.execClient.AssertNotCalled(suite.T(), "GetAccountAtBlockID", mock.Anything, mock.Anything)
+	})
+
+	suite.Run("execution nodes without the extended execution API", func() {
+		unimplemented := status.Error(codes.Unimplemented, "unknown service flow.extended.ExtendedExecutionAPI")
+		suite.extendedExecClient.
+			On("GetAccountBalanceAtBlockID", mock.Anything, mock.Anything).
+			Return(nil, unimplemented)
+		suite.extendedExecClient.
+			On("GetAccountKeyAtBlockID", mock.Anything, mock.Anything).
+			Return(nil, unimplemented)
+		suite.execClient.On("GetAccountAtBlockID", mock.Anything, execReq).Return(execRes, nil).Twice()
+
+		backend := newBackend(nil, ScriptExecutionModeExecutionNodesOnly)
+
+		// the whole account is requested from execution nodes instead
+		balance, err := backend.GetAccountBalanceAtBlockHeight(ctx, account.Address, height)
+		suite.Require().NoError(err)
+		suite.Require().Equal(account.Balance, balance)
+
+		key, err := backend.GetAccountKeyAtBlockHeight(ctx, account.Address, 0, height)
+		suite.Require().NoError(err)
+		suite.requireAccountKey(account.Keys[0], *key)
+		suite.execClient.AssertExpectations(suite.T())
+	})
+}
+
+// requireAccountKey checks that the account keys are equal, comparing public keys by their
+// encoding.
+func (suite *Suite) requireAccountKey(expected flow.AccountPublicKey, actual flow.AccountPublicKey) {
+	suite.Require().Equal(expected.PublicKey.Encode(), actual.PublicKey.Encode())
+	expected.PublicKey = nil
+	actual.PublicKey = nil
+	suite.Require().Equal(expected, actual)
+}
+
 func (suite *Suite) assertAllExpectations() {
 	suite.snapshot.AssertExpectations(suite.T())
 	suite.state.AssertExpectations(suite.T())
@@ -2276,6 +2950,7 @@ func (suite *Suite) setupConnectionFactory() ConnectionFactory {
 	// create a mock connection factory
 	connFactory := new(backendmock.ConnectionFactory)
 	connFactory.On("GetExecutionAPIClient", mock.Anything).Return(suite.execClient, &mockCloser{}, nil)
+	connFactory.On("GetExtendedExecutionAPIClient", mock.Anything).Return(suite.extendedExecClient, &mockCloser{}, nil).Maybe()
 	connFactory.On("InvalidateExecutionAPIClient", mock.Anything)
 	return connFactory
 }
@@ -2287,3 +2962,29 @@ func getEvents(n int) []flow.Event {
 	}
 	return events
 }
+
+// mockServerTransportStream records the metadata the backend sends with its response.
+type mockServerTransportStream struct {
+	header  metadata.MD
+	trailer metadata.MD
+}
+
+var _ grpc.ServerTransportStream = (*mockServerTransportStream)(nil)
+
+func (m *mockServerTransportStream) Method() string {
+	return ""
+}
+
+func (m *mockServerTransportStream) SetHeader(md metadata.MD) error {
+	m.header = metadata.Join(m.header, md)
+	return nil
+}
+
+func (m *mockServerTransportStream) SendHeader(md metadata.MD) error {
+	return m.SetHeader(md)
+}
+
+func (m *mockServerTransportStream) SetTrailer(md metadata.MD) error {
+	m.trailer = metadata.Join(m.trailer, md)
+	return nil
+}
@@ -17,9 +17,11 @@ import (
 	"github.com/onflow/flow-go/access"
 	"github.com/onflow/flow-go/engine/common/rpc"
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	"github.com/onflow/flow-go/engine/common/rpc/extended"
 	"github.com/onflow/flow-go/fvm/blueprints"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/execution"
 	"github.com/onflow/flow-go/state/protocol"
 	"github.com/onflow/flow-go/storage"
 )
@@ -38,6 +40,8 @@ type backendTransactions struct {
 	transactionValidator *access.TransactionValidator
 	retry                *Retry
 	connFactory          ConnectionFactory
+	nodeSelector         *ExecutionNodeSelector   // optional, execution nodes are chosen randomly if nil
+	scriptExecutor       execution.ScriptExecutor // optional, only required to simulate transactions locally
 
 	previousAccessNodes []accessproto.AccessAPIClient
 	log                 zerolog.Logger
@@ -50,7 +54,7 @@ func (b *backendTransactions) SendTransaction(
 ) error {
 	now := time.Now().UTC()
 
-	err := b.transactionValidator.Validate(tx)
+	err := b.transactionValidator.Validate(ctx, tx)
 	if err != nil {
 		return status.Errorf(codes.InvalidArgument, "invalid transaction: %s", err.Error())
 	}
@@ -312,7 +316,7 @@ func (b *backendTransactions) GetTransactionResultsByBlockID(
 	req := &execproto.GetTransactionsByBlockIDRequest{
 		BlockId: blockID[:],
 	}
-	execNodes, err := executionNodesForBlockID(ctx, blockID, b.executionReceipts, b.state, b.log)
+	execNodes, err := executionNodesForBlockID(ctx, blockID, b.executionReceipts, b.state, b.nodeSelector, b.log)
 	if err != nil {
 		if IsInsufficientExecutionReceipts(err) {
 			return nil, status.Errorf(codes.NotFound, err.Error())
@@ -432,7 +436,7 @@ func (b *backendTransactions) GetTransactionResultByIndex(
 		BlockId: blockID[:],
 		Index:   index,
 	}
-	execNodes, err := executionNodesForBlockID(ctx, blockID, b.executionReceipts, b.state, b.log)
+	execNodes, err := executionNodesForBlockID(ctx, blockID, b.executionReceipts, b.state, b.nodeSelector, b.log)
 	if err != nil {
 		if IsInsufficientExecutionReceipts(err) {
 			return nil, status.Errorf(codes.NotFound, err.Error())
@@ -462,6 +466,109 @@ func (b *backendTransactions) GetTransactionResultByIndex(
 	}, nil
 }
 
+// SimulateTransaction executes the transaction at the given block without applying its state
+// changes. The verification of the transaction signatures is optionally skipped.
+// The transaction is executed against the locally indexed execution state if local script
+// execution is enabled, otherwise on the execution nodes holding the state of the block.
+func (b *backendTransactions) SimulateTransaction(
+	ctx context.Context,
+	blockID flow.Identifier,
+	tx *flow.TransactionBody,
+	skipSignatureVerification bool,
+) (*access.TransactionSimulationResult, error) {
+	if b.scriptExecutor == nil {
+		return b.simulateTransactionOnExecutionNodes(ctx, blockID, tx, skipSignatureVerification)
+	}
+
+	block, err := b.blocks.ByID(blockID)
+	if err != nil {
+		return nil, rpc.ConvertStorageError(err)
+	}
+
+	simulation, err := b.scriptExecutor.SimulateTransactionAtBlockHeight(ctx, tx, block.Header.Height, skipSignatureVerification)
+	if err != nil {
+		if errors.Is(err, execution.ErrDataNotAvailable) {
+			return nil, status.Errorf(codes.OutOfRange, "failed to simulate transaction: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to simulate transaction: %v", err)
+	}
+
+	return &access.TransactionSimulationResult{
+		BlockID:          blockID,
+		Events:           simulation.Events,
+		ErrorMessage:     simulation.ErrorMessage,
+		ComputationUsed:  simulation.ComputationUsed,
+		MemoryEstimate:   simulation.MemoryEstimate,
+		RegistersRead:    simulation.RegistersRead,
+		RegistersWritten: simulation.RegistersWritten,
+	}, nil
+}
+
+// simulateTransactionOnExecutionNodes simulates the transaction through the extended Execution API
+// of the execution nodes holding the state of the block, until one of them responds.
+func (b *backendTransactions) simulateTransactionOnExecutionNodes(
+	ctx context.Context,
+	blockID flow.Identifier,
+	tx *flow.TransactionBody,
+	skipSignatureVerification bool,
+) (*access.TransactionSimulationResult, error) {
+	execNodes, err := executionNodesForBlockID(ctx, blockID, b.executionReceipts, b.state, b.nodeSelector, b.log)
+	if err != nil {
+		return nil, rpc.ConvertError(err, "failed to simulate transaction on the execution node", codes.Internal)
+	}
+
+	txID := tx.ID()
+	req := &extended.SimulateTransactionAtBlockIDRequest{
+		BlockId:                   blockID[:],
+		Transaction:               convert.TransactionToMessage(*tx),
+		SkipSignatureVerification: skipSignatureVerification,
+	}
+
+	resp, _, err := executeHedged(ctx, b.nodeSelector, execNodes,
+		func(ctx context.Context, execNode *flow.Identity) (*extended.SimulateTransactionResponse, error) {
+			resp, err := tryExtendedExecutionAPI(ctx, b.connFactory, execNode,
+				func(ctx context.Context, client extended.ExtendedExecutionAPIClient) (*extended.SimulateTransactionResponse, error) {
+					return client.SimulateTransactionAtBlockID(ctx, req)
+				})
+			if err != nil && status.Code(err) != codes.Canceled {
+				b.log.Error().
+					Str("execution_node", execNode.String()).
+					Hex("block_id", blockID[:]).
+					Hex("transaction_id", txID[:]).
+					Err(err).
+					Msg("failed to simulate transaction")
+			}
+			return resp, err
+		},
+		// an invalid transaction fails the same way on all execution nodes
+		func(err error) bool { return status.Code(err) == codes.InvalidArgument },
+	)
+	if err != nil {
+		return nil, rpc.ConvertError(err, "failed to simulate transaction on the execution node", codes.Internal)
+	}
+
+	return &access.TransactionSimulationResult{
+		BlockID:          blockID,
+		Events:           convert.MessagesToEvents(resp.GetEvents()),
+		ErrorMessage:     resp.GetErrorMessage(),
+		ComputationUsed:  resp.GetComputationUsed(),
+		MemoryEstimate:   resp.GetMemoryEstimate(),
+		RegistersRead:    messagesToRegisterIDs(resp.GetRegistersRead()),
+		RegistersWritten: messagesToRegisterIDs(resp.GetRegistersWritten()),
+	}, nil
+}
+
+func messagesToRegisterIDs(messages []*extended.RegisterID) flow.RegisterIDs {
+	ids := make(flow.RegisterIDs, len(messages))
+	for i, m := range messages {
+		ids[i] = flow.RegisterID{
+			Owner: string(m.GetOwner()),
+			Key:   string(m.GetKey()),
+		}
+	}
+	return ids
+}
+
 // deriveTransactionStatus derives the transaction status based on current protocol state
 func (b *backendTransactions) deriveTransactionStatus(
 	tx *flow.TransactionBody,
@@ -656,7 +763,7 @@ func (b *backendTransactions) getTransactionResultFromExecutionNode(
 		TransactionId: transactionID,
 	}
 
-	execNodes, err := executionNodesForBlockID(ctx, blockID, b.executionReceipts, b.state, b.log)
+	execNodes, err := executionNodesForBlockID(ctx, blockID, b.executionReceipts, b.state, b.nodeSelector, b.log)
 	if err != nil {
 		// if no execution receipt were found, return a NotFound GRPC error
 		if IsInsufficientExecutionReceipts(err) {
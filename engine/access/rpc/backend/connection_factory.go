@@ -17,6 +17,7 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/keepalive"
 
+	"github.com/onflow/flow-go/engine/common/rpc/extended"
 	"github.com/onflow/flow-go/module"
 )
 
@@ -28,6 +29,7 @@ type ConnectionFactory interface {
 	GetAccessAPIClient(address string) (access.AccessAPIClient, io.Closer, error)
 	InvalidateAccessAPIClient(address string)
 	GetExecutionAPIClient(address string) (execution.ExecutionAPIClient, io.Closer, error)
+	GetExtendedExecutionAPIClient(address string) (extended.ExtendedExecutionAPIClient, io.Closer, error)
 	InvalidateExecutionAPIClient(address string)
 }
 
@@ -50,6 +52,10 @@ func (p *ProxyConnectionFactory) GetExecutionAPIClient(address string) (executio
 	return p.ConnectionFactory.GetExecutionAPIClient(p.targetAddress)
 }
 
+func (p *ProxyConnectionFactory) GetExtendedExecutionAPIClient(address string) (extended.ExtendedExecutionAPIClient, io.Closer, error) {
+	return p.ConnectionFactory.GetExtendedExecutionAPIClient(p.targetAddress)
+}
+
 type ConnectionFactoryImpl struct {
 	CollectionGRPCPort        uint
 	ExecutionGRPCPort         uint
@@ -179,6 +185,28 @@ func (cf *ConnectionFactoryImpl) InvalidateAccessAPIClient(address string) {
 }
 
 func (cf *ConnectionFactoryImpl) GetExecutionAPIClient(address string) (execution.ExecutionAPIClient, io.Closer, error) {
+	conn, closer, err := cf.executionConnection(address)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return execution.NewExecutionAPIClient(conn), closer, nil
+}
+
+// GetExtendedExecutionAPIClient returns a client of the extended Execution API, which is served
+// by execution nodes on the same gRPC server as the Execution API.
+func (cf *ConnectionFactoryImpl) GetExtendedExecutionAPIClient(address string) (extended.ExtendedExecutionAPIClient, io.Closer, error) {
+	conn, closer, err := cf.executionConnection(address)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return extended.NewExtendedExecutionAPIClient(conn), closer, nil
+}
+
+// executionConnection returns a connection to the gRPC server of the execution node, taken from
+// the connections cache if enabled, along with the closer releasing it.
+func (cf *ConnectionFactoryImpl) executionConnection(address string) (*grpc.ClientConn, io.Closer, error) {
 
 	grpcAddress, err := getGRPCAddress(address, cf.ExecutionGRPCPort)
 	if err != nil {
@@ -191,7 +219,7 @@ func (cf *ConnectionFactoryImpl) GetExecutionAPIClient(address string) (executio
 		if err != nil {
 			return nil, nil, err
 		}
-		return execution.NewExecutionAPIClient(conn), &noopCloser{}, nil
+		return conn, &noopCloser{}, nil
 	}
 
 	conn, err = cf.createConnection(grpcAddress, cf.ExecutionNodeGRPCTimeout)
@@ -199,9 +227,7 @@ func (cf *ConnectionFactoryImpl) GetExecutionAPIClient(address string) (executio
 		return nil, nil, err
 	}
 
-	executionAPIClient := execution.NewExecutionAPIClient(conn)
-	closer := io.Closer(conn)
-	return executionAPIClient, closer, nil
+	return conn, io.Closer(conn), nil
 }
 
 func (cf *ConnectionFactoryImpl) InvalidateExecutionAPIClient(address string) {
@@ -0,0 +1,381 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/engine/common/rpc/extended"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+)
+
+// latencySmoothing is the weight of the latest observation in the moving averages of the latency
+// and error rate of execution nodes
+const latencySmoothing = 0.2
+
+// errorRatePenalty is the latency added to the score of an execution node which fails all requests.
+// Execution nodes which fail a fraction of the requests are penalized proportionally.
+const errorRatePenalty = time.Second
+
+// ExecutionNodeSelectorConfig configures how execution nodes are selected for requests.
+type ExecutionNodeSelectorConfig struct {
+	// HedgeDelay is the time to wait for a response from an execution node before sending the same
+	// request to the next one, for scripts and account queries. Hedging is disabled if zero.
+	HedgeDelay time.Duration
+	// FailureThreshold is the number of consecutive failed requests after which requests to an
+	// execution node are suspended. Suspending is disabled if zero.
+	FailureThreshold uint
+	// SuspensionDuration is the time requests to an execution node are suspended for. Once it
+	// elapsed, requests are sent to the execution node again, and it is suspended again on the
+	// first failure.
+	SuspensionDuration time.Duration
+	// MaxHeightLag is the number of blocks the highest executed height of an execution node may lag
+	// behind the highest executed height of all execution nodes, before it is only selected if no
+	// other execution node is available.
+	MaxHeightLag uint64
+}
+
+// DefaultExecutionNodeSelectorConfig returns the default execution node selector config.
+func DefaultExecutionNodeSelectorConfig() ExecutionNodeSelectorConfig {
+	return ExecutionNodeSelectorConfig{
+		HedgeDelay:         500 * time.Millisecond,
+		FailureThreshold:   5,
+		SuspensionDuration: 30 * time.Second,
+		MaxHeightLag:       10,
+	}
+}
+
+// ExecutionNodeScore is the health of an execution node as observed by the access node.
+type ExecutionNodeScore struct {
+	NodeID flow.Identifier
+	// Latency is the moving average of the latency of requests.
+	Latency time.Duration
+	// ErrorRate is the moving average of the fraction of failed requests.
+	ErrorRate           float64
+	Requests            uint64
+	Failures            uint64
+	ConsecutiveFailures uint
+	// SuspendedUntil is the time until which requests are suspended, zero if they never were.
+	SuspendedUntil time.Time
+	// ExecutedHeight is the highest block height the execution node sent a receipt for.
+	ExecutedHeight uint64
+}
+
+// Suspended returns whether requests to the execution node are suspended at the given time.
+func (s ExecutionNodeScore) Suspended(now time.Time) bool {
+	return now.Before(s.SuspendedUntil)
+}
+
+// ExecutionNodeSelector orders the execution nodes requests are sent to by their health, tracked
+// from the latency and outcome of previous requests and from the execution receipts they sent.
+// Requests to execution nodes failing repeatedly are suspended for a while.
+type ExecutionNodeSelector struct {
+	mu      sync.Mutex
+	config  ExecutionNodeSelectorConfig
+	metrics module.ExecutionNodeHealthMetrics
+	scores  map[flow.Identifier]*ExecutionNodeScore
+	// highest block height any execution node sent a receipt for
+	highestExecutedHeight uint64
+	now                   func() time.Time
+}
+
+// NewExecutionNodeSelector creates a new execution node selector.
+func NewExecutionNodeSelector(config ExecutionNodeSelectorConfig, metrics module.ExecutionNodeHealthMetrics) *ExecutionNodeSelector {
+	return &ExecutionNodeSelector{
+		config:  config,
+		metrics: metrics,
+		scores:  make(map[flow.Identifier]*ExecutionNodeScore),
+		now:     time.Now,
+	}
+}
+
+// Select returns up to count of the given execution nodes, healthiest first. Execution nodes for
+// which requests are suspended are only returned if requests to all given nodes are suspended.
+func (s *ExecutionNodeSelector) Select(nodes flow.IdentityList, count uint) flow.IdentityList {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	available := make(flow.IdentityList, 0, len(nodes))
+	for _, node := range nodes {
+		if !s.lookup(node.NodeID).Suspended(now) {
+			available = append(available, node)
+		}
+	}
+	if len(available) == 0 {
+		// there's nothing to lose by trying
+		available = append(available, nodes...)
+	}
+
+	// shuffle first, so that the load is spread across equally healthy execution nodes
+	rand.Shuffle(len(available), func(i, j int) {
+		available[i], available[j] = available[j], available[i]
+	})
+	sort.SliceStable(available, func(i, j int) bool {
+		laggingI := s.lagging(available[i].NodeID)
+		laggingJ := s.lagging(available[j].NodeID)
+		if laggingI != laggingJ {
+			return laggingJ
+		}
+		return s.penalty(available[i].NodeID) < s.penalty(available[j].NodeID)
+	})
+
+	if uint(len(available)) > count {
+		available = available[:count]
+	}
+	return available
+}
+
+// ReportRequest updates the health of the execution node from the latency and outcome of a request
+// sent to it. Failed is true if the request failed because of the execution node, e.g. it was
+// unavailable, and false if it succeeded or was rejected because of the request itself.
+func (s *ExecutionNodeSelector) ReportRequest(nodeID flow.Identifier, latency time.Duration, failed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	score := s.score(nodeID)
+	failure := 0.0
+	if failed {
+		failure = 1
+	}
+	if score.Requests == 0 {
+		score.Latency = latency
+		score.ErrorRate = failure
+	} else {
+		score.Latency = time.Duration((1-latencySmoothing)*float64(score.Latency) + latencySmoothing*float64(latency))
+		score.ErrorRate = (1-latencySmoothing)*score.ErrorRate + latencySmoothing*failure
+	}
+	score.Requests++
+
+	now := s.now()
+	if failed {
+		score.Failures++
+		score.ConsecutiveFailures++
+		if s.config.FailureThreshold > 0 && score.ConsecutiveFailures >= s.config.FailureThreshold {
+			score.SuspendedUntil = now.Add(s.config.SuspensionDuration)
+		}
+	} else {
+		score.ConsecutiveFailures = 0
+		score.SuspendedUntil = time.Time{}
+	}
+
+	s.metrics.ExecutionNodeRequest(nodeID, latency, failed)
+	s.metrics.ExecutionNodeScore(nodeID, score.Latency, score.ErrorRate, score.Suspended(now))
+}
+
+// ReportExecutedHeight updates the highest block height the execution node sent a receipt for.
+func (s *ExecutionNodeSelector) ReportExecutedHeight(nodeID flow.Identifier, height uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	score := s.score(nodeID)
+	if height <= score.ExecutedHeight {
+		return
+	}
+	score.ExecutedHeight = height
+	if height > s.highestExecutedHeight {
+		s.highestExecutedHeight = height
+	}
+
+	s.metrics.ExecutionNodeExecutedHeight(nodeID, height)
+}
+
+// Scoreboard returns the health of all execution nodes requests were sent to or receipts were
+// received from, ordered by node ID.
+func (s *ExecutionNodeSelector) Scoreboard() []ExecutionNodeScore {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	scores := make([]ExecutionNodeScore, 0, len(s.scores))
+	for _, score := range s.scores {
+		scores = append(scores, *score)
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		return scores[i].NodeID.String() < scores[j].NodeID.String()
+	})
+	return scores
+}
+
+// Now returns the current time, as used to determine whether requests are suspended.
+func (s *ExecutionNodeSelector) Now() time.Time {
+	return s.now()
+}
+
+// score returns the score of the given execution node, creating it if needed.
+// The caller must hold the lock.
+func (s *ExecutionNodeSelector) score(nodeID flow.Identifier) *ExecutionNodeScore {
+	score, ok := s.scores[nodeID]
+	if !ok {
+		score = &ExecutionNodeScore{NodeID: nodeID}
+		s.scores[nodeID] = score
+	}
+	return score
+}
+
+// lookup returns the score of the given execution node, or an empty score if it is not tracked.
+// The caller must hold the lock.
+func (s *ExecutionNodeSelector) lookup(nodeID flow.Identifier) ExecutionNodeScore {
+	score, ok := s.scores[nodeID]
+	if !ok {
+		return ExecutionNodeScore{NodeID: nodeID}
+	}
+	return *score
+}
+
+// lagging returns whether the highest executed height of the given execution node lags too far
+// behind. Execution nodes which never sent a receipt are not considered lagging.
+// The caller must hold the lock.
+func (s *ExecutionNodeSelector) lagging(nodeID flow.Identifier) bool {
+	score := s.lookup(nodeID)
+	return score.ExecutedHeight > 0 && s.highestExecutedHeight-score.ExecutedHeight > s.config.MaxHeightLag
+}
+
+// penalty returns the expected latency of the given execution node, penalized by its error rate.
+// Execution nodes no request was sent to yet have no penalty, so they are tried first.
+// The caller must hold the lock.
+func (s *ExecutionNodeSelector) penalty(nodeID flow.Identifier) time.Duration {
+	score := s.lookup(nodeID)
+	return score.Latency + time.Duration(score.ErrorRate*float64(errorRatePenalty))
+}
+
+// hedgedResponse is the response of an execution node to a hedged request.
+type hedgedResponse[T any] struct {
+	node  *flow.Identity
+	value T
+	err   error
+}
+
+// executeHedged sends a request to the given execution nodes in order, and returns the first
+// successful response. The request is sent to the next execution node once the previous one
+// failed, or if the selector is configured with a hedge delay, once the previous one did not
+// respond within the delay. Requests still in flight are canceled once a response is returned.
+// Errors for which isFinal returns true are caused by the request rather than the execution node,
+// so they are returned immediately without trying the remaining execution nodes.
+// If the selector is nil, requests are sent sequentially and the health is not tracked.
+// Returns the execution node which responded, and the errors of all execution nodes if all failed.
+func executeHedged[T any](
+	ctx context.Context,
+	selector *ExecutionNodeSelector,
+	nodes flow.IdentityList,
+	request func(context.Context, *flow.Identity) (T, error),
+	isFinal func(error) bool,
+) (T, *flow.Identity, error) {
+	var zero T
+	if len(nodes) == 0 {
+		return zero, nil, fmt.Errorf("no execution node to send the request to")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	responses := make(chan hedgedResponse[T], len(nodes))
+	send := func(node *flow.Identity) {
+		go func() {
+			start := time.Now()
+			value, err := request(ctx, node)
+			// requests canceled because another execution node responded first say nothing about
+			// the health of this one
+			if selector != nil && ctx.Err() == nil {
+				selector.ReportRequest(node.NodeID, time.Since(start), isExecutionNodeFailure(err))
+			}
+			responses <- hedgedResponse[T]{node: node, value: value, err: err}
+		}()
+	}
+
+	var hedgeDelay time.Duration
+	if selector != nil {
+		hedgeDelay = selector.config.HedgeDelay
+	}
+
+	var errs *multierror.Error
+	next := 0
+	inFlight := 0
+	for next < len(nodes) || inFlight > 0 {
+		if inFlight == 0 {
+			send(nodes[next])
+			next++
+			inFlight++
+		}
+
+		var hedge <-chan time.Time
+		var timer *time.Timer
+		if hedgeDelay > 0 && next < len(nodes) {
+			timer = time.NewTimer(hedgeDelay)
+			hedge = timer.C
+		}
+
+		select {
+		case response := <-responses:
+			inFlight--
+			if response.err == nil || isFinal(response.err) {
+				stopTimer(timer)
+				return response.value, response.node, response.err
+			}
+			errs = multierror.Append(errs, response.err)
+		case <-hedge:
+			send(nodes[next])
+			next++
+			inFlight++
+			selector.metrics.HedgedRequestSent()
+		}
+		stopTimer(timer)
+	}
+
+	return zero, nil, errs.ErrorOrNil()
+}
+
+// tryExtendedExecutionAPI sends the request to the extended Execution API of the execution node,
+// invalidating the connection to the execution node if it is unavailable.
+func tryExtendedExecutionAPI[T any](
+	ctx context.Context,
+	connFactory ConnectionFactory,
+	execNode *flow.Identity,
+	request func(context.Context, extended.ExtendedExecutionAPIClient) (T, error),
+) (T, error) {
+	var zero T
+
+	client, closer, err := connFactory.GetExtendedExecutionAPIClient(execNode.Address)
+	if err != nil {
+		return zero, err
+	}
+	defer closer.Close()
+
+	resp, err := request(ctx, client)
+	if err != nil {
+		if status.Code(err) == codes.Unavailable {
+			connFactory.InvalidateExecutionAPIClient(execNode.Address)
+		}
+		return zero, err
+	}
+	return resp, nil
+}
+
+// isExecutionNodeFailure returns whether the given request error is caused by the execution node,
+// rather than by the request itself. Execution nodes which do not serve the extended Execution API
+// yet are not considered failing.
+func isExecutionNodeFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	switch status.Code(err) {
+	case codes.InvalidArgument, codes.NotFound, codes.OutOfRange, codes.FailedPrecondition, codes.Canceled, codes.Unimplemented:
+		return false
+	default:
+		return true
+	}
+}
+
+// stopTimer stops the given timer, if any.
+func stopTimer(timer *time.Timer) {
+	if timer != nil {
+		timer.Stop()
+	}
+}
@@ -0,0 +1,157 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func newTestSelector(config ExecutionNodeSelectorConfig) (*ExecutionNodeSelector, *time.Time) {
+	selector := NewExecutionNodeSelector(config, metrics.NewNoopCollector())
+	now := time.Now()
+	selector.now = func() time.Time { return now }
+	return selector, &now
+}
+
+// TestExecutionNodeSelector_OrdersByHealth tests that execution nodes are ordered by latency and
+// error rate, and that lagging execution nodes are ordered last.
+func TestExecutionNodeSelector_OrdersByHealth(t *testing.T) {
+	selector, _ := newTestSelector(DefaultExecutionNodeSelectorConfig())
+	nodes := unittest.IdentityListFixture(4, unittest.WithRole(flow.RoleExecution))
+	fast, slow, failing, lagging := nodes[0], nodes[1], nodes[2], nodes[3]
+
+	selector.ReportRequest(fast.NodeID, 10*time.Millisecond, false)
+	selector.ReportRequest(slow.NodeID, 200*time.Millisecond, false)
+	selector.ReportRequest(failing.NodeID, 10*time.Millisecond, true)
+	selector.ReportRequest(lagging.NodeID, time.Millisecond, false)
+
+	selector.ReportExecutedHeight(fast.NodeID, 100)
+	selector.ReportExecutedHeight(lagging.NodeID, 100-DefaultExecutionNodeSelectorConfig().MaxHeightLag-1)
+
+	selected := selector.Select(nodes, 4)
+	assert.Equal(t, flow.IdentityList{fast, slow, failing, lagging}, selected)
+
+	// only the healthiest are returned
+	selected = selector.Select(nodes, 2)
+	assert.Equal(t, flow.IdentityList{fast, slow}, selected)
+}
+
+// TestExecutionNodeSelector_Suspension tests that requests to execution nodes failing repeatedly are
+// suspended, unless all execution nodes are suspended.
+func TestExecutionNodeSelector_Suspension(t *testing.T) {
+	config := DefaultExecutionNodeSelectorConfig()
+	selector, now := newTestSelector(config)
+	nodes := unittest.IdentityListFixture(2, unittest.WithRole(flow.RoleExecution))
+	healthy, failing := nodes[0], nodes[1]
+
+	for i := uint(0); i < config.FailureThreshold; i++ {
+		assert.Len(t, selector.Select(nodes, 2), 2)
+		selector.ReportRequest(failing.NodeID, time.Millisecond, true)
+	}
+
+	assert.Equal(t, flow.IdentityList{healthy}, selector.Select(nodes, 2))
+	// all nodes are suspended, so try them anyway
+	assert.Equal(t, flow.IdentityList{failing}, selector.Select(flow.IdentityList{failing}, 2))
+
+	// the suspension ends after the configured duration
+	*now = now.Add(config.SuspensionDuration)
+	assert.Len(t, selector.Select(nodes, 2), 2)
+
+	// the first failure suspends the execution node again
+	selector.ReportRequest(failing.NodeID, time.Millisecond, true)
+	assert.Equal(t, flow.IdentityList{healthy}, selector.Select(nodes, 2))
+
+	// a success lifts the suspension
+	selector.ReportRequest(failing.NodeID, time.Millisecond, false)
+	assert.Len(t, selector.Select(nodes, 2), 2)
+
+	scores := selector.Scoreboard()
+	require.Len(t, scores, 1)
+	assert.Equal(t, failing.NodeID, scores[0].NodeID)
+	assert.Equal(t, uint64(config.FailureThreshold+2), scores[0].Requests)
+	assert.Equal(t, uint64(config.FailureThreshold+1), scores[0].Failures)
+	assert.Equal(t, uint(0), scores[0].ConsecutiveFailures)
+}
+
+// TestExecuteHedged tests that requests are sent to the next execution node if the previous one
+// fails or does not respond in time.
+func TestExecuteHedged(t *testing.T) {
+	nodes := unittest.IdentityListFixture(3, unittest.WithRole(flow.RoleExecution))
+	isFinal := func(err error) bool { return status.Code(err) == codes.InvalidArgument }
+
+	t.Run("failover", func(t *testing.T) {
+		selector, _ := newTestSelector(ExecutionNodeSelectorConfig{})
+		value, node, err := executeHedged(context.Background(), selector, nodes,
+			func(_ context.Context, node *flow.Identity) (int, error) {
+				if node == nodes[2] {
+					return 2, nil
+				}
+				return 0, status.Error(codes.Unavailable, "unavailable")
+			}, isFinal)
+		require.NoError(t, err)
+		assert.Equal(t, 2, value)
+		assert.Equal(t, nodes[2], node)
+
+		scores := selector.Scoreboard()
+		require.Len(t, scores, 3)
+		for _, score := range scores {
+			assert.Equal(t, score.NodeID != nodes[2].NodeID, score.ConsecutiveFailures == 1)
+		}
+	})
+
+	t.Run("final error", func(t *testing.T) {
+		var mu sync.Mutex
+		requested := 0
+		_, _, err := executeHedged(context.Background(), nil, nodes,
+			func(_ context.Context, node *flow.Identity) (int, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				requested++
+				return 0, status.Error(codes.InvalidArgument, "invalid script")
+			}, isFinal)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Equal(t, 1, requested)
+	})
+
+	t.Run("all failed", func(t *testing.T) {
+		_, _, err := executeHedged(context.Background(), nil, nodes,
+			func(_ context.Context, node *flow.Identity) (int, error) {
+				return 0, errors.New("failed")
+			}, isFinal)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "3 errors occurred")
+	})
+
+	t.Run("hedging", func(t *testing.T) {
+		selector, _ := newTestSelector(ExecutionNodeSelectorConfig{HedgeDelay: 10 * time.Millisecond})
+		value, node, err := executeHedged(context.Background(), selector, nodes,
+			func(ctx context.Context, node *flow.Identity) (int, error) {
+				if node == nodes[0] {
+					// never responds in time
+					<-ctx.Done()
+					return 0, status.Error(codes.Canceled, ctx.Err().Error())
+				}
+				return 1, nil
+			}, isFinal)
+		require.NoError(t, err)
+		assert.Equal(t, 1, value)
+		assert.Equal(t, nodes[1], node)
+
+		// the canceled request does not count against the execution node
+		time.Sleep(10 * time.Millisecond)
+		for _, score := range selector.Scoreboard() {
+			assert.Equal(t, uint64(0), score.Failures)
+		}
+	})
+}
@@ -7,6 +7,8 @@ import (
 
 	execution "github.com/onflow/flow/protobuf/go/flow/execution"
 
+	extended "github.com/onflow/flow-go/engine/common/rpc/extended"
+
 	io "io"
 
 	mock "github.com/stretchr/testify/mock"
@@ -87,6 +89,41 @@ func (_m *ConnectionFactory) GetExecutionAPIClient(address string) (execution.Ex
 	return r0, r1, r2
 }
 
+// GetExtendedExecutionAPIClient provides a mock function with given fields: address
+func (_m *ConnectionFactory) GetExtendedExecutionAPIClient(address string) (extended.ExtendedExecutionAPIClient, io.Closer, error) {
+	ret := _m.Called(address)
+
+	var r0 extended.ExtendedExecutionAPIClient
+	var r1 io.Closer
+	var r2 error
+	if rf, ok := ret.Get(0).(func(string) (extended.ExtendedExecutionAPIClient, io.Closer, error)); ok {
+		return rf(address)
+	}
+	if rf, ok := ret.Get(0).(func(string) extended.ExtendedExecutionAPIClient); ok {
+		r0 = rf(address)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(extended.ExtendedExecutionAPIClient)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) io.Closer); ok {
+		r1 = rf(address)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(io.Closer)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(string) error); ok {
+		r2 = rf(address)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // InvalidateAccessAPIClient provides a mock function with given fields: address
 func (_m *ConnectionFactory) InvalidateAccessAPIClient(address string) {
 	_m.Called(address)
@@ -3,6 +3,8 @@ package backend
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/onflow/flow-go/model/flow"
@@ -13,14 +15,23 @@ import (
 const retryFrequency uint64 = 120 // blocks
 
 // Retry implements a simple retry mechanism for transaction submission.
+// If a storage is configured, pending transactions are persisted so that they are still retried
+// after a restart.
 type Retry struct {
 	mu sync.RWMutex
 	// pending transactions
 	transactionByReferencBlockHeight map[uint64]map[flow.Identifier]*flow.TransactionBody
 	backend                          *Backend
+	store                            storage.TransactionRetries // optional
 	active                           bool
 }
 
+// PendingTransaction is a transaction pending in the retry mechanism.
+type PendingTransaction struct {
+	TransactionID   flow.Identifier
+	ReferenceHeight uint64
+}
+
 func newRetry() *Retry {
 	return &Retry{
 		transactionByReferencBlockHeight: map[uint64]map[flow.Identifier]*flow.TransactionBody{},
@@ -41,6 +52,46 @@ func (r *Retry) SetBackend(b *Backend) *Retry {
 	return r
 }
 
+// SetStorage configures the storage pending transactions are persisted in, and loads the
+// transactions persisted previously. Must be called after SetBackend, before the first retry.
+// No errors are expected during normal operations.
+func (r *Retry) SetStorage(store storage.TransactionRetries) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.store = store
+
+	pending, err := store.All()
+	if err != nil {
+		return fmt.Errorf("could not load pending transactions: %w", err)
+	}
+
+	for height, txIDs := range pending {
+		for _, txID := range txIDs {
+			tx, err := r.backend.transactions.ByID(txID)
+			if err != nil {
+				if !errors.Is(err, storage.ErrNotFound) {
+					return fmt.Errorf("could not retrieve pending transaction %v: %w", txID, err)
+				}
+				// the transaction body is gone, so there is nothing we could resubmit
+				err = store.Remove(height, txID)
+				if err != nil {
+					return fmt.Errorf("could not remove pending transaction %v: %w", txID, err)
+				}
+				continue
+			}
+
+			if r.transactionByReferencBlockHeight[height] == nil {
+				r.transactionByReferencBlockHeight[height] = make(map[flow.Identifier]*flow.TransactionBody)
+			}
+			r.transactionByReferencBlockHeight[height][txID] = tx
+		}
+	}
+
+	r.backend.transactionMetrics.TransactionRetryQueueSize(r.size())
+	return nil
+}
+
 func (r *Retry) Retry(height uint64) {
 	// No need to retry if height is lower than DefaultTransactionExpiry
 	if height < flow.DefaultTransactionExpiry {
@@ -78,7 +129,87 @@ func (r *Retry) RegisterTransaction(height uint64, tx *flow.TransactionBody) {
 	if r.transactionByReferencBlockHeight[height] == nil {
 		r.transactionByReferencBlockHeight[height] = make(map[flow.Identifier]*flow.TransactionBody)
 	}
-	r.transactionByReferencBlockHeight[height][tx.ID()] = tx
+	txID := tx.ID()
+	if _, ok := r.transactionByReferencBlockHeight[height][txID]; ok {
+		return
+	}
+	r.transactionByReferencBlockHeight[height][txID] = tx
+
+	if r.store != nil {
+		err := r.store.Add(height, txID)
+		if err != nil {
+			// the transaction is still retried, unless the node restarts
+			r.backend.backendTransactions.log.Error().Err(err).
+				Hex("tx_id", txID[:]).
+				Msg("failed to persist transaction for retry")
+		}
+	}
+
+	r.backend.transactionMetrics.TransactionRetryQueued()
+	r.backend.transactionMetrics.TransactionRetryQueueSize(r.size())
+}
+
+// Pending returns the transactions pending in the retry mechanism, ordered by reference block height.
+func (r *Retry) Pending() []PendingTransaction {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	pending := make([]PendingTransaction, 0, r.size())
+	for height, txs := range r.transactionByReferencBlockHeight {
+		for txID := range txs {
+			pending = append(pending, PendingTransaction{
+				TransactionID:   txID,
+				ReferenceHeight: height,
+			})
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		if pending[i].ReferenceHeight != pending[j].ReferenceHeight {
+			return pending[i].ReferenceHeight < pending[j].ReferenceHeight
+		}
+		return pending[i].TransactionID.String() < pending[j].TransactionID.String()
+	})
+
+	return pending
+}
+
+// Drop removes the transaction with the given ID from the retry mechanism, so it won't be retried
+// anymore. Returns false if the transaction is not pending.
+// No errors are expected during normal operations.
+func (r *Retry) Drop(txID flow.Identifier) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for height, txs := range r.transactionByReferencBlockHeight {
+		if _, ok := txs[txID]; !ok {
+			continue
+		}
+
+		if r.store != nil {
+			err := r.store.Remove(height, txID)
+			if err != nil {
+				return false, fmt.Errorf("could not remove pending transaction %v: %w", txID, err)
+			}
+		}
+
+		delete(txs, txID)
+		if len(txs) == 0 {
+			delete(r.transactionByReferencBlockHeight, height)
+		}
+		r.backend.transactionMetrics.TransactionRetryQueueSize(r.size())
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// size returns the number of pending transactions. The caller must hold the lock.
+func (r *Retry) size() int {
+	size := 0
+	for _, txs := range r.transactionByReferencBlockHeight {
+		size += len(txs)
+	}
+	return size
 }
 
 func (r *Retry) prune(height uint64) {
@@ -88,11 +219,34 @@ func (r *Retry) prune(height uint64) {
 	if height < flow.DefaultTransactionExpiry {
 		return
 	}
-	for h := range r.transactionByReferencBlockHeight {
+	expired := 0
+	for h, txs := range r.transactionByReferencBlockHeight {
 		if h < height-flow.DefaultTransactionExpiry {
+			for txID := range txs {
+				r.unpersist(h, txID)
+			}
+			expired += len(txs)
 			delete(r.transactionByReferencBlockHeight, h)
 		}
 	}
+	if expired > 0 {
+		r.backend.transactionMetrics.TransactionRetryExpired(expired)
+		r.backend.transactionMetrics.TransactionRetryQueueSize(r.size())
+	}
+}
+
+// unpersist removes the given transaction from the storage, if configured.
+func (r *Retry) unpersist(height uint64, txID flow.Identifier) {
+	if r.store == nil {
+		return
+	}
+	err := r.store.Remove(height, txID)
+	if err != nil {
+		// the transaction is loaded again after a restart, and pruned then
+		r.backend.backendTransactions.log.Error().Err(err).
+			Hex("tx_id", txID[:]).
+			Msg("failed to remove persisted transaction from retry")
+	}
 }
 
 func (r *Retry) retryTxsAtHeight(heightToRetry uint64) {
@@ -115,10 +269,15 @@ func (r *Retry) retryTxsAtHeight(heightToRetry uint64) {
 			continue
 		}
 		if status == flow.TransactionStatusPending {
-			_ = r.backend.SendRawTransaction(context.Background(), tx)
+			err = r.backend.SendRawTransaction(context.Background(), tx)
+			if err == nil {
+				r.backend.transactionMetrics.TransactionRetried()
+			}
 		} else if status != flow.TransactionStatusUnknown {
 			// not pending or unknown, don't need to retry anymore
+			r.unpersist(heightToRetry, txID)
 			delete(txsAtHeight, txID)
+			r.backend.transactionMetrics.TransactionRetryQueueSize(r.size())
 		}
 	}
 }
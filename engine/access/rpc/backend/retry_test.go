@@ -14,6 +14,7 @@ import (
 	"github.com/onflow/flow-go/module/metrics"
 	protocol "github.com/onflow/flow-go/state/protocol/mock"
 	realstorage "github.com/onflow/flow-go/storage"
+	storagemock "github.com/onflow/flow-go/storage/mock"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
@@ -175,3 +176,89 @@ func (suite *Suite) TestSuccessfulTransactionsDontRetry() {
 
 	suite.assertAllExpectations()
 }
+
+// TestTransactionRetryPersisted tests that pending transactions are persisted until they expire
+func (suite *Suite) TestTransactionRetryPersisted() {
+	transactionBody := unittest.TransactionBodyFixture()
+	txID := transactionBody.ID()
+	height := uint64(flow.DefaultTransactionExpiry + 1)
+
+	store := storagemock.NewTransactionRetries(suite.T())
+	store.On("All").Return(map[uint64][]flow.Identifier{}, nil).Once()
+
+	backend := suite.retryBackend()
+	retry := newRetry().SetBackend(backend).Activate()
+	suite.Require().NoError(retry.SetStorage(store))
+
+	store.On("Add", height, txID).Return(nil).Once()
+	retry.RegisterTransaction(height, &transactionBody)
+
+	// registering the same transaction again is a no-op
+	retry.RegisterTransaction(height, &transactionBody)
+
+	suite.Assert().Equal([]PendingTransaction{{TransactionID: txID, ReferenceHeight: height}}, retry.Pending())
+
+	// the transaction is removed from storage once it expired
+	store.On("Remove", height, txID).Return(nil).Once()
+	retry.prune(height + flow.DefaultTransactionExpiry + 1)
+
+	suite.Assert().Empty(retry.Pending())
+}
+
+// TestTransactionRetryResume tests that persisted transactions are retried after a restart
+func (suite *Suite) TestTransactionRetryResume() {
+	transactionBody := unittest.TransactionBodyFixture()
+	txID := transactionBody.ID()
+	missingTxID := unittest.IdentifierFixture()
+	height := uint64(flow.DefaultTransactionExpiry + 1)
+
+	store := storagemock.NewTransactionRetries(suite.T())
+	store.On("All").Return(map[uint64][]flow.Identifier{height: {txID, missingTxID}}, nil).Once()
+
+	suite.transactions.On("ByID", txID).Return(&transactionBody, nil).Once()
+	suite.transactions.On("ByID", missingTxID).Return(nil, realstorage.ErrNotFound).Once()
+	// transactions which are not stored anymore can't be retried, so they are dropped
+	store.On("Remove", height, missingTxID).Return(nil).Once()
+
+	backend := suite.retryBackend()
+	retry := newRetry().SetBackend(backend).Activate()
+	suite.Require().NoError(retry.SetStorage(store))
+
+	suite.Assert().Equal([]PendingTransaction{{TransactionID: txID, ReferenceHeight: height}}, retry.Pending())
+
+	// dropping a transaction removes it from storage
+	store.On("Remove", height, txID).Return(nil).Once()
+	dropped, err := retry.Drop(txID)
+	suite.Require().NoError(err)
+	suite.Assert().True(dropped)
+	suite.Assert().Empty(retry.Pending())
+
+	// dropping a transaction which is not pending is a no-op
+	dropped, err = retry.Drop(txID)
+	suite.Require().NoError(err)
+	suite.Assert().False(dropped)
+
+	suite.assertAllExpectations()
+}
+
+func (suite *Suite) retryBackend() *Backend {
+	return New(suite.state,
+		suite.colClient,
+		nil,
+		suite.blocks,
+		suite.headers,
+		suite.collections,
+		suite.transactions,
+		suite.receipts,
+		suite.results,
+		suite.chainID,
+		metrics.NewNoopCollector(),
+		nil,
+		false,
+		DefaultMaxHeightRange,
+		nil,
+		nil,
+		suite.log,
+		DefaultSnapshotHistoryLimit,
+	)
+}
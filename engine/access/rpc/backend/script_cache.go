@@ -0,0 +1,142 @@
+package backend
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/onflow/flow-go/engine/common/rpc"
+	"github.com/onflow/flow-go/fvm/environment"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	herocache "github.com/onflow/flow-go/module/mempool/herocache/backdata"
+	"github.com/onflow/flow-go/module/mempool/herocache/backdata/heropool"
+)
+
+// maxCachedScriptResultSize is the size of the largest script result which is cached, so that a few
+// large results can't take up a disproportionate amount of memory.
+const maxCachedScriptResultSize = 100 * 1024 // 100 KB
+
+// nonDeterministicComputationKinds are the computation kinds metered by the built-in functions
+// whose results may differ between executions of the same script at the same block. Results of
+// scripts calling them are not cached.
+var nonDeterministicComputationKinds = []common.ComputationKind{
+	environment.ComputationKindUnsafeRandom,
+}
+
+// ScriptResultCache caches the results of scripts executed against sealed blocks. Results are keyed
+// by the block ID, the script and its arguments, and the least recently used results are ejected
+// once the cache is full.
+//
+// Results are only correct to cache if the script is deterministic. Whether a script called
+// non-deterministic built-in functions such as unsafeRandom, directly or through contracts, is
+// decided after its execution from the computation intensities metered by the FVM, so results are
+// only cached along with the computation and memory used by the script.
+type ScriptResultCache struct {
+	cache *herocache.Cache
+}
+
+// NewScriptResultCache creates a new script result cache holding up to sizeLimit results.
+func NewScriptResultCache(sizeLimit uint32, logger zerolog.Logger, collector module.HeroCacheMetrics) *ScriptResultCache {
+	return &ScriptResultCache{
+		cache: herocache.NewCache(
+			sizeLimit,
+			herocache.DefaultOversizeFactor,
+			heropool.LRUEjection,
+			logger.With().Str("mempool", "script-result-cache").Logger(),
+			collector),
+	}
+}
+
+// Get returns the cached result of the script with the given arguments executed at the given
+// block, if any.
+func (c *ScriptResultCache) Get(blockID flow.Identifier, script []byte, arguments [][]byte) ([]byte, bool) {
+	entity, ok := c.cache.ByID(scriptResultKey(blockID, script, arguments))
+	if !ok {
+		return nil, false
+	}
+	return entity.(scriptResult).value, true
+}
+
+// Add caches the result of the script with the given arguments executed at the given block, given
+// the computation and memory used by the script as a response trailer. The block must be sealed.
+// Results larger than maxCachedScriptResultSize, results without usage, and results of scripts
+// calling non-deterministic functions, are not cached.
+func (c *ScriptResultCache) Add(blockID flow.Identifier, script []byte, arguments [][]byte, value []byte, usage metadata.MD) {
+	if len(value) > maxCachedScriptResultSize || !isDeterministicUsage(usage) {
+		return
+	}
+	key := scriptResultKey(blockID, script, arguments)
+	_ = c.cache.Add(key, scriptResult{id: key, value: value})
+}
+
+// Clear removes all cached results, and returns the number of results removed.
+func (c *ScriptResultCache) Clear() uint {
+	size := c.cache.Size()
+	c.cache.Clear()
+	return size
+}
+
+// Size returns the number of cached results.
+func (c *ScriptResultCache) Size() uint {
+	return c.cache.Size()
+}
+
+// isDeterministicUsage returns whether the given usage of a script shows that it did not meter
+// any of the nonDeterministicComputationKinds. Returns false if the usage is missing, e.g. because
+// the execution node did not send it.
+func isDeterministicUsage(usage metadata.MD) bool {
+	if len(usage.Get(rpc.ComputationUsedTrailer)) == 0 {
+		return false
+	}
+	for _, intensity := range usage.Get(rpc.ComputationIntensitiesTrailer) {
+		for _, kind := range nonDeterministicComputationKinds {
+			// intensities are formatted as "<kind>=<intensity>"
+			if strings.HasPrefix(intensity, fmt.Sprintf("%s=", kind)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// scriptResultKey returns the cache key of the script with the given arguments executed at the
+// given block. The script and the arguments are length-prefixed, so that different requests can't
+// result in the same key.
+func scriptResultKey(blockID flow.Identifier, script []byte, arguments [][]byte) flow.Identifier {
+	size := flow.IdentifierLen + 8 + len(script)
+	for _, arg := range arguments {
+		size += 8 + len(arg)
+	}
+
+	fingerprint := make([]byte, 0, size)
+	fingerprint = append(fingerprint, blockID[:]...)
+	fingerprint = binary.BigEndian.AppendUint64(fingerprint, uint64(len(script)))
+	fingerprint = append(fingerprint, script...)
+	for _, arg := range arguments {
+		fingerprint = binary.BigEndian.AppendUint64(fingerprint, uint64(len(arg)))
+		fingerprint = append(fingerprint, arg...)
+	}
+
+	return flow.MakeIDFromFingerPrint(fingerprint)
+}
+
+// scriptResult is the cached result of a script.
+type scriptResult struct {
+	id    flow.Identifier
+	value []byte
+}
+
+var _ flow.Entity = scriptResult{}
+
+func (s scriptResult) ID() flow.Identifier {
+	return s.id
+}
+
+func (s scriptResult) Checksum() flow.Identifier {
+	return s.id
+}
@@ -0,0 +1,91 @@
+package backend
+
+import (
+	"testing"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine/common/rpc"
+	"github.com/onflow/flow-go/fvm/environment"
+	"github.com/onflow/flow-go/fvm/meter"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestScriptResultCache tests that script results are cached by block ID, script and arguments.
+func TestScriptResultCache(t *testing.T) {
+	cache := NewScriptResultCache(10, zerolog.Nop(), metrics.NewNoopCollector())
+	blockID := unittest.IdentifierFixture()
+	script := []byte("pub fun main(a: Int): Int { return a }")
+	args := [][]byte{[]byte("1")}
+	usage := rpc.ScriptUsageTrailer(meter.Usage{ComputationUsed: 1})
+
+	_, ok := cache.Get(blockID, script, args)
+	assert.False(t, ok)
+
+	cache.Add(blockID, script, args, []byte("result"), usage)
+	value, ok := cache.Get(blockID, script, args)
+	require.True(t, ok)
+	assert.Equal(t, []byte("result"), value)
+
+	// results of other blocks, scripts or arguments are not returned
+	_, ok = cache.Get(unittest.IdentifierFixture(), script, args)
+	assert.False(t, ok)
+	_, ok = cache.Get(blockID, []byte("pub fun main(): Int { return 1 }"), args)
+	assert.False(t, ok)
+	_, ok = cache.Get(blockID, script, [][]byte{[]byte("2")})
+	assert.False(t, ok)
+
+	// results which are too large are not cached
+	cache.Add(blockID, script, nil, make([]byte, maxCachedScriptResultSize+1), usage)
+	_, ok = cache.Get(blockID, script, nil)
+	assert.False(t, ok)
+
+	assert.Equal(t, uint(1), cache.Size())
+	assert.Equal(t, uint(1), cache.Clear())
+	assert.Equal(t, uint(0), cache.Size())
+	_, ok = cache.Get(blockID, script, args)
+	assert.False(t, ok)
+}
+
+// TestScriptResultCacheNonDeterministicScripts tests that results of scripts which metered
+// non-deterministic functions, or whose usage is unknown, are not cached.
+func TestScriptResultCacheNonDeterministicScripts(t *testing.T) {
+	cache := NewScriptResultCache(10, zerolog.Nop(), metrics.NewNoopCollector())
+	blockID := unittest.IdentifierFixture()
+	script := []byte("import Lottery from 0x01\npub fun main(): UInt64 { return Lottery.draw() }")
+
+	// the script calls unsafeRandom through the imported contract
+	cache.Add(blockID, script, nil, []byte("result"), rpc.ScriptUsageTrailer(meter.Usage{
+		ComputationUsed: 1,
+		ComputationIntensities: meter.MeteredComputationIntensities{
+			common.ComputationKindStatement:                                 3,
+			common.ComputationKind(environment.ComputationKindUnsafeRandom): 1,
+		},
+	}))
+	_, ok := cache.Get(blockID, script, nil)
+	assert.False(t, ok)
+
+	// the usage was not sent, e.g. by an execution node not reporting it
+	cache.Add(blockID, script, nil, []byte("result"), nil)
+	_, ok = cache.Get(blockID, script, nil)
+	assert.False(t, ok)
+
+	assert.Equal(t, uint(0), cache.Size())
+}
+
+// TestScriptResultKey tests that moving bytes between the script and its arguments changes the key.
+func TestScriptResultKey(t *testing.T) {
+	blockID := unittest.IdentifierFixture()
+	keys := map[string]struct{}{
+		scriptResultKey(blockID, []byte("ab"), [][]byte{[]byte("c")}).String():             {},
+		scriptResultKey(blockID, []byte("a"), [][]byte{[]byte("bc")}).String():             {},
+		scriptResultKey(blockID, []byte("abc"), nil).String():                              {},
+		scriptResultKey(blockID, []byte("a"), [][]byte{[]byte("b"), []byte("c")}).String(): {},
+		scriptResultKey(blockID, []byte("a"), [][]byte{[]byte("bc"), {}}).String():         {},
+	}
+	assert.Len(t, keys, 5)
+}
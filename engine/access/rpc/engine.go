@@ -16,9 +16,11 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 
+	"github.com/onflow/flow-go/access"
 	"github.com/onflow/flow-go/engine"
 	"github.com/onflow/flow-go/engine/access/rest"
 	"github.com/onflow/flow-go/engine/access/rpc/backend"
+	"github.com/onflow/flow-go/engine/access/state_stream"
 	"github.com/onflow/flow-go/engine/common/rpc"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
@@ -44,6 +46,7 @@ type Config struct {
 	MaxHeightRange            uint                             // max size of height range requests
 	PreferredExecutionNodeIDs []string                         // preferred list of upstream execution node IDs
 	FixedExecutionNodeIDs     []string                         // fixed list of execution node IDs to choose from if no node node ID can be chosen from the PreferredExecutionNodeIDs
+	WebsocketConfig           rest.WebsocketConfig             // the configuration of websocket subscriptions on the REST server
 }
 
 // Engine exposes the server with a simplified version of the Access API.
@@ -57,6 +60,7 @@ type Engine struct {
 	secureGrpcServer   *grpc.Server     // the secure gRPC server
 	httpServer         *http.Server
 	restServer         *http.Server
+	stateStreamApi     state_stream.API // the optional state stream API used for REST websocket subscriptions
 	config             Config
 	chain              flow.Chain
 
@@ -265,6 +269,22 @@ func (e *Engine) RestApiAddress() net.Addr {
 	return e.restAPIAddress
 }
 
+// API returns the Access API implementation served by this engine.
+func (e *Engine) API() access.API {
+	return e.backend
+}
+
+// NotifyExecutedHeight reports the height of a block the given execution node sent a receipt for,
+// so that lagging execution nodes can be avoided.
+func (e *Engine) NotifyExecutedHeight(executorID flow.Identifier, height uint64) {
+	e.backend.NotifyExecutedHeight(executorID, height)
+}
+
+// TransactionRetry returns the retry mechanism for submitted transactions.
+func (e *Engine) TransactionRetry() *backend.Retry {
+	return e.backend.TransactionRetry()
+}
+
 // process processes the given ingestion engine event. Events that are given
 // to this function originate within the expulsion engine on the node with the
 // given origin ID.
@@ -348,7 +368,7 @@ func (e *Engine) serveREST() {
 
 	e.log.Info().Str("rest_api_address", e.config.RESTListenAddr).Msg("starting REST server on address")
 
-	r, err := rest.NewServer(e.backend, e.config.RESTListenAddr, e.log, e.chain)
+	r, err := rest.NewServer(e.backend, e.config.RESTListenAddr, e.log, e.chain, e.stateStreamApi, e.config.WebsocketConfig)
 	if err != nil {
 		e.log.Err(err).Msg("failed to initialize the REST server")
 		return
@@ -10,6 +10,12 @@ import (
 	"github.com/onflow/flow-go/access"
 	legacyaccess "github.com/onflow/flow-go/access/legacy"
 	"github.com/onflow/flow-go/consensus/hotstuff"
+	"github.com/onflow/flow-go/engine/access/rpc/backend"
+	"github.com/onflow/flow-go/engine/access/state_stream"
+	"github.com/onflow/flow-go/engine/common/rpc/extended"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/execution"
+	"github.com/onflow/flow-go/storage"
 )
 
 type RPCEngineBuilder struct {
@@ -18,6 +24,9 @@ type RPCEngineBuilder struct {
 	// optional parameters, only one can be set during build phase
 	signerIndicesDecoder hotstuff.BlockSignerDecoder
 	handler              accessproto.AccessAPIServer // Use the parent interface instead of implementation, so that we can assign it to proxy.
+
+	// optional storage for the transactions pending in the retry mechanism, loaded during build phase
+	retryStorage storage.TransactionRetries
 }
 
 // NewRPCEngineBuilder helps to build a new RPC engine.
@@ -72,6 +81,63 @@ func (builder *RPCEngineBuilder) WithLegacy() *RPCEngineBuilder {
 	return builder
 }
 
+// WithStateStreamAPI specifies that the given state stream API should be used to serve websocket
+// subscriptions on the REST server. If not set, websocket subscriptions are disabled.
+// Returns self-reference for chaining.
+func (builder *RPCEngineBuilder) WithStateStreamAPI(stateStreamApi state_stream.API) *RPCEngineBuilder {
+	builder.stateStreamApi = stateStreamApi
+	return builder
+}
+
+// WithEventsIndex specifies that events for heights which are indexed locally should be served
+// from the given events index instead of execution nodes.
+// Returns self-reference for chaining.
+func (builder *RPCEngineBuilder) WithEventsIndex(eventsIndex backend.EventsIndex) *RPCEngineBuilder {
+	builder.backend.SetEventsIndex(eventsIndex)
+	return builder
+}
+
+// WithScriptExecutor specifies that scripts should be executed locally with the given script
+// executor, according to the given script execution mode.
+// Returns self-reference for chaining.
+func (builder *RPCEngineBuilder) WithScriptExecutor(scriptExecutor execution.ScriptExecutor, mode backend.ScriptExecutionMode) *RPCEngineBuilder {
+	builder.backend.SetScriptExecutor(scriptExecutor, mode)
+	return builder
+}
+
+// WithTransactionStateChecks specifies that submitted transactions should be validated with the
+// given checks which depend on the execution state, tracked with the given metrics.
+// Returns self-reference for chaining.
+func (builder *RPCEngineBuilder) WithTransactionStateChecks(checks backend.TransactionStateChecks, metrics module.TransactionValidationMetrics) *RPCEngineBuilder {
+	builder.backend.SetTransactionStateChecks(checks, metrics)
+	return builder
+}
+
+// WithTransactionRetryStorage specifies that the transactions pending in the retry mechanism should
+// be persisted in the given storage. The transactions persisted previously are resumed during the
+// build step.
+// Returns self-reference for chaining.
+func (builder *RPCEngineBuilder) WithTransactionRetryStorage(store storage.TransactionRetries) *RPCEngineBuilder {
+	builder.retryStorage = store
+	return builder
+}
+
+// WithExecutionNodeSelector specifies that the execution nodes requests are sent to should be chosen
+// by their health, as tracked by the given selector.
+// Returns self-reference for chaining.
+func (builder *RPCEngineBuilder) WithExecutionNodeSelector(selector *backend.ExecutionNodeSelector) *RPCEngineBuilder {
+	builder.backend.SetExecutionNodeSelector(selector)
+	return builder
+}
+
+// WithScriptResultCache specifies that the results of scripts executed against sealed blocks should
+// be cached in the given cache.
+// Returns self-reference for chaining.
+func (builder *RPCEngineBuilder) WithScriptResultCache(cache *backend.ScriptResultCache) *RPCEngineBuilder {
+	builder.backend.SetScriptResultCache(cache)
+	return builder
+}
+
 // WithMetrics specifies the metrics should be collected.
 // Returns self-reference for chaining.
 func (builder *RPCEngineBuilder) WithMetrics() *RPCEngineBuilder {
@@ -86,6 +152,12 @@ func (builder *RPCEngineBuilder) Build() (*Engine, error) {
 	if builder.signerIndicesDecoder != nil && builder.handler != nil {
 		return nil, fmt.Errorf("only BlockSignerDecoder (via method `WithBlockSignerDecoder`) or AccessAPIServer (via method `WithNewHandler`) can be specified but not both")
 	}
+	if builder.retryStorage != nil {
+		err := builder.backend.SetTransactionRetryStorage(builder.retryStorage)
+		if err != nil {
+			return nil, fmt.Errorf("could not resume transaction retries: %w", err)
+		}
+	}
 	handler := builder.handler
 	if handler == nil {
 		if builder.signerIndicesDecoder == nil {
@@ -96,5 +168,10 @@ func (builder *RPCEngineBuilder) Build() (*Engine, error) {
 	}
 	accessproto.RegisterAccessAPIServer(builder.unsecureGrpcServer, handler)
 	accessproto.RegisterAccessAPIServer(builder.secureGrpcServer, handler)
+	// handlers replacing the default one, such as the observer proxy, may not serve the extended Access API
+	if extendedHandler, ok := handler.(extended.ExtendedAccessAPIServer); ok {
+		extended.RegisterExtendedAccessAPIServer(builder.unsecureGrpcServer, extendedHandler)
+		extended.RegisterExtendedAccessAPIServer(builder.secureGrpcServer, extendedHandler)
+	}
 	return builder.Engine, nil
 }
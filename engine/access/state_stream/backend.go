@@ -13,6 +13,7 @@ import (
 	"github.com/onflow/flow-go/engine/common/rpc"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module/executiondatasync/execution_data"
+	"github.com/onflow/flow-go/module/executiondatasync/pruner"
 	herocache "github.com/onflow/flow-go/module/mempool/herocache/backdata"
 	"github.com/onflow/flow-go/state/protocol"
 	"github.com/onflow/flow-go/storage"
@@ -38,11 +39,16 @@ type API interface {
 	GetExecutionDataByBlockID(ctx context.Context, blockID flow.Identifier) (*execution_data.BlockExecutionData, error)
 	SubscribeExecutionData(ctx context.Context, startBlockID flow.Identifier, startBlockHeight uint64) Subscription
 	SubscribeEvents(ctx context.Context, startBlockID flow.Identifier, startHeight uint64, filter EventFilter) Subscription
+	SubscribeBlockHeaders(ctx context.Context, startBlockID flow.Identifier, startHeight uint64, blockStatus flow.BlockStatus) Subscription
+	SubscribeTransactionStatuses(ctx context.Context, txID flow.Identifier) Subscription
+	SendAndSubscribeTransactionStatuses(ctx context.Context, tx *flow.TransactionBody) Subscription
 }
 
 type StateStreamBackend struct {
 	ExecutionDataBackend
 	EventsBackend
+	BlocksBackend
+	TransactionsBackend
 
 	log           zerolog.Logger
 	state         protocol.State
@@ -52,8 +58,14 @@ type StateStreamBackend struct {
 	execDataStore execution_data.ExecutionDataStore
 	execDataCache *herocache.Cache
 	broadcaster   *engine.Broadcaster
+	pins          *pruner.Pins
 }
 
+// New creates a new state stream backend. The heights streamed by subscriptions are pinned in
+// pins, so that the execution data pruner does not prune them while they are streamed.
+// Subscriptions backed by execution data are notified through broadcaster when new execution data
+// is available, while block header and transaction status subscriptions are notified through
+// blockBroadcaster when new blocks are finalized.
 func New(
 	log zerolog.Logger,
 	config Config,
@@ -64,6 +76,8 @@ func New(
 	execDataStore execution_data.ExecutionDataStore,
 	execDataCache *herocache.Cache,
 	broadcaster *engine.Broadcaster,
+	blockBroadcaster *engine.Broadcaster,
+	pins *pruner.Pins,
 ) (*StateStreamBackend, error) {
 	logger := log.With().Str("module", "state_stream_api").Logger()
 
@@ -76,6 +90,7 @@ func New(
 		execDataStore: execDataStore,
 		execDataCache: execDataCache,
 		broadcaster:   broadcaster,
+		pins:          pins,
 	}
 
 	b.ExecutionDataBackend = ExecutionDataBackend{
@@ -86,6 +101,7 @@ func New(
 		sendBufferSize:   int(config.ClientSendBufferSize),
 		getExecutionData: b.getExecutionData,
 		getStartHeight:   b.getStartHeight,
+		pins:             pins,
 	}
 
 	b.EventsBackend = EventsBackend{
@@ -96,11 +112,35 @@ func New(
 		sendBufferSize:   int(config.ClientSendBufferSize),
 		getExecutionData: b.getExecutionData,
 		getStartHeight:   b.getStartHeight,
+		pins:             pins,
+	}
+
+	b.BlocksBackend = BlocksBackend{
+		log:            logger,
+		state:          state,
+		headers:        headers,
+		broadcaster:    blockBroadcaster,
+		sendTimeout:    config.ClientSendTimeout,
+		sendBufferSize: int(config.ClientSendBufferSize),
+		getStartHeight: b.getStartHeight,
+	}
+
+	b.TransactionsBackend = TransactionsBackend{
+		log:            logger,
+		broadcaster:    blockBroadcaster,
+		sendTimeout:    config.ClientSendTimeout,
+		sendBufferSize: int(config.ClientSendBufferSize),
 	}
 
 	return b, nil
 }
 
+// SetTransactionsAPI sets the Access API used to send transactions and derive their status for
+// transaction status subscriptions. It must be called before the API is served.
+func (b *StateStreamBackend) SetTransactionsAPI(transactions TransactionsAPI) {
+	b.TransactionsBackend.transactions = transactions
+}
+
 func (b *StateStreamBackend) getExecutionData(ctx context.Context, blockID flow.Identifier) (*execution_data.BlockExecutionDataEntity, error) {
 	if cached, ok := b.execDataCache.ByID(blockID); ok {
 		b.log.Trace().
@@ -171,3 +211,15 @@ func (b *StateStreamBackend) getStartHeight(startBlockID flow.Identifier, startH
 	}
 	return header.Height, nil
 }
+
+// pinHeights pins startHeight in pins, so that the execution data for it and all later heights is
+// not pruned while it is streamed. The pin follows the heights read by the returned function, and
+// must be released once streaming is done.
+func pinHeights(pins *pruner.Pins, startHeight uint64, getData GetDataByHeightFunc) (GetDataByHeightFunc, *pruner.Pin) {
+	pin := pins.Pin(startHeight)
+
+	return func(ctx context.Context, height uint64) (interface{}, error) {
+		pin.Update(height)
+		return getData(ctx, height)
+	}, pin
+}
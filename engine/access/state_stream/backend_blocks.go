@@ -0,0 +1,83 @@
+package state_stream
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/storage"
+)
+
+type BlocksBackend struct {
+	log            zerolog.Logger
+	state          protocol.State
+	headers        storage.Headers
+	broadcaster    *engine.Broadcaster
+	sendTimeout    time.Duration
+	sendBufferSize int
+
+	getStartHeight GetStartHeightFunc
+}
+
+// SubscribeBlockHeaders streams the headers of all blocks starting at the requested start block,
+// up until the latest block with the requested status (finalized or sealed).
+func (b BlocksBackend) SubscribeBlockHeaders(ctx context.Context, startBlockID flow.Identifier, startHeight uint64, blockStatus flow.BlockStatus) Subscription {
+	if blockStatus != flow.BlockStatusFinalized && blockStatus != flow.BlockStatusSealed {
+		sub := NewSubscription(b.sendBufferSize)
+		sub.Fail(status.Errorf(codes.InvalidArgument, "block status must be either finalized or sealed, got: %s", blockStatus))
+		return sub
+	}
+
+	nextHeight, err := b.getStartHeight(startBlockID, startHeight)
+	if err != nil {
+		sub := NewSubscription(b.sendBufferSize)
+		if st, ok := status.FromError(err); ok {
+			sub.Fail(status.Errorf(st.Code(), "could not get start height: %s", st.Message()))
+			return sub
+		}
+
+		sub.Fail(fmt.Errorf("could not get start height: %w", err))
+		return sub
+	}
+
+	sub := NewHeightBasedSubscription(b.sendBufferSize, nextHeight, b.getResponseFactory(blockStatus))
+
+	go NewStreamer(b.log, b.broadcaster, b.sendTimeout, sub).Stream(ctx)
+
+	return sub
+}
+
+func (b BlocksBackend) getResponseFactory(blockStatus flow.BlockStatus) GetDataByHeightFunc {
+	return func(_ context.Context, height uint64) (interface{}, error) {
+		var snapshot protocol.Snapshot
+		if blockStatus == flow.BlockStatusSealed {
+			snapshot = b.state.Sealed()
+		} else {
+			snapshot = b.state.Final()
+		}
+
+		latest, err := snapshot.Head()
+		if err != nil {
+			return nil, fmt.Errorf("could not get latest %s block: %w", blockStatus, err)
+		}
+
+		// blocks above the latest block with the requested status are not available yet
+		if height > latest.Height {
+			return nil, fmt.Errorf("block %d is not %s yet: %w", height, blockStatus, storage.ErrNotFound)
+		}
+
+		header, err := b.headers.ByHeight(height)
+		if err != nil {
+			return nil, fmt.Errorf("could not get block header for height %d: %w", height, err)
+		}
+
+		return header, nil
+	}
+}
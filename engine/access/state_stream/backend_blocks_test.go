@@ -0,0 +1,151 @@
+package state_stream
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/model/flow"
+	protocolmock "github.com/onflow/flow-go/state/protocol/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+type BackendBlocksSuite struct {
+	BackendExecutionDataSuite
+
+	finalSnapshot *protocolmock.Snapshot
+}
+
+func TestBackendBlocksSuite(t *testing.T) {
+	suite.Run(t, new(BackendBlocksSuite))
+}
+
+func (s *BackendBlocksSuite) SetupTest() {
+	s.BackendExecutionDataSuite.SetupTest()
+
+	s.finalSnapshot = protocolmock.NewSnapshot(s.T())
+	s.finalSnapshot.On("Head").Return(s.blocks[len(s.blocks)-1].Header, nil).Maybe()
+	s.state.On("Final").Return(s.finalSnapshot, nil).Maybe()
+}
+
+// TestSubscribeBlockHeaders tests that headers are streamed up to the latest block with the
+// requested status
+func (s *BackendBlocksSuite) TestSubscribeBlockHeaders() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tests := []struct {
+		name        string
+		blockStatus flow.BlockStatus
+		expected    []*flow.Block
+	}{
+		{
+			name:        "finalized headers",
+			blockStatus: flow.BlockStatusFinalized,
+			expected:    s.blocks,
+		},
+		{
+			name:        "sealed headers",
+			blockStatus: flow.BlockStatusSealed,
+			expected:    s.blocks[:1], // only the first block is sealed
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			subCtx, subCancel := context.WithCancel(ctx)
+			sub := s.backend.SubscribeBlockHeaders(subCtx, s.blocks[0].ID(), 0, test.blockStatus)
+
+			for _, b := range test.expected {
+				unittest.RequireReturnsBefore(s.T(), func() {
+					v, ok := <-sub.Channel()
+					require.True(s.T(), ok, "channel closed while waiting for header %d %v: err: %v", b.Header.Height, b.ID(), sub.Err())
+
+					header, ok := v.(*flow.Header)
+					require.True(s.T(), ok, "unexpected response type: %T", v)
+
+					assert.Equal(s.T(), b.Header.ID(), header.ID())
+				}, time.Second, fmt.Sprintf("timed out waiting for header %d %v", b.Header.Height, b.ID()))
+			}
+
+			// blocks above the latest block with the requested status must not be sent
+			unittest.RequireNeverReturnBefore(s.T(), func() {
+				<-sub.Channel()
+			}, 100*time.Millisecond, "unexpected header received")
+
+			subCancel()
+
+			unittest.RequireReturnsBefore(s.T(), func() {
+				v, ok := <-sub.Channel()
+				assert.Nil(s.T(), v)
+				assert.False(s.T(), ok)
+				assert.ErrorIs(s.T(), sub.Err(), context.Canceled)
+			}, 100*time.Millisecond, "timed out waiting for subscription to shutdown")
+		})
+	}
+}
+
+// TestSubscribeBlockHeadersOnFinalizedBlocks tests that headers of newly finalized blocks are
+// streamed when finalized blocks are broadcast, and not when new execution data is broadcast.
+func (s *BackendBlocksSuite) TestSubscribeBlockHeadersOnFinalizedBlocks() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// only the first block is finalized until more blocks are broadcast
+	var finalized atomic.Int32
+	s.finalSnapshot.ExpectedCalls = nil
+	s.finalSnapshot.On("Head").Return(func() (*flow.Header, error) {
+		return s.blocks[finalized.Load()].Header, nil
+	})
+
+	sub := s.backend.SubscribeBlockHeaders(ctx, s.blocks[0].ID(), 0, flow.BlockStatusFinalized)
+
+	for i, b := range s.blocks {
+		if i > 0 {
+			finalized.Store(int32(i))
+
+			s.broadcaster.Publish()
+			s.requireNoResponse(sub)
+
+			s.blockBroadcaster.Publish()
+		}
+
+		unittest.RequireReturnsBefore(s.T(), func() {
+			v, ok := <-sub.Channel()
+			require.True(s.T(), ok, "channel closed while waiting for header %d %v: err: %v", b.Header.Height, b.ID(), sub.Err())
+
+			header, ok := v.(*flow.Header)
+			require.True(s.T(), ok, "unexpected response type: %T", v)
+
+			assert.Equal(s.T(), b.Header.ID(), header.ID())
+		}, time.Second, fmt.Sprintf("timed out waiting for header %d %v", b.Header.Height, b.ID()))
+	}
+}
+
+func (s *BackendBlocksSuite) TestSubscribeBlockHeadersHandlesErrors() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s.Run("returns error for unknown block status", func() {
+		sub := s.backend.SubscribeBlockHeaders(ctx, flow.ZeroID, 0, flow.BlockStatusUnknown)
+		assert.Equal(s.T(), codes.InvalidArgument, status.Code(sub.Err()))
+	})
+
+	s.Run("returns error if both start blockID and start height are provided", func() {
+		sub := s.backend.SubscribeBlockHeaders(ctx, unittest.IdentifierFixture(), 1, flow.BlockStatusFinalized)
+		assert.Equal(s.T(), codes.InvalidArgument, status.Code(sub.Err()))
+	})
+
+	s.Run("returns error for unindexed start blockID", func() {
+		sub := s.backend.SubscribeBlockHeaders(ctx, unittest.IdentifierFixture(), 0, flow.BlockStatusFinalized)
+		assert.Equal(s.T(), codes.NotFound, status.Code(sub.Err()))
+	})
+}
@@ -10,14 +10,16 @@ import (
 
 	"github.com/onflow/flow-go/engine"
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/executiondatasync/pruner"
 	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/utils/logging"
 )
 
 type EventsResponse struct {
-	BlockID flow.Identifier
-	Height  uint64
-	Events  flow.EventsList
+	BlockID        flow.Identifier
+	Height         uint64
+	BlockTimestamp time.Time
+	Events         flow.EventsList
 }
 
 type EventsBackend struct {
@@ -29,6 +31,7 @@ type EventsBackend struct {
 
 	getExecutionData GetExecutionDataFunc
 	getStartHeight   GetStartHeightFunc
+	pins             *pruner.Pins
 }
 
 func (b EventsBackend) SubscribeEvents(ctx context.Context, startBlockID flow.Identifier, startHeight uint64, filter EventFilter) Subscription {
@@ -44,9 +47,13 @@ func (b EventsBackend) SubscribeEvents(ctx context.Context, startBlockID flow.Id
 		return sub
 	}
 
-	sub := NewHeightBasedSubscription(b.sendBufferSize, nextHeight, b.getResponseFactory(filter))
+	getResponse, pin := pinHeights(b.pins, nextHeight, b.getResponseFactory(filter))
+	sub := NewHeightBasedSubscription(b.sendBufferSize, nextHeight, getResponse)
 
-	go NewStreamer(b.log, b.broadcaster, b.sendTimeout, sub).Stream(ctx)
+	go func() {
+		defer pin.Release()
+		NewStreamer(b.log, b.broadcaster, b.sendTimeout, sub).Stream(ctx)
+	}()
 
 	return sub
 }
@@ -74,9 +81,10 @@ func (b EventsBackend) getResponseFactory(filter EventFilter) GetDataByHeightFun
 			Msgf("sending %d events", len(events))
 
 		return &EventsResponse{
-			BlockID: header.ID(),
-			Height:  header.Height,
-			Events:  events,
+			BlockID:        header.ID(),
+			Height:         header.Height,
+			BlockTimestamp: header.Timestamp,
+			Events:         events,
 		}, nil
 	}
 }
@@ -14,6 +14,7 @@ import (
 	"github.com/onflow/flow-go/engine/common/rpc"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module/executiondatasync/execution_data"
+	"github.com/onflow/flow-go/module/executiondatasync/pruner"
 	"github.com/onflow/flow-go/storage"
 )
 
@@ -31,6 +32,7 @@ type ExecutionDataBackend struct {
 
 	getExecutionData GetExecutionDataFunc
 	getStartHeight   GetStartHeightFunc
+	pins             *pruner.Pins
 }
 
 func (b *ExecutionDataBackend) GetExecutionDataByBlockID(ctx context.Context, blockID flow.Identifier) (*execution_data.BlockExecutionData, error) {
@@ -61,9 +63,13 @@ func (b *ExecutionDataBackend) SubscribeExecutionData(ctx context.Context, start
 		return sub
 	}
 
-	sub := NewHeightBasedSubscription(b.sendBufferSize, nextHeight, b.getResponse)
+	getResponse, pin := pinHeights(b.pins, nextHeight, b.getResponse)
+	sub := NewHeightBasedSubscription(b.sendBufferSize, nextHeight, getResponse)
 
-	go NewStreamer(b.log, b.broadcaster, b.sendTimeout, sub).Stream(ctx)
+	go func() {
+		defer pin.Release()
+		NewStreamer(b.log, b.broadcaster, b.sendTimeout, sub).Stream(ctx)
+	}()
 
 	return sub
 }
@@ -23,6 +23,7 @@ import (
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module/blobs"
 	"github.com/onflow/flow-go/module/executiondatasync/execution_data"
+	"github.com/onflow/flow-go/module/executiondatasync/pruner"
 	herocache "github.com/onflow/flow-go/module/mempool/herocache/backdata"
 	"github.com/onflow/flow-go/module/mempool/herocache/backdata/heropool"
 	"github.com/onflow/flow-go/module/metrics"
@@ -51,8 +52,10 @@ type BackendExecutionDataSuite struct {
 	bs                  blobs.Blobstore
 	eds                 execution_data.ExecutionDataStore
 	broadcaster         *engine.Broadcaster
+	blockBroadcaster    *engine.Broadcaster
 	execDataDistributor *requester.ExecutionDataDistributor
 	execDataCache       *herocache.Cache
+	pins                *pruner.Pins
 	backend             *StateStreamBackend
 
 	blocks      []*flow.Block
@@ -82,6 +85,7 @@ func (s *BackendExecutionDataSuite) SetupTest() {
 	s.eds = execution_data.NewExecutionDataStore(s.bs, execution_data.DefaultSerializer)
 
 	s.broadcaster = engine.NewBroadcaster()
+	s.blockBroadcaster = engine.NewBroadcaster()
 	s.execDataDistributor = requester.NewExecutionDataDistributor()
 
 	s.execDataCache = herocache.NewCache(
@@ -92,6 +96,8 @@ func (s *BackendExecutionDataSuite) SetupTest() {
 		metrics.NewNoopCollector(),
 	)
 
+	s.pins = pruner.NewPins()
+
 	conf := Config{
 		ClientSendTimeout:    DefaultSendTimeout,
 		ClientSendBufferSize: DefaultSendBufferSize,
@@ -108,6 +114,8 @@ func (s *BackendExecutionDataSuite) SetupTest() {
 		s.eds,
 		s.execDataCache,
 		s.broadcaster,
+		s.blockBroadcaster,
+		s.pins,
 	)
 	require.NoError(s.T(), err)
 
@@ -392,6 +400,46 @@ func (s *BackendExecutionDataSuite) TestSubscribeExecutionData() {
 	}
 }
 
+func (s *BackendExecutionDataSuite) TestSubscribeExecutionDataPinsHeights() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s.execDataCache.Clear()
+
+	sub := s.backend.SubscribeExecutionData(ctx, s.blocks[0].ID(), 0)
+
+	// the start height is pinned before any data is streamed
+	lowest, ok := s.pins.Lowest()
+	require.True(s.T(), ok)
+	assert.Equal(s.T(), s.blocks[0].Header.Height, lowest)
+
+	for _, b := range s.blocks {
+		s.execDataDistributor.OnExecutionDataReceived(s.execDataMap[b.ID()])
+		s.broadcaster.Publish()
+
+		unittest.RequireReturnsBefore(s.T(), func() {
+			v, ok := <-sub.Channel()
+			require.True(s.T(), ok, "channel closed while waiting for exec data for block %d %v: err: %v", b.Header.Height, b.ID(), sub.Err())
+
+			resp, ok := v.(*ExecutionDataResponse)
+			require.True(s.T(), ok, "unexpected response type: %T", v)
+			assert.Equal(s.T(), b.Header.Height, resp.Height)
+		}, time.Second, fmt.Sprintf("timed out waiting for exec data for block %d %v", b.Header.Height, b.ID()))
+
+		// the pin follows the streamed heights, and never allows the next height to be pruned
+		lowest, ok := s.pins.Lowest()
+		require.True(s.T(), ok)
+		assert.GreaterOrEqual(s.T(), lowest, b.Header.Height)
+		assert.LessOrEqual(s.T(), lowest, b.Header.Height+1)
+	}
+
+	// the pin is released once the subscription shuts down
+	cancel()
+	require.Eventually(s.T(), func() bool {
+		return s.pins.Count() == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
 func (s *BackendExecutionDataSuite) TestSubscribeExecutionDataHandlesErrors() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -423,3 +471,13 @@ func (s *BackendExecutionDataSuite) TestSubscribeExecutionDataHandlesErrors() {
 		assert.Equal(s.T(), codes.NotFound, status.Code(sub.Err()))
 	})
 }
+
+// requireNoResponse checks that no response is received on the subscription for a short time,
+// without consuming later responses.
+func (s *BackendExecutionDataSuite) requireNoResponse(sub Subscription) {
+	select {
+	case v := <-sub.Channel():
+		s.Require().Failf("unexpected response received", "response: %v", v)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
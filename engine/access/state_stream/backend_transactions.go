@@ -0,0 +1,125 @@
+package state_stream
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+// TransactionsAPI provides the transaction functionality of the Access API used to stream
+// transaction statuses.
+type TransactionsAPI interface {
+	SendTransaction(ctx context.Context, tx *flow.TransactionBody) error
+	GetTransactionResult(ctx context.Context, id flow.Identifier) (*access.TransactionResult, error)
+}
+
+type TransactionsBackend struct {
+	log            zerolog.Logger
+	broadcaster    *engine.Broadcaster
+	sendTimeout    time.Duration
+	sendBufferSize int
+
+	// transactions is used to send transactions and derive their status. It is nil until set with
+	// SetTransactionsAPI, since the Access API backend is created after the state stream backend.
+	transactions TransactionsAPI
+}
+
+// SubscribeTransactionStatuses streams the result of the transaction every time its status changes.
+// The subscription is closed once the transaction is either sealed or expired. Transactions not
+// known to the node yet are waited for, since they may still be submitted.
+func (b TransactionsBackend) SubscribeTransactionStatuses(ctx context.Context, txID flow.Identifier) Subscription {
+	if b.transactions == nil {
+		sub := NewSubscription(b.sendBufferSize)
+		sub.Fail(status.Errorf(codes.Unimplemented, "transaction status streaming is not available"))
+		return sub
+	}
+
+	sub := NewTransactionStatusSubscription(b.sendBufferSize, txID, b.transactions.GetTransactionResult)
+
+	go NewStreamer(b.log, b.broadcaster, b.sendTimeout, sub).Stream(ctx)
+
+	return sub
+}
+
+// SendAndSubscribeTransactionStatuses sends the transaction to the network and streams its
+// result every time its status changes, like SubscribeTransactionStatuses.
+func (b TransactionsBackend) SendAndSubscribeTransactionStatuses(ctx context.Context, tx *flow.TransactionBody) Subscription {
+	if b.transactions == nil {
+		sub := NewSubscription(b.sendBufferSize)
+		sub.Fail(status.Errorf(codes.Unimplemented, "transaction status streaming is not available"))
+		return sub
+	}
+
+	err := b.transactions.SendTransaction(ctx, tx)
+	if err != nil {
+		sub := NewSubscription(b.sendBufferSize)
+		if st, ok := status.FromError(err); ok {
+			sub.Fail(status.Errorf(st.Code(), "could not send transaction: %s", st.Message()))
+			return sub
+		}
+
+		sub.Fail(fmt.Errorf("could not send transaction: %w", err))
+		return sub
+	}
+
+	return b.SubscribeTransactionStatuses(ctx, tx.ID())
+}
+
+// GetTransactionResultFunc is a callback used by transaction status subscriptions to retrieve the
+// current result of a transaction.
+type GetTransactionResultFunc func(ctx context.Context, txID flow.Identifier) (*access.TransactionResult, error)
+
+var _ Subscription = (*TransactionStatusSubscription)(nil)
+var _ Streamable = (*TransactionStatusSubscription)(nil)
+
+// TransactionStatusSubscription is a subscription that retrieves the result of a transaction
+// every time its status changes, until the transaction reaches a final status.
+type TransactionStatusSubscription struct {
+	*SubscriptionImpl
+	txID       flow.Identifier
+	lastStatus flow.TransactionStatus
+	getResult  GetTransactionResultFunc
+}
+
+func NewTransactionStatusSubscription(bufferSize int, txID flow.Identifier, getResult GetTransactionResultFunc) *TransactionStatusSubscription {
+	return &TransactionStatusSubscription{
+		SubscriptionImpl: NewSubscription(bufferSize),
+		txID:             txID,
+		lastStatus:       flow.TransactionStatusUnknown,
+		getResult:        getResult,
+	}
+}
+
+// Next returns the result of the transaction if its status changed since the last result.
+// Expected errors:
+// - storage.ErrNotFound if the transaction is not known yet, or its status did not change
+// - ErrEndOfData if the transaction already reached a final status
+func (s *TransactionStatusSubscription) Next(ctx context.Context) (interface{}, error) {
+	if s.lastStatus == flow.TransactionStatusSealed || s.lastStatus == flow.TransactionStatusExpired {
+		return nil, ErrEndOfData
+	}
+
+	result, err := s.getResult(ctx, s.txID)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, fmt.Errorf("transaction %v is not known yet: %w", s.txID, storage.ErrNotFound)
+		}
+		return nil, fmt.Errorf("could not get result for transaction %v: %w", s.txID, err)
+	}
+
+	if result.Status == s.lastStatus {
+		return nil, fmt.Errorf("status of transaction %v did not change: %w", s.txID, storage.ErrNotFound)
+	}
+	s.lastStatus = result.Status
+
+	return result, nil
+}
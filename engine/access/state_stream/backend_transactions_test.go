@@ -0,0 +1,146 @@
+package state_stream
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/access"
+	accessmock "github.com/onflow/flow-go/access/mock"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+type BackendTransactionsSuite struct {
+	BackendExecutionDataSuite
+
+	transactions *accessmock.API
+}
+
+func TestBackendTransactionsSuite(t *testing.T) {
+	suite.Run(t, new(BackendTransactionsSuite))
+}
+
+func (s *BackendTransactionsSuite) SetupTest() {
+	s.BackendExecutionDataSuite.SetupTest()
+
+	s.transactions = accessmock.NewAPI(s.T())
+	s.backend.SetTransactionsAPI(s.transactions)
+}
+
+// TestSendAndSubscribeTransactionStatuses tests that the transaction is sent, and a result is
+// streamed every time its status changes until it is sealed.
+func (s *BackendTransactionsSuite) TestSendAndSubscribeTransactionStatuses() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tx := unittest.TransactionBodyFixture()
+	txID := tx.ID()
+
+	// the transaction is not known until the status is first set
+	var currentStatus atomic.Int32
+	currentStatus.Store(-1)
+
+	s.transactions.On("SendTransaction", mock.Anything, &tx).Return(nil).Once()
+	s.transactions.
+		On("GetTransactionResult", mock.Anything, txID).
+		Return(func(context.Context, flow.Identifier) (*access.TransactionResult, error) {
+			txStatus := currentStatus.Load()
+			if txStatus < 0 {
+				return nil, status.Errorf(codes.NotFound, "transaction not found")
+			}
+			return &access.TransactionResult{
+				Status:        flow.TransactionStatus(txStatus),
+				TransactionID: txID,
+			}, nil
+		})
+
+	sub := s.backend.SendAndSubscribeTransactionStatuses(ctx, &tx)
+
+	// nothing is sent while the transaction is unknown
+	s.requireNoResponse(sub)
+
+	expected := []flow.TransactionStatus{
+		flow.TransactionStatusPending,
+		flow.TransactionStatusFinalized,
+		flow.TransactionStatusExecuted,
+		flow.TransactionStatusSealed,
+	}
+	for _, txStatus := range expected {
+		currentStatus.Store(int32(txStatus))
+		s.blockBroadcaster.Publish()
+
+		unittest.RequireReturnsBefore(s.T(), func() {
+			v, ok := <-sub.Channel()
+			require.True(s.T(), ok, "channel closed while waiting for status %s: err: %v", txStatus, sub.Err())
+
+			result, ok := v.(*access.TransactionResult)
+			require.True(s.T(), ok, "unexpected response type: %T", v)
+
+			assert.Equal(s.T(), txStatus, result.Status)
+			assert.Equal(s.T(), txID, result.TransactionID)
+		}, time.Second, fmt.Sprintf("timed out waiting for status %s", txStatus))
+
+		// the same status is not sent again
+		s.blockBroadcaster.Publish()
+		if txStatus != flow.TransactionStatusSealed {
+			s.requireNoResponse(sub)
+		}
+	}
+
+	// the subscription is closed gracefully once the transaction is sealed
+	unittest.RequireReturnsBefore(s.T(), func() {
+		v, ok := <-sub.Channel()
+		assert.Nil(s.T(), v)
+		assert.False(s.T(), ok)
+		assert.NoError(s.T(), sub.Err())
+	}, time.Second, "timed out waiting for subscription to shutdown")
+}
+
+func (s *BackendTransactionsSuite) TestSubscribeTransactionStatusesHandlesErrors() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s.Run("returns error if the transaction can not be sent", func() {
+		tx := unittest.TransactionBodyFixture()
+		s.transactions.
+			On("SendTransaction", mock.Anything, &tx).
+			Return(status.Errorf(codes.InvalidArgument, "invalid transaction")).
+			Once()
+
+		sub := s.backend.SendAndSubscribeTransactionStatuses(ctx, &tx)
+		assert.Equal(s.T(), codes.InvalidArgument, status.Code(sub.Err()))
+	})
+
+	s.Run("returns error if the result can not be retrieved", func() {
+		txID := unittest.IdentifierFixture()
+		s.transactions.
+			On("GetTransactionResult", mock.Anything, txID).
+			Return(nil, status.Errorf(codes.Internal, "failed to get result")).
+			Once()
+
+		sub := s.backend.SubscribeTransactionStatuses(ctx, txID)
+
+		unittest.RequireReturnsBefore(s.T(), func() {
+			_, ok := <-sub.Channel()
+			assert.False(s.T(), ok)
+			assert.ErrorContains(s.T(), sub.Err(), "failed to get result")
+		}, time.Second, "timed out waiting for subscription to fail")
+	})
+
+	s.Run("returns error if transactions API is not set", func() {
+		s.backend.SetTransactionsAPI(nil)
+
+		sub := s.backend.SubscribeTransactionStatuses(ctx, unittest.IdentifierFixture())
+		assert.Equal(s.T(), codes.Unimplemented, status.Code(sub.Err()))
+	})
+}
@@ -10,12 +10,14 @@ import (
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc"
 
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
 	"github.com/onflow/flow-go/engine"
 	"github.com/onflow/flow-go/engine/common/rpc"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/module/component"
 	"github.com/onflow/flow-go/module/executiondatasync/execution_data"
+	"github.com/onflow/flow-go/module/executiondatasync/pruner"
 	"github.com/onflow/flow-go/module/irrecoverable"
 	herocache "github.com/onflow/flow-go/module/mempool/herocache/backdata"
 	"github.com/onflow/flow-go/module/mempool/herocache/backdata/heropool"
@@ -64,6 +66,7 @@ type Engine struct {
 	handler *Handler
 
 	execDataBroadcaster *engine.Broadcaster
+	blockBroadcaster    *engine.Broadcaster
 	execDataCache       *herocache.Cache
 
 	stateStreamGrpcAddress net.Addr
@@ -82,6 +85,7 @@ func NewEng(
 	apiRatelimits map[string]int, // the api rate limit (max calls per second) for each of the gRPC API e.g. Ping->100, GetExecutionDataByBlockID->300
 	apiBurstLimits map[string]int, // the api burst limit (max calls at the same time) for each of the gRPC API e.g. Ping->50, GetExecutionDataByBlockID->10
 	heroCacheMetrics module.HeroCacheMetrics,
+	pins *pruner.Pins, // heights of execution data streamed by subscriptions, which must not be pruned
 ) (*Engine, error) {
 	logger := log.With().Str("engine", "state_stream_rpc").Logger()
 
@@ -122,8 +126,9 @@ func NewEng(
 	)
 
 	broadcaster := engine.NewBroadcaster()
+	blockBroadcaster := engine.NewBroadcaster()
 
-	backend, err := New(logger, config, state, headers, seals, results, execDataStore, execDataCache, broadcaster)
+	backend, err := New(logger, config, state, headers, seals, results, execDataStore, execDataCache, broadcaster, blockBroadcaster, pins)
 	if err != nil {
 		return nil, fmt.Errorf("could not create state stream backend: %w", err)
 	}
@@ -136,6 +141,7 @@ func NewEng(
 		config:              config,
 		handler:             NewHandler(backend, chainID.Chain(), config.EventFilterConfig, config.MaxGlobalStreams),
 		execDataBroadcaster: broadcaster,
+		blockBroadcaster:    blockBroadcaster,
 		execDataCache:       execDataCache,
 	}
 
@@ -158,6 +164,29 @@ func (e *Engine) OnExecutionData(executionData *execution_data.BlockExecutionDat
 	e.execDataBroadcaster.Publish()
 }
 
+// OnFinalizedBlock is called to notify the engine when a new block is finalized.
+// Block header and transaction status subscriptions are not backed by execution data, so they are
+// woken up on their own broadcaster when new blocks become available, without waking up the
+// execution data subscriptions.
+func (e *Engine) OnFinalizedBlock(block *model.Block) {
+	e.log.Trace().
+		Hex("block_id", logging.ID(block.BlockID)).
+		Msg("received finalized block")
+
+	e.blockBroadcaster.Publish()
+}
+
+// SetTransactionsAPI sets the Access API used by transaction status subscriptions.
+// It must be called before the engine is started.
+func (e *Engine) SetTransactionsAPI(transactions TransactionsAPI) {
+	e.backend.SetTransactionsAPI(transactions)
+}
+
+// API returns the state stream API implementation served by this engine.
+func (e *Engine) API() API {
+	return e.backend
+}
+
 // serve starts the gRPC server.
 // When this function returns, the server is considered ready.
 func (e *Engine) serve(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
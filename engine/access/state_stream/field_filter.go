@@ -0,0 +1,241 @@
+package state_stream
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+const (
+	// DefaultMaxFieldFilters is the default maximum number of field filters that can be specified in a filter
+	DefaultMaxFieldFilters = 10
+
+	// DefaultMaxFieldFilterValues is the default maximum number of values that can be specified in a field filter
+	DefaultMaxFieldFilterValues = 1000
+)
+
+// FieldFilterOperator is the comparison applied by an EventFieldFilter to the value of an event field.
+type FieldFilterOperator string
+
+const (
+	// FieldFilterEqual matches if the field is equal to the single filter value
+	FieldFilterEqual FieldFilterOperator = "eq"
+
+	// FieldFilterIn matches if the field is equal to any of the filter values
+	FieldFilterIn FieldFilterOperator = "in"
+
+	// FieldFilterLessThan matches if the numeric field is less than the single filter value
+	FieldFilterLessThan FieldFilterOperator = "lt"
+
+	// FieldFilterLessThanOrEqual matches if the numeric field is less than or equal to the single filter value
+	FieldFilterLessThanOrEqual FieldFilterOperator = "lte"
+
+	// FieldFilterGreaterThan matches if the numeric field is greater than the single filter value
+	FieldFilterGreaterThan FieldFilterOperator = "gt"
+
+	// FieldFilterGreaterThanOrEqual matches if the numeric field is greater than or equal to the single filter value
+	FieldFilterGreaterThanOrEqual FieldFilterOperator = "gte"
+)
+
+// EventFieldFilter is a predicate on a field of the decoded Cadence event payload.
+//
+// Values are compared to the field as follows:
+//   - Address fields match values in hex format, with or without the 0x prefix
+//   - String and Character fields match the raw value, without quotes
+//   - numeric fields are compared numerically, e.g. "1.5" matches a UFix64 field of 1.50000000
+//   - all other fields match their Cadence string representation
+//
+// Optional fields are unwrapped, and nil values never match.
+type EventFieldFilter struct {
+	Field    string
+	Operator FieldFilterOperator
+	Values   []string
+}
+
+// fieldPredicate is the validated form of an EventFieldFilter used to match events.
+type fieldPredicate struct {
+	field    string
+	operator FieldFilterOperator
+
+	// values, addresses and numbers contain the filter values for equality and set membership.
+	// numbers are keyed by their normalized fraction representation.
+	values    map[string]struct{}
+	addresses map[flow.Address]struct{}
+	numbers   map[string]struct{}
+
+	// number contains the filter value for numeric comparisons
+	number *big.Rat
+}
+
+func newFieldPredicate(config EventFilterConfig, filter EventFieldFilter) (fieldPredicate, error) {
+	if filter.Field == "" {
+		return fieldPredicate{}, fmt.Errorf("field filter must specify a field")
+	}
+
+	p := fieldPredicate{
+		field:    filter.Field,
+		operator: filter.Operator,
+	}
+
+	switch filter.Operator {
+	case FieldFilterEqual, FieldFilterIn:
+		if filter.Operator == FieldFilterEqual && len(filter.Values) != 1 {
+			return fieldPredicate{}, fmt.Errorf("field filter %s %s must have exactly 1 value", filter.Field, filter.Operator)
+		}
+		if len(filter.Values) == 0 {
+			return fieldPredicate{}, fmt.Errorf("field filter %s %s must have at least 1 value", filter.Field, filter.Operator)
+		}
+		if len(filter.Values) > config.MaxFieldFilterValues {
+			return fieldPredicate{}, fmt.Errorf("too many values in field filter %s (%d). use %d or fewer", filter.Field, len(filter.Values), config.MaxFieldFilterValues)
+		}
+
+		p.values = make(map[string]struct{}, len(filter.Values))
+		p.addresses = make(map[flow.Address]struct{})
+		p.numbers = make(map[string]struct{})
+		for _, value := range filter.Values {
+			p.values[value] = struct{}{}
+			// the type of the field is only known when matching, so also keep the values that are
+			// valid addresses or numbers in their parsed form
+			if address, ok := parseFieldAddress(value); ok {
+				p.addresses[address] = struct{}{}
+			}
+			if number, ok := new(big.Rat).SetString(value); ok {
+				p.numbers[number.RatString()] = struct{}{}
+			}
+		}
+
+	case FieldFilterLessThan, FieldFilterLessThanOrEqual, FieldFilterGreaterThan, FieldFilterGreaterThanOrEqual:
+		if len(filter.Values) != 1 {
+			return fieldPredicate{}, fmt.Errorf("field filter %s %s must have exactly 1 value", filter.Field, filter.Operator)
+		}
+
+		number, ok := new(big.Rat).SetString(filter.Values[0])
+		if !ok {
+			return fieldPredicate{}, fmt.Errorf("invalid number in field filter %s %s: %s", filter.Field, filter.Operator, filter.Values[0])
+		}
+		p.number = number
+
+	default:
+		return fieldPredicate{}, fmt.Errorf("invalid operator in field filter %s: %q", filter.Field, filter.Operator)
+	}
+
+	return p, nil
+}
+
+// match returns true if the field value satisfies the predicate
+func (p *fieldPredicate) match(value cadence.Value) bool {
+	for {
+		optional, ok := value.(cadence.Optional)
+		if !ok {
+			break
+		}
+		if optional.Value == nil {
+			return false
+		}
+		value = optional.Value
+	}
+
+	switch p.operator {
+	case FieldFilterEqual, FieldFilterIn:
+		switch v := value.(type) {
+		case cadence.Address:
+			_, ok := p.addresses[flow.Address(v)]
+			return ok
+		case cadence.String:
+			_, ok := p.values[string(v)]
+			return ok
+		case cadence.Character:
+			_, ok := p.values[string(v)]
+			return ok
+		case cadence.NumberValue:
+			number, ok := new(big.Rat).SetString(v.String())
+			if !ok {
+				return false
+			}
+			_, ok = p.numbers[number.RatString()]
+			return ok
+		default:
+			_, ok := p.values[v.String()]
+			return ok
+		}
+
+	default:
+		numberValue, ok := value.(cadence.NumberValue)
+		if !ok {
+			return false
+		}
+		number, ok := new(big.Rat).SetString(numberValue.String())
+		if !ok {
+			return false
+		}
+
+		cmp := number.Cmp(p.number)
+		switch p.operator {
+		case FieldFilterLessThan:
+			return cmp < 0
+		case FieldFilterLessThanOrEqual:
+			return cmp <= 0
+		case FieldFilterGreaterThan:
+			return cmp > 0
+		case FieldFilterGreaterThanOrEqual:
+			return cmp >= 0
+		}
+		return false
+	}
+}
+
+// matchFields decodes the event payload and returns true if all predicates match. Events that do
+// not have a field referenced by a predicate, or that cannot be decoded, do not match.
+func matchFields(predicates []fieldPredicate, event flow.Event) bool {
+	value, err := jsoncdc.Decode(nil, event.Payload)
+	if err != nil {
+		return false
+	}
+
+	cadenceEvent, ok := value.(cadence.Event)
+	if !ok || cadenceEvent.EventType == nil {
+		return false
+	}
+
+	for i := range predicates {
+		field, ok := eventField(cadenceEvent, predicates[i].field)
+		if !ok || !predicates[i].match(field) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// eventField returns the value of the field with the given name
+func eventField(event cadence.Event, name string) (cadence.Value, bool) {
+	for i, field := range event.EventType.Fields {
+		if field.Identifier == name && i < len(event.Fields) {
+			return event.Fields[i], true
+		}
+	}
+	return nil, false
+}
+
+// parseFieldAddress parses a hex encoded address, with or without the 0x prefix
+func parseFieldAddress(value string) (flow.Address, bool) {
+	trimmed := strings.TrimPrefix(value, "0x")
+	if len(trimmed) == 0 || len(trimmed) > 2*flow.AddressLength {
+		return flow.EmptyAddress, false
+	}
+	if len(trimmed)%2 == 1 {
+		trimmed = "0" + trimmed
+	}
+
+	b, err := hex.DecodeString(trimmed)
+	if err != nil {
+		return flow.EmptyAddress, false
+	}
+	return flow.BytesToAddress(b), true
+}
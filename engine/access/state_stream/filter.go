@@ -20,16 +20,20 @@ const (
 
 // EventFilterConfig is used to configure the limits for EventFilters
 type EventFilterConfig struct {
-	MaxEventTypes int
-	MaxAddresses  int
-	MaxContracts  int
+	MaxEventTypes        int
+	MaxAddresses         int
+	MaxContracts         int
+	MaxFieldFilters      int
+	MaxFieldFilterValues int
 }
 
 // DefaultEventFilterConfig is the default configuration for EventFilters
 var DefaultEventFilterConfig = EventFilterConfig{
-	MaxEventTypes: DefaultMaxEventTypes,
-	MaxAddresses:  DefaultMaxAddresses,
-	MaxContracts:  DefaultMaxContracts,
+	MaxEventTypes:        DefaultMaxEventTypes,
+	MaxAddresses:         DefaultMaxAddresses,
+	MaxContracts:         DefaultMaxContracts,
+	MaxFieldFilters:      DefaultMaxFieldFilters,
+	MaxFieldFilterValues: DefaultMaxFieldFilterValues,
 }
 
 // EventFilter represents a filter applied to events for a given subscription
@@ -38,6 +42,9 @@ type EventFilter struct {
 	EventTypes map[flow.EventType]struct{}
 	Addresses  map[string]struct{}
 	Contracts  map[string]struct{}
+
+	// fieldPredicates must all match the decoded payload of events selected by the other filters
+	fieldPredicates []fieldPredicate
 }
 
 func NewEventFilter(
@@ -46,6 +53,7 @@ func NewEventFilter(
 	eventTypes []string,
 	addresses []string,
 	contracts []string,
+	fieldFilters []EventFieldFilter,
 ) (EventFilter, error) {
 	// put some reasonable limits on the number of filters. Lookups use a map so they are fast,
 	// this just puts a cap on the memory consumed per filter.
@@ -61,6 +69,12 @@ func NewEventFilter(
 		return EventFilter{}, fmt.Errorf("too many contracts in filter (%d). use %d or fewer", len(contracts), config.MaxContracts)
 	}
 
+	// field filters require decoding the payload of every event, so their number is also limited
+	// to bound the cost of matching
+	if len(fieldFilters) > config.MaxFieldFilters {
+		return EventFilter{}, fmt.Errorf("too many field filters in filter (%d). use %d or fewer", len(fieldFilters), config.MaxFieldFilters)
+	}
+
 	f := EventFilter{
 		EventTypes: make(map[flow.EventType]struct{}, len(eventTypes)),
 		Addresses:  make(map[string]struct{}, len(addresses)),
@@ -93,6 +107,14 @@ func NewEventFilter(
 		f.Contracts[contract] = struct{}{}
 	}
 
+	for _, fieldFilter := range fieldFilters {
+		predicate, err := newFieldPredicate(config, fieldFilter)
+		if err != nil {
+			return EventFilter{}, err
+		}
+		f.fieldPredicates = append(f.fieldPredicates, predicate)
+	}
+
 	f.hasFilters = len(f.EventTypes) > 0 || len(f.Addresses) > 0 || len(f.Contracts) > 0
 	return f, nil
 }
@@ -111,6 +133,21 @@ func (f *EventFilter) Filter(events flow.EventsList) flow.EventsList {
 
 // Match applies all filters to a specific event, and returns true if the event matches
 func (f *EventFilter) Match(event flow.Event) bool {
+	if !f.matchType(event) {
+		return false
+	}
+
+	// field filters are only evaluated for events selected by the other filters, since they
+	// require decoding the event payload
+	if len(f.fieldPredicates) == 0 {
+		return true
+	}
+	return matchFields(f.fieldPredicates, event)
+}
+
+// matchType applies the event type, address and contract filters to a specific event, and returns
+// true if the event matches any of them
+func (f *EventFilter) matchType(event flow.Event) bool {
 	// No filters means all events match
 	if !f.hasFilters {
 		return true
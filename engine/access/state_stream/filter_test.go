@@ -3,7 +3,11 @@ package state_stream_test
 import (
 	"testing"
 
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime/common"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/flow-go/engine/access/state_stream"
 	"github.com/onflow/flow-go/model/flow"
@@ -64,7 +68,7 @@ func TestContructor(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			filter, err := state_stream.NewEventFilter(state_stream.DefaultEventFilterConfig, chain, test.eventTypes, test.addresses, test.contracts)
+			filter, err := state_stream.NewEventFilter(state_stream.DefaultEventFilterConfig, chain, test.eventTypes, test.addresses, test.contracts, nil)
 			if test.err {
 				assert.Error(t, err)
 				assert.Equal(t, filter, state_stream.EventFilter{})
@@ -83,7 +87,7 @@ func TestFilter(t *testing.T) {
 
 	chain := flow.MonotonicEmulator.Chain()
 
-	filter, err := state_stream.NewEventFilter(state_stream.DefaultEventFilterConfig, chain, []string{"flow.AccountCreated", "A.0000000000000001.Contract1.EventA"}, nil, nil)
+	filter, err := state_stream.NewEventFilter(state_stream.DefaultEventFilterConfig, chain, []string{"flow.AccountCreated", "A.0000000000000001.Contract1.EventA"}, nil, nil, nil)
 	assert.NoError(t, err)
 
 	events := flow.EventsList{
@@ -175,6 +179,7 @@ func TestMatch(t *testing.T) {
 				test.eventTypes,
 				test.addresses,
 				test.contracts,
+				nil,
 			)
 			assert.NoError(t, err)
 			for _, event := range events {
@@ -183,3 +188,196 @@ func TestMatch(t *testing.T) {
 		})
 	}
 }
+
+func TestFieldFilterConstructor(t *testing.T) {
+	t.Parallel()
+
+	config := state_stream.DefaultEventFilterConfig
+	config.MaxFieldFilters = 2
+	config.MaxFieldFilterValues = 2
+
+	tests := []struct {
+		name         string
+		fieldFilters []state_stream.EventFieldFilter
+		err          bool
+	}{
+		{
+			name: "valid filters",
+			fieldFilters: []state_stream.EventFieldFilter{
+				{Field: "to", Operator: state_stream.FieldFilterIn, Values: []string{"0x01", "0x02"}},
+				{Field: "amount", Operator: state_stream.FieldFilterGreaterThanOrEqual, Values: []string{"10.5"}},
+			},
+		},
+		{
+			name: "too many filters",
+			fieldFilters: []state_stream.EventFieldFilter{
+				{Field: "a", Operator: state_stream.FieldFilterEqual, Values: []string{"1"}},
+				{Field: "b", Operator: state_stream.FieldFilterEqual, Values: []string{"1"}},
+				{Field: "c", Operator: state_stream.FieldFilterEqual, Values: []string{"1"}},
+			},
+			err: true,
+		},
+		{
+			name:         "too many values",
+			fieldFilters: []state_stream.EventFieldFilter{{Field: "a", Operator: state_stream.FieldFilterIn, Values: []string{"1", "2", "3"}}},
+			err:          true,
+		},
+		{
+			name:         "missing field",
+			fieldFilters: []state_stream.EventFieldFilter{{Operator: state_stream.FieldFilterEqual, Values: []string{"1"}}},
+			err:          true,
+		},
+		{
+			name:         "invalid operator",
+			fieldFilters: []state_stream.EventFieldFilter{{Field: "a", Operator: "ne", Values: []string{"1"}}},
+			err:          true,
+		},
+		{
+			name:         "multiple values for equality",
+			fieldFilters: []state_stream.EventFieldFilter{{Field: "a", Operator: state_stream.FieldFilterEqual, Values: []string{"1", "2"}}},
+			err:          true,
+		},
+		{
+			name:         "no values for set membership",
+			fieldFilters: []state_stream.EventFieldFilter{{Field: "a", Operator: state_stream.FieldFilterIn}},
+			err:          true,
+		},
+		{
+			name:         "invalid number for comparison",
+			fieldFilters: []state_stream.EventFieldFilter{{Field: "a", Operator: state_stream.FieldFilterLessThan, Values: []string{"foo"}}},
+			err:          true,
+		},
+	}
+
+	chain := flow.MonotonicEmulator.Chain()
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filter, err := state_stream.NewEventFilter(config, chain, nil, nil, nil, test.fieldFilters)
+			if test.err {
+				assert.Error(t, err)
+				assert.Equal(t, filter, state_stream.EventFilter{})
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMatchFieldFilters(t *testing.T) {
+	t.Parallel()
+
+	recipient1 := flow.HexToAddress("0000000000000001")
+	recipient2 := flow.HexToAddress("0000000000000002")
+
+	depositType := "A.0000000000000001.FungibleToken.Deposited"
+	events := []flow.Event{
+		depositEventFixture(t, depositType, "10.0", &recipient1, "first"),
+		depositEventFixture(t, depositType, "25.5", &recipient2, "second"),
+		depositEventFixture(t, depositType, "100.0", nil, "third"),
+		unittest.EventFixture("A.0000000000000001.Contract1.EventA", 0, 0, unittest.IdentifierFixture(), 0),
+	}
+
+	tests := []struct {
+		name         string
+		eventTypes   []string
+		fieldFilters []state_stream.EventFieldFilter
+		matches      []bool
+	}{
+		{
+			name:         "address equality",
+			fieldFilters: []state_stream.EventFieldFilter{{Field: "to", Operator: state_stream.FieldFilterEqual, Values: []string{"0x01"}}},
+			matches:      []bool{true, false, false, false},
+		},
+		{
+			name:         "address set membership",
+			fieldFilters: []state_stream.EventFieldFilter{{Field: "to", Operator: state_stream.FieldFilterIn, Values: []string{"0000000000000001", "0x0000000000000002"}}},
+			matches:      []bool{true, true, false, false},
+		},
+		{
+			name:         "string equality",
+			fieldFilters: []state_stream.EventFieldFilter{{Field: "memo", Operator: state_stream.FieldFilterEqual, Values: []string{"second"}}},
+			matches:      []bool{false, true, false, false},
+		},
+		{
+			name:         "numeric equality",
+			fieldFilters: []state_stream.EventFieldFilter{{Field: "amount", Operator: state_stream.FieldFilterIn, Values: []string{"10", "100.00"}}},
+			matches:      []bool{true, false, true, false},
+		},
+		{
+			name:         "numeric comparison",
+			fieldFilters: []state_stream.EventFieldFilter{{Field: "amount", Operator: state_stream.FieldFilterGreaterThan, Values: []string{"10"}}},
+			matches:      []bool{false, true, true, false},
+		},
+		{
+			name: "all field filters must match",
+			fieldFilters: []state_stream.EventFieldFilter{
+				{Field: "amount", Operator: state_stream.FieldFilterLessThanOrEqual, Values: []string{"25.5"}},
+				{Field: "to", Operator: state_stream.FieldFilterEqual, Values: []string{"0x02"}},
+			},
+			matches: []bool{false, true, false, false},
+		},
+		{
+			name:         "missing field",
+			fieldFilters: []state_stream.EventFieldFilter{{Field: "from", Operator: state_stream.FieldFilterEqual, Values: []string{"0x01"}}},
+			matches:      []bool{false, false, false, false},
+		},
+		{
+			name:         "combined with event type filter",
+			eventTypes:   []string{"A.0000000000000001.Contract1.EventA"},
+			fieldFilters: []state_stream.EventFieldFilter{{Field: "amount", Operator: state_stream.FieldFilterLessThan, Values: []string{"50"}}},
+			matches:      []bool{false, false, false, false},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filter, err := state_stream.NewEventFilter(
+				state_stream.DefaultEventFilterConfig,
+				flow.MonotonicEmulator.Chain(),
+				test.eventTypes,
+				nil,
+				nil,
+				test.fieldFilters,
+			)
+			require.NoError(t, err)
+			for i, event := range events {
+				assert.Equal(t, test.matches[i], filter.Match(event), "event %d", i)
+			}
+		})
+	}
+}
+
+// depositEventFixture returns an event with a json-cdc encoded payload, containing an amount,
+// an optional recipient and a memo.
+func depositEventFixture(t *testing.T, eventType string, amount string, to *flow.Address, memo string) flow.Event {
+	amountValue, err := cadence.NewUFix64(amount)
+	require.NoError(t, err)
+
+	toValue := cadence.NewOptional(nil)
+	if to != nil {
+		toValue = cadence.NewOptional(cadence.NewAddress(*to))
+	}
+
+	event := cadence.NewEvent([]cadence.Value{
+		amountValue,
+		toValue,
+		cadence.String(memo),
+	}).WithType(&cadence.EventType{
+		Location:            common.AddressLocation{Address: common.Address{0, 0, 0, 0, 0, 0, 0, 1}, Name: "FungibleToken"},
+		QualifiedIdentifier: "FungibleToken.Deposited",
+		Fields: []cadence.Field{
+			{Identifier: "amount", Type: cadence.UFix64Type{}},
+			{Identifier: "to", Type: &cadence.OptionalType{Type: cadence.AddressType{}}},
+			{Identifier: "memo", Type: cadence.StringType{}},
+		},
+	})
+
+	payload, err := jsoncdc.Encode(event)
+	require.NoError(t, err)
+
+	return flow.Event{
+		Type:    flow.EventType(eventType),
+		Payload: payload,
+	}
+}
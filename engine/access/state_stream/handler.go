@@ -129,6 +129,7 @@ func (h *Handler) SubscribeEvents(request *access.SubscribeEventsRequest, stream
 			reqFilter.GetEventType(),
 			reqFilter.GetAddress(),
 			reqFilter.GetContract(),
+			nil, // field filters are not part of the protobuf event filter
 		)
 		if err != nil {
 			return status.Errorf(codes.InvalidArgument, "invalid event filter: %v", err)
@@ -44,6 +44,38 @@ func (_m *API) GetExecutionDataByBlockID(ctx context.Context, blockID flow.Ident
 	return r0, r1
 }
 
+// SendAndSubscribeTransactionStatuses provides a mock function with given fields: ctx, tx
+func (_m *API) SendAndSubscribeTransactionStatuses(ctx context.Context, tx *flow.TransactionBody) state_stream.Subscription {
+	ret := _m.Called(ctx, tx)
+
+	var r0 state_stream.Subscription
+	if rf, ok := ret.Get(0).(func(context.Context, *flow.TransactionBody) state_stream.Subscription); ok {
+		r0 = rf(ctx, tx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(state_stream.Subscription)
+		}
+	}
+
+	return r0
+}
+
+// SubscribeBlockHeaders provides a mock function with given fields: ctx, startBlockID, startHeight, blockStatus
+func (_m *API) SubscribeBlockHeaders(ctx context.Context, startBlockID flow.Identifier, startHeight uint64, blockStatus flow.BlockStatus) state_stream.Subscription {
+	ret := _m.Called(ctx, startBlockID, startHeight, blockStatus)
+
+	var r0 state_stream.Subscription
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Identifier, uint64, flow.BlockStatus) state_stream.Subscription); ok {
+		r0 = rf(ctx, startBlockID, startHeight, blockStatus)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(state_stream.Subscription)
+		}
+	}
+
+	return r0
+}
+
 // SubscribeEvents provides a mock function with given fields: ctx, startBlockID, startHeight, filter
 func (_m *API) SubscribeEvents(ctx context.Context, startBlockID flow.Identifier, startHeight uint64, filter state_stream.EventFilter) state_stream.Subscription {
 	ret := _m.Called(ctx, startBlockID, startHeight, filter)
@@ -76,6 +108,22 @@ func (_m *API) SubscribeExecutionData(ctx context.Context, startBlockID flow.Ide
 	return r0
 }
 
+// SubscribeTransactionStatuses provides a mock function with given fields: ctx, txID
+func (_m *API) SubscribeTransactionStatuses(ctx context.Context, txID flow.Identifier) state_stream.Subscription {
+	ret := _m.Called(ctx, txID)
+
+	var r0 state_stream.Subscription
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Identifier) state_stream.Subscription); ok {
+		r0 = rf(ctx, txID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(state_stream.Subscription)
+		}
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewAPI interface {
 	mock.TestingT
 	Cleanup(func())
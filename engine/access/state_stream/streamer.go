@@ -68,6 +68,12 @@ func (s *Streamer) Stream(ctx context.Context) {
 
 		err := s.sendAllAvailable(ctx)
 
+		if errors.Is(err, ErrEndOfData) {
+			s.log.Debug().Msg("reached end of data")
+			s.sub.Close()
+			return
+		}
+
 		if err != nil {
 			s.log.Err(err).Msg("error sending response")
 			s.sub.Fail(err)
@@ -87,6 +93,10 @@ func (s *Streamer) sendAllAvailable(ctx context.Context) error {
 				return nil
 			}
 
+			if errors.Is(err, ErrEndOfData) {
+				return err
+			}
+
 			return fmt.Errorf("could not get response: %w", err)
 		}
 
@@ -2,6 +2,7 @@ package state_stream
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -14,6 +15,10 @@ import (
 // streaming existing data.
 const DefaultSendBufferSize = 10
 
+// ErrEndOfData is returned by a subscription's Next method when no more data will ever be
+// available, in which case the subscription is closed gracefully.
+var ErrEndOfData = errors.New("end of data")
+
 // GetDataByHeightFunc is a callback used by subscriptions to retrieve data for a given height.
 // Expected errors:
 // - storage.ErrNotFound
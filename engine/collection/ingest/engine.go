@@ -354,7 +354,9 @@ func (e *Engine) ingestTransaction(
 	}
 
 	// check if the transaction is valid
-	err = e.transactionValidator.Validate(tx)
+	// collection nodes don't run the validation checks which depend on the execution state, so
+	// validation doesn't block on the context
+	err = e.transactionValidator.Validate(context.Background(), tx)
 	if err != nil {
 		return engine.NewInvalidInputErrorf("invalid transaction (%x): %w", txID, err)
 	}
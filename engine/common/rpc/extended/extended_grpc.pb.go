@@ -0,0 +1,549 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.21.9
+// source: extended.proto
+
+package extended
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// ExtendedAccessAPIClient is the client API for ExtendedAccessAPI service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ExtendedAccessAPIClient interface {
+	// GetAccountBalanceAtLatestBlock gets the balance of an account at the latest sealed block.
+	GetAccountBalanceAtLatestBlock(ctx context.Context, in *GetAccountBalanceAtLatestBlockRequest, opts ...grpc.CallOption) (*AccountBalanceResponse, error)
+	// GetAccountBalanceAtBlockHeight gets the balance of an account at the given block height.
+	GetAccountBalanceAtBlockHeight(ctx context.Context, in *GetAccountBalanceAtBlockHeightRequest, opts ...grpc.CallOption) (*AccountBalanceResponse, error)
+	// GetAccountKeyAtLatestBlock gets a public key of an account at the latest sealed block.
+	GetAccountKeyAtLatestBlock(ctx context.Context, in *GetAccountKeyAtLatestBlockRequest, opts ...grpc.CallOption) (*AccountKeyResponse, error)
+	// GetAccountKeyAtBlockHeight gets a public key of an account at the given block height.
+	GetAccountKeyAtBlockHeight(ctx context.Context, in *GetAccountKeyAtBlockHeightRequest, opts ...grpc.CallOption) (*AccountKeyResponse, error)
+	// GetAccountKeysAtLatestBlock gets the public keys of an account at the latest sealed block.
+	GetAccountKeysAtLatestBlock(ctx context.Context, in *GetAccountKeysAtLatestBlockRequest, opts ...grpc.CallOption) (*AccountKeysResponse, error)
+	// GetAccountKeysAtBlockHeight gets the public keys of an account at the given block height.
+	GetAccountKeysAtBlockHeight(ctx context.Context, in *GetAccountKeysAtBlockHeightRequest, opts ...grpc.CallOption) (*AccountKeysResponse, error)
+}
+
+type extendedAccessAPIClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewExtendedAccessAPIClient(cc grpc.ClientConnInterface) ExtendedAccessAPIClient {
+	return &extendedAccessAPIClient{cc}
+}
+
+func (c *extendedAccessAPIClient) GetAccountBalanceAtLatestBlock(ctx context.Context, in *GetAccountBalanceAtLatestBlockRequest, opts ...grpc.CallOption) (*AccountBalanceResponse, error) {
+	out := new(AccountBalanceResponse)
+	err := c.cc.Invoke(ctx, "/flow.extended.ExtendedAccessAPI/GetAccountBalanceAtLatestBlock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extendedAccessAPIClient) GetAccountBalanceAtBlockHeight(ctx context.Context, in *GetAccountBalanceAtBlockHeightRequest, opts ...grpc.CallOption) (*AccountBalanceResponse, error) {
+	out := new(AccountBalanceResponse)
+	err := c.cc.Invoke(ctx, "/flow.extended.ExtendedAccessAPI/GetAccountBalanceAtBlockHeight", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extendedAccessAPIClient) GetAccountKeyAtLatestBlock(ctx context.Context, in *GetAccountKeyAtLatestBlockRequest, opts ...grpc.CallOption) (*AccountKeyResponse, error) {
+	out := new(AccountKeyResponse)
+	err := c.cc.Invoke(ctx, "/flow.extended.ExtendedAccessAPI/GetAccountKeyAtLatestBlock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extendedAccessAPIClient) GetAccountKeyAtBlockHeight(ctx context.Context, in *GetAccountKeyAtBlockHeightRequest, opts ...grpc.CallOption) (*AccountKeyResponse, error) {
+	out := new(AccountKeyResponse)
+	err := c.cc.Invoke(ctx, "/flow.extended.ExtendedAccessAPI/GetAccountKeyAtBlockHeight", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extendedAccessAPIClient) GetAccountKeysAtLatestBlock(ctx context.Context, in *GetAccountKeysAtLatestBlockRequest, opts ...grpc.CallOption) (*AccountKeysResponse, error) {
+	out := new(AccountKeysResponse)
+	err := c.cc.Invoke(ctx, "/flow.extended.ExtendedAccessAPI/GetAccountKeysAtLatestBlock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extendedAccessAPIClient) GetAccountKeysAtBlockHeight(ctx context.Context, in *GetAccountKeysAtBlockHeightRequest, opts ...grpc.CallOption) (*AccountKeysResponse, error) {
+	out := new(AccountKeysResponse)
+	err := c.cc.Invoke(ctx, "/flow.extended.ExtendedAccessAPI/GetAccountKeysAtBlockHeight", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ExtendedAccessAPIServer is the server API for ExtendedAccessAPI service.
+// All implementations must embed UnimplementedExtendedAccessAPIServer
+// for forward compatibility
+type ExtendedAccessAPIServer interface {
+	// GetAccountBalanceAtLatestBlock gets the balance of an account at the latest sealed block.
+	GetAccountBalanceAtLatestBlock(context.Context, *GetAccountBalanceAtLatestBlockRequest) (*AccountBalanceResponse, error)
+	// GetAccountBalanceAtBlockHeight gets the balance of an account at the given block height.
+	GetAccountBalanceAtBlockHeight(context.Context, *GetAccountBalanceAtBlockHeightRequest) (*AccountBalanceResponse, error)
+	// GetAccountKeyAtLatestBlock gets a public key of an account at the latest sealed block.
+	GetAccountKeyAtLatestBlock(context.Context, *GetAccountKeyAtLatestBlockRequest) (*AccountKeyResponse, error)
+	// GetAccountKeyAtBlockHeight gets a public key of an account at the given block height.
+	GetAccountKeyAtBlockHeight(context.Context, *GetAccountKeyAtBlockHeightRequest) (*AccountKeyResponse, error)
+	// GetAccountKeysAtLatestBlock gets the public keys of an account at the latest sealed block.
+	GetAccountKeysAtLatestBlock(context.Context, *GetAccountKeysAtLatestBlockRequest) (*AccountKeysResponse, error)
+	// GetAccountKeysAtBlockHeight gets the public keys of an account at the given block height.
+	GetAccountKeysAtBlockHeight(context.Context, *GetAccountKeysAtBlockHeightRequest) (*AccountKeysResponse, error)
+	mustEmbedUnimplementedExtendedAccessAPIServer()
+}
+
+// UnimplementedExtendedAccessAPIServer must be embedded to have forward compatible implementations.
+type UnimplementedExtendedAccessAPIServer struct {
+}
+
+func (UnimplementedExtendedAccessAPIServer) GetAccountBalanceAtLatestBlock(context.Context, *GetAccountBalanceAtLatestBlockRequest) (*AccountBalanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccountBalanceAtLatestBlock not implemented")
+}
+func (UnimplementedExtendedAccessAPIServer) GetAccountBalanceAtBlockHeight(context.Context, *GetAccountBalanceAtBlockHeightRequest) (*AccountBalanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccountBalanceAtBlockHeight not implemented")
+}
+func (UnimplementedExtendedAccessAPIServer) GetAccountKeyAtLatestBlock(context.Context, *GetAccountKeyAtLatestBlockRequest) (*AccountKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccountKeyAtLatestBlock not implemented")
+}
+func (UnimplementedExtendedAccessAPIServer) GetAccountKeyAtBlockHeight(context.Context, *GetAccountKeyAtBlockHeightRequest) (*AccountKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccountKeyAtBlockHeight not implemented")
+}
+func (UnimplementedExtendedAccessAPIServer) GetAccountKeysAtLatestBlock(context.Context, *GetAccountKeysAtLatestBlockRequest) (*AccountKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccountKeysAtLatestBlock not implemented")
+}
+func (UnimplementedExtendedAccessAPIServer) GetAccountKeysAtBlockHeight(context.Context, *GetAccountKeysAtBlockHeightRequest) (*AccountKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccountKeysAtBlockHeight not implemented")
+}
+func (UnimplementedExtendedAccessAPIServer) mustEmbedUnimplementedExtendedAccessAPIServer() {}
+
+// UnsafeExtendedAccessAPIServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ExtendedAccessAPIServer will
+// result in compilation errors.
+type UnsafeExtendedAccessAPIServer interface {
+	mustEmbedUnimplementedExtendedAccessAPIServer()
+}
+
+func RegisterExtendedAccessAPIServer(s grpc.ServiceRegistrar, srv ExtendedAccessAPIServer) {
+	s.RegisterService(&ExtendedAccessAPI_ServiceDesc, srv)
+}
+
+func _ExtendedAccessAPI_GetAccountBalanceAtLatestBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccountBalanceAtLatestBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtendedAccessAPIServer).GetAccountBalanceAtLatestBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/flow.extended.ExtendedAccessAPI/GetAccountBalanceAtLatestBlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtendedAccessAPIServer).GetAccountBalanceAtLatestBlock(ctx, req.(*GetAccountBalanceAtLatestBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExtendedAccessAPI_GetAccountBalanceAtBlockHeight_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccountBalanceAtBlockHeightRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtendedAccessAPIServer).GetAccountBalanceAtBlockHeight(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/flow.extended.ExtendedAccessAPI/GetAccountBalanceAtBlockHeight",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtendedAccessAPIServer).GetAccountBalanceAtBlockHeight(ctx, req.(*GetAccountBalanceAtBlockHeightRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExtendedAccessAPI_GetAccountKeyAtLatestBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccountKeyAtLatestBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtendedAccessAPIServer).GetAccountKeyAtLatestBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/flow.extended.ExtendedAccessAPI/GetAccountKeyAtLatestBlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtendedAccessAPIServer).GetAccountKeyAtLatestBlock(ctx, req.(*GetAccountKeyAtLatestBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExtendedAccessAPI_GetAccountKeyAtBlockHeight_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccountKeyAtBlockHeightRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtendedAccessAPIServer).GetAccountKeyAtBlockHeight(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/flow.extended.ExtendedAccessAPI/GetAccountKeyAtBlockHeight",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtendedAccessAPIServer).GetAccountKeyAtBlockHeight(ctx, req.(*GetAccountKeyAtBlockHeightRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExtendedAccessAPI_GetAccountKeysAtLatestBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccountKeysAtLatestBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtendedAccessAPIServer).GetAccountKeysAtLatestBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/flow.extended.ExtendedAccessAPI/GetAccountKeysAtLatestBlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtendedAccessAPIServer).GetAccountKeysAtLatestBlock(ctx, req.(*GetAccountKeysAtLatestBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExtendedAccessAPI_GetAccountKeysAtBlockHeight_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccountKeysAtBlockHeightRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtendedAccessAPIServer).GetAccountKeysAtBlockHeight(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/flow.extended.ExtendedAccessAPI/GetAccountKeysAtBlockHeight",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtendedAccessAPIServer).GetAccountKeysAtBlockHeight(ctx, req.(*GetAccountKeysAtBlockHeightRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ExtendedAccessAPI_ServiceDesc is the grpc.ServiceDesc for ExtendedAccessAPI service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ExtendedAccessAPI_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "flow.extended.ExtendedAccessAPI",
+	HandlerType: (*ExtendedAccessAPIServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetAccountBalanceAtLatestBlock",
+			Handler:    _ExtendedAccessAPI_GetAccountBalanceAtLatestBlock_Handler,
+		},
+		{
+			MethodName: "GetAccountBalanceAtBlockHeight",
+			Handler:    _ExtendedAccessAPI_GetAccountBalanceAtBlockHeight_Handler,
+		},
+		{
+			MethodName: "GetAccountKeyAtLatestBlock",
+			Handler:    _ExtendedAccessAPI_GetAccountKeyAtLatestBlock_Handler,
+		},
+		{
+			MethodName: "GetAccountKeyAtBlockHeight",
+			Handler:    _ExtendedAccessAPI_GetAccountKeyAtBlockHeight_Handler,
+		},
+		{
+			MethodName: "GetAccountKeysAtLatestBlock",
+			Handler:    _ExtendedAccessAPI_GetAccountKeysAtLatestBlock_Handler,
+		},
+		{
+			MethodName: "GetAccountKeysAtBlockHeight",
+			Handler:    _ExtendedAccessAPI_GetAccountKeysAtBlockHeight_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "extended.proto",
+}
+
+// ExtendedExecutionAPIClient is the client API for ExtendedExecutionAPI service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ExtendedExecutionAPIClient interface {
+	// GetAccountBalanceAtBlockID gets the balance of an account at the given block, reading only the
+	// registers storing it.
+	GetAccountBalanceAtBlockID(ctx context.Context, in *GetAccountBalanceAtBlockIDRequest, opts ...grpc.CallOption) (*AccountBalanceResponse, error)
+	// GetAccountKeyAtBlockID gets a public key of an account at the given block, reading only the
+	// register storing it.
+	GetAccountKeyAtBlockID(ctx context.Context, in *GetAccountKeyAtBlockIDRequest, opts ...grpc.CallOption) (*AccountKeyResponse, error)
+	// GetAccountKeysAtBlockID gets the public keys of an account at the given block, reading only the
+	// registers storing them.
+	GetAccountKeysAtBlockID(ctx context.Context, in *GetAccountKeysAtBlockIDRequest, opts ...grpc.CallOption) (*AccountKeysResponse, error)
+	// SimulateTransactionAtBlockID executes a transaction at the given block without applying its
+	// state changes.
+	SimulateTransactionAtBlockID(ctx context.Context, in *SimulateTransactionAtBlockIDRequest, opts ...grpc.CallOption) (*SimulateTransactionResponse, error)
+	// IterateRegistersAtBlockID lists the registers of the execution state at the given block, one
+	// page at a time. It is meant for debugging, since finding the registers of an account requires
+	// visiting all registers of the state in the worst case.
+	IterateRegistersAtBlockID(ctx context.Context, in *IterateRegistersAtBlockIDRequest, opts ...grpc.CallOption) (*IterateRegistersResponse, error)
+}
+
+type extendedExecutionAPIClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewExtendedExecutionAPIClient(cc grpc.ClientConnInterface) ExtendedExecutionAPIClient {
+	return &extendedExecutionAPIClient{cc}
+}
+
+func (c *extendedExecutionAPIClient) GetAccountBalanceAtBlockID(ctx context.Context, in *GetAccountBalanceAtBlockIDRequest, opts ...grpc.CallOption) (*AccountBalanceResponse, error) {
+	out := new(AccountBalanceResponse)
+	err := c.cc.Invoke(ctx, "/flow.extended.ExtendedExecutionAPI/GetAccountBalanceAtBlockID", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extendedExecutionAPIClient) GetAccountKeyAtBlockID(ctx context.Context, in *GetAccountKeyAtBlockIDRequest, opts ...grpc.CallOption) (*AccountKeyResponse, error) {
+	out := new(AccountKeyResponse)
+	err := c.cc.Invoke(ctx, "/flow.extended.ExtendedExecutionAPI/GetAccountKeyAtBlockID", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extendedExecutionAPIClient) GetAccountKeysAtBlockID(ctx context.Context, in *GetAccountKeysAtBlockIDRequest, opts ...grpc.CallOption) (*AccountKeysResponse, error) {
+	out := new(AccountKeysResponse)
+	err := c.cc.Invoke(ctx, "/flow.extended.ExtendedExecutionAPI/GetAccountKeysAtBlockID", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extendedExecutionAPIClient) SimulateTransactionAtBlockID(ctx context.Context, in *SimulateTransactionAtBlockIDRequest, opts ...grpc.CallOption) (*SimulateTransactionResponse, error) {
+	out := new(SimulateTransactionResponse)
+	err := c.cc.Invoke(ctx, "/flow.extended.ExtendedExecutionAPI/SimulateTransactionAtBlockID", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extendedExecutionAPIClient) IterateRegistersAtBlockID(ctx context.Context, in *IterateRegistersAtBlockIDRequest, opts ...grpc.CallOption) (*IterateRegistersResponse, error) {
+	out := new(IterateRegistersResponse)
+	err := c.cc.Invoke(ctx, "/flow.extended.ExtendedExecutionAPI/IterateRegistersAtBlockID", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ExtendedExecutionAPIServer is the server API for ExtendedExecutionAPI service.
+// All implementations must embed UnimplementedExtendedExecutionAPIServer
+// for forward compatibility
+type ExtendedExecutionAPIServer interface {
+	// GetAccountBalanceAtBlockID gets the balance of an account at the given block, reading only the
+	// registers storing it.
+	GetAccountBalanceAtBlockID(context.Context, *GetAccountBalanceAtBlockIDRequest) (*AccountBalanceResponse, error)
+	// GetAccountKeyAtBlockID gets a public key of an account at the given block, reading only the
+	// register storing it.
+	GetAccountKeyAtBlockID(context.Context, *GetAccountKeyAtBlockIDRequest) (*AccountKeyResponse, error)
+	// GetAccountKeysAtBlockID gets the public keys of an account at the given block, reading only the
+	// registers storing them.
+	GetAccountKeysAtBlockID(context.Context, *GetAccountKeysAtBlockIDRequest) (*AccountKeysResponse, error)
+	// SimulateTransactionAtBlockID executes a transaction at the given block without applying its
+	// state changes.
+	SimulateTransactionAtBlockID(context.Context, *SimulateTransactionAtBlockIDRequest) (*SimulateTransactionResponse, error)
+	// IterateRegistersAtBlockID lists the registers of the execution state at the given block, one
+	// page at a time. It is meant for debugging, since finding the registers of an account requires
+	// visiting all registers of the state in the worst case.
+	IterateRegistersAtBlockID(context.Context, *IterateRegistersAtBlockIDRequest) (*IterateRegistersResponse, error)
+	mustEmbedUnimplementedExtendedExecutionAPIServer()
+}
+
+// UnimplementedExtendedExecutionAPIServer must be embedded to have forward compatible implementations.
+type UnimplementedExtendedExecutionAPIServer struct {
+}
+
+func (UnimplementedExtendedExecutionAPIServer) GetAccountBalanceAtBlockID(context.Context, *GetAccountBalanceAtBlockIDRequest) (*AccountBalanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccountBalanceAtBlockID not implemented")
+}
+func (UnimplementedExtendedExecutionAPIServer) GetAccountKeyAtBlockID(context.Context, *GetAccountKeyAtBlockIDRequest) (*AccountKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccountKeyAtBlockID not implemented")
+}
+func (UnimplementedExtendedExecutionAPIServer) GetAccountKeysAtBlockID(context.Context, *GetAccountKeysAtBlockIDRequest) (*AccountKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccountKeysAtBlockID not implemented")
+}
+func (UnimplementedExtendedExecutionAPIServer) SimulateTransactionAtBlockID(context.Context, *SimulateTransactionAtBlockIDRequest) (*SimulateTransactionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SimulateTransactionAtBlockID not implemented")
+}
+func (UnimplementedExtendedExecutionAPIServer) IterateRegistersAtBlockID(context.Context, *IterateRegistersAtBlockIDRequest) (*IterateRegistersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IterateRegistersAtBlockID not implemented")
+}
+func (UnimplementedExtendedExecutionAPIServer) mustEmbedUnimplementedExtendedExecutionAPIServer() {}
+
+// UnsafeExtendedExecutionAPIServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ExtendedExecutionAPIServer will
+// result in compilation errors.
+type UnsafeExtendedExecutionAPIServer interface {
+	mustEmbedUnimplementedExtendedExecutionAPIServer()
+}
+
+func RegisterExtendedExecutionAPIServer(s grpc.ServiceRegistrar, srv ExtendedExecutionAPIServer) {
+	s.RegisterService(&ExtendedExecutionAPI_ServiceDesc, srv)
+}
+
+func _ExtendedExecutionAPI_GetAccountBalanceAtBlockID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccountBalanceAtBlockIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtendedExecutionAPIServer).GetAccountBalanceAtBlockID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/flow.extended.ExtendedExecutionAPI/GetAccountBalanceAtBlockID",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtendedExecutionAPIServer).GetAccountBalanceAtBlockID(ctx, req.(*GetAccountBalanceAtBlockIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExtendedExecutionAPI_GetAccountKeyAtBlockID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccountKeyAtBlockIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtendedExecutionAPIServer).GetAccountKeyAtBlockID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/flow.extended.ExtendedExecutionAPI/GetAccountKeyAtBlockID",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtendedExecutionAPIServer).GetAccountKeyAtBlockID(ctx, req.(*GetAccountKeyAtBlockIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExtendedExecutionAPI_GetAccountKeysAtBlockID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccountKeysAtBlockIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtendedExecutionAPIServer).GetAccountKeysAtBlockID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/flow.extended.ExtendedExecutionAPI/GetAccountKeysAtBlockID",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtendedExecutionAPIServer).GetAccountKeysAtBlockID(ctx, req.(*GetAccountKeysAtBlockIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExtendedExecutionAPI_SimulateTransactionAtBlockID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SimulateTransactionAtBlockIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtendedExecutionAPIServer).SimulateTransactionAtBlockID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/flow.extended.ExtendedExecutionAPI/SimulateTransactionAtBlockID",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtendedExecutionAPIServer).SimulateTransactionAtBlockID(ctx, req.(*SimulateTransactionAtBlockIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExtendedExecutionAPI_IterateRegistersAtBlockID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IterateRegistersAtBlockIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtendedExecutionAPIServer).IterateRegistersAtBlockID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/flow.extended.ExtendedExecutionAPI/IterateRegistersAtBlockID",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtendedExecutionAPIServer).IterateRegistersAtBlockID(ctx, req.(*IterateRegistersAtBlockIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ExtendedExecutionAPI_ServiceDesc is the grpc.ServiceDesc for ExtendedExecutionAPI service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ExtendedExecutionAPI_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "flow.extended.ExtendedExecutionAPI",
+	HandlerType: (*ExtendedExecutionAPIServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetAccountBalanceAtBlockID",
+			Handler:    _ExtendedExecutionAPI_GetAccountBalanceAtBlockID_Handler,
+		},
+		{
+			MethodName: "GetAccountKeyAtBlockID",
+			Handler:    _ExtendedExecutionAPI_GetAccountKeyAtBlockID_Handler,
+		},
+		{
+			MethodName: "GetAccountKeysAtBlockID",
+			Handler:    _ExtendedExecutionAPI_GetAccountKeysAtBlockID_Handler,
+		},
+		{
+			MethodName: "SimulateTransactionAtBlockID",
+			Handler:    _ExtendedExecutionAPI_SimulateTransactionAtBlockID_Handler,
+		},
+		{
+			MethodName: "IterateRegistersAtBlockID",
+			Handler:    _ExtendedExecutionAPI_IterateRegistersAtBlockID_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "extended.proto",
+}
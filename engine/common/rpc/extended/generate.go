@@ -0,0 +1,5 @@
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative extended.proto
+
+// Package extended contains the gRPC services served by access and execution nodes alongside the
+// Access and Execution APIs, for the calls not defined by the flow protobuf definitions.
+package extended
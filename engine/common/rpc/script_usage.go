@@ -0,0 +1,135 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/onflow/flow-go/fvm/meter"
+)
+
+// ScriptUsageHeader is the request metadata key with which clients request the computation and
+// memory used by a script. If it is set to "true", the usage is returned in the response trailer,
+// since the API messages have no fields for it.
+const ScriptUsageHeader = "x-flow-script-usage"
+
+// Response trailer keys of the computation and memory used by a script.
+// Intensities are returned as one "<kind>=<intensity>" value per metered kind.
+const (
+	ComputationUsedTrailer        = "x-flow-computation-used"
+	ComputationLimitTrailer       = "x-flow-computation-limit"
+	ComputationIntensitiesTrailer = "x-flow-computation-intensities"
+	MemoryEstimateTrailer         = "x-flow-memory-estimate"
+	MemoryLimitTrailer            = "x-flow-memory-limit"
+	MemoryIntensitiesTrailer      = "x-flow-memory-intensities"
+)
+
+var scriptUsageTrailers = []string{
+	ComputationUsedTrailer,
+	ComputationLimitTrailer,
+	ComputationIntensitiesTrailer,
+	MemoryEstimateTrailer,
+	MemoryLimitTrailer,
+	MemoryIntensitiesTrailer,
+}
+
+// ScriptUsageRequested returns whether the client of the incoming request requested the
+// computation and memory used by the script.
+func ScriptUsageRequested(ctx context.Context) bool {
+	if _, ok := ctx.Value(scriptUsageRecorderKey{}).(*ScriptUsageRecorder); ok {
+		return true
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+	values := md.Get(ScriptUsageHeader)
+	return len(values) > 0 && values[0] == "true"
+}
+
+// WithScriptUsageRequested returns a context with which outgoing requests request the computation
+// and memory used by the script.
+func WithScriptUsageRequested(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, ScriptUsageHeader, "true")
+}
+
+type scriptUsageRecorderKey struct{}
+
+// ScriptUsageRecorder records the computation and memory used by a script for requests which are
+// not received over gRPC, e.g. through the REST API, and so have no response trailer.
+type ScriptUsageRecorder struct {
+	usage metadata.MD
+}
+
+// WithScriptUsageRecorder returns a context with which the computation and memory used by the
+// script are requested, and recorded by the returned recorder instead of the response trailer.
+func WithScriptUsageRecorder(ctx context.Context) (context.Context, *ScriptUsageRecorder) {
+	recorder := &ScriptUsageRecorder{}
+	return context.WithValue(ctx, scriptUsageRecorderKey{}, recorder), recorder
+}
+
+// RecordScriptUsage records the computation and memory used by a script, in the format of the
+// response trailer, with the recorder of the given context. Returns false if the context has no
+// recorder.
+func RecordScriptUsage(ctx context.Context, usage metadata.MD) bool {
+	recorder, ok := ctx.Value(scriptUsageRecorderKey{}).(*ScriptUsageRecorder)
+	if !ok {
+		return false
+	}
+	recorder.usage = usage
+	return true
+}
+
+// Usage returns the recorded computation and memory used by the script, in the format of the
+// response trailer. Returns nil if no usage was recorded.
+func (r *ScriptUsageRecorder) Usage() metadata.MD {
+	return r.usage
+}
+
+// ScriptUsageTrailer returns the response trailer with the given computation and memory used by
+// a script.
+func ScriptUsageTrailer(usage meter.Usage) metadata.MD {
+	computationIntensities := make([]string, 0, len(usage.ComputationIntensities))
+	for kind, intensity := range usage.ComputationIntensities {
+		computationIntensities = append(computationIntensities, fmt.Sprintf("%s=%d", kind, intensity))
+	}
+	sort.Strings(computationIntensities)
+
+	memoryIntensities := make([]string, 0, len(usage.MemoryIntensities))
+	for kind, intensity := range usage.MemoryIntensities {
+		memoryIntensities = append(memoryIntensities, fmt.Sprintf("%s=%d", kind, intensity))
+	}
+	sort.Strings(memoryIntensities)
+
+	md := metadata.Pairs(
+		ComputationUsedTrailer, strconv.FormatUint(usage.ComputationUsed, 10),
+		ComputationLimitTrailer, strconv.FormatUint(usage.ComputationLimit, 10),
+		MemoryEstimateTrailer, strconv.FormatUint(usage.MemoryEstimate, 10),
+		MemoryLimitTrailer, strconv.FormatUint(usage.MemoryLimit, 10),
+	)
+	md.Append(ComputationIntensitiesTrailer, computationIntensities...)
+	md.Append(MemoryIntensitiesTrailer, memoryIntensities...)
+	return md
+}
+
+// FilterScriptUsageTrailer returns the computation and memory used by a script from the given
+// response trailer, so that it can be forwarded to the client. Returns nil if the trailer
+// contains no usage.
+func FilterScriptUsageTrailer(trailer metadata.MD) metadata.MD {
+	var md metadata.MD
+	for _, key := range scriptUsageTrailers {
+		values := trailer.Get(key)
+		if len(values) == 0 {
+			continue
+		}
+		if md == nil {
+			md = metadata.MD{}
+		}
+		md.Set(key, values...)
+	}
+	return md
+}
@@ -0,0 +1,74 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/onflow/flow-go/fvm/meter"
+)
+
+func TestScriptUsageRequested(t *testing.T) {
+	ctx := context.Background()
+	assert.False(t, ScriptUsageRequested(ctx))
+
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(ScriptUsageHeader, "false"))
+	assert.False(t, ScriptUsageRequested(ctx))
+
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(ScriptUsageHeader, "true"))
+	assert.True(t, ScriptUsageRequested(ctx))
+
+	// the request is forwarded in outgoing requests
+	md, ok := metadata.FromOutgoingContext(WithScriptUsageRequested(context.Background()))
+	assert.True(t, ok)
+	assert.Equal(t, []string{"true"}, md.Get(ScriptUsageHeader))
+}
+
+func TestScriptUsageTrailer(t *testing.T) {
+	trailer := ScriptUsageTrailer(meter.Usage{
+		ComputationUsed:  12,
+		ComputationLimit: 9999,
+		ComputationIntensities: meter.MeteredComputationIntensities{
+			common.ComputationKindStatement: 3,
+			common.ComputationKindLoop:      5,
+		},
+		MemoryEstimate: 2048,
+		MemoryLimit:    4096,
+		MemoryIntensities: meter.MeteredMemoryIntensities{
+			common.MemoryKindStringValue: 2,
+		},
+	})
+
+	assert.Equal(t, []string{"12"}, trailer.Get(ComputationUsedTrailer))
+	assert.Equal(t, []string{"9999"}, trailer.Get(ComputationLimitTrailer))
+	assert.Equal(t, []string{"Loop=5", "Statement=3"}, trailer.Get(ComputationIntensitiesTrailer))
+	assert.Equal(t, []string{"2048"}, trailer.Get(MemoryEstimateTrailer))
+	assert.Equal(t, []string{"4096"}, trailer.Get(MemoryLimitTrailer))
+	assert.Equal(t, []string{"StringValue=2"}, trailer.Get(MemoryIntensitiesTrailer))
+
+	// only the usage is forwarded
+	trailer.Set("unrelated", "value")
+	filtered := FilterScriptUsageTrailer(trailer)
+	assert.Len(t, filtered, len(scriptUsageTrailers))
+	assert.Empty(t, filtered.Get("unrelated"))
+	assert.Equal(t, trailer.Get(ComputationIntensitiesTrailer), filtered.Get(ComputationIntensitiesTrailer))
+
+	assert.Nil(t, FilterScriptUsageTrailer(metadata.Pairs("unrelated", "value")))
+}
+
+func TestScriptUsageRecorder(t *testing.T) {
+	usage := ScriptUsageTrailer(meter.Usage{ComputationUsed: 12})
+
+	// no recorder, the usage is sent in the response trailer
+	assert.False(t, RecordScriptUsage(context.Background(), usage))
+
+	ctx, recorder := WithScriptUsageRecorder(context.Background())
+	assert.True(t, ScriptUsageRequested(ctx))
+	assert.Nil(t, recorder.Usage())
+
+	assert.True(t, RecordScriptUsage(ctx, usage))
+	assert.Equal(t, usage, recorder.Usage())
+}
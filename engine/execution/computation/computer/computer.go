@@ -128,6 +128,10 @@ type blockComputer struct {
 	spockHasher           hash.Hasher
 	receiptHasher         hash.Hasher
 	colResCons            []result.ExecutedCollectionConsumer
+
+	executionMode   TransactionExecutionMode
+	parallelWorkers int
+	speculativeVM   fvm.SpeculativeVM
 }
 
 func SystemChunkContext(vmCtx fvm.Context, logger zerolog.Logger) fvm.Context {
@@ -155,13 +159,14 @@ func NewBlockComputer(
 	signer module.Local,
 	executionDataProvider *provider.Provider,
 	colResCons []result.ExecutedCollectionConsumer,
+	opts ...BlockComputerOption,
 ) (BlockComputer, error) {
 	systemChunkCtx := SystemChunkContext(vmCtx, logger)
 	vmCtx = fvm.NewContextFromParent(
 		vmCtx,
 		fvm.WithMetricsReporter(metrics),
 		fvm.WithTracer(tracer))
-	return &blockComputer{
+	e := &blockComputer{
 		vm:                    vm,
 		vmCtx:                 vmCtx,
 		metrics:               metrics,
@@ -174,7 +179,29 @@ func NewBlockComputer(
 		spockHasher:           utils.NewSPOCKHasher(),
 		receiptHasher:         utils.NewExecutionReceiptHasher(),
 		colResCons:            colResCons,
-	}, nil
+		executionMode:         TransactionExecutionModeSequential,
+	}
+
+	for _, apply := range opts {
+		apply(e)
+	}
+
+	if e.executionMode != TransactionExecutionModeSequential {
+		speculativeVM, ok := vm.(fvm.SpeculativeVM)
+		if !ok {
+			return nil, fmt.Errorf(
+				"%s transaction execution requires a vm supporting speculative execution",
+				e.executionMode)
+		}
+		if e.parallelWorkers < 1 {
+			return nil, fmt.Errorf(
+				"invalid number of parallel execution workers: %d",
+				e.parallelWorkers)
+		}
+		e.speculativeVM = speculativeVM
+	}
+
+	return e, nil
 }
 
 // ExecuteBlock executes a block and returns the resulting chunks.
@@ -311,28 +338,29 @@ func (e *blockComputer) executeBlock(
 		e.colResCons)
 	defer collector.Stop()
 
-	snapshotTree := storage.NewSnapshotTree(baseSnapshot)
-	for _, txn := range transactions {
-		txnExecutionSnapshot, output, err := e.executeTransaction(
+	switch e.executionMode {
+	case TransactionExecutionModeParallel:
+		err = e.executeTransactionsInParallel(
 			blockSpan,
-			txn,
-			snapshotTree,
-			collector)
-		if err != nil {
-			prefix := ""
-			if txn.isSystemTransaction {
-				prefix = "system "
-			}
-
-			return nil, fmt.Errorf(
-				"failed to execute %stransaction at txnIndex %v: %w",
-				prefix,
-				txn.txnIndex,
-				err)
-		}
-
-		collector.AddTransactionResult(txn, txnExecutionSnapshot, output)
-		snapshotTree = snapshotTree.Append(txnExecutionSnapshot)
+			transactions,
+			baseSnapshot,
+			collector.AddTransactionResult)
+	case TransactionExecutionModeCompare:
+		err = e.executeTransactionsAndCompare(
+			blockSpan,
+			block,
+			transactions,
+			baseSnapshot,
+			collector.AddTransactionResult)
+	default:
+		err = e.executeTransactionsSequentially(
+			blockSpan,
+			transactions,
+			baseSnapshot,
+			collector.AddTransactionResult)
+	}
+	if err != nil {
+		return nil, err
 	}
 
 	res, err := collector.Finalize(ctx)
@@ -349,11 +377,81 @@ func (e *blockComputer) executeBlock(
 	return res, nil
 }
 
+// transactionResultConsumer receives the results of a block's transactions,
+// in transaction order.
+type transactionResultConsumer func(
+	txn transaction,
+	snapshot *state.ExecutionSnapshot,
+	output fvm.ProcedureOutput,
+)
+
+func (e *blockComputer) executeTransactionsSequentially(
+	blockSpan otelTrace.Span,
+	transactions []transaction,
+	baseSnapshot state.StorageSnapshot,
+	consume transactionResultConsumer,
+) error {
+	snapshotTree := storage.NewSnapshotTree(baseSnapshot)
+	for _, txn := range transactions {
+		txnExecutionSnapshot, output, err := e.executeTransaction(
+			blockSpan,
+			txn,
+			snapshotTree)
+		if err != nil {
+			return transactionExecutionError(txn, err)
+		}
+
+		consume(txn, txnExecutionSnapshot, output)
+		snapshotTree = snapshotTree.Append(txnExecutionSnapshot)
+	}
+
+	return nil
+}
+
+func transactionExecutionError(txn transaction, err error) error {
+	prefix := ""
+	if txn.isSystemTransaction {
+		prefix = "system "
+	}
+
+	return fmt.Errorf(
+		"failed to execute %stransaction at txnIndex %v: %w",
+		prefix,
+		txn.txnIndex,
+		err)
+}
+
 func (e *blockComputer) executeTransaction(
 	parentSpan otelTrace.Span,
 	txn transaction,
 	storageSnapshot state.StorageSnapshot,
-	collector *resultCollector,
+) (
+	*state.ExecutionSnapshot,
+	fvm.ProcedureOutput,
+	error,
+) {
+	return e.runTransaction(
+		parentSpan,
+		txn,
+		func(ctx fvm.Context) (
+			*state.ExecutionSnapshot,
+			fvm.ProcedureOutput,
+			error,
+		) {
+			return e.vm.Run(ctx, txn.TransactionProcedure, storageSnapshot)
+		})
+}
+
+// runTransaction runs the transaction with the given run function, and
+// reports its execution.
+func (e *blockComputer) runTransaction(
+	parentSpan otelTrace.Span,
+	txn transaction,
+	run func(ctx fvm.Context) (
+		*state.ExecutionSnapshot,
+		fvm.ProcedureOutput,
+		error,
+	),
 ) (
 	*state.ExecutionSnapshot,
 	fvm.ProcedureOutput,
@@ -385,10 +483,7 @@ func (e *blockComputer) executeTransaction(
 
 	txn.ctx = fvm.NewContextFromParent(txn.ctx, fvm.WithSpan(txSpan))
 
-	executionSnapshot, output, err := e.vm.Run(
-		txn.ctx,
-		txn.TransactionProcedure,
-		storageSnapshot)
+	executionSnapshot, output, err := run(txn.ctx)
 	if err != nil {
 		return nil, fvm.ProcedureOutput{}, fmt.Errorf(
 			"failed to execute transaction %v for block %s at height %v: %w",
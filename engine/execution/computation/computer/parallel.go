@@ -0,0 +1,419 @@
+package computer
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sync"
+
+	otelTrace "go.opentelemetry.io/otel/trace"
+
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/state"
+	"github.com/onflow/flow-go/fvm/storage"
+	"github.com/onflow/flow-go/fvm/storage/derived"
+	"github.com/onflow/flow-go/fvm/storage/logical"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/mempool/entity"
+	"github.com/onflow/flow-go/utils/logging"
+)
+
+// TransactionExecutionMode determines how the block computer executes the
+// transactions of a block.
+type TransactionExecutionMode int
+
+const (
+	// TransactionExecutionModeSequential executes the transactions one after
+	// the other, each against the state produced by its predecessors.
+	TransactionExecutionModeSequential TransactionExecutionMode = iota
+
+	// TransactionExecutionModeParallel executes the transactions
+	// optimistically in parallel. Transactions which read registers written
+	// by a concurrently executed predecessor are re-executed in order, so
+	// the results are identical to sequential execution.
+	TransactionExecutionModeParallel
+
+	// TransactionExecutionModeCompare executes the transactions both
+	// sequentially and in parallel, and logs any differences. The results of
+	// the sequential execution are used.
+	TransactionExecutionModeCompare
+)
+
+// ParseTransactionExecutionMode parses the string representation of a
+// TransactionExecutionMode.
+func ParseTransactionExecutionMode(s string) (TransactionExecutionMode, error) {
+	switch s {
+	case TransactionExecutionModeSequential.String():
+		return TransactionExecutionModeSequential, nil
+	case TransactionExecutionModeParallel.String():
+		return TransactionExecutionModeParallel, nil
+	case TransactionExecutionModeCompare.String():
+		return TransactionExecutionModeCompare, nil
+	default:
+		return 0, fmt.Errorf("invalid transaction execution mode: %s", s)
+	}
+}
+
+func (m TransactionExecutionMode) String() string {
+	switch m {
+	case TransactionExecutionModeSequential:
+		return "sequential"
+	case TransactionExecutionModeParallel:
+		return "parallel"
+	case TransactionExecutionModeCompare:
+		return "compare"
+	default:
+		return ""
+	}
+}
+
+// BlockComputerOption configures optional behaviour of the block computer.
+type BlockComputerOption func(*blockComputer)
+
+// WithTransactionExecutionMode sets how the transactions of a block are
+// executed. For the parallel and compare modes, workers is the maximum
+// number of transactions executed concurrently.
+func WithTransactionExecutionMode(
+	mode TransactionExecutionMode,
+	workers int,
+) BlockComputerOption {
+	return func(e *blockComputer) {
+		e.executionMode = mode
+		e.parallelWorkers = workers
+	}
+}
+
+// speculativeResult is the result of a transaction executed against a
+// snapshot which may not include the changes of all its predecessors.
+type speculativeResult struct {
+	snapshot       *state.ExecutionSnapshot
+	output         fvm.ProcedureOutput
+	derivedTxnData derived.DerivedTransactionCommitter
+	err            error
+}
+
+// executeTransactionsInParallel executes the transactions in batches of up to
+// parallelWorkers transactions. All transactions of a batch are executed
+// concurrently against the state produced by the preceding batches. The
+// results are then committed in transaction order. A transaction which read
+// a register written by a preceding transaction of the same batch, or whose
+// derived data (e.g. cached programs) was invalidated by one, is re-executed
+// against the up-to-date state before it is committed.
+//
+// The system transaction is always executed on its own.
+func (e *blockComputer) executeTransactionsInParallel(
+	blockSpan otelTrace.Span,
+	transactions []transaction,
+	baseSnapshot state.StorageSnapshot,
+	consume transactionResultConsumer,
+) error {
+	snapshotTree := storage.NewSnapshotTree(baseSnapshot)
+
+	executed := 0
+	reexecuted := 0
+	for start := 0; start < len(transactions); {
+		end := start + 1
+		if !transactions[start].isSystemTransaction {
+			for end < len(transactions) &&
+				end-start < e.parallelWorkers &&
+				!transactions[end].isSystemTransaction {
+				end++
+			}
+		}
+
+		var err error
+		var batchReexecuted int
+		snapshotTree, batchReexecuted, err = e.executeBatch(
+			blockSpan,
+			transactions[start:end],
+			snapshotTree,
+			consume)
+		if err != nil {
+			return err
+		}
+
+		executed += end - start
+		reexecuted += batchReexecuted
+		start = end
+	}
+
+	e.log.Debug().
+		Int("transactions", executed).
+		Int("reexecuted_transactions", reexecuted).
+		Msg("transactions executed in parallel")
+
+	return nil
+}
+
+// executeBatch executes the batch of transactions concurrently, commits
+// their results in order, and returns the resulting snapshot tree and the
+// number of transactions which had to be re-executed.
+func (e *blockComputer) executeBatch(
+	blockSpan otelTrace.Span,
+	batch []transaction,
+	snapshotTree storage.SnapshotTree,
+	consume transactionResultConsumer,
+) (
+	storage.SnapshotTree,
+	int,
+	error,
+) {
+	if len(batch) == 1 {
+		snapshot, output, err := e.executeTransaction(
+			blockSpan,
+			batch[0],
+			snapshotTree)
+		if err != nil {
+			return snapshotTree, 0, transactionExecutionError(batch[0], err)
+		}
+
+		consume(batch[0], snapshot, output)
+		return snapshotTree.Append(snapshot), 0, nil
+	}
+
+	// the snapshot tree includes the changes of all transactions preceding
+	// the batch.
+	snapshotTime := logical.Time(batch[0].txnIndex)
+
+	results := make([]speculativeResult, len(batch))
+	wg := sync.WaitGroup{}
+	for i := range batch {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			results[i] = e.executeTransactionSpeculatively(
+				blockSpan,
+				batch[i],
+				snapshotTime,
+				snapshotTree)
+		}(i)
+	}
+	wg.Wait()
+
+	reexecuted := 0
+	written := map[flow.RegisterID]struct{}{}
+	for i, txn := range batch {
+		snapshot := results[i].snapshot
+		output := results[i].output
+
+		if canCommitSpeculativeResult(results[i], written) {
+			err := results[i].derivedTxnData.Commit()
+			if err != nil {
+				return snapshotTree, 0, transactionExecutionError(
+					txn,
+					fmt.Errorf("failed to commit derived data: %w", err))
+			}
+		} else {
+			reexecuted++
+
+			var err error
+			snapshot, output, err = e.executeTransaction(
+				blockSpan,
+				txn,
+				snapshotTree)
+			if err != nil {
+				return snapshotTree, 0, transactionExecutionError(txn, err)
+			}
+		}
+
+		for id := range snapshot.WriteSet {
+			written[id] = struct{}{}
+		}
+
+		consume(txn, snapshot, output)
+		snapshotTree = snapshotTree.Append(snapshot)
+	}
+
+	return snapshotTree, reexecuted, nil
+}
+
+func (e *blockComputer) executeTransactionSpeculatively(
+	blockSpan otelTrace.Span,
+	txn transaction,
+	snapshotTime logical.Time,
+	storageSnapshot state.StorageSnapshot,
+) speculativeResult {
+	var derivedTxnData derived.DerivedTransactionCommitter
+	snapshot, output, err := e.runTransaction(
+		blockSpan,
+		txn,
+		func(ctx fvm.Context) (
+			*state.ExecutionSnapshot,
+			fvm.ProcedureOutput,
+			error,
+		) {
+			var snapshot *state.ExecutionSnapshot
+			var output fvm.ProcedureOutput
+			var err error
+			snapshot, output, derivedTxnData, err = e.speculativeVM.RunSpeculatively(
+				ctx,
+				txn.TransactionProcedure,
+				snapshotTime,
+				storageSnapshot)
+			return snapshot, output, err
+		})
+
+	return speculativeResult{
+		snapshot:       snapshot,
+		output:         output,
+		derivedTxnData: derivedTxnData,
+		err:            err,
+	}
+}
+
+// canCommitSpeculativeResult returns true if the speculative execution
+// succeeded, did not read any of the written registers, and its derived data
+// is still valid. Otherwise, the transaction must be re-executed.
+func canCommitSpeculativeResult(
+	result speculativeResult,
+	written map[flow.RegisterID]struct{},
+) bool {
+	if result.err != nil {
+		// errors are reported by the re-execution, if they are not caused
+		// by the speculation.
+		return false
+	}
+
+	for id := range result.snapshot.ReadSet {
+		if _, ok := written[id]; ok {
+			return false
+		}
+	}
+
+	return result.derivedTxnData.Validate() == nil
+}
+
+// executeTransactionsAndCompare executes the transactions sequentially, and
+// then again in parallel, and logs any difference between the two. Only the
+// results of the sequential execution are passed on to the consumer.
+//
+// The parallel execution uses its own derived block data, so it does not
+// interfere with the derived data of the sequential execution.
+func (e *blockComputer) executeTransactionsAndCompare(
+	blockSpan otelTrace.Span,
+	block *entity.ExecutableBlock,
+	transactions []transaction,
+	baseSnapshot state.StorageSnapshot,
+	consume transactionResultConsumer,
+) error {
+	expected := make([]transactionResult, 0, len(transactions))
+	err := e.executeTransactionsSequentially(
+		blockSpan,
+		transactions,
+		baseSnapshot,
+		func(
+			txn transaction,
+			snapshot *state.ExecutionSnapshot,
+			output fvm.ProcedureOutput,
+		) {
+			expected = append(expected, transactionResult{
+				transaction:       txn,
+				ExecutionSnapshot: snapshot,
+				ProcedureOutput:   output,
+			})
+			consume(txn, snapshot, output)
+		})
+	if err != nil {
+		return err
+	}
+
+	log := e.log.With().
+		Hex("block_id", logging.Entity(block)).
+		Uint64("height", block.Height()).
+		Logger()
+
+	_, parallelTransactions, err := e.getRootSpanAndTransactions(
+		block,
+		derived.NewEmptyDerivedBlockData())
+	if err != nil {
+		log.Error().Err(err).Msg("failed to prepare parallel execution for comparison")
+		return nil
+	}
+
+	actual := make([]transactionResult, 0, len(parallelTransactions))
+	err = e.executeTransactionsInParallel(
+		blockSpan,
+		parallelTransactions,
+		baseSnapshot,
+		func(
+			txn transaction,
+			snapshot *state.ExecutionSnapshot,
+			output fvm.ProcedureOutput,
+		) {
+			actual = append(actual, transactionResult{
+				transaction:       txn,
+				ExecutionSnapshot: snapshot,
+				ProcedureOutput:   output,
+			})
+		})
+	if err != nil {
+		log.Error().Err(err).Msg("parallel execution failed, while sequential execution succeeded")
+		return nil
+	}
+
+	mismatches := 0
+	for i := range expected {
+		diff := compareTransactionResults(expected[i], actual[i])
+		if diff == "" {
+			continue
+		}
+
+		mismatches++
+		log.Error().
+			Str("tx_id", expected[i].txnIdStr).
+			Uint32("tx_index", expected[i].txnIndex).
+			Str("difference", diff).
+			Msg("parallel execution result differs from sequential execution result")
+	}
+
+	if mismatches == 0 {
+		log.Debug().Msg("parallel execution result matches sequential execution result")
+	}
+
+	return nil
+}
+
+// compareTransactionResults returns a description of the first difference
+// between the two results which affects the execution result, or an empty
+// string if there is none.
+func compareTransactionResults(expected, actual transactionResult) string {
+	if expected.txnId != actual.txnId {
+		return fmt.Sprintf("transaction %v != %v", expected.txnId, actual.txnId)
+	}
+
+	if !reflect.DeepEqual(expected.ReadSet, actual.ReadSet) {
+		return "read set"
+	}
+	if !reflect.DeepEqual(expected.WriteSet, actual.WriteSet) {
+		return "write set"
+	}
+	if !bytes.Equal(expected.SpockSecret, actual.SpockSecret) {
+		return "spock secret"
+	}
+
+	if (expected.Err == nil) != (actual.Err == nil) ||
+		(expected.Err != nil && expected.Err.Error() != actual.Err.Error()) {
+		return fmt.Sprintf("error: %v != %v", expected.Err, actual.Err)
+	}
+	if expected.ComputationUsed != actual.ComputationUsed {
+		return fmt.Sprintf(
+			"computation used: %d != %d",
+			expected.ComputationUsed,
+			actual.ComputationUsed)
+	}
+	if expected.MemoryEstimate != actual.MemoryEstimate {
+		return fmt.Sprintf(
+			"memory estimate: %d != %d",
+			expected.MemoryEstimate,
+			actual.MemoryEstimate)
+	}
+	if !reflect.DeepEqual(expected.Events, actual.Events) {
+		return "events"
+	}
+	if !reflect.DeepEqual(expected.ServiceEvents, actual.ServiceEvents) {
+		return "service events"
+	}
+
+	return ""
+}
@@ -0,0 +1,520 @@
+package computer_test
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	blockstore "github.com/ipfs/go-ipfs-blockstore"
+
+	"github.com/onflow/flow-go/engine/execution"
+	"github.com/onflow/flow-go/engine/execution/computation/committer"
+	"github.com/onflow/flow-go/engine/execution/computation/computer"
+	computermock "github.com/onflow/flow-go/engine/execution/computation/computer/mock"
+	executionState "github.com/onflow/flow-go/engine/execution/state"
+	bootstrapexec "github.com/onflow/flow-go/engine/execution/state/bootstrap"
+	"github.com/onflow/flow-go/engine/execution/testutil"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/blueprints"
+	"github.com/onflow/flow-go/fvm/environment"
+	"github.com/onflow/flow-go/fvm/state"
+	"github.com/onflow/flow-go/fvm/storage/derived"
+	"github.com/onflow/flow-go/fvm/storage/logical"
+	completeLedger "github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/wal/fixtures"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/executiondatasync/execution_data"
+	"github.com/onflow/flow-go/module/executiondatasync/provider"
+	mocktracker "github.com/onflow/flow-go/module/executiondatasync/tracker/mock"
+	"github.com/onflow/flow-go/module/mempool/entity"
+	"github.com/onflow/flow-go/module/metrics"
+	modulemock "github.com/onflow/flow-go/module/mock"
+	requesterunit "github.com/onflow/flow-go/module/state_synchronization/requester/unittest"
+	"github.com/onflow/flow-go/module/trace"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestParseTransactionExecutionMode(t *testing.T) {
+	for _, mode := range []computer.TransactionExecutionMode{
+		computer.TransactionExecutionModeSequential,
+		computer.TransactionExecutionModeParallel,
+		computer.TransactionExecutionModeCompare,
+	} {
+		parsed, err := computer.ParseTransactionExecutionMode(mode.String())
+		require.NoError(t, err)
+		require.Equal(t, mode, parsed)
+	}
+
+	_, err := computer.ParseTransactionExecutionMode("concurrent")
+	require.Error(t, err)
+}
+
+func TestBlockExecutor_ExecuteBlockInParallel(t *testing.T) {
+
+	collectionCount := 3
+	transactionsPerCollection := 5
+
+	// the same block is executed by all computers, so the results can be
+	// compared.
+	block := generateBlock(
+		collectionCount,
+		transactionsPerCollection,
+		&RandomAddressGenerator{})
+
+	sequentialVM := &counterVM{}
+	expected := executeBlockWithMode(
+		t,
+		sequentialVM,
+		block,
+		computer.TransactionExecutionModeSequential,
+		1)
+
+	// +1 for the system transaction
+	totalTransactionCount := collectionCount*transactionsPerCollection + 1
+	require.Equal(t, int64(totalTransactionCount), sequentialVM.runCount)
+	require.Equal(t, int64(0), sequentialVM.speculativeRunCount)
+
+	t.Run("parallel", func(t *testing.T) {
+		for _, workers := range []int{1, 2, 4, 16} {
+			vm := &counterVM{}
+			result := executeBlockWithMode(
+				t,
+				vm,
+				block,
+				computer.TransactionExecutionModeParallel,
+				workers)
+
+			requireSameComputationResult(t, expected, result)
+
+			if workers > 1 {
+				// the non-conflicting transactions are not re-executed
+				require.Less(t, vm.runCount, int64(totalTransactionCount))
+				require.Greater(t, vm.speculativeRunCount, int64(0))
+			}
+		}
+	})
+
+	t.Run("compare", func(t *testing.T) {
+		vm := &counterVM{}
+		result := executeBlockWithMode(
+			t,
+			vm,
+			block,
+			computer.TransactionExecutionModeCompare,
+			4)
+
+		requireSameComputationResult(t, expected, result)
+		require.Greater(t, vm.speculativeRunCount, int64(0))
+	})
+
+	t.Run("parallel requires speculative vm", func(t *testing.T) {
+		_, err := computer.NewBlockComputer(
+			&testVM{t: t},
+			fvm.NewContext(),
+			metrics.NewNoopCollector(),
+			trace.NewNoopTracer(),
+			zerolog.Nop(),
+			new(computermock.ViewCommitter),
+			new(modulemock.Local),
+			nil,
+			nil,
+			computer.WithTransactionExecutionMode(
+				computer.TransactionExecutionModeParallel,
+				4))
+		require.Error(t, err)
+	})
+}
+
+// TestBlockExecutor_ExecuteBlockInParallelWithFVM executes a block with the
+// FVM sequentially and in parallel, and compares the complete results,
+// including the spock secrets and the final state commitment.
+//
+// The block updates a contract which was loaded by a preceding transaction,
+// and calls it in the following transaction, so with more than one worker
+// the call is executed speculatively with the outdated program.
+func TestBlockExecutor_ExecuteBlockInParallelWithFVM(t *testing.T) {
+	chain := flow.Emulator.Chain()
+
+	contract := `
+		pub contract Foo {
+			pub event Value(value: Int)
+
+			pub fun emitValue() {
+				emit Value(value: %d)
+			}
+		}`
+
+	deployTx := blueprints.DeployContractTransaction(
+		chain.ServiceAddress(),
+		[]byte(fmt.Sprintf(contract, 1)),
+		"Foo")
+
+	updateTx := flow.NewTransactionBody().
+		SetScript([]byte(fmt.Sprintf(`
+			transaction {
+				prepare(signer: AuthAccount) {
+					signer.contracts.update__experimental(name: "Foo", code: "%s".decodeHex())
+				}
+			}`,
+			hex.EncodeToString([]byte(fmt.Sprintf(contract, 2)))))).
+		AddAuthorizer(chain.ServiceAddress())
+
+	newCallTx := func() *flow.TransactionBody {
+		return flow.NewTransactionBody().
+			SetScript([]byte(fmt.Sprintf(`
+				import Foo from 0x%s
+
+				transaction {
+					prepare() {}
+					execute {
+						Foo.emitValue()
+					}
+				}`,
+				chain.ServiceAddress())))
+	}
+
+	collections := [][]*flow.TransactionBody{
+		{deployTx, newCallTx()},
+		{updateTx, newCallTx()},
+		{newCallTx(), newCallTx()},
+	}
+
+	seqNum := uint64(0)
+	for _, collection := range collections {
+		for _, tx := range collection {
+			err := testutil.SignTransactionAsServiceAccount(tx, seqNum, chain)
+			require.NoError(t, err)
+			seqNum++
+		}
+	}
+
+	block := unittest.ExecutableBlockFromTransactions(chain.ChainID(), collections)
+	me := new(modulemock.Local)
+	me.On("NodeID").Return(unittest.IdentifierFixture())
+	me.On("Sign", mock.Anything, mock.Anything).Return(nil, nil)
+	me.On("SignFunc", mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, nil)
+	parentResultID := unittest.IdentifierFixture()
+
+	expected := executeBlockWithFVM(
+		t,
+		chain,
+		block,
+		me,
+		parentResultID,
+		computer.TransactionExecutionModeSequential,
+		1)
+
+	// the system transaction fails, as the epoch is not set up, which is not
+	// relevant to the comparison
+	for _, result := range expected.TransactionResults[:len(expected.TransactionResults)-1] {
+		require.Empty(t, result.ErrorMessage)
+	}
+
+	// the calls emit the value of the contract version they were executed with
+	requireValueEvent := func(events flow.EventsList, value int) {
+		require.NotEmpty(t, events)
+
+		event := events[len(events)-1]
+		require.Equal(
+			t,
+			flow.EventType(fmt.Sprintf("A.%s.Foo.Value", chain.ServiceAddress())),
+			event.Type)
+
+		decoded, err := jsoncdc.Decode(nil, event.Payload)
+		require.NoError(t, err)
+		require.Equal(t, cadence.NewInt(value), decoded.(cadence.Event).Fields[0])
+	}
+	requireValueEvent(expected.Events[0], 1)
+	requireValueEvent(expected.Events[1], 2)
+	requireValueEvent(expected.Events[2], 2)
+
+	for _, workers := range []int{1, 2, 4, 8} {
+		t.Run(fmt.Sprintf("%d workers", workers), func(t *testing.T) {
+			result := executeBlockWithFVM(
+				t,
+				chain,
+				block,
+				me,
+				parentResultID,
+				computer.TransactionExecutionModeParallel,
+				workers)
+
+			require.Len(t, result.StateSnapshots, len(expected.StateSnapshots))
+			for i := range expected.StateSnapshots {
+				require.Equal(
+					t,
+					expected.StateSnapshots[i].SpockSecret,
+					result.StateSnapshots[i].SpockSecret)
+			}
+			require.Equal(t, expected.EndState, result.EndState)
+
+			require.Equal(t, expected, result)
+		})
+	}
+}
+
+// executeBlockWithFVM executes the block with the FVM against a freshly
+// bootstrapped ledger.
+func executeBlockWithFVM(
+	t *testing.T,
+	chain flow.Chain,
+	block *entity.ExecutableBlock,
+	me *modulemock.Local,
+	parentResultID flow.Identifier,
+	mode computer.TransactionExecutionMode,
+	workers int,
+) *execution.ComputationResult {
+	logger := zerolog.Nop()
+	collector := metrics.NewNoopCollector()
+	tracer := trace.NewNoopTracer()
+
+	ledger, err := completeLedger.NewLedger(
+		&fixtures.NoopWAL{},
+		100,
+		collector,
+		logger,
+		completeLedger.DefaultPathFinderVersion)
+	require.NoError(t, err)
+
+	compactor := fixtures.NewNoopCompactor(ledger)
+	<-compactor.Ready()
+	defer func() {
+		<-ledger.Done()
+		<-compactor.Done()
+	}()
+
+	initialCommit, err := bootstrapexec.NewBootstrapper(logger).BootstrapLedger(
+		ledger,
+		unittest.ServiceAccountPublicKey,
+		chain)
+	require.NoError(t, err)
+
+	bservice := requesterunit.MockBlobService(blockstore.NewBlockstore(dssync.MutexWrap(datastore.NewMapDatastore())))
+	prov := provider.NewProvider(
+		logger,
+		collector,
+		execution_data.DefaultSerializer,
+		bservice,
+		mocktracker.NewMockStorage(),
+	)
+
+	exe, err := computer.NewBlockComputer(
+		fvm.NewVirtualMachine(),
+		fvm.NewContext(
+			fvm.WithChain(chain),
+			fvm.WithLogger(logger),
+			fvm.WithBlocks(&environment.NoopBlockFinder{})),
+		collector,
+		tracer,
+		logger,
+		committer.NewLedgerViewCommitter(ledger, tracer),
+		me,
+		prov,
+		nil,
+		computer.WithTransactionExecutionMode(mode, workers))
+	require.NoError(t, err)
+
+	block.StartState = &initialCommit
+
+	result, err := exe.ExecuteBlock(
+		context.Background(),
+		parentResultID,
+		block,
+		executionState.NewLedgerStorageSnapshot(ledger, initialCommit),
+		derived.NewEmptyDerivedBlockData())
+	require.NoError(t, err)
+
+	return result
+}
+
+func executeBlockWithMode(
+	t *testing.T,
+	vm fvm.VM,
+	block *entity.ExecutableBlock,
+	mode computer.TransactionExecutionMode,
+	workers int,
+) *execution.ComputationResult {
+	committer := new(computermock.ViewCommitter)
+	committer.On("CommitView", mock.Anything, mock.Anything).
+		Return(nil, nil, nil, nil)
+
+	me := new(modulemock.Local)
+	me.On("NodeID").Return(unittest.IdentifierFixture())
+	me.On("Sign", mock.Anything, mock.Anything).Return(nil, nil)
+	me.On("SignFunc", mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, nil)
+
+	bservice := requesterunit.MockBlobService(blockstore.NewBlockstore(dssync.MutexWrap(datastore.NewMapDatastore())))
+	prov := provider.NewProvider(
+		zerolog.Nop(),
+		metrics.NewNoopCollector(),
+		execution_data.DefaultSerializer,
+		bservice,
+		mocktracker.NewMockStorage(),
+	)
+
+	exe, err := computer.NewBlockComputer(
+		vm,
+		fvm.NewContext(),
+		metrics.NewNoopCollector(),
+		trace.NewNoopTracer(),
+		zerolog.Nop(),
+		committer,
+		me,
+		prov,
+		nil,
+		computer.WithTransactionExecutionMode(mode, workers))
+	require.NoError(t, err)
+
+	result, err := exe.ExecuteBlock(
+		context.Background(),
+		unittest.IdentifierFixture(),
+		block,
+		nil,
+		derived.NewEmptyDerivedBlockData())
+	require.NoError(t, err)
+
+	return result
+}
+
+func requireSameComputationResult(
+	t *testing.T,
+	expected *execution.ComputationResult,
+	actual *execution.ComputationResult,
+) {
+	require.Len(t, actual.StateSnapshots, len(expected.StateSnapshots))
+	for i := range expected.StateSnapshots {
+		require.Equal(t, expected.StateSnapshots[i].ReadSet, actual.StateSnapshots[i].ReadSet)
+		require.Equal(t, expected.StateSnapshots[i].WriteSet, actual.StateSnapshots[i].WriteSet)
+	}
+
+	require.Equal(t, expected.Events, actual.Events)
+	require.Equal(t, expected.EventsHashes, actual.EventsHashes)
+	require.Equal(t, expected.TransactionResults, actual.TransactionResults)
+}
+
+var counterRegister = flow.NewRegisterID("", "counter")
+
+// counterVM executes every transaction with an even index as an increment of
+// a shared counter register, and every other transaction as a write of its
+// index to its own register. The read counter value is emitted as an event,
+// so the results depend on the order of execution of the conflicting
+// transactions.
+type counterVM struct {
+	testVM
+
+	runCount            int64
+	speculativeRunCount int64
+}
+
+var _ fvm.SpeculativeVM = &counterVM{}
+
+func (vm *counterVM) Run(
+	ctx fvm.Context,
+	proc fvm.Procedure,
+	storageSnapshot state.StorageSnapshot,
+) (
+	*state.ExecutionSnapshot,
+	fvm.ProcedureOutput,
+	error,
+) {
+	atomic.AddInt64(&vm.runCount, 1)
+
+	txn := proc.(*fvm.TransactionProcedure)
+	snapshot, output, derivedTxnData, err := vm.run(
+		ctx,
+		txn,
+		txn.ExecutionTime(),
+		storageSnapshot)
+	if err != nil {
+		return nil, fvm.ProcedureOutput{}, err
+	}
+
+	err = derivedTxnData.Commit()
+	if err != nil {
+		return nil, fvm.ProcedureOutput{}, err
+	}
+
+	return snapshot, output, nil
+}
+
+func (vm *counterVM) RunSpeculatively(
+	ctx fvm.Context,
+	proc *fvm.TransactionProcedure,
+	snapshotTime logical.Time,
+	storageSnapshot state.StorageSnapshot,
+) (
+	*state.ExecutionSnapshot,
+	fvm.ProcedureOutput,
+	derived.DerivedTransactionCommitter,
+	error,
+) {
+	atomic.AddInt64(&vm.speculativeRunCount, 1)
+
+	return vm.run(ctx, proc, snapshotTime, storageSnapshot)
+}
+
+func (vm *counterVM) run(
+	ctx fvm.Context,
+	txn *fvm.TransactionProcedure,
+	snapshotTime logical.Time,
+	storageSnapshot state.StorageSnapshot,
+) (
+	*state.ExecutionSnapshot,
+	fvm.ProcedureOutput,
+	derived.DerivedTransactionCommitter,
+	error,
+) {
+	derivedTxnData, err := ctx.DerivedBlockData.NewDerivedTransactionData(
+		snapshotTime,
+		txn.ExecutionTime())
+	if err != nil {
+		return nil, fvm.ProcedureOutput{}, nil, err
+	}
+
+	snapshot := &state.ExecutionSnapshot{
+		ReadSet:  map[flow.RegisterID]struct{}{},
+		WriteSet: map[flow.RegisterID]flow.RegisterValue{},
+	}
+	output := fvm.ProcedureOutput{}
+
+	if txn.TxIndex%2 == 1 {
+		id := flow.NewRegisterID("", txn.ID.String())
+		snapshot.WriteSet[id] = []byte{byte(txn.TxIndex)}
+		return snapshot, output, derivedTxnData, nil
+	}
+
+	value, err := storageSnapshot.Get(counterRegister)
+	if err != nil {
+		return nil, fvm.ProcedureOutput{}, nil, err
+	}
+
+	counter := byte(0)
+	if len(value) > 0 {
+		counter = value[0]
+	}
+
+	snapshot.ReadSet[counterRegister] = struct{}{}
+	snapshot.WriteSet[counterRegister] = []byte{counter + 1}
+	output.Events = []flow.Event{
+		{
+			Type:             "flow.Counter",
+			TransactionID:    txn.ID,
+			TransactionIndex: txn.TxIndex,
+			Payload:          []byte{counter},
+		},
+	}
+
+	return snapshot, output, derivedTxnData, nil
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/onflow/flow-go/engine/execution/computation/computer"
 	"github.com/onflow/flow-go/engine/execution/computation/query"
 	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/meter"
 	reusableRuntime "github.com/onflow/flow-go/fvm/runtime"
 	"github.com/onflow/flow-go/fvm/state"
 	"github.com/onflow/flow-go/fvm/storage/derived"
@@ -35,6 +36,7 @@ type ComputationManager interface {
 		snapshot state.StorageSnapshot,
 	) (
 		[]byte,
+		meter.Usage,
 		error,
 	)
 
@@ -48,6 +50,17 @@ type ComputationManager interface {
 		error,
 	)
 
+	SimulateTransaction(
+		ctx context.Context,
+		tx *flow.TransactionBody,
+		blockHeader *flow.Header,
+		snapshot state.StorageSnapshot,
+		skipSignatureVerification bool,
+	) (
+		*query.TransactionSimulation,
+		error,
+	)
+
 	GetAccount(
 		ctx context.Context,
 		addr flow.Address,
@@ -57,6 +70,37 @@ type ComputationManager interface {
 		*flow.Account,
 		error,
 	)
+
+	GetAccountBalance(
+		ctx context.Context,
+		addr flow.Address,
+		header *flow.Header,
+		snapshot state.StorageSnapshot,
+	) (
+		uint64,
+		error,
+	)
+
+	GetAccountKeys(
+		ctx context.Context,
+		addr flow.Address,
+		header *flow.Header,
+		snapshot state.StorageSnapshot,
+	) (
+		[]flow.AccountPublicKey,
+		error,
+	)
+
+	GetAccountKey(
+		ctx context.Context,
+		addr flow.Address,
+		keyIndex uint64,
+		header *flow.Header,
+		snapshot state.StorageSnapshot,
+	) (
+		*flow.AccountPublicKey,
+		error,
+	)
 }
 
 type ComputationConfig struct {
@@ -65,6 +109,12 @@ type ComputationConfig struct {
 	ExtensiveTracing     bool
 	DerivedDataCacheSize uint
 
+	// TransactionExecutionMode determines how the transactions of a block are
+	// executed. ParallelExecutionWorkers is the maximum number of transactions
+	// executed concurrently in the parallel and compare modes.
+	TransactionExecutionMode computer.TransactionExecutionMode
+	ParallelExecutionWorkers int
+
 	// When NewCustomVirtualMachine is nil, the manager will create a standard
 	// fvm virtual machine via fvm.NewVirtualMachine.  Otherwise, the manager
 	// will create a virtual machine using this function.
@@ -135,6 +185,9 @@ func New(
 		me,
 		executionDataProvider,
 		nil, // TODO(ramtin): update me with proper consumers
+		computer.WithTransactionExecutionMode(
+			params.TransactionExecutionMode,
+			params.ParallelExecutionWorkers),
 	)
 
 	if err != nil {
@@ -212,7 +265,7 @@ func (e *Manager) ExecuteScript(
 	arguments [][]byte,
 	blockHeader *flow.Header,
 	snapshot state.StorageSnapshot,
-) ([]byte, error) {
+) ([]byte, meter.Usage, error) {
 	return e.queryExecutor.ExecuteScript(ctx,
 		code,
 		arguments,
@@ -220,6 +273,24 @@ func (e *Manager) ExecuteScript(
 		snapshot)
 }
 
+func (e *Manager) SimulateTransaction(
+	ctx context.Context,
+	tx *flow.TransactionBody,
+	blockHeader *flow.Header,
+	snapshot state.StorageSnapshot,
+	skipSignatureVerification bool,
+) (
+	*query.TransactionSimulation,
+	error,
+) {
+	return e.queryExecutor.SimulateTransaction(
+		ctx,
+		tx,
+		blockHeader,
+		snapshot,
+		skipSignatureVerification)
+}
+
 func (e *Manager) GetAccount(
 	ctx context.Context,
 	address flow.Address,
@@ -235,3 +306,53 @@ func (e *Manager) GetAccount(
 		blockHeader,
 		snapshot)
 }
+
+func (e *Manager) GetAccountBalance(
+	ctx context.Context,
+	address flow.Address,
+	blockHeader *flow.Header,
+	snapshot state.StorageSnapshot,
+) (
+	uint64,
+	error,
+) {
+	return e.queryExecutor.GetAccountBalance(
+		ctx,
+		address,
+		blockHeader,
+		snapshot)
+}
+
+func (e *Manager) GetAccountKeys(
+	ctx context.Context,
+	address flow.Address,
+	blockHeader *flow.Header,
+	snapshot state.StorageSnapshot,
+) (
+	[]flow.AccountPublicKey,
+	error,
+) {
+	return e.queryExecutor.GetAccountKeys(
+		ctx,
+		address,
+		blockHeader,
+		snapshot)
+}
+
+func (e *Manager) GetAccountKey(
+	ctx context.Context,
+	address flow.Address,
+	keyIndex uint64,
+	blockHeader *flow.Header,
+	snapshot state.StorageSnapshot,
+) (
+	*flow.AccountPublicKey,
+	error,
+) {
+	return e.queryExecutor.GetAccountKey(
+		ctx,
+		address,
+		keyIndex,
+		blockHeader,
+		snapshot)
+}
@@ -272,13 +272,17 @@ func TestExecuteScript(t *testing.T) {
 	require.NoError(t, err)
 
 	header := unittest.BlockHeaderFixture()
-	_, err = engine.ExecuteScript(
+	_, usage, err := engine.ExecuteScript(
 		context.Background(),
 		script,
 		nil,
 		header,
 		ledger)
 	require.NoError(t, err)
+	require.Equal(t, uint64(fvm.DefaultComputationLimit), usage.ComputationLimit)
+	require.Equal(t, uint64(fvm.DefaultMemoryLimit), usage.MemoryLimit)
+	require.NotZero(t, usage.MemoryEstimate)
+	require.NotEmpty(t, usage.MemoryIntensities)
 }
 
 // Balance script used to swallow errors, which meant that even if the view was empty, a script that did nothing but get
@@ -336,7 +340,7 @@ func TestExecuteScript_BalanceScriptFailsIfViewIsEmpty(t *testing.T) {
 	require.NoError(t, err)
 
 	header := unittest.BlockHeaderFixture()
-	_, err = engine.ExecuteScript(
+	_, _, err = engine.ExecuteScript(
 		context.Background(),
 		script,
 		nil,
@@ -383,7 +387,7 @@ func TestExecuteScripPanicsAreHandled(t *testing.T) {
 	)
 	require.NoError(t, err)
 
-	_, err = manager.ExecuteScript(
+	_, _, err = manager.ExecuteScript(
 		context.Background(),
 		[]byte("whatever"),
 		nil,
@@ -436,7 +440,7 @@ func TestExecuteScript_LongScriptsAreLogged(t *testing.T) {
 	)
 	require.NoError(t, err)
 
-	_, err = manager.ExecuteScript(
+	_, _, err = manager.ExecuteScript(
 		context.Background(),
 		[]byte("whatever"),
 		nil,
@@ -489,7 +493,7 @@ func TestExecuteScript_ShortScriptsAreNotLogged(t *testing.T) {
 	)
 	require.NoError(t, err)
 
-	_, err = manager.ExecuteScript(
+	_, _, err = manager.ExecuteScript(
 		context.Background(),
 		[]byte("whatever"),
 		nil,
@@ -610,7 +614,7 @@ func TestExecuteScriptTimeout(t *testing.T) {
 	`)
 
 	header := unittest.BlockHeaderFixture()
-	value, err := manager.ExecuteScript(
+	value, _, err := manager.ExecuteScript(
 		context.Background(),
 		script,
 		nil,
@@ -663,7 +667,7 @@ func TestExecuteScriptCancelled(t *testing.T) {
 	wg.Add(1)
 	go func() {
 		header := unittest.BlockHeaderFixture()
-		value, err = manager.ExecuteScript(
+		value, _, err = manager.ExecuteScript(
 			reqCtx,
 			script,
 			nil,
@@ -873,7 +877,7 @@ func TestScriptStorageMutationsDiscarded(t *testing.T) {
 	`)
 
 	header := unittest.BlockHeaderFixture()
-	_, err = manager.ExecuteScript(
+	_, _, err = manager.ExecuteScript(
 		context.Background(),
 		script,
 		[][]byte{jsoncdc.MustEncode(address)},
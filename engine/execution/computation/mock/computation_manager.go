@@ -10,8 +10,12 @@ import (
 
 	flow "github.com/onflow/flow-go/model/flow"
 
+	meter "github.com/onflow/flow-go/fvm/meter"
+
 	mock "github.com/stretchr/testify/mock"
 
+	query "github.com/onflow/flow-go/engine/execution/computation/query"
+
 	state "github.com/onflow/flow-go/fvm/state"
 )
 
@@ -47,12 +51,13 @@ func (_m *ComputationManager) ComputeBlock(ctx context.Context, parentBlockExecu
 }
 
 // ExecuteScript provides a mock function with given fields: ctx, script, arguments, blockHeader, snapshot
-func (_m *ComputationManager) ExecuteScript(ctx context.Context, script []byte, arguments [][]byte, blockHeader *flow.Header, snapshot state.StorageSnapshot) ([]byte, error) {
+func (_m *ComputationManager) ExecuteScript(ctx context.Context, script []byte, arguments [][]byte, blockHeader *flow.Header, snapshot state.StorageSnapshot) ([]byte, meter.Usage, error) {
 	ret := _m.Called(ctx, script, arguments, blockHeader, snapshot)
 
 	var r0 []byte
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, []byte, [][]byte, *flow.Header, state.StorageSnapshot) ([]byte, error)); ok {
+	var r1 meter.Usage
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, [][]byte, *flow.Header, state.StorageSnapshot) ([]byte, meter.Usage, error)); ok {
 		return rf(ctx, script, arguments, blockHeader, snapshot)
 	}
 	if rf, ok := ret.Get(0).(func(context.Context, []byte, [][]byte, *flow.Header, state.StorageSnapshot) []byte); ok {
@@ -63,13 +68,19 @@ func (_m *ComputationManager) ExecuteScript(ctx context.Context, script []byte,
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, []byte, [][]byte, *flow.Header, state.StorageSnapshot) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, []byte, [][]byte, *flow.Header, state.StorageSnapshot) meter.Usage); ok {
 		r1 = rf(ctx, script, arguments, blockHeader, snapshot)
 	} else {
-		r1 = ret.Error(1)
+		r1 = ret.Get(1).(meter.Usage)
 	}
 
-	return r0, r1
+	if rf, ok := ret.Get(2).(func(context.Context, []byte, [][]byte, *flow.Header, state.StorageSnapshot) error); ok {
+		r2 = rf(ctx, script, arguments, blockHeader, snapshot)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
 // GetAccount provides a mock function with given fields: ctx, addr, header, snapshot
@@ -98,6 +109,108 @@ func (_m *ComputationManager) GetAccount(ctx context.Context, addr flow.Address,
 	return r0, r1
 }
 
+// GetAccountBalance provides a mock function with given fields: ctx, addr, header, snapshot
+func (_m *ComputationManager) GetAccountBalance(ctx context.Context, addr flow.Address, header *flow.Header, snapshot state.StorageSnapshot) (uint64, error) {
+	ret := _m.Called(ctx, addr, header, snapshot)
+
+	var r0 uint64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Address, *flow.Header, state.StorageSnapshot) (uint64, error)); ok {
+		return rf(ctx, addr, header, snapshot)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Address, *flow.Header, state.StorageSnapshot) uint64); ok {
+		r0 = rf(ctx, addr, header, snapshot)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, flow.Address, *flow.Header, state.StorageSnapshot) error); ok {
+		r1 = rf(ctx, addr, header, snapshot)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAccountKey provides a mock function with given fields: ctx, addr, keyIndex, header, snapshot
+func (_m *ComputationManager) GetAccountKey(ctx context.Context, addr flow.Address, keyIndex uint64, header *flow.Header, snapshot state.StorageSnapshot) (*flow.AccountPublicKey, error) {
+	ret := _m.Called(ctx, addr, keyIndex, header, snapshot)
+
+	var r0 *flow.AccountPublicKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Address, uint64, *flow.Header, state.StorageSnapshot) (*flow.AccountPublicKey, error)); ok {
+		return rf(ctx, addr, keyIndex, header, snapshot)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Address, uint64, *flow.Header, state.StorageSnapshot) *flow.AccountPublicKey); ok {
+		r0 = rf(ctx, addr, keyIndex, header, snapshot)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*flow.AccountPublicKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, flow.Address, uint64, *flow.Header, state.StorageSnapshot) error); ok {
+		r1 = rf(ctx, addr, keyIndex, header, snapshot)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAccountKeys provides a mock function with given fields: ctx, addr, header, snapshot
+func (_m *ComputationManager) GetAccountKeys(ctx context.Context, addr flow.Address, header *flow.Header, snapshot state.StorageSnapshot) ([]flow.AccountPublicKey, error) {
+	ret := _m.Called(ctx, addr, header, snapshot)
+
+	var r0 []flow.AccountPublicKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Address, *flow.Header, state.StorageSnapshot) ([]flow.AccountPublicKey, error)); ok {
+		return rf(ctx, addr, header, snapshot)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Address, *flow.Header, state.StorageSnapshot) []flow.AccountPublicKey); ok {
+		r0 = rf(ctx, addr, header, snapshot)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]flow.AccountPublicKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, flow.Address, *flow.Header, state.StorageSnapshot) error); ok {
+		r1 = rf(ctx, addr, header, snapshot)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SimulateTransaction provides a mock function with given fields: ctx, tx, blockHeader, snapshot, skipSignatureVerification
+func (_m *ComputationManager) SimulateTransaction(ctx context.Context, tx *flow.TransactionBody, blockHeader *flow.Header, snapshot state.StorageSnapshot, skipSignatureVerification bool) (*query.TransactionSimulation, error) {
+	ret := _m.Called(ctx, tx, blockHeader, snapshot, skipSignatureVerification)
+
+	var r0 *query.TransactionSimulation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *flow.TransactionBody, *flow.Header, state.StorageSnapshot, bool) (*query.TransactionSimulation, error)); ok {
+		return rf(ctx, tx, blockHeader, snapshot, skipSignatureVerification)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *flow.TransactionBody, *flow.Header, state.StorageSnapshot, bool) *query.TransactionSimulation); ok {
+		r0 = rf(ctx, tx, blockHeader, snapshot, skipSignatureVerification)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*query.TransactionSimulation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *flow.TransactionBody, *flow.Header, state.StorageSnapshot, bool) error); ok {
+		r1 = rf(ctx, tx, blockHeader, snapshot, skipSignatureVerification)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewComputationManager interface {
 	mock.TestingT
 	Cleanup(func())
@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math/rand"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -13,6 +14,7 @@ import (
 	"github.com/rs/zerolog"
 
 	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/meter"
 	"github.com/onflow/flow-go/fvm/state"
 	"github.com/onflow/flow-go/fvm/storage/derived"
 	"github.com/onflow/flow-go/model/flow"
@@ -35,6 +37,18 @@ type Executor interface {
 		snapshot state.StorageSnapshot,
 	) (
 		[]byte,
+		meter.Usage,
+		error,
+	)
+
+	SimulateTransaction(
+		ctx context.Context,
+		tx *flow.TransactionBody,
+		blockHeader *flow.Header,
+		snapshot state.StorageSnapshot,
+		skipSignatureVerification bool,
+	) (
+		*TransactionSimulation,
 		error,
 	)
 
@@ -47,6 +61,51 @@ type Executor interface {
 		*flow.Account,
 		error,
 	)
+
+	GetAccountBalance(
+		ctx context.Context,
+		addr flow.Address,
+		header *flow.Header,
+		snapshot state.StorageSnapshot,
+	) (
+		uint64,
+		error,
+	)
+
+	GetAccountKeys(
+		ctx context.Context,
+		addr flow.Address,
+		header *flow.Header,
+		snapshot state.StorageSnapshot,
+	) (
+		[]flow.AccountPublicKey,
+		error,
+	)
+
+	GetAccountKey(
+		ctx context.Context,
+		addr flow.Address,
+		keyIndex uint64,
+		header *flow.Header,
+		snapshot state.StorageSnapshot,
+	) (
+		*flow.AccountPublicKey,
+		error,
+	)
+}
+
+// TransactionSimulation is the outcome of a transaction executed against the execution state at a
+// block, without applying its state changes.
+type TransactionSimulation struct {
+	Events flow.EventsList
+	// ErrorMessage is empty if the transaction succeeded. Only fee deduction events are emitted by
+	// failed transactions.
+	ErrorMessage    string
+	ComputationUsed uint64
+	MemoryEstimate  uint64
+	// RegistersRead and RegistersWritten are the registers touched by the transaction, sorted.
+	RegistersRead    flow.RegisterIDs
+	RegistersWritten flow.RegisterIDs
 }
 
 type QueryConfig struct {
@@ -104,6 +163,7 @@ func (e *QueryExecutor) ExecuteScript(
 	snapshot state.StorageSnapshot,
 ) (
 	encodedValue []byte,
+	usage meter.Usage,
 	err error,
 ) {
 
@@ -156,21 +216,21 @@ func (e *QueryExecutor) ExecuteScript(
 		}
 	}()
 
+	blockCtx := fvm.NewContextFromParent(
+		e.vmCtx,
+		fvm.WithBlockHeader(blockHeader),
+		fvm.WithDerivedBlockData(
+			e.derivedChainData.NewDerivedBlockDataForScript(blockHeader.ID())))
+	proc := fvm.NewScriptWithContextAndArgs(script, requestCtx, arguments...)
+
 	var output fvm.ProcedureOutput
-	_, output, err = e.vm.Run(
-		fvm.NewContextFromParent(
-			e.vmCtx,
-			fvm.WithBlockHeader(blockHeader),
-			fvm.WithDerivedBlockData(
-				e.derivedChainData.NewDerivedBlockDataForScript(blockHeader.ID()))),
-		fvm.NewScriptWithContextAndArgs(script, requestCtx, arguments...),
-		snapshot)
+	_, output, err = e.vm.Run(blockCtx, proc, snapshot)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute script (internal error): %w", err)
+		return nil, meter.Usage{}, fmt.Errorf("failed to execute script (internal error): %w", err)
 	}
 
 	if output.Err != nil {
-		return nil, fmt.Errorf("failed to execute script at block (%s): %s",
+		return nil, meter.Usage{}, fmt.Errorf("failed to execute script at block (%s): %s",
 			blockHeader.ID(),
 			summarizeLog(output.Err.Error(),
 				e.config.MaxErrorMessageSize))
@@ -178,7 +238,16 @@ func (e *QueryExecutor) ExecuteScript(
 
 	encodedValue, err = jsoncdc.Encode(output.Value)
 	if err != nil {
-		return nil, fmt.Errorf("failed to encode runtime value: %w", err)
+		return nil, meter.Usage{}, fmt.Errorf("failed to encode runtime value: %w", err)
+	}
+
+	usage = meter.Usage{
+		ComputationUsed:        output.ComputationUsed,
+		ComputationLimit:       proc.ComputationLimit(blockCtx),
+		ComputationIntensities: output.ComputationIntensities,
+		MemoryEstimate:         output.MemoryEstimate,
+		MemoryLimit:            proc.MemoryLimit(blockCtx),
+		MemoryIntensities:      output.MemoryIntensities,
 	}
 
 	memAllocAfter := debug.GetHeapAllocsBytes()
@@ -188,7 +257,56 @@ func (e *QueryExecutor) ExecuteScript(
 		memAllocAfter-memAllocBefore,
 		output.MemoryEstimate)
 
-	return encodedValue, nil
+	return encodedValue, usage, nil
+}
+
+// SimulateTransaction executes the transaction against the given snapshot at the given block, and
+// discards its state changes. If skipSignatureVerification is true, the transaction is executed
+// even if its signatures are missing or invalid.
+// Failures of the transaction itself are reported in the simulation rather than as an error.
+func (e *QueryExecutor) SimulateTransaction(
+	ctx context.Context,
+	tx *flow.TransactionBody,
+	blockHeader *flow.Header,
+	snapshot state.StorageSnapshot,
+	skipSignatureVerification bool,
+) (
+	*TransactionSimulation,
+	error,
+) {
+	// the derived block data is not shared with scripts, since transactions may change the
+	// programs cached in it
+	blockCtx := fvm.NewContextFromParent(
+		e.vmCtx,
+		fvm.WithBlockHeader(blockHeader))
+	if skipSignatureVerification {
+		blockCtx = fvm.NewContextFromParent(
+			blockCtx,
+			fvm.WithAuthorizationChecksEnabled(false))
+	}
+
+	executionSnapshot, output, err := e.vm.Run(
+		blockCtx,
+		fvm.Transaction(tx, 0),
+		snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to simulate transaction (internal error): %w", err)
+	}
+
+	simulation := &TransactionSimulation{
+		Events:           output.Events,
+		ComputationUsed:  output.ComputationUsed,
+		MemoryEstimate:   output.MemoryEstimate,
+		RegistersRead:    executionSnapshot.ReadRegisterIDs(),
+		RegistersWritten: executionSnapshot.UpdatedRegisterIDs(),
+	}
+	if output.Err != nil {
+		simulation.ErrorMessage = output.Err.Error()
+	}
+	sort.Sort(simulation.RegistersRead)
+	sort.Sort(simulation.RegistersWritten)
+
+	return simulation, nil
 }
 
 func summarizeLog(log string, limit int) string {
@@ -213,14 +331,8 @@ func (e *QueryExecutor) GetAccount(
 	error,
 ) {
 	// TODO(ramtin): utilize ctx
-	blockCtx := fvm.NewContextFromParent(
-		e.vmCtx,
-		fvm.WithBlockHeader(blockHeader),
-		fvm.WithDerivedBlockData(
-			e.derivedChainData.NewDerivedBlockDataForScript(blockHeader.ID())))
-
 	account, err := e.vm.GetAccount(
-		blockCtx,
+		e.newBlockContext(blockHeader),
 		address,
 		snapshot)
 	if err != nil {
@@ -233,3 +345,88 @@ func (e *QueryExecutor) GetAccount(
 
 	return account, nil
 }
+
+func (e *QueryExecutor) GetAccountBalance(
+	ctx context.Context,
+	address flow.Address,
+	blockHeader *flow.Header,
+	snapshot state.StorageSnapshot,
+) (
+	uint64,
+	error,
+) {
+	balance, err := fvm.GetAccountBalance(
+		e.newBlockContext(blockHeader),
+		address,
+		snapshot)
+	if err != nil {
+		return 0, fmt.Errorf(
+			"failed to get account (%s) balance at block (%s): %w",
+			address.String(),
+			blockHeader.ID(),
+			err)
+	}
+
+	return balance, nil
+}
+
+func (e *QueryExecutor) GetAccountKeys(
+	ctx context.Context,
+	address flow.Address,
+	blockHeader *flow.Header,
+	snapshot state.StorageSnapshot,
+) (
+	[]flow.AccountPublicKey,
+	error,
+) {
+	keys, err := fvm.GetAccountKeys(
+		e.newBlockContext(blockHeader),
+		address,
+		snapshot)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to get account (%s) keys at block (%s): %w",
+			address.String(),
+			blockHeader.ID(),
+			err)
+	}
+
+	return keys, nil
+}
+
+func (e *QueryExecutor) GetAccountKey(
+	ctx context.Context,
+	address flow.Address,
+	keyIndex uint64,
+	blockHeader *flow.Header,
+	snapshot state.StorageSnapshot,
+) (
+	*flow.AccountPublicKey,
+	error,
+) {
+	key, err := fvm.GetAccountKey(
+		e.newBlockContext(blockHeader),
+		address,
+		keyIndex,
+		snapshot)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to get account (%s) key %d at block (%s): %w",
+			address.String(),
+			keyIndex,
+			blockHeader.ID(),
+			err)
+	}
+
+	return key, nil
+}
+
+// newBlockContext returns the fvm context used to query account data at the
+// given block.
+func (e *QueryExecutor) newBlockContext(blockHeader *flow.Header) fvm.Context {
+	return fvm.NewContextFromParent(
+		e.vmCtx,
+		fvm.WithBlockHeader(blockHeader),
+		fvm.WithDerivedBlockData(
+			e.derivedChainData.NewDerivedBlockDataForScript(blockHeader.ID())))
+}
@@ -15,9 +15,12 @@ import (
 	"github.com/onflow/flow-go/engine"
 	"github.com/onflow/flow-go/engine/execution"
 	"github.com/onflow/flow-go/engine/execution/computation"
+	"github.com/onflow/flow-go/engine/execution/computation/query"
 	"github.com/onflow/flow-go/engine/execution/ingestion/uploader"
 	"github.com/onflow/flow-go/engine/execution/provider"
 	"github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/fvm/meter"
+	fvmState "github.com/onflow/flow-go/fvm/state"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/flow/filter"
 	"github.com/onflow/flow-go/module"
@@ -1004,22 +1007,22 @@ func (e *Engine) matchAndFindMissingCollections(
 	return missingCollections, nil
 }
 
-func (e *Engine) ExecuteScriptAtBlockID(ctx context.Context, script []byte, arguments [][]byte, blockID flow.Identifier) ([]byte, error) {
+func (e *Engine) ExecuteScriptAtBlockID(ctx context.Context, script []byte, arguments [][]byte, blockID flow.Identifier) ([]byte, meter.Usage, error) {
 
 	stateCommit, err := e.execState.StateCommitmentByBlockID(ctx, blockID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get state commitment for block (%s): %w", blockID, err)
+		return nil, meter.Usage{}, fmt.Errorf("failed to get state commitment for block (%s): %w", blockID, err)
 	}
 
 	// return early if state with the given state commitment is not in memory
 	// and already purged. This reduces allocations for scripts targeting old blocks.
 	if !e.execState.HasState(stateCommit) {
-		return nil, fmt.Errorf("failed to execute script at block (%s): state commitment not found (%s). this error usually happens if the reference block for this script is not set to a recent block", blockID.String(), hex.EncodeToString(stateCommit[:]))
+		return nil, meter.Usage{}, fmt.Errorf("failed to execute script at block (%s): state commitment not found (%s). this error usually happens if the reference block for this script is not set to a recent block", blockID.String(), hex.EncodeToString(stateCommit[:]))
 	}
 
 	block, err := e.state.AtBlockID(blockID).Head()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get block (%s): %w", blockID, err)
+		return nil, meter.Usage{}, fmt.Errorf("failed to get block (%s): %w", blockID, err)
 	}
 
 	blockSnapshot := e.execState.NewStorageSnapshot(stateCommit)
@@ -1045,6 +1048,37 @@ func (e *Engine) ExecuteScriptAtBlockID(ctx context.Context, script []byte, argu
 		blockSnapshot)
 }
 
+func (e *Engine) SimulateTransactionAtBlockID(
+	ctx context.Context,
+	tx *flow.TransactionBody,
+	blockID flow.Identifier,
+	skipSignatureVerification bool,
+) (*query.TransactionSimulation, error) {
+
+	stateCommit, err := e.execState.StateCommitmentByBlockID(ctx, blockID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get state commitment for block (%s): %w", blockID, err)
+	}
+
+	// return early if state with the given state commitment is not in memory
+	// and already purged, consistent with script execution.
+	if !e.execState.HasState(stateCommit) {
+		return nil, fmt.Errorf("failed to simulate transaction at block (%s): state commitment not found (%s)", blockID.String(), hex.EncodeToString(stateCommit[:]))
+	}
+
+	block, err := e.state.AtBlockID(blockID).Head()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block (%s): %w", blockID, err)
+	}
+
+	return e.computationManager.SimulateTransaction(
+		ctx,
+		tx,
+		block,
+		e.execState.NewStorageSnapshot(stateCommit),
+		skipSignatureVerification)
+}
+
 func (e *Engine) GetRegisterAtBlockID(ctx context.Context, owner, key []byte, blockID flow.Identifier) ([]byte, error) {
 
 	stateCommit, err := e.execState.StateCommitmentByBlockID(ctx, blockID)
@@ -1064,15 +1098,59 @@ func (e *Engine) GetRegisterAtBlockID(ctx context.Context, owner, key []byte, bl
 }
 
 func (e *Engine) GetAccount(ctx context.Context, addr flow.Address, blockID flow.Identifier) (*flow.Account, error) {
+	block, blockSnapshot, err := e.accountSnapshotAtBlockID(ctx, blockID)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.computationManager.GetAccount(ctx, addr, block, blockSnapshot)
+}
+
+// GetAccountBalanceAtBlockID returns the balance of the account at the given block, reading only
+// the registers required to compute it.
+func (e *Engine) GetAccountBalanceAtBlockID(ctx context.Context, addr flow.Address, blockID flow.Identifier) (uint64, error) {
+	block, blockSnapshot, err := e.accountSnapshotAtBlockID(ctx, blockID)
+	if err != nil {
+		return 0, err
+	}
+
+	return e.computationManager.GetAccountBalance(ctx, addr, block, blockSnapshot)
+}
+
+// GetAccountKeysAtBlockID returns the public keys of the account at the given block, reading only
+// the registers storing them.
+func (e *Engine) GetAccountKeysAtBlockID(ctx context.Context, addr flow.Address, blockID flow.Identifier) ([]flow.AccountPublicKey, error) {
+	block, blockSnapshot, err := e.accountSnapshotAtBlockID(ctx, blockID)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.computationManager.GetAccountKeys(ctx, addr, block, blockSnapshot)
+}
+
+// GetAccountKeyAtBlockID returns the public key with the given index of the account at the given
+// block, reading only the register storing it.
+func (e *Engine) GetAccountKeyAtBlockID(ctx context.Context, addr flow.Address, keyIndex uint64, blockID flow.Identifier) (*flow.AccountPublicKey, error) {
+	block, blockSnapshot, err := e.accountSnapshotAtBlockID(ctx, blockID)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.computationManager.GetAccountKey(ctx, addr, keyIndex, block, blockSnapshot)
+}
+
+// accountSnapshotAtBlockID returns the header of the given block, and a snapshot of the execution
+// state at the block to read accounts from.
+func (e *Engine) accountSnapshotAtBlockID(ctx context.Context, blockID flow.Identifier) (*flow.Header, fvmState.StorageSnapshot, error) {
 	stateCommit, err := e.execState.StateCommitmentByBlockID(ctx, blockID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get state commitment for block (%s): %w", blockID, err)
+		return nil, nil, fmt.Errorf("failed to get state commitment for block (%s): %w", blockID, err)
 	}
 
 	// return early if state with the given state commitment is not in memory
 	// and already purged. This reduces allocations for get accounts targeting old blocks.
 	if !e.execState.HasState(stateCommit) {
-		return nil, fmt.Errorf(
+		return nil, nil, fmt.Errorf(
 			"failed to get account at block (%s): state commitment not "+
 				"found (%s). this error usually happens if the reference "+
 				"block for this script is not set to a recent block.",
@@ -1082,12 +1160,10 @@ func (e *Engine) GetAccount(ctx context.Context, addr flow.Address, blockID flow
 
 	block, err := e.state.AtBlockID(blockID).Head()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get block (%s): %w", blockID, err)
+		return nil, nil, fmt.Errorf("failed to get block (%s): %w", blockID, err)
 	}
 
-	blockSnapshot := e.execState.NewStorageSnapshot(stateCommit)
-
-	return e.computationManager.GetAccount(ctx, addr, block, blockSnapshot)
+	return block, e.execState.NewStorageSnapshot(stateCommit), nil
 }
 
 // save the execution result of a block
@@ -11,7 +11,6 @@ import (
 	"time"
 
 	"github.com/golang/mock/gomock"
-	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -28,6 +27,7 @@ import (
 	stateMock "github.com/onflow/flow-go/engine/execution/state/mock"
 	executionUnittest "github.com/onflow/flow-go/engine/execution/state/unittest"
 	"github.com/onflow/flow-go/engine/testutil/mocklocal"
+	"github.com/onflow/flow-go/fvm/meter"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/flow/filter"
 	"github.com/onflow/flow-go/model/flow/order"
@@ -200,7 +200,7 @@ func runWithEngine(t *testing.T, f func(testingContext)) {
 		return stateProtocol.IsNodeAuthorizedAt(protocolState.AtBlockID(blockID), myIdentity.NodeID)
 	}
 
-	stopControl := NewStopControl(zerolog.Nop(), false, 0)
+	stopControl := newTestStopControl(t, false, 0)
 
 	uploadMgr := uploader.NewManager(trace.NewNoopTracer())
 
@@ -1338,10 +1338,10 @@ func TestExecuteScriptAtBlockID(t *testing.T) {
 			// Successful call to computation manager
 			ctx.computationManager.
 				On("ExecuteScript", mock.Anything, script, [][]byte(nil), blockA.Block.Header, nil).
-				Return(scriptResult, nil)
+				Return(scriptResult, meter.Usage{}, nil)
 
 			// Execute our script and expect no error
-			res, err := ctx.engine.ExecuteScriptAtBlockID(context.Background(), script, nil, blockA.Block.ID())
+			res, _, err := ctx.engine.ExecuteScriptAtBlockID(context.Background(), script, nil, blockA.Block.ID())
 			assert.NoError(t, err)
 			assert.Equal(t, scriptResult, res)
 
@@ -1368,7 +1368,7 @@ func TestExecuteScriptAtBlockID(t *testing.T) {
 			ctx.executionState.On("HasState", *blockA.StartState).Return(false)
 
 			// Execute our script and expect no error
-			_, err := ctx.engine.ExecuteScriptAtBlockID(context.Background(), script, nil, blockA.Block.ID())
+			_, _, err := ctx.engine.ExecuteScriptAtBlockID(context.Background(), script, nil, blockA.Block.ID())
 			assert.Error(t, err)
 			assert.True(t, strings.Contains(err.Error(), "state commitment not found"))
 
@@ -1582,7 +1582,7 @@ func newIngestionEngine(t *testing.T, ps *mocks.ProtocolState, es *mockExecution
 		checkAuthorizedAtBlock,
 		nil,
 		nil,
-		NewStopControl(zerolog.Nop(), false, 0),
+		newTestStopControl(t, false, 0),
 	)
 
 	require.NoError(t, err)
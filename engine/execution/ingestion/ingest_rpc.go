@@ -3,18 +3,34 @@ package ingestion
 import (
 	"context"
 
+	"github.com/onflow/flow-go/engine/execution/computation/query"
+	"github.com/onflow/flow-go/fvm/meter"
 	"github.com/onflow/flow-go/model/flow"
 )
 
 // IngestRPC represents the RPC calls that the execution ingest engine exposes to support the Access Node API calls
 type IngestRPC interface {
 
-	// ExecuteScriptAtBlockID executes a script at the given Block id
-	ExecuteScriptAtBlockID(ctx context.Context, script []byte, arguments [][]byte, blockID flow.Identifier) ([]byte, error)
+	// ExecuteScriptAtBlockID executes a script at the given Block id, and returns its result along
+	// with the computation and memory it used
+	ExecuteScriptAtBlockID(ctx context.Context, script []byte, arguments [][]byte, blockID flow.Identifier) ([]byte, meter.Usage, error)
+
+	// SimulateTransactionAtBlockID executes a transaction at the given Block id without applying its
+	// state changes, optionally skipping the verification of its signatures
+	SimulateTransactionAtBlockID(ctx context.Context, tx *flow.TransactionBody, blockID flow.Identifier, skipSignatureVerification bool) (*query.TransactionSimulation, error)
 
 	// GetAccount returns the Account details at the given Block id
 	GetAccount(ctx context.Context, address flow.Address, blockID flow.Identifier) (*flow.Account, error)
 
+	// GetAccountBalanceAtBlockID returns the balance of the account at the given Block id
+	GetAccountBalanceAtBlockID(ctx context.Context, addr flow.Address, blockID flow.Identifier) (uint64, error)
+
+	// GetAccountKeysAtBlockID returns the public keys of the account at the given Block id
+	GetAccountKeysAtBlockID(ctx context.Context, addr flow.Address, blockID flow.Identifier) ([]flow.AccountPublicKey, error)
+
+	// GetAccountKeyAtBlockID returns the public key with the given index of the account at the given Block id
+	GetAccountKeyAtBlockID(ctx context.Context, addr flow.Address, keyIndex uint64, blockID flow.Identifier) (*flow.AccountPublicKey, error)
+
 	// GetRegisterAtBlockID returns the value of a register at the given Block id (if available)
 	GetRegisterAtBlockID(ctx context.Context, owner, key []byte, blockID flow.Identifier) ([]byte, error)
 }
@@ -7,7 +7,11 @@ import (
 
 	flow "github.com/onflow/flow-go/model/flow"
 
+	meter "github.com/onflow/flow-go/fvm/meter"
+
 	mock "github.com/stretchr/testify/mock"
+
+	query "github.com/onflow/flow-go/engine/execution/computation/query"
 )
 
 // IngestRPC is an autogenerated mock type for the IngestRPC type
@@ -16,12 +20,13 @@ type IngestRPC struct {
 }
 
 // ExecuteScriptAtBlockID provides a mock function with given fields: ctx, script, arguments, blockID
-func (_m *IngestRPC) ExecuteScriptAtBlockID(ctx context.Context, script []byte, arguments [][]byte, blockID flow.Identifier) ([]byte, error) {
+func (_m *IngestRPC) ExecuteScriptAtBlockID(ctx context.Context, script []byte, arguments [][]byte, blockID flow.Identifier) ([]byte, meter.Usage, error) {
 	ret := _m.Called(ctx, script, arguments, blockID)
 
 	var r0 []byte
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, []byte, [][]byte, flow.Identifier) ([]byte, error)); ok {
+	var r1 meter.Usage
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, [][]byte, flow.Identifier) ([]byte, meter.Usage, error)); ok {
 		return rf(ctx, script, arguments, blockID)
 	}
 	if rf, ok := ret.Get(0).(func(context.Context, []byte, [][]byte, flow.Identifier) []byte); ok {
@@ -32,13 +37,19 @@ func (_m *IngestRPC) ExecuteScriptAtBlockID(ctx context.Context, script []byte,
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, []byte, [][]byte, flow.Identifier) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, []byte, [][]byte, flow.Identifier) meter.Usage); ok {
 		r1 = rf(ctx, script, arguments, blockID)
 	} else {
-		r1 = ret.Error(1)
+		r1 = ret.Get(1).(meter.Usage)
 	}
 
-	return r0, r1
+	if rf, ok := ret.Get(2).(func(context.Context, []byte, [][]byte, flow.Identifier) error); ok {
+		r2 = rf(ctx, script, arguments, blockID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
 // GetAccount provides a mock function with given fields: ctx, address, blockID
@@ -67,6 +78,82 @@ func (_m *IngestRPC) GetAccount(ctx context.Context, address flow.Address, block
 	return r0, r1
 }
 
+// GetAccountBalanceAtBlockID provides a mock function with given fields: ctx, addr, blockID
+func (_m *IngestRPC) GetAccountBalanceAtBlockID(ctx context.Context, addr flow.Address, blockID flow.Identifier) (uint64, error) {
+	ret := _m.Called(ctx, addr, blockID)
+
+	var r0 uint64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Address, flow.Identifier) (uint64, error)); ok {
+		return rf(ctx, addr, blockID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Address, flow.Identifier) uint64); ok {
+		r0 = rf(ctx, addr, blockID)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, flow.Address, flow.Identifier) error); ok {
+		r1 = rf(ctx, addr, blockID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAccountKeyAtBlockID provides a mock function with given fields: ctx, addr, keyIndex, blockID
+func (_m *IngestRPC) GetAccountKeyAtBlockID(ctx context.Context, addr flow.Address, keyIndex uint64, blockID flow.Identifier) (*flow.AccountPublicKey, error) {
+	ret := _m.Called(ctx, addr, keyIndex, blockID)
+
+	var r0 *flow.AccountPublicKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Address, uint64, flow.Identifier) (*flow.AccountPublicKey, error)); ok {
+		return rf(ctx, addr, keyIndex, blockID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Address, uint64, flow.Identifier) *flow.AccountPublicKey); ok {
+		r0 = rf(ctx, addr, keyIndex, blockID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*flow.AccountPublicKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, flow.Address, uint64, flow.Identifier) error); ok {
+		r1 = rf(ctx, addr, keyIndex, blockID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAccountKeysAtBlockID provides a mock function with given fields: ctx, addr, blockID
+func (_m *IngestRPC) GetAccountKeysAtBlockID(ctx context.Context, addr flow.Address, blockID flow.Identifier) ([]flow.AccountPublicKey, error) {
+	ret := _m.Called(ctx, addr, blockID)
+
+	var r0 []flow.AccountPublicKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Address, flow.Identifier) ([]flow.AccountPublicKey, error)); ok {
+		return rf(ctx, addr, blockID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, flow.Address, flow.Identifier) []flow.AccountPublicKey); ok {
+		r0 = rf(ctx, addr, blockID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]flow.AccountPublicKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, flow.Address, flow.Identifier) error); ok {
+		r1 = rf(ctx, addr, blockID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetRegisterAtBlockID provides a mock function with given fields: ctx, owner, key, blockID
 func (_m *IngestRPC) GetRegisterAtBlockID(ctx context.Context, owner []byte, key []byte, blockID flow.Identifier) ([]byte, error) {
 	ret := _m.Called(ctx, owner, key, blockID)
@@ -93,6 +180,32 @@ func (_m *IngestRPC) GetRegisterAtBlockID(ctx context.Context, owner []byte, key
 	return r0, r1
 }
 
+// SimulateTransactionAtBlockID provides a mock function with given fields: ctx, tx, blockID, skipSignatureVerification
+func (_m *IngestRPC) SimulateTransactionAtBlockID(ctx context.Context, tx *flow.TransactionBody, blockID flow.Identifier, skipSignatureVerification bool) (*query.TransactionSimulation, error) {
+	ret := _m.Called(ctx, tx, blockID, skipSignatureVerification)
+
+	var r0 *query.TransactionSimulation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *flow.TransactionBody, flow.Identifier, bool) (*query.TransactionSimulation, error)); ok {
+		return rf(ctx, tx, blockID, skipSignatureVerification)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *flow.TransactionBody, flow.Identifier, bool) *query.TransactionSimulation); ok {
+		r0 = rf(ctx, tx, blockID, skipSignatureVerification)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*query.TransactionSimulation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *flow.TransactionBody, flow.Identifier, bool) error); ok {
+		r1 = rf(ctx, tx, blockID, skipSignatureVerification)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewIngestRPC interface {
 	mock.TestingT
 	Cleanup(func())
@@ -2,19 +2,28 @@ package ingestion
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/rs/zerolog"
+	"golang.org/x/mod/semver"
 
 	"github.com/onflow/flow-go/engine/execution/state"
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
 )
 
 // StopControl is a specialized component used by ingestion.Engine to encapsulate
 // control of pausing/stopping blocks execution.
 // It is intended to work tightly with the Engine, not as a general mechanism or interface.
 // StopControl follows states described in StopState
+//
+// The stop height can be tagged with the node software version required to execute blocks at and
+// above it, in which case it is a version boundary. Version boundaries are persisted together with the
+// crash mode, so that a node which is restarted without being upgraded does not execute beyond the
+// boundary, and stops at it the same way.
 type StopControl struct {
 	sync.RWMutex
 	// desired stopHeight, the first value new version should be used,
@@ -27,6 +36,16 @@ type StopControl struct {
 	// This is the block ID of the block that should be executed last.
 	stopAfterExecuting flow.Identifier
 
+	// version required to execute blocks at and above stopHeight,
+	// empty if stopHeight is not a version boundary
+	stopVersion string
+
+	// version of the node software, used to check whether a version boundary applies to this node
+	nodeVersion string
+
+	// persists the version boundary across restarts
+	stopBoundary storage.StopBoundary
+
 	log   zerolog.Logger
 	state StopControlState
 
@@ -60,22 +79,105 @@ const (
 	StopControlPaused
 )
 
-// NewStopControl creates new empty NewStopControl
+func (s StopControlState) String() string {
+	switch s {
+	case StopControlOff:
+		return "off"
+	case StopControlSet:
+		return "set"
+	case StopControlCommenced:
+		return "commenced"
+	case StopControlPaused:
+		return "paused"
+	default:
+		return fmt.Sprintf("unknown(%d)", byte(s))
+	}
+}
+
+// NewStopControl creates new NewStopControl, and restores the version boundary persisted by
+// previous runs of the node, unless the node version satisfies it.
+// No errors are expected during normal operations.
 func NewStopControl(
 	log zerolog.Logger,
 	paused bool,
 	lastExecutedHeight uint64,
-) *StopControl {
+	nodeVersion string,
+	stopBoundary storage.StopBoundary,
+) (*StopControl, error) {
 	state := StopControlOff
 	if paused {
 		state = StopControlPaused
 	}
 	log.Debug().Msgf("created StopControl module with paused = %t", paused)
-	return &StopControl{
+	sc := &StopControl{
 		log:                    log,
 		state:                  state,
 		highestExecutingHeight: lastExecutedHeight,
+		nodeVersion:            nodeVersion,
+		stopBoundary:           stopBoundary,
+	}
+
+	err := sc.loadVersionBoundary(lastExecutedHeight)
+	if err != nil {
+		return nil, fmt.Errorf("could not load version boundary: %w", err)
+	}
+
+	return sc, nil
+}
+
+// loadVersionBoundary restores the persisted version boundary.
+// A boundary satisfied by the node version is removed, since the node was upgraded.
+// Otherwise, the node stops at the boundary, crashing or pausing as requested when the boundary
+// was set, right away if all blocks below the boundary have already been executed.
+func (s *StopControl) loadVersionBoundary(lastExecutedHeight uint64) error {
+	boundary, crash, err := s.stopBoundary.Retrieve()
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not retrieve version boundary: %w", err)
 	}
+
+	satisfied, err := versionSatisfies(s.nodeVersion, boundary.Version)
+	if err != nil {
+		s.log.Warn().Err(err).
+			Str("node_version", s.nodeVersion).
+			Str("required_version", boundary.Version).
+			Msg("could not compare node version with version boundary, assuming it is not satisfied")
+	}
+
+	if satisfied {
+		s.log.Info().
+			Uint64("stop_height", boundary.BlockHeight).
+			Str("node_version", s.nodeVersion).
+			Str("required_version", boundary.Version).
+			Msg("node version satisfies the version boundary, removing it")
+		return s.stopBoundary.Remove()
+	}
+
+	s.stopHeight = boundary.BlockHeight
+	s.stopVersion = boundary.Version
+	s.crash = crash
+
+	if s.state == StopControlPaused {
+		return nil
+	}
+
+	if lastExecutedHeight+1 >= boundary.BlockHeight {
+		// all blocks below the boundary were executed, so the node crashes or pauses right away,
+		// as it would have when reaching the boundary
+		s.stopExecution()
+		return nil
+	}
+
+	s.state = StopControlSet
+	s.log.Info().
+		Uint64("stop_height", boundary.BlockHeight).
+		Str("required_version", boundary.Version).
+		Bool("crash", crash).
+		Msg("version boundary restored")
+
+	return nil
 }
 
 // GetState returns current state of StopControl module
@@ -96,6 +198,7 @@ func (s *StopControl) IsPaused() bool {
 //   - stopHeight
 //   - crash
 //
+// The new stopHeight replaces the version boundary, if any.
 // Returns error if the stopping process has already commenced, new values will be rejected.
 func (s *StopControl) SetStopHeight(
 	height uint64,
@@ -107,34 +210,16 @@ func (s *StopControl) SetStopHeight(
 	oldHeight := s.stopHeight
 	oldCrash := s.crash
 
-	if s.state == StopControlCommenced {
-		return oldHeight,
-			oldCrash,
-			fmt.Errorf(
-				"cannot update stopHeight, "+
-					"stopping commenced for stopHeight %d with crash=%t",
-				oldHeight,
-				oldCrash,
-			)
-	}
-
-	if s.state == StopControlPaused {
-		return oldHeight,
-			oldCrash,
-			fmt.Errorf("cannot update stopHeight, already paused")
+	err := s.checkCanSetStopHeight(height)
+	if err != nil {
+		return oldHeight, oldCrash, err
 	}
 
-	// cannot set stopHeight to block which is already executing
-	// so the lowest possible stopHeight is highestExecutingHeight+1
-	if height <= s.highestExecutingHeight {
-		return oldHeight,
-			oldCrash,
-			fmt.Errorf(
-				"cannot update stopHeight, "+
-					"given stopHeight %d below or equal to highest executing height %d",
-				height,
-				s.highestExecutingHeight,
-			)
+	if s.stopVersion != "" {
+		err = s.stopBoundary.Remove()
+		if err != nil {
+			return oldHeight, oldCrash, fmt.Errorf("could not remove version boundary: %w", err)
+		}
 	}
 
 	s.log.Info().
@@ -144,17 +229,110 @@ func (s *StopControl) SetStopHeight(
 		Bool("crash", crash).
 		Uint64("old_height", oldHeight).
 		Bool("old_crash", oldCrash).
+		Str("old_version", s.stopVersion).
 		Msg("new stopHeight set")
 
 	s.state = StopControlSet
 
 	s.stopHeight = height
 	s.crash = crash
+	s.stopVersion = ""
 	s.stopAfterExecuting = flow.ZeroID
 
 	return oldHeight, oldCrash, nil
 }
 
+// SetVersionBoundary sets a version boundary: the node stops before executing the block at the
+// given height, which requires the given node version, and does not execute it after a restart
+// unless it runs a version satisfying the boundary. The boundary replaces the current stopHeight.
+//
+// Returns error if the stopping process has already commenced, if the node version already
+// satisfies the boundary, or if the boundary cannot be persisted.
+func (s *StopControl) SetVersionBoundary(
+	height uint64,
+	version string,
+	crash bool,
+) error {
+	s.Lock()
+	defer s.Unlock()
+
+	_, err := normalizeVersion(version)
+	if err != nil {
+		return fmt.Errorf("invalid version %q: %w", version, err)
+	}
+
+	// a node version which is not a valid semantic version never satisfies a boundary
+	satisfied, _ := versionSatisfies(s.nodeVersion, version)
+	if satisfied {
+		return fmt.Errorf(
+			"cannot set version boundary, node version %s already satisfies version %s",
+			s.nodeVersion,
+			version,
+		)
+	}
+
+	err = s.checkCanSetStopHeight(height)
+	if err != nil {
+		return err
+	}
+
+	boundary := flow.VersionBoundary{BlockHeight: height, Version: version}
+	err = s.stopBoundary.Store(boundary, crash)
+	if err != nil {
+		return fmt.Errorf("could not store version boundary: %w", err)
+	}
+
+	s.log.Info().
+		Int8("previous_state", int8(s.state)).
+		Int8("new_state", int8(StopControlSet)).
+		Uint64("stopHeight", height).
+		Str("required_version", version).
+		Bool("crash", crash).
+		Uint64("old_height", s.stopHeight).
+		Bool("old_crash", s.crash).
+		Str("old_version", s.stopVersion).
+		Msg("new version boundary set")
+
+	s.state = StopControlSet
+
+	s.stopHeight = height
+	s.crash = crash
+	s.stopVersion = version
+	s.stopAfterExecuting = flow.ZeroID
+
+	return nil
+}
+
+// checkCanSetStopHeight returns an error if the stopHeight cannot be changed to the given height.
+// Must be called while holding the lock.
+func (s *StopControl) checkCanSetStopHeight(height uint64) error {
+	if s.state == StopControlCommenced {
+		return fmt.Errorf(
+			"cannot update stopHeight, "+
+				"stopping commenced for stopHeight %d with crash=%t",
+			s.stopHeight,
+			s.crash,
+		)
+	}
+
+	if s.state == StopControlPaused {
+		return fmt.Errorf("cannot update stopHeight, already paused")
+	}
+
+	// cannot set stopHeight to block which is already executing
+	// so the lowest possible stopHeight is highestExecutingHeight+1
+	if height <= s.highestExecutingHeight {
+		return fmt.Errorf(
+			"cannot update stopHeight, "+
+				"given stopHeight %d below or equal to highest executing height %d",
+			height,
+			s.highestExecutingHeight,
+		)
+	}
+
+	return nil
+}
+
 // GetStopHeight returns:
 //   - stopHeight
 //   - crash
@@ -167,6 +345,22 @@ func (s *StopControl) GetStopHeight() (uint64, bool) {
 	return s.stopHeight, s.crash
 }
 
+// GetVersionBoundary returns the version boundary, and false if stopHeight is not a version boundary.
+func (s *StopControl) GetVersionBoundary() (flow.VersionBoundary, bool) {
+	s.RLock()
+	defer s.RUnlock()
+
+	if s.stopVersion == "" {
+		return flow.VersionBoundary{}, false
+	}
+	return flow.VersionBoundary{BlockHeight: s.stopHeight, Version: s.stopVersion}, true
+}
+
+// NodeVersion returns the version of the node software
+func (s *StopControl) NodeVersion() string {
+	return s.nodeVersion
+}
+
 // blockProcessable should be called when new block is processable.
 // It returns boolean indicating if the block should be processed.
 func (s *StopControl) blockProcessable(b *flow.Header) bool {
@@ -279,12 +473,15 @@ func (s *StopControl) blockExecuted(h *flow.Header) {
 
 func (s *StopControl) stopExecution() {
 	if s.crash {
-		s.log.Fatal().Msgf(
-			"Crashing as finalization reached requested "+
-				"stop height %d and the highest executed block is (%d - 1)",
-			s.stopHeight,
-			s.stopHeight,
-		)
+		s.log.Fatal().
+			Str("required_version", s.stopVersion).
+			Str("node_version", s.nodeVersion).
+			Msgf(
+				"Crashing as finalization reached requested "+
+					"stop height %d and the highest executed block is (%d - 1)",
+				s.stopHeight,
+				s.stopHeight,
+			)
 		return
 	}
 
@@ -295,11 +492,14 @@ func (s *StopControl) stopExecution() {
 
 	s.state = StopControlPaused
 
-	s.log.Warn().Msgf(
-		"Pausing execution as finalization reached "+
-			"the requested stop height %d",
-		s.stopHeight,
-	)
+	s.log.Warn().
+		Str("required_version", s.stopVersion).
+		Str("node_version", s.nodeVersion).
+		Msgf(
+			"Pausing execution as finalization reached "+
+				"the requested stop height %d",
+			s.stopHeight,
+		)
 
 }
 
@@ -318,3 +518,31 @@ func (s *StopControl) executingBlockHeight(height uint64) {
 		s.highestExecutingHeight = height
 	}
 }
+
+// versionSatisfies returns true if the node version is the same as or newer than the required
+// version. Versions are compared according to semantic versioning, ignoring build metadata.
+// Returns an error if either version is not a valid semantic version, for example if the node
+// version was not defined at build time.
+func versionSatisfies(nodeVersion string, requiredVersion string) (bool, error) {
+	node, err := normalizeVersion(nodeVersion)
+	if err != nil {
+		return false, fmt.Errorf("invalid node version %q: %w", nodeVersion, err)
+	}
+	required, err := normalizeVersion(requiredVersion)
+	if err != nil {
+		return false, fmt.Errorf("invalid required version %q: %w", requiredVersion, err)
+	}
+	return semver.Compare(node, required) >= 0, nil
+}
+
+// normalizeVersion returns the version with the "v" prefix required by the semver package, which
+// versions may omit. Returns an error if the version is not a valid semantic version.
+func normalizeVersion(version string) (string, error) {
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+	if !semver.IsValid(version) {
+		return "", fmt.Errorf("not a semantic version")
+	}
+	return version, nil
+}
@@ -4,22 +4,36 @@ import (
 	"context"
 	"testing"
 
-	"github.com/onflow/flow-go/storage"
-
+	"github.com/dgraph-io/badger/v2"
 	testifyMock "github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/flow-go/engine/execution/state/mock"
-
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	bstorage "github.com/onflow/flow-go/storage/badger"
+	storagemock "github.com/onflow/flow-go/storage/mock"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
+const testNodeVersion = "v0.30.1"
+
+// newTestStopControl creates a StopControl without a persisted version boundary
+func newTestStopControl(t *testing.T, paused bool, lastExecutedHeight uint64) *StopControl {
+	stopBoundary := storagemock.NewStopBoundary(t)
+	stopBoundary.On("Retrieve").Return(flow.VersionBoundary{}, false, storage.ErrNotFound)
+
+	sc, err := NewStopControl(unittest.Logger(), paused, lastExecutedHeight, testNodeVersion, stopBoundary)
+	require.NoError(t, err)
+	return sc
+}
+
 // If stopping mechanism has caused any changes to execution flow (skipping execution of blocks)
 // we disallow setting new values
 func TestCannotSetNewValuesAfterStoppingCommenced(t *testing.T) {
 
 	t.Run("when processing block at stop height", func(t *testing.T) {
-		sc := NewStopControl(unittest.Logger(), false, 0)
+		sc := newTestStopControl(t, false, 0)
 
 		require.Equal(t, sc.GetState(), StopControlOff)
 
@@ -55,7 +69,7 @@ func TestCannotSetNewValuesAfterStoppingCommenced(t *testing.T) {
 
 		execState := new(mock.ReadOnlyExecutionState)
 
-		sc := NewStopControl(unittest.Logger(), false, 0)
+		sc := newTestStopControl(t, false, 0)
 
 		require.Equal(t, sc.GetState(), StopControlOff)
 
@@ -100,7 +114,7 @@ func TestExecutionFallingBehind(t *testing.T) {
 	headerC := unittest.BlockHeaderWithParentFixture(headerB) // 22
 	headerD := unittest.BlockHeaderWithParentFixture(headerC) // 23
 
-	sc := NewStopControl(unittest.Logger(), false, 0)
+	sc := newTestStopControl(t, false, 0)
 
 	require.Equal(t, sc.GetState(), StopControlOff)
 
@@ -139,7 +153,7 @@ func TestExecutionFallingBehind(t *testing.T) {
 // below or too close to it
 func TestCannotSetHeightBelowLastExecuted(t *testing.T) {
 
-	sc := NewStopControl(unittest.Logger(), false, 0)
+	sc := newTestStopControl(t, false, 0)
 
 	require.Equal(t, sc.GetState(), StopControlOff)
 
@@ -158,13 +172,13 @@ func TestCannotSetHeightBelowLastExecuted(t *testing.T) {
 // StopControl started as paused will keep the state
 func TestStartingPaused(t *testing.T) {
 
-	sc := NewStopControl(unittest.Logger(), true, 0)
+	sc := newTestStopControl(t, true, 0)
 	require.Equal(t, StopControlPaused, sc.GetState())
 }
 
 func TestPausedStateRejectsAllBlocksAndChanged(t *testing.T) {
 
-	sc := NewStopControl(unittest.Logger(), true, 0)
+	sc := newTestStopControl(t, true, 0)
 	require.Equal(t, StopControlPaused, sc.GetState())
 
 	_, _, err := sc.SetStopHeight(2137, true)
@@ -181,3 +195,175 @@ func TestPausedStateRejectsAllBlocksAndChanged(t *testing.T) {
 
 	execState.AssertExpectations(t)
 }
+
+func TestVersionBoundary(t *testing.T) {
+
+	t.Run("pauses at the boundary", func(t *testing.T) {
+		stopBoundary := storagemock.NewStopBoundary(t)
+		stopBoundary.On("Retrieve").Return(flow.VersionBoundary{}, false, storage.ErrNotFound)
+		stopBoundary.On("Store", flow.VersionBoundary{BlockHeight: 22, Version: "v0.31.0"}, false).Return(nil).Once()
+
+		sc, err := NewStopControl(unittest.Logger(), false, 0, testNodeVersion, stopBoundary)
+		require.NoError(t, err)
+
+		err = sc.SetVersionBoundary(22, "v0.31.0", false)
+		require.NoError(t, err)
+		require.Equal(t, StopControlSet, sc.GetState())
+
+		boundary, ok := sc.GetVersionBoundary()
+		require.True(t, ok)
+		require.Equal(t, flow.VersionBoundary{BlockHeight: 22, Version: "v0.31.0"}, boundary)
+
+		execState := new(mock.ReadOnlyExecutionState)
+		execState.On("StateCommitmentByBlockID", testifyMock.Anything, testifyMock.Anything).Return(nil, nil)
+
+		header := unittest.BlockHeaderFixture(unittest.WithHeaderHeight(22))
+		require.False(t, sc.blockProcessable(header))
+		sc.blockFinalized(context.TODO(), execState, header)
+		require.Equal(t, StopControlPaused, sc.GetState())
+
+		execState.AssertExpectations(t)
+	})
+
+	t.Run("rejects invalid boundaries", func(t *testing.T) {
+		sc := newTestStopControl(t, false, 20)
+
+		// invalid version
+		err := sc.SetVersionBoundary(25, "latest", false)
+		require.Error(t, err)
+
+		// version already satisfied by the node
+		err = sc.SetVersionBoundary(25, "v0.30.0", false)
+		require.Error(t, err)
+
+		// boundary at already executed height
+		err = sc.SetVersionBoundary(20, "v0.31.0", false)
+		require.Error(t, err)
+
+		require.Equal(t, StopControlOff, sc.GetState())
+	})
+
+	t.Run("stop height replaces the boundary", func(t *testing.T) {
+		stopBoundary := storagemock.NewStopBoundary(t)
+		stopBoundary.On("Retrieve").Return(flow.VersionBoundary{}, false, storage.ErrNotFound)
+		stopBoundary.On("Store", testifyMock.Anything, true).Return(nil).Once()
+		stopBoundary.On("Remove").Return(nil).Once()
+
+		sc, err := NewStopControl(unittest.Logger(), false, 0, testNodeVersion, stopBoundary)
+		require.NoError(t, err)
+
+		err = sc.SetVersionBoundary(22, "v0.31.0", true)
+		require.NoError(t, err)
+
+		_, _, err = sc.SetStopHeight(30, false)
+		require.NoError(t, err)
+
+		_, ok := sc.GetVersionBoundary()
+		require.False(t, ok)
+	})
+}
+
+// TestVersionBoundaryRestart checks that the version boundary applies after a restart,
+// until the node runs a version satisfying it
+func TestVersionBoundaryRestart(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		stopBoundary := bstorage.NewStopBoundary(db)
+
+		sc, err := NewStopControl(unittest.Logger(), false, 10, testNodeVersion, stopBoundary)
+		require.NoError(t, err)
+		require.Equal(t, StopControlOff, sc.GetState())
+
+		err = sc.SetVersionBoundary(22, "v0.31.0", false)
+		require.NoError(t, err)
+
+		// restarted with the same version before reaching the boundary
+		sc, err = NewStopControl(unittest.Logger(), false, 15, testNodeVersion, stopBoundary)
+		require.NoError(t, err)
+		require.Equal(t, StopControlSet, sc.GetState())
+		height, _ := sc.GetStopHeight()
+		require.Equal(t, uint64(22), height)
+
+		// the crash mode is restored with the boundary
+		err = sc.SetVersionBoundary(22, "v0.31.0", true)
+		require.NoError(t, err)
+		sc, err = NewStopControl(unittest.Logger(), false, 15, testNodeVersion, stopBoundary)
+		require.NoError(t, err)
+		require.Equal(t, StopControlSet, sc.GetState())
+		height, crash := sc.GetStopHeight()
+		require.Equal(t, uint64(22), height)
+		require.True(t, crash)
+
+		err = sc.SetVersionBoundary(22, "v0.31.0", false)
+		require.NoError(t, err)
+
+		// restarted with the same version after executing all blocks below the boundary
+		sc, err = NewStopControl(unittest.Logger(), false, 21, testNodeVersion, stopBoundary)
+		require.NoError(t, err)
+		require.Equal(t, StopControlPaused, sc.GetState())
+		boundary, ok := sc.GetVersionBoundary()
+		require.True(t, ok)
+		require.Equal(t, "v0.31.0", boundary.Version)
+
+		// restarted with an undefined version
+		sc, err = NewStopControl(unittest.Logger(), false, 21, "undefined", stopBoundary)
+		require.NoError(t, err)
+		require.Equal(t, StopControlPaused, sc.GetState())
+
+		// restarted with the required version
+		sc, err = NewStopControl(unittest.Logger(), false, 21, "v0.31.0", stopBoundary)
+		require.NoError(t, err)
+		require.Equal(t, StopControlOff, sc.GetState())
+		_, ok = sc.GetVersionBoundary()
+		require.False(t, ok)
+
+		_, _, err = stopBoundary.Retrieve()
+		require.ErrorIs(t, err, storage.ErrNotFound)
+	})
+}
+
+func TestVersionSatisfies(t *testing.T) {
+	for _, c := range []struct {
+		node      string
+		required  string
+		satisfied bool
+	}{
+		{"v0.31.0", "v0.31.0", true},
+		{"0.31.0", "v0.31.0", true},
+		{"v0.31.1", "v0.31.0", true},
+		{"v1.0.0", "v0.31.0", true},
+		{"v0.30.9", "v0.31.0", false},
+		{"v0.31.0-rc.1", "v0.31.0", false},
+		{"v0.31.0-rc.2", "v0.31.0-rc.1", true},
+		// pre-release identifiers are compared one by one, numeric identifiers numerically
+		{"v0.31.0-rc.10", "v0.31.0-rc.9", true},
+		{"v0.31.0-rc.9", "v0.31.0-rc.10", false},
+		{"v0.31.0-rc.1.2", "v0.31.0-rc.1", true},
+		{"v0.31.0-rc", "v0.31.0-rc.1", false},
+		{"v0.31.0-alpha.beta", "v0.31.0-alpha.1", true},
+		{"v0.31.0-alpha.1", "v0.31.0-alpha.beta", false},
+		{"v0.31.0-beta", "v0.31.0-alpha.beta", true},
+		{"v0.31.0-rc.18446744073709551616", "v0.31.0-rc.18446744073709551615", true},
+		{"v0.31.0", "v0.31.0-rc.1", true},
+		{"v0.31.0+build.1", "v0.31.0", true},
+		// MAJOR.MINOR and MAJOR are shorthands for MAJOR.MINOR.0 and MAJOR.0.0
+		{"v0.31.0", "v0.31", true},
+		{"v0.30.9", "v0.31", false},
+	} {
+		satisfied, err := versionSatisfies(c.node, c.required)
+		require.NoError(t, err)
+		require.Equal(t, c.satisfied, satisfied, "%s satisfies %s", c.node, c.required)
+	}
+
+	_, err := versionSatisfies("undefined", "v0.31.0")
+	require.Error(t, err)
+
+	_, err = versionSatisfies("v0.31.0", "v0.31.0.1")
+	require.Error(t, err)
+
+	_, err = versionSatisfies("v0.31.0", "v0.31.0-rc..1")
+	require.Error(t, err)
+
+	// numeric pre-release identifiers must not have leading zeros
+	_, err = versionSatisfies("v0.31.0", "v0.31.0-rc.01")
+	require.Error(t, err)
+}
@@ -10,6 +10,7 @@ import (
 	"unicode/utf8"
 
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
+	"github.com/onflow/flow/protobuf/go/flow/entities"
 	"github.com/onflow/flow/protobuf/go/flow/execution"
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc"
@@ -20,13 +21,31 @@ import (
 	"github.com/onflow/flow-go/engine"
 	"github.com/onflow/flow-go/engine/common/rpc"
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	"github.com/onflow/flow-go/engine/common/rpc/extended"
 	"github.com/onflow/flow-go/engine/execution/ingestion"
+	"github.com/onflow/flow-go/engine/execution/state"
 	fvmerrors "github.com/onflow/flow-go/fvm/errors"
+	"github.com/onflow/flow-go/ledger"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/state/protocol"
 	"github.com/onflow/flow-go/storage"
 )
 
+const (
+	// DefaultIterateRegistersLimit is the number of registers returned by IterateRegistersAtBlockID
+	// if no limit is provided
+	DefaultIterateRegistersLimit = 100
+
+	// MaxIterateRegistersLimit is the maximum number of registers returned by a single
+	// IterateRegistersAtBlockID request
+	MaxIterateRegistersLimit = 1000
+)
+
+// LedgerIterator iterates the registers of a ledger state.
+type LedgerIterator interface {
+	Iterate(query *ledger.IterateQuery) (*ledger.IterateResult, error)
+}
+
 // Config defines the configurable options for the gRPC server.
 type Config struct {
 	ListenAddr        string
@@ -54,6 +73,7 @@ func New(
 	exeResults storage.ExecutionResults,
 	txResults storage.TransactionResults,
 	commits storage.Commits,
+	ledgerIterator LedgerIterator,
 	chainID flow.ChainID,
 	signerIndicesDecoder hotstuff.BlockSignerDecoder,
 	apiRatelimits map[string]int, // the api rate limit (max calls per second) for each of the gRPC API e.g. Ping->100, ExecuteScriptAtBlockID->300
@@ -97,6 +117,7 @@ func New(
 			exeResults:           exeResults,
 			transactionResults:   txResults,
 			commits:              commits,
+			ledger:               ledgerIterator,
 			log:                  log,
 		},
 		server: server,
@@ -109,6 +130,7 @@ func New(
 	}
 
 	execution.RegisterExecutionAPIServer(eng.server, eng.handler)
+	extended.RegisterExtendedExecutionAPIServer(eng.server, eng.handler)
 
 	return eng
 }
@@ -145,8 +167,10 @@ func (e *Engine) serve() {
 	}
 }
 
-// handler implements a subset of the Observation API.
+// handler implements a subset of the Observation API, and the extended Execution API.
 type handler struct {
+	extended.UnimplementedExtendedExecutionAPIServer
+
 	engine               ingestion.IngestRPC
 	chain                flow.ChainID
 	headers              storage.Headers
@@ -157,9 +181,11 @@ type handler struct {
 	transactionResults   storage.TransactionResults
 	log                  zerolog.Logger
 	commits              storage.Commits
+	ledger               LedgerIterator
 }
 
 var _ execution.ExecutionAPIServer = &handler{}
+var _ extended.ExtendedExecutionAPIServer = &handler{}
 
 // Ping responds to requests when the server is up.
 func (h *handler) Ping(_ context.Context, _ *execution.PingRequest) (*execution.PingResponse, error) {
@@ -176,12 +202,20 @@ func (h *handler) ExecuteScriptAtBlockID(
 		return nil, err
 	}
 
-	value, err := h.engine.ExecuteScriptAtBlockID(ctx, req.GetScript(), req.GetArguments(), blockID)
+	value, usage, err := h.engine.ExecuteScriptAtBlockID(ctx, req.GetScript(), req.GetArguments(), blockID)
 	if err != nil {
 		// return code 3 as this passes the litmus test in our context
 		return nil, status.Errorf(codes.InvalidArgument, "failed to execute script: %v", err)
 	}
 
+	// the response has no fields for the computation and memory used, so they're sent in the trailer
+	if rpc.ScriptUsageRequested(ctx) {
+		err = grpc.SetTrailer(ctx, rpc.ScriptUsageTrailer(usage))
+		if err != nil {
+			h.log.Debug().Err(err).Msg("failed to send script usage")
+		}
+	}
+
 	res := &execution.ExecuteScriptAtBlockIDResponse{
 		Value: value,
 	}
@@ -520,6 +554,248 @@ func (h *handler) GetAccountAtBlockID(
 
 }
 
+// GetAccountBalanceAtBlockID returns the balance of an account at the given block, reading only the
+// registers storing it.
+func (h *handler) GetAccountBalanceAtBlockID(
+	ctx context.Context,
+	req *extended.GetAccountBalanceAtBlockIDRequest,
+) (*extended.AccountBalanceResponse, error) {
+	flowAddress, blockID, err := h.accountRequest(req.GetAddress(), req.GetBlockId())
+	if err != nil {
+		return nil, err
+	}
+
+	balance, err := h.engine.GetAccountBalanceAtBlockID(ctx, flowAddress, blockID)
+	if err != nil {
+		return nil, convertAccountError(err, flowAddress, "failed to get account balance")
+	}
+
+	return &extended.AccountBalanceResponse{
+		Balance: balance,
+	}, nil
+}
+
+// GetAccountKeyAtBlockID returns the public key with the given index of an account at the given
+// block, reading only the register storing it.
+func (h *handler) GetAccountKeyAtBlockID(
+	ctx context.Context,
+	req *extended.GetAccountKeyAtBlockIDRequest,
+) (*extended.AccountKeyResponse, error) {
+	flowAddress, blockID, err := h.accountRequest(req.GetAddress(), req.GetBlockId())
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := h.engine.GetAccountKeyAtBlockID(ctx, flowAddress, uint64(req.GetIndex()), blockID)
+	if err != nil {
+		return nil, convertAccountError(err, flowAddress, "failed to get account key")
+	}
+
+	accountKey, err := convert.AccountKeyToMessage(*key)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to convert account key to message: %v", err)
+	}
+
+	return &extended.AccountKeyResponse{
+		AccountKey: accountKey,
+	}, nil
+}
+
+// GetAccountKeysAtBlockID returns the public keys of an account at the given block, reading only
+// the registers storing them.
+func (h *handler) GetAccountKeysAtBlockID(
+	ctx context.Context,
+	req *extended.GetAccountKeysAtBlockIDRequest,
+) (*extended.AccountKeysResponse, error) {
+	flowAddress, blockID, err := h.accountRequest(req.GetAddress(), req.GetBlockId())
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := h.engine.GetAccountKeysAtBlockID(ctx, flowAddress, blockID)
+	if err != nil {
+		return nil, convertAccountError(err, flowAddress, "failed to get account keys")
+	}
+
+	accountKeys := make([]*entities.AccountKey, len(keys))
+	for i, key := range keys {
+		accountKeys[i], err = convert.AccountKeyToMessage(key)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to convert account key to message: %v", err)
+		}
+	}
+
+	return &extended.AccountKeysResponse{
+		AccountKeys: accountKeys,
+	}, nil
+}
+
+// SimulateTransactionAtBlockID executes the transaction at the end of the given block without
+// applying its state changes, and returns the events it emitted, its error and the registers it
+// touched.
+func (h *handler) SimulateTransactionAtBlockID(
+	ctx context.Context,
+	req *extended.SimulateTransactionAtBlockIDRequest,
+) (*extended.SimulateTransactionResponse, error) {
+	blockID, err := convert.BlockID(req.GetBlockId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid blockID: %v", err)
+	}
+
+	if req.GetTransaction() == nil {
+		return nil, status.Error(codes.InvalidArgument, "transaction is required")
+	}
+	tx, err := convert.MessageToTransaction(req.GetTransaction(), h.chain.Chain())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid transaction: %v", err)
+	}
+
+	simulation, err := h.engine.SimulateTransactionAtBlockID(ctx, &tx, blockID, req.GetSkipSignatureVerification())
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, status.Errorf(codes.NotFound, "failed to simulate transaction: state of block not found: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to simulate transaction: %v", err)
+	}
+
+	return &extended.SimulateTransactionResponse{
+		Events:           convert.EventsToMessages(simulation.Events),
+		ErrorMessage:     simulation.ErrorMessage,
+		ComputationUsed:  simulation.ComputationUsed,
+		MemoryEstimate:   simulation.MemoryEstimate,
+		RegistersRead:    registerIDsToMessages(simulation.RegistersRead),
+		RegistersWritten: registerIDsToMessages(simulation.RegistersWritten),
+	}, nil
+}
+
+// IterateRegistersAtBlockID returns a page of the registers of the execution state at the given
+// block, optionally restricted to the registers of an account whose keys start with a prefix, and
+// the path to start after to get the next page.
+func (h *handler) IterateRegistersAtBlockID(
+	_ context.Context,
+	req *extended.IterateRegistersAtBlockIDRequest,
+) (*extended.IterateRegistersResponse, error) {
+	blockID, err := convert.BlockID(req.GetBlockId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid blockID: %v", err)
+	}
+
+	var prefix ledger.Key
+	if owner := req.GetOwner(); len(owner) > 0 {
+		address, err := convert.Address(owner, h.chain.Chain())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid owner: %v", err)
+		}
+		parts := []ledger.KeyPart{ledger.NewKeyPart(state.KeyPartOwner, address.Bytes())}
+		if keyPrefix := req.GetKeyPrefix(); len(keyPrefix) > 0 {
+			parts = append(parts, ledger.NewKeyPart(state.KeyPartKey, keyPrefix))
+		}
+		prefix = ledger.NewKey(parts)
+	} else if len(req.GetKeyPrefix()) > 0 {
+		return nil, status.Error(codes.InvalidArgument, "key prefix requires an owner")
+	}
+
+	var startAfter *ledger.Path
+	if len(req.GetStartAfter()) > 0 {
+		path, err := ledger.ToPath(req.GetStartAfter())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid start after path: %v", err)
+		}
+		startAfter = &path
+	}
+
+	limit := int(req.GetLimit())
+	if limit == 0 {
+		limit = DefaultIterateRegistersLimit
+	} else if limit > MaxIterateRegistersLimit {
+		return nil, status.Errorf(codes.InvalidArgument, "limit must be at most %d, got %d", MaxIterateRegistersLimit, limit)
+	}
+
+	commit, err := h.commits.ByBlockID(blockID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, status.Errorf(codes.NotFound, "state commitment for block ID %s does not exist", blockID)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get state commitment for block ID %s: %v", blockID, err)
+	}
+
+	query, err := ledger.NewIterateQuery(ledger.State(commit), prefix, startAfter, limit)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create iterate query: %v", err)
+	}
+
+	result, err := h.ledger.Iterate(query)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to iterate registers at state %v: %v", commit, err)
+	}
+
+	registers := make([]*extended.Register, len(result.Keys))
+	for i, key := range result.Keys {
+		if len(key.KeyParts) != 2 {
+			return nil, status.Errorf(codes.Internal, "unexpected key with %d parts: %s", len(key.KeyParts), key.String())
+		}
+		registers[i] = &extended.Register{
+			Id: &extended.RegisterID{
+				Owner: key.KeyParts[0].Value,
+				Key:   key.KeyParts[1].Value,
+			},
+			Value: result.Values[i],
+		}
+	}
+
+	var nextStartAfter []byte
+	if result.NextStartAfter != nil {
+		nextStartAfter = result.NextStartAfter[:]
+	}
+
+	return &extended.IterateRegistersResponse{
+		StateCommitment: commit[:],
+		Registers:       registers,
+		NextStartAfter:  nextStartAfter,
+	}, nil
+}
+
+func registerIDsToMessages(ids flow.RegisterIDs) []*extended.RegisterID {
+	messages := make([]*extended.RegisterID, len(ids))
+	for i, id := range ids {
+		messages[i] = &extended.RegisterID{
+			Owner: []byte(id.Owner),
+			Key:   []byte(id.Key),
+		}
+	}
+	return messages
+}
+
+// accountRequest converts the address and block ID of an account request.
+func (h *handler) accountRequest(address []byte, blockID []byte) (flow.Address, flow.Identifier, error) {
+	flowBlockID, err := convert.BlockID(blockID)
+	if err != nil {
+		return flow.EmptyAddress, flow.ZeroID, status.Errorf(codes.InvalidArgument, "invalid blockID: %v", err)
+	}
+
+	flowAddress, err := convert.Address(address, h.chain.Chain())
+	if err != nil {
+		return flow.EmptyAddress, flow.ZeroID, status.Errorf(codes.InvalidArgument, "invalid address: %v", err)
+	}
+
+	return flowAddress, flowBlockID, nil
+}
+
+// convertAccountError converts an error returned when reading parts of an account into a grpc
+// status error.
+func convertAccountError(err error, address flow.Address, msg string) error {
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		return status.Errorf(codes.NotFound, "%s: state of block not found: %v", msg, err)
+	case fvmerrors.IsAccountNotFoundError(err):
+		return status.Errorf(codes.NotFound, "%s: account with address %s not found", msg, address)
+	case fvmerrors.IsAccountPublicKeyNotFoundError(err):
+		return status.Errorf(codes.NotFound, "%s: %v", msg, err)
+	default:
+		return status.Errorf(codes.Internal, "%s: %v", msg, err)
+	}
+}
+
 // GetLatestBlockHeader gets the latest sealed or finalized block header.
 func (h *handler) GetLatestBlockHeader(
 	_ context.Context,
@@ -10,15 +10,26 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/testing/protocmp"
 
 	"github.com/onflow/flow/protobuf/go/flow/entities"
 	"github.com/onflow/flow/protobuf/go/flow/execution"
 
+	"github.com/onflow/flow-go/crypto"
+	"github.com/onflow/flow-go/crypto/hash"
+	"github.com/onflow/flow-go/engine/common/rpc"
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	"github.com/onflow/flow-go/engine/common/rpc/extended"
+	"github.com/onflow/flow-go/engine/execution/computation/query"
 	ingestion "github.com/onflow/flow-go/engine/execution/ingestion/mock"
+	"github.com/onflow/flow-go/engine/execution/state"
+	fvmerrors "github.com/onflow/flow-go/fvm/errors"
+	"github.com/onflow/flow-go/fvm/meter"
+	"github.com/onflow/flow-go/ledger"
 	"github.com/onflow/flow-go/model/flow"
 	realstorage "github.com/onflow/flow-go/storage"
 	storage "github.com/onflow/flow-go/storage/mock"
@@ -73,16 +84,39 @@ func (suite *Suite) TestExecuteScriptAtBlockID() {
 
 	suite.Run("happy path with successful script execution", func() {
 		mockEngine.On("ExecuteScriptAtBlockID", ctx, script, arguments, mockIdentifier).
-			Return(scriptExecValue, nil).Once()
+			Return(scriptExecValue, meter.Usage{}, nil).Once()
 		response, err := handler.ExecuteScriptAtBlockID(ctx, &executionReq)
 		suite.Require().NoError(err)
 		suite.Require().Equal(&executionResp, response)
 		mockEngine.AssertExpectations(suite.T())
 	})
 
+	suite.Run("happy path with script usage requested", func() {
+		usage := meter.Usage{
+			ComputationUsed:  12,
+			ComputationLimit: 9999,
+			MemoryEstimate:   2048,
+			MemoryLimit:      4096,
+		}
+		stream := &mockServerTransportStream{}
+		usageCtx := metadata.NewIncomingContext(ctx, metadata.Pairs(rpc.ScriptUsageHeader, "true"))
+		usageCtx = grpc.NewContextWithServerTransportStream(usageCtx, stream)
+
+		mockEngine.On("ExecuteScriptAtBlockID", usageCtx, script, arguments, mockIdentifier).
+			Return(scriptExecValue, usage, nil).Once()
+		response, err := handler.ExecuteScriptAtBlockID(usageCtx, &executionReq)
+		suite.Require().NoError(err)
+		suite.Require().Equal(&executionResp, response)
+		suite.Require().Equal([]string{"12"}, stream.trailer.Get(rpc.ComputationUsedTrailer))
+		suite.Require().Equal([]string{"9999"}, stream.trailer.Get(rpc.ComputationLimitTrailer))
+		suite.Require().Equal([]string{"2048"}, stream.trailer.Get(rpc.MemoryEstimateTrailer))
+		suite.Require().Equal([]string{"4096"}, stream.trailer.Get(rpc.MemoryLimitTrailer))
+		mockEngine.AssertExpectations(suite.T())
+	})
+
 	suite.Run("valid request with script execution failure", func() {
 		mockEngine.On("ExecuteScriptAtBlockID", ctx, script, arguments, mockIdentifier).
-			Return(nil, status.Error(codes.InvalidArgument, "")).Once()
+			Return(nil, meter.Usage{}, status.Error(codes.InvalidArgument, "")).Once()
 		_, err := handler.ExecuteScriptAtBlockID(ctx, &executionReq)
 		suite.Require().Error(err)
 		errors.Is(err, status.Error(codes.InvalidArgument, ""))
@@ -294,6 +328,254 @@ func (suite *Suite) TestGetAccountAtBlockID() {
 	})
 }
 
+// TestGetAccountBalanceAndKeysAtBlockID tests the extended Execution API calls reading the balance
+// and public keys of an account
+func (suite *Suite) TestGetAccountBalanceAndKeysAtBlockID() {
+
+	id := unittest.IdentifierFixture()
+	serviceAddress := flow.Mainnet.Chain().ServiceAddress()
+	privateKey, err := unittest.AccountKeyFixture(128, crypto.ECDSAP256, hash.SHA3_256)
+	suite.Require().NoError(err)
+	key := privateKey.PublicKey(1000)
+
+	mockEngine := new(ingestion.IngestRPC)
+
+	// create the handler
+	handler := &handler{
+		engine: mockEngine,
+		chain:  flow.Mainnet,
+	}
+
+	suite.Run("balance", func() {
+		mockEngine.On("GetAccountBalanceAtBlockID", mock.Anything, serviceAddress, id).Return(uint64(42), nil).Once()
+
+		resp, err := handler.GetAccountBalanceAtBlockID(context.Background(), &extended.GetAccountBalanceAtBlockIDRequest{
+			Address: serviceAddress.Bytes(),
+			BlockId: id[:],
+		})
+
+		suite.Require().NoError(err)
+		suite.Require().Equal(uint64(42), resp.GetBalance())
+		mockEngine.AssertExpectations(suite.T())
+	})
+
+	suite.Run("keys", func() {
+		mockEngine.On("GetAccountKeysAtBlockID", mock.Anything, serviceAddress, id).Return([]flow.AccountPublicKey{key}, nil).Once()
+
+		resp, err := handler.GetAccountKeysAtBlockID(context.Background(), &extended.GetAccountKeysAtBlockIDRequest{
+			Address: serviceAddress.Bytes(),
+			BlockId: id[:],
+		})
+
+		suite.Require().NoError(err)
+		expectedKey, err := convert.AccountKeyToMessage(key)
+		suite.Require().NoError(err)
+		suite.Require().Len(resp.GetAccountKeys(), 1)
+		suite.Require().Empty(cmp.Diff(expectedKey, resp.GetAccountKeys()[0], protocmp.Transform()))
+		mockEngine.AssertExpectations(suite.T())
+	})
+
+	suite.Run("key", func() {
+		mockEngine.On("GetAccountKeyAtBlockID", mock.Anything, serviceAddress, uint64(0), id).Return(&key, nil).Once()
+
+		resp, err := handler.GetAccountKeyAtBlockID(context.Background(), &extended.GetAccountKeyAtBlockIDRequest{
+			Address: serviceAddress.Bytes(),
+			Index:   0,
+			BlockId: id[:],
+		})
+
+		suite.Require().NoError(err)
+		expectedKey, err := convert.AccountKeyToMessage(key)
+		suite.Require().NoError(err)
+		suite.Require().Empty(cmp.Diff(expectedKey, resp.GetAccountKey(), protocmp.Transform()))
+		mockEngine.AssertExpectations(suite.T())
+	})
+
+	suite.Run("missing key", func() {
+		mockEngine.On("GetAccountKeyAtBlockID", mock.Anything, serviceAddress, uint64(1), id).
+			Return(nil, fvmerrors.NewAccountPublicKeyNotFoundError(serviceAddress, 1)).Once()
+
+		_, err := handler.GetAccountKeyAtBlockID(context.Background(), &extended.GetAccountKeyAtBlockIDRequest{
+			Address: serviceAddress.Bytes(),
+			Index:   1,
+			BlockId: id[:],
+		})
+
+		suite.Require().Equal(codes.NotFound, status.Code(err))
+		mockEngine.AssertExpectations(suite.T())
+	})
+
+	suite.Run("invalid request with nil block id", func() {
+		_, err := handler.GetAccountBalanceAtBlockID(context.Background(), &extended.GetAccountBalanceAtBlockIDRequest{
+			Address: serviceAddress.Bytes(),
+		})
+
+		suite.Require().Equal(codes.InvalidArgument, status.Code(err))
+	})
+
+	suite.Run("invalid request with nil address", func() {
+		_, err := handler.GetAccountKeysAtBlockID(context.Background(), &extended.GetAccountKeysAtBlockIDRequest{
+			BlockId: id[:],
+		})
+
+		suite.Require().Equal(codes.InvalidArgument, status.Code(err))
+	})
+}
+
+// TestSimulateTransactionAtBlockID tests the SimulateTransactionAtBlockID API call
+func (suite *Suite) TestSimulateTransactionAtBlockID() {
+
+	id := unittest.IdentifierFixture()
+	tx := unittest.TransactionBodyFixture()
+	register := flow.NewRegisterID("01", "foo")
+
+	mockEngine := new(ingestion.IngestRPC)
+
+	// create the handler
+	handler := &handler{
+		engine: mockEngine,
+		chain:  flow.Testnet,
+	}
+
+	suite.Run("happy path with valid request", func() {
+		simulation := &query.TransactionSimulation{
+			Events:          flow.EventsList{unittest.EventFixture(flow.EventAccountCreated, 0, 0, tx.ID(), 0)},
+			ErrorMessage:    "out of gas",
+			ComputationUsed: 12,
+			MemoryEstimate:  300,
+			RegistersRead:   flow.RegisterIDs{register},
+		}
+		mockEngine.On("SimulateTransactionAtBlockID", mock.Anything, &tx, id, true).Return(simulation, nil).Once()
+
+		resp, err := handler.SimulateTransactionAtBlockID(context.Background(), &extended.SimulateTransactionAtBlockIDRequest{
+			BlockId:                   id[:],
+			Transaction:               convert.TransactionToMessage(tx),
+			SkipSignatureVerification: true,
+		})
+
+		suite.Require().NoError(err)
+		suite.Require().Equal([]flow.Event(simulation.Events), convert.MessagesToEvents(resp.GetEvents()))
+		suite.Require().Equal("out of gas", resp.GetErrorMessage())
+		suite.Require().Equal(uint64(12), resp.GetComputationUsed())
+		suite.Require().Equal(uint64(300), resp.GetMemoryEstimate())
+		suite.Require().Len(resp.GetRegistersRead(), 1)
+		suite.Require().Equal([]byte(register.Owner), resp.GetRegistersRead()[0].GetOwner())
+		suite.Require().Equal([]byte(register.Key), resp.GetRegistersRead()[0].GetKey())
+		suite.Require().Empty(resp.GetRegistersWritten())
+		mockEngine.AssertExpectations(suite.T())
+	})
+
+	suite.Run("state of block not found", func() {
+		mockEngine.On("SimulateTransactionAtBlockID", mock.Anything, &tx, id, false).Return(nil, realstorage.ErrNotFound).Once()
+
+		_, err := handler.SimulateTransactionAtBlockID(context.Background(), &extended.SimulateTransactionAtBlockIDRequest{
+			BlockId:     id[:],
+			Transaction: convert.TransactionToMessage(tx),
+		})
+
+		suite.Require().Equal(codes.NotFound, status.Code(err))
+		mockEngine.AssertExpectations(suite.T())
+	})
+
+	suite.Run("invalid request without transaction", func() {
+		_, err := handler.SimulateTransactionAtBlockID(context.Background(), &extended.SimulateTransactionAtBlockIDRequest{
+			BlockId: id[:],
+		})
+
+		suite.Require().Equal(codes.InvalidArgument, status.Code(err))
+	})
+}
+
+type ledgerIteratorFunc func(query *ledger.IterateQuery) (*ledger.IterateResult, error)
+
+func (f ledgerIteratorFunc) Iterate(query *ledger.IterateQuery) (*ledger.IterateResult, error) {
+	return f(query)
+}
+
+// TestIterateRegistersAtBlockID tests the IterateRegistersAtBlockID API call
+func (suite *Suite) TestIterateRegistersAtBlockID() {
+
+	id := unittest.IdentifierFixture()
+	commit := unittest.StateCommitmentFixture()
+	owner := unittest.RandomAddressFixture()
+
+	var startAfter, nextStartAfter ledger.Path
+	startAfter[0] = 1
+	nextStartAfter[0] = 2
+
+	var query *ledger.IterateQuery
+	iterator := ledgerIteratorFunc(func(q *ledger.IterateQuery) (*ledger.IterateResult, error) {
+		query = q
+		return &ledger.IterateResult{
+			Keys: []ledger.Key{ledger.NewKey([]ledger.KeyPart{
+				ledger.NewKeyPart(state.KeyPartOwner, owner.Bytes()),
+				ledger.NewKeyPart(state.KeyPartKey, []byte("public_key_0")),
+			})},
+			Values:         []ledger.Value{[]byte{1, 2, 3}},
+			NextStartAfter: &nextStartAfter,
+		}, nil
+	})
+
+	// create the handler
+	handler := &handler{
+		chain:   flow.Testnet,
+		commits: suite.commits,
+		ledger:  iterator,
+	}
+
+	suite.commits.On("ByBlockID", id).Return(commit, nil)
+
+	suite.Run("happy path with valid request", func() {
+		resp, err := handler.IterateRegistersAtBlockID(context.Background(), &extended.IterateRegistersAtBlockIDRequest{
+			BlockId:    id[:],
+			Owner:      owner.Bytes(),
+			KeyPrefix:  []byte("public_key_"),
+			StartAfter: startAfter[:],
+			Limit:      10,
+		})
+		suite.Require().NoError(err)
+
+		suite.Require().Equal(ledger.State(commit), query.State())
+		suite.Require().Equal(ledger.NewKey([]ledger.KeyPart{
+			ledger.NewKeyPart(state.KeyPartOwner, owner.Bytes()),
+			ledger.NewKeyPart(state.KeyPartKey, []byte("public_key_")),
+		}), query.Prefix())
+		suite.Require().Equal(startAfter, *query.StartAfter())
+		suite.Require().Equal(10, query.Limit())
+
+		suite.Require().Equal(commit[:], resp.GetStateCommitment())
+		suite.Require().Len(resp.GetRegisters(), 1)
+		suite.Require().Equal(owner.Bytes(), resp.GetRegisters()[0].GetId().GetOwner())
+		suite.Require().Equal([]byte("public_key_0"), resp.GetRegisters()[0].GetId().GetKey())
+		suite.Require().Equal([]byte{1, 2, 3}, resp.GetRegisters()[0].GetValue())
+		suite.Require().Equal(nextStartAfter[:], resp.GetNextStartAfter())
+	})
+
+	suite.Run("defaults", func() {
+		_, err := handler.IterateRegistersAtBlockID(context.Background(), &extended.IterateRegistersAtBlockIDRequest{
+			BlockId: id[:],
+		})
+		suite.Require().NoError(err)
+
+		suite.Require().Empty(query.Prefix().KeyParts)
+		suite.Require().Nil(query.StartAfter())
+		suite.Require().Equal(DefaultIterateRegistersLimit, query.Limit())
+	})
+
+	suite.Run("invalid requests", func() {
+		for name, req := range map[string]*extended.IterateRegistersAtBlockIDRequest{
+			"missing block id":         {},
+			"invalid owner":            {BlockId: id[:], Owner: []byte{1, 2}},
+			"key prefix without owner": {BlockId: id[:], KeyPrefix: []byte("public_key_")},
+			"invalid start after":      {BlockId: id[:], StartAfter: []byte{1, 2}},
+			"limit too large":          {BlockId: id[:], Limit: MaxIterateRegistersLimit + 1},
+		} {
+			_, err := handler.IterateRegistersAtBlockID(context.Background(), req)
+			suite.Require().Equal(codes.InvalidArgument, status.Code(err), name)
+		}
+	})
+}
+
 // Test GetRegisterAtBlockID tests the GetRegisterAtBlockID API call
 func (suite *Suite) TestGetRegisterAtBlockID() {
 
@@ -900,3 +1182,29 @@ func (suite *Suite) TestGetTransactionResultsByBlockID() {
 		txResultsMock.AssertExpectations(suite.T())
 	})
 }
+
+// mockServerTransportStream records the metadata the handler sends with its response.
+type mockServerTransportStream struct {
+	header  metadata.MD
+	trailer metadata.MD
+}
+
+var _ grpc.ServerTransportStream = (*mockServerTransportStream)(nil)
+
+func (m *mockServerTransportStream) Method() string {
+	return ""
+}
+
+func (m *mockServerTransportStream) SetHeader(md metadata.MD) error {
+	m.header = metadata.Join(m.header, md)
+	return nil
+}
+
+func (m *mockServerTransportStream) SendHeader(md metadata.MD) error {
+	return m.SetHeader(md)
+}
+
+func (m *mockServerTransportStream) SetTrailer(md metadata.MD) error {
+	m.trailer = metadata.Join(m.trailer, md)
+	return nil
+}
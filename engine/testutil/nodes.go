@@ -17,6 +17,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/atomic"
 
+	"github.com/onflow/flow-go/cmd/build"
 	"github.com/onflow/flow-go/consensus"
 	"github.com/onflow/flow-go/consensus/hotstuff"
 	"github.com/onflow/flow-go/consensus/hotstuff/committees"
@@ -673,6 +674,15 @@ func ExecutionNode(t *testing.T, hub *stub.Hub, identity *flow.Identity, identit
 	uploader := uploader.NewManager(node.Tracer)
 
 	rootHead, rootQC := getRoot(t, &node)
+	stopControl, err := ingestion.NewStopControl(
+		node.Log.With().Str("compontent", "stop_control").Logger(),
+		false,
+		latestExecutedHeight,
+		build.Semver(),
+		storage.NewStopBoundary(node.PublicDB),
+	)
+	require.NoError(t, err)
+
 	ingestionEngine, err := ingestion.New(
 		node.Log,
 		node.Net,
@@ -693,7 +703,7 @@ func ExecutionNode(t *testing.T, hub *stub.Hub, identity *flow.Identity, identit
 		checkAuthorizedAtBlock,
 		nil,
 		uploader,
-		ingestion.NewStopControl(node.Log.With().Str("compontent", "stop_control").Logger(), false, latestExecutedHeight),
+		stopControl,
 	)
 	require.NoError(t, err)
 	requestEngine.WithHandle(ingestionEngine.OnCollection)
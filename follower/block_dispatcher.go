@@ -0,0 +1,168 @@
+package follower
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/component"
+	"github.com/onflow/flow-go/module/irrecoverable"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/storage"
+)
+
+// FinalizedBlockConsumer is called with the header of each finalized block, in consecutive height
+// order. Returning an error halts the consensus follower, without marking the block as delivered.
+type FinalizedBlockConsumer func(header *flow.Header) error
+
+// SealedBlockConsumer is called with the header of each sealed block, in consecutive height
+// order. Returning an error halts the consensus follower, without marking the block as delivered.
+type SealedBlockConsumer func(header *flow.Header) error
+
+// blockStream is a sequence of blocks delivered in consecutive height order, up to the head of
+// the stream. The height of the last delivered block is persisted.
+type blockStream struct {
+	name     string
+	head     func() (*flow.Header, error)
+	deliver  func(*flow.Header) error
+	progress storage.ConsumerProgress
+}
+
+// blockDispatcher delivers finalized and sealed blocks to the consumers of the consensus follower.
+//
+// Blocks are delivered in consecutive height order, without gaps. The height of the last
+// delivered block of each stream is persisted after the consumers returned, so delivery resumes
+// where it left off after a restart. Hence, each block is delivered at least once: blocks for
+// which delivery was interrupted are delivered again.
+type blockDispatcher struct {
+	component.Component
+	log      zerolog.Logger
+	headers  storage.Headers
+	streams  []*blockStream
+	notifier engine.Notifier
+}
+
+// newBlockDispatcher creates a new blockDispatcher. Without persisted progress, delivery starts
+// at startHeight, which must be above the root block height.
+//
+// No errors are expected during normal operation.
+func newBlockDispatcher(
+	log zerolog.Logger,
+	state protocol.State,
+	headers storage.Headers,
+	finalizedProgress storage.ConsumerProgress,
+	sealedProgress storage.ConsumerProgress,
+	startHeight uint64,
+	onFinalized func(*flow.Header) error,
+	onSealed func(*flow.Header) error,
+) (*blockDispatcher, error) {
+	d := &blockDispatcher{
+		log:     log.With().Str("module", "follower_block_dispatcher").Logger(),
+		headers: headers,
+		streams: []*blockStream{
+			{
+				name:     "finalized",
+				head:     func() (*flow.Header, error) { return state.Final().Head() },
+				deliver:  onFinalized,
+				progress: finalizedProgress,
+			},
+			{
+				name:     "sealed",
+				head:     func() (*flow.Header, error) { return state.Sealed().Head() },
+				deliver:  onSealed,
+				progress: sealedProgress,
+			},
+		},
+		notifier: engine.NewNotifier(),
+	}
+
+	for _, stream := range d.streams {
+		err := stream.progress.InitProcessedIndex(startHeight - 1)
+		if err != nil && !errors.Is(err, storage.ErrAlreadyExists) {
+			return nil, fmt.Errorf("could not initialize %s block progress: %w", stream.name, err)
+		}
+
+		delivered, err := stream.progress.ProcessedIndex()
+		if err != nil {
+			return nil, fmt.Errorf("could not get %s block progress: %w", stream.name, err)
+		}
+		d.log.Info().Uint64("delivered_height", delivered).Msgf("resuming delivery of %s blocks", stream.name)
+	}
+
+	d.Component = component.NewComponentManagerBuilder().
+		AddWorker(d.dispatchLoop).
+		Build()
+
+	return d, nil
+}
+
+// OnBlockFinalized notifies the dispatcher about newly finalized blocks. As seals are included in
+// finalized blocks, this also covers newly sealed blocks.
+func (d *blockDispatcher) OnBlockFinalized() {
+	d.notifier.Notify()
+}
+
+// dispatchLoop delivers all blocks which are not yet delivered, and then waits for notifications
+// about newly finalized blocks.
+func (d *blockDispatcher) dispatchLoop(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
+	ready()
+
+	notifier := d.notifier.Channel()
+	for {
+		for _, stream := range d.streams {
+			err := d.dispatch(ctx, stream)
+			if err != nil {
+				ctx.Throw(err)
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-notifier:
+		}
+	}
+}
+
+// dispatch delivers the blocks of the stream up to its current head.
+// No errors are expected during normal operation, except for errors returned by the consumers.
+func (d *blockDispatcher) dispatch(ctx irrecoverable.SignalerContext, stream *blockStream) error {
+	head, err := stream.head()
+	if err != nil {
+		return fmt.Errorf("could not get head of %s blocks: %w", stream.name, err)
+	}
+
+	delivered, err := stream.progress.ProcessedIndex()
+	if err != nil {
+		return fmt.Errorf("could not get %s block progress: %w", stream.name, err)
+	}
+
+	for height := delivered + 1; height <= head.Height; height++ {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		header, err := d.headers.ByHeight(height)
+		if err != nil {
+			return fmt.Errorf("could not get %s block at height %d: %w", stream.name, height, err)
+		}
+
+		err = stream.deliver(header)
+		if err != nil {
+			return fmt.Errorf("consumer failed to process %s block %v at height %d: %w", stream.name, header.ID(), height, err)
+		}
+
+		err = stream.progress.SetProcessedIndex(height)
+		if err != nil {
+			return fmt.Errorf("could not update %s block progress: %w", stream.name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,193 @@
+package follower
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/irrecoverable"
+	"github.com/onflow/flow-go/state/protocol"
+	protocolmock "github.com/onflow/flow-go/state/protocol/mock"
+	bstorage "github.com/onflow/flow-go/storage/badger"
+	storage "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// dispatcherFixture provides a chain of blocks with adjustable finalized and sealed heads.
+type dispatcherFixture struct {
+	state     *protocolmock.State
+	headers   *storage.Headers
+	chain     map[uint64]*flow.Header
+	finalized *atomic.Uint64
+	sealed    *atomic.Uint64
+
+	mu              sync.Mutex
+	deliveredFinal  []uint64
+	deliveredSealed []uint64
+}
+
+func newDispatcherFixture(t *testing.T, length uint64) *dispatcherFixture {
+	f := &dispatcherFixture{
+		state:     protocolmock.NewState(t),
+		headers:   storage.NewHeaders(t),
+		chain:     make(map[uint64]*flow.Header),
+		finalized: atomic.NewUint64(0),
+		sealed:    atomic.NewUint64(0),
+	}
+	for height := uint64(0); height <= length; height++ {
+		f.chain[height] = unittest.BlockHeaderFixture(unittest.WithHeaderHeight(height))
+	}
+
+	snapshot := func(head *atomic.Uint64) func() protocol.Snapshot {
+		return func() protocol.Snapshot {
+			snapshot := protocolmock.NewSnapshot(t)
+			snapshot.On("Head").Return(f.chain[head.Load()], nil)
+			return snapshot
+		}
+	}
+	f.state.On("Final").Return(snapshot(f.finalized)).Maybe()
+	f.state.On("Sealed").Return(snapshot(f.sealed)).Maybe()
+	f.headers.On("ByHeight", mock.Anything).Return(
+		func(height uint64) (*flow.Header, error) {
+			header, ok := f.chain[height]
+			if !ok {
+				return nil, fmt.Errorf("unknown height %d", height)
+			}
+			return header, nil
+		}).Maybe()
+
+	return f
+}
+
+func (f *dispatcherFixture) dispatcher(t *testing.T, db *badger.DB, startHeight uint64, onFinalized func(*flow.Header) error) *blockDispatcher {
+	if onFinalized == nil {
+		onFinalized = func(header *flow.Header) error {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			f.deliveredFinal = append(f.deliveredFinal, header.Height)
+			return nil
+		}
+	}
+	onSealed := func(header *flow.Header) error {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.deliveredSealed = append(f.deliveredSealed, header.Height)
+		return nil
+	}
+
+	dispatcher, err := newBlockDispatcher(
+		unittest.Logger(),
+		f.state,
+		f.headers,
+		bstorage.NewConsumerProgress(db, module.ConsumeProgressFollowerFinalizedBlockHeight),
+		bstorage.NewConsumerProgress(db, module.ConsumeProgressFollowerSealedBlockHeight),
+		startHeight,
+		onFinalized,
+		onSealed,
+	)
+	require.NoError(t, err)
+	return dispatcher
+}
+
+func (f *dispatcherFixture) delivered() ([]uint64, []uint64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]uint64{}, f.deliveredFinal...), append([]uint64{}, f.deliveredSealed...)
+}
+
+func heights(from uint64, to uint64) []uint64 {
+	var heights []uint64
+	for height := from; height <= to; height++ {
+		heights = append(heights, height)
+	}
+	return heights
+}
+
+// TestBlockDispatcher_Delivery tests that finalized and sealed blocks are delivered in consecutive
+// height order, and that delivery resumes from the persisted progress after a restart.
+func TestBlockDispatcher_Delivery(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		f := newDispatcherFixture(t, 20)
+		f.finalized.Store(5)
+		f.sealed.Store(3)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		dispatcher := f.dispatcher(t, db, 1, nil)
+		dispatcher.Start(irrecoverable.NewMockSignalerContext(t, ctx))
+		unittest.RequireCloseBefore(t, dispatcher.Ready(), time.Second, "dispatcher not ready")
+
+		// blocks finalized and sealed before starting are delivered
+		require.Eventually(t, func() bool {
+			final, sealed := f.delivered()
+			return assert.ObjectsAreEqual(heights(1, 5), final) && assert.ObjectsAreEqual(heights(1, 3), sealed)
+		}, time.Second, 10*time.Millisecond)
+
+		// newly finalized and sealed blocks are delivered after notification
+		f.finalized.Store(10)
+		f.sealed.Store(7)
+		dispatcher.OnBlockFinalized()
+		require.Eventually(t, func() bool {
+			final, sealed := f.delivered()
+			return assert.ObjectsAreEqual(heights(1, 10), final) && assert.ObjectsAreEqual(heights(1, 7), sealed)
+		}, time.Second, 10*time.Millisecond)
+
+		cancel()
+		unittest.RequireCloseBefore(t, dispatcher.Done(), time.Second, "dispatcher not done")
+
+		// after a restart, delivery resumes after the last delivered height, regardless of the start height
+		f.finalized.Store(12)
+		f.sealed.Store(9)
+		ctx, cancel = context.WithCancel(context.Background())
+		defer cancel()
+		dispatcher = f.dispatcher(t, db, 1, nil)
+		dispatcher.Start(irrecoverable.NewMockSignalerContext(t, ctx))
+		require.Eventually(t, func() bool {
+			final, sealed := f.delivered()
+			return assert.ObjectsAreEqual(heights(1, 12), final) && assert.ObjectsAreEqual(heights(1, 9), sealed)
+		}, time.Second, 10*time.Millisecond)
+	})
+}
+
+// TestBlockDispatcher_ConsumerError tests that an error of a consumer is thrown as irrecoverable
+// error, and the block is delivered again after a restart.
+func TestBlockDispatcher_ConsumerError(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		f := newDispatcherFixture(t, 20)
+		f.finalized.Store(5)
+
+		consumerErr := fmt.Errorf("consumer error")
+		dispatcher := f.dispatcher(t, db, 1, func(header *flow.Header) error {
+			if header.Height == 3 {
+				return consumerErr
+			}
+			return nil
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		signalerCtx, errChan := irrecoverable.WithSignaler(ctx)
+		dispatcher.Start(signalerCtx)
+
+		select {
+		case err := <-errChan:
+			require.ErrorIs(t, err, consumerErr)
+		case <-time.After(time.Second):
+			require.Fail(t, "consumer error was not thrown")
+		}
+
+		// the failed block is delivered again
+		delivered, err := bstorage.NewConsumerProgress(db, module.ConsumeProgressFollowerFinalizedBlockHeight).ProcessedIndex()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(2), delivered)
+	})
+}
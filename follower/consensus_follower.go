@@ -13,22 +13,44 @@ import (
 	"github.com/onflow/flow-go/consensus/hotstuff/notifications/pubsub"
 	"github.com/onflow/flow-go/crypto"
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/module/chainsync"
 	"github.com/onflow/flow-go/module/compliance"
 	"github.com/onflow/flow-go/module/component"
 	"github.com/onflow/flow-go/module/irrecoverable"
+	"github.com/onflow/flow-go/module/state_synchronization"
+	edrequester "github.com/onflow/flow-go/module/state_synchronization/requester"
 	"github.com/onflow/flow-go/module/util"
+	"github.com/onflow/flow-go/state/protocol"
+	bstorage "github.com/onflow/flow-go/storage/badger"
 )
 
 // ConsensusFollower is a standalone module run by third parties which provides
 // a mechanism for observing the block chain. It maintains a set of subscribers
 // and delivers block proposals broadcasted by the consensus nodes to each one.
+//
+// Finalized blocks, sealed blocks and execution data are delivered to the typed consumers in
+// consecutive height order. The delivered heights are persisted, so after a restart delivery
+// resumes where it left off, and each block is delivered at least once. Consumers should be
+// added before the follower is started, to not miss any blocks.
 type ConsensusFollower interface {
 	component.Component
 	// Run starts the consensus follower.
 	Run(context.Context)
-	// AddOnBlockFinalizedConsumer adds a new block finalization subscriber.
+	// AddOnBlockFinalizedConsumer adds a new block finalization subscriber. In contrast to the
+	// typed consumers, it is only notified of blocks finalized while the follower is running,
+	// and notifications might skip heights.
 	AddOnBlockFinalizedConsumer(pubsub.OnBlockFinalizedConsumer)
+	// AddFinalizedBlockConsumer adds a consumer of finalized blocks.
+	AddFinalizedBlockConsumer(FinalizedBlockConsumer)
+	// AddSealedBlockConsumer adds a consumer of sealed blocks.
+	AddSealedBlockConsumer(SealedBlockConsumer)
+	// AddExecutionDataConsumer adds a consumer of the execution data of sealed blocks. It is
+	// only called if execution data sync is enabled, see WithExecutionDataSync.
+	AddExecutionDataConsumer(state_synchronization.OnExecutionDataReceivedConsumer)
+	// ProtocolState returns the local protocol state, which provides snapshots of the finalized
+	// and sealed state, and of any known block.
+	ProtocolState() protocol.State
 }
 
 // Config contains the configurable fields for a `ConsensusFollower`.
@@ -43,6 +65,8 @@ type Config struct {
 	exposeMetrics    bool                // whether to expose metrics
 	syncConfig       *chainsync.Config   // sync core configuration
 	complianceConfig *compliance.Config  // follower engine configuration
+	startHeight      uint64              // first height to deliver without persisted progress (0: after the root block)
+	executionDataDir string              // directory to store execution data, if execution data sync is enabled
 }
 
 type Option func(c *Config)
@@ -96,6 +120,24 @@ func WithComplianceConfig(config *compliance.Config) Option {
 	}
 }
 
+// WithStartHeight sets the height of the first block delivered to the typed consumers, when the
+// follower starts without persisted progress. The height must be above the root block height.
+// By default, delivery starts with the first block after the root block.
+func WithStartHeight(height uint64) Option {
+	return func(c *Config) {
+		c.startHeight = height
+	}
+}
+
+// WithExecutionDataSync enables syncing the execution data of sealed blocks from the network,
+// which is stored in the given directory. The execution data is delivered to the consumers
+// added with AddExecutionDataConsumer.
+func WithExecutionDataSync(executionDataDir string) Option {
+	return func(c *Config) {
+		c.executionDataDir = executionDataDir
+	}
+}
+
 // BootstrapNodeInfo contains the details about the upstream bootstrap peer the consensus follower uses
 type BootstrapNodeInfo struct {
 	Host             string // ip or hostname
@@ -118,11 +160,16 @@ func bootstrapIdentities(bootstrapNodes []BootstrapNodeInfo) flow.IdentityList {
 
 func getFollowerServiceOptions(config *Config) []FollowerOption {
 	ids := bootstrapIdentities(config.bootstrapNodes)
-	return []FollowerOption{
+	options := []FollowerOption{
 		WithBootStrapPeers(ids...),
 		WithBaseOptions(getBaseOptions(config)),
 		WithNetworkKey(config.networkPrivKey),
+		WithFirstDeliveredHeight(config.startHeight),
+	}
+	if config.executionDataDir != "" {
+		options = append(options, WithExecutionDataDir(config.executionDataDir))
 	}
+	return options
 }
 
 func getBaseOptions(config *Config) []cmd.Option {
@@ -171,11 +218,16 @@ func buildConsensusFollower(opts []FollowerOption) (*FollowerServiceBuilder, err
 type ConsensusFollowerImpl struct {
 	component.Component
 	*cmd.NodeConfig
-	logger      zerolog.Logger
-	consumersMu sync.RWMutex
-	consumers   []pubsub.OnBlockFinalizedConsumer
+	logger                   zerolog.Logger
+	consumersMu              sync.RWMutex
+	consumers                []pubsub.OnBlockFinalizedConsumer
+	finalizedConsumers       []FinalizedBlockConsumer
+	sealedConsumers          []SealedBlockConsumer
+	executionDataDistributor *edrequester.ExecutionDataDistributor
 }
 
+var _ ConsensusFollower = (*ConsensusFollowerImpl)(nil)
+
 // NewConsensusFollower creates a new consensus follower.
 func NewConsensusFollower(
 	networkPrivKey crypto.PrivateKey,
@@ -201,11 +253,41 @@ func NewConsensusFollower(
 		return nil, err
 	}
 
-	cf := &ConsensusFollowerImpl{logger: anb.Logger}
+	cf := &ConsensusFollowerImpl{
+		logger:                   anb.Logger,
+		executionDataDistributor: anb.ExecutionDataDistributor,
+	}
 	anb.BaseConfig.NodeRole = "consensus_follower"
 	anb.FinalizationDistributor.AddOnBlockFinalizedConsumer(cf.onBlockFinalized)
 	cf.NodeConfig = anb.NodeConfig
 
+	anb.Component("block dispatcher", func(node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
+		startHeight := anb.FirstDeliveredHeight()
+		if startHeight <= node.RootBlock.Header.Height {
+			return nil, fmt.Errorf("start height (%d) must be greater than the root block height (%d)",
+				startHeight, node.RootBlock.Header.Height)
+		}
+
+		dispatcher, err := newBlockDispatcher(
+			node.Logger,
+			node.State,
+			node.Storage.Headers,
+			bstorage.NewConsumerProgress(node.DB, module.ConsumeProgressFollowerFinalizedBlockHeight),
+			bstorage.NewConsumerProgress(node.DB, module.ConsumeProgressFollowerSealedBlockHeight),
+			startHeight,
+			cf.onFinalizedBlock,
+			cf.onSealedBlock,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("could not create block dispatcher: %w", err)
+		}
+		anb.FinalizationDistributor.AddOnBlockFinalizedConsumer(func(*model.Block) {
+			dispatcher.OnBlockFinalized()
+		})
+
+		return dispatcher, nil
+	})
+
 	cf.Component, err = anb.Build()
 	if err != nil {
 		return nil, err
@@ -232,6 +314,60 @@ func (cf *ConsensusFollowerImpl) AddOnBlockFinalizedConsumer(consumer pubsub.OnB
 	cf.consumers = append(cf.consumers, consumer)
 }
 
+// onFinalizedBlock delivers the finalized block to all registered consumers.
+func (cf *ConsensusFollowerImpl) onFinalizedBlock(header *flow.Header) error {
+	cf.consumersMu.RLock()
+	consumers := cf.finalizedConsumers
+	cf.consumersMu.RUnlock()
+
+	for _, consumer := range consumers {
+		err := consumer(header)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// onSealedBlock delivers the sealed block to all registered consumers.
+func (cf *ConsensusFollowerImpl) onSealedBlock(header *flow.Header) error {
+	cf.consumersMu.RLock()
+	consumers := cf.sealedConsumers
+	cf.consumersMu.RUnlock()
+
+	for _, consumer := range consumers {
+		err := consumer(header)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddFinalizedBlockConsumer adds a consumer of finalized blocks.
+func (cf *ConsensusFollowerImpl) AddFinalizedBlockConsumer(consumer FinalizedBlockConsumer) {
+	cf.consumersMu.Lock()
+	defer cf.consumersMu.Unlock()
+	cf.finalizedConsumers = append(cf.finalizedConsumers, consumer)
+}
+
+// AddSealedBlockConsumer adds a consumer of sealed blocks.
+func (cf *ConsensusFollowerImpl) AddSealedBlockConsumer(consumer SealedBlockConsumer) {
+	cf.consumersMu.Lock()
+	defer cf.consumersMu.Unlock()
+	cf.sealedConsumers = append(cf.sealedConsumers, consumer)
+}
+
+// AddExecutionDataConsumer adds a consumer of the execution data of sealed blocks.
+func (cf *ConsensusFollowerImpl) AddExecutionDataConsumer(consumer state_synchronization.OnExecutionDataReceivedConsumer) {
+	cf.executionDataDistributor.AddOnExecutionDataReceivedConsumer(consumer)
+}
+
+// ProtocolState returns the local protocol state.
+func (cf *ConsensusFollowerImpl) ProtocolState() protocol.State {
+	return cf.NodeConfig.State
+}
+
 // Run starts the consensus follower.
 // This may also be implemented directly in a calling library to take advantage of error recovery
 // possible with the irrecoverable error handling.
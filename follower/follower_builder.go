@@ -5,12 +5,15 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"strings"
 
+	badger "github.com/ipfs/go-ds-badger2"
 	dht "github.com/libp2p/go-libp2p-kad-dht"
 	"github.com/libp2p/go-libp2p/core/host"
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/libp2p/go-libp2p/core/routing"
+	"github.com/onflow/go-bitswap"
 	"github.com/rs/zerolog"
 
 	"github.com/onflow/flow-go/cmd"
@@ -32,10 +35,13 @@ import (
 	"github.com/onflow/flow-go/module"
 	synchronization "github.com/onflow/flow-go/module/chainsync"
 	"github.com/onflow/flow-go/module/compliance"
+	"github.com/onflow/flow-go/module/executiondatasync/execution_data"
 	finalizer "github.com/onflow/flow-go/module/finalizer/consensus"
 	"github.com/onflow/flow-go/module/id"
 	"github.com/onflow/flow-go/module/local"
 	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/module/state_synchronization"
+	edrequester "github.com/onflow/flow-go/module/state_synchronization/requester"
 	"github.com/onflow/flow-go/module/upstream"
 	"github.com/onflow/flow-go/network"
 	netcache "github.com/onflow/flow-go/network/cache"
@@ -43,6 +49,7 @@ import (
 	cborcodec "github.com/onflow/flow-go/network/codec/cbor"
 	"github.com/onflow/flow-go/network/converter"
 	"github.com/onflow/flow-go/network/p2p"
+	"github.com/onflow/flow-go/network/p2p/blob"
 	"github.com/onflow/flow-go/network/p2p/cache"
 	p2pdht "github.com/onflow/flow-go/network/p2p/dht"
 	"github.com/onflow/flow-go/network/p2p/keyutils"
@@ -60,6 +67,8 @@ import (
 	badgerState "github.com/onflow/flow-go/state/protocol/badger"
 	"github.com/onflow/flow-go/state/protocol/blocktimer"
 	"github.com/onflow/flow-go/state/protocol/events/gadgets"
+	"github.com/onflow/flow-go/storage"
+	bstorage "github.com/onflow/flow-go/storage/badger"
 )
 
 // FlowBuilder extends cmd.NodeBuilder and declares additional functions needed to bootstrap an Access node
@@ -87,13 +96,28 @@ type FollowerServiceConfig struct {
 	bootstrapIdentities     flow.IdentityList // the identity list of bootstrap peers the node uses to discover other nodes
 	NetworkKey              crypto.PrivateKey // the networking key passed in by the caller when being used as a library
 	baseOptions             []cmd.Option
+	// startHeight is the first block height delivered to consumers when starting without persisted
+	// progress. If it is 0, delivery starts with the first block after the root block.
+	startHeight              uint64
+	executionDataSyncEnabled bool
+	executionDataDir         string
+	executionDataConfig      edrequester.ExecutionDataConfig
 }
 
 // DefaultFollowerServiceConfig defines all the default values for the FollowerServiceConfig
 func DefaultFollowerServiceConfig() *FollowerServiceConfig {
 	return &FollowerServiceConfig{
-		bootstrapNodeAddresses:  []string{},
-		bootstrapNodePublicKeys: []string{},
+		bootstrapNodeAddresses:   []string{},
+		bootstrapNodePublicKeys:  []string{},
+		startHeight:              0,
+		executionDataSyncEnabled: false,
+		executionDataConfig: edrequester.ExecutionDataConfig{
+			InitialBlockHeight: 0,
+			MaxSearchAhead:     edrequester.DefaultMaxSearchAhead,
+			FetchTimeout:       edrequester.DefaultFetchTimeout,
+			RetryDelay:         edrequester.DefaultRetryDelay,
+			MaxRetryDelay:      edrequester.DefaultMaxRetryDelay,
+		},
 	}
 }
 
@@ -119,6 +143,9 @@ type FollowerServiceBuilder struct {
 	// available until after the network has started. Hence, a factory function that needs to be called just before
 	// creating the sync engine
 	SyncEngineParticipantsProviderFactory func() module.IdentifierProvider
+	ExecutionDataDistributor              *edrequester.ExecutionDataDistributor
+	ExecutionDataDownloader               execution_data.Downloader
+	ExecutionDataRequester                state_synchronization.ExecutionDataRequester
 
 	// engines
 	FollowerEng *follower.ComplianceEngine
@@ -328,6 +355,114 @@ func (builder *FollowerServiceBuilder) BuildConsensusFollower() cmd.NodeBuilder
 	return builder
 }
 
+// BuildExecutionDataRequester enqueues the components syncing execution data of sealed blocks
+// from the network. Received execution data is passed on to the ExecutionDataDistributor.
+func (builder *FollowerServiceBuilder) BuildExecutionDataRequester() *FollowerServiceBuilder {
+	var ds *badger.Datastore
+	var bs network.BlobService
+	var processedBlockHeight storage.ConsumerProgress
+	var processedNotifications storage.ConsumerProgress
+
+	builder.
+		Module("execution data datastore and blobstore", func(node *cmd.NodeConfig) error {
+			err := os.MkdirAll(builder.executionDataDir, 0700)
+			if err != nil {
+				return err
+			}
+
+			ds, err = badger.NewDatastore(builder.executionDataDir, &badger.DefaultOptions)
+			if err != nil {
+				return err
+			}
+
+			builder.ShutdownFunc(func() error {
+				if err := ds.Close(); err != nil {
+					return fmt.Errorf("could not close execution data datastore: %w", err)
+				}
+				return nil
+			})
+
+			return nil
+		}).
+		Module("processed block height consumer progress", func(node *cmd.NodeConfig) error {
+			// uses the datastore's DB
+			processedBlockHeight = bstorage.NewConsumerProgress(ds.DB, module.ConsumeProgressExecutionDataRequesterBlockHeight)
+			return nil
+		}).
+		Module("processed notifications consumer progress", func(node *cmd.NodeConfig) error {
+			// uses the datastore's DB
+			processedNotifications = bstorage.NewConsumerProgress(ds.DB, module.ConsumeProgressExecutionDataRequesterNotification)
+			return nil
+		}).
+		Component("execution data service", func(node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
+			var err error
+			bs, err = node.Network.RegisterBlobService(channels.ExecutionDataService, ds,
+				blob.WithBitswapOptions(
+					bitswap.WithTracer(
+						blob.NewTracer(node.Logger.With().Str("blob_service", channels.ExecutionDataService.String()).Logger()),
+					),
+				),
+			)
+			if err != nil {
+				return nil, fmt.Errorf("could not register blob service: %w", err)
+			}
+
+			builder.ExecutionDataDownloader = execution_data.NewDownloader(bs)
+
+			return builder.ExecutionDataDownloader, nil
+		}).
+		Component("execution data requester", func(node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
+			// Validation of the start block height needs to be done after loading state
+			if builder.startHeight > 0 {
+				latestSeal, err := builder.State.Sealed().Head()
+				if err != nil {
+					return nil, fmt.Errorf("failed to get latest sealed height")
+				}
+
+				// Note: since the root block of a spork is also sealed in the root protocol state, the
+				// latest sealed height is always equal to the root block height. That means that at the
+				// very beginning of a spork, this check will always fail. A start height should not be
+				// specified when starting from the beginning of a spork.
+				if builder.startHeight > latestSeal.Height {
+					return nil, fmt.Errorf(
+						"execution data start block height (%d) must be less than or equal to the latest sealed block height (%d)",
+						builder.startHeight, latestSeal.Height)
+				}
+			}
+			// the requester expects the initial last processed height, which is the first height - 1
+			builder.executionDataConfig.InitialBlockHeight = builder.FirstDeliveredHeight() - 1
+
+			builder.ExecutionDataRequester = edrequester.New(
+				builder.Logger,
+				metrics.NewExecutionDataRequesterCollector(),
+				builder.ExecutionDataDownloader,
+				processedBlockHeight,
+				processedNotifications,
+				builder.State,
+				builder.Storage.Headers,
+				builder.Storage.Results,
+				builder.Storage.Seals,
+				builder.executionDataConfig,
+			)
+
+			builder.FinalizationDistributor.AddOnBlockFinalizedConsumer(builder.ExecutionDataRequester.OnBlockFinalized)
+			builder.ExecutionDataRequester.AddOnExecutionDataReceivedConsumer(builder.ExecutionDataDistributor.OnExecutionDataReceived)
+
+			return builder.ExecutionDataRequester, nil
+		})
+
+	return builder
+}
+
+// FirstDeliveredHeight returns the height of the first block delivered to consumers when starting
+// without persisted progress. It must only be called after the protocol state is initialized.
+func (builder *FollowerServiceBuilder) FirstDeliveredHeight() uint64 {
+	if builder.startHeight > 0 {
+		return builder.startHeight
+	}
+	return builder.RootBlock.Header.Height + 1
+}
+
 type FollowerOption func(*FollowerServiceConfig)
 
 func WithBootStrapPeers(bootstrapNodes ...*flow.Identity) FollowerOption {
@@ -348,6 +483,23 @@ func WithBaseOptions(baseOptions []cmd.Option) FollowerOption {
 	}
 }
 
+// WithFirstDeliveredHeight sets the first block height delivered to consumers when starting
+// without persisted progress.
+func WithFirstDeliveredHeight(height uint64) FollowerOption {
+	return func(config *FollowerServiceConfig) {
+		config.startHeight = height
+	}
+}
+
+// WithExecutionDataDir enables syncing the execution data of sealed blocks, which is stored in
+// the given directory.
+func WithExecutionDataDir(executionDataDir string) FollowerOption {
+	return func(config *FollowerServiceConfig) {
+		config.executionDataSyncEnabled = true
+		config.executionDataDir = executionDataDir
+	}
+}
+
 func FlowConsensusFollowerService(opts ...FollowerOption) *FollowerServiceBuilder {
 	config := DefaultFollowerServiceConfig()
 	for _, opt := range opts {
@@ -356,8 +508,9 @@ func FlowConsensusFollowerService(opts ...FollowerOption) *FollowerServiceBuilde
 	ret := &FollowerServiceBuilder{
 		FollowerServiceConfig: config,
 		// TODO: using RoleAccess here for now. This should be refactored eventually to have its own role type
-		FlowNodeBuilder:         cmd.FlowNode(flow.RoleAccess.String(), config.baseOptions...),
-		FinalizationDistributor: pubsub.NewFinalizationDistributor(),
+		FlowNodeBuilder:          cmd.FlowNode(flow.RoleAccess.String(), config.baseOptions...),
+		FinalizationDistributor:  pubsub.NewFinalizationDistributor(),
+		ExecutionDataDistributor: edrequester.NewExecutionDataDistributor(),
 	}
 	ret.FinalizationDistributor.AddConsumer(notifications.NewSlashingViolationsConsumer(ret.Logger))
 	// the observer gets a version of the root snapshot file that does not contain any node addresses
@@ -562,6 +715,9 @@ func (builder *FollowerServiceBuilder) validateParams() error {
 	if builder.FollowerServiceConfig.NetworkKey == nil {
 		return errors.New("networking key not provided")
 	}
+	if builder.executionDataSyncEnabled && builder.executionDataDir == "" {
+		return errors.New("execution data directory not specified")
+	}
 	if len(builder.bootstrapIdentities) > 0 {
 		return nil
 	}
@@ -676,6 +832,9 @@ func (builder *FollowerServiceBuilder) initObserverLocal() func(node *cmd.NodeCo
 // Currently, the observer only runs the follower engine.
 func (builder *FollowerServiceBuilder) Build() (cmd.Node, error) {
 	builder.BuildConsensusFollower()
+	if builder.executionDataSyncEnabled {
+		builder.BuildExecutionDataRequester()
+	}
 	return builder.FlowNodeBuilder.Build()
 }
 
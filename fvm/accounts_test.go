@@ -13,6 +13,7 @@ import (
 
 	"github.com/onflow/flow-go/engine/execution/testutil"
 	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/errors"
 	"github.com/onflow/flow-go/fvm/storage"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/utils/unittest"
@@ -1798,3 +1799,81 @@ func TestGetStorageCapacity(t *testing.T) {
 			}),
 	)
 }
+
+func TestGetAccountBalanceAndKeys(t *testing.T) {
+	options := []fvm.Option{
+		fvm.WithAuthorizationChecksEnabled(false),
+		fvm.WithSequenceNumberCheckAndIncrementEnabled(false),
+	}
+
+	t.Run("Existing account",
+		newVMTest().withContextOptions(options...).
+			run(func(t *testing.T, vm fvm.VM, chain flow.Chain, ctx fvm.Context, snapshotTree storage.SnapshotTree) {
+				snapshotTree, address := createAccount(
+					t,
+					vm,
+					chain,
+					ctx,
+					snapshotTree)
+
+				snapshotTree, publicKey := addAccountKey(
+					t,
+					vm,
+					ctx,
+					snapshotTree,
+					address,
+					accountKeyAPIVersionV2)
+
+				txBody := transferTokensTx(chain).
+					AddArgument(jsoncdc.MustEncode(cadence.UFix64(100_000_000))).
+					AddArgument(jsoncdc.MustEncode(cadence.Address(address))).
+					AddAuthorizer(chain.ServiceAddress())
+
+				executionSnapshot, output, err := vm.Run(
+					ctx,
+					fvm.Transaction(txBody, 0),
+					snapshotTree)
+				require.NoError(t, err)
+				require.NoError(t, output.Err)
+
+				snapshotTree = snapshotTree.Append(executionSnapshot)
+
+				account, err := vm.GetAccount(ctx, address, snapshotTree)
+				require.NoError(t, err)
+
+				balance, err := fvm.GetAccountBalance(ctx, address, snapshotTree)
+				require.NoError(t, err)
+				assert.Equal(t, account.Balance, balance)
+
+				keys, err := fvm.GetAccountKeys(ctx, address, snapshotTree)
+				require.NoError(t, err)
+				require.Len(t, keys, 1)
+				assert.Equal(t, account.Keys, keys)
+				assert.Equal(t, publicKey.PublicKey, keys[0].PublicKey)
+
+				key, err := fvm.GetAccountKey(ctx, address, 0, snapshotTree)
+				require.NoError(t, err)
+				assert.Equal(t, keys[0], *key)
+
+				_, err = fvm.GetAccountKey(ctx, address, 1, snapshotTree)
+				require.True(t, errors.IsAccountPublicKeyNotFoundError(err))
+			}),
+	)
+
+	t.Run("Non-existent account",
+		newVMTest().withContextOptions(options...).
+			run(func(t *testing.T, vm fvm.VM, chain flow.Chain, ctx fvm.Context, snapshotTree storage.SnapshotTree) {
+				address, err := chain.AddressAtIndex(100)
+				require.NoError(t, err)
+
+				_, err = fvm.GetAccountBalance(ctx, address, snapshotTree)
+				require.True(t, errors.IsAccountNotFoundError(err))
+
+				_, err = fvm.GetAccountKeys(ctx, address, snapshotTree)
+				require.True(t, errors.IsAccountNotFoundError(err))
+
+				_, err = fvm.GetAccountKey(ctx, address, 0, snapshotTree)
+				require.True(t, errors.IsAccountNotFoundError(err))
+			}),
+	)
+}
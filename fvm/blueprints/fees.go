@@ -42,6 +42,9 @@ var setupFeesTransactionTemplate string
 //go:embed scripts/setExecutionMemoryLimit.cdc
 var setExecutionMemoryLimit string
 
+//go:embed scripts/verifyPayerBalanceForTxExecution.cdc
+var verifyPayerBalanceForTxExecution string
+
 func DeployTxFeesContractTransaction(service, fungibleToken, flowToken, storageFees, flowFees flow.Address) *flow.TransactionBody {
 	contract := contracts.FlowFees(
 		fungibleToken.HexWithPrefix(),
@@ -230,3 +233,15 @@ func SetExecutionMemoryLimitTransaction(
 
 	return tx, nil
 }
+
+// VerifyPayerBalanceForTxExecution returns a script which checks whether a payer has enough
+// balance to pay for a transaction with the given inclusion and maximum execution effort. The
+// script returns the result of the FlowFees.verifyPayersBalanceForTransactionExecution function,
+// the same check as performed by the FVM before executing a transaction.
+func VerifyPayerBalanceForTxExecution(flowFees flow.Address) []byte {
+	return []byte(templates.ReplaceAddresses(verifyPayerBalanceForTxExecution,
+		templates.Environment{
+			FlowFeesAddress: flowFees.Hex(),
+		}),
+	)
+}
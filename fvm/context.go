@@ -348,3 +348,16 @@ func WithEventEncoder(encoder environment.EventEncoder) Option {
 		return ctx
 	}
 }
+
+// WithExecutionTraceCollector sets the collector of the steps performed while
+// executing a transaction or script. Function calls are only collected if
+// tracing is enabled in the Cadence runtime config, see
+// WithReusableCadenceRuntimePool.
+func WithExecutionTraceCollector(
+	collector environment.ExecutionTraceCollector,
+) Option {
+	return func(ctx Context) Context {
+		ctx.ExecutionTraceCollector = collector
+		return ctx
+	}
+}
@@ -99,6 +99,10 @@ type EnvironmentParams struct {
 	TransactionInfoParams
 
 	ContractUpdaterParams
+
+	// ExecutionTraceCollector, if set, collects a step for every register
+	// access, function call, emitted event and computation metering.
+	ExecutionTraceCollector ExecutionTraceCollector
 }
 
 func DefaultEnvironmentParams() EnvironmentParams {
@@ -0,0 +1,278 @@
+package environment
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime/common"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// cadenceFunctionTracePrefix is the prefix of the operation names Cadence
+// reports function invocations with, when tracing is enabled in its runtime
+// config.
+const cadenceFunctionTracePrefix = "function."
+
+// ExecutionTraceStepKind is the kind of operation recorded by an execution
+// trace step.
+type ExecutionTraceStepKind string
+
+const (
+	ExecutionTraceRegisterRead       ExecutionTraceStepKind = "register_read"
+	ExecutionTraceRegisterWrite      ExecutionTraceStepKind = "register_write"
+	ExecutionTraceFunctionCall       ExecutionTraceStepKind = "function_call"
+	ExecutionTraceEventEmitted       ExecutionTraceStepKind = "event_emitted"
+	ExecutionTraceComputationMetered ExecutionTraceStepKind = "computation_metered"
+)
+
+// ExecutionTraceStep is a single operation performed while executing a
+// transaction or script. Only the fields relevant to the step kind are set,
+// except for the meter usage, which is recorded for every step after the
+// operation completed.
+type ExecutionTraceStep struct {
+	Kind ExecutionTraceStepKind `json:"kind"`
+
+	// register reads and writes, the value is hex encoded
+	Register string `json:"register,omitempty"`
+	Value    string `json:"value,omitempty"`
+
+	// function calls
+	Location string        `json:"location,omitempty"`
+	Function string        `json:"function,omitempty"`
+	Duration time.Duration `json:"duration_ns,omitempty"`
+
+	// emitted events, the payload is JSON-CDC encoded
+	EventType    string          `json:"event_type,omitempty"`
+	EventPayload json.RawMessage `json:"event_payload,omitempty"`
+
+	// computation metering
+	ComputationKind string `json:"computation_kind,omitempty"`
+	Intensity       uint   `json:"intensity,omitempty"`
+
+	ComputationUsed uint64 `json:"computation_used"`
+	MemoryEstimate  uint64 `json:"memory_estimate"`
+}
+
+// ExecutionTraceCollector receives the steps of a transaction or script
+// execution, in the order they are performed. Function calls are reported
+// by Cadence when the call returns, so they are collected after the steps
+// performed by the called function.
+//
+// Function calls are only reported if tracing is enabled in the Cadence
+// runtime config of the reusable runtime pool.
+type ExecutionTraceCollector interface {
+	Collect(step ExecutionTraceStep)
+}
+
+// ExecutionTrace is an ExecutionTraceCollector which keeps all steps in
+// memory. It is not concurrency safe, and must only be used for a single
+// transaction or script.
+type ExecutionTrace struct {
+	Steps []ExecutionTraceStep `json:"steps"`
+}
+
+var _ ExecutionTraceCollector = &ExecutionTrace{}
+
+func NewExecutionTrace() *ExecutionTrace {
+	return &ExecutionTrace{
+		Steps: []ExecutionTraceStep{},
+	}
+}
+
+func (trace *ExecutionTrace) Collect(step ExecutionTraceStep) {
+	trace.Steps = append(trace.Steps, step)
+}
+
+// collectExecutionTraceStep records the current meter usage on the step and
+// passes it to the collector.
+func collectExecutionTraceStep(
+	collector ExecutionTraceCollector,
+	meter Meter,
+	step ExecutionTraceStep,
+) {
+	// the meter implementations never return errors, and the usage is
+	// informational only.
+	step.ComputationUsed, _ = meter.ComputationUsed()
+	step.MemoryEstimate, _ = meter.MemoryUsed()
+
+	collector.Collect(step)
+}
+
+type tracingMeter struct {
+	Meter
+
+	collector ExecutionTraceCollector
+}
+
+// NewTracingMeter returns a Meter which reports every successful computation
+// metering to the collector.
+func NewTracingMeter(
+	impl Meter,
+	collector ExecutionTraceCollector,
+) Meter {
+	return &tracingMeter{
+		Meter:     impl,
+		collector: collector,
+	}
+}
+
+func (meter *tracingMeter) MeterComputation(
+	kind common.ComputationKind,
+	intensity uint,
+) error {
+	err := meter.Meter.MeterComputation(kind, intensity)
+	if err != nil {
+		return err
+	}
+
+	collectExecutionTraceStep(
+		meter.collector,
+		meter.Meter,
+		ExecutionTraceStep{
+			Kind:            ExecutionTraceComputationMetered,
+			ComputationKind: kind.String(),
+			Intensity:       intensity,
+		})
+	return nil
+}
+
+type tracingValueStore struct {
+	ValueStore
+
+	meter     Meter
+	collector ExecutionTraceCollector
+}
+
+// NewTracingValueStore returns a ValueStore which reports every successful
+// register read and write, including the register values, to the collector.
+func NewTracingValueStore(
+	impl ValueStore,
+	meter Meter,
+	collector ExecutionTraceCollector,
+) ValueStore {
+	return &tracingValueStore{
+		ValueStore: impl,
+		meter:      meter,
+		collector:  collector,
+	}
+}
+
+func (store *tracingValueStore) GetValue(
+	owner []byte,
+	key []byte,
+) (
+	[]byte,
+	error,
+) {
+	value, err := store.ValueStore.GetValue(owner, key)
+	if err != nil {
+		return nil, err
+	}
+
+	store.collectRegisterAccess(ExecutionTraceRegisterRead, owner, key, value)
+	return value, nil
+}
+
+func (store *tracingValueStore) SetValue(
+	owner []byte,
+	key []byte,
+	value []byte,
+) error {
+	err := store.ValueStore.SetValue(owner, key, value)
+	if err != nil {
+		return err
+	}
+
+	store.collectRegisterAccess(ExecutionTraceRegisterWrite, owner, key, value)
+	return nil
+}
+
+func (store *tracingValueStore) collectRegisterAccess(
+	kind ExecutionTraceStepKind,
+	owner []byte,
+	key []byte,
+	value []byte,
+) {
+	collectExecutionTraceStep(
+		store.collector,
+		store.meter,
+		ExecutionTraceStep{
+			Kind:     kind,
+			Register: flow.CadenceRegisterID(owner, key).String(),
+			Value:    hex.EncodeToString(value),
+		})
+}
+
+type tracingEventEmitter struct {
+	EventEmitter
+
+	meter     Meter
+	collector ExecutionTraceCollector
+}
+
+// NewTracingEventEmitter returns an EventEmitter which reports every
+// successfully emitted event to the collector.
+func NewTracingEventEmitter(
+	impl EventEmitter,
+	meter Meter,
+	collector ExecutionTraceCollector,
+) EventEmitter {
+	return &tracingEventEmitter{
+		EventEmitter: impl,
+		meter:        meter,
+		collector:    collector,
+	}
+}
+
+func (emitter *tracingEventEmitter) EmitEvent(event cadence.Event) error {
+	err := emitter.EventEmitter.EmitEvent(event)
+	if err != nil {
+		return err
+	}
+
+	step := ExecutionTraceStep{
+		Kind:      ExecutionTraceEventEmitted,
+		EventType: event.EventType.ID(),
+	}
+
+	// the event was already encoded by the wrapped emitter, so encoding it
+	// again is not expected to fail. If it does, the payload is omitted
+	// rather than failing the execution.
+	payload, err := jsoncdc.Encode(event)
+	if err == nil {
+		step.EventPayload = payload
+	}
+
+	collectExecutionTraceStep(emitter.collector, emitter.meter, step)
+	return nil
+}
+
+// collectFunctionCall reports a Cadence function trace to the collector. Other
+// Cadence traces (e.g. parsing, checking, value encoding) are ignored.
+func collectFunctionCall(
+	collector ExecutionTraceCollector,
+	meter Meter,
+	operation string,
+	location common.Location,
+	duration time.Duration,
+) {
+	if !strings.HasPrefix(operation, cadenceFunctionTracePrefix) {
+		return
+	}
+
+	step := ExecutionTraceStep{
+		Kind:     ExecutionTraceFunctionCall,
+		Function: strings.TrimPrefix(operation, cadenceFunctionTracePrefix),
+		Duration: duration,
+	}
+	if location != nil {
+		step.Location = location.String()
+	}
+
+	collectExecutionTraceStep(collector, meter, step)
+}
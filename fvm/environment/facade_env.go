@@ -2,9 +2,11 @@ package environment
 
 import (
 	"context"
+	"time"
 
 	"github.com/onflow/cadence/runtime/common"
 	"github.com/onflow/cadence/runtime/interpreter"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/onflow/flow-go/engine/execution/state/delta"
 	"github.com/onflow/flow-go/fvm/state"
@@ -50,6 +52,8 @@ type facadeEnvironment struct {
 
 	accounts Accounts
 	txnState storage.Transaction
+
+	executionTraceCollector ExecutionTraceCollector
 }
 
 func newFacadeEnvironment(
@@ -58,6 +62,10 @@ func newFacadeEnvironment(
 	txnState storage.Transaction,
 	meter Meter,
 ) *facadeEnvironment {
+	if params.ExecutionTraceCollector != nil {
+		meter = NewTracingMeter(meter, params.ExecutionTraceCollector)
+	}
+
 	accounts := NewAccounts(txnState)
 	logger := NewProgramLogger(tracer, params.ProgramLoggerParams)
 	runtime := NewRuntime(params.RuntimeParams)
@@ -78,6 +86,7 @@ func newFacadeEnvironment(
 
 		UnsafeRandomGenerator: NewUnsafeRandomGenerator(
 			tracer,
+			meter,
 			params.BlockHeader,
 		),
 		CryptoLibrary: NewCryptoLibrary(tracer, meter),
@@ -134,6 +143,15 @@ func newFacadeEnvironment(
 
 		accounts: accounts,
 		txnState: txnState,
+
+		executionTraceCollector: params.ExecutionTraceCollector,
+	}
+
+	if env.executionTraceCollector != nil {
+		env.ValueStore = NewTracingValueStore(
+			env.ValueStore,
+			meter,
+			env.executionTraceCollector)
 	}
 
 	env.Runtime.SetEnvironment(env)
@@ -210,6 +228,12 @@ func NewTransactionEnvironment(
 		params.TransactionInfoParams,
 		params.EventEmitterParams,
 	)
+	if env.executionTraceCollector != nil {
+		env.EventEmitter = NewTracingEventEmitter(
+			env.EventEmitter,
+			env.Meter,
+			env.executionTraceCollector)
+	}
 	env.AccountCreator = NewAccountCreator(
 		txnState,
 		params.Chain,
@@ -288,6 +312,26 @@ func (env *facadeEnvironment) addParseRestrictedChecks() {
 		env.ValueStore)
 }
 
+// RecordTrace reports Cadence traces to the tracer, and function calls to the
+// execution trace collector, if any.
+func (env *facadeEnvironment) RecordTrace(
+	operation string,
+	location common.Location,
+	duration time.Duration,
+	attrs []attribute.KeyValue,
+) {
+	env.ProgramLogger.RecordTrace(operation, location, duration, attrs)
+
+	if env.executionTraceCollector != nil {
+		collectFunctionCall(
+			env.executionTraceCollector,
+			env.Meter,
+			operation,
+			location,
+			duration)
+	}
+}
+
 func (env *facadeEnvironment) FlushPendingUpdates() (
 	ContractUpdates,
 	error,
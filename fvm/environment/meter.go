@@ -46,6 +46,7 @@ const (
 	ComputationKindBLSAggregateSignatures     = 2032
 	ComputationKindBLSAggregatePublicKeys     = 2033
 	ComputationKindGetOrLoadProgram           = 2034
+	ComputationKindUnsafeRandom               = 2035
 )
 
 type Meter interface {
@@ -55,6 +56,7 @@ type Meter interface {
 
 	MeterMemory(usage common.MemoryUsage) error
 	MemoryUsed() (uint64, error)
+	MemoryIntensities() meter.MeteredMemoryIntensities
 
 	MeterEmittedEvent(byteSize uint64) error
 	TotalEmittedEventBytes() uint64
@@ -95,6 +97,10 @@ func (meter *meterImpl) MemoryUsed() (uint64, error) {
 	return meter.txnState.TotalMemoryEstimate(), nil
 }
 
+func (meter *meterImpl) MemoryIntensities() meter.MeteredMemoryIntensities {
+	return meter.txnState.MemoryIntensities()
+}
+
 func (meter *meterImpl) InteractionUsed() (uint64, error) {
 	return meter.txnState.InteractionUsed(), nil
 }
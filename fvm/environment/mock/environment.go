@@ -989,6 +989,22 @@ func (_m *Environment) Logs() []string {
 	return r0
 }
 
+// MemoryIntensities provides a mock function with given fields:
+func (_m *Environment) MemoryIntensities() meter.MeteredMemoryIntensities {
+	ret := _m.Called()
+
+	var r0 meter.MeteredMemoryIntensities
+	if rf, ok := ret.Get(0).(func() meter.MeteredMemoryIntensities); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(meter.MeteredMemoryIntensities)
+		}
+	}
+
+	return r0
+}
+
 // MemoryUsed provides a mock function with given fields:
 func (_m *Environment) MemoryUsed() (uint64, error) {
 	ret := _m.Called()
@@ -79,6 +79,22 @@ func (_m *Meter) InteractionUsed() (uint64, error) {
 	return r0, r1
 }
 
+// MemoryIntensities provides a mock function with given fields:
+func (_m *Meter) MemoryIntensities() meter.MeteredMemoryIntensities {
+	ret := _m.Called()
+
+	var r0 meter.MeteredMemoryIntensities
+	if rf, ok := ret.Get(0).(func() meter.MeteredMemoryIntensities); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(meter.MeteredMemoryIntensities)
+		}
+	}
+
+	return r0
+}
+
 // MemoryUsed provides a mock function with given fields:
 func (_m *Meter) MemoryUsed() (uint64, error) {
 	ret := _m.Called()
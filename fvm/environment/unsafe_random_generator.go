@@ -24,6 +24,7 @@ type UnsafeRandomGenerator interface {
 
 type unsafeRandomGenerator struct {
 	tracer tracing.TracerSpan
+	meter  Meter
 
 	blockHeader *flow.Header
 
@@ -58,10 +59,12 @@ func (gen ParseRestrictedUnsafeRandomGenerator) UnsafeRandom() (
 
 func NewUnsafeRandomGenerator(
 	tracer tracing.TracerSpan,
+	meter Meter,
 	blockHeader *flow.Header,
 ) UnsafeRandomGenerator {
 	gen := &unsafeRandomGenerator{
 		tracer:      tracer,
+		meter:       meter,
 		blockHeader: blockHeader,
 	}
 
@@ -131,6 +134,13 @@ func (gen *unsafeRandomGenerator) seed() {
 func (gen *unsafeRandomGenerator) UnsafeRandom() (uint64, error) {
 	defer gen.tracer.StartExtensiveTracingChildSpan(trace.FVMEnvUnsafeRandom).End()
 
+	// metered without a weight, so that callers can tell from the computation intensities
+	// whether the results of a script depend on random numbers
+	err := gen.meter.MeterComputation(ComputationKindUnsafeRandom, 1)
+	if err != nil {
+		return 0, fmt.Errorf("unsafe random failed: %w", err)
+	}
+
 	// The internal seeding is only done once.
 	gen.seed()
 
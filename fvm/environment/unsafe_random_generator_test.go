@@ -6,11 +6,13 @@ import (
 	mrand "math/rand"
 	"testing"
 
+	"github.com/onflow/cadence/runtime/common"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gonum.org/v1/gonum/stat"
 
 	"github.com/onflow/flow-go/fvm/environment"
+	"github.com/onflow/flow-go/fvm/environment/mock"
 	"github.com/onflow/flow-go/fvm/tracing"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/utils/unittest"
@@ -47,12 +49,18 @@ func EvaluateDistributionUniformity(t *testing.T, distribution []float64) {
 	assert.Greater(t, tolerance*mean, stdev, fmt.Sprintf("basic randomness test failed: n: %d, stdev: %v, mean: %v", len(distribution), stdev, mean))
 }
 
+func newMeter(t *testing.T) environment.Meter {
+	meter := mock.NewMeter(t)
+	meter.On("MeterComputation", common.ComputationKind(environment.ComputationKindUnsafeRandom), uint(1)).Return(nil)
+	return meter
+}
+
 func TestUnsafeRandomGenerator(t *testing.T) {
 	// basic randomness test to check outputs are "uniformly" spread over the
 	// output space
 	t.Run("randomness test", func(t *testing.T) {
 		bh := unittest.BlockHeaderFixtureOnChain(flow.Mainnet.Chain().ChainID())
-		urg := environment.NewUnsafeRandomGenerator(tracing.NewTracerSpan(), bh)
+		urg := environment.NewUnsafeRandomGenerator(tracing.NewTracerSpan(), newMeter(t), bh)
 
 		// make sure n is a power of 2 so that there is no bias in the last class
 		// n is a random power of 2 (from 2 to 2^10)
@@ -67,7 +75,7 @@ func TestUnsafeRandomGenerator(t *testing.T) {
 		N := 100
 		getRandoms := func() []uint64 {
 			// seed the RG with the same block header
-			urg := environment.NewUnsafeRandomGenerator(tracing.NewTracerSpan(), bh)
+			urg := environment.NewUnsafeRandomGenerator(tracing.NewTracerSpan(), newMeter(t), bh)
 			numbers := make([]uint64, N)
 			for i := 0; i < N; i++ {
 				u, err := urg.UnsafeRandom()
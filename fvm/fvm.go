@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
 
 	"github.com/onflow/flow-go/engine/execution/state/delta"
 	"github.com/onflow/flow-go/fvm/environment"
@@ -34,6 +35,7 @@ type ProcedureOutput struct {
 	ComputationUsed        uint64
 	ComputationIntensities meter.MeteredComputationIntensities
 	MemoryEstimate         uint64
+	MemoryIntensities      meter.MeteredMemoryIntensities
 	Err                    errors.CodedError
 
 	// Output only by script.
@@ -58,6 +60,7 @@ func (output *ProcedureOutput) PopulateEnvironmentValues(
 	output.MemoryEstimate = memoryUsed
 
 	output.ComputationIntensities = env.ComputationIntensities()
+	output.MemoryIntensities = env.MemoryIntensities()
 
 	// if tx failed this will only contain fee deduction events
 	output.Events = env.Events()
@@ -120,7 +123,26 @@ type VM interface {
 	GetAccount(Context, flow.Address, state.StorageSnapshot) (*flow.Account, error)
 }
 
+// SpeculativeVM is a VM which can run transactions speculatively, see
+// VirtualMachine.RunSpeculatively.
+type SpeculativeVM interface {
+	VM
+
+	RunSpeculatively(
+		Context,
+		*TransactionProcedure,
+		logical.Time,
+		state.StorageSnapshot,
+	) (
+		*state.ExecutionSnapshot,
+		ProcedureOutput,
+		derived.DerivedTransactionCommitter,
+		error,
+	)
+}
+
 var _ VM = (*VirtualMachine)(nil)
+var _ SpeculativeVM = (*VirtualMachine)(nil)
 
 // A VirtualMachine augments the Cadence runtime with Flow host functionality.
 type VirtualMachine struct {
@@ -182,6 +204,87 @@ func (vm *VirtualMachine) Run(
 			err)
 	}
 
+	executionSnapshot, output, err := vm.run(
+		ctx,
+		proc,
+		storageSnapshot,
+		derivedTxnData)
+	if err != nil {
+		return nil, ProcedureOutput{}, err
+	}
+
+	// Note: it is safe to skip committing derived data for non-normal
+	// transactions (i.e., bootstrap and script) since these do not invalidate
+	// derived data entries.
+	if proc.Type() == TransactionProcedureType {
+		// NOTE: It is not safe to ignore derivedTxnData' commit error for
+		// transactions that trigger derived data invalidation.
+		err = derivedTxnData.Commit()
+		if err != nil {
+			return nil, ProcedureOutput{}, err
+		}
+	}
+
+	return executionSnapshot, output, nil
+}
+
+// RunSpeculatively runs a transaction against a storage snapshot which may
+// be older than the transaction's execution time, i.e., which may not include
+// the changes of all the transactions preceding it in the block.
+//
+// Unlike Run, the transaction's derived data is not committed.  The caller is
+// responsible for checking that the transaction did not read any register
+// written by the transactions between snapshotTime and the transaction's
+// execution time, and for validating and committing the returned derived
+// data in execution time order.  If either check fails, the transaction must
+// be re-executed against an up-to-date snapshot with Run.
+func (vm *VirtualMachine) RunSpeculatively(
+	ctx Context,
+	proc *TransactionProcedure,
+	snapshotTime logical.Time,
+	storageSnapshot state.StorageSnapshot,
+) (
+	*state.ExecutionSnapshot,
+	ProcedureOutput,
+	derived.DerivedTransactionCommitter,
+	error,
+) {
+	if ctx.DerivedBlockData == nil {
+		return nil, ProcedureOutput{}, nil, fmt.Errorf(
+			"speculative execution requires derived block data")
+	}
+
+	derivedTxnData, err := ctx.DerivedBlockData.NewDerivedTransactionData(
+		snapshotTime,
+		proc.ExecutionTime())
+	if err != nil {
+		return nil, ProcedureOutput{}, nil, fmt.Errorf(
+			"error creating derived transaction data: %w",
+			err)
+	}
+
+	executionSnapshot, output, err := vm.run(
+		ctx,
+		proc,
+		storageSnapshot,
+		derivedTxnData)
+	if err != nil {
+		return nil, ProcedureOutput{}, nil, err
+	}
+
+	return executionSnapshot, output, derivedTxnData, nil
+}
+
+func (vm *VirtualMachine) run(
+	ctx Context,
+	proc Procedure,
+	storageSnapshot state.StorageSnapshot,
+	derivedTxnData derived.DerivedTransactionCommitter,
+) (
+	*state.ExecutionSnapshot,
+	ProcedureOutput,
+	error,
+) {
 	// TODO(patrick): initialize view inside TransactionState
 	nestedTxn := state.NewTransactionState(
 		delta.NewDeltaView(storageSnapshot),
@@ -196,23 +299,11 @@ func (vm *VirtualMachine) Run(
 	}
 
 	executor := proc.NewExecutor(ctx, txnState)
-	err = Run(executor)
+	err := Run(executor)
 	if err != nil {
 		return nil, ProcedureOutput{}, err
 	}
 
-	// Note: it is safe to skip committing derived data for non-normal
-	// transactions (i.e., bootstrap and script) since these do not invalidate
-	// derived data entries.
-	if proc.Type() == TransactionProcedureType {
-		// NOTE: It is not safe to ignore derivedTxnData' commit error for
-		// transactions that trigger derived data invalidation.
-		err = derivedTxnData.Commit()
-		if err != nil {
-			return nil, ProcedureOutput{}, err
-		}
-	}
-
 	executionSnapshot, err := txnState.FinalizeMainTransaction()
 	if err != nil {
 		return nil, ProcedureOutput{}, err
@@ -229,6 +320,137 @@ func (vm *VirtualMachine) GetAccount(
 ) (
 	*flow.Account,
 	error,
+) {
+	txnState, err := newQueryTransactionState(ctx, storageSnapshot)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"error creating derived transaction data for GetAccount: %w",
+			err)
+	}
+
+	env := environment.NewScriptEnv(
+		context.Background(),
+		ctx.TracerSpan,
+		ctx.EnvironmentParams,
+		txnState)
+	account, err := env.GetAccount(address)
+	if err != nil {
+		if errors.IsLedgerFailure(err) {
+			return nil, fmt.Errorf(
+				"cannot get account, this error usually happens if the "+
+					"reference block for this query is not set to a recent "+
+					"block: %w",
+				err)
+		}
+		return nil, fmt.Errorf("cannot get account: %w", err)
+	}
+	return account, nil
+}
+
+// GetAccountBalance returns the balance of an account by address or an error
+// if none exists. Unlike GetAccount, only the registers required to compute
+// the balance are read.
+func GetAccountBalance(
+	ctx Context,
+	address flow.Address,
+	storageSnapshot state.StorageSnapshot,
+) (
+	uint64,
+	error,
+) {
+	txnState, err := newQueryTransactionState(ctx, storageSnapshot)
+	if err != nil {
+		return 0, fmt.Errorf(
+			"error creating derived transaction data for GetAccountBalance: %w",
+			err)
+	}
+
+	err = checkAccountExists(txnState, address)
+	if err != nil {
+		return 0, fmt.Errorf("cannot get account balance: %w", err)
+	}
+
+	env := environment.NewScriptEnv(
+		context.Background(),
+		ctx.TracerSpan,
+		ctx.EnvironmentParams,
+		txnState)
+	balance, err := env.GetAccountBalance(common.Address(address))
+	if err != nil {
+		return 0, fmt.Errorf("cannot get account balance: %w", err)
+	}
+	return balance, nil
+}
+
+// GetAccountKeys returns the public keys of an account by address or an
+// error if none exists. Unlike GetAccount, only the registers storing the
+// keys are read.
+func GetAccountKeys(
+	ctx Context,
+	address flow.Address,
+	storageSnapshot state.StorageSnapshot,
+) (
+	[]flow.AccountPublicKey,
+	error,
+) {
+	txnState, err := newQueryTransactionState(ctx, storageSnapshot)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"error creating derived transaction data for GetAccountKeys: %w",
+			err)
+	}
+
+	err = checkAccountExists(txnState, address)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get account keys: %w", err)
+	}
+
+	keys, err := environment.NewAccounts(txnState).GetPublicKeys(address)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get account keys: %w", err)
+	}
+	return keys, nil
+}
+
+// GetAccountKey returns the public key with the given index of an account by
+// address, or an error if either the account or the key does not exist.
+// Unlike GetAccount, only the registers storing the key are read.
+func GetAccountKey(
+	ctx Context,
+	address flow.Address,
+	keyIndex uint64,
+	storageSnapshot state.StorageSnapshot,
+) (
+	*flow.AccountPublicKey,
+	error,
+) {
+	txnState, err := newQueryTransactionState(ctx, storageSnapshot)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"error creating derived transaction data for GetAccountKey: %w",
+			err)
+	}
+
+	err = checkAccountExists(txnState, address)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get account key: %w", err)
+	}
+
+	key, err := environment.NewAccounts(txnState).GetPublicKey(address, keyIndex)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get account key: %w", err)
+	}
+	return &key, nil
+}
+
+// newQueryTransactionState returns the transaction state used to read
+// account data outside of a procedure.
+func newQueryTransactionState(
+	ctx Context,
+	storageSnapshot state.StorageSnapshot,
+) (
+	*storage.SerialTransaction,
+	error,
 ) {
 	nestedTxn := state.NewTransactionState(
 		// TODO(patrick): initialize view inside TransactionState
@@ -249,31 +471,27 @@ func (vm *VirtualMachine) GetAccount(
 		logical.EndOfBlockExecutionTime,
 		logical.EndOfBlockExecutionTime)
 	if err != nil {
-		return nil, fmt.Errorf(
-			"error creating derived transaction data for GetAccount: %w",
-			err)
+		return nil, err
 	}
 
-	txnState := &storage.SerialTransaction{
+	return &storage.SerialTransaction{
 		NestedTransaction:           nestedTxn,
 		DerivedTransactionCommitter: derivedTxnData,
-	}
+	}, nil
+}
 
-	env := environment.NewScriptEnv(
-		context.Background(),
-		ctx.TracerSpan,
-		ctx.EnvironmentParams,
-		txnState)
-	account, err := env.GetAccount(address)
+// checkAccountExists returns an account not found error if the account does
+// not exist.
+func checkAccountExists(
+	txnState *storage.SerialTransaction,
+	address flow.Address,
+) error {
+	exists, err := environment.NewAccounts(txnState).Exists(address)
 	if err != nil {
-		if errors.IsLedgerFailure(err) {
-			return nil, fmt.Errorf(
-				"cannot get account, this error usually happens if the "+
-					"reference block for this query is not set to a recent "+
-					"block: %w",
-				err)
-		}
-		return nil, fmt.Errorf("cannot get account: %w", err)
+		return err
 	}
-	return account, nil
+	if !exists {
+		return errors.NewAccountNotFoundError(address)
+	}
+	return nil
 }
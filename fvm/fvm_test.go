@@ -2449,3 +2449,164 @@ func TestAttachments(t *testing.T) {
 		test(t, false)
 	})
 }
+
+func TestExecutionTrace(t *testing.T) {
+
+	t.Parallel()
+
+	chain, vm := createChainAndVm(flow.Testnet)
+
+	ctx := fvm.NewContext(fvm.WithChain(chain))
+	snapshotTree := testutil.RootBootstrappedLedger(vm, ctx)
+
+	trace := environment.NewExecutionTrace()
+	ctx = fvm.NewContextFromParent(
+		ctx,
+		fvm.WithReusableCadenceRuntimePool(
+			reusableRuntime.NewReusableCadenceRuntimePool(
+				0,
+				runtime.Config{
+					TracingEnabled: true,
+				})),
+		fvm.WithExecutionTraceCollector(trace),
+	)
+
+	txBody := flow.NewTransactionBody().
+		SetScript([]byte(`
+			pub fun double(_ x: Int): Int {
+				return x * 2
+			}
+
+			transaction {
+				prepare(signer: AuthAccount) {
+					signer.save(double(21), to: /storage/traced)
+					AuthAccount(payer: signer)
+				}
+			}
+		`)).
+		AddAuthorizer(chain.ServiceAddress())
+
+	err := testutil.SignTransactionAsServiceAccount(txBody, 0, chain)
+	require.NoError(t, err)
+
+	_, output, err := vm.Run(
+		ctx,
+		fvm.Transaction(txBody, 0),
+		snapshotTree)
+	require.NoError(t, err)
+	require.NoError(t, output.Err)
+
+	steps := map[environment.ExecutionTraceStepKind][]environment.ExecutionTraceStep{}
+	for _, step := range trace.Steps {
+		steps[step.Kind] = append(steps[step.Kind], step)
+	}
+
+	require.NotEmpty(t, steps[environment.ExecutionTraceRegisterRead])
+	require.NotEmpty(t, steps[environment.ExecutionTraceRegisterWrite])
+	require.NotEmpty(t, steps[environment.ExecutionTraceComputationMetered])
+
+	found := false
+	for _, step := range steps[environment.ExecutionTraceFunctionCall] {
+		if step.Function == "double" {
+			require.Equal(t, common.TransactionLocation(txBody.ID()).String(), step.Location)
+			found = true
+		}
+	}
+	require.True(t, found, "call to double was not traced")
+
+	found = false
+	for _, step := range steps[environment.ExecutionTraceEventEmitted] {
+		if step.EventType == string(flow.EventAccountCreated) {
+			require.NotEmpty(t, step.EventPayload)
+			found = true
+		}
+	}
+	require.True(t, found, "account created event was not traced")
+}
+
+// TestUnsafeRandomComputationKind tests that calls to unsafeRandom are metered, whether scripts
+// call it directly or through an imported contract, so that callers can tell whether the results
+// of scripts depend on random numbers.
+func TestUnsafeRandomComputationKind(t *testing.T) {
+	t.Run("direct and indirect calls",
+		newVMTest().
+			withContextOptions(fvm.WithBlockHeader(unittest.BlockHeaderFixture())).
+			run(
+				func(t *testing.T, vm fvm.VM, chain flow.Chain, ctx fvm.Context, snapshotTree storage.SnapshotTree) {
+					privateKeys, err := testutil.GenerateAccountPrivateKeys(1)
+					require.NoError(t, err)
+
+					snapshotTree, accounts, err := testutil.CreateAccounts(
+						vm,
+						snapshotTree,
+						privateKeys,
+						chain)
+					require.NoError(t, err)
+					account := accounts[0]
+
+					contract := `
+						pub contract Lottery {
+							pub fun draw(): UInt64 {
+								return unsafeRandom()
+							}
+
+							pub fun constant(): UInt64 {
+								return 42
+							}
+						}`
+
+					txBody := flow.NewTransactionBody().SetScript([]byte(fmt.Sprintf(`
+						transaction {
+							prepare(signer: AuthAccount, service: AuthAccount) {
+								signer.contracts.add(name: "Lottery", code: "%s".decodeHex())
+							}
+						}
+					`, hex.EncodeToString([]byte(contract))))).
+						AddAuthorizer(account).
+						AddAuthorizer(chain.ServiceAddress()).
+						SetPayer(chain.ServiceAddress()).
+						SetProposalKey(chain.ServiceAddress(), 0, 0)
+
+					_ = testutil.SignPayload(txBody, account, privateKeys[0])
+					_ = testutil.SignEnvelope(
+						txBody,
+						chain.ServiceAddress(),
+						unittest.ServiceAccountPrivateKey)
+
+					executionSnapshot, output, err := vm.Run(
+						ctx,
+						fvm.Transaction(txBody, 0),
+						snapshotTree)
+					require.NoError(t, err)
+					require.NoError(t, output.Err)
+					snapshotTree = snapshotTree.Append(executionSnapshot)
+
+					unsafeRandomCalls := func(code string) uint {
+						_, output, err := vm.Run(ctx, fvm.Script([]byte(code)), snapshotTree)
+						require.NoError(t, err)
+						require.NoError(t, output.Err)
+						return output.ComputationIntensities[environment.ComputationKindUnsafeRandom]
+					}
+
+					require.Equal(t, uint(1), unsafeRandomCalls(`
+						pub fun main(): UInt64 {
+							return unsafeRandom()
+						}`))
+
+					require.Equal(t, uint(2), unsafeRandomCalls(fmt.Sprintf(`
+						import Lottery from 0x%s
+
+						pub fun main(): UInt64 {
+							return Lottery.draw() ^ Lottery.draw()
+						}`, account.Hex())))
+
+					require.Equal(t, uint(0), unsafeRandomCalls(fmt.Sprintf(`
+						import Lottery from 0x%s
+
+						pub fun main(): UInt64 {
+							return Lottery.constant()
+						}`, account.Hex())))
+				},
+			),
+	)
+}
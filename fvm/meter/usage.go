@@ -0,0 +1,11 @@
+package meter
+
+// Usage is the computation and memory used by a procedure, and the limits it was executed with.
+type Usage struct {
+	ComputationUsed        uint64
+	ComputationLimit       uint64
+	ComputationIntensities MeteredComputationIntensities
+	MemoryEstimate         uint64
+	MemoryLimit            uint64
+	MemoryIntensities      MeteredMemoryIntensities
+}
@@ -1,9 +1,12 @@
 package proof
 
 import (
+	"fmt"
+
 	"github.com/onflow/flow-go/ledger"
 	"github.com/onflow/flow-go/ledger/common/bitutils"
 	"github.com/onflow/flow-go/ledger/common/hash"
+	"github.com/onflow/flow-go/ledger/common/pathfinder"
 )
 
 // TODO move this to proof itself
@@ -59,3 +62,66 @@ func VerifyTrieBatchProof(bp *ledger.TrieBatchProof, expectedState ledger.State)
 	}
 	return true
 }
+
+// VerifyEncodedBatchProof decodes the batch proof and verifies that it proves the given values of
+// the given keys at the expected state. Keys which are not set in the state must have empty values.
+// An error is returned if the proof is malformed, does not verify against the expected state, or
+// does not prove the values of all keys.
+func VerifyEncodedBatchProof(
+	encodedProof []byte,
+	keys []ledger.Key,
+	values []ledger.Value,
+	expectedState ledger.State,
+	pathFinderVersion uint8,
+) error {
+	if len(keys) != len(values) {
+		return fmt.Errorf("number of keys (%d) does not match number of values (%d)", len(keys), len(values))
+	}
+
+	bp, err := ledger.DecodeTrieBatchProof(encodedProof)
+	if err != nil {
+		return fmt.Errorf("failed to decode batch proof: %w", err)
+	}
+
+	if !VerifyTrieBatchProof(bp, expectedState) {
+		return fmt.Errorf("batch proof does not verify against state %s", expectedState)
+	}
+
+	// proofs are not ordered by key, so index them by path
+	proofs := make(map[ledger.Path]*ledger.TrieProof, len(bp.Proofs))
+	for _, p := range bp.Proofs {
+		proofs[p.Path] = p
+	}
+
+	for i, key := range keys {
+		path, err := pathfinder.KeyToPath(key, pathFinderVersion)
+		if err != nil {
+			return fmt.Errorf("failed to get path of key %s: %w", key.String(), err)
+		}
+
+		p, ok := proofs[path]
+		if !ok {
+			return fmt.Errorf("batch proof has no proof for key %s", key.String())
+		}
+
+		proven := p.Payload.Value()
+		if !proven.Equals(values[i]) {
+			return fmt.Errorf("value of key %s does not match the proof: got %x, proven %x", key.String(), values[i], proven)
+		}
+
+		// empty values are proven by empty payloads, which do not have a key
+		if len(proven) == 0 {
+			continue
+		}
+
+		provenKey, err := p.Payload.Key()
+		if err != nil {
+			return fmt.Errorf("failed to decode key of proof for key %s: %w", key.String(), err)
+		}
+		if !provenKey.Equals(&key) {
+			return fmt.Errorf("key of proof %s does not match key %s", provenKey.String(), key.String())
+		}
+	}
+
+	return nil
+}
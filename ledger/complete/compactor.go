@@ -57,6 +57,32 @@ type Compactor struct {
 	stopCh                               chan chan struct{}
 	trieUpdateCh                         <-chan *WALTrieUpdate
 	triggerCheckpointOnNextSegmentFinish *atomic.Bool // to trigger checkpoint manually
+
+	// fullCheckpointInterval is the number of checkpoints between full checkpoints, with delta
+	// checkpoints in between. Zero disables delta checkpoints.
+	fullCheckpointInterval uint
+	// lastCheckpoint and deltasSinceFull are only accessed by the checkpointing goroutine,
+	// which runs one at a time.
+	lastCheckpoint  *realWAL.CheckpointBase
+	deltasSinceFull uint
+}
+
+// CompactorOption configures a Compactor.
+type CompactorOption func(*Compactor)
+
+// WithDeltaCheckpoints enables delta checkpoints, which only contain the trie nodes created
+// since the previous checkpoint. Every fullCheckpointInterval-th checkpoint is a full
+// checkpoint, which the delta checkpoints until the next full checkpoint are based on.
+// The first checkpoint after startup is always a full checkpoint, since delta checkpoints are
+// computed from the tries of the previous checkpoint in memory.
+// Intervals smaller than 2 disable delta checkpoints.
+func WithDeltaCheckpoints(fullCheckpointInterval uint) CompactorOption {
+	return func(c *Compactor) {
+		if fullCheckpointInterval < 2 {
+			fullCheckpointInterval = 0
+		}
+		c.fullCheckpointInterval = fullCheckpointInterval
+	}
 }
 
 // NewCompactor creates new Compactor which writes WAL record and triggers
@@ -76,6 +102,7 @@ func NewCompactor(
 	checkpointDistance uint,
 	checkpointsToKeep uint,
 	triggerCheckpointOnNextSegmentFinish *atomic.Bool,
+	opts ...CompactorOption,
 ) (*Compactor, error) {
 	if checkpointDistance < 1 {
 		checkpointDistance = 1
@@ -102,7 +129,7 @@ func NewCompactor(
 	// Create trieQueue with initial values from ledger state.
 	trieQueue := realWAL.NewTrieQueueWithValues(checkpointCapacity, tries)
 
-	return &Compactor{
+	c := &Compactor{
 		checkpointer:                         checkpointer,
 		wal:                                  w,
 		trieQueue:                            trieQueue,
@@ -114,7 +141,13 @@ func NewCompactor(
 		checkpointDistance:                   checkpointDistance,
 		checkpointsToKeep:                    checkpointsToKeep,
 		triggerCheckpointOnNextSegmentFinish: triggerCheckpointOnNextSegmentFinish,
-	}, nil
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
 }
 
 // Subscribe subscribes observer to Compactor.
@@ -186,6 +219,15 @@ func (c *Compactor) run() {
 		lastCheckpointNum = -1
 	}
 
+	lastDeltaCheckpointNum, err := c.checkpointer.LatestDeltaCheckpoint()
+	if err != nil {
+		c.logger.Error().Err(err).Msg("compactor failed to get last delta checkpoint number")
+		lastDeltaCheckpointNum = -1
+	}
+	if lastDeltaCheckpointNum > lastCheckpointNum {
+		lastCheckpointNum = lastDeltaCheckpointNum
+	}
+
 	// Compute next checkpoint number.
 	// nextCheckpointNum is updated when checkpointing starts, fails to start, or fails.
 	// NOTE: next checkpoint number must >= active segment num.
@@ -288,7 +330,7 @@ Loop:
 // Since this function is only for checkpointing, Compactor isn't affected by returned error.
 func (c *Compactor) checkpoint(ctx context.Context, tries []*trie.MTrie, checkpointNum int) error {
 
-	err := createCheckpoint(c.checkpointer, c.logger, tries, checkpointNum)
+	err := c.createCheckpoint(tries, checkpointNum)
 	if err != nil {
 		return &createCheckpointError{num: checkpointNum, err: err}
 	}
@@ -322,6 +364,40 @@ func (c *Compactor) checkpoint(ctx context.Context, tries []*trie.MTrie, checkpo
 	return nil
 }
 
+// createCheckpoint creates a delta checkpoint based on the previous checkpoint if delta
+// checkpoints are enabled and the next full checkpoint isn't due yet, otherwise a full
+// checkpoint, which folds the preceding delta checkpoints.
+// Errors indicate that checkpoint file can't be created.
+func (c *Compactor) createCheckpoint(tries []*trie.MTrie, checkpointNum int) error {
+	isDelta := c.fullCheckpointInterval > 0 &&
+		c.lastCheckpoint != nil &&
+		c.deltasSinceFull+1 < c.fullCheckpointInterval
+
+	var err error
+	if isDelta {
+		err = createDeltaCheckpoint(c.checkpointer, c.logger, *c.lastCheckpoint, tries, checkpointNum)
+	} else {
+		err = createCheckpoint(c.checkpointer, c.logger, tries, checkpointNum)
+	}
+	if err != nil {
+		return err
+	}
+
+	if c.fullCheckpointInterval == 0 {
+		return nil
+	}
+
+	base := realWAL.NewCheckpointBase(checkpointNum, isDelta, tries)
+	c.lastCheckpoint = &base
+	if isDelta {
+		c.deltasSinceFull++
+	} else {
+		c.deltasSinceFull = 0
+	}
+
+	return nil
+}
+
 // createCheckpoint creates checkpoint with given checkpointNum and tries.
 // Errors indicate that checkpoint file can't be created.
 // Caller should handle returned errors by retrying checkpointing when appropriate.
@@ -343,7 +419,37 @@ func createCheckpoint(checkpointer *realWAL.Checkpointer, logger zerolog.Logger,
 	return nil
 }
 
+// createDeltaCheckpoint creates delta checkpoint with given checkpointNum and tries, based on
+// the given checkpoint.
+// Errors indicate that checkpoint file can't be created.
+// Caller should handle returned errors by retrying checkpointing when appropriate.
+func createDeltaCheckpoint(
+	checkpointer *realWAL.Checkpointer,
+	logger zerolog.Logger,
+	base realWAL.CheckpointBase,
+	tries []*trie.MTrie,
+	checkpointNum int,
+) error {
+
+	logger.Info().Msgf("serializing delta checkpoint %d with %v tries, based on checkpoint %d", checkpointNum, len(tries), base.Num)
+
+	startTime := time.Now()
+
+	fileName := realWAL.DeltaNumberToFilename(checkpointNum)
+	err := realWAL.StoreDeltaCheckpoint(base, tries, checkpointer.Dir(), fileName, &logger)
+	if err != nil {
+		return fmt.Errorf("error serializing delta checkpoint (%d): %w", checkpointNum, err)
+	}
+
+	duration := time.Since(startTime)
+	logger.Info().Float64("total_time_s", duration.Seconds()).Msgf("created delta checkpoint %d", checkpointNum)
+
+	return nil
+}
+
 // cleanupCheckpoints deletes prior checkpoint files if needed.
+// Delta checkpoints older than the oldest kept full checkpoint are deleted as well, since
+// the full checkpoints after them supersede them.
 // Since the function is side-effect free, all failures are simply a no-op.
 func cleanupCheckpoints(checkpointer *realWAL.Checkpointer, checkpointsToKeep int) error {
 	// Don't list checkpoints if we keep them all
@@ -364,6 +470,24 @@ func cleanupCheckpoints(checkpointer *realWAL.Checkpointer, checkpointsToKeep in
 				return fmt.Errorf("cannot remove checkpoint %d: %w", checkpoint, err)
 			}
 		}
+		checkpoints = checkpoints[len(checkpoints)-int(checkpointsToKeep):]
+	}
+
+	if len(checkpoints) == 0 {
+		return nil
+	}
+	deltaCheckpoints, err := checkpointer.DeltaCheckpoints()
+	if err != nil {
+		return fmt.Errorf("cannot list delta checkpoints: %w", err)
+	}
+	for _, checkpoint := range deltaCheckpoints {
+		if checkpoint >= checkpoints[0] {
+			break
+		}
+		err := checkpointer.RemoveDeltaCheckpoint(checkpoint)
+		if err != nil {
+			return fmt.Errorf("cannot remove delta checkpoint %d: %w", checkpoint, err)
+		}
 	}
 	return nil
 }
@@ -523,6 +523,138 @@ func TestCompactorAccuracy(t *testing.T) {
 	})
 }
 
+// TestCompactorDeltaCheckpoints tests that the compactor creates delta checkpoints between full
+// checkpoints, that delta checkpoints match the tries replayed from segments, and that the
+// ledger state can be rebuilt from the chain of checkpoints and the segments after it.
+func TestCompactorDeltaCheckpoints(t *testing.T) {
+
+	const (
+		numInsPerStep          = 2
+		pathByteSize           = 32
+		minPayloadByteSize     = 2<<11 - 256 // 3840 bytes
+		maxPayloadByteSize     = 2 << 11     // 4096 bytes
+		size                   = 20
+		checkpointDistance     = 2
+		checkpointsToKeep      = 1
+		fullCheckpointInterval = 3
+		forestCapacity         = 500
+	)
+
+	metricsCollector := &metrics.NoopCollector{}
+
+	unittest.RunWithTempDir(t, func(dir string) {
+
+		wal, err := realWAL.NewDiskWAL(unittest.Logger(), nil, metrics.NewNoopCollector(), dir, forestCapacity, pathByteSize, 32*1024)
+		require.NoError(t, err)
+
+		l, err := NewLedger(wal, forestCapacity, metricsCollector, zerolog.Logger{}, DefaultPathFinderVersion)
+		require.NoError(t, err)
+
+		compactor, err := NewCompactor(l, wal, unittest.Logger(), forestCapacity, checkpointDistance, checkpointsToKeep, atomic.NewBool(false),
+			WithDeltaCheckpoints(fullCheckpointInterval))
+		require.NoError(t, err)
+
+		co := CompactorObserver{fromBound: size/2 - 1, done: make(chan struct{})}
+		compactor.Subscribe(&co)
+
+		// Run Compactor in background.
+		<-compactor.Ready()
+
+		rootHash := trie.EmptyTrieRootHash()
+		var lastUpdate *ledger.Update
+
+		// size+2 is used to ensure that size/2 segments are finalized.
+		for i := 0; i < size+2; i++ {
+			// slow down updating the ledger, because running too fast would cause the previous checkpoint
+			// to not finish and get delayed
+			time.Sleep(LedgerUpdateDelay)
+
+			payloads := testutils.RandomPayloads(numInsPerStep, minPayloadByteSize, maxPayloadByteSize)
+
+			keys := make([]ledger.Key, len(payloads))
+			values := make([]ledger.Value, len(payloads))
+			for i, p := range payloads {
+				k, err := p.Key()
+				require.NoError(t, err)
+				keys[i] = k
+				values[i] = p.Value()
+			}
+
+			update, err := ledger.NewUpdate(ledger.State(rootHash), keys, values)
+			require.NoError(t, err)
+
+			newState, _, err := l.Set(update)
+			require.NoError(t, err)
+
+			rootHash = ledger.RootHash(newState)
+			lastUpdate = update
+		}
+
+		// wait for the bound-checking observer to confirm checkpoints have been made
+		select {
+		case <-co.done:
+			// continue
+		case <-time.After(60 * time.Second):
+			assert.FailNow(t, "timed out")
+		}
+
+		// Shutdown ledger and compactor
+		<-l.Done()
+		<-compactor.Done()
+
+		checkpointer, err := wal.NewCheckpointer()
+		require.NoError(t, err)
+
+		// older delta checkpoints are removed along with the full checkpoints they are based on
+		fulls, err := checkpointer.Checkpoints()
+		require.NoError(t, err)
+		require.Len(t, fulls, checkpointsToKeep)
+
+		deltas, err := checkpointer.DeltaCheckpoints()
+		require.NoError(t, err)
+		require.NotEmpty(t, deltas)
+		require.Less(t, len(deltas), fullCheckpointInterval)
+
+		for _, n := range deltas {
+			require.Greater(t, n, fulls[0])
+
+			triesFromLoadingCheckpoint, err := checkpointer.LoadDeltaCheckpoint(n)
+			require.NoError(t, err)
+
+			triesFromReplayingSegments, err := triesUpToSegment(dir, n, len(triesFromLoadingCheckpoint))
+			require.NoError(t, err)
+
+			require.Equal(t, len(triesFromReplayingSegments), len(triesFromLoadingCheckpoint))
+			for i := 0; i < len(triesFromReplayingSegments); i++ {
+				require.Equal(t, triesFromReplayingSegments[i].RootHash(), triesFromLoadingCheckpoint[i].RootHash())
+			}
+		}
+
+		// remove the segments covered by the latest delta checkpoint, so that the ledger state
+		// can only be rebuilt from the checkpoints and the remaining segments
+		latestDelta := deltas[len(deltas)-1]
+		for i := 0; i <= latestDelta; i++ {
+			require.NoError(t, os.Remove(path.Join(dir, realWAL.NumberToFilenamePart(i))))
+		}
+
+		wal2, err := realWAL.NewDiskWAL(unittest.Logger(), nil, metrics.NewNoopCollector(), dir, forestCapacity, pathByteSize, 32*1024)
+		require.NoError(t, err)
+
+		l2, err := NewLedger(wal2, forestCapacity, metricsCollector, zerolog.Logger{}, DefaultPathFinderVersion)
+		require.NoError(t, err)
+		defer func() { <-wal2.Done() }()
+
+		require.True(t, l2.HasState(ledger.State(rootHash)))
+
+		q, err := ledger.NewQuery(ledger.State(rootHash), lastUpdate.Keys())
+		require.NoError(t, err)
+
+		values, err := l2.Get(q)
+		require.NoError(t, err)
+		require.Equal(t, lastUpdate.Values(), values)
+	})
+}
+
 // TestCompactorTriggeredByAdminTool tests that the compactor will listen to the signal from admin tool
 // to trigger checkpoint when current segment file is finished.
 func TestCompactorTriggeredByAdminTool(t *testing.T) {
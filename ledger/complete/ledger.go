@@ -194,6 +194,29 @@ func (l *Ledger) Get(query *ledger.Query) (values []ledger.Value, err error) {
 	return values, err
 }
 
+// Iterate returns a page of the registers whose keys start with the query prefix at the given
+// state, in path order. The NextStartAfter path of the result can be used to query the next page.
+// CAUTION: the registers with a prefix are not stored next to each other, so iterating them may
+// require visiting every register of the state.
+func (l *Ledger) Iterate(query *ledger.IterateQuery) (*ledger.IterateResult, error) {
+	start := time.Now()
+	trieIterate := &ledger.TrieIterate{
+		RootHash:   ledger.RootHash(query.State()),
+		Prefix:     query.Prefix(),
+		StartAfter: query.StartAfter(),
+		Limit:      query.Limit(),
+	}
+	result, err := l.forest.Iterate(trieIterate)
+	if err != nil {
+		return nil, err
+	}
+
+	l.metrics.ReadValuesNumber(uint64(len(result.Values)))
+	l.metrics.ReadDuration(time.Since(start))
+
+	return result, nil
+}
+
 // Set updates the ledger given an update.
 // It returns the state after update and errors (if any)
 func (l *Ledger) Set(update *ledger.Update) (newState ledger.State, trieUpdate *ledger.TrieUpdate, err error) {
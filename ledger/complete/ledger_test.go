@@ -148,6 +148,108 @@ func TestLedger_Get(t *testing.T) {
 	})
 }
 
+// TestLedger_Iterate tests that all registers with a key prefix are iterated in pages, and that
+// other registers are skipped.
+func TestLedger_Iterate(t *testing.T) {
+
+	wal := &fixtures.NoopWAL{}
+
+	led, err := complete.NewLedger(wal, 100, &metrics.NoopCollector{}, zerolog.Logger{}, complete.DefaultPathFinderVersion)
+	require.NoError(t, err)
+
+	compactor := fixtures.NewNoopCompactor(led)
+	<-compactor.Ready()
+	defer func() {
+		<-led.Done()
+		<-compactor.Done()
+	}()
+
+	owner1 := []byte("owner1")
+	owner2 := []byte("owner2")
+
+	var keys []ledger.Key
+	var values []ledger.Value
+	expected := make(map[string]ledger.Value)
+	for i := 0; i < 20; i++ {
+		for _, owner := range [][]byte{owner1, owner2} {
+			key := ledger.NewKey([]ledger.KeyPart{
+				ledger.NewKeyPart(0, owner),
+				ledger.NewKeyPart(2, []byte(fmt.Sprintf("key%d", i))),
+			})
+			value := ledger.Value(fmt.Sprintf("%s-%d", owner, i))
+			keys = append(keys, key)
+			values = append(values, value)
+			if bytes.Equal(owner, owner1) {
+				expected[key.String()] = value
+			}
+		}
+	}
+
+	update, err := ledger.NewUpdate(led.InitialState(), keys, values)
+	require.NoError(t, err)
+	state, _, err := led.Set(update)
+	require.NoError(t, err)
+
+	prefix := ledger.NewKey([]ledger.KeyPart{ledger.NewKeyPart(0, owner1)})
+
+	t.Run("pages", func(t *testing.T) {
+		actual := make(map[string]ledger.Value)
+		var startAfter *ledger.Path
+		pages := 0
+		for {
+			query, err := ledger.NewIterateQuery(state, prefix, startAfter, 7)
+			require.NoError(t, err)
+
+			result, err := led.Iterate(query)
+			require.NoError(t, err)
+			require.Len(t, result.Values, len(result.Keys))
+			require.LessOrEqual(t, len(result.Keys), 7)
+			pages++
+
+			for i, key := range result.Keys {
+				require.True(t, key.HasPrefix(&prefix))
+				actual[key.String()] = result.Values[i]
+			}
+
+			if result.NextStartAfter == nil {
+				break
+			}
+			startAfter = result.NextStartAfter
+		}
+
+		assert.Equal(t, expected, actual)
+		assert.GreaterOrEqual(t, pages, 3)
+	})
+
+	t.Run("key part value prefix", func(t *testing.T) {
+		prefix := ledger.NewKey([]ledger.KeyPart{
+			ledger.NewKeyPart(0, owner2),
+			ledger.NewKeyPart(2, []byte("key1")),
+		})
+		query, err := ledger.NewIterateQuery(state, prefix, nil, 100)
+		require.NoError(t, err)
+
+		result, err := led.Iterate(query)
+		require.NoError(t, err)
+		// key1 and key10 to key19
+		assert.Len(t, result.Keys, 11)
+		assert.Nil(t, result.NextStartAfter)
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		_, err := ledger.NewIterateQuery(state, prefix, nil, 0)
+		require.Error(t, err)
+	})
+
+	t.Run("unknown state", func(t *testing.T) {
+		query, err := ledger.NewIterateQuery(ledger.State(unittest.StateCommitmentFixture()), prefix, nil, 10)
+		require.NoError(t, err)
+
+		_, err = led.Iterate(query)
+		require.Error(t, err)
+	})
+}
+
 // TestLedger_GetSingleValue tests reading value from a single path.
 func TestLedger_GetSingleValue(t *testing.T) {
 
@@ -489,6 +591,77 @@ func TestLedger_Proof(t *testing.T) {
 	})
 }
 
+func TestLedger_VerifyEncodedBatchProof(t *testing.T) {
+	wal := &fixtures.NoopWAL{}
+	led, err := complete.NewLedger(wal, 100, &metrics.NoopCollector{}, zerolog.Logger{}, complete.DefaultPathFinderVersion)
+	require.NoError(t, err)
+
+	compactor := fixtures.NewNoopCompactor(led)
+	<-compactor.Ready()
+	defer func() {
+		<-led.Done()
+		<-compactor.Done()
+	}()
+
+	u := testutils.UpdateFixture()
+	u.SetState(led.InitialState())
+
+	state, _, err := led.Set(u)
+	require.NoError(t, err)
+
+	missing := ledger.NewKey([]ledger.KeyPart{ledger.NewKeyPart(0, []byte("missing"))})
+	keys := append(u.Keys(), missing)
+
+	q, err := ledger.NewQuery(state, keys)
+	require.NoError(t, err)
+
+	values, err := led.Get(q)
+	require.NoError(t, err)
+	require.Len(t, values, 3)
+	require.Empty(t, values[2])
+
+	encoded, err := led.Prove(q)
+	require.NoError(t, err)
+
+	t.Run("valid proof", func(t *testing.T) {
+		err := proof.VerifyEncodedBatchProof(encoded, keys, values, state, complete.DefaultPathFinderVersion)
+		require.NoError(t, err)
+	})
+
+	t.Run("subset of keys", func(t *testing.T) {
+		err := proof.VerifyEncodedBatchProof(encoded, keys[1:], values[1:], state, complete.DefaultPathFinderVersion)
+		require.NoError(t, err)
+	})
+
+	t.Run("wrong value", func(t *testing.T) {
+		wrong := []ledger.Value{values[0], ledger.Value("wrong"), values[2]}
+		err := proof.VerifyEncodedBatchProof(encoded, keys, wrong, state, complete.DefaultPathFinderVersion)
+		require.Error(t, err)
+	})
+
+	t.Run("value for missing key", func(t *testing.T) {
+		wrong := []ledger.Value{values[0], values[1], ledger.Value("wrong")}
+		err := proof.VerifyEncodedBatchProof(encoded, keys, wrong, state, complete.DefaultPathFinderVersion)
+		require.Error(t, err)
+	})
+
+	t.Run("key not in proof", func(t *testing.T) {
+		other := ledger.NewKey([]ledger.KeyPart{ledger.NewKeyPart(0, []byte("other"))})
+		err := proof.VerifyEncodedBatchProof(encoded, []ledger.Key{other}, []ledger.Value{nil}, state, complete.DefaultPathFinderVersion)
+		require.Error(t, err)
+	})
+
+	t.Run("wrong state", func(t *testing.T) {
+		err := proof.VerifyEncodedBatchProof(encoded, keys, values, led.InitialState(), complete.DefaultPathFinderVersion)
+		require.Error(t, err)
+	})
+
+	t.Run("malformed proof", func(t *testing.T) {
+		err := proof.VerifyEncodedBatchProof(encoded[:len(encoded)/2], keys, values, state, complete.DefaultPathFinderVersion)
+		require.Error(t, err)
+	})
+}
+
 func Test_WAL(t *testing.T) {
 	const (
 		numInsPerStep      = 2
@@ -308,52 +308,70 @@ func EncodeTrie(trie *trie.MTrie, rootIndex uint64, scratch []byte) []byte {
 // ReadTrie reconstructs a trie from data read from reader.
 func ReadTrie(reader io.Reader, scratch []byte, getNode func(nodeIndex uint64) (*node.Node, error)) (*trie.MTrie, error) {
 
+	rootIndex, regCount, regSize, readRootHash, err := ReadTrieRoot(reader, scratch)
+	if err != nil {
+		return nil, err
+	}
+
+	rootNode, err := getNode(rootIndex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find root node of serialized trie: %w", err)
+	}
+
+	mtrie, err := trie.NewMTrie(rootNode, regCount, regSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore serialized trie: %w", err)
+	}
+
+	rootHash := mtrie.RootHash()
+	if !rootHash.Equals(readRootHash) {
+		return nil, fmt.Errorf("failed to restore serialized trie: roothash doesn't match")
+	}
+
+	return mtrie, nil
+}
+
+// ReadTrieRoot reads a serialized trie from reader without resolving its root node.
+// It returns the root node index, the allocated reg count and size, and the root hash.
+func ReadTrieRoot(reader io.Reader, scratch []byte) (
+	rootIndex uint64,
+	regCount uint64,
+	regSize uint64,
+	rootHash ledger.RootHash,
+	err error,
+) {
+
 	if len(scratch) < encodedTrieSize {
 		scratch = make([]byte, encodedTrieSize)
 	}
 
 	// Read encoded trie
-	_, err := io.ReadFull(reader, scratch[:encodedTrieSize])
+	_, err = io.ReadFull(reader, scratch[:encodedTrieSize])
 	if err != nil {
-		return nil, fmt.Errorf("failed to read serialized trie: %w", err)
+		return 0, 0, 0, ledger.RootHash{}, fmt.Errorf("failed to read serialized trie: %w", err)
 	}
 
 	pos := 0
 
 	// Decode root node index
-	rootIndex := binary.BigEndian.Uint64(scratch)
+	rootIndex = binary.BigEndian.Uint64(scratch)
 	pos += encNodeIndexSize
 
 	// Decode trie reg count (8 bytes)
-	regCount := binary.BigEndian.Uint64(scratch[pos:])
+	regCount = binary.BigEndian.Uint64(scratch[pos:])
 	pos += encRegCountSize
 
 	// Decode trie reg size (8 bytes)
-	regSize := binary.BigEndian.Uint64(scratch[pos:])
+	regSize = binary.BigEndian.Uint64(scratch[pos:])
 	pos += encRegSizeSize
 
 	// Decode root node hash
 	readRootHash, err := hash.ToHash(scratch[pos : pos+encHashSize])
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode hash of serialized trie: %w", err)
-	}
-
-	rootNode, err := getNode(rootIndex)
-	if err != nil {
-		return nil, fmt.Errorf("failed to find root node of serialized trie: %w", err)
-	}
-
-	mtrie, err := trie.NewMTrie(rootNode, regCount, regSize)
-	if err != nil {
-		return nil, fmt.Errorf("failed to restore serialized trie: %w", err)
+		return 0, 0, 0, ledger.RootHash{}, fmt.Errorf("failed to decode hash of serialized trie: %w", err)
 	}
 
-	rootHash := mtrie.RootHash()
-	if !rootHash.Equals(ledger.RootHash(readRootHash)) {
-		return nil, fmt.Errorf("failed to restore serialized trie: roothash doesn't match")
-	}
-
-	return mtrie, nil
+	return rootIndex, regCount, regSize, ledger.RootHash(readRootHash), nil
 }
 
 // readPayloadFromReader reads and decodes payload from reader.
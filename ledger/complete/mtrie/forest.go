@@ -168,6 +168,53 @@ func (f *Forest) Read(r *ledger.TrieRead) ([]ledger.Value, error) {
 	return orderedValues, nil
 }
 
+// Iterate returns the keys and values of at most r.Limit registers whose keys start with r.Prefix,
+// in ascending path order, starting after r.StartAfter. Since paths are hashes of the keys, the
+// registers with a prefix are spread over the whole trie, and finding them requires visiting all
+// leaves after r.StartAfter in the worst case.
+func (f *Forest) Iterate(r *ledger.TrieIterate) (*ledger.IterateResult, error) {
+	// lookup the trie by rootHash
+	trie, err := f.GetTrie(r.RootHash)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ledger.IterateResult{}
+	var iterErr error
+	totalPayloadSize := 0
+	trie.IterateLeaves(r.StartAfter, func(path ledger.Path, payload *ledger.Payload) bool {
+		key, err := payload.Key()
+		if err != nil {
+			iterErr = fmt.Errorf("could not decode key of payload at path %s: %w", path, err)
+			return false
+		}
+		if !key.HasPrefix(&r.Prefix) {
+			return true
+		}
+
+		// the decoded key shares its data with the payload in the trie
+		result.Keys = append(result.Keys, key.DeepCopy())
+		result.Values = append(result.Values, payload.Value().DeepCopy())
+		totalPayloadSize += payload.Size()
+
+		if len(result.Keys) < r.Limit {
+			return true
+		}
+
+		// the page is full, continue after this register
+		nextStartAfter := path
+		result.NextStartAfter = &nextStartAfter
+		return false
+	})
+	if iterErr != nil {
+		return nil, iterErr
+	}
+
+	f.metrics.ReadValuesSize(uint64(totalPayloadSize))
+
+	return result, nil
+}
+
 // Update creates a new trie by updating Values for registers in the parent trie,
 // adds new trie to forest, and returns rootHash and error (if any).
 // In case there are multiple updates to the same register, Update will persist
@@ -1,6 +1,7 @@
 package trie
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -752,6 +753,43 @@ func (mt *MTrie) AllPayloads() []ledger.Payload {
 	return mt.root.AllPayloads()
 }
 
+// IterateLeaves calls fn for the path and payload of every leaf of the trie in ascending path
+// order, starting after the given path, or at the first leaf if startAfter is nil. Subtries that
+// only contain paths before startAfter are skipped. The iteration stops when fn returns false.
+func (mt *MTrie) IterateLeaves(startAfter *ledger.Path, fn func(path ledger.Path, payload *ledger.Payload) bool) {
+	iterateLeaves(mt.root, startAfter, fn)
+}
+
+// iterateLeaves iterates the leaves of the subtrie with `head` as root node, and returns false if
+// the iteration was stopped by fn.
+// CAUTION: unchecked requirement: if startAfter is not nil, the path from the trie root to the
+// `head` node must be a prefix of startAfter.
+func iterateLeaves(head *node.Node, startAfter *ledger.Path, fn func(path ledger.Path, payload *ledger.Payload) bool) bool {
+	if head == nil {
+		return true
+	}
+
+	if head.IsLeaf() {
+		path := *head.Path()
+		if startAfter != nil && bytes.Compare(path[:], startAfter[:]) <= 0 {
+			return true
+		}
+		return fn(path, head.Payload())
+	}
+
+	if startAfter == nil {
+		return iterateLeaves(head.LeftChild(), nil, fn) && iterateLeaves(head.RightChild(), nil, fn)
+	}
+
+	// only the subtrie on the path to startAfter can contain paths before it. All paths in the
+	// right subtrie are after the paths in the left subtrie.
+	depth := ledger.NodeMaxHeight - head.Height() // distance to the tree root
+	if bitutils.ReadBit(startAfter[:], depth) == 1 {
+		return iterateLeaves(head.RightChild(), startAfter, fn)
+	}
+	return iterateLeaves(head.LeftChild(), startAfter, fn) && iterateLeaves(head.RightChild(), nil, fn)
+}
+
 // IsAValidTrie verifies the content of the trie for potential issues
 func (mt *MTrie) IsAValidTrie() bool {
 	// TODO add checks on the health of node max height ...
@@ -1197,3 +1197,68 @@ func TestReadSinglePayload(t *testing.T) {
 		}
 	})
 }
+
+// TestIterateLeaves tests that leaves are iterated in ascending path order, starting after the
+// given path, and that the iteration stops when requested.
+func TestIterateLeaves(t *testing.T) {
+	emptyTrie := trie.NewEmptyMTrie()
+
+	t.Run("empty trie", func(t *testing.T) {
+		emptyTrie.IterateLeaves(nil, func(ledger.Path, *ledger.Payload) bool {
+			require.Fail(t, "unexpected leaf")
+			return true
+		})
+	})
+
+	// paths are spread over the first two bytes, in reverse order to check that leaves are sorted
+	paths := make([]ledger.Path, 100)
+	for i := range paths {
+		paths[i] = testutils.PathByUint16(uint16(len(paths)-i) * 600)
+	}
+	payloads := testutils.RandomPayloads(len(paths), 1, 100)
+	values := make([]ledger.Payload, len(payloads))
+	for i, payload := range payloads {
+		values[i] = *payload
+	}
+
+	newTrie, _, err := trie.NewTrieWithUpdatedRegisters(emptyTrie, paths, values, true)
+	require.NoError(t, err)
+
+	sorted := make([]ledger.Path, len(paths))
+	copy(sorted, paths)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i][:], sorted[j][:]) < 0
+	})
+
+	collect := func(startAfter *ledger.Path, limit int) []ledger.Path {
+		visited := []ledger.Path{}
+		newTrie.IterateLeaves(startAfter, func(path ledger.Path, payload *ledger.Payload) bool {
+			require.Equal(t, newTrie.ReadSinglePayload(path), payload)
+			visited = append(visited, path)
+			return len(visited) < limit
+		})
+		return visited
+	}
+
+	t.Run("all leaves", func(t *testing.T) {
+		require.Equal(t, sorted, collect(nil, len(paths)+1))
+	})
+
+	t.Run("start after existing path", func(t *testing.T) {
+		for _, i := range []int{0, 1, 42, len(sorted) - 1} {
+			require.Equal(t, sorted[i+1:], collect(&sorted[i], len(paths)+1))
+		}
+	})
+
+	t.Run("start after missing path", func(t *testing.T) {
+		// the path directly after an existing path is not in the trie
+		startAfter := sorted[41]
+		startAfter[ledger.PathLen-1] = 1
+		require.Equal(t, sorted[42:], collect(&startAfter, len(paths)+1))
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		require.Equal(t, sorted[:10], collect(nil, 10))
+		require.Equal(t, sorted[11:21], collect(&sorted[10], 10))
+	})
+}
@@ -0,0 +1,553 @@
+package wal
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/bitutils"
+	"github.com/onflow/flow-go/ledger/common/hash"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/flattener"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/node"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+)
+
+const deltaCheckpointFilenamePrefix = "checkpoint.delta."
+
+const MagicBytesCheckpointDelta uint16 = 0x2138
+
+// VersionDeltaV1 is the first version of the delta checkpoint format.
+const VersionDeltaV1 uint16 = 0x01
+
+const (
+	encBaseIsDeltaSize    = 1
+	encBaseNumSize        = 8
+	encRefCountSize       = 8
+	encRefTrieIndexSize   = 2
+	encRefHeightSize      = 2
+	encRefSize            = encRefTrieIndexSize + encRefHeightSize + ledger.PathLen
+	encDeltaBaseInfoSize  = encBaseIsDeltaSize + encBaseNumSize + encTrieCountSize
+	encDeltaRootHashSize  = hash.HashLen
+	maxDeltaCheckpointRef = 1<<16 - 1
+)
+
+// CheckpointBase is the checkpoint a delta checkpoint is based on: either a full checkpoint,
+// or another delta checkpoint. RootHashes are the root hashes of the tries of the base
+// checkpoint, in the order they were stored in, and LatestTrie is the last of them, which the
+// new tries are compared with. The other base tries are not kept, so that the nodes replaced
+// in them are not retained in memory until the next checkpoint.
+type CheckpointBase struct {
+	Num        int
+	IsDelta    bool
+	RootHashes []ledger.RootHash
+	LatestTrie *trie.MTrie
+}
+
+// NewCheckpointBase returns the base for delta checkpoints of the checkpoint with the given
+// number, which stored the given tries.
+func NewCheckpointBase(num int, isDelta bool, tries []*trie.MTrie) CheckpointBase {
+	base := CheckpointBase{
+		Num:        num,
+		IsDelta:    isDelta,
+		RootHashes: make([]ledger.RootHash, len(tries)),
+	}
+	for i, t := range tries {
+		base.RootHashes[i] = t.RootHash()
+	}
+	if len(tries) > 0 {
+		base.LatestTrie = tries[len(tries)-1]
+	}
+	return base
+}
+
+// nodeRef references a node of a base trie by its position: the node at the given height on
+// the given path.
+type nodeRef struct {
+	trieIndex uint16
+	height    uint16
+	path      ledger.Path
+}
+
+// deltaBuilder collects the nodes of a delta checkpoint: references to the nodes shared with
+// the base tries, and the nodes created since the base checkpoint in Descendents-First order.
+type deltaBuilder struct {
+	refs     []nodeRef
+	newNodes []*node.Node
+	refIdx   map[*node.Node]int
+	newIdx   map[*node.Node]int
+}
+
+// DeltaNumberToFilename returns the file name of the delta checkpoint with the given number.
+func DeltaNumberToFilename(n int) string {
+	return fmt.Sprintf("%s%s", deltaCheckpointFilenamePrefix, NumberToFilenamePart(n))
+}
+
+// DeltaCheckpoints returns the numbers of the delta checkpoint files in the given directory in
+// asc order.
+func DeltaCheckpoints(dir string) ([]int, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot list directory [%s] content: %w", dir, err)
+	}
+
+	list := make([]int, 0)
+	for _, fn := range files {
+		fname := fn.Name()
+		if !strings.HasPrefix(fname, deltaCheckpointFilenamePrefix) {
+			continue
+		}
+		k, err := strconv.Atoi(fname[len(deltaCheckpointFilenamePrefix):])
+		if err != nil {
+			continue
+		}
+		list = append(list, k)
+	}
+
+	sort.Ints(list)
+
+	return list, nil
+}
+
+// DeltaCheckpoints returns the numbers of the delta checkpoint files in asc order.
+func (c *Checkpointer) DeltaCheckpoints() ([]int, error) {
+	return DeltaCheckpoints(c.dir)
+}
+
+// LatestDeltaCheckpoint returns number of latest delta checkpoint or -1 if there are no delta
+// checkpoints.
+func (c *Checkpointer) LatestDeltaCheckpoint() (int, error) {
+	list, err := c.DeltaCheckpoints()
+	if err != nil {
+		return -1, err
+	}
+	if len(list) == 0 {
+		return -1, nil
+	}
+	return list[len(list)-1], nil
+}
+
+// LoadDeltaCheckpoint loads the tries of the delta checkpoint with the given number, along
+// with the chain of checkpoints it is based on.
+func (c *Checkpointer) LoadDeltaCheckpoint(checkpoint int) ([]*trie.MTrie, error) {
+	return LoadDeltaCheckpoint(c.dir, checkpoint, &c.wal.log)
+}
+
+func (c *Checkpointer) RemoveDeltaCheckpoint(checkpoint int) error {
+	return deleteCheckpointFiles(c.dir, DeltaNumberToFilename(checkpoint))
+}
+
+// StoreDeltaCheckpoint writes the given tries to a delta checkpoint file, which only contains
+// the trie nodes that are not part of the base checkpoint's tries. The file ends with a CRC32
+// file checksum for integrity check.
+//
+// A delta checkpoint file consists of:
+//   - the base checkpoint number and type, and the root hashes of the base tries.
+//   - a list of references to nodes of the base tries, by trie index, path and height.
+//   - a list of encoded nodes, where references to other nodes are by index into the
+//     concatenation of the referenced nodes and the encoded nodes (index 0 meaning nil).
+//   - a list of encoded tries, each referencing their respective root node by index.
+//
+// Tries that are part of the base are stored as a reference to their root node. Other tries
+// are compared with the latest base trie, which all tries created from it since the base
+// checkpoint share their unchanged subtries with. Subtries of tries created from older base
+// tries (forks) may be stored again, which only increases the file size.
+func StoreDeltaCheckpoint(
+	base CheckpointBase,
+	tries []*trie.MTrie,
+	dir string,
+	fileName string,
+	logger *zerolog.Logger,
+) (
+	errToReturn error,
+) {
+	if len(base.RootHashes) == 0 || base.LatestTrie == nil {
+		return fmt.Errorf("base checkpoint %d has no tries", base.Num)
+	}
+	if len(base.RootHashes) > maxDeltaCheckpointRef {
+		return fmt.Errorf("base checkpoint %d has too many tries: %d", base.Num, len(base.RootHashes))
+	}
+	if len(tries) > maxDeltaCheckpointRef {
+		return fmt.Errorf("too many tries for delta checkpoint: %d", len(tries))
+	}
+
+	b := &deltaBuilder{
+		refIdx: make(map[*node.Node]int),
+		newIdx: make(map[*node.Node]int),
+	}
+
+	baseRoots := make(map[ledger.RootHash]int, len(base.RootHashes))
+	for i, rootHash := range base.RootHashes {
+		baseRoots[rootHash] = i
+	}
+
+	// tries with the root hash of a base trie are stored as a reference to its root
+	latestBase := len(base.RootHashes) - 1
+	for _, t := range tries {
+		if i, ok := baseRoots[t.RootHash()]; ok {
+			b.addRef(t.RootNode(), i, ledger.DummyPath)
+			continue
+		}
+		b.add(t.RootNode(), base.LatestTrie.RootNode(), latestBase, ledger.DummyPath)
+	}
+
+	logger.Info().Msgf("serializing delta checkpoint with %d tries, %d new nodes and %d base node references",
+		len(tries), len(b.newNodes), len(b.refs))
+
+	writer, err := CreateCheckpointWriterForFile(dir, fileName, logger)
+	if err != nil {
+		return fmt.Errorf("could not create writer: %w", err)
+	}
+	defer func() {
+		errToReturn = closeAndMergeError(writer, errToReturn)
+	}()
+
+	crc32Writer := NewCRC32Writer(writer)
+
+	_, err = crc32Writer.Write(encodeVersion(MagicBytesCheckpointDelta, VersionDeltaV1))
+	if err != nil {
+		return fmt.Errorf("cannot write delta checkpoint header: %w", err)
+	}
+
+	// base checkpoint
+	baseInfo := make([]byte, encDeltaBaseInfoSize)
+	if base.IsDelta {
+		baseInfo[0] = 1
+	}
+	binary.BigEndian.PutUint64(baseInfo[encBaseIsDeltaSize:], uint64(base.Num))
+	binary.BigEndian.PutUint16(baseInfo[encBaseIsDeltaSize+encBaseNumSize:], uint16(len(base.RootHashes)))
+	_, err = crc32Writer.Write(baseInfo)
+	if err != nil {
+		return fmt.Errorf("cannot write base checkpoint: %w", err)
+	}
+	for _, rootHash := range base.RootHashes {
+		_, err = crc32Writer.Write(rootHash[:])
+		if err != nil {
+			return fmt.Errorf("cannot write base trie root hash: %w", err)
+		}
+	}
+
+	// references to base nodes
+	buf := make([]byte, encRefSize)
+	binary.BigEndian.PutUint64(buf, uint64(len(b.refs)))
+	_, err = crc32Writer.Write(buf[:encRefCountSize])
+	if err != nil {
+		return fmt.Errorf("cannot write base node reference count: %w", err)
+	}
+	for _, ref := range b.refs {
+		binary.BigEndian.PutUint16(buf, ref.trieIndex)
+		binary.BigEndian.PutUint16(buf[encRefTrieIndexSize:], ref.height)
+		copy(buf[encRefTrieIndexSize+encRefHeightSize:], ref.path[:])
+		_, err = crc32Writer.Write(buf)
+		if err != nil {
+			return fmt.Errorf("cannot write base node reference: %w", err)
+		}
+	}
+
+	// new nodes
+	binary.BigEndian.PutUint64(buf, uint64(len(b.newNodes)))
+	_, err = crc32Writer.Write(buf[:encNodeCountSize])
+	if err != nil {
+		return fmt.Errorf("cannot write node count: %w", err)
+	}
+	scratch := make([]byte, 1024*4)
+	for _, n := range b.newNodes {
+		encoded := flattener.EncodeNode(n, b.index(n.LeftChild()), b.index(n.RightChild()), scratch)
+		_, err = crc32Writer.Write(encoded)
+		if err != nil {
+			return fmt.Errorf("cannot write node: %w", err)
+		}
+	}
+
+	// tries
+	binary.BigEndian.PutUint16(buf, uint16(len(tries)))
+	_, err = crc32Writer.Write(buf[:encTrieCountSize])
+	if err != nil {
+		return fmt.Errorf("cannot write trie count: %w", err)
+	}
+	for _, t := range tries {
+		encoded := flattener.EncodeTrie(t, b.index(t.RootNode()), scratch)
+		_, err = crc32Writer.Write(encoded)
+		if err != nil {
+			return fmt.Errorf("cannot write trie: %w", err)
+		}
+	}
+
+	_, err = writer.Write(encodeCRC32Sum(crc32Writer.Crc32()))
+	if err != nil {
+		return fmt.Errorf("cannot write CRC32 checksum: %w", err)
+	}
+
+	return nil
+}
+
+// add collects the given node of a new trie, along with its descendents, unless they are
+// already collected. baseNode is the node at the same position in the base trie with the
+// given index, if any.
+func (b *deltaBuilder) add(n *node.Node, baseNode *node.Node, trieIndex int, path ledger.Path) {
+	if n == nil {
+		return
+	}
+	if _, ok := b.refIdx[n]; ok {
+		return
+	}
+	if _, ok := b.newIdx[n]; ok {
+		return
+	}
+	if n == baseNode {
+		b.addRef(n, trieIndex, path)
+		return
+	}
+
+	if !n.IsLeaf() {
+		var baseLeft, baseRight *node.Node
+		if baseNode != nil && !baseNode.IsLeaf() {
+			baseLeft, baseRight = baseNode.LeftChild(), baseNode.RightChild()
+		}
+
+		depth := ledger.NodeMaxHeight - n.Height() // distance to the tree root
+		b.add(n.LeftChild(), baseLeft, trieIndex, path)
+		rightPath := path
+		bitutils.SetBit(rightPath[:], depth)
+		b.add(n.RightChild(), baseRight, trieIndex, rightPath)
+	}
+
+	b.newIdx[n] = len(b.newNodes)
+	b.newNodes = append(b.newNodes, n)
+}
+
+func (b *deltaBuilder) addRef(n *node.Node, trieIndex int, path ledger.Path) {
+	if n == nil {
+		return
+	}
+	if _, ok := b.refIdx[n]; ok {
+		return
+	}
+	b.refIdx[n] = len(b.refs)
+	b.refs = append(b.refs, nodeRef{
+		trieIndex: uint16(trieIndex),
+		height:    uint16(n.Height()),
+		path:      path,
+	})
+}
+
+// index returns the index of the given collected node in the delta checkpoint encoding:
+// references come first, followed by the new nodes. Index 0 means nil.
+func (b *deltaBuilder) index(n *node.Node) uint64 {
+	if n == nil {
+		return 0
+	}
+	if i, ok := b.refIdx[n]; ok {
+		return uint64(1 + i)
+	}
+	return uint64(1 + len(b.refs) + b.newIdx[n])
+}
+
+// LoadDeltaCheckpoint loads the tries of the delta checkpoint with the given number in the
+// given directory. The checkpoints it is based on are loaded first, down to the full checkpoint
+// the chain of delta checkpoints starts with.
+func LoadDeltaCheckpoint(dir string, num int, logger *zerolog.Logger) (
+	tries []*trie.MTrie,
+	errToReturn error,
+) {
+	filepath := path.Join(dir, DeltaNumberToFilename(num))
+	file, err := os.Open(filepath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open delta checkpoint file %s: %w", filepath, err)
+	}
+	defer func() {
+		evictErr := evictFileFromLinuxPageCache(file, false, logger)
+		if evictErr != nil {
+			logger.Warn().Msgf("failed to evict file %s from Linux page cache: %s", filepath, evictErr)
+			// No need to return this error because it's possible to continue normal operations.
+		}
+
+		errToReturn = closeAndMergeError(file, errToReturn)
+	}()
+
+	return readDeltaCheckpoint(file, dir, num, logger)
+}
+
+func readDeltaCheckpoint(f *os.File, dir string, num int, logger *zerolog.Logger) ([]*trie.MTrie, error) {
+	var bufReader io.Reader = bufio.NewReaderSize(f, defaultBufioReadSize)
+	crcReader := NewCRC32Reader(bufReader)
+	var reader io.Reader = crcReader
+
+	scratch := make([]byte, 1024*4) // must not be less than 1024
+
+	_, err := io.ReadFull(reader, scratch[:headerSize])
+	if err != nil {
+		return nil, fmt.Errorf("cannot read header: %w", err)
+	}
+	magic, version, err := decodeVersion(scratch[:headerSize])
+	if err != nil {
+		return nil, err
+	}
+	if magic != MagicBytesCheckpointDelta {
+		return nil, fmt.Errorf("unknown file format. Magic constant %x does not match expected %x", magic, MagicBytesCheckpointDelta)
+	}
+	if version != VersionDeltaV1 {
+		return nil, fmt.Errorf("unsupported delta checkpoint version %x", version)
+	}
+
+	// base checkpoint
+	_, err = io.ReadFull(reader, scratch[:encDeltaBaseInfoSize])
+	if err != nil {
+		return nil, fmt.Errorf("cannot read base checkpoint: %w", err)
+	}
+	baseIsDelta := scratch[0] == 1
+	baseNum := binary.BigEndian.Uint64(scratch[encBaseIsDeltaSize:])
+	baseTrieCount := binary.BigEndian.Uint16(scratch[encBaseIsDeltaSize+encBaseNumSize:])
+
+	if baseNum >= uint64(num) {
+		return nil, fmt.Errorf("invalid base checkpoint %d for delta checkpoint %d", baseNum, num)
+	}
+
+	logger.Info().Msgf("loading base checkpoint %d (delta: %v) of delta checkpoint %d", baseNum, baseIsDelta, num)
+
+	var baseTries []*trie.MTrie
+	if baseIsDelta {
+		baseTries, err = LoadDeltaCheckpoint(dir, int(baseNum), logger)
+	} else {
+		baseTries, err = LoadCheckpoint(path.Join(dir, NumberToFilename(int(baseNum))), logger)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot load base checkpoint %d: %w", baseNum, err)
+	}
+
+	if len(baseTries) != int(baseTrieCount) {
+		return nil, fmt.Errorf("base checkpoint %d has %d tries, expected %d", baseNum, len(baseTries), baseTrieCount)
+	}
+	for i, t := range baseTries {
+		_, err = io.ReadFull(reader, scratch[:encDeltaRootHashSize])
+		if err != nil {
+			return nil, fmt.Errorf("cannot read base trie root hash: %w", err)
+		}
+		readRootHash, err := hash.ToHash(scratch[:encDeltaRootHashSize])
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode base trie root hash: %w", err)
+		}
+		if !t.RootHash().Equals(ledger.RootHash(readRootHash)) {
+			return nil, fmt.Errorf("root hash of base trie %d does not match", i)
+		}
+	}
+
+	// references to base nodes
+	_, err = io.ReadFull(reader, scratch[:encRefCountSize])
+	if err != nil {
+		return nil, fmt.Errorf("cannot read base node reference count: %w", err)
+	}
+	refCount := binary.BigEndian.Uint64(scratch)
+
+	// nodes's element at index 0 is a special, meaning nil .
+	nodes := make([]*node.Node, 1, refCount+1)
+	for i := uint64(0); i < refCount; i++ {
+		_, err = io.ReadFull(reader, scratch[:encRefSize])
+		if err != nil {
+			return nil, fmt.Errorf("cannot read base node reference %d: %w", i, err)
+		}
+		trieIndex := binary.BigEndian.Uint16(scratch)
+		height := int(binary.BigEndian.Uint16(scratch[encRefTrieIndexSize:]))
+		refPath, err := ledger.ToPath(scratch[encRefTrieIndexSize+encRefHeightSize : encRefSize])
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode path of base node reference %d: %w", i, err)
+		}
+		if int(trieIndex) >= len(baseTries) {
+			return nil, fmt.Errorf("base node reference %d has invalid trie index %d", i, trieIndex)
+		}
+
+		n, err := nodeAtPosition(baseTries[trieIndex].RootNode(), refPath, height)
+		if err != nil {
+			return nil, fmt.Errorf("cannot resolve base node reference %d: %w", i, err)
+		}
+		nodes = append(nodes, n)
+	}
+
+	// new nodes
+	_, err = io.ReadFull(reader, scratch[:encNodeCountSize])
+	if err != nil {
+		return nil, fmt.Errorf("cannot read node count: %w", err)
+	}
+	nodesCount := binary.BigEndian.Uint64(scratch)
+
+	logging := logProgress("reading delta trie nodes", int(nodesCount), logger)
+
+	for i := uint64(1); i <= nodesCount; i++ {
+		next := uint64(len(nodes))
+		n, err := flattener.ReadNode(reader, scratch, func(nodeIndex uint64) (*node.Node, error) {
+			if nodeIndex >= next {
+				return nil, fmt.Errorf("sequence of serialized nodes does not satisfy Descendents-First-Relationship")
+			}
+			return nodes[nodeIndex], nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("cannot read node %d: %w", i, err)
+		}
+		nodes = append(nodes, n)
+		logging(i)
+	}
+
+	// tries
+	_, err = io.ReadFull(reader, scratch[:encTrieCountSize])
+	if err != nil {
+		return nil, fmt.Errorf("cannot read trie count: %w", err)
+	}
+	triesCount := binary.BigEndian.Uint16(scratch)
+
+	tries := make([]*trie.MTrie, triesCount)
+	for i := uint16(0); i < triesCount; i++ {
+		t, err := flattener.ReadTrie(reader, scratch, func(nodeIndex uint64) (*node.Node, error) {
+			if nodeIndex >= uint64(len(nodes)) {
+				return nil, fmt.Errorf("sequence of stored nodes doesn't contain node")
+			}
+			return nodes[nodeIndex], nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("cannot read trie %d: %w", i, err)
+		}
+		tries[i] = t
+	}
+
+	// Read CRC32
+	crc32buf := scratch[:crc32SumSize]
+	_, err = io.ReadFull(bufReader, crc32buf)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read CRC32: %w", err)
+	}
+
+	readCrc32 := binary.BigEndian.Uint32(crc32buf)
+
+	calculatedCrc32 := crcReader.Crc32()
+
+	if calculatedCrc32 != readCrc32 {
+		return nil, fmt.Errorf("delta checkpoint checksum failed! File contains %x but calculated crc32 is %x", readCrc32, calculatedCrc32)
+	}
+
+	return tries, nil
+}
+
+// nodeAtPosition returns the node at the given height on the given path of the subtrie with
+// the given root node.
+func nodeAtPosition(root *node.Node, path ledger.Path, height int) (*node.Node, error) {
+	n := root
+	for n != nil && n.Height() > height {
+		depth := ledger.NodeMaxHeight - n.Height() // distance to the tree root
+		if bitutils.ReadBit(path[:], depth) == 0 {
+			n = n.LeftChild()
+		} else {
+			n = n.RightChild()
+		}
+	}
+	if n == nil || n.Height() != height {
+		return nil, fmt.Errorf("no node at height %d on path %x", height, path)
+	}
+	return n, nil
+}
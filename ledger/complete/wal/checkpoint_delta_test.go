@@ -0,0 +1,155 @@
+package wal
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// updateTrie returns a new trie with random registers added to the given trie, and some of the
+// given existing paths updated or removed.
+func updateTrie(t *testing.T, parent *trie.MTrie, existing []ledger.Path) *trie.MTrie {
+	paths, payloads := randNPathPayloads(10)
+	for i, p := range existing {
+		paths = append(paths, p)
+		if i%2 == 0 {
+			payloads = append(payloads, *ledger.EmptyPayload())
+		} else {
+			_, payload := randPathPayload()
+			payloads = append(payloads, payload)
+		}
+	}
+	updated, _, err := trie.NewTrieWithUpdatedRegisters(parent, paths, payloads, true)
+	require.NoError(t, err)
+	return updated
+}
+
+// createDeltaCheckpointChain stores a full checkpoint 1, and delta checkpoints 2 and 3, based on
+// checkpoint 1 and 2 respectively. It returns the tries of each checkpoint.
+func createDeltaCheckpointChain(t *testing.T, dir string) [][]*trie.MTrie {
+	logger := unittest.Logger()
+
+	var tries []*trie.MTrie
+	var paths []ledger.Path
+	active := trie.NewEmptyMTrie()
+	tries = append(tries, active)
+	for i := 0; i < 10; i++ {
+		newPaths, payloads := randNPathPayloads(300)
+		var err error
+		active, _, err = trie.NewTrieWithUpdatedRegisters(active, newPaths, payloads, true)
+		require.NoError(t, err)
+		tries = append(tries, active)
+		paths = append(paths, newPaths...)
+	}
+
+	full := tries
+	require.NoError(t, StoreCheckpointV6SingleThread(full, dir, NumberToFilename(1), &logger))
+
+	// the second checkpoint drops the oldest tries, and adds tries created from the latest trie,
+	// and a fork created from an older trie
+	delta1 := append([]*trie.MTrie{}, full[3:]...)
+	next := full[len(full)-1]
+	for i := 0; i < 5; i++ {
+		next = updateTrie(t, next, paths[i*10:i*10+10])
+		delta1 = append(delta1, next)
+	}
+	delta1 = append(delta1, updateTrie(t, full[5], paths[:10]))
+
+	require.NoError(t, StoreDeltaCheckpoint(NewCheckpointBase(1, false, full), delta1, dir, DeltaNumberToFilename(2), &logger))
+
+	// the third checkpoint is based on the second one
+	delta2 := append([]*trie.MTrie{}, delta1[2:]...)
+	for i := 0; i < 5; i++ {
+		next = updateTrie(t, next, paths[i*10+50:i*10+60])
+		delta2 = append(delta2, next)
+	}
+	delta2 = append(delta2, trie.NewEmptyMTrie())
+
+	require.NoError(t, StoreDeltaCheckpoint(NewCheckpointBase(2, true, delta1), delta2, dir, DeltaNumberToFilename(3), &logger))
+
+	return [][]*trie.MTrie{full, delta1, delta2}
+}
+
+func TestWriteAndReadDeltaCheckpoint(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		tries := createDeltaCheckpointChain(t, dir)
+		logger := unittest.Logger()
+
+		decoded, err := LoadDeltaCheckpoint(dir, 2, &logger)
+		require.NoError(t, err)
+		requireTriesEqual(t, tries[1], decoded)
+
+		decoded, err = LoadDeltaCheckpoint(dir, 3, &logger)
+		require.NoError(t, err)
+		requireTriesEqual(t, tries[2], decoded)
+
+		// delta checkpoints only contain the new nodes
+		fullFiles, err := filepath.Glob(filePathPattern(dir, NumberToFilename(1)))
+		require.NoError(t, err)
+		fullSize := int64(0)
+		for _, file := range fullFiles {
+			info, err := os.Stat(file)
+			require.NoError(t, err)
+			fullSize += info.Size()
+		}
+		deltaInfo, err := os.Stat(path.Join(dir, DeltaNumberToFilename(3)))
+		require.NoError(t, err)
+		require.Less(t, deltaInfo.Size(), fullSize)
+	})
+}
+
+func TestListDeltaCheckpoints(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		createDeltaCheckpointChain(t, dir)
+
+		deltas, err := DeltaCheckpoints(dir)
+		require.NoError(t, err)
+		require.Equal(t, []int{2, 3}, deltas)
+
+		// delta checkpoints are not listed as full checkpoints
+		checkpoints, err := Checkpoints(dir)
+		require.NoError(t, err)
+		require.Equal(t, []int{1}, checkpoints)
+	})
+}
+
+func TestReadCorruptedDeltaCheckpoint(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		createDeltaCheckpointChain(t, dir)
+		logger := unittest.Logger()
+
+		filePath := path.Join(dir, DeltaNumberToFilename(2))
+		data, err := os.ReadFile(filePath)
+		require.NoError(t, err)
+
+		// flip a byte of the last trie
+		data[len(data)-crc32SumSize-10] ^= 0xff
+		require.NoError(t, os.WriteFile(filePath, data, 0644))
+
+		_, err = LoadDeltaCheckpoint(dir, 2, &logger)
+		require.Error(t, err)
+
+		// the corrupted checkpoint breaks the chain
+		_, err = LoadDeltaCheckpoint(dir, 3, &logger)
+		require.Error(t, err)
+	})
+}
+
+func TestReadDeltaCheckpointMissingBase(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		createDeltaCheckpointChain(t, dir)
+		logger := unittest.Logger()
+
+		require.NoError(t, deleteCheckpointFiles(dir, NumberToFilename(1)))
+
+		_, err := LoadDeltaCheckpoint(dir, 3, &logger)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,215 @@
+package wal
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/flattener"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/node"
+)
+
+// CheckpointNode is a node read from a checkpoint, with its children referenced by node index.
+// Node indices are unique across all part files of the checkpoint and start at 1, the index 0
+// represents a missing child.
+type CheckpointNode struct {
+	Index uint64
+	// Node is the node read from the checkpoint. The children of interim nodes are placeholders,
+	// so only the height, hash, and for leaves the path and payload of the node are meaningful.
+	Node       *node.Node
+	LeftIndex  uint64
+	RightIndex uint64
+}
+
+// CheckpointTrie is a trie read from a checkpoint, with its root node referenced by node index.
+type CheckpointTrie struct {
+	RootIndex uint64
+	RootHash  ledger.RootHash
+	RegCount  uint64
+	RegSize   uint64
+}
+
+// ReadNodesFromCheckpointV6 reads the checkpoint one node at a time, without keeping the tries
+// of the checkpoint in memory. The nodes are passed to processNode in the order they are stored,
+// which is descendants first, and are followed by the tries, which are passed to processTrie.
+// Any error returned by processNode or processTrie aborts the reading and is returned.
+func ReadNodesFromCheckpointV6(
+	dir string,
+	fileName string,
+	logger *zerolog.Logger,
+	processNode func(*CheckpointNode) error,
+	processTrie func(*CheckpointTrie) error,
+) error {
+	filepath := filePathCheckpointHeader(dir, fileName)
+
+	lg := logger.With().Str("checkpoint_file", filepath).Logger()
+	lg.Info().Msgf("streaming nodes of v6 checkpoint file")
+
+	subtrieChecksums, topTrieChecksum, err := readCheckpointHeader(filepath, logger)
+	if err != nil {
+		return fmt.Errorf("could not read header: %w", err)
+	}
+
+	// ensure all checkpoint part file exists, might return os.ErrNotExist error
+	// if a file is missing
+	err = allPartFileExist(dir, fileName, len(subtrieChecksums))
+	if err != nil {
+		return fmt.Errorf("fail to check all checkpoint part file exist: %w", err)
+	}
+
+	scratch := make([]byte, 1024*4) // must not be less than 1024
+
+	// readNode reads the node with the given index, whose children have indices in the range
+	// (offset, index), or 0 if missing. The child indices are relative to the offset.
+	dummyChild := &node.Node{}
+	readNode := func(reader *Crc32Reader, index uint64, offset uint64) error {
+		var children []uint64
+		n, err := flattener.ReadNode(reader, scratch, func(childIndex uint64) (*node.Node, error) {
+			if childIndex == 0 {
+				children = append(children, 0)
+				return nil, nil
+			}
+			childIndex += offset
+			if childIndex >= index {
+				return nil, fmt.Errorf("sequence of serialized nodes does not satisfy Descendents-First-Relationship")
+			}
+			children = append(children, childIndex)
+			return dummyChild, nil
+		})
+		if err != nil {
+			return fmt.Errorf("cannot read node %d: %w", index, err)
+		}
+
+		checkpointNode := &CheckpointNode{
+			Index: index,
+			Node:  n,
+		}
+		if len(children) == 2 {
+			checkpointNode.LeftIndex, checkpointNode.RightIndex = children[0], children[1]
+		}
+		return processNode(checkpointNode)
+	}
+
+	// child indices of subtrie nodes are relative to their subtrie file
+	var totalSubTrieNodeCount uint64
+	for i, checksum := range subtrieChecksums {
+		offset := totalSubTrieNodeCount
+		err = processCheckpointSubTrie(dir, fileName, i, checksum, &lg,
+			func(reader *Crc32Reader, nodesCount uint64) error {
+				logging := logProgress(fmt.Sprintf("streaming %v-th sub trie nodes", i), int(nodesCount), &lg)
+				for j := uint64(1); j <= nodesCount; j++ {
+					err := readNode(reader, offset+j, offset)
+					if err != nil {
+						return err
+					}
+					logging(j)
+				}
+				totalSubTrieNodeCount += nodesCount
+				return nil
+			})
+		if err != nil {
+			return fmt.Errorf("could not read %v-th subtrie file: %w", i, err)
+		}
+	}
+
+	// child indices of top level nodes are relative to the whole checkpoint
+	err = processCheckpointTopLevelTries(dir, fileName, totalSubTrieNodeCount, topTrieChecksum, &lg,
+		func(reader *Crc32Reader, topLevelNodesCount uint64, triesCount uint16) error {
+			for i := uint64(1); i <= topLevelNodesCount; i++ {
+				err := readNode(reader, totalSubTrieNodeCount+i, 0)
+				if err != nil {
+					return err
+				}
+			}
+
+			for i := uint16(0); i < triesCount; i++ {
+				rootIndex, regCount, regSize, rootHash, err := flattener.ReadTrieRoot(reader, scratch)
+				if err != nil {
+					return fmt.Errorf("cannot read root trie at index %d: %w", i, err)
+				}
+				if rootIndex > totalSubTrieNodeCount+topLevelNodesCount {
+					return fmt.Errorf("root node index %d of trie at index %d is out of range", rootIndex, i)
+				}
+
+				err = processTrie(&CheckpointTrie{
+					RootIndex: rootIndex,
+					RootHash:  rootHash,
+					RegCount:  regCount,
+					RegSize:   regSize,
+				})
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	if err != nil {
+		return fmt.Errorf("could not read top level nodes or tries: %w", err)
+	}
+
+	return nil
+}
+
+// ReadPayloadsFromCheckpointV6 reads the payloads of the only trie of the checkpoint one at a time,
+// without keeping the trie in memory, and returns the root hash of the trie once all payloads were
+// passed to processPayload. Any error returned by processPayload aborts the reading and is returned.
+// Returns an error if the checkpoint does not contain exactly one trie, since the payloads of a
+// checkpoint with several tries can not be attributed to a trie without keeping its nodes.
+func ReadPayloadsFromCheckpointV6(
+	dir string,
+	fileName string,
+	logger *zerolog.Logger,
+	processPayload func(*ledger.Payload) error,
+) (ledger.RootHash, error) {
+	triesCount, err := readCheckpointV6TriesCount(dir, fileName)
+	if err != nil {
+		return ledger.RootHash{}, fmt.Errorf("could not read tries count: %w", err)
+	}
+	if triesCount != 1 {
+		return ledger.RootHash{}, fmt.Errorf("checkpoint contains %d tries, expected exactly one", triesCount)
+	}
+
+	var rootHash ledger.RootHash
+	err = ReadNodesFromCheckpointV6(dir, fileName, logger,
+		func(n *CheckpointNode) error {
+			if !n.Node.IsLeaf() {
+				return nil
+			}
+			return processPayload(n.Node.Payload())
+		},
+		func(t *CheckpointTrie) error {
+			rootHash = t.RootHash
+			return nil
+		})
+	if err != nil {
+		return ledger.RootHash{}, err
+	}
+
+	return rootHash, nil
+}
+
+// readCheckpointV6TriesCount returns the number of tries of the checkpoint, read from the footer of
+// its top level tries file.
+func readCheckpointV6TriesCount(dir string, fileName string) (triesCount uint16, errToReturn error) {
+	filepath, _ := filePathTopTries(dir, fileName)
+	file, err := os.Open(filepath)
+	if err != nil {
+		return 0, fmt.Errorf("could not open file %v: %w", filepath, err)
+	}
+	defer func(file *os.File) {
+		errToReturn = closeAndMergeError(file, errToReturn)
+	}(file)
+
+	err = validateFileHeader(MagicBytesCheckpointToptrie, VersionV6, file)
+	if err != nil {
+		return 0, err
+	}
+
+	_, triesCount, _, err = readTopTriesFooter(file)
+	if err != nil {
+		return 0, fmt.Errorf("could not read top tries footer: %w", err)
+	}
+	return triesCount, nil
+}
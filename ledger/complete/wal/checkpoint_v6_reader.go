@@ -490,6 +490,63 @@ func readSubTriesFooter(f *os.File) (uint64, uint32, error) {
 	return nodeCount, expectedSum, nil
 }
 
+func readTopLevelTries(dir string, fileName string, subtrieNodes [][]*node.Node, topTrieChecksum uint32, logger *zerolog.Logger) (
+	[]*trie.MTrie,
+	error,
+) {
+	totalSubTrieNodeCount := computeTotalSubTrieNodeCount(subtrieNodes)
+
+	var tries []*trie.MTrie
+	err := processCheckpointTopLevelTries(dir, fileName, totalSubTrieNodeCount, topTrieChecksum, logger,
+		func(reader *Crc32Reader, topLevelNodesCount uint64, triesCount uint16) error {
+			topLevelNodes := make([]*node.Node, topLevelNodesCount+1) //+1 for 0 index meaning nil
+			tries = make([]*trie.MTrie, triesCount)
+
+			// Scratch buffer is used as temporary buffer that reader can read into.
+			// Raw data in scratch buffer should be copied or converted into desired
+			// objects before next Read operation.  If the scratch buffer isn't large
+			// enough, a new buffer will be allocated.  However, 4096 bytes will
+			// be large enough to handle almost all payloads and 100% of interim nodes.
+			scratch := make([]byte, 1024*4) // must not be less than 1024
+
+			// read the nodes from subtrie level to the root level
+			for i := uint64(1); i <= topLevelNodesCount; i++ {
+				node, err := flattener.ReadNode(reader, scratch, func(nodeIndex uint64) (*node.Node, error) {
+					if nodeIndex >= i+uint64(totalSubTrieNodeCount) {
+						return nil, fmt.Errorf("sequence of serialized nodes does not satisfy Descendents-First-Relationship")
+					}
+
+					return getNodeByIndex(subtrieNodes, totalSubTrieNodeCount, topLevelNodes, nodeIndex)
+				})
+				if err != nil {
+					return fmt.Errorf("cannot read node at index %d: %w", i, err)
+				}
+
+				topLevelNodes[i] = node
+			}
+
+			// read the trie root nodes
+			for i := uint16(0); i < triesCount; i++ {
+				trie, err := flattener.ReadTrie(reader, scratch, func(nodeIndex uint64) (*node.Node, error) {
+					return getNodeByIndex(subtrieNodes, totalSubTrieNodeCount, topLevelNodes, nodeIndex)
+				})
+
+				if err != nil {
+					return fmt.Errorf("cannot read root trie at index %d: %w", i, err)
+				}
+				tries[i] = trie
+			}
+
+			return nil
+		})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return tries, nil
+}
+
 // 17th part file contains:
 // 1. checkpoint version
 // 2. subtrieNodeCount
@@ -498,14 +555,20 @@ func readSubTriesFooter(f *os.File) (uint64, uint32, error) {
 // 5. node count
 // 6. trie count
 // 7. checksum
-func readTopLevelTries(dir string, fileName string, subtrieNodes [][]*node.Node, topTrieChecksum uint32, logger *zerolog.Logger) (
-	rootTries []*trie.MTrie,
+func processCheckpointTopLevelTries(
+	dir string,
+	fileName string,
+	totalSubTrieNodeCount uint64,
+	topTrieChecksum uint32,
+	logger *zerolog.Logger,
+	processNodesAndTries func(reader *Crc32Reader, topLevelNodesCount uint64, triesCount uint16) error,
+) (
 	errToReturn error,
 ) {
 	filepath, _ := filePathTopTries(dir, fileName)
 	file, err := os.Open(filepath)
 	if err != nil {
-		return nil, fmt.Errorf("could not open file %v: %w", filepath, err)
+		return fmt.Errorf("could not open file %v: %w", filepath, err)
 	}
 	defer func(file *os.File) {
 		evictErr := evictFileFromLinuxPageCache(file, false, logger)
@@ -519,17 +582,17 @@ func readTopLevelTries(dir string, fileName string, subtrieNodes [][]*node.Node,
 	// read and validate magic bytes and version
 	err = validateFileHeader(MagicBytesCheckpointToptrie, VersionV6, file)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	// read subtrie Node count and validate
 	topLevelNodesCount, triesCount, expectedSum, err := readTopTriesFooter(file)
 	if err != nil {
-		return nil, fmt.Errorf("could not read top tries footer: %w", err)
+		return fmt.Errorf("could not read top tries footer: %w", err)
 	}
 
 	if topTrieChecksum != expectedSum {
-		return nil, fmt.Errorf("mismatch top trie checksum, header file has %v, toptrie file has %v",
+		return fmt.Errorf("mismatch top trie checksum, header file has %v, toptrie file has %v",
 			topTrieChecksum, expectedSum)
 	}
 
@@ -537,7 +600,7 @@ func readTopLevelTries(dir string, fileName string, subtrieNodes [][]*node.Node,
 	// in order to compute the correct checksum
 	_, err = file.Seek(0, io.SeekStart)
 	if err != nil {
-		return nil, fmt.Errorf("could not seek to 0: %w", err)
+		return fmt.Errorf("could not seek to 0: %w", err)
 	}
 
 	reader := NewCRC32Reader(bufio.NewReaderSize(file, defaultBufioReadSize))
@@ -545,90 +608,57 @@ func readTopLevelTries(dir string, fileName string, subtrieNodes [][]*node.Node,
 	// read version again for calculating checksum
 	_, _, err = readFileHeader(reader)
 	if err != nil {
-		return nil, fmt.Errorf("could not read version for top trie: %w", err)
+		return fmt.Errorf("could not read version for top trie: %w", err)
 	}
 
 	// read subtrie count and validate
 	buf := make([]byte, encNodeCountSize)
 	_, err = io.ReadFull(reader, buf)
 	if err != nil {
-		return nil, fmt.Errorf("could not read subtrie node count: %w", err)
+		return fmt.Errorf("could not read subtrie node count: %w", err)
 	}
 	readSubtrieNodeCount, err := decodeNodeCount(buf)
 	if err != nil {
-		return nil, fmt.Errorf("could not decode node count: %w", err)
+		return fmt.Errorf("could not decode node count: %w", err)
 	}
 
-	totalSubTrieNodeCount := computeTotalSubTrieNodeCount(subtrieNodes)
-
 	if readSubtrieNodeCount != totalSubTrieNodeCount {
-		return nil, fmt.Errorf("mismatch subtrie node count, read from disk (%v), but got actual node count (%v)",
+		return fmt.Errorf("mismatch subtrie node count, read from disk (%v), but got actual node count (%v)",
 			readSubtrieNodeCount, totalSubTrieNodeCount)
 	}
 
-	topLevelNodes := make([]*node.Node, topLevelNodesCount+1) //+1 for 0 index meaning nil
-	tries := make([]*trie.MTrie, triesCount)
-
-	// Scratch buffer is used as temporary buffer that reader can read into.
-	// Raw data in scratch buffer should be copied or converted into desired
-	// objects before next Read operation.  If the scratch buffer isn't large
-	// enough, a new buffer will be allocated.  However, 4096 bytes will
-	// be large enough to handle almost all payloads and 100% of interim nodes.
-	scratch := make([]byte, 1024*4) // must not be less than 1024
-
-	// read the nodes from subtrie level to the root level
-	for i := uint64(1); i <= topLevelNodesCount; i++ {
-		node, err := flattener.ReadNode(reader, scratch, func(nodeIndex uint64) (*node.Node, error) {
-			if nodeIndex >= i+uint64(totalSubTrieNodeCount) {
-				return nil, fmt.Errorf("sequence of serialized nodes does not satisfy Descendents-First-Relationship")
-			}
-
-			return getNodeByIndex(subtrieNodes, totalSubTrieNodeCount, topLevelNodes, nodeIndex)
-		})
-		if err != nil {
-			return nil, fmt.Errorf("cannot read node at index %d: %w", i, err)
-		}
-
-		topLevelNodes[i] = node
+	err = processNodesAndTries(reader, topLevelNodesCount, triesCount)
+	if err != nil {
+		return err
 	}
 
-	// read the trie root nodes
-	for i := uint16(0); i < triesCount; i++ {
-		trie, err := flattener.ReadTrie(reader, scratch, func(nodeIndex uint64) (*node.Node, error) {
-			return getNodeByIndex(subtrieNodes, totalSubTrieNodeCount, topLevelNodes, nodeIndex)
-		})
-
-		if err != nil {
-			return nil, fmt.Errorf("cannot read root trie at index %d: %w", i, err)
-		}
-		tries[i] = trie
-	}
+	scratch := make([]byte, encNodeCountSize+encTrieCountSize+crc32SumSize)
 
 	// read footer and discard, since we only care about checksum
 	_, err = io.ReadFull(reader, scratch[:encNodeCountSize+encTrieCountSize])
 	if err != nil {
-		return nil, fmt.Errorf("cannot read footer: %w", err)
+		return fmt.Errorf("cannot read footer: %w", err)
 	}
 
 	actualSum := reader.Crc32()
 
 	if actualSum != expectedSum {
-		return nil, fmt.Errorf("invalid checksum in top level trie, expected %v, actual %v",
+		return fmt.Errorf("invalid checksum in top level trie, expected %v, actual %v",
 			expectedSum, actualSum)
 	}
 
 	// read the checksum and discard, since we only care about whether ensureReachedEOF
 	_, err = io.ReadFull(reader, scratch[:crc32SumSize])
 	if err != nil {
-		return nil, fmt.Errorf("could not read checksum from top trie file: %w", err)
+		return fmt.Errorf("could not read checksum from top trie file: %w", err)
 	}
 
 	err = ensureReachedEOF(reader)
 	if err != nil {
-		return nil, fmt.Errorf("fail to read top trie file: %w", err)
+		return fmt.Errorf("fail to read top trie file: %w", err)
 	}
 
-	return tries, nil
+	return nil
 }
 
 func readFileHeader(reader io.Reader) (uint16, uint16, error) {
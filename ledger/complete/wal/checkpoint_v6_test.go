@@ -363,6 +363,102 @@ func TestWriteAndReadCheckpointV6LeafMultipleTries(t *testing.T) {
 	})
 }
 
+func TestWriteAndReadCheckpointV6Nodes(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		fileName := "checkpoint-multi-node-file"
+		tries := createMultipleRandomTries(t)
+		logger := unittest.Logger()
+		require.NoErrorf(t, StoreCheckpointV6Concurrently(tries, dir, fileName, &logger), "fail to store checkpoint")
+
+		// rebuild the tries from the streamed nodes
+		nodes := map[uint64]*node.Node{0: nil}
+		decoded := make([]*trie.MTrie, 0, len(tries))
+		err := ReadNodesFromCheckpointV6(dir, fileName, &logger,
+			func(n *CheckpointNode) error {
+				_, found := nodes[n.Index]
+				require.False(t, found, "node index %d is not unique", n.Index)
+				if n.Node.IsLeaf() {
+					nodes[n.Index] = n.Node
+					return nil
+				}
+				_, foundLeft := nodes[n.LeftIndex]
+				_, foundRight := nodes[n.RightIndex]
+				require.True(t, foundLeft && foundRight, "children of node %d are not read before it", n.Index)
+				nodes[n.Index] = node.NewNode(n.Node.Height(), nodes[n.LeftIndex], nodes[n.RightIndex], ledger.DummyPath, nil, n.Node.Hash())
+				return nil
+			},
+			func(ct *CheckpointTrie) error {
+				decodedTrie, err := trie.NewMTrie(nodes[ct.RootIndex], ct.RegCount, ct.RegSize)
+				require.NoError(t, err)
+				require.Equal(t, ct.RootHash, decodedTrie.RootHash())
+				decoded = append(decoded, decodedTrie)
+				return nil
+			})
+		require.NoErrorf(t, err, "fail to read checkpoint %v/%v", dir, fileName)
+		requireTriesEqual(t, tries, decoded)
+	})
+}
+
+func TestWriteAndReadCheckpointV6Payloads(t *testing.T) {
+	t.Run("single trie", func(t *testing.T) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			fileName := "checkpoint-single-trie"
+			tries := createMultipleRandomTriesMini(t)
+			last := tries[len(tries)-1]
+			logger := unittest.Logger()
+			require.NoErrorf(t, StoreCheckpointV6Concurrently([]*trie.MTrie{last}, dir, fileName, &logger), "fail to store checkpoint")
+
+			payloads := make(map[string]ledger.Value)
+			rootHash, err := ReadPayloadsFromCheckpointV6(dir, fileName, &logger, func(payload *ledger.Payload) error {
+				key, err := payload.Key()
+				require.NoError(t, err)
+				payloads[key.String()] = payload.Value()
+				return nil
+			})
+			require.NoError(t, err)
+			require.Equal(t, last.RootHash(), rootHash)
+
+			expected := make(map[string]ledger.Value)
+			for _, payload := range last.AllPayloads() {
+				key, err := payload.Key()
+				require.NoError(t, err)
+				expected[key.String()] = payload.Value()
+			}
+			require.Equal(t, expected, payloads)
+		})
+	})
+
+	t.Run("processing error", func(t *testing.T) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			fileName := "checkpoint-single-trie"
+			tries := createSimpleTrie(t)
+			logger := unittest.Logger()
+			require.NoErrorf(t, StoreCheckpointV6Concurrently(tries[len(tries)-1:], dir, fileName, &logger), "fail to store checkpoint")
+
+			expectedErr := fmt.Errorf("processing error")
+			_, err := ReadPayloadsFromCheckpointV6(dir, fileName, &logger, func(*ledger.Payload) error {
+				return expectedErr
+			})
+			require.ErrorIs(t, err, expectedErr)
+		})
+	})
+
+	t.Run("multiple tries", func(t *testing.T) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			fileName := "checkpoint-multiple-tries"
+			tries := createMultipleRandomTriesMini(t)
+			logger := unittest.Logger()
+			require.NoErrorf(t, StoreCheckpointV6Concurrently(tries, dir, fileName, &logger), "fail to store checkpoint")
+
+			_, err := ReadPayloadsFromCheckpointV6(dir, fileName, &logger, func(*ledger.Payload) error {
+				require.Fail(t, "payloads of a checkpoint with multiple tries must not be read")
+				return nil
+			})
+			require.Error(t, err)
+		})
+	})
+}
+
 // compareFiles takes two files' full path, and read them bytes by bytes and compare if
 // the two files are identical
 // it returns nil if identical
@@ -170,31 +170,53 @@ func (w *DiskWAL) replay(
 			return fmt.Errorf("cannot get list of checkpoints: %w", err)
 		}
 
-		var availableCheckpoints []int
+		allDeltaCheckpoints, err := checkpointer.DeltaCheckpoints()
+		if err != nil {
+			return fmt.Errorf("cannot get list of delta checkpoints: %w", err)
+		}
+
+		var availableCheckpoints, availableDeltaCheckpoints []int
 
 		// if there are no checkpoints already, don't bother
 		if len(allCheckpoints) > 0 {
 			// from-1 to account for checkpoints connected to segments, ie. checkpoint 8 if replaying segments 9-12
 			availableCheckpoints = getPossibleCheckpoints(allCheckpoints, from-1, to)
 		}
+		if len(allDeltaCheckpoints) > 0 {
+			availableDeltaCheckpoints = getPossibleCheckpoints(allDeltaCheckpoints, from-1, to)
+		}
 
-		for len(availableCheckpoints) > 0 {
+		for len(availableCheckpoints) > 0 || len(availableDeltaCheckpoints) > 0 {
 			// as long as there are checkpoints to try, we always try with the last checkpoint file, since
-			// it allows us to load less segments.
-			latestCheckpoint := availableCheckpoints[len(availableCheckpoints)-1]
+			// it allows us to load less segments. Full checkpoints are preferred over delta checkpoints
+			// with the same number, since they don't depend on other checkpoint files.
+			latestCheckpoint := -1
+			if len(availableCheckpoints) > 0 {
+				latestCheckpoint = availableCheckpoints[len(availableCheckpoints)-1]
+			}
+			isDelta := len(availableDeltaCheckpoints) > 0 &&
+				availableDeltaCheckpoints[len(availableDeltaCheckpoints)-1] > latestCheckpoint
 
-			w.log.Info().Int("checkpoint", latestCheckpoint).Msg("loading checkpoint")
+			var forestSequencing []*trie.MTrie
+			if isDelta {
+				latestCheckpoint = availableDeltaCheckpoints[len(availableDeltaCheckpoints)-1]
+				availableDeltaCheckpoints = availableDeltaCheckpoints[:len(availableDeltaCheckpoints)-1]
 
-			forestSequencing, err := checkpointer.LoadCheckpoint(latestCheckpoint)
+				w.log.Info().Int("checkpoint", latestCheckpoint).Msg("loading delta checkpoint")
+				forestSequencing, err = checkpointer.LoadDeltaCheckpoint(latestCheckpoint)
+			} else {
+				availableCheckpoints = availableCheckpoints[:len(availableCheckpoints)-1]
+
+				w.log.Info().Int("checkpoint", latestCheckpoint).Msg("loading checkpoint")
+				forestSequencing, err = checkpointer.LoadCheckpoint(latestCheckpoint)
+			}
 			if err != nil {
-				w.log.Warn().Int("checkpoint", latestCheckpoint).Err(err).
+				w.log.Warn().Int("checkpoint", latestCheckpoint).Bool("delta", isDelta).Err(err).
 					Msg("checkpoint loading failed")
-
-				availableCheckpoints = availableCheckpoints[:len(availableCheckpoints)-1]
 				continue
 			}
 
-			w.log.Info().Int("checkpoint", latestCheckpoint).Msg("checkpoint loaded")
+			w.log.Info().Int("checkpoint", latestCheckpoint).Bool("delta", isDelta).Msg("checkpoint loaded")
 
 			err = checkpointFn(forestSequencing)
 			if err != nil {
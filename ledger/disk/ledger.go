@@ -0,0 +1,543 @@
+package disk
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/hash"
+	"github.com/onflow/flow-go/ledger/common/pathfinder"
+	"github.com/onflow/flow-go/ledger/complete/mtrie"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/node"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/metrics"
+)
+
+const DefaultCacheSize = 1_000_000
+
+// Ledger (disk) is a fork-aware thread-safe trie-based key/value storage, which keeps the nodes
+// of its tries in an on-disk key-value store instead of in memory, with a cache of recently
+// used nodes.
+//
+// It uses the same tries as the complete ledger, and therefore produces identical states and
+// proofs. For each operation, the nodes on the paths of the operation are loaded from disk into
+// a partial in-memory trie, on which the operation is performed by the mtrie implementation.
+// The nodes created by an update are written to disk before the new state is returned, so no
+// write-ahead log or checkpointing is needed, and the ledger doesn't have to be rebuilt on startup.
+//
+// Like the forest of the complete ledger, the ledger keeps the most recently stored states up to
+// its capacity. When a state is removed, the nodes which are no longer reachable from any kept
+// state are removed from disk, which is tracked by reference counting the stored nodes.
+type Ledger struct {
+	db                *badger.DB
+	nodes             *nodeStore
+	metrics           module.LedgerMetrics
+	logger            zerolog.Logger
+	pathFinderVersion uint8
+	capacity          int
+
+	// writeLock serializes writes, which update the reference counts of stored nodes
+	writeLock sync.Mutex
+	// pruneLock prevents states from being removed while their nodes are loaded
+	pruneLock sync.RWMutex
+
+	// the following fields are protected by writeLock
+	states   *list.List // root hashes of the kept states, least recently stored first
+	elements map[ledger.RootHash]*list.Element
+	nextSeq  uint64
+}
+
+var _ ledger.Ledger = (*Ledger)(nil)
+
+// NewLedger creates a new disk-backed ledger which stores its tries in a badger database in
+// the given directory, caching up to cacheSize nodes in memory. It keeps the capacity most
+// recently stored states, or all states if capacity is 0. The state of the empty trie is always kept.
+func NewLedger(
+	dir string,
+	cacheSize int,
+	capacity int,
+	metrics module.LedgerMetrics,
+	log zerolog.Logger,
+	pathFinderVer uint8) (*Ledger, error) {
+
+	logger := log.With().Str("ledger_mod", "disk").Logger()
+
+	db, err := badger.Open(badger.DefaultOptions(dir).WithLogger(nil))
+	if err != nil {
+		return nil, fmt.Errorf("could not open ledger db: %w", err)
+	}
+
+	nodes, err := newNodeStore(db, cacheSize)
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("could not create node store: %w", err)
+	}
+
+	l := &Ledger{
+		db:                db,
+		nodes:             nodes,
+		metrics:           metrics,
+		logger:            logger,
+		pathFinderVersion: pathFinderVer,
+		capacity:          capacity,
+		states:            list.New(),
+		elements:          make(map[ledger.RootHash]*list.Element),
+	}
+
+	err = l.loadStates()
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("could not load stored states: %w", err)
+	}
+
+	// like the forest, the ledger always has the empty trie
+	err = l.ImportTrie(trie.NewEmptyMTrie())
+	if err != nil {
+		return nil, fmt.Errorf("could not store empty trie: %w", err)
+	}
+
+	return l, nil
+}
+
+// Ready implements interface module.ReadyDoneAware
+func (l *Ledger) Ready() <-chan struct{} {
+	ready := make(chan struct{})
+	close(ready)
+	return ready
+}
+
+// Done implements interface module.ReadyDoneAware
+// It closes the database.
+func (l *Ledger) Done() <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		err := l.db.Close()
+		if err != nil {
+			l.logger.Error().Err(err).Msg("failed to close ledger db")
+		}
+	}()
+	return done
+}
+
+// InitialState returns the state of an empty ledger
+func (l *Ledger) InitialState() ledger.State {
+	return ledger.State(trie.EmptyTrieRootHash())
+}
+
+// HasState returns true if the given state exists inside the ledger
+func (l *Ledger) HasState(state ledger.State) bool {
+	found, err := l.nodes.hasRoot(ledger.RootHash(state))
+	if err != nil {
+		l.logger.Error().Err(err).Hex("state", state[:]).Msg("failed to look up state")
+		return false
+	}
+	return found
+}
+
+// loadStates restores the order of the kept states from their sequence numbers.
+func (l *Ledger) loadStates() error {
+	records, err := l.nodes.roots()
+	if err != nil {
+		return err
+	}
+
+	rootHashes := make([]ledger.RootHash, 0, len(records))
+	for rootHash, record := range records {
+		if record.seq >= l.nextSeq {
+			l.nextSeq = record.seq + 1
+		}
+		if rootHash != trie.EmptyTrieRootHash() {
+			rootHashes = append(rootHashes, rootHash)
+		}
+	}
+	sort.Slice(rootHashes, func(i, j int) bool {
+		return records[rootHashes[i]].seq < records[rootHashes[j]].seq
+	})
+
+	for _, rootHash := range rootHashes {
+		l.keep(rootHash)
+	}
+
+	// the capacity may have been lowered since the states were stored
+	return l.prune()
+}
+
+// ImportTrie stores the given trie, for example a trie loaded from a checkpoint, so that its
+// state can be read and updated.
+func (l *Ledger) ImportTrie(t *trie.MTrie) error {
+	err := l.store(t, make(map[*node.Node]uint64), nil)
+	if err != nil {
+		return fmt.Errorf("could not store trie: %w", err)
+	}
+	return nil
+}
+
+// ImportCheckpoint stores the tries of the given V6 checkpoint file in the given directory,
+// so that their states can be read and updated. Unlike loading the checkpoint and importing
+// its tries, the checkpoint is read and stored a batch of nodes at a time, so the tries are
+// never held in memory.
+func (l *Ledger) ImportCheckpoint(dir string, fileName string) error {
+	l.writeLock.Lock()
+	defer l.writeLock.Unlock()
+
+	stored, err := l.nodes.importCheckpoint(dir, fileName, l.nextSeq, l.logger)
+	if err != nil {
+		return fmt.Errorf("could not import checkpoint: %w", err)
+	}
+	l.nextSeq += uint64(len(stored))
+
+	for _, rootHash := range stored {
+		l.keep(rootHash)
+	}
+
+	return l.prune()
+}
+
+// store stores the given trie as the most recent state, and removes the least recently stored
+// states beyond the capacity. The nodes of the trie with an ID in the given map were loaded
+// from the stored trie with the given base root hash.
+func (l *Ledger) store(t *trie.MTrie, ids map[*node.Node]uint64, base *ledger.RootHash) error {
+	l.writeLock.Lock()
+	defer l.writeLock.Unlock()
+
+	// the loaded nodes are only guaranteed to still be stored if the base trie is
+	if base != nil {
+		found, err := l.nodes.hasRoot(*base)
+		if err != nil {
+			return fmt.Errorf("could not look up trie %s: %w", *base, err)
+		}
+		if !found {
+			return fmt.Errorf("trie with the given rootHash %s not found", *base)
+		}
+	}
+
+	rootHash := t.RootHash()
+	found, err := l.nodes.hasRoot(rootHash)
+	if err != nil {
+		return fmt.Errorf("could not look up trie %s: %w", rootHash, err)
+	}
+	if found {
+		err = l.nodes.touchRoot(rootHash, l.nextSeq)
+	} else {
+		err = l.nodes.storeTrie(t, ids, l.nextSeq)
+	}
+	if err != nil {
+		return err
+	}
+	l.nextSeq++
+
+	l.keep(rootHash)
+	return l.prune()
+}
+
+// keep makes the state with the given root hash the most recently stored state.
+// It must be called with the write lock held.
+func (l *Ledger) keep(rootHash ledger.RootHash) {
+	if rootHash == trie.EmptyTrieRootHash() {
+		return
+	}
+	if element, ok := l.elements[rootHash]; ok {
+		l.states.MoveToBack(element)
+		return
+	}
+	l.elements[rootHash] = l.states.PushBack(rootHash)
+}
+
+// prune removes the least recently stored states beyond the capacity, together with the nodes
+// which are no longer reachable from any kept state.
+// It must be called with the write lock held.
+func (l *Ledger) prune() error {
+	for l.capacity > 0 && l.states.Len() > l.capacity {
+		oldest := l.states.Front()
+		rootHash := oldest.Value.(ledger.RootHash)
+
+		l.pruneLock.Lock()
+		removed, err := l.nodes.removeTrie(rootHash)
+		l.pruneLock.Unlock()
+		if err != nil {
+			return fmt.Errorf("could not remove trie %s: %w", rootHash, err)
+		}
+
+		l.states.Remove(oldest)
+		delete(l.elements, rootHash)
+
+		l.logger.Debug().
+			Hex("state", rootHash[:]).
+			Int("removed_nodes", removed).
+			Msg("removed state")
+	}
+	return nil
+}
+
+// forest loads the parts of the trie with the given root hash needed for the given paths, and
+// returns a forest holding the loaded trie, on which the operation can be performed.
+func (l *Ledger) forest(rootHash ledger.RootHash, paths []ledger.Path) (*mtrie.Forest, map[*node.Node]uint64, error) {
+	l.pruneLock.RLock()
+	t, ids, err := l.nodes.loadTrie(rootHash, paths)
+	l.pruneLock.RUnlock()
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return nil, nil, fmt.Errorf("trie with the given rootHash %s not found", rootHash)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not load trie %s: %w", rootHash, err)
+	}
+
+	// the forest is only used for this operation, so it doesn't report metrics
+	forest, err := mtrie.NewForest(2, metrics.NewNoopCollector(), nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot create forest: %w", err)
+	}
+	err = forest.AddTrie(t)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to add trie to forest: %w", err)
+	}
+
+	return forest, ids, nil
+}
+
+// ValueSizes read the values of the given keys at the given state.
+// It returns value sizes in the same order as given registerIDs and errors (if any)
+func (l *Ledger) ValueSizes(query *ledger.Query) (valueSizes []int, err error) {
+	start := time.Now()
+	paths, err := pathfinder.KeysToPaths(query.Keys(), l.pathFinderVersion)
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return []int{}, nil
+	}
+
+	rootHash := ledger.RootHash(query.State())
+	forest, _, err := l.forest(rootHash, paths)
+	if err != nil {
+		return nil, err
+	}
+
+	valueSizes, err = forest.ValueSizes(&ledger.TrieRead{RootHash: rootHash, Paths: paths})
+	if err != nil {
+		return nil, err
+	}
+
+	l.metrics.ReadValuesNumber(uint64(len(paths)))
+	readDuration := time.Since(start)
+	l.metrics.ReadDuration(readDuration)
+
+	durationPerValue := time.Duration(readDuration.Nanoseconds()/int64(len(paths))) * time.Nanosecond
+	l.metrics.ReadDurationPerItem(durationPerValue)
+
+	return valueSizes, nil
+}
+
+// GetSingleValue reads value of a single given key at the given state.
+func (l *Ledger) GetSingleValue(query *ledger.QuerySingleValue) (value ledger.Value, err error) {
+	start := time.Now()
+	path, err := pathfinder.KeyToPath(query.Key(), l.pathFinderVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	rootHash := ledger.RootHash(query.State())
+	forest, _, err := l.forest(rootHash, []ledger.Path{path})
+	if err != nil {
+		return nil, err
+	}
+
+	value, err = forest.ReadSingleValue(&ledger.TrieReadSingleValue{RootHash: rootHash, Path: path})
+	if err != nil {
+		return nil, err
+	}
+
+	l.metrics.ReadValuesNumber(1)
+	readDuration := time.Since(start)
+	l.metrics.ReadDuration(readDuration)
+	l.metrics.ReadDurationPerItem(readDuration)
+
+	return value, nil
+}
+
+// Get read the values of the given keys at the given state
+// it returns the values in the same order as given registerIDs and errors (if any)
+func (l *Ledger) Get(query *ledger.Query) (values []ledger.Value, err error) {
+	start := time.Now()
+	paths, err := pathfinder.KeysToPaths(query.Keys(), l.pathFinderVersion)
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return []ledger.Value{}, nil
+	}
+
+	rootHash := ledger.RootHash(query.State())
+	forest, _, err := l.forest(rootHash, paths)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err = forest.Read(&ledger.TrieRead{RootHash: rootHash, Paths: paths})
+	if err != nil {
+		return nil, err
+	}
+
+	l.metrics.ReadValuesNumber(uint64(len(paths)))
+	readDuration := time.Since(start)
+	l.metrics.ReadDuration(readDuration)
+
+	durationPerValue := time.Duration(readDuration.Nanoseconds()/int64(len(paths))) * time.Nanosecond
+	l.metrics.ReadDurationPerItem(durationPerValue)
+
+	return values, nil
+}
+
+// Iterate returns a page of the registers whose keys start with the query prefix at the given
+// state, in path order. The NextStartAfter path of the result can be used to query the next page.
+// CAUTION: the registers with a prefix are not stored next to each other, so iterating them may
+// require loading every node of the state from disk, during which no states are removed.
+func (l *Ledger) Iterate(query *ledger.IterateQuery) (*ledger.IterateResult, error) {
+	start := time.Now()
+
+	l.pruneLock.RLock()
+	defer l.pruneLock.RUnlock()
+
+	rootHash := ledger.RootHash(query.State())
+	record, err := l.nodes.root(rootHash)
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return nil, fmt.Errorf("trie with the given rootHash %s not found", rootHash)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not load trie %s: %w", rootHash, err)
+	}
+
+	prefix := query.Prefix()
+	result := &ledger.IterateResult{}
+	var iterErr error
+	_, err = l.nodes.iterateLeaves(record.nodeID, query.StartAfter(), func(path ledger.Path, payload *ledger.Payload) bool {
+		key, err := payload.Key()
+		if err != nil {
+			iterErr = fmt.Errorf("could not decode key of payload at path %s: %w", path, err)
+			return false
+		}
+		if !key.HasPrefix(&prefix) {
+			return true
+		}
+
+		// the decoded key shares its data with the cached payload
+		result.Keys = append(result.Keys, key.DeepCopy())
+		result.Values = append(result.Values, payload.Value().DeepCopy())
+
+		if len(result.Keys) < query.Limit() {
+			return true
+		}
+
+		// the page is full, continue after this register
+		nextStartAfter := path
+		result.NextStartAfter = &nextStartAfter
+		return false
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not iterate trie %s: %w", rootHash, err)
+	}
+	if iterErr != nil {
+		return nil, iterErr
+	}
+
+	l.metrics.ReadValuesNumber(uint64(len(result.Values)))
+	l.metrics.ReadDuration(time.Since(start))
+
+	return result, nil
+}
+
+// Set updates the ledger given an update.
+// It returns the state after update and errors (if any)
+func (l *Ledger) Set(update *ledger.Update) (newState ledger.State, trieUpdate *ledger.TrieUpdate, err error) {
+	start := time.Now()
+
+	if update.Size() == 0 {
+		// return current state root unchanged
+		return update.State(), nil, nil
+	}
+
+	trieUpdate, err = pathfinder.UpdateToTrieUpdate(update, l.pathFinderVersion)
+	if err != nil {
+		return ledger.State(hash.DummyHash), nil, err
+	}
+
+	l.metrics.UpdateCount()
+
+	forest, ids, err := l.forest(trieUpdate.RootHash, trieUpdate.Paths)
+	if err != nil {
+		return ledger.State(hash.DummyHash), nil, err
+	}
+
+	newTrie, err := forest.NewTrie(trieUpdate)
+	if err != nil {
+		return ledger.State(hash.DummyHash), nil, fmt.Errorf("cannot update state: %w", err)
+	}
+
+	err = l.store(newTrie, ids, &trieUpdate.RootHash)
+	if err != nil {
+		return ledger.State(hash.DummyHash), nil, fmt.Errorf("cannot store updated state: %w", err)
+	}
+
+	l.metrics.LatestTrieRegCount(newTrie.AllocatedRegCount())
+	l.metrics.LatestTrieRegSize(newTrie.AllocatedRegSize())
+
+	elapsed := time.Since(start)
+	l.metrics.UpdateDuration(elapsed)
+
+	durationPerValue := time.Duration(elapsed.Nanoseconds() / int64(len(trieUpdate.Paths)))
+	l.metrics.UpdateDurationPerItem(durationPerValue)
+
+	newState = ledger.State(newTrie.RootHash())
+	state := update.State()
+	l.logger.Info().Hex("from", state[:]).
+		Hex("to", newState[:]).
+		Int("update_size", update.Size()).
+		Msg("ledger updated")
+	return newState, trieUpdate, nil
+}
+
+// Prove provides proofs for a ledger query and errors (if any).
+//
+// Proves are generally _not_ provided in the register order of the query.
+// The proofs are identical to the proofs of the complete ledger.
+func (l *Ledger) Prove(query *ledger.Query) (proof ledger.Proof, err error) {
+
+	paths, err := pathfinder.KeysToPaths(query.Keys(), l.pathFinderVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	rootHash := ledger.RootHash(query.State())
+	var batchProof *ledger.TrieBatchProof
+	if len(paths) == 0 {
+		batchProof = ledger.NewTrieBatchProof()
+	} else {
+		forest, _, err := l.forest(rootHash, paths)
+		if err != nil {
+			return nil, err
+		}
+
+		batchProof, err = forest.Proofs(&ledger.TrieRead{RootHash: rootHash, Paths: paths})
+		if err != nil {
+			return nil, fmt.Errorf("could not get proofs: %w", err)
+		}
+	}
+
+	proofToGo := ledger.EncodeTrieBatchProof(batchProof)
+
+	if len(paths) > 0 {
+		l.metrics.ProofSize(uint32(len(proofToGo) / len(paths)))
+	}
+
+	return proofToGo, nil
+}
@@ -0,0 +1,375 @@
+package disk_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/proof"
+	"github.com/onflow/flow-go/ledger/common/testutils"
+	"github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/wal"
+	"github.com/onflow/flow-go/ledger/complete/wal/fixtures"
+	"github.com/onflow/flow-go/ledger/disk"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// a small cache, so that most nodes are loaded from disk
+const testCacheSize = 16
+
+// the capacity of the complete ledger used for comparison
+const testCapacity = 1000
+
+func runWithLedgers(t *testing.T, f func(led *disk.Ledger, memLed *complete.Ledger)) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		led, err := disk.NewLedger(dir, testCacheSize, testCapacity, &metrics.NoopCollector{}, zerolog.Nop(), complete.DefaultPathFinderVersion)
+		require.NoError(t, err)
+
+		memLed, err := complete.NewLedger(&fixtures.NoopWAL{}, testCapacity, &metrics.NoopCollector{}, zerolog.Nop(), complete.DefaultPathFinderVersion)
+		require.NoError(t, err)
+		compactor := fixtures.NewNoopCompactor(memLed)
+		<-compactor.Ready()
+
+		defer func() {
+			<-led.Done()
+			<-memLed.Done()
+			<-compactor.Done()
+		}()
+
+		f(led, memLed)
+	})
+}
+
+// requireSameQueryResults checks that both ledgers return the same values, value sizes and proofs.
+func requireSameQueryResults(t *testing.T, led *disk.Ledger, memLed *complete.Ledger, state ledger.State, keys []ledger.Key) {
+	query, err := ledger.NewQuery(state, keys)
+	require.NoError(t, err)
+
+	values, err := led.Get(query)
+	require.NoError(t, err)
+	expectedValues, err := memLed.Get(query)
+	require.NoError(t, err)
+	require.Equal(t, expectedValues, values)
+
+	sizes, err := led.ValueSizes(query)
+	require.NoError(t, err)
+	expectedSizes, err := memLed.ValueSizes(query)
+	require.NoError(t, err)
+	require.Equal(t, expectedSizes, sizes)
+
+	p, err := led.Prove(query)
+	require.NoError(t, err)
+	expectedProof, err := memLed.Prove(query)
+	require.NoError(t, err)
+	require.Equal(t, expectedProof, p)
+
+	if len(keys) > 0 {
+		require.NoError(t, proof.VerifyEncodedBatchProof(p, keys, expectedValues, state, complete.DefaultPathFinderVersion))
+
+		single, err := ledger.NewQuerySingleValue(state, keys[0])
+		require.NoError(t, err)
+		value, err := led.GetSingleValue(single)
+		require.NoError(t, err)
+		expectedValue, err := memLed.GetSingleValue(single)
+		require.NoError(t, err)
+		require.Equal(t, expectedValue, value)
+	}
+}
+
+// TestLedger_MatchesCompleteLedger applies the same random updates to a disk ledger and a
+// complete ledger, including updates of existing registers, removals, and updates of older
+// states, and checks that both ledgers produce the same states, values and proofs.
+func TestLedger_MatchesCompleteLedger(t *testing.T) {
+	runWithLedgers(t, func(led *disk.Ledger, memLed *complete.Ledger) {
+		require.Equal(t, memLed.InitialState(), led.InitialState())
+		require.True(t, led.HasState(led.InitialState()))
+
+		states := []ledger.State{led.InitialState()}
+		var allKeys []ledger.Key
+
+		for step := 0; step < 30; step++ {
+			// update a random recent state, which creates forks
+			state := states[len(states)-1-rand.Intn(minInt(len(states), 3))]
+
+			keys := testutils.RandomUniqueKeys(20, 2, 1, 10)
+			values := testutils.RandomValues(20, 1, 100)
+
+			// update and remove some existing registers
+			for i, j := range rand.Perm(len(allKeys))[:minInt(len(allKeys), 10)] {
+				keys = append(keys, allKeys[j])
+				if i%2 == 0 {
+					values = append(values, ledger.Value{})
+				} else {
+					values = append(values, testutils.RandomValues(1, 1, 100)...)
+				}
+			}
+
+			update, err := ledger.NewUpdate(state, keys, values)
+			require.NoError(t, err)
+
+			newState, trieUpdate, err := led.Set(update)
+			require.NoError(t, err)
+			expectedState, expectedTrieUpdate, err := memLed.Set(update)
+			require.NoError(t, err)
+
+			require.Equal(t, expectedState, newState)
+			require.Equal(t, expectedTrieUpdate, trieUpdate)
+			require.True(t, led.HasState(newState))
+
+			allKeys = append(allKeys, keys[:20]...)
+			states = append(states, newState)
+
+			// query updated, existing and missing registers at the new and an older state
+			queryKeys := append(keys, testutils.RandomUniqueKeys(5, 2, 1, 10)...)
+			requireSameQueryResults(t, led, memLed, newState, queryKeys)
+			requireSameQueryResults(t, led, memLed, states[rand.Intn(len(states))], allKeys)
+		}
+
+		requireSameQueryResults(t, led, memLed, led.InitialState(), allKeys)
+		requireSameQueryResults(t, led, memLed, states[len(states)-1], nil)
+	})
+}
+
+func TestLedger_Reopen(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		led, err := disk.NewLedger(dir, testCacheSize, testCapacity, &metrics.NoopCollector{}, zerolog.Nop(), complete.DefaultPathFinderVersion)
+		require.NoError(t, err)
+
+		keys := testutils.RandomUniqueKeys(100, 2, 1, 10)
+		values := testutils.RandomValues(100, 1, 100)
+
+		update, err := ledger.NewUpdate(led.InitialState(), keys, values)
+		require.NoError(t, err)
+		state, _, err := led.Set(update)
+		require.NoError(t, err)
+
+		<-led.Done()
+
+		led, err = disk.NewLedger(dir, testCacheSize, testCapacity, &metrics.NoopCollector{}, zerolog.Nop(), complete.DefaultPathFinderVersion)
+		require.NoError(t, err)
+		defer func() { <-led.Done() }()
+
+		require.True(t, led.HasState(state))
+
+		query, err := ledger.NewQuery(state, keys)
+		require.NoError(t, err)
+		retValues, err := led.Get(query)
+		require.NoError(t, err)
+		require.Equal(t, values, retValues)
+
+		// new nodes don't overwrite the nodes stored before reopening
+		update, err = ledger.NewUpdate(state, testutils.RandomUniqueKeys(100, 2, 1, 10), testutils.RandomValues(100, 1, 100))
+		require.NoError(t, err)
+		_, _, err = led.Set(update)
+		require.NoError(t, err)
+
+		retValues, err = led.Get(query)
+		require.NoError(t, err)
+		require.Equal(t, values, retValues)
+	})
+}
+
+func TestLedger_ImportTrie(t *testing.T) {
+	runWithLedgers(t, func(led *disk.Ledger, memLed *complete.Ledger) {
+		keys := testutils.RandomUniqueKeys(100, 2, 1, 10)
+		values := testutils.RandomValues(100, 1, 100)
+
+		update, err := ledger.NewUpdate(memLed.InitialState(), keys, values)
+		require.NoError(t, err)
+		state, _, err := memLed.Set(update)
+		require.NoError(t, err)
+
+		require.False(t, led.HasState(state))
+
+		tries, err := memLed.Tries()
+		require.NoError(t, err)
+		for _, tr := range tries {
+			require.NoError(t, led.ImportTrie(tr))
+		}
+
+		require.True(t, led.HasState(state))
+		requireSameQueryResults(t, led, memLed, state, append(keys, testutils.RandomUniqueKeys(5, 2, 1, 10)...))
+
+		// importing a trie twice is a no-op
+		require.NoError(t, led.ImportTrie(tries[len(tries)-1]))
+	})
+}
+
+// TestLedger_Prune checks that only the most recently stored states are kept, and that the kept
+// states are not affected by removing the nodes of older states.
+func TestLedger_Prune(t *testing.T) {
+	runWithLedgers(t, func(_ *disk.Ledger, memLed *complete.Ledger) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			const capacity = 5
+			led, err := disk.NewLedger(dir, testCacheSize, capacity, &metrics.NoopCollector{}, zerolog.Nop(), complete.DefaultPathFinderVersion)
+			require.NoError(t, err)
+			defer func() { <-led.Done() }()
+
+			states := []ledger.State{led.InitialState()}
+			allKeys := testutils.RandomUniqueKeys(50, 2, 1, 10)
+
+			for step := 0; step < 20; step++ {
+				// update the latest or the previous state, and overwrite existing registers
+				state := states[len(states)-1-rand.Intn(minInt(len(states), 2))]
+				keys := append(testutils.RandomUniqueKeys(10, 2, 1, 10), allKeys[rand.Intn(40):][:10]...)
+
+				update, err := ledger.NewUpdate(state, keys, testutils.RandomValues(len(keys), 1, 100))
+				require.NoError(t, err)
+
+				newState, _, err := led.Set(update)
+				require.NoError(t, err)
+				expectedState, _, err := memLed.Set(update)
+				require.NoError(t, err)
+				require.Equal(t, expectedState, newState)
+
+				states = append(states, newState)
+
+				// the initial state is always kept
+				require.True(t, led.HasState(led.InitialState()))
+				for i, s := range states[1:] {
+					kept := i >= len(states)-1-capacity
+					require.Equal(t, kept, led.HasState(s), "state %d of %d", i, len(states)-1)
+					if kept {
+						requireSameQueryResults(t, led, memLed, s, allKeys)
+					}
+				}
+			}
+
+			// updating a removed state fails
+			update, err := ledger.NewUpdate(states[1], allKeys[:1], testutils.RandomValues(1, 1, 10))
+			require.NoError(t, err)
+			_, _, err = led.Set(update)
+			require.Error(t, err)
+		})
+	})
+}
+
+func TestLedger_ImportCheckpoint(t *testing.T) {
+	runWithLedgers(t, func(led *disk.Ledger, memLed *complete.Ledger) {
+		unittest.RunWithTempDir(t, func(checkpointDir string) {
+			var states []ledger.State
+			var allKeys []ledger.Key
+			state := memLed.InitialState()
+			for i := 0; i < 3; i++ {
+				keys := testutils.RandomUniqueKeys(100, 2, 1, 10)
+				update, err := ledger.NewUpdate(state, keys, testutils.RandomValues(100, 1, 100))
+				require.NoError(t, err)
+				state, _, err = memLed.Set(update)
+				require.NoError(t, err)
+
+				states = append(states, state)
+				allKeys = append(allKeys, keys...)
+			}
+
+			tries, err := memLed.Tries()
+			require.NoError(t, err)
+			logger := zerolog.Nop()
+			require.NoError(t, wal.StoreCheckpointV6Concurrently(tries, checkpointDir, "root.checkpoint", &logger))
+
+			require.NoError(t, led.ImportCheckpoint(checkpointDir, "root.checkpoint"))
+
+			for _, state := range states {
+				require.True(t, led.HasState(state))
+				requireSameQueryResults(t, led, memLed, state, append(allKeys, testutils.RandomUniqueKeys(5, 2, 1, 10)...))
+			}
+
+			// imported states can be updated
+			update, err := ledger.NewUpdate(states[1], allKeys[:10], testutils.RandomValues(10, 1, 100))
+			require.NoError(t, err)
+			newState, _, err := led.Set(update)
+			require.NoError(t, err)
+			expectedState, _, err := memLed.Set(update)
+			require.NoError(t, err)
+			require.Equal(t, expectedState, newState)
+			requireSameQueryResults(t, led, memLed, newState, allKeys)
+
+			// importing a checkpoint twice is a no-op
+			require.NoError(t, led.ImportCheckpoint(checkpointDir, "root.checkpoint"))
+			requireSameQueryResults(t, led, memLed, states[2], allKeys)
+		})
+	})
+}
+
+func TestLedger_Iterate(t *testing.T) {
+	runWithLedgers(t, func(led *disk.Ledger, memLed *complete.Ledger) {
+		owner1 := []byte("owner1")
+		owner2 := []byte("owner2")
+
+		var keys []ledger.Key
+		var values []ledger.Value
+		for i := 0; i < 20; i++ {
+			for _, owner := range [][]byte{owner1, owner2} {
+				keys = append(keys, ledger.NewKey([]ledger.KeyPart{
+					ledger.NewKeyPart(0, owner),
+					ledger.NewKeyPart(2, []byte(fmt.Sprintf("key%d", i))),
+				}))
+				values = append(values, ledger.Value(fmt.Sprintf("%s-%d", owner, i)))
+			}
+		}
+
+		update, err := ledger.NewUpdate(led.InitialState(), keys, values)
+		require.NoError(t, err)
+		state, _, err := led.Set(update)
+		require.NoError(t, err)
+		_, _, err = memLed.Set(update)
+		require.NoError(t, err)
+
+		prefix := ledger.NewKey([]ledger.KeyPart{ledger.NewKeyPart(0, owner1)})
+
+		var startAfter *ledger.Path
+		pages := 0
+		for {
+			query, err := ledger.NewIterateQuery(state, prefix, startAfter, 7)
+			require.NoError(t, err)
+
+			result, err := led.Iterate(query)
+			require.NoError(t, err)
+			expected, err := memLed.Iterate(query)
+			require.NoError(t, err)
+			require.Equal(t, expected, result)
+			pages++
+
+			if result.NextStartAfter == nil {
+				break
+			}
+			startAfter = result.NextStartAfter
+		}
+		assert.Equal(t, 3, pages)
+	})
+}
+
+func TestLedger_UnknownState(t *testing.T) {
+	runWithLedgers(t, func(led *disk.Ledger, _ *complete.Ledger) {
+		state := ledger.State(unittest.StateCommitmentFixture())
+		keys := testutils.RandomUniqueKeys(1, 2, 1, 10)
+
+		require.False(t, led.HasState(state))
+
+		query, err := ledger.NewQuery(state, keys)
+		require.NoError(t, err)
+
+		_, err = led.Get(query)
+		require.Error(t, err)
+
+		_, err = led.Prove(query)
+		require.Error(t, err)
+
+		update, err := ledger.NewUpdate(state, keys, testutils.RandomValues(1, 1, 10))
+		require.NoError(t, err)
+		_, _, err = led.Set(update)
+		require.Error(t, err)
+	})
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
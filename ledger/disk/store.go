@@ -0,0 +1,755 @@
+package disk
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/dgraph-io/badger/v2"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/bitutils"
+	"github.com/onflow/flow-go/ledger/common/hash"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/flattener"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/node"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+	"github.com/onflow/flow-go/ledger/complete/wal"
+)
+
+// badger key prefixes
+const (
+	prefixNode       byte = iota + 1 // encoded trie nodes, by node ID
+	prefixRoot                       // root node ID, register stats and sequence number, by root hash
+	prefixNextNodeID                 // the next unused node ID
+	prefixRefCount                   // number of references to a node by stored nodes and roots, by node ID
+)
+
+// nilNodeID is the ID of an empty subtrie. Stored nodes have IDs starting at 1, which matches
+// the child indices of the flattener encoding, where 0 represents a missing child.
+const nilNodeID uint64 = 0
+
+const rootRecordLength = 8 + 8 + 8 + 8
+
+// number of checkpoint nodes written to disk at once when importing a checkpoint
+const importBatchSize = 100_000
+
+// unloaded is the placeholder child of interim nodes whose subtrie was not loaded from disk.
+// Such nodes are only used as siblings of the nodes on the paths of an operation, for which the
+// trie algorithms only need the node's height and hash. The placeholder makes sure they are
+// never mistaken for leaves.
+var unloaded = node.NewNode(0, nil, nil, ledger.DummyPath, nil, hash.DummyHash)
+
+// storedNode is a node as stored on disk, with its children referenced by node ID.
+type storedNode struct {
+	height int
+	hash   hash.Hash
+	leaf   *node.Node // the complete node, for leaves only
+	left   uint64
+	right  uint64
+}
+
+// rootRecord references the root node of a trie, and holds its register stats. The sequence
+// number orders the tries by the time they were last stored.
+type rootRecord struct {
+	nodeID   uint64
+	regCount uint64
+	regSize  uint64
+	seq      uint64
+}
+
+// nodeStore stores the nodes of all tries in badger. Nodes are immutable, and shared between
+// tries just like in the in-memory forest: an update only stores the nodes it created.
+// Each node has a reference count of the stored nodes and tries referencing it, so that the
+// nodes which are no longer reachable from any stored trie are removed with the last trie
+// referencing them. Decoded nodes are cached by ID.
+//
+// Reads are safe for concurrent use. Writes (storing, importing and removing tries) must not be
+// concurrent with each other, and removing tries must not be concurrent with reading them.
+type nodeStore struct {
+	db    *badger.DB
+	cache *lru.Cache
+	// nextID is the next unused node ID. IDs allocated by an interrupted write are not persisted,
+	// so they are allocated again.
+	nextID uint64
+}
+
+func newNodeStore(db *badger.DB, cacheSize int) (*nodeStore, error) {
+	cache, err := lru.New(cacheSize)
+	if err != nil {
+		return nil, fmt.Errorf("could not create node cache: %w", err)
+	}
+
+	nextID := nilNodeID + 1
+	err = db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte{prefixNextNodeID})
+		if errors.Is(err, badger.ErrKeyNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return item.Value(func(val []byte) error {
+			if len(val) != 8 {
+				return fmt.Errorf("invalid next node ID length %d", len(val))
+			}
+			nextID = binary.BigEndian.Uint64(val)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not read next node ID: %w", err)
+	}
+
+	return &nodeStore{
+		db:     db,
+		cache:  cache,
+		nextID: nextID,
+	}, nil
+}
+
+func makeNodeKey(id uint64) []byte {
+	key := make([]byte, 1+8)
+	key[0] = prefixNode
+	binary.BigEndian.PutUint64(key[1:], id)
+	return key
+}
+
+func makeRootKey(rootHash ledger.RootHash) []byte {
+	key := make([]byte, 1+hash.HashLen)
+	key[0] = prefixRoot
+	copy(key[1:], rootHash[:])
+	return key
+}
+
+func makeRefCountKey(id uint64) []byte {
+	key := make([]byte, 1+8)
+	key[0] = prefixRefCount
+	binary.BigEndian.PutUint64(key[1:], id)
+	return key
+}
+
+func encodeUint64(v uint64) []byte {
+	value := make([]byte, 8)
+	binary.BigEndian.PutUint64(value, v)
+	return value
+}
+
+func encodeRootRecord(record *rootRecord) []byte {
+	value := make([]byte, rootRecordLength)
+	binary.BigEndian.PutUint64(value, record.nodeID)
+	binary.BigEndian.PutUint64(value[8:], record.regCount)
+	binary.BigEndian.PutUint64(value[16:], record.regSize)
+	binary.BigEndian.PutUint64(value[24:], record.seq)
+	return value
+}
+
+func decodeRootRecord(value []byte) (*rootRecord, error) {
+	if len(value) != rootRecordLength {
+		return nil, fmt.Errorf("invalid root record length %d", len(value))
+	}
+	return &rootRecord{
+		nodeID:   binary.BigEndian.Uint64(value),
+		regCount: binary.BigEndian.Uint64(value[8:]),
+		regSize:  binary.BigEndian.Uint64(value[16:]),
+		seq:      binary.BigEndian.Uint64(value[24:]),
+	}, nil
+}
+
+// root returns the root record of the trie with the given root hash.
+// Expected errors during normal operations:
+//   - badger.ErrKeyNotFound if the trie is not stored
+func (s *nodeStore) root(rootHash ledger.RootHash) (*rootRecord, error) {
+	var record *rootRecord
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(makeRootKey(rootHash))
+		if err != nil {
+			return err
+		}
+		return item.Value(func(val []byte) error {
+			record, err = decodeRootRecord(val)
+			return err
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// roots returns the root records of all stored tries, by root hash.
+func (s *nodeStore) roots() (map[ledger.RootHash]*rootRecord, error) {
+	records := make(map[ledger.RootHash]*rootRecord)
+	err := s.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.IteratorOptions{Prefix: []byte{prefixRoot}})
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			var rootHash ledger.RootHash
+			copy(rootHash[:], it.Item().Key()[1:])
+
+			err := it.Item().Value(func(val []byte) error {
+				record, err := decodeRootRecord(val)
+				if err != nil {
+					return fmt.Errorf("could not decode root record of trie %s: %w", rootHash, err)
+				}
+				records[rootHash] = record
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// touchRoot updates the sequence number of the stored trie with the given root hash.
+func (s *nodeStore) touchRoot(rootHash ledger.RootHash, seq uint64) error {
+	record, err := s.root(rootHash)
+	if err != nil {
+		return err
+	}
+	record.seq = seq
+	return s.db.Update(func(txn *badger.Txn) error {
+		return txn.Set(makeRootKey(rootHash), encodeRootRecord(record))
+	})
+}
+
+func (s *nodeStore) hasRoot(rootHash ledger.RootHash) (bool, error) {
+	_, err := s.root(rootHash)
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// node returns the stored node with the given ID.
+func (s *nodeStore) node(id uint64) (*storedNode, error) {
+	if cached, ok := s.cache.Get(id); ok {
+		return cached.(*storedNode), nil
+	}
+
+	var stored *storedNode
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(makeNodeKey(id))
+		if err != nil {
+			return err
+		}
+		return item.Value(func(val []byte) error {
+			stored, err = decodeNode(val)
+			return err
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not load node %d: %w", id, err)
+	}
+
+	s.cache.Add(id, stored)
+	return stored, nil
+}
+
+// decodeNode decodes a node in the flattener encoding. The payload of leaves is copied, so the
+// returned node does not reference the given buffer.
+func decodeNode(encoded []byte) (*storedNode, error) {
+	var children []uint64
+	n, err := flattener.ReadNode(bytes.NewReader(encoded), nil, func(index uint64) (*node.Node, error) {
+		children = append(children, index)
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stored := &storedNode{
+		height: n.Height(),
+		hash:   n.Hash(),
+	}
+	if len(children) == 0 {
+		stored.leaf = n
+		return stored, nil
+	}
+	stored.left, stored.right = children[0], children[1]
+	return stored, nil
+}
+
+// loadTrie loads the parts of the trie with the given root hash which are needed to operate on
+// the given paths: all nodes on the paths, and their siblings. The subtries of siblings are not
+// loaded, see `unloaded`. The returned map holds the IDs of the loaded nodes, so that they are
+// not stored again when a trie derived from the loaded trie is stored.
+// Expected errors during normal operations:
+//   - badger.ErrKeyNotFound if the trie is not stored
+func (s *nodeStore) loadTrie(rootHash ledger.RootHash, paths []ledger.Path) (*trie.MTrie, map[*node.Node]uint64, error) {
+	record, err := s.root(rootHash)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ids := make(map[*node.Node]uint64)
+	root, err := s.load(record.nodeID, paths, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	t, err := trie.NewMTrie(root, record.regCount, record.regSize)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not create trie: %w", err)
+	}
+	return t, ids, nil
+}
+
+func (s *nodeStore) load(id uint64, paths []ledger.Path, ids map[*node.Node]uint64) (*node.Node, error) {
+	if id == nilNodeID {
+		return nil, nil
+	}
+
+	stored, err := s.node(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if stored.leaf != nil {
+		ids[stored.leaf] = id
+		return stored.leaf, nil
+	}
+
+	if len(paths) == 0 {
+		n := node.NewNode(stored.height, unloaded, nil, ledger.DummyPath, nil, stored.hash)
+		ids[n] = id
+		return n, nil
+	}
+
+	depth := ledger.NodeMaxHeight - stored.height // distance to the tree root
+	var lpaths, rpaths []ledger.Path
+	for _, path := range paths {
+		if bitutils.ReadBit(path[:], depth) == 0 {
+			lpaths = append(lpaths, path)
+		} else {
+			rpaths = append(rpaths, path)
+		}
+	}
+
+	left, err := s.load(stored.left, lpaths, ids)
+	if err != nil {
+		return nil, err
+	}
+	right, err := s.load(stored.right, rpaths, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	n := node.NewNode(stored.height, left, right, ledger.DummyPath, nil, stored.hash)
+	ids[n] = id
+	return n, nil
+}
+
+// storeTrie stores the given trie with the given sequence number. The trie must not be stored
+// yet. Nodes with an ID in the given map are already stored, all other nodes are stored with a
+// new ID. The root record is only written once all nodes are stored, so an interrupted write
+// leaves no partially stored trie behind. It may however leave reference counts of previously
+// stored nodes too high, which prevents them from being removed, but never removes a reachable node.
+func (s *nodeStore) storeTrie(t *trie.MTrie, ids map[*node.Node]uint64, seq uint64) error {
+	batch := s.db.NewWriteBatch()
+	defer batch.Cancel()
+
+	firstNewID := s.nextID
+	nextID := s.nextID
+	refs := make(map[uint64]uint64)
+
+	var scratch []byte
+	var storeNode func(n *node.Node) (uint64, error)
+	storeNode = func(n *node.Node) (uint64, error) {
+		if n == nil {
+			return nilNodeID, nil
+		}
+		if id, ok := ids[n]; ok {
+			return id, nil
+		}
+
+		left, err := storeNode(n.LeftChild())
+		if err != nil {
+			return 0, err
+		}
+		right, err := storeNode(n.RightChild())
+		if err != nil {
+			return 0, err
+		}
+
+		id := nextID
+		nextID++
+
+		encoded := flattener.EncodeNode(n, left, right, scratch)
+		err = batch.Set(makeNodeKey(id), append([]byte(nil), encoded...))
+		if err != nil {
+			return 0, fmt.Errorf("could not store node %d: %w", id, err)
+		}
+		// keep the larger buffer, if the encoding had to allocate one
+		scratch = encoded[:cap(encoded)]
+
+		addRef(refs, left)
+		addRef(refs, right)
+
+		ids[n] = id
+		return id, nil
+	}
+
+	rootID, err := storeNode(t.RootNode())
+	if err != nil {
+		return err
+	}
+	addRef(refs, rootID)
+
+	err = s.addRefs(batch, refs, firstNewID)
+	if err != nil {
+		return err
+	}
+
+	err = batch.Flush()
+	if err != nil {
+		return fmt.Errorf("could not write nodes: %w", err)
+	}
+
+	record := &rootRecord{
+		nodeID:   rootID,
+		regCount: t.AllocatedRegCount(),
+		regSize:  t.AllocatedRegSize(),
+		seq:      seq,
+	}
+	err = s.db.Update(func(txn *badger.Txn) error {
+		err := txn.Set(makeRootKey(t.RootHash()), encodeRootRecord(record))
+		if err != nil {
+			return err
+		}
+		return txn.Set([]byte{prefixNextNodeID}, encodeUint64(nextID))
+	})
+	if err != nil {
+		return fmt.Errorf("could not write root record: %w", err)
+	}
+
+	s.nextID = nextID
+	return nil
+}
+
+// importCheckpoint stores the tries of the given V6 checkpoint which are not stored yet, reading
+// the checkpoint one node at a time and writing the nodes in batches, so that the tries of the
+// checkpoint are never held in memory. The stored tries are given consecutive sequence numbers
+// starting at firstSeq, in the order of the checkpoint. It returns their root hashes.
+func (s *nodeStore) importCheckpoint(dir string, fileName string, firstSeq uint64, logger zerolog.Logger) ([]ledger.RootHash, error) {
+	// the checkpoint index of a node determines its ID, so that nodes can be written as soon as
+	// they are read
+	base := s.nextID - 1
+	nextID := s.nextID
+
+	batch := s.db.NewWriteBatch()
+	defer func() {
+		batch.Cancel()
+	}()
+
+	// nodes with an ID of at least firstBatchID are written by the current batch
+	firstBatchID := nextID
+	batchNodes := 0
+	refs := make(map[uint64]uint64)
+
+	flush := func() error {
+		err := s.addRefs(batch, refs, firstBatchID)
+		if err != nil {
+			return err
+		}
+		err = batch.Flush()
+		if err != nil {
+			return fmt.Errorf("could not write nodes: %w", err)
+		}
+
+		batch = s.db.NewWriteBatch()
+		firstBatchID = nextID
+		batchNodes = 0
+		refs = make(map[uint64]uint64)
+		return nil
+	}
+
+	nodeID := func(index uint64) uint64 {
+		if index == 0 {
+			return nilNodeID
+		}
+		return base + index
+	}
+
+	var scratch []byte
+	var tries []*wal.CheckpointTrie
+	err := wal.ReadNodesFromCheckpointV6(dir, fileName, &logger,
+		func(n *wal.CheckpointNode) error {
+			id := nodeID(n.Index)
+			left, right := nodeID(n.LeftIndex), nodeID(n.RightIndex)
+
+			encoded := flattener.EncodeNode(n.Node, left, right, scratch)
+			err := batch.Set(makeNodeKey(id), append([]byte(nil), encoded...))
+			if err != nil {
+				return fmt.Errorf("could not store node %d: %w", id, err)
+			}
+			scratch = encoded[:cap(encoded)]
+
+			addRef(refs, left)
+			addRef(refs, right)
+
+			if id >= nextID {
+				nextID = id + 1
+			}
+			batchNodes++
+			if batchNodes >= importBatchSize {
+				return flush()
+			}
+			return nil
+		},
+		func(t *wal.CheckpointTrie) error {
+			tries = append(tries, t)
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("could not read checkpoint: %w", err)
+	}
+
+	// tries which are already stored are not stored again, so their root nodes are only
+	// referenced temporarily, and removed below unless shared with other tries
+	var stored []ledger.RootHash
+	var skippedRootIDs []uint64
+	records := make(map[ledger.RootHash]*rootRecord)
+	for _, t := range tries {
+		rootID := nodeID(t.RootIndex)
+		addRef(refs, rootID)
+
+		_, seen := records[t.RootHash]
+		found, err := s.hasRoot(t.RootHash)
+		if err != nil {
+			return nil, fmt.Errorf("could not look up trie %s: %w", t.RootHash, err)
+		}
+		if found || seen {
+			skippedRootIDs = append(skippedRootIDs, rootID)
+			continue
+		}
+
+		records[t.RootHash] = &rootRecord{
+			nodeID:   rootID,
+			regCount: t.RegCount,
+			regSize:  t.RegSize,
+			seq:      firstSeq + uint64(len(stored)),
+		}
+		stored = append(stored, t.RootHash)
+	}
+
+	err = flush()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.db.Update(func(txn *badger.Txn) error {
+		for _, rootHash := range stored {
+			err := txn.Set(makeRootKey(rootHash), encodeRootRecord(records[rootHash]))
+			if err != nil {
+				return err
+			}
+		}
+		return txn.Set([]byte{prefixNextNodeID}, encodeUint64(nextID))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not write root records: %w", err)
+	}
+	s.nextID = nextID
+
+	for _, rootID := range skippedRootIDs {
+		_, err = s.release(rootID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return stored, nil
+}
+
+// removeTrie removes the trie with the given root hash, and all nodes which are no longer
+// referenced by any other stored trie. It returns the number of removed nodes.
+// The root record is removed first, so an interrupted removal may leave unreachable nodes
+// behind, but never removes a reachable node.
+// Expected errors during normal operations:
+//   - badger.ErrKeyNotFound if the trie is not stored
+func (s *nodeStore) removeTrie(rootHash ledger.RootHash) (int, error) {
+	record, err := s.root(rootHash)
+	if err != nil {
+		return 0, err
+	}
+
+	err = s.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete(makeRootKey(rootHash))
+	})
+	if err != nil {
+		return 0, fmt.Errorf("could not remove root record: %w", err)
+	}
+
+	return s.release(record.nodeID)
+}
+
+// release removes a reference to the node with the given ID. Nodes which are no longer
+// referenced are removed, and release the references to their children in turn.
+// It returns the number of removed nodes.
+func (s *nodeStore) release(id uint64) (int, error) {
+	if id == nilNodeID {
+		return 0, nil
+	}
+
+	batch := s.db.NewWriteBatch()
+	defer batch.Cancel()
+
+	removed := 0
+	stack := []uint64{id}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		var count uint64
+		err := s.db.View(func(txn *badger.Txn) error {
+			var err error
+			count, err = refCount(txn, id)
+			return err
+		})
+		if err != nil {
+			return 0, fmt.Errorf("could not read reference count of node %d: %w", id, err)
+		}
+
+		// a node is only reachable once from the removed trie, so its reference count is
+		// never decremented twice by the same removal
+		if count > 1 {
+			err = batch.Set(makeRefCountKey(id), encodeUint64(count-1))
+			if err != nil {
+				return 0, fmt.Errorf("could not update reference count of node %d: %w", id, err)
+			}
+			continue
+		}
+
+		stored, err := s.node(id)
+		if err != nil {
+			return 0, err
+		}
+		if stored.leaf == nil {
+			if stored.left != nilNodeID {
+				stack = append(stack, stored.left)
+			}
+			if stored.right != nilNodeID {
+				stack = append(stack, stored.right)
+			}
+		}
+
+		err = batch.Delete(makeNodeKey(id))
+		if err != nil {
+			return 0, fmt.Errorf("could not remove node %d: %w", id, err)
+		}
+		err = batch.Delete(makeRefCountKey(id))
+		if err != nil {
+			return 0, fmt.Errorf("could not remove reference count of node %d: %w", id, err)
+		}
+		s.cache.Remove(id)
+		removed++
+	}
+
+	err := batch.Flush()
+	if err != nil {
+		return 0, fmt.Errorf("could not write removal of nodes: %w", err)
+	}
+	return removed, nil
+}
+
+func addRef(refs map[uint64]uint64, id uint64) {
+	if id != nilNodeID {
+		refs[id]++
+	}
+}
+
+// addRefs adds the given numbers of references to the reference counts of the nodes.
+// Nodes with an ID of at least firstNewID are written by the given batch, so they have
+// no stored reference count yet.
+func (s *nodeStore) addRefs(batch *badger.WriteBatch, refs map[uint64]uint64, firstNewID uint64) error {
+	return s.db.View(func(txn *badger.Txn) error {
+		for id, count := range refs {
+			if id < firstNewID {
+				stored, err := refCount(txn, id)
+				if err != nil {
+					return fmt.Errorf("could not read reference count of node %d: %w", id, err)
+				}
+				count += stored
+			}
+
+			err := batch.Set(makeRefCountKey(id), encodeUint64(count))
+			if err != nil {
+				return fmt.Errorf("could not update reference count of node %d: %w", id, err)
+			}
+		}
+		return nil
+	})
+}
+
+// refCount returns the stored reference count of the node with the given ID, which is 0
+// if none is stored.
+func refCount(txn *badger.Txn, id uint64) (uint64, error) {
+	item, err := txn.Get(makeRefCountKey(id))
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var count uint64
+	err = item.Value(func(val []byte) error {
+		if len(val) != 8 {
+			return fmt.Errorf("invalid reference count length %d", len(val))
+		}
+		count = binary.BigEndian.Uint64(val)
+		return nil
+	})
+	return count, err
+}
+
+// iterateLeaves iterates the leaves of the stored subtrie with the given root node ID in
+// ascending path order, like trie.MTrie.IterateLeaves, but only loads the nodes it visits.
+// It returns false if the iteration was stopped by fn.
+func (s *nodeStore) iterateLeaves(
+	id uint64,
+	startAfter *ledger.Path,
+	fn func(path ledger.Path, payload *ledger.Payload) bool,
+) (bool, error) {
+	if id == nilNodeID {
+		return true, nil
+	}
+
+	stored, err := s.node(id)
+	if err != nil {
+		return false, err
+	}
+
+	if stored.leaf != nil {
+		path := *stored.leaf.Path()
+		if startAfter != nil && bytes.Compare(path[:], startAfter[:]) <= 0 {
+			return true, nil
+		}
+		return fn(path, stored.leaf.Payload()), nil
+	}
+
+	// only the subtrie on the path to startAfter can contain paths before it
+	depth := ledger.NodeMaxHeight - stored.height // distance to the tree root
+	if startAfter != nil && bitutils.ReadBit(startAfter[:], depth) == 1 {
+		return s.iterateLeaves(stored.right, startAfter, fn)
+	}
+
+	more, err := s.iterateLeaves(stored.left, startAfter, fn)
+	if err != nil || !more {
+		return more, err
+	}
+	return s.iterateLeaves(stored.right, nil, fn)
+}
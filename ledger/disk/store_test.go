@@ -0,0 +1,173 @@
+package disk
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/testutils"
+	"github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/node"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+	"github.com/onflow/flow-go/ledger/complete/wal"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestNodeStore_RemovesUnreachableNodes checks that after each update, import and removal of
+// states, exactly the nodes reachable from the kept states are stored, with the number of
+// references to them as reference count.
+func TestNodeStore_RemovesUnreachableNodes(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		led, err := NewLedger(dir, 16, 3, &metrics.NoopCollector{}, zerolog.Nop(), complete.DefaultPathFinderVersion)
+		require.NoError(t, err)
+
+		states := []ledger.State{led.InitialState()}
+		allKeys := testutils.RandomUniqueKeys(30, 2, 1, 10)
+		for step := 0; step < 15; step++ {
+			// fork from recent states, and overwrite existing registers
+			state := states[len(states)-1-rand.Intn(minInt(len(states), 3))]
+			if !led.HasState(state) {
+				state = states[len(states)-1]
+			}
+			keys := append(testutils.RandomUniqueKeys(5, 2, 1, 10), allKeys[rand.Intn(20):][:10]...)
+
+			update, err := ledger.NewUpdate(state, keys, testutils.RandomValues(len(keys), 1, 100))
+			require.NoError(t, err)
+			newState, _, err := led.Set(update)
+			require.NoError(t, err)
+			states = append(states, newState)
+
+			requireConsistentStore(t, led.nodes)
+		}
+
+		// importing a checkpoint removes the least recently stored states
+		tries := []*trie.MTrie{trie.NewEmptyMTrie()}
+		for i := 0; i < 2; i++ {
+			paths := testutils.RandomPaths(20)
+			payloads := testutils.RandomPayloads(20, 1, 100)
+			updated, _, err := trie.NewTrieWithUpdatedRegisters(tries[len(tries)-1], paths, derefPayloads(payloads), true)
+			require.NoError(t, err)
+			tries = append(tries, updated)
+		}
+		unittest.RunWithTempDir(t, func(checkpointDir string) {
+			logger := zerolog.Nop()
+			require.NoError(t, wal.StoreCheckpointV6Concurrently(tries, checkpointDir, "checkpoint", &logger))
+			require.NoError(t, led.ImportCheckpoint(checkpointDir, "checkpoint"))
+		})
+		requireConsistentStore(t, led.nodes)
+		require.Equal(t, 3, led.states.Len())
+		require.True(t, led.HasState(ledger.State(tries[1].RootHash())))
+		require.True(t, led.HasState(ledger.State(tries[2].RootHash())))
+
+		<-led.Done()
+
+		// reopening with a lower capacity removes the least recently stored states
+		led, err = NewLedger(dir, 16, 1, &metrics.NoopCollector{}, zerolog.Nop(), complete.DefaultPathFinderVersion)
+		require.NoError(t, err)
+		defer func() { <-led.Done() }()
+
+		requireConsistentStore(t, led.nodes)
+		require.False(t, led.HasState(ledger.State(tries[1].RootHash())))
+		require.True(t, led.HasState(ledger.State(tries[2].RootHash())))
+		require.True(t, led.HasState(led.InitialState()))
+
+		// only the nodes of the remaining trie are stored
+		var trieNodes func(n *node.Node) int
+		trieNodes = func(n *node.Node) int {
+			if n == nil {
+				return 0
+			}
+			return 1 + trieNodes(n.LeftChild()) + trieNodes(n.RightChild())
+		}
+		require.Equal(t, trieNodes(tries[2].RootNode()), countNodes(t, led.nodes))
+	})
+}
+
+// requireConsistentStore checks that the stored nodes are exactly the nodes reachable from the
+// stored tries, and that their reference counts match the references to them.
+func requireConsistentStore(t *testing.T, s *nodeStore) {
+	refs := reachableNodes(t, s)
+
+	stored := make(map[uint64]uint64)
+	err := s.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.IteratorOptions{Prefix: []byte{prefixNode}})
+		defer it.Close()
+		for it.Rewind(); it.Valid(); it.Next() {
+			id := binary.BigEndian.Uint64(it.Item().Key()[1:])
+			count, err := refCount(txn, id)
+			if err != nil {
+				return err
+			}
+			stored[id] = count
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, refs, stored)
+}
+
+// reachableNodes returns the number of references to the nodes reachable from the stored tries,
+// by node ID.
+func reachableNodes(t *testing.T, s *nodeStore) map[uint64]uint64 {
+	records, err := s.roots()
+	require.NoError(t, err)
+
+	refs := make(map[uint64]uint64)
+	var visit func(id uint64)
+	visit = func(id uint64) {
+		if id == nilNodeID {
+			return
+		}
+		refs[id]++
+		if refs[id] > 1 {
+			// the subtrie was already visited
+			return
+		}
+		stored, err := s.node(id)
+		require.NoError(t, err)
+		if stored.leaf == nil {
+			visit(stored.left)
+			visit(stored.right)
+		}
+	}
+	for _, record := range records {
+		visit(record.nodeID)
+	}
+	return refs
+}
+
+func countNodes(t *testing.T, s *nodeStore) int {
+	count := 0
+	err := s.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.IteratorOptions{Prefix: []byte{prefixNode}, PrefetchValues: false})
+		defer it.Close()
+		for it.Rewind(); it.Valid(); it.Next() {
+			count++
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	return count
+}
+
+func derefPayloads(payloads []*ledger.Payload) []ledger.Payload {
+	values := make([]ledger.Payload, 0, len(payloads))
+	for _, payload := range payloads {
+		values = append(values, *payload)
+	}
+	return values
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -98,6 +98,53 @@ func (q *QuerySingleValue) State() State {
 	return q.state
 }
 
+// IterateQuery holds all data needed to iterate the registers of a ledger state
+type IterateQuery struct {
+	state      State
+	prefix     Key
+	startAfter *Path
+	limit      int
+}
+
+// NewIterateQuery constructs a new ledger query iterating at most limit registers whose keys
+// start with the given prefix (see Key.HasPrefix). Registers are iterated in path order, starting
+// after startAfter if not nil.
+func NewIterateQuery(sc State, prefix Key, startAfter *Path, limit int) (*IterateQuery, error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("limit must be positive, got %d", limit)
+	}
+	return &IterateQuery{state: sc, prefix: prefix, startAfter: startAfter, limit: limit}, nil
+}
+
+// State returns the state part of the query
+func (q *IterateQuery) State() State {
+	return q.state
+}
+
+// Prefix returns the key prefix of the query
+func (q *IterateQuery) Prefix() Key {
+	return q.prefix
+}
+
+// StartAfter returns the path after which the iteration starts, or nil to start at the first path
+func (q *IterateQuery) StartAfter() *Path {
+	return q.startAfter
+}
+
+// Limit returns the maximum number of registers returned by the query
+func (q *IterateQuery) Limit() int {
+	return q.limit
+}
+
+// IterateResult is a page of registers returned by an IterateQuery
+type IterateResult struct {
+	Keys   []Key
+	Values []Value
+	// NextStartAfter is the path to start after to retrieve the next page, or nil if all
+	// registers were iterated.
+	NextStartAfter *Path
+}
+
 // Update holds all data needed for a ledger update
 type Update struct {
 	state  State
@@ -245,6 +292,29 @@ func (k *Key) CanonicalForm() []byte {
 	return retval
 }
 
+// HasPrefix returns true if the key starts with the given prefix. All parts of the prefix except
+// the last one must be equal to the key parts at the same position. The last part of the prefix
+// must have the same type as the key part at its position, and its value must be a prefix of the
+// key part value. All keys have the empty prefix.
+func (k *Key) HasPrefix(prefix *Key) bool {
+	if prefix == nil || len(prefix.KeyParts) == 0 {
+		return true
+	}
+	if len(k.KeyParts) < len(prefix.KeyParts) {
+		return false
+	}
+
+	last := len(prefix.KeyParts) - 1
+	for i := 0; i < last; i++ {
+		if !k.KeyParts[i].Equals(&prefix.KeyParts[i]) {
+			return false
+		}
+	}
+
+	return k.KeyParts[last].Type == prefix.KeyParts[last].Type &&
+		bytes.HasPrefix(k.KeyParts[last].Value, prefix.KeyParts[last].Value)
+}
+
 func (k *Key) String() string {
 	return string(k.CanonicalForm())
 }
@@ -98,6 +98,14 @@ type TrieReadSingleValue struct {
 	Path     Path
 }
 
+// TrieIterate captures a trie iteration query over the registers whose keys start with Prefix
+type TrieIterate struct {
+	RootHash   RootHash
+	Prefix     Key
+	StartAfter *Path
+	Limit      int
+}
+
 // TrieUpdate holds all data for a trie update
 type TrieUpdate struct {
 	RootHash RootHash
@@ -0,0 +1,161 @@
+package flow
+
+import (
+	"bytes"
+	"sort"
+	"time"
+)
+
+// SlashableOffense is the kind of protocol violation evidence is recorded for.
+type SlashableOffense string
+
+const (
+	// OffenseDoubleVote is the evidence of a replica voting for two different blocks in the same view.
+	OffenseDoubleVote SlashableOffense = "double_vote"
+	// OffenseDoubleTimeout is the evidence of a replica sending two different timeouts for the same view.
+	OffenseDoubleTimeout SlashableOffense = "double_timeout"
+	// OffenseDoubleProposal is the evidence of a leader proposing two different blocks in the same view.
+	OffenseDoubleProposal SlashableOffense = "double_proposal"
+	// OffenseInvalidVote is the evidence of a replica sending an invalid vote.
+	OffenseInvalidVote SlashableOffense = "invalid_vote"
+	// OffenseInvalidTimeout is the evidence of a replica sending an invalid timeout.
+	OffenseInvalidTimeout SlashableOffense = "invalid_timeout"
+	// OffenseVoteForInvalidBlock is the evidence of a replica voting for an invalid proposal.
+	OffenseVoteForInvalidBlock SlashableOffense = "vote_for_invalid_block"
+	// OffenseNetworkViolation is the evidence of a node violating the networking protocol,
+	// e.g. by sending messages it is not authorized to send.
+	OffenseNetworkViolation SlashableOffense = "network_violation"
+)
+
+// SignedMessageType is the type of a consensus message recorded as slashing evidence.
+type SignedMessageType string
+
+const (
+	SignedMessageVote     SignedMessageType = "vote"
+	SignedMessageTimeout  SignedMessageType = "timeout"
+	SignedMessageProposal SignedMessageType = "proposal"
+)
+
+// SignedConsensusMessage is a consensus message signed by its sender, with all data required
+// to re-construct the signed message and verify the signature:
+//   - votes and proposals are signed over (View, BlockID)
+//   - timeouts are signed over (View, NewestQCView)
+type SignedConsensusMessage struct {
+	Type     SignedMessageType
+	SignerID Identifier
+	View     uint64
+	// BlockID is the ID of the block voted for or proposed. Unset for timeouts.
+	BlockID Identifier
+	// NewestQCView is the view of the newest QC included in a timeout. Unset for votes and proposals.
+	NewestQCView uint64
+	// Header is the header of a proposed block, if it is known. It proves that the proposer signed
+	// a block with the given content, as BlockID is the ID of the header.
+	Header *Header
+	// SigData is the signature of the signer, in the same encoding as in the original message.
+	SigData []byte
+}
+
+// ID returns a unique identifier of the signed message.
+func (m *SignedConsensusMessage) ID() Identifier {
+	var headerID Identifier
+	if m.Header != nil {
+		headerID = m.Header.ID()
+	}
+
+	body := struct {
+		Type         SignedMessageType
+		SignerID     Identifier
+		View         uint64
+		BlockID      Identifier
+		NewestQCView uint64
+		HeaderID     Identifier
+		SigData      []byte
+	}{
+		Type:         m.Type,
+		SignerID:     m.SignerID,
+		View:         m.View,
+		BlockID:      m.BlockID,
+		NewestQCView: m.NewestQCView,
+		HeaderID:     headerID,
+		SigData:      m.SigData,
+	}
+	return MakeID(body)
+}
+
+// NetworkViolation describes the message a networking protocol violation was detected for.
+type NetworkViolation struct {
+	PeerID      string
+	Channel     string
+	Protocol    string
+	MessageType string
+}
+
+// SlashingEvidence is the evidence of a slashable offense committed by a node. Evidence of
+// consensus offenses contains the conflicting or invalid signed messages, together with the
+// identity and keys of the offender at the time of the offense, so it can be verified
+// independently of the protocol state of the node that detected it.
+type SlashingEvidence struct {
+	Offense    SlashableOffense
+	OffenderID Identifier
+	// ChainID is the chain of the consensus instance the offense was committed in. Unset for
+	// network violations.
+	ChainID ChainID
+	// EpochCounter is the epoch the offense was committed in. For network violations, it is
+	// the current epoch at the time the violation was detected.
+	EpochCounter uint64
+	// View is the view the offense was committed in. Unset for network violations.
+	View uint64
+	// Messages are the signed messages proving the offense. For double votes, timeouts and
+	// proposals, these are the two conflicting messages. For invalid votes and timeouts, this is
+	// the invalid message. For votes for invalid blocks, these are the vote and the proposal.
+	Messages []SignedConsensusMessage
+	// Network describes the violating message of network violations.
+	Network *NetworkViolation
+	// Offender is the identity of the offender, including its staking key, at the time of the
+	// offense, if it is known.
+	Offender *Identity
+	// RandomBeaconPubKey is the encoded random beacon key share of the offender for the epoch of
+	// the offense, if it is known. Votes in the main consensus may be signed with it.
+	RandomBeaconPubKey []byte
+	// Reason is a description of the offense, e.g. why a message is invalid.
+	Reason string
+	// DetectedAt is the time the offense was detected at. It is not part of the evidence ID, so
+	// the same offense detected twice results in the same evidence.
+	DetectedAt time.Time
+}
+
+// ID returns a unique identifier of the evidence. Evidence of the same offense, with the same
+// messages (in any order), has the same ID.
+func (e *SlashingEvidence) ID() Identifier {
+	messageIDs := make([]Identifier, 0, len(e.Messages))
+	for i := range e.Messages {
+		messageIDs = append(messageIDs, e.Messages[i].ID())
+	}
+	sort.Slice(messageIDs, func(i, j int) bool {
+		return bytes.Compare(messageIDs[i][:], messageIDs[j][:]) < 0
+	})
+
+	var network NetworkViolation
+	if e.Network != nil {
+		network = *e.Network
+	}
+
+	body := struct {
+		Offense    SlashableOffense
+		OffenderID Identifier
+		ChainID    ChainID
+		View       uint64
+		MessageIDs []Identifier
+		Network    NetworkViolation
+		Reason     string
+	}{
+		Offense:    e.Offense,
+		OffenderID: e.OffenderID,
+		ChainID:    e.ChainID,
+		View:       e.View,
+		MessageIDs: messageIDs,
+		Network:    network,
+		Reason:     e.Reason,
+	}
+	return MakeID(body)
+}
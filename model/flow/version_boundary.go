@@ -0,0 +1,14 @@
+package flow
+
+import "fmt"
+
+// VersionBoundary is a block height from which a node software version is required to execute
+// blocks. Nodes running an older version stop before executing the block at BlockHeight.
+type VersionBoundary struct {
+	BlockHeight uint64
+	Version     string
+}
+
+func (v VersionBoundary) String() string {
+	return fmt.Sprintf("%d:%s", v.BlockHeight, v.Version)
+}
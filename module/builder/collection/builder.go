@@ -202,6 +202,9 @@ func (b *Builder) BuildOn(parentID flow.Identifier, setter func(*flow.Header) er
 	minRefHeight := uint64(math.MaxUint64)
 	minRefID := refChainFinalizedID
 
+	// keep track of omitted transactions to enforce sequence number ordering
+	sequences := newSequenceTracker(b.config)
+
 	var transactions []*flow.TransactionBody
 	var totalByteSize uint64
 	var totalGas uint64
@@ -212,11 +215,18 @@ func (b *Builder) BuildOn(parentID flow.Identifier, setter func(*flow.Header) er
 			break
 		}
 
+		// omit transactions which depend on an omitted transaction
+		if sequences.shouldOmit(tx) {
+			sequences.transactionOmitted(tx)
+			continue
+		}
+
 		txByteSize := uint64(tx.ByteSize())
 		// ignore transactions with tx byte size bigger that the max amount per collection
 		// this case shouldn't happen ever since we keep a limit on tx byte size but in case
 		// we keep this condition
 		if txByteSize > b.config.MaxCollectionByteSize {
+			sequences.transactionOmitted(tx)
 			continue
 		}
 
@@ -229,6 +239,7 @@ func (b *Builder) BuildOn(parentID flow.Identifier, setter func(*flow.Header) er
 		// ignore transactions with max gas bigger that the max total gas per collection
 		// this case shouldn't happen ever but in case we keep this condition
 		if tx.GasLimit > b.config.MaxCollectionTotalGas {
+			sequences.transactionOmitted(tx)
 			continue
 		}
 
@@ -241,6 +252,7 @@ func (b *Builder) BuildOn(parentID flow.Identifier, setter func(*flow.Header) er
 		// retrieve the main chain header that was used as reference
 		refHeader, err := b.mainHeaders.ByBlockID(tx.ReferenceBlockID)
 		if errors.Is(err, storage.ErrNotFound) {
+			sequences.transactionOmitted(tx)
 			continue // in case we are configured with liberal transaction ingest rules
 		}
 		if err != nil {
@@ -249,6 +261,7 @@ func (b *Builder) BuildOn(parentID flow.Identifier, setter func(*flow.Header) er
 
 		// disallow un-finalized reference blocks
 		if refChainFinalizedHeight < refHeader.Height {
+			sequences.transactionOmitted(tx)
 			continue
 		}
 		// make sure the reference block is finalized and not orphaned
@@ -259,6 +272,7 @@ func (b *Builder) BuildOn(parentID flow.Identifier, setter func(*flow.Header) er
 		if blockFinalizedAtReferenceHeight.ID() != tx.ReferenceBlockID {
 			// the transaction references an orphaned block - it will never be valid
 			b.transactions.Remove(tx.ID())
+			sequences.transactionOmitted(tx)
 			continue
 		}
 
@@ -266,6 +280,7 @@ func (b *Builder) BuildOn(parentID flow.Identifier, setter func(*flow.Header) er
 		if refHeader.Height < minPossibleRefHeight {
 			// the transaction is expired, it will never be valid
 			b.transactions.Remove(tx.ID())
+			sequences.transactionOmitted(tx)
 			continue
 		}
 
@@ -297,6 +312,7 @@ func (b *Builder) BuildOn(parentID flow.Identifier, setter func(*flow.Header) er
 					Str("payer_addr", tx.Payer.String()).
 					Float64("rate_limit", b.config.MaxPayerTransactionRate).
 					Msg("transaction is rate-limited")
+				sequences.transactionOmitted(tx)
 				continue
 			}
 		}
@@ -18,6 +18,7 @@ import (
 	builder "github.com/onflow/flow-go/module/builder/collection"
 	"github.com/onflow/flow-go/module/mempool"
 	"github.com/onflow/flow-go/module/mempool/herocache"
+	"github.com/onflow/flow-go/module/mempool/stdmap"
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/module/trace"
 	"github.com/onflow/flow-go/state/cluster"
@@ -937,6 +938,107 @@ func (suite *BuilderSuite) TestBuildOn_RateLimitDryRun() {
 	}
 }
 
+// TestBuildOn_PriorityOrdering tests that transactions of priority payers are included first,
+// when using a mempool ordered by the configured transaction priority.
+func (suite *BuilderSuite) TestBuildOn_PriorityOrdering() {
+
+	payer := unittest.RandomAddressFixture()
+	opts := []builder.Opt{
+		builder.WithMaxCollectionSize(5),
+		builder.WithPriorityPayers(payer),
+	}
+	conf := builder.DefaultConfig()
+	for _, apply := range opts {
+		apply(&conf)
+	}
+
+	suite.pool = stdmap.NewPriorityTransactions(1000, builder.TransactionPriority(conf))
+	suite.builder, _ = builder.NewBuilder(suite.db, trace.NewNoopTracer(), suite.headers, suite.headers, suite.payloads, suite.pool, unittest.Logger(), opts...)
+
+	// fill the pool with 10 transactions of other payers, followed by 5 transactions of the priority payer
+	suite.FillPool(10, func() *flow.TransactionBody {
+		tx := unittest.TransactionBodyFixture()
+		tx.ReferenceBlockID = suite.ProtoStateRoot().ID()
+		return &tx
+	})
+	var priorityTxIDs []flow.Identifier
+	suite.FillPool(5, func() *flow.TransactionBody {
+		tx := unittest.TransactionBodyFixture()
+		tx.ReferenceBlockID = suite.ProtoStateRoot().ID()
+		tx.Payer = payer
+		priorityTxIDs = append(priorityTxIDs, tx.ID())
+		return &tx
+	})
+
+	header, err := suite.builder.BuildOn(suite.genesis.ID(), noopSetter)
+	suite.Require().NoError(err)
+
+	var built model.Block
+	err = suite.db.View(procedure.RetrieveClusterBlock(header.ID(), &built))
+	suite.Require().NoError(err)
+
+	suite.Assert().Len(built.Payload.Collection.Transactions, 5)
+	suite.Assert().True(collectionContains(built.Payload.Collection, priorityTxIDs...))
+}
+
+// TestBuildOn_SequenceNumberOrdering tests that transactions are omitted if a transaction with
+// the same proposal key and a lower sequence number is omitted.
+func (suite *BuilderSuite) TestBuildOn_SequenceNumberOrdering() {
+
+	// start with an empty mempool
+	suite.ClearPool()
+
+	suite.pool = stdmap.NewPriorityTransactions(1000, builder.TransactionPriority(builder.DefaultConfig()))
+	suite.builder, _ = builder.NewBuilder(suite.db, trace.NewNoopTracer(), suite.headers, suite.headers, suite.payloads, suite.pool, unittest.Logger(),
+		builder.WithSequenceNumberOrdering(true),
+	)
+
+	proposer := unittest.RandomAddressFixture()
+	create := func(seqNum uint64, refBlockID flow.Identifier) *flow.TransactionBody {
+		tx := unittest.TransactionBodyFixture(func(tx *flow.TransactionBody) {
+			tx.ReferenceBlockID = refBlockID
+			tx.ProposalKey = flow.ProposalKey{Address: proposer, KeyIndex: 0, SequenceNumber: seqNum}
+		})
+		return &tx
+	}
+
+	// the first transaction of the proposal key references an unknown block, so it is omitted
+	tx0 := create(0, unittest.IdentifierFixture())
+	tx1 := create(1, suite.ProtoStateRoot().ID())
+	tx2 := create(2, suite.ProtoStateRoot().ID())
+	// a transaction of another proposal key is not affected
+	other := unittest.TransactionBodyFixture(func(tx *flow.TransactionBody) {
+		tx.ReferenceBlockID = suite.ProtoStateRoot().ID()
+	})
+
+	for _, tx := range []*flow.TransactionBody{tx2, tx1, tx0, &other} {
+		suite.Require().True(suite.pool.Add(tx))
+	}
+
+	header, err := suite.builder.BuildOn(suite.genesis.ID(), noopSetter)
+	suite.Require().NoError(err)
+
+	var built model.Block
+	err = suite.db.View(procedure.RetrieveClusterBlock(header.ID(), &built))
+	suite.Require().NoError(err)
+
+	suite.Assert().Len(built.Payload.Collection.Transactions, 1)
+	suite.Assert().True(collectionContains(built.Payload.Collection, other.ID()))
+
+	// once the first transaction is removed, the dependent transactions are included in order
+	suite.pool.Remove(tx0.ID())
+
+	header, err = suite.builder.BuildOn(header.ID(), noopSetter)
+	suite.Require().NoError(err)
+
+	err = suite.db.View(procedure.RetrieveClusterBlock(header.ID(), &built))
+	suite.Require().NoError(err)
+
+	suite.Require().Len(built.Payload.Collection.Transactions, 2)
+	suite.Assert().Equal(tx1.ID(), built.Payload.Collection.Transactions[0].ID())
+	suite.Assert().Equal(tx2.ID(), built.Payload.Collection.Transactions[1].ID())
+}
+
 // helper to check whether a collection contains each of the given transactions.
 func collectionContains(collection flow.Collection, txIDs ...flow.Identifier) bool {
 
@@ -44,6 +44,23 @@ type Config struct {
 
 	// MaxCollectionTotalGas is the maximum of total of gas per collection (sum of maxGasLimit over transactions)
 	MaxCollectionTotalGas uint64
+
+	// PriorityPayers is a set of addresses whose transactions are included
+	// before the transactions of all other payers. Only applies if the
+	// transaction mempool orders transactions by TransactionPriority.
+	PriorityPayers map[flow.Address]struct{}
+
+	// GasLimitPriority orders transactions by their declared gas limit, highest
+	// first, among transactions of the same payer priority. Only applies if the
+	// transaction mempool orders transactions by TransactionPriority.
+	GasLimitPriority bool
+
+	// SequenceNumberOrdering omits transactions from a collection if a
+	// transaction with the same proposal key and a lower sequence number was
+	// omitted from it, so that dependent transactions are included in order.
+	// Should be used with a transaction mempool which returns transactions in
+	// sequence number order, like stdmap.PriorityTransactions.
+	SequenceNumberOrdering bool
 }
 
 func DefaultConfig() Config {
@@ -55,6 +72,9 @@ func DefaultConfig() Config {
 		UnlimitedPayers:         make(map[flow.Address]struct{}), // no unlimited payers
 		MaxCollectionByteSize:   flow.DefaultMaxCollectionByteSize,
 		MaxCollectionTotalGas:   flow.DefaultMaxCollectionTotalGas,
+		PriorityPayers:          make(map[flow.Address]struct{}), // no priority payers
+		GasLimitPriority:        false,
+		SequenceNumberOrdering:  false,
 	}
 }
 
@@ -108,3 +128,25 @@ func WithMaxCollectionTotalGas(limit uint64) Opt {
 		c.MaxCollectionTotalGas = limit
 	}
 }
+
+func WithPriorityPayers(payers ...flow.Address) Opt {
+	lookup := make(map[flow.Address]struct{})
+	for _, payer := range payers {
+		lookup[payer] = struct{}{}
+	}
+	return func(c *Config) {
+		c.PriorityPayers = lookup
+	}
+}
+
+func WithGasLimitPriority(enabled bool) Opt {
+	return func(c *Config) {
+		c.GasLimitPriority = enabled
+	}
+}
+
+func WithSequenceNumberOrdering(enabled bool) Opt {
+	return func(c *Config) {
+		c.SequenceNumberOrdering = enabled
+	}
+}
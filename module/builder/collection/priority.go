@@ -0,0 +1,77 @@
+package collection
+
+import (
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/mempool/stdmap"
+)
+
+// priorityPayerBit is set in the priority of transactions of priority payers, so that they
+// are ordered before all other transactions.
+const priorityPayerBit = uint64(1) << 63
+
+// TransactionPriority returns the transaction inclusion priority configured by the given
+// config, for use with the stdmap.PriorityTransactions mempool. Transactions of priority
+// payers are included first. If GasLimitPriority is enabled, transactions are ordered by
+// their declared gas limit among transactions of the same payer priority. Otherwise, and
+// among transactions with the same gas limit, older transactions are included first.
+func TransactionPriority(conf Config) stdmap.TransactionPriority {
+	return func(tx *flow.TransactionBody) uint64 {
+		var priority uint64
+		if _, ok := conf.PriorityPayers[tx.Payer]; ok {
+			priority |= priorityPayerBit
+		}
+		if conf.GasLimitPriority {
+			gasLimit := tx.GasLimit
+			if gasLimit >= priorityPayerBit {
+				gasLimit = priorityPayerBit - 1
+			}
+			priority |= gasLimit
+		}
+		return priority
+	}
+}
+
+// proposalKeyID identifies a proposal key, independent of the sequence number.
+type proposalKeyID struct {
+	address  flow.Address
+	keyIndex uint64
+}
+
+// sequenceTracker implements proposal key sequence number ordering. See Config for details.
+type sequenceTracker struct {
+	enabled bool
+	// lowest sequence number of the omitted transactions, by proposal key
+	omitted map[proposalKeyID]uint64
+}
+
+func newSequenceTracker(conf Config) *sequenceTracker {
+	return &sequenceTracker{
+		enabled: conf.SequenceNumberOrdering,
+		omitted: make(map[proposalKeyID]uint64),
+	}
+}
+
+// note that a transaction was omitted from the collection under construction.
+func (t *sequenceTracker) transactionOmitted(tx *flow.TransactionBody) {
+	if !t.enabled {
+		return
+	}
+
+	key := proposalKeyID{address: tx.ProposalKey.Address, keyIndex: tx.ProposalKey.KeyIndex}
+	lowest, ok := t.omitted[key]
+	if !ok || tx.ProposalKey.SequenceNumber < lowest {
+		t.omitted[key] = tx.ProposalKey.SequenceNumber
+	}
+}
+
+// returns whether the transaction should be omitted from the collection under construction,
+// because a transaction it depends on was omitted.
+func (t *sequenceTracker) shouldOmit(tx *flow.TransactionBody) bool {
+	if !t.enabled {
+		return false
+	}
+
+	key := proposalKeyID{address: tx.ProposalKey.Address, keyIndex: tx.ProposalKey.KeyIndex}
+	lowest, ok := t.omitted[key]
+	return ok && tx.ProposalKey.SequenceNumber > lowest
+}
@@ -40,6 +40,22 @@ func NewChunkVerifier(vm fvm.VM, vmCtx fvm.Context, logger zerolog.Logger) *Chun
 	}
 }
 
+// ChunkExecution is the outcome of re-executing a chunk during verification.
+// It allows to inspect how the re-execution diverges from the execution result,
+// beyond the verification fault of the chunk.
+type ChunkExecution struct {
+	// Events are the events emitted by the transactions of the chunk.
+	Events flow.EventsList
+	// ServiceEvents are the service events emitted by the transactions of the chunk.
+	ServiceEvents flow.ServiceEventList
+	// UpdatedRegisters are the registers updated by the chunk, sorted by register ID.
+	UpdatedRegisters flow.RegisterEntries
+	// EndState is the state commitment after applying the updated registers to
+	// the partial trie of the chunk data pack. It is only set if the events of
+	// the chunk match, and the chunk data pack contains all updated registers.
+	EndState flow.StateCommitment
+}
+
 // Verify verifies a given VerifiableChunk by executing it and checking the
 // final state commitment.
 // It returns a Spock Secret as a byte array, verification fault of the chunk,
@@ -51,6 +67,35 @@ func (fcv *ChunkVerifier) Verify(
 	chmodels.ChunkFault,
 	error,
 ) {
+	return fcv.verify(vc, &ChunkExecution{})
+}
+
+// VerifyWithExecution verifies a given VerifiableChunk like Verify, and
+// additionally returns the outcome of re-executing the chunk, for debugging
+// chunks which fail verification.
+// The outcome is only partially populated if the chunk could not be fully
+// re-executed (e.g. if the partial trie of the chunk data pack is invalid).
+func (fcv *ChunkVerifier) VerifyWithExecution(
+	vc *verification.VerifiableChunkData,
+) (
+	*ChunkExecution,
+	[]byte,
+	chmodels.ChunkFault,
+	error,
+) {
+	execution := &ChunkExecution{}
+	spockSecret, fault, err := fcv.verify(vc, execution)
+	return execution, spockSecret, fault, err
+}
+
+func (fcv *ChunkVerifier) verify(
+	vc *verification.VerifiableChunkData,
+	execution *ChunkExecution,
+) (
+	[]byte,
+	chmodels.ChunkFault,
+	error,
+) {
 
 	var ctx fvm.Context
 	var transactions []*fvm.TransactionProcedure
@@ -90,7 +135,8 @@ func (fcv *ChunkVerifier) Verify(
 		vc.Result,
 		transactions,
 		vc.EndState,
-		vc.IsSystemChunk)
+		vc.IsSystemChunk,
+		execution)
 }
 
 type partialLedgerStorageSnapshot struct {
@@ -130,6 +176,7 @@ func (fcv *ChunkVerifier) verifyTransactionsInContext(
 	transactions []*fvm.TransactionProcedure,
 	endState flow.StateCommitment,
 	systemChunk bool,
+	execution *ChunkExecution,
 ) (
 	[]byte,
 	chmodels.ChunkFault,
@@ -209,6 +256,12 @@ func (fcv *ChunkVerifier) verifyTransactionsInContext(
 		}
 	}
 
+	chunkExecutionSnapshot := chunkView.Finalize()
+
+	execution.Events = events
+	execution.ServiceEvents = serviceEvents
+	execution.UpdatedRegisters = chunkExecutionSnapshot.UpdatedRegisters()
+
 	// check read access to unknown registers
 	if len(unknownRegTouch) > 0 {
 		var missingRegs []string
@@ -257,9 +310,8 @@ func (fcv *ChunkVerifier) verifyTransactionsInContext(
 	// Applying chunk updates to the partial trie.	This returns the expected
 	// end state commitment after updates and the list of register keys that
 	// was not provided by the chunk data package (err).
-	chunkExecutionSnapshot := chunkView.Finalize()
 	keys, values := executionState.RegisterEntriesToKeysValues(
-		chunkExecutionSnapshot.UpdatedRegisters())
+		execution.UpdatedRegisters)
 
 	update, err := ledger.NewUpdate(
 		ledger.State(chunkDataPack.StartState),
@@ -283,6 +335,8 @@ func (fcv *ChunkVerifier) verifyTransactionsInContext(
 		return nil, chmodels.NewCFMissingRegisterTouch(nil, chIndex, execResID, problematicTx), nil
 	}
 
+	execution.EndState = flow.StateCommitment(expEndStateComm)
+
 	// TODO check if exec node provided register touches that was not used (no read and no update)
 	// check if the end state commitment mentioned in the chunk matches
 	// what the partial trie is providing.
@@ -203,6 +203,51 @@ func (s *ChunkVerifierTestSuite) TestEmptyCollection() {
 	assert.NotNil(s.T(), spockSecret)
 }
 
+// TestVerifyWithExecution tests that the outcome of re-executing the chunk is
+// returned along with the verification result
+func (s *ChunkVerifierTestSuite) TestVerifyWithExecution() {
+	id0 := flow.NewRegisterID("00", "")
+	id5 := flow.NewRegisterID("05", "")
+
+	s.Run("happy path", func() {
+		vch := GetBaselineVerifiableChunk(s.T(), "", false)
+		execution, spockSecret, chFault, err := s.verifier.VerifyWithExecution(vch)
+		require.NoError(s.T(), err)
+		assert.Nil(s.T(), chFault)
+		assert.NotNil(s.T(), spockSecret)
+
+		assert.Len(s.T(), execution.Events, len(vch.ChunkDataPack.Collection.Transactions)*len(eventsList))
+		assert.Equal(s.T(), flow.RegisterEntries{{Key: id5, Value: []byte{'B'}}}, execution.UpdatedRegisters)
+		assert.Equal(s.T(), vch.EndState, execution.EndState)
+	})
+
+	s.Run("wrong end state", func() {
+		vch := GetBaselineVerifiableChunk(s.T(), "wrongEndState", false)
+		execution, _, chFault, err := s.verifier.VerifyWithExecution(vch)
+		require.NoError(s.T(), err)
+		assert.IsType(s.T(), &chunksmodels.CFNonMatchingFinalState{}, chFault)
+
+		expectedRegisters := flow.RegisterEntries{
+			{Key: id0, Value: []byte{'F'}},
+			{Key: id5, Value: []byte{'B'}},
+		}
+		assert.Equal(s.T(), expectedRegisters, execution.UpdatedRegisters)
+		assert.NotEqual(s.T(), flow.StateCommitment{}, execution.EndState)
+		assert.NotEqual(s.T(), vch.EndState, execution.EndState)
+	})
+
+	s.Run("events mismatch", func() {
+		vch := GetBaselineVerifiableChunk(s.T(), "eventsMismatch", false)
+		execution, _, chFault, err := s.verifier.VerifyWithExecution(vch)
+		require.NoError(s.T(), err)
+		assert.IsType(s.T(), &chunksmodels.CFInvalidEventsCollection{}, chFault)
+
+		// the extra event is included, but the end state is not computed
+		assert.Len(s.T(), execution.Events, len(vch.ChunkDataPack.Collection.Transactions)*len(eventsList)+1)
+		assert.Equal(s.T(), flow.StateCommitment{}, execution.EndState)
+	})
+}
+
 // GetBaselineVerifiableChunk returns a verifiable chunk and sets the script
 // of a transaction in the middle of the collection to some value to signal the
 // mocked vm on what to return as tx exec outcome.
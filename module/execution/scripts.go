@@ -0,0 +1,235 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/onflow/cadence/runtime"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/engine/execution/computation/query"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/meter"
+	reusableRuntime "github.com/onflow/flow-go/fvm/runtime"
+	"github.com/onflow/flow-go/fvm/state"
+	"github.com/onflow/flow-go/fvm/storage/derived"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/storage"
+)
+
+// ReusableCadenceRuntimePoolSize is the size of the pool of cadence runtimes used to execute scripts.
+const ReusableCadenceRuntimePoolSize = 1000
+
+// ErrDataNotAvailable is returned when the execution state required to execute a script at a
+// height is not available locally.
+var ErrDataNotAvailable = errors.New("data for block is not available")
+
+// RegisterIndex provides the register values of the execution state at indexed heights.
+type RegisterIndex interface {
+	// IndexedHeightRange returns the range of consecutive heights for which registers are indexed
+	// (inclusive on both ends). ok is false if no height was indexed yet.
+	IndexedHeightRange() (lowest uint64, highest uint64, ok bool)
+
+	// RegisterValue returns the value of the register at the given height, or an empty value if
+	// the register is not set.
+	// No errors are expected during normal operation.
+	RegisterValue(id flow.RegisterID, height uint64) (flow.RegisterValue, error)
+}
+
+// ScriptExecutor executes scripts against the locally available execution state.
+type ScriptExecutor interface {
+	// ExecuteAtBlockHeight executes the script against the execution state at the given height, and
+	// returns its result along with the computation and memory it used.
+	// Expected errors:
+	//   - ErrDataNotAvailable if the execution state at the height is not available locally
+	//   - any other error returned by query.Executor.ExecuteScript, e.g. if the script fails
+	ExecuteAtBlockHeight(ctx context.Context, script []byte, arguments [][]byte, height uint64) ([]byte, meter.Usage, error)
+
+	// SimulateTransactionAtBlockHeight executes the transaction against the execution state at the
+	// given height without applying its state changes, optionally skipping the verification of its
+	// signatures. Failures of the transaction itself are reported in the simulation.
+	// Expected errors:
+	//   - ErrDataNotAvailable if the execution state at the height is not available locally
+	SimulateTransactionAtBlockHeight(ctx context.Context, tx *flow.TransactionBody, height uint64, skipSignatureVerification bool) (*query.TransactionSimulation, error)
+
+	// GetAccountBalance returns the balance of the account at the given height.
+	// Expected errors:
+	//   - ErrDataNotAvailable if the execution state at the height is not available locally
+	//   - fvm account not found error if the account does not exist at the height
+	GetAccountBalance(ctx context.Context, address flow.Address, height uint64) (uint64, error)
+
+	// GetAccountKeys returns the public keys of the account at the given height.
+	// Expected errors:
+	//   - ErrDataNotAvailable if the execution state at the height is not available locally
+	//   - fvm account not found error if the account does not exist at the height
+	GetAccountKeys(ctx context.Context, address flow.Address, height uint64) ([]flow.AccountPublicKey, error)
+
+	// GetAccountKey returns the public key with the given index of the account at the given height.
+	// Expected errors:
+	//   - ErrDataNotAvailable if the execution state at the height is not available locally
+	//   - fvm account not found error if the account does not exist at the height
+	//   - fvm account public key not found error if the key does not exist at the height
+	GetAccountKey(ctx context.Context, address flow.Address, keyIndex uint64, height uint64) (*flow.AccountPublicKey, error)
+}
+
+// Scripts executes scripts with the fvm, reading registers from a local register index.
+type Scripts struct {
+	executor  *query.QueryExecutor
+	headers   storage.Headers
+	registers RegisterIndex
+}
+
+var _ ScriptExecutor = (*Scripts)(nil)
+
+// NewScripts returns a new Scripts executor for the given chain. The fvm options are applied to the
+// context used to execute scripts, in addition to a pool of reusable cadence runtimes.
+//
+// No errors are expected during normal operation.
+func NewScripts(
+	log zerolog.Logger,
+	metrics module.ExecutionMetrics,
+	chainID flow.ChainID,
+	options []fvm.Option,
+	config query.QueryConfig,
+	headers storage.Headers,
+	registers RegisterIndex,
+) (*Scripts, error) {
+	vmCtx := fvm.NewContext(options...)
+	vmCtx = fvm.NewContextFromParent(vmCtx,
+		fvm.WithReusableCadenceRuntimePool(
+			reusableRuntime.NewReusableCadenceRuntimePool(
+				ReusableCadenceRuntimePoolSize,
+				runtime.Config{
+					AccountLinkingEnabled: true,
+					// Attachments are enabled everywhere except for Mainnet
+					AttachmentsEnabled: chainID != flow.Mainnet,
+				},
+			),
+		),
+	)
+
+	derivedChainData, err := derived.NewDerivedChainData(derived.DefaultDerivedDataCacheSize)
+	if err != nil {
+		return nil, fmt.Errorf("could not create derived data cache: %w", err)
+	}
+
+	executor := query.NewQueryExecutor(
+		config,
+		log.With().Str("module", "script_executor").Logger(),
+		metrics,
+		fvm.NewVirtualMachine(),
+		vmCtx,
+		derivedChainData,
+	)
+
+	return &Scripts{
+		executor:  executor,
+		headers:   headers,
+		registers: registers,
+	}, nil
+}
+
+// ExecuteAtBlockHeight executes the script against the execution state at the given height, and
+// returns its result along with the computation and memory it used.
+// Expected errors:
+//   - ErrDataNotAvailable if the execution state at the height is not available locally
+//   - any other error returned by query.Executor.ExecuteScript, e.g. if the script fails
+func (s *Scripts) ExecuteAtBlockHeight(
+	ctx context.Context,
+	script []byte,
+	arguments [][]byte,
+	height uint64,
+) ([]byte, meter.Usage, error) {
+	header, snapshot, err := s.snapshotAtHeight(height)
+	if err != nil {
+		return nil, meter.Usage{}, err
+	}
+
+	return s.executor.ExecuteScript(ctx, script, arguments, header, snapshot)
+}
+
+// SimulateTransactionAtBlockHeight executes the transaction against the execution state at the
+// given height without applying its state changes, optionally skipping the verification of its
+// signatures. Failures of the transaction itself are reported in the simulation.
+// Expected errors:
+//   - ErrDataNotAvailable if the execution state at the height is not available locally
+func (s *Scripts) SimulateTransactionAtBlockHeight(
+	ctx context.Context,
+	tx *flow.TransactionBody,
+	height uint64,
+	skipSignatureVerification bool,
+) (*query.TransactionSimulation, error) {
+	header, snapshot, err := s.snapshotAtHeight(height)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.executor.SimulateTransaction(ctx, tx, header, snapshot, skipSignatureVerification)
+}
+
+// GetAccountBalance returns the balance of the account at the given height. Only the registers
+// required to compute the balance are read.
+// Expected errors:
+//   - ErrDataNotAvailable if the execution state at the height is not available locally
+//   - fvm account not found error if the account does not exist at the height
+func (s *Scripts) GetAccountBalance(ctx context.Context, address flow.Address, height uint64) (uint64, error) {
+	header, snapshot, err := s.snapshotAtHeight(height)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.executor.GetAccountBalance(ctx, address, header, snapshot)
+}
+
+// GetAccountKeys returns the public keys of the account at the given height. Only the registers
+// storing the keys are read.
+// Expected errors:
+//   - ErrDataNotAvailable if the execution state at the height is not available locally
+//   - fvm account not found error if the account does not exist at the height
+func (s *Scripts) GetAccountKeys(ctx context.Context, address flow.Address, height uint64) ([]flow.AccountPublicKey, error) {
+	header, snapshot, err := s.snapshotAtHeight(height)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.executor.GetAccountKeys(ctx, address, header, snapshot)
+}
+
+// GetAccountKey returns the public key with the given index of the account at the given height.
+// Only the registers storing the key are read.
+// Expected errors:
+//   - ErrDataNotAvailable if the execution state at the height is not available locally
+//   - fvm account not found error if the account does not exist at the height
+//   - fvm account public key not found error if the key does not exist at the height
+func (s *Scripts) GetAccountKey(ctx context.Context, address flow.Address, keyIndex uint64, height uint64) (*flow.AccountPublicKey, error) {
+	header, snapshot, err := s.snapshotAtHeight(height)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.executor.GetAccountKey(ctx, address, keyIndex, header, snapshot)
+}
+
+// snapshotAtHeight returns the header at the given height, and a snapshot reading registers from
+// the register index at the height.
+// Expected errors:
+//   - ErrDataNotAvailable if the execution state at the height is not available locally
+func (s *Scripts) snapshotAtHeight(height uint64) (*flow.Header, state.StorageSnapshot, error) {
+	lowest, highest, ok := s.registers.IndexedHeightRange()
+	if !ok || height < lowest || height > highest {
+		return nil, nil, fmt.Errorf("registers at height %d are not indexed: %w", height, ErrDataNotAvailable)
+	}
+
+	header, err := s.headers.ByHeight(height)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not get header at height %d: %w", height, err)
+	}
+
+	snapshot := state.NewReadFuncStorageSnapshot(func(id flow.RegisterID) (flow.RegisterValue, error) {
+		return s.registers.RegisterValue(id, height)
+	})
+
+	return header, snapshot, nil
+}
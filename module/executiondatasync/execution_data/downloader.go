@@ -12,7 +12,9 @@ import (
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/module/blobs"
+	"github.com/onflow/flow-go/module/executiondatasync/tracker"
 	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/storage"
 )
 
 // Downloader is used to download execution data blobs from the network via a blob service.
@@ -31,6 +33,11 @@ type downloader struct {
 	blobService network.BlobService
 	maxBlobSize int
 	serializer  Serializer
+
+	// tracker and headers are used to track the downloaded blobs by block height, so that they
+	// can be pruned. Blobs are not tracked if tracker is nil.
+	tracker tracker.Storage
+	headers storage.Headers
 }
 
 type DownloaderOption func(*downloader)
@@ -41,11 +48,21 @@ func WithSerializer(serializer Serializer) DownloaderOption {
 	}
 }
 
+// WithExecutionDataTracker configures the downloader to track the CIDs of all downloaded blobs
+// in the given tracker storage, at the height of the block the execution data belongs to, so that
+// the blobs can be pruned later. The headers are used to look up the block heights.
+func WithExecutionDataTracker(trackerStorage tracker.Storage, headers storage.Headers) DownloaderOption {
+	return func(d *downloader) {
+		d.tracker = trackerStorage
+		d.headers = headers
+	}
+}
+
 func NewDownloader(blobService network.BlobService, opts ...DownloaderOption) *downloader {
 	d := &downloader{
-		blobService,
-		DefaultMaxBlobSize,
-		DefaultSerializer,
+		blobService: blobService,
+		maxBlobSize: DefaultMaxBlobSize,
+		serializer:  DefaultSerializer,
 	}
 
 	for _, opt := range opts {
@@ -81,12 +98,13 @@ func (d *downloader) Download(ctx context.Context, executionDataID flow.Identifi
 
 	// Next, download each of the chunk execution data blobs
 	chunkExecutionDatas := make([]*ChunkExecutionData, len(edRoot.ChunkExecutionDataIDs))
+	chunkCids := make([][]cid.Cid, len(edRoot.ChunkExecutionDataIDs))
 	for i, chunkDataID := range edRoot.ChunkExecutionDataIDs {
 		i := i
 		chunkDataID := chunkDataID
 
 		g.Go(func() error {
-			ced, err := d.getChunkExecutionData(
+			ced, cids, err := d.getChunkExecutionData(
 				gCtx,
 				chunkDataID,
 				blobGetter,
@@ -97,6 +115,7 @@ func (d *downloader) Download(ctx context.Context, executionDataID flow.Identifi
 			}
 
 			chunkExecutionDatas[i] = ced
+			chunkCids[i] = cids
 
 			return nil
 		})
@@ -106,6 +125,17 @@ func (d *downloader) Download(ctx context.Context, executionDataID flow.Identifi
 		return nil, err
 	}
 
+	if d.tracker != nil {
+		cids := []cid.Cid{flow.IdToCid(executionDataID)}
+		for _, c := range chunkCids {
+			cids = append(cids, c...)
+		}
+
+		if err := d.trackBlobs(edRoot.BlockID, cids); err != nil {
+			return nil, fmt.Errorf("failed to track blobs: %w", err)
+		}
+	}
+
 	// Finally, recombine data into original record.
 	bed := &BlockExecutionData{
 		BlockID:             edRoot.BlockID,
@@ -150,32 +180,49 @@ func (d *downloader) getExecutionDataRoot(
 	return edRoot, nil
 }
 
+// getChunkExecutionData downloads the blob tree of a chunk execution data, and returns the
+// chunk execution data together with the CIDs of all blobs in the tree.
 func (d *downloader) getChunkExecutionData(
 	ctx context.Context,
 	chunkExecutionDataID cid.Cid,
 	blobGetter network.BlobGetter,
-) (*ChunkExecutionData, error) {
+) (*ChunkExecutionData, []cid.Cid, error) {
 	cids := []cid.Cid{chunkExecutionDataID}
+	var allCids []cid.Cid
 
 	// iteratively process each level of the blob tree until a ChunkExecutionData is returned or an
 	// error is encountered
 	for i := 0; ; i++ {
 		v, err := d.getBlobs(ctx, blobGetter, cids)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get level %d of blob tree: %w", i, err)
+			return nil, nil, fmt.Errorf("failed to get level %d of blob tree: %w", i, err)
 		}
+		allCids = append(allCids, cids...)
 
 		switch v := v.(type) {
 		case *ChunkExecutionData:
-			return v, nil
+			return v, allCids, nil
 		case *[]cid.Cid:
 			cids = *v
 		default:
-			return nil, NewMalformedDataError(fmt.Errorf("blob tree contains unexpected type %T at level %d", v, i))
+			return nil, nil, NewMalformedDataError(fmt.Errorf("blob tree contains unexpected type %T at level %d", v, i))
 		}
 	}
 }
 
+// trackBlobs tracks the given CIDs at the height of the given block.
+// No errors are expected during normal operation.
+func (d *downloader) trackBlobs(blockID flow.Identifier, cids []cid.Cid) error {
+	header, err := d.headers.ByBlockID(blockID)
+	if err != nil {
+		return fmt.Errorf("failed to get header for block %v: %w", blockID, err)
+	}
+
+	return d.tracker.Update(func(trackBlobs tracker.TrackBlobsFn) error {
+		return trackBlobs(header.Height, cids...)
+	})
+}
+
 // getBlobs gets the given CIDs from the blobservice, reassembles the blobs, and deserializes the reassembled data into an object.
 func (d *downloader) getBlobs(ctx context.Context, blobGetter network.BlobGetter, cids []cid.Cid) (interface{}, error) {
 	blobCh, errCh := d.retrieveBlobs(ctx, blobGetter, cids)
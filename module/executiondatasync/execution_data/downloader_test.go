@@ -14,7 +14,11 @@ import (
 
 	"github.com/onflow/flow-go/module/blobs"
 	"github.com/onflow/flow-go/module/executiondatasync/execution_data"
+	"github.com/onflow/flow-go/module/executiondatasync/tracker"
+	mocktracker "github.com/onflow/flow-go/module/executiondatasync/tracker/mock"
 	"github.com/onflow/flow-go/network/mocknetwork"
+	storagemock "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
 )
 
 func TestCIDNotFound(t *testing.T) {
@@ -58,3 +62,68 @@ func TestCIDNotFound(t *testing.T) {
 	var blobNotFoundError *execution_data.BlobNotFoundError
 	assert.ErrorAs(t, err, &blobNotFoundError)
 }
+
+func TestDownloadTracksBlobs(t *testing.T) {
+	blobstore := blobs.NewBlobstore(dssync.MutexWrap(datastore.NewMapDatastore()))
+	blobService := new(mocknetwork.BlobService)
+	edStore := execution_data.NewExecutionDataStore(blobstore, execution_data.DefaultSerializer)
+	bed := generateBlockExecutionData(t, 3, 3*execution_data.DefaultMaxBlobSize)
+	edID, err := edStore.AddExecutionData(context.Background(), bed)
+	require.NoError(t, err)
+
+	blobGetter := new(mocknetwork.BlobGetter)
+	blobService.On("GetSession", mock.Anything).Return(blobGetter, nil)
+	blobGetter.On("GetBlob", mock.Anything, mock.AnythingOfType("cid.Cid")).Return(
+		func(ctx context.Context, c cid.Cid) blobs.Blob {
+			blob, _ := blobstore.Get(ctx, c)
+			return blob
+		},
+		func(ctx context.Context, c cid.Cid) error {
+			_, err := blobstore.Get(ctx, c)
+			return err
+		},
+	)
+	blobGetter.On("GetBlobs", mock.Anything, mock.AnythingOfType("[]cid.Cid")).Return(
+		func(ctx context.Context, cids []cid.Cid) <-chan blobs.Blob {
+			blobCh := make(chan blobs.Blob, len(cids))
+			for _, c := range cids {
+				blob, err := blobstore.Get(ctx, c)
+				assert.NoError(t, err)
+				blobCh <- blob
+			}
+			close(blobCh)
+			return blobCh
+		},
+	)
+
+	header := unittest.BlockHeaderFixture()
+	headers := storagemock.NewHeaders(t)
+	headers.On("ByBlockID", bed.BlockID).Return(header, nil)
+
+	tracked := make(map[string]struct{})
+	trackerStorage := mocktracker.NewStorage(t)
+	trackerStorage.On("Update", mock.Anything).Return(func(fn tracker.UpdateFn) error {
+		return fn(func(height uint64, cids ...cid.Cid) error {
+			assert.Equal(t, header.Height, height)
+			for _, c := range cids {
+				tracked[c.Hash().String()] = struct{}{}
+			}
+			return nil
+		})
+	}).Once()
+
+	downloader := execution_data.NewDownloader(blobService, execution_data.WithExecutionDataTracker(trackerStorage, headers))
+
+	downloaded, err := downloader.Download(context.Background(), edID)
+	require.NoError(t, err)
+	assert.Equal(t, bed, downloaded)
+
+	// all blobs of the execution data are tracked. the blobstore only keeps the multihashes
+	keys, err := blobstore.AllKeysChan(context.Background())
+	require.NoError(t, err)
+	stored := make(map[string]struct{})
+	for c := range keys {
+		stored[c.Hash().String()] = struct{}{}
+	}
+	assert.Equal(t, stored, tracked)
+}
@@ -0,0 +1,88 @@
+package pruner
+
+import (
+	"sync"
+)
+
+// Pins tracks the heights of execution data which are still being read, for example by state
+// stream subscriptions, so that the Pruner does not prune them mid-stream.
+// A pin protects its height and all heights above it. Readers move their pin forward as they
+// progress, and release it once done.
+//
+// Pins is safe for concurrent use.
+type Pins struct {
+	mu      sync.Mutex
+	nextID  uint64
+	heights map[uint64]uint64 // pin ID -> pinned height
+}
+
+// NewPins creates a new empty set of pins.
+func NewPins() *Pins {
+	return &Pins{
+		heights: make(map[uint64]uint64),
+	}
+}
+
+// Pin pins the given height and all heights above it, until the returned pin is released.
+func (p *Pins) Pin(height uint64) *Pin {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	id := p.nextID
+	p.nextID++
+	p.heights[id] = height
+
+	return &Pin{
+		pins: p,
+		id:   id,
+	}
+}
+
+// Lowest returns the lowest pinned height, and false if no height is pinned.
+func (p *Pins) Lowest() (uint64, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var lowest uint64
+	found := false
+	for _, height := range p.heights {
+		if !found || height < lowest {
+			lowest = height
+			found = true
+		}
+	}
+	return lowest, found
+}
+
+// Count returns the number of pins which have not been released.
+func (p *Pins) Count() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.heights)
+}
+
+// Pin protects a height and all heights above it from being pruned.
+type Pin struct {
+	pins *Pins
+	id   uint64
+}
+
+// Update moves the pin to the given height, which allows the heights below it to be pruned.
+// It is a no-op if the pin was released.
+func (p *Pin) Update(height uint64) {
+	p.pins.mu.Lock()
+	defer p.pins.mu.Unlock()
+
+	if _, ok := p.pins.heights[p.id]; ok {
+		p.pins.heights[p.id] = height
+	}
+}
+
+// Release releases the pin. It is safe to call multiple times.
+func (p *Pin) Release() {
+	p.pins.mu.Lock()
+	defer p.pins.mu.Unlock()
+
+	delete(p.pins.heights, p.id)
+}
@@ -3,6 +3,7 @@ package pruner
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -12,6 +13,7 @@ import (
 	"github.com/onflow/flow-go/module/executiondatasync/tracker"
 	"github.com/onflow/flow-go/module/irrecoverable"
 	"github.com/onflow/flow-go/module/util"
+	"github.com/onflow/flow-go/storage"
 )
 
 const (
@@ -19,43 +21,81 @@ const (
 	defaultThreshold         = uint64(100_000)
 )
 
+// RetentionPolicy defines which execution data is kept by the Pruner. Each limit can be disabled
+// by setting it to 0, and data is pruned as soon as it falls outside of any enabled limit.
+type RetentionPolicy struct {
+	// HeightRangeTarget is the target number of most recent blocks to store data for. After
+	// pruning, the range of heights between the last pruned and last fulfilled height is equal
+	// to the target.
+	HeightRangeTarget uint64
+
+	// Threshold is the number of block heights that the retained range can exceed the policy by
+	// before pruning is triggered. This controls the frequency of pruning.
+	Threshold uint64
+
+	// MaxAge is the maximum age of the blocks to store data for, based on the block timestamps.
+	MaxAge time.Duration
+
+	// DiskBudget is the maximum number of bytes used by the execution data datastore. Once the
+	// budget is exceeded, enough heights are pruned to return below it, assuming an even
+	// distribution of data across heights, plus the threshold. As the datastore reclaims disk
+	// space only some time after pruning, the estimated size of the pruned data is deducted
+	// from the disk usage until the disk usage drops accordingly.
+	DiskBudget uint64
+}
+
+// DefaultRetentionPolicy returns the retention policy used if none is configured.
+func DefaultRetentionPolicy() RetentionPolicy {
+	return RetentionPolicy{
+		HeightRangeTarget: defaultHeightRangeTarget,
+		Threshold:         defaultThreshold,
+	}
+}
+
 // Pruner is a component responsible for pruning data from
-// execution data storage. It is configured with the following
-// parameters:
-//   - Height range target: The target number of most recent blocks
-//     to store data for. This controls the total amount of data
-//     stored on disk.
-//   - Threshold: The number of block heights that we can exceed
-//     the height range target by before pruning is triggered. This
-//     controls the frequency of pruning.
+// execution data storage according to a RetentionPolicy.
 //
 // The Pruner consumes a stream of tracked height notifications,
-// and triggers pruning once the difference between the tracked
-// height and the last pruned height reaches the height range
-// target + threshold.
+// and triggers pruning once the data stored exceeds the retention
+// policy by more than the threshold.
 // A height is considered fulfilled once it has both been executed,
 // tracked, and sealed.
+//
+// Heights pinned in the Pins of the Pruner are never pruned, so that
+// readers like state stream subscriptions don't lose data mid-stream.
 type Pruner struct {
 	storage       tracker.Storage
 	pruneCallback func(ctx context.Context) error
 
-	// channels used to send new fulfilled heights and config changes to the worker thread
-	fulfilledHeights      chan uint64
-	thresholdChan         chan uint64
-	heightRangeTargetChan chan uint64
+	// headers is used to look up block timestamps, and is required by the max age policy
+	headers storage.Headers
 
-	lastFulfilledHeight uint64
-	lastPrunedHeight    uint64
+	// diskUsage returns the disk usage of the execution data datastore, and is required by
+	// the disk budget policy
+	diskUsage func(ctx context.Context) (uint64, error)
 
-	// the height range is the range of heights between the last pruned and last fulfilled
-	// heightRangeTarget is the target minimum value for this range, so that after pruning
-	// the height range is equal to the target.
-	heightRangeTarget uint64
+	pins *Pins
 
-	// threshold defines the maximum height range and how frequently pruning is performed.
-	// once the height range reaches `heightRangeTarget+threshold`, `threshold` many blocks
-	// are pruned
-	threshold uint64
+	// the following fields track the disk space of pruned data which the datastore has not
+	// reclaimed yet, and are only accessed by the worker thread:
+	// - bytesPerHeight is the estimated disk usage per height at the last evaluation of the disk budget
+	// - unreclaimedBytes is the estimated size of pruned data which is still included in the disk usage
+	// - lastDiskUsage is the disk usage at the last evaluation, when lastDiskUsageHeight was fulfilled
+	bytesPerHeight      uint64
+	unreclaimedBytes    uint64
+	lastDiskUsage       uint64
+	lastDiskUsageHeight uint64
+
+	// channels used to send new fulfilled heights and policy changes to the worker thread
+	fulfilledHeights chan uint64
+	policyUpdates    chan func(*RetentionPolicy)
+
+	// mu protects the fields below, which are only modified by the worker thread, from
+	// concurrent reads by the getters
+	mu                  sync.RWMutex
+	lastFulfilledHeight uint64
+	lastPrunedHeight    uint64
+	policy              RetentionPolicy
 
 	logger  zerolog.Logger
 	metrics module.ExecutionDataPrunerMetrics
@@ -70,14 +110,53 @@ type PrunerOption func(*Pruner)
 // height range target.
 func WithHeightRangeTarget(heightRangeTarget uint64) PrunerOption {
 	return func(p *Pruner) {
-		p.heightRangeTarget = heightRangeTarget
+		p.policy.HeightRangeTarget = heightRangeTarget
 	}
 }
 
 // WithThreshold is used to configure the pruner with a custom threshold.
 func WithThreshold(threshold uint64) PrunerOption {
 	return func(p *Pruner) {
-		p.threshold = threshold
+		p.policy.Threshold = threshold
+	}
+}
+
+// WithMaxAge is used to configure the pruner with a maximum age of the
+// stored data. It requires the WithHeaders option.
+func WithMaxAge(maxAge time.Duration) PrunerOption {
+	return func(p *Pruner) {
+		p.policy.MaxAge = maxAge
+	}
+}
+
+// WithDiskBudget is used to configure the pruner with a maximum disk usage
+// of the stored data. It requires the WithDiskUsage option.
+func WithDiskBudget(diskBudget uint64) PrunerOption {
+	return func(p *Pruner) {
+		p.policy.DiskBudget = diskBudget
+	}
+}
+
+// WithHeaders is used to provide the block headers used by the max age policy.
+func WithHeaders(headers storage.Headers) PrunerOption {
+	return func(p *Pruner) {
+		p.headers = headers
+	}
+}
+
+// WithDiskUsage is used to provide the function returning the disk usage
+// used by the disk budget policy.
+func WithDiskUsage(diskUsage func(context.Context) (uint64, error)) PrunerOption {
+	return func(p *Pruner) {
+		p.diskUsage = diskUsage
+	}
+}
+
+// WithPins is used to share the pins of heights which must not be pruned
+// with the readers of the execution data.
+func WithPins(pins *Pins) PrunerOption {
+	return func(p *Pruner) {
+		p.pins = pins
 	}
 }
 
@@ -103,17 +182,16 @@ func NewPruner(logger zerolog.Logger, metrics module.ExecutionDataPrunerMetrics,
 	fulfilledHeights <- fulfilledHeight
 
 	p := &Pruner{
-		logger:                logger.With().Str("component", "execution_data_pruner").Logger(),
-		storage:               storage,
-		pruneCallback:         func(ctx context.Context) error { return nil },
-		fulfilledHeights:      fulfilledHeights,
-		thresholdChan:         make(chan uint64),
-		heightRangeTargetChan: make(chan uint64),
-		lastFulfilledHeight:   fulfilledHeight,
-		lastPrunedHeight:      lastPrunedHeight,
-		heightRangeTarget:     defaultHeightRangeTarget,
-		threshold:             defaultThreshold,
-		metrics:               metrics,
+		logger:              logger.With().Str("component", "execution_data_pruner").Logger(),
+		storage:             storage,
+		pruneCallback:       func(ctx context.Context) error { return nil },
+		pins:                NewPins(),
+		fulfilledHeights:    fulfilledHeights,
+		policyUpdates:       make(chan func(*RetentionPolicy)),
+		lastFulfilledHeight: fulfilledHeight,
+		lastPrunedHeight:    lastPrunedHeight,
+		policy:              DefaultRetentionPolicy(),
+		metrics:             metrics,
 	}
 	p.cm = component.NewComponentManagerBuilder().
 		AddWorker(p.loop).
@@ -124,6 +202,10 @@ func NewPruner(logger zerolog.Logger, metrics module.ExecutionDataPrunerMetrics,
 		opt(p)
 	}
 
+	if err := p.validatePolicy(p.policy); err != nil {
+		return nil, err
+	}
+
 	return p, nil
 }
 
@@ -143,19 +225,82 @@ func (p *Pruner) NotifyFulfilledHeight(height uint64) {
 // SetHeightRangeTarget updates the Pruner's height range target.
 // This may block for the duration of a pruning operation.
 func (p *Pruner) SetHeightRangeTarget(heightRangeTarget uint64) error {
-	select {
-	case p.heightRangeTargetChan <- heightRangeTarget:
-		return nil
-	case <-p.cm.ShutdownSignal():
-		return component.ErrComponentShutdown
-	}
+	return p.updatePolicy(func(policy *RetentionPolicy) {
+		policy.HeightRangeTarget = heightRangeTarget
+	})
 }
 
 // SetThreshold update's the Pruner's threshold.
 // This may block for the duration of a pruning operation.
 func (p *Pruner) SetThreshold(threshold uint64) error {
+	return p.updatePolicy(func(policy *RetentionPolicy) {
+		policy.Threshold = threshold
+	})
+}
+
+// SetRetentionPolicy replaces the Pruner's retention policy.
+// This may block for the duration of a pruning operation.
+//
+// Returns an error if the policy uses a limit the Pruner was not configured to support, i.e. a
+// max age without headers, or a disk budget without a disk usage function.
+func (p *Pruner) SetRetentionPolicy(policy RetentionPolicy) error {
+	if err := p.validatePolicy(policy); err != nil {
+		return err
+	}
+
+	return p.updatePolicy(func(current *RetentionPolicy) {
+		*current = policy
+	})
+}
+
+// RetentionPolicy returns the Pruner's current retention policy.
+func (p *Pruner) RetentionPolicy() RetentionPolicy {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.policy
+}
+
+// Heights returns the last pruned and last fulfilled heights.
+func (p *Pruner) Heights() (pruned uint64, fulfilled uint64) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.lastPrunedHeight, p.lastFulfilledHeight
+}
+
+// Pins returns the pins of heights which the Pruner must not prune.
+func (p *Pruner) Pins() *Pins {
+	return p.pins
+}
+
+// DiskUsage returns the disk usage of the execution data datastore, and false if the Pruner
+// was not configured with a disk usage function.
+func (p *Pruner) DiskUsage(ctx context.Context) (uint64, bool, error) {
+	if p.diskUsage == nil {
+		return 0, false, nil
+	}
+
+	usage, err := p.diskUsage(ctx)
+	if err != nil {
+		return 0, true, fmt.Errorf("failed to get disk usage: %w", err)
+	}
+	return usage, true, nil
+}
+
+func (p *Pruner) validatePolicy(policy RetentionPolicy) error {
+	if policy.MaxAge > 0 && p.headers == nil {
+		return fmt.Errorf("max age retention requires block headers")
+	}
+	if policy.DiskBudget > 0 && p.diskUsage == nil {
+		return fmt.Errorf("disk budget retention requires a disk usage function")
+	}
+	return nil
+}
+
+func (p *Pruner) updatePolicy(update func(*RetentionPolicy)) error {
 	select {
-	case p.thresholdChan <- threshold:
+	case p.policyUpdates <- update:
 		return nil
 	case <-p.cm.ShutdownSignal():
 		return component.ErrComponentShutdown
@@ -171,39 +316,186 @@ func (p *Pruner) loop(ctx irrecoverable.SignalerContext, ready component.ReadyFu
 			return
 		case height := <-p.fulfilledHeights:
 			if height > p.lastFulfilledHeight {
+				p.mu.Lock()
 				p.lastFulfilledHeight = height
+				p.mu.Unlock()
 			}
 			p.checkPrune(ctx)
-		case heightRangeTarget := <-p.heightRangeTargetChan:
-			p.heightRangeTarget = heightRangeTarget
-			p.checkPrune(ctx)
-		case threshold := <-p.thresholdChan:
-			p.threshold = threshold
+		case update := <-p.policyUpdates:
+			p.mu.Lock()
+			update(&p.policy)
+			p.mu.Unlock()
 			p.checkPrune(ctx)
 		}
 	}
 }
 
 func (p *Pruner) checkPrune(ctx irrecoverable.SignalerContext) {
-	if p.lastFulfilledHeight > p.heightRangeTarget+p.threshold+p.lastPrunedHeight {
-		pruneHeight := p.lastFulfilledHeight - p.heightRangeTarget
+	pruneHeight, err := p.pruneHeight(ctx)
+	if err != nil {
+		ctx.Throw(fmt.Errorf("failed to determine prune height: %w", err))
+	}
+
+	if pruneHeight <= p.lastPrunedHeight {
+		return
+	}
+
+	p.logger.Info().Uint64("prune_height", pruneHeight).Msg("pruning storage")
+	start := time.Now()
+
+	if err := p.storage.PruneUpToHeight(pruneHeight); err != nil {
+		ctx.Throw(fmt.Errorf("failed to prune: %w", err))
+	}
+
+	if err := p.pruneCallback(ctx); err != nil {
+		ctx.Throw(err)
+	}
+
+	// the pruned data is still included in the disk usage, until the datastore reclaims its space
+	p.unreclaimedBytes += (pruneHeight - p.lastPrunedHeight) * p.bytesPerHeight
+
+	duration := time.Since(start)
+	p.logger.Info().Dur("duration", duration).Msg("pruned storage")
+
+	p.metrics.Pruned(pruneHeight, duration)
+
+	p.mu.Lock()
+	p.lastPrunedHeight = pruneHeight
+	p.mu.Unlock()
+}
+
+// pruneHeight returns the height up to which data should be pruned according to the retention
+// policy and the pinned heights. Data should not be pruned if the returned height is not above
+// the last pruned height.
+func (p *Pruner) pruneHeight(ctx context.Context) (uint64, error) {
+	policy := p.policy
+	fulfilled := p.lastFulfilledHeight
+	pruned := p.lastPrunedHeight
+	pruneHeight := pruned
+
+	// the height and age limits can only be exceeded by more than the threshold if there are
+	// more than threshold heights stored
+	if fulfilled > pruned+policy.Threshold {
+		if policy.HeightRangeTarget > 0 && fulfilled > policy.HeightRangeTarget+policy.Threshold+pruned {
+			pruneHeight = fulfilled - policy.HeightRangeTarget
+		}
+
+		if policy.MaxAge > 0 {
+			height, err := p.highestHeightBefore(time.Now().Add(-policy.MaxAge), pruned, fulfilled)
+			if err != nil {
+				return 0, err
+			}
+			if height > pruned+policy.Threshold && height > pruneHeight {
+				pruneHeight = height
+			}
+		}
+	}
 
-		p.logger.Info().Uint64("prune_height", pruneHeight).Msg("pruning storage")
-		start := time.Now()
+	if policy.DiskBudget == 0 {
+		p.bytesPerHeight = 0
+		p.unreclaimedBytes = 0
+	}
 
-		if err := p.storage.PruneUpToHeight(pruneHeight); err != nil {
-			ctx.Throw(fmt.Errorf("failed to prune: %w", err))
+	if policy.DiskBudget > 0 && fulfilled > pruned {
+		usage, err := p.retainedDiskUsage(ctx, fulfilled)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get disk usage: %w", err)
 		}
 
-		if err := p.pruneCallback(ctx); err != nil {
-			ctx.Throw(err)
+		// assume data is spread evenly across the stored heights
+		p.bytesPerHeight = usage / (fulfilled - pruned)
+		if p.bytesPerHeight == 0 {
+			p.bytesPerHeight = 1
 		}
 
-		duration := time.Since(start)
-		p.logger.Info().Dur("duration", duration).Msg("pruned storage")
+		if usage > policy.DiskBudget {
+			bytesPerHeight := p.bytesPerHeight
+			excessHeights := (usage - policy.DiskBudget + bytesPerHeight - 1) / bytesPerHeight
+
+			height := pruned + excessHeights + policy.Threshold
+			if height > fulfilled {
+				height = fulfilled
+			}
+			if height > pruneHeight {
+				pruneHeight = height
+			}
+
+			p.logger.Debug().
+				Uint64("disk_usage", usage).
+				Uint64("unreclaimed_bytes", p.unreclaimedBytes).
+				Uint64("disk_budget", policy.DiskBudget).
+				Msg("disk budget exceeded")
+		}
+	}
 
-		p.metrics.Pruned(pruneHeight, duration)
+	if lowest, ok := p.pins.Lowest(); ok && lowest <= pruneHeight {
+		p.logger.Debug().
+			Uint64("prune_height", pruneHeight).
+			Uint64("lowest_pinned_height", lowest).
+			Msg("pruning limited by pinned height")
 
-		p.lastPrunedHeight = pruneHeight
+		if lowest == 0 {
+			return pruned, nil
+		}
+		pruneHeight = lowest - 1
+	}
+
+	return pruneHeight, nil
+}
+
+// retainedDiskUsage returns the disk usage of the data which is not pruned yet, i.e. the measured
+// disk usage minus the estimated size of the pruned data whose disk space is not reclaimed yet.
+//
+// The datastore reclaims disk space some time after pruning, e.g. after value log garbage collection
+// and compactions. Since data is only deleted by pruning, the difference between the disk usage
+// expected from the data added since the last evaluation and the measured disk usage is attributed
+// to reclaimed space.
+func (p *Pruner) retainedDiskUsage(ctx context.Context, fulfilled uint64) (uint64, error) {
+	usage, err := p.diskUsage(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if p.unreclaimedBytes > 0 {
+		expected := p.lastDiskUsage
+		if fulfilled > p.lastDiskUsageHeight {
+			expected += (fulfilled - p.lastDiskUsageHeight) * p.bytesPerHeight
+		}
+
+		if expected > usage {
+			reclaimed := expected - usage
+			if reclaimed >= p.unreclaimedBytes {
+				p.unreclaimedBytes = 0
+			} else {
+				p.unreclaimedBytes -= reclaimed
+			}
+		}
+	}
+	p.lastDiskUsage = usage
+	p.lastDiskUsageHeight = fulfilled
+
+	if p.unreclaimedBytes >= usage {
+		return 0, nil
+	}
+	return usage - p.unreclaimedBytes, nil
+}
+
+// highestHeightBefore returns the highest height in (low, high] whose block timestamp is before
+// the given time, or low if there is none.
+func (p *Pruner) highestHeightBefore(before time.Time, low uint64, high uint64) (uint64, error) {
+	for low < high {
+		mid := low + (high-low+1)/2
+
+		header, err := p.headers.ByHeight(mid)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get header for height %d: %w", mid, err)
+		}
+
+		if header.Timestamp.Before(before) {
+			low = mid
+		} else {
+			high = mid - 1
+		}
 	}
+	return low, nil
 }
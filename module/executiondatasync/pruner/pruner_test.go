@@ -6,12 +6,15 @@ import (
 	"time"
 
 	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module/executiondatasync/pruner"
 	mocktracker "github.com/onflow/flow-go/module/executiondatasync/tracker/mock"
 	"github.com/onflow/flow-go/module/irrecoverable"
 	"github.com/onflow/flow-go/module/metrics"
+	storagemock "github.com/onflow/flow-go/storage/mock"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
@@ -172,3 +175,294 @@ func TestUpdateHeightRangeTarget(t *testing.T) {
 	default:
 	}
 }
+
+func TestMaxAgePrune(t *testing.T) {
+	trackerStorage := new(mocktracker.Storage)
+	trackerStorage.On("GetFulfilledHeight").Return(uint64(20), nil).Once()
+	trackerStorage.On("GetPrunedHeight").Return(uint64(0), nil).Once()
+
+	// blocks 1 to 12 are older than an hour, blocks 13 to 20 are recent
+	headers := storagemock.NewHeaders(t)
+	headers.On("ByHeight", mock.Anything).Return(func(height uint64) *flow.Header {
+		header := unittest.BlockHeaderFixture(unittest.WithHeaderHeight(height))
+		header.Timestamp = time.Now().Add(-time.Minute)
+		if height <= 12 {
+			header.Timestamp = time.Now().Add(-2 * time.Hour)
+		}
+		return header
+	}, nil)
+
+	pruner, err := pruner.NewPruner(
+		zerolog.Nop(),
+		metrics.NewNoopCollector(),
+		trackerStorage,
+		pruner.WithHeightRangeTarget(0),
+		pruner.WithThreshold(5),
+		pruner.WithMaxAge(time.Hour),
+		pruner.WithHeaders(headers),
+	)
+	require.NoError(t, err)
+	trackerStorage.AssertExpectations(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	signalerCtx, errChan := irrecoverable.WithSignaler(ctx)
+
+	pruned := make(chan struct{})
+	trackerStorage.On("PruneUpToHeight", uint64(12)).Return(func(height uint64) error {
+		close(pruned)
+		return nil
+	}).Once()
+
+	pruner.Start(signalerCtx)
+	unittest.AssertClosesBefore(t, pruned, time.Second)
+	trackerStorage.AssertExpectations(t)
+
+	cancel()
+	<-pruner.Done()
+
+	select {
+	case err := <-errChan:
+		require.NoError(t, err)
+	default:
+	}
+}
+
+func TestDiskBudgetPrune(t *testing.T) {
+	trackerStorage := new(mocktracker.Storage)
+	trackerStorage.On("GetFulfilledHeight").Return(uint64(10), nil).Once()
+	trackerStorage.On("GetPrunedHeight").Return(uint64(0), nil).Once()
+
+	// 10 heights use 1000 bytes, so 3 heights need to be pruned to get below the budget
+	diskUsage := func(context.Context) (uint64, error) {
+		return 1000, nil
+	}
+
+	pruner, err := pruner.NewPruner(
+		zerolog.Nop(),
+		metrics.NewNoopCollector(),
+		trackerStorage,
+		pruner.WithHeightRangeTarget(100),
+		pruner.WithThreshold(2),
+		pruner.WithDiskBudget(750),
+		pruner.WithDiskUsage(diskUsage),
+	)
+	require.NoError(t, err)
+	trackerStorage.AssertExpectations(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	signalerCtx, errChan := irrecoverable.WithSignaler(ctx)
+
+	pruned := make(chan struct{})
+	trackerStorage.On("PruneUpToHeight", uint64(5)).Return(func(height uint64) error {
+		close(pruned)
+		return nil
+	}).Once()
+
+	pruner.Start(signalerCtx)
+	unittest.AssertClosesBefore(t, pruned, time.Second)
+	trackerStorage.AssertExpectations(t)
+
+	cancel()
+	<-pruner.Done()
+
+	select {
+	case err := <-errChan:
+		require.NoError(t, err)
+	default:
+	}
+}
+
+// TestDiskBudgetPruneUnreclaimedSpace tests that pruned data whose disk space is not reclaimed yet
+// does not cause further pruning.
+func TestDiskBudgetPruneUnreclaimedSpace(t *testing.T) {
+	trackerStorage := new(mocktracker.Storage)
+	trackerStorage.On("GetFulfilledHeight").Return(uint64(10), nil).Once()
+	trackerStorage.On("GetPrunedHeight").Return(uint64(0), nil).Once()
+
+	// each evaluation of the disk budget reads the disk usage sent on the channel
+	usages := make(chan uint64)
+	diskUsage := func(ctx context.Context) (uint64, error) {
+		select {
+		case usage := <-usages:
+			return usage, nil
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+	sendUsage := func(usage uint64) {
+		select {
+		case usages <- usage:
+		case <-time.After(time.Second):
+			require.Fail(t, "disk usage was not requested")
+		}
+	}
+
+	pruner, err := pruner.NewPruner(
+		zerolog.Nop(),
+		metrics.NewNoopCollector(),
+		trackerStorage,
+		pruner.WithHeightRangeTarget(100),
+		pruner.WithThreshold(2),
+		pruner.WithDiskBudget(750),
+		pruner.WithDiskUsage(diskUsage),
+	)
+	require.NoError(t, err)
+	trackerStorage.AssertExpectations(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	signalerCtx, errChan := irrecoverable.WithSignaler(ctx)
+
+	pruned := make(chan struct{})
+	trackerStorage.On("PruneUpToHeight", uint64(5)).Return(func(height uint64) error {
+		close(pruned)
+		return nil
+	}).Once()
+
+	// 10 heights use 1000 bytes, so 3 heights need to be pruned to get below the budget
+	pruner.Start(signalerCtx)
+	sendUsage(1000)
+	unittest.AssertClosesBefore(t, pruned, time.Second)
+
+	// the disk usage does not drop right after pruning, and grows with the new height. the pruned
+	// heights are still included in the disk usage, so nothing is pruned
+	pruner.NotifyFulfilledHeight(11)
+	sendUsage(1000)
+
+	// the disk space of the pruned heights is reclaimed, and the data of the 7 remaining heights
+	// is within the budget
+	pruner.NotifyFulfilledHeight(12)
+	sendUsage(600)
+
+	// the disk usage of the 9 remaining heights exceeds the budget, so 2 heights are pruned
+	pruned = make(chan struct{})
+	trackerStorage.On("PruneUpToHeight", uint64(9)).Return(func(height uint64) error {
+		close(pruned)
+		return nil
+	}).Once()
+
+	pruner.NotifyFulfilledHeight(14)
+	sendUsage(900)
+	unittest.AssertClosesBefore(t, pruned, time.Second)
+	trackerStorage.AssertExpectations(t)
+
+	cancel()
+	<-pruner.Done()
+
+	select {
+	case err := <-errChan:
+		require.NoError(t, err)
+	default:
+	}
+}
+
+func TestPinnedHeightsNotPruned(t *testing.T) {
+	trackerStorage := new(mocktracker.Storage)
+	trackerStorage.On("GetFulfilledHeight").Return(uint64(0), nil).Once()
+	trackerStorage.On("GetPrunedHeight").Return(uint64(0), nil).Once()
+
+	pins := pruner.NewPins()
+	pin := pins.Pin(4)
+
+	pruner, err := pruner.NewPruner(
+		zerolog.Nop(),
+		metrics.NewNoopCollector(),
+		trackerStorage,
+		pruner.WithHeightRangeTarget(10),
+		pruner.WithThreshold(5),
+		pruner.WithPins(pins),
+	)
+	require.NoError(t, err)
+	trackerStorage.AssertExpectations(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	signalerCtx, errChan := irrecoverable.WithSignaler(ctx)
+
+	pruner.Start(signalerCtx)
+
+	// pruning is limited to the heights below the pinned height
+	pruned := make(chan struct{})
+	trackerStorage.On("PruneUpToHeight", uint64(3)).Return(func(height uint64) error {
+		close(pruned)
+		return nil
+	}).Once()
+
+	pruner.NotifyFulfilledHeight(16)
+	unittest.AssertClosesBefore(t, pruned, time.Second)
+	trackerStorage.AssertExpectations(t)
+
+	// once the pin is released, pruning resumes up to the height range target
+	pin.Release()
+	require.Equal(t, 0, pins.Count())
+
+	pruned = make(chan struct{})
+	trackerStorage.On("PruneUpToHeight", uint64(9)).Return(func(height uint64) error {
+		close(pruned)
+		return nil
+	}).Once()
+
+	pruner.NotifyFulfilledHeight(19)
+	unittest.AssertClosesBefore(t, pruned, time.Second)
+	trackerStorage.AssertExpectations(t)
+
+	cancel()
+	<-pruner.Done()
+
+	select {
+	case err := <-errChan:
+		require.NoError(t, err)
+	default:
+	}
+}
+
+func TestSetRetentionPolicy(t *testing.T) {
+	trackerStorage := new(mocktracker.Storage)
+	trackerStorage.On("GetFulfilledHeight").Return(uint64(15), nil).Once()
+	trackerStorage.On("GetPrunedHeight").Return(uint64(0), nil).Once()
+
+	maxAgePolicy := pruner.RetentionPolicy{MaxAge: time.Hour}
+	diskBudgetPolicy := pruner.RetentionPolicy{DiskBudget: 1000}
+	policy := pruner.RetentionPolicy{HeightRangeTarget: 8, Threshold: 2}
+
+	pruner, err := pruner.NewPruner(
+		zerolog.Nop(),
+		metrics.NewNoopCollector(),
+		trackerStorage,
+		pruner.WithHeightRangeTarget(10),
+		pruner.WithThreshold(10),
+	)
+	require.NoError(t, err)
+	trackerStorage.AssertExpectations(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	signalerCtx, errChan := irrecoverable.WithSignaler(ctx)
+
+	pruner.Start(signalerCtx)
+
+	// policies which need dependencies the pruner was not configured with are rejected
+	require.Error(t, pruner.SetRetentionPolicy(maxAgePolicy))
+	require.Error(t, pruner.SetRetentionPolicy(diskBudgetPolicy))
+
+	pruned := make(chan struct{})
+	trackerStorage.On("PruneUpToHeight", uint64(7)).Return(func(height uint64) error {
+		close(pruned)
+		return nil
+	}).Once()
+
+	require.NoError(t, pruner.SetRetentionPolicy(policy))
+	unittest.AssertClosesBefore(t, pruned, time.Second)
+	trackerStorage.AssertExpectations(t)
+
+	require.Equal(t, policy, pruner.RetentionPolicy())
+	prunedHeight, fulfilledHeight := pruner.Heights()
+	require.Equal(t, uint64(7), prunedHeight)
+	require.Equal(t, uint64(15), fulfilledHeight)
+
+	cancel()
+	<-pruner.Done()
+
+	select {
+	case err := <-errChan:
+		require.NoError(t, err)
+	default:
+	}
+}
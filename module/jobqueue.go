@@ -10,6 +10,15 @@ const (
 
 	ConsumeProgressExecutionDataRequesterBlockHeight  = "ConsumeProgressExecutionDataRequesterBlockHeight"
 	ConsumeProgressExecutionDataRequesterNotification = "ConsumeProgressExecutionDataRequesterNotification"
+
+	ConsumeProgressEventsIndexerLowestHeight  = "ConsumeProgressEventsIndexerLowestHeight"
+	ConsumeProgressEventsIndexerHighestHeight = "ConsumeProgressEventsIndexerHighestHeight"
+
+	ConsumeProgressRegistersIndexerLowestHeight  = "ConsumeProgressRegistersIndexerLowestHeight"
+	ConsumeProgressRegistersIndexerHighestHeight = "ConsumeProgressRegistersIndexerHighestHeight"
+
+	ConsumeProgressFollowerFinalizedBlockHeight = "ConsumeProgressFollowerFinalizedBlockHeight"
+	ConsumeProgressFollowerSealedBlockHeight    = "ConsumeProgressFollowerSealedBlockHeight"
 )
 
 // JobID is a unique ID of the job.
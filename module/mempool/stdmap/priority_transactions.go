@@ -0,0 +1,334 @@
+package stdmap
+
+import (
+	"container/heap"
+	"sort"
+	"sync"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/mempool"
+)
+
+// TransactionPriority returns the inclusion priority of a transaction. Transactions with a
+// higher priority are included in collections first.
+type TransactionPriority func(tx *flow.TransactionBody) uint64
+
+// proposalKeyID identifies a proposal key, independent of the sequence number.
+type proposalKeyID struct {
+	address  flow.Address
+	keyIndex uint64
+}
+
+// prioritizedTransaction is a transaction in the PriorityTransactions mempool.
+type prioritizedTransaction struct {
+	tx       *flow.TransactionBody
+	id       flow.Identifier
+	priority uint64
+	added    uint64 // insertion counter, lower values were added earlier
+	index    int    // position in the eviction heap, only set for the last transaction of a proposal key
+}
+
+// PriorityTransactions implements the transactions memory pool of the collection nodes,
+// ordered by inclusion priority: All returns transactions with a higher priority first, and
+// transactions with the same priority in the order they were added. Transactions with the
+// same proposal key are returned in the order of their sequence numbers, so that dependent
+// transactions are included in collections in order. A transaction inherits the priority of
+// the transactions with the same proposal key and a higher sequence number, so a low priority
+// transaction doesn't hold back the transactions which depend on it.
+//
+// When the mempool is full, adding a transaction evicts the transaction with the lowest
+// priority, unless the added transaction has a lower priority itself. Only the transaction with
+// the highest sequence number of each proposal key can be evicted, so that evictions never leave
+// behind transactions whose predecessors are gone. As transactions inherit the priority of the
+// transactions after them, this evicts the transaction with the lowest inherited priority.
+type PriorityTransactions struct {
+	mu       sync.RWMutex
+	limit    uint
+	priority TransactionPriority
+	counter  uint64
+	byID     map[flow.Identifier]*prioritizedTransaction
+	// transactions of each proposal key, in sequence number order
+	byKey map[proposalKeyID][]*prioritizedTransaction
+	// last transaction of each proposal key, by priority
+	eviction evictionHeap
+}
+
+var _ mempool.Transactions = (*PriorityTransactions)(nil)
+
+// NewPriorityTransactions creates a new memory pool for transactions, which orders the
+// transactions by the given priority.
+func NewPriorityTransactions(limit uint, priority TransactionPriority) *PriorityTransactions {
+	return &PriorityTransactions{
+		limit:    limit,
+		priority: priority,
+		byID:     make(map[flow.Identifier]*prioritizedTransaction),
+		byKey:    make(map[proposalKeyID][]*prioritizedTransaction),
+	}
+}
+
+// Has checks whether the transaction with the given ID is in the mempool.
+func (t *PriorityTransactions) Has(txID flow.Identifier) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	_, ok := t.byID[txID]
+	return ok
+}
+
+// Add adds a transaction to the mempool. It returns false if the transaction was already in
+// the mempool, or if the mempool is full and the transaction has a lower priority than all
+// transactions in the mempool.
+func (t *PriorityTransactions) Add(tx *flow.TransactionBody) bool {
+	txID := tx.ID()
+	priority := t.priority(tx)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.byID[txID]; ok {
+		return false
+	}
+
+	entry := &prioritizedTransaction{
+		tx:       tx,
+		id:       txID,
+		priority: priority,
+		added:    t.counter,
+	}
+	t.counter++
+	t.insert(entry)
+
+	// the transaction is added before evicting, as it changes which transaction of its proposal
+	// key can be evicted. It is evicted itself if it has the lowest priority.
+	if t.limit > 0 && uint(len(t.byID)) > t.limit {
+		evicted := t.eviction[0]
+		t.remove(evicted)
+		if evicted == entry {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Remove removes the transaction with the given ID from the mempool. It returns true if the
+// transaction was known and removed.
+func (t *PriorityTransactions) Remove(txID flow.Identifier) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	entry, ok := t.byID[txID]
+	if !ok {
+		return false
+	}
+	t.remove(entry)
+	return true
+}
+
+// insert adds the transaction to the mempool indices. If it becomes the last transaction of its
+// proposal key, it replaces the previous last transaction in the eviction heap.
+func (t *PriorityTransactions) insert(entry *prioritizedTransaction) {
+	key := proposalKeyOf(entry.tx)
+	chain := t.byKey[key]
+
+	i := sort.Search(len(chain), func(i int) bool { return inSequence(entry, chain[i]) })
+	chain = append(chain, nil)
+	copy(chain[i+1:], chain[i:])
+	chain[i] = entry
+	t.byKey[key] = chain
+	t.byID[entry.id] = entry
+
+	if i == len(chain)-1 {
+		if i > 0 {
+			heap.Remove(&t.eviction, chain[i-1].index)
+		}
+		heap.Push(&t.eviction, entry)
+	}
+}
+
+// remove removes the transaction from the mempool indices. If it was the last transaction of its
+// proposal key, the transaction before it replaces it in the eviction heap.
+func (t *PriorityTransactions) remove(entry *prioritizedTransaction) {
+	key := proposalKeyOf(entry.tx)
+	chain := t.byKey[key]
+
+	i := 0
+	for chain[i] != entry {
+		i++
+	}
+	last := i == len(chain)-1
+	chain = append(chain[:i], chain[i+1:]...)
+	delete(t.byID, entry.id)
+
+	if last {
+		heap.Remove(&t.eviction, entry.index)
+		if len(chain) > 0 {
+			heap.Push(&t.eviction, chain[len(chain)-1])
+		}
+	}
+
+	if len(chain) == 0 {
+		delete(t.byKey, key)
+		return
+	}
+	t.byKey[key] = chain
+}
+
+// ByID returns the transaction with the given ID from the mempool.
+func (t *PriorityTransactions) ByID(txID flow.Identifier) (*flow.TransactionBody, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	entry, ok := t.byID[txID]
+	if !ok {
+		return nil, false
+	}
+	return entry.tx, true
+}
+
+// Size returns the number of transactions in the mempool.
+func (t *PriorityTransactions) Size() uint {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return uint(len(t.byID))
+}
+
+// All returns all transactions from the mempool, in inclusion order.
+func (t *PriorityTransactions) All() []*flow.TransactionBody {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	// the transactions of each proposal key are already in sequence number order
+	queue := make(inclusionQueue, 0, len(t.byKey))
+	for _, chain := range t.byKey {
+		group := &transactionGroup{entries: chain}
+		group.prioritize()
+		queue = append(queue, group)
+	}
+	heap.Init(&queue)
+
+	// repeatedly take the next transaction of the group with the highest priority
+	txs := make([]*flow.TransactionBody, 0, len(t.byID))
+	for queue.Len() > 0 {
+		group := queue[0]
+		txs = append(txs, group.entries[group.next].tx)
+		group.next++
+		if group.next == len(group.entries) {
+			heap.Pop(&queue)
+			continue
+		}
+		heap.Fix(&queue, 0)
+	}
+
+	return txs
+}
+
+// Clear removes all transactions from the mempool.
+func (t *PriorityTransactions) Clear() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.byID = make(map[flow.Identifier]*prioritizedTransaction)
+	t.byKey = make(map[proposalKeyID][]*prioritizedTransaction)
+	t.eviction = nil
+}
+
+// proposalKeyOf returns the proposal key of the transaction.
+func proposalKeyOf(tx *flow.TransactionBody) proposalKeyID {
+	return proposalKeyID{
+		address:  tx.ProposalKey.Address,
+		keyIndex: tx.ProposalKey.KeyIndex,
+	}
+}
+
+// transactionGroup holds the transactions with the same proposal key, in sequence number order.
+type transactionGroup struct {
+	entries []*prioritizedTransaction
+	// effective priority of each transaction: the highest priority of the transaction and
+	// the transactions after it
+	effective []uint64
+	next      int
+}
+
+// prioritize computes the effective priority of the transactions.
+func (g *transactionGroup) prioritize() {
+	g.effective = make([]uint64, len(g.entries))
+	var highest uint64
+	for i := len(g.entries) - 1; i >= 0; i-- {
+		if g.entries[i].priority > highest {
+			highest = g.entries[i].priority
+		}
+		g.effective[i] = highest
+	}
+}
+
+// inSequence returns whether a is included before b, for transactions with the same proposal key.
+func inSequence(a, b *prioritizedTransaction) bool {
+	if a.tx.ProposalKey.SequenceNumber != b.tx.ProposalKey.SequenceNumber {
+		return a.tx.ProposalKey.SequenceNumber < b.tx.ProposalKey.SequenceNumber
+	}
+	return higherPriority(a, b)
+}
+
+// higherPriority returns whether a is included before b, without considering proposal keys.
+func higherPriority(a, b *prioritizedTransaction) bool {
+	if a.priority != b.priority {
+		return a.priority > b.priority
+	}
+	return a.added < b.added
+}
+
+// inclusionQueue is a max-heap of transaction groups, by the effective priority of their next
+// transaction.
+type inclusionQueue []*transactionGroup
+
+func (q inclusionQueue) Len() int { return len(q) }
+
+func (q inclusionQueue) Less(i, j int) bool {
+	a, b := q[i], q[j]
+	if a.effective[a.next] != b.effective[b.next] {
+		return a.effective[a.next] > b.effective[b.next]
+	}
+	return a.entries[a.next].added < b.entries[b.next].added
+}
+
+func (q inclusionQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *inclusionQueue) Push(x interface{}) { *q = append(*q, x.(*transactionGroup)) }
+
+func (q *inclusionQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	group := old[n-1]
+	*q = old[:n-1]
+	return group
+}
+
+// evictionHeap is a min-heap of the last transactions of each proposal key, by priority. Among
+// transactions with the same priority, the most recently added transaction is evicted first.
+type evictionHeap []*prioritizedTransaction
+
+func (h evictionHeap) Len() int { return len(h) }
+
+func (h evictionHeap) Less(i, j int) bool { return higherPriority(h[j], h[i]) }
+
+func (h evictionHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *evictionHeap) Push(x interface{}) {
+	entry := x.(*prioritizedTransaction)
+	entry.index = len(*h)
+	*h = append(*h, entry)
+}
+
+func (h *evictionHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	entry := old[n-1]
+	*h = old[:n-1]
+	return entry
+}
@@ -0,0 +1,161 @@
+package stdmap_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/mempool/stdmap"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// byGasLimit uses the gas limit as priority.
+func byGasLimit(tx *flow.TransactionBody) uint64 {
+	return tx.GasLimit
+}
+
+func transactionFixture(gasLimit uint64, key flow.Address, seqNum uint64) *flow.TransactionBody {
+	tx := unittest.TransactionBodyFixture(func(tx *flow.TransactionBody) {
+		tx.GasLimit = gasLimit
+		tx.ProposalKey = flow.ProposalKey{Address: key, KeyIndex: 0, SequenceNumber: seqNum}
+	})
+	return &tx
+}
+
+func TestPriorityTransactionPool(t *testing.T) {
+	tx1 := transactionFixture(10, unittest.RandomAddressFixture(), 0)
+	tx2 := transactionFixture(20, unittest.RandomAddressFixture(), 0)
+
+	pool := stdmap.NewPriorityTransactions(1000, byGasLimit)
+
+	t.Run("should be able to add", func(t *testing.T) {
+		assert.True(t, pool.Add(tx1))
+		assert.True(t, pool.Add(tx2))
+		assert.False(t, pool.Add(tx1))
+		assert.EqualValues(t, 2, pool.Size())
+	})
+
+	t.Run("should be able to get", func(t *testing.T) {
+		assert.True(t, pool.Has(tx1.ID()))
+		got, exists := pool.ByID(tx1.ID())
+		assert.True(t, exists)
+		assert.Equal(t, tx1, got)
+	})
+
+	t.Run("should be able to retrieve all by priority", func(t *testing.T) {
+		assert.Equal(t, []*flow.TransactionBody{tx2, tx1}, pool.All())
+	})
+
+	t.Run("should be able to remove", func(t *testing.T) {
+		assert.True(t, pool.Remove(tx2.ID()))
+		assert.False(t, pool.Remove(tx2.ID()))
+		assert.False(t, pool.Has(tx2.ID()))
+		assert.Equal(t, []*flow.TransactionBody{tx1}, pool.All())
+	})
+
+	t.Run("should be able to clear", func(t *testing.T) {
+		pool.Clear()
+		assert.Equal(t, uint(0), pool.Size())
+		assert.Empty(t, pool.All())
+		assert.True(t, pool.Add(tx1))
+	})
+}
+
+// TestPriorityTransactionPool_Ordering tests that transactions are ordered by priority, then
+// by age, and that transactions with the same proposal key are ordered by sequence number.
+func TestPriorityTransactionPool_Ordering(t *testing.T) {
+	pool := stdmap.NewPriorityTransactions(1000, byGasLimit)
+
+	key1 := unittest.RandomAddressFixture()
+	key2 := unittest.RandomAddressFixture()
+	key3 := unittest.RandomAddressFixture()
+
+	// transactions of key1 with increasing priority, added in reverse sequence order
+	k1s2 := transactionFixture(300, key1, 2)
+	k1s1 := transactionFixture(20, key1, 1)
+	k1s0 := transactionFixture(10, key1, 0)
+	// independent transactions with the same priority
+	k2 := transactionFixture(100, key2, 0)
+	k3 := transactionFixture(100, key3, 0)
+
+	for _, tx := range []*flow.TransactionBody{k1s2, k1s1, k1s0, k2, k3} {
+		require.True(t, pool.Add(tx))
+	}
+
+	// the transactions of key1 inherit the priority of the last one
+	expected := []*flow.TransactionBody{k1s0, k1s1, k1s2, k2, k3}
+	assert.Equal(t, expected, pool.All())
+
+	// without the high priority transaction, key1's transactions are included last
+	require.True(t, pool.Remove(k1s2.ID()))
+	expected = []*flow.TransactionBody{k2, k3, k1s0, k1s1}
+	assert.Equal(t, expected, pool.All())
+}
+
+// TestPriorityTransactionPool_Eviction tests that the transactions with the lowest priority are
+// evicted when the mempool is full.
+func TestPriorityTransactionPool_Eviction(t *testing.T) {
+	pool := stdmap.NewPriorityTransactions(2, byGasLimit)
+
+	low := transactionFixture(10, unittest.RandomAddressFixture(), 0)
+	mid := transactionFixture(20, unittest.RandomAddressFixture(), 0)
+	high := transactionFixture(30, unittest.RandomAddressFixture(), 0)
+	lowest := transactionFixture(5, unittest.RandomAddressFixture(), 0)
+
+	require.True(t, pool.Add(low))
+	require.True(t, pool.Add(mid))
+
+	// a higher priority transaction evicts the lowest priority transaction
+	assert.True(t, pool.Add(high))
+	assert.False(t, pool.Has(low.ID()))
+	assert.EqualValues(t, 2, pool.Size())
+
+	// a lower priority transaction is rejected
+	assert.False(t, pool.Add(lowest))
+	assert.Equal(t, []*flow.TransactionBody{high, mid}, pool.All())
+}
+
+// TestPriorityTransactionPool_EvictionDependents tests that only the last transaction of a proposal
+// key is evicted, so that no transaction is left without the transactions before it.
+func TestPriorityTransactionPool_EvictionDependents(t *testing.T) {
+	pool := stdmap.NewPriorityTransactions(3, byGasLimit)
+
+	key := unittest.RandomAddressFixture()
+	s0 := transactionFixture(5, key, 0)
+	s1 := transactionFixture(30, key, 1)
+	other := transactionFixture(10, unittest.RandomAddressFixture(), 0)
+
+	require.True(t, pool.Add(s0))
+	require.True(t, pool.Add(s1))
+	require.True(t, pool.Add(other))
+
+	// s0 has the lowest priority, but evicting it would leave s1 behind, so the transaction with
+	// the lowest inherited priority is evicted instead
+	high := transactionFixture(20, unittest.RandomAddressFixture(), 0)
+	assert.True(t, pool.Add(high))
+	assert.False(t, pool.Has(other.ID()))
+	assert.Equal(t, []*flow.TransactionBody{s0, s1, high}, pool.All())
+
+	// the transactions of the key are evicted from the highest sequence number down
+	higher := transactionFixture(40, unittest.RandomAddressFixture(), 0)
+	assert.True(t, pool.Add(higher))
+	assert.False(t, pool.Has(high.ID()))
+	highest := transactionFixture(50, unittest.RandomAddressFixture(), 0)
+	assert.True(t, pool.Add(highest))
+	assert.False(t, pool.Has(s1.ID()))
+	assert.True(t, pool.Has(s0.ID()))
+	assert.Equal(t, []*flow.TransactionBody{highest, higher, s0}, pool.All())
+
+	// a missing predecessor inherits the priority of the transactions after it, so it can be
+	// added even if it has the lowest priority
+	pool = stdmap.NewPriorityTransactions(2, byGasLimit)
+	s2 := transactionFixture(30, key, 2)
+	require.True(t, pool.Add(s2))
+	require.True(t, pool.Add(other))
+	predecessor := transactionFixture(1, key, 1)
+	assert.True(t, pool.Add(predecessor))
+	assert.False(t, pool.Has(other.ID()))
+	assert.Equal(t, []*flow.TransactionBody{predecessor, s2}, pool.All())
+}
@@ -282,6 +282,10 @@ type HotstuffMetrics interface {
 	// SetTimeout sets the current timeout duration
 	SetTimeout(duration time.Duration)
 
+	// SetTimeoutConfig reports the current parameters of the pacemaker's timeout, which can be
+	// updated while the node is running. It is called on startup, and after each update.
+	SetTimeoutConfig(minReplicaTimeout time.Duration, maxReplicaTimeout time.Duration, timeoutAdjustmentFactor float64, happyPathMaxRoundFailures uint64, blockRateDelay time.Duration)
+
 	// BlockProcessingDuration measures the time which the compliance engine
 	// spends to process one block proposal.
 	BlockProcessingDuration(duration time.Duration)
@@ -693,6 +697,49 @@ type TransactionMetrics interface {
 
 	// UpdateExecutionReceiptMaxHeight is called whenever we store an execution receipt from a block from a newer height
 	UpdateExecutionReceiptMaxHeight(height uint64)
+
+	// TransactionRetryQueued tracks transactions added to the queue of transactions to resubmit
+	TransactionRetryQueued()
+
+	// TransactionRetried tracks transactions resubmitted to collection nodes
+	TransactionRetried()
+
+	// TransactionRetryExpired tracks transactions dropped from the resubmission queue because they expired
+	TransactionRetryExpired(count int)
+
+	// TransactionRetryQueueSize tracks the number of transactions in the resubmission queue
+	TransactionRetryQueueSize(size int)
+}
+
+// TransactionValidationMetrics tracks the optional transaction validation checks which depend on
+// the execution state at the latest sealed block.
+type TransactionValidationMetrics interface {
+	// TransactionValidationFailed tracks transactions which failed the given check. In dry-run
+	// mode, these transactions are not rejected.
+	TransactionValidationFailed(check string, dryRun bool)
+
+	// TransactionValidationSkipped tracks checks which could not be performed, because the state
+	// could not be read.
+	TransactionValidationSkipped(check string)
+}
+
+// ExecutionNodeHealthMetrics tracks the health of the execution nodes an access node forwards
+// requests to, as observed by the access node.
+type ExecutionNodeHealthMetrics interface {
+	// ExecutionNodeRequest tracks the latency and outcome of a request to the execution node.
+	ExecutionNodeRequest(nodeID flow.Identifier, latency time.Duration, failed bool)
+
+	// ExecutionNodeScore reports the current latency estimate and error rate of the execution node,
+	// and whether requests to it are currently suspended.
+	ExecutionNodeScore(nodeID flow.Identifier, latency time.Duration, errorRate float64, suspended bool)
+
+	// ExecutionNodeExecutedHeight reports the highest block height the execution node sent a
+	// receipt for.
+	ExecutionNodeExecutedHeight(nodeID flow.Identifier, height uint64)
+
+	// HedgedRequestSent tracks requests sent to an additional execution node, because the
+	// previous one did not respond in time.
+	HedgedRequestSent()
 }
 
 type PingMetrics interface {
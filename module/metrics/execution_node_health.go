@@ -0,0 +1,90 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+)
+
+type ExecutionNodeHealthCollector struct {
+	requestDuration *prometheus.HistogramVec
+	latency         *prometheus.GaugeVec
+	errorRate       *prometheus.GaugeVec
+	suspended       *prometheus.GaugeVec
+	executedHeight  *prometheus.GaugeVec
+	hedgedRequests  prometheus.Counter
+}
+
+var _ module.ExecutionNodeHealthMetrics = (*ExecutionNodeHealthCollector)(nil)
+
+func NewExecutionNodeHealthCollector() *ExecutionNodeHealthCollector {
+	return &ExecutionNodeHealthCollector{
+		requestDuration: promauto.NewHistogramVec(prometheus.HistogramOpts{
+			Name:      "request_duration_seconds",
+			Namespace: namespaceAccess,
+			Subsystem: subsystemExecutionNodeHealth,
+			Help:      "histogram for the duration of requests to execution nodes",
+			Buckets:   []float64{.01, .05, .1, .25, .5, 1, 2.5, 5, 10},
+		}, []string{LabelNodeID, "result"}),
+		latency: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Name:      "latency_seconds",
+			Namespace: namespaceAccess,
+			Subsystem: subsystemExecutionNodeHealth,
+			Help:      "the moving average of the latency of requests to execution nodes",
+		}, []string{LabelNodeID}),
+		errorRate: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Name:      "error_rate",
+			Namespace: namespaceAccess,
+			Subsystem: subsystemExecutionNodeHealth,
+			Help:      "the moving average of the fraction of failed requests to execution nodes",
+		}, []string{LabelNodeID}),
+		suspended: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Name:      "suspended",
+			Namespace: namespaceAccess,
+			Subsystem: subsystemExecutionNodeHealth,
+			Help:      "whether requests to execution nodes are suspended because of repeated failures (1) or not (0)",
+		}, []string{LabelNodeID}),
+		executedHeight: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Name:      "executed_height",
+			Namespace: namespaceAccess,
+			Subsystem: subsystemExecutionNodeHealth,
+			Help:      "the highest block height execution nodes sent a receipt for",
+		}, []string{LabelNodeID}),
+		hedgedRequests: promauto.NewCounter(prometheus.CounterOpts{
+			Name:      "hedged_requests_total",
+			Namespace: namespaceAccess,
+			Subsystem: subsystemExecutionNodeHealth,
+			Help:      "counter for the number of requests sent to an additional execution node because the previous one did not respond in time",
+		}),
+	}
+}
+
+func (ec *ExecutionNodeHealthCollector) ExecutionNodeRequest(nodeID flow.Identifier, latency time.Duration, failed bool) {
+	result := "success"
+	if failed {
+		result = "failure"
+	}
+	ec.requestDuration.WithLabelValues(nodeID.String(), result).Observe(latency.Seconds())
+}
+
+func (ec *ExecutionNodeHealthCollector) ExecutionNodeScore(nodeID flow.Identifier, latency time.Duration, errorRate float64, suspended bool) {
+	ec.latency.WithLabelValues(nodeID.String()).Set(latency.Seconds())
+	ec.errorRate.WithLabelValues(nodeID.String()).Set(errorRate)
+	var suspendedValue float64
+	if suspended {
+		suspendedValue = 1
+	}
+	ec.suspended.WithLabelValues(nodeID.String()).Set(suspendedValue)
+}
+
+func (ec *ExecutionNodeHealthCollector) ExecutionNodeExecutedHeight(nodeID flow.Identifier, height uint64) {
+	ec.executedHeight.WithLabelValues(nodeID.String()).Set(float64(height))
+}
+
+func (ec *ExecutionNodeHealthCollector) HedgedRequestSent() {
+	ec.hedgedRequests.Inc()
+}
@@ -94,6 +94,10 @@ func AccessNodeExecutionDataCacheMetrics(registrar prometheus.Registerer) *HeroC
 	return NewHeroCacheCollector(namespaceAccess, ResourceExecutionDataCache, registrar)
 }
 
+func AccessNodeScriptResultCacheMetrics(registrar prometheus.Registerer) *HeroCacheCollector {
+	return NewHeroCacheCollector(namespaceAccess, ResourceScriptResultCache, registrar)
+}
+
 func NewHeroCacheCollector(nameSpace string, cacheName string, registrar prometheus.Registerer) *HeroCacheCollector {
 
 	histogramNormalizedBucketSlotAvailable := prometheus.NewHistogram(prometheus.HistogramOpts{
@@ -36,6 +36,11 @@ type HotstuffCollector struct {
 	skips                         prometheus.Counter
 	timeouts                      prometheus.Counter
 	timeoutDuration               prometheus.Gauge
+	minReplicaTimeout             prometheus.Gauge
+	maxReplicaTimeout             prometheus.Gauge
+	timeoutAdjustmentFactor       prometheus.Gauge
+	happyPathMaxRoundFailures     prometheus.Gauge
+	blockRateDelay                prometheus.Gauge
 	voteProcessingDuration        prometheus.Histogram
 	timeoutProcessingDuration     prometheus.Histogram
 	blockProcessingDuration       prometheus.Histogram
@@ -126,6 +131,46 @@ func NewHotstuffCollector(chain flow.ChainID) *HotstuffCollector {
 			ConstLabels: prometheus.Labels{LabelChain: chain.String()},
 		}),
 
+		minReplicaTimeout: promauto.NewGauge(prometheus.GaugeOpts{
+			Name:        "timeout_config_min_seconds",
+			Namespace:   namespaceConsensus,
+			Subsystem:   subsystemHotstuff,
+			Help:        "The configured minimum length of the timeout",
+			ConstLabels: prometheus.Labels{LabelChain: chain.String()},
+		}),
+
+		maxReplicaTimeout: promauto.NewGauge(prometheus.GaugeOpts{
+			Name:        "timeout_config_max_seconds",
+			Namespace:   namespaceConsensus,
+			Subsystem:   subsystemHotstuff,
+			Help:        "The configured maximum length of the timeout",
+			ConstLabels: prometheus.Labels{LabelChain: chain.String()},
+		}),
+
+		timeoutAdjustmentFactor: promauto.NewGauge(prometheus.GaugeOpts{
+			Name:        "timeout_config_adjustment_factor",
+			Namespace:   namespaceConsensus,
+			Subsystem:   subsystemHotstuff,
+			Help:        "The configured factor by which the timeout is adjusted",
+			ConstLabels: prometheus.Labels{LabelChain: chain.String()},
+		}),
+
+		happyPathMaxRoundFailures: promauto.NewGauge(prometheus.GaugeOpts{
+			Name:        "timeout_config_happy_path_max_round_failures",
+			Namespace:   namespaceConsensus,
+			Subsystem:   subsystemHotstuff,
+			Help:        "The configured number of failed rounds before the timeout is increased",
+			ConstLabels: prometheus.Labels{LabelChain: chain.String()},
+		}),
+
+		blockRateDelay: promauto.NewGauge(prometheus.GaugeOpts{
+			Name:        "timeout_config_block_rate_delay_seconds",
+			Namespace:   namespaceConsensus,
+			Subsystem:   subsystemHotstuff,
+			Help:        "The configured delay of broadcasting block proposals",
+			ConstLabels: prometheus.Labels{LabelChain: chain.String()},
+		}),
+
 		committeeComputationsDuration: promauto.NewHistogram(prometheus.HistogramOpts{
 			Name:        "committee_computations_seconds",
 			Namespace:   namespaceConsensus,
@@ -252,6 +297,15 @@ func (hc *HotstuffCollector) SetTimeout(duration time.Duration) {
 	hc.timeoutDuration.Set(duration.Seconds()) // unit: seconds; with float64 precision
 }
 
+// SetTimeoutConfig reports the current parameters of the pacemaker's timeout.
+func (hc *HotstuffCollector) SetTimeoutConfig(minReplicaTimeout time.Duration, maxReplicaTimeout time.Duration, timeoutAdjustmentFactor float64, happyPathMaxRoundFailures uint64, blockRateDelay time.Duration) {
+	hc.minReplicaTimeout.Set(minReplicaTimeout.Seconds()) // unit: seconds; with float64 precision
+	hc.maxReplicaTimeout.Set(maxReplicaTimeout.Seconds())
+	hc.timeoutAdjustmentFactor.Set(timeoutAdjustmentFactor)
+	hc.happyPathMaxRoundFailures.Set(float64(happyPathMaxRoundFailures))
+	hc.blockRateDelay.Set(blockRateDelay.Seconds())
+}
+
 // CommitteeProcessingDuration measures the time which the HotStuff's core logic
 // spends in the hotstuff.Committee component, i.e. the time determining consensus
 // committee relations.
@@ -110,6 +110,7 @@ const (
 	ResourceTransactionResultIndices   = "transaction_result_indices"        // execution node
 	ResourceTransactionResultByBlock   = "transaction_result_by_block"       // execution node
 	ResourceExecutionDataCache         = "execution_data_cache"              // access node
+	ResourceScriptResultCache          = "script_result_cache"               // access node
 )
 
 const (
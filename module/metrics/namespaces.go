@@ -40,6 +40,8 @@ const (
 const (
 	subsystemTransactionTiming     = "transaction_timing"
 	subsystemTransactionSubmission = "transaction_submission"
+	subsystemTransactionValidation = "transaction_validation"
+	subsystemExecutionNodeHealth   = "execution_node_health"
 	subsystemConnectionPool        = "connection_pool"
 )
 
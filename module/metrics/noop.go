@@ -112,24 +112,26 @@ func (nc *NoopCollector) BlockProcessingDuration(time.Duration)
 func (nc *NoopCollector) VoteProcessingDuration(time.Duration)                                   {}
 func (nc *NoopCollector) TimeoutObjectProcessingDuration(time.Duration)                          {}
 func (nc *NoopCollector) SetTimeout(duration time.Duration)                                      {}
-func (nc *NoopCollector) CommitteeProcessingDuration(duration time.Duration)                     {}
-func (nc *NoopCollector) SignerProcessingDuration(duration time.Duration)                        {}
-func (nc *NoopCollector) ValidatorProcessingDuration(duration time.Duration)                     {}
-func (nc *NoopCollector) PayloadProductionDuration(duration time.Duration)                       {}
-func (nc *NoopCollector) TransactionIngested(txID flow.Identifier)                               {}
-func (nc *NoopCollector) ClusterBlockProposed(*cluster.Block)                                    {}
-func (nc *NoopCollector) ClusterBlockFinalized(*cluster.Block)                                   {}
-func (nc *NoopCollector) StartCollectionToFinalized(collectionID flow.Identifier)                {}
-func (nc *NoopCollector) FinishCollectionToFinalized(collectionID flow.Identifier)               {}
-func (nc *NoopCollector) StartBlockToSeal(blockID flow.Identifier)                               {}
-func (nc *NoopCollector) FinishBlockToSeal(blockID flow.Identifier)                              {}
-func (nc *NoopCollector) EmergencySeal()                                                         {}
-func (nc *NoopCollector) OnReceiptProcessingDuration(duration time.Duration)                     {}
-func (nc *NoopCollector) OnApprovalProcessingDuration(duration time.Duration)                    {}
-func (nc *NoopCollector) CheckSealingDuration(duration time.Duration)                            {}
-func (nc *NoopCollector) OnExecutionResultReceivedAtAssignerEngine()                             {}
-func (nc *NoopCollector) OnVerifiableChunkReceivedAtVerifierEngine()                             {}
-func (nc *NoopCollector) OnResultApprovalDispatchedInNetworkByVerifier()                         {}
+func (nc *NoopCollector) SetTimeoutConfig(time.Duration, time.Duration, float64, uint64, time.Duration) {
+}
+func (nc *NoopCollector) CommitteeProcessingDuration(duration time.Duration)       {}
+func (nc *NoopCollector) SignerProcessingDuration(duration time.Duration)          {}
+func (nc *NoopCollector) ValidatorProcessingDuration(duration time.Duration)       {}
+func (nc *NoopCollector) PayloadProductionDuration(duration time.Duration)         {}
+func (nc *NoopCollector) TransactionIngested(txID flow.Identifier)                 {}
+func (nc *NoopCollector) ClusterBlockProposed(*cluster.Block)                      {}
+func (nc *NoopCollector) ClusterBlockFinalized(*cluster.Block)                     {}
+func (nc *NoopCollector) StartCollectionToFinalized(collectionID flow.Identifier)  {}
+func (nc *NoopCollector) FinishCollectionToFinalized(collectionID flow.Identifier) {}
+func (nc *NoopCollector) StartBlockToSeal(blockID flow.Identifier)                 {}
+func (nc *NoopCollector) FinishBlockToSeal(blockID flow.Identifier)                {}
+func (nc *NoopCollector) EmergencySeal()                                           {}
+func (nc *NoopCollector) OnReceiptProcessingDuration(duration time.Duration)       {}
+func (nc *NoopCollector) OnApprovalProcessingDuration(duration time.Duration)      {}
+func (nc *NoopCollector) CheckSealingDuration(duration time.Duration)              {}
+func (nc *NoopCollector) OnExecutionResultReceivedAtAssignerEngine()               {}
+func (nc *NoopCollector) OnVerifiableChunkReceivedAtVerifierEngine()               {}
+func (nc *NoopCollector) OnResultApprovalDispatchedInNetworkByVerifier()           {}
 func (nc *NoopCollector) SetMaxChunkDataPackAttemptsForNextUnsealedHeightAtRequester(attempts uint64) {
 }
 func (nc *NoopCollector) OnFinalizedBlockArrivedAtAssigner(height uint64)                       {}
@@ -198,6 +200,16 @@ func (nc *NoopCollector) TransactionFinalized(txID flow.Identifier, when time.Ti
 func (nc *NoopCollector) TransactionExecuted(txID flow.Identifier, when time.Time)         {}
 func (nc *NoopCollector) TransactionExpired(txID flow.Identifier)                          {}
 func (nc *NoopCollector) TransactionSubmissionFailed()                                     {}
+func (nc *NoopCollector) TransactionRetryQueued()                                          {}
+func (nc *NoopCollector) TransactionRetried()                                              {}
+func (nc *NoopCollector) TransactionRetryExpired(count int)                                {}
+func (nc *NoopCollector) TransactionRetryQueueSize(size int)                               {}
+func (nc *NoopCollector) TransactionValidationFailed(check string, dryRun bool)            {}
+func (nc *NoopCollector) TransactionValidationSkipped(check string)                        {}
+func (nc *NoopCollector) ExecutionNodeRequest(flow.Identifier, time.Duration, bool)        {}
+func (nc *NoopCollector) ExecutionNodeScore(flow.Identifier, time.Duration, float64, bool) {}
+func (nc *NoopCollector) ExecutionNodeExecutedHeight(flow.Identifier, uint64)              {}
+func (nc *NoopCollector) HedgedRequestSent()                                               {}
 func (nc *NoopCollector) UpdateExecutionReceiptMaxHeight(height uint64)                    {}
 func (nc *NoopCollector) ChunkDataPackRequestProcessed()                                   {}
 func (nc *NoopCollector) ExecutionSync(syncing bool)                                       {}
@@ -27,6 +27,8 @@ type TransactionCollector struct {
 	scriptSize                 prometheus.Histogram
 	transactionSize            prometheus.Histogram
 	maxReceiptHeight           prometheus.Gauge
+	transactionRetry           *prometheus.CounterVec
+	transactionRetryQueueSize  prometheus.Gauge
 
 	// used to skip heights that are lower than the current max height
 	maxReceiptHeightValue counters.StrictMonotonousCounter
@@ -122,6 +124,18 @@ func NewTransactionCollector(transactionTimings mempool.TransactionTimings, log
 			Subsystem: subsystemIngestion,
 			Help:      "gauge to track the maximum block height of execution receipts received",
 		}),
+		transactionRetry: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name:      "transaction_retry",
+			Namespace: namespaceAccess,
+			Subsystem: subsystemTransactionSubmission,
+			Help:      "counter for the transactions queued/retried/expired by the transaction resubmission mechanism",
+		}, []string{"event"}),
+		transactionRetryQueueSize: promauto.NewGauge(prometheus.GaugeOpts{
+			Name:      "transaction_retry_queue_size",
+			Namespace: namespaceAccess,
+			Subsystem: subsystemTransactionSubmission,
+			Help:      "gauge to track the number of transactions queued for resubmission",
+		}),
 		maxReceiptHeightValue: counters.NewMonotonousCounter(0),
 	}
 
@@ -286,3 +300,19 @@ func (tc *TransactionCollector) UpdateExecutionReceiptMaxHeight(height uint64) {
 		tc.maxReceiptHeight.Set(float64(height))
 	}
 }
+
+func (tc *TransactionCollector) TransactionRetryQueued() {
+	tc.transactionRetry.WithLabelValues("queued").Inc()
+}
+
+func (tc *TransactionCollector) TransactionRetried() {
+	tc.transactionRetry.WithLabelValues("retried").Inc()
+}
+
+func (tc *TransactionCollector) TransactionRetryExpired(count int) {
+	tc.transactionRetry.WithLabelValues("expired").Add(float64(count))
+}
+
+func (tc *TransactionCollector) TransactionRetryQueueSize(size int) {
+	tc.transactionRetryQueueSize.Set(float64(size))
+}
@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/onflow/flow-go/module"
+)
+
+type TransactionValidationCollector struct {
+	failed  *prometheus.CounterVec
+	skipped *prometheus.CounterVec
+}
+
+var _ module.TransactionValidationMetrics = (*TransactionValidationCollector)(nil)
+
+func NewTransactionValidationCollector() *TransactionValidationCollector {
+	return &TransactionValidationCollector{
+		failed: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name:      "failed_total",
+			Namespace: namespaceAccess,
+			Subsystem: subsystemTransactionValidation,
+			Help:      "counter for the number of transactions which failed a validation check",
+		}, []string{"check", "dry_run"}),
+		skipped: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name:      "skipped_total",
+			Namespace: namespaceAccess,
+			Subsystem: subsystemTransactionValidation,
+			Help:      "counter for the number of validation checks skipped because the state could not be read",
+		}, []string{"check"}),
+	}
+}
+
+func (tc *TransactionValidationCollector) TransactionValidationFailed(check string, dryRun bool) {
+	tc.failed.WithLabelValues(check, strconv.FormatBool(dryRun)).Inc()
+}
+
+func (tc *TransactionValidationCollector) TransactionValidationSkipped(check string) {
+	tc.skipped.WithLabelValues(check).Inc()
+}
@@ -73,6 +73,11 @@ func (_m *HotstuffMetrics) SetTimeout(duration time.Duration) {
 	_m.Called(duration)
 }
 
+// SetTimeoutConfig provides a mock function with given fields: minReplicaTimeout, maxReplicaTimeout, timeoutAdjustmentFactor, happyPathMaxRoundFailures, blockRateDelay
+func (_m *HotstuffMetrics) SetTimeoutConfig(minReplicaTimeout time.Duration, maxReplicaTimeout time.Duration, timeoutAdjustmentFactor float64, happyPathMaxRoundFailures uint64, blockRateDelay time.Duration) {
+	_m.Called(minReplicaTimeout, maxReplicaTimeout, timeoutAdjustmentFactor, happyPathMaxRoundFailures, blockRateDelay)
+}
+
 // SignerProcessingDuration provides a mock function with given fields: duration
 func (_m *HotstuffMetrics) SignerProcessingDuration(duration time.Duration) {
 	_m.Called(duration)
@@ -44,6 +44,26 @@ func (_m *TransactionMetrics) TransactionResultFetched(dur time.Duration, size i
 	_m.Called(dur, size)
 }
 
+// TransactionRetried provides a mock function with given fields:
+func (_m *TransactionMetrics) TransactionRetried() {
+	_m.Called()
+}
+
+// TransactionRetryExpired provides a mock function with given fields: count
+func (_m *TransactionMetrics) TransactionRetryExpired(count int) {
+	_m.Called(count)
+}
+
+// TransactionRetryQueueSize provides a mock function with given fields: size
+func (_m *TransactionMetrics) TransactionRetryQueueSize(size int) {
+	_m.Called(size)
+}
+
+// TransactionRetryQueued provides a mock function with given fields:
+func (_m *TransactionMetrics) TransactionRetryQueued() {
+	_m.Called()
+}
+
 // TransactionSubmissionFailed provides a mock function with given fields:
 func (_m *TransactionMetrics) TransactionSubmissionFailed() {
 	_m.Called()
@@ -0,0 +1,290 @@
+package slashing
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/verification"
+	"github.com/onflow/flow-go/crypto"
+	"github.com/onflow/flow-go/crypto/hash"
+	"github.com/onflow/flow-go/model/encoding"
+	"github.com/onflow/flow-go/model/flow"
+	msig "github.com/onflow/flow-go/module/signature"
+)
+
+// EvidenceBundleVersion is the version of the evidence bundle format.
+const EvidenceBundleVersion = 1
+
+// clusterChainIDPrefix is the prefix of the chain IDs of collection clusters, see
+// cluster.CanonicalClusterID.
+const clusterChainIDPrefix = "cluster-"
+
+// ErrUnverifiableEvidence is returned when verifying evidence which does not contain
+// signed messages attributable to the offender, e.g. evidence of network violations.
+var ErrUnverifiableEvidence = errors.New("evidence is not independently verifiable")
+
+// EvidenceBundle is a self-contained export of slashing evidence. All data required to verify
+// the evidence is included, so it can be verified without access to the protocol state.
+type EvidenceBundle struct {
+	Version uint
+	// RecorderID is the ID of the node which recorded the evidence.
+	RecorderID flow.Identifier
+	CreatedAt  time.Time
+	Evidence   []*flow.SlashingEvidence
+}
+
+// NewEvidenceBundle returns a bundle of the given evidence, recorded by the given node.
+func NewEvidenceBundle(recorderID flow.Identifier, evidence []*flow.SlashingEvidence) *EvidenceBundle {
+	return &EvidenceBundle{
+		Version:    EvidenceBundleVersion,
+		RecorderID: recorderID,
+		CreatedAt:  time.Now().UTC(),
+		Evidence:   evidence,
+	}
+}
+
+// VerifyEvidence verifies that the evidence proves the offense was committed by the offender:
+//   - all signed messages are validly signed by the offender, using the keys included in the
+//     evidence, and
+//   - for double votes, timeouts and proposals, the messages conflict, i.e. the offender signed
+//     two different messages for the same view.
+//
+// For votes for invalid blocks, only the vote is verified. Whether the proposed block is invalid
+// has to be checked against the full block.
+//
+// Expected errors during normal operations:
+//   - ErrUnverifiableEvidence if the evidence contains no messages attributable to the offender,
+//     e.g. for network violations, or invalid messages with invalid signatures, which anyone could
+//     have created.
+//   - generic error if the evidence is invalid
+func VerifyEvidence(evidence *flow.SlashingEvidence) error {
+	if evidence.Offense == flow.OffenseNetworkViolation {
+		return fmt.Errorf("network violations contain no signed messages: %w", ErrUnverifiableEvidence)
+	}
+	if evidence.Offender == nil {
+		return fmt.Errorf("identity of offender is unknown: %w", ErrUnverifiableEvidence)
+	}
+	if evidence.Offender.NodeID != evidence.OffenderID {
+		return fmt.Errorf("offender identity %v does not match offender %v", evidence.Offender.NodeID, evidence.OffenderID)
+	}
+
+	verifier, err := newMessageVerifier(evidence)
+	if err != nil {
+		return err
+	}
+
+	switch evidence.Offense {
+	case flow.OffenseDoubleVote:
+		return verifier.verifyEquivocation(flow.SignedMessageVote)
+	case flow.OffenseDoubleTimeout:
+		return verifier.verifyEquivocation(flow.SignedMessageTimeout)
+	case flow.OffenseDoubleProposal:
+		return verifier.verifyEquivocation(flow.SignedMessageProposal)
+	case flow.OffenseInvalidVote:
+		return verifier.verifyInvalidMessage(flow.SignedMessageVote)
+	case flow.OffenseInvalidTimeout:
+		return verifier.verifyInvalidMessage(flow.SignedMessageTimeout)
+	case flow.OffenseVoteForInvalidBlock:
+		return verifier.verifyVoteForInvalidBlock()
+	default:
+		return fmt.Errorf("unknown offense %s", evidence.Offense)
+	}
+}
+
+// messageVerifier verifies the signed messages of consensus evidence.
+type messageVerifier struct {
+	evidence *flow.SlashingEvidence
+
+	// cluster is true for evidence of collection cluster consensus, which only uses staking
+	// signatures, with different domain separation tags than the main consensus.
+	cluster       bool
+	voteHasher    hash.Hasher
+	timeoutHasher hash.Hasher
+	beaconHasher  hash.Hasher
+	beaconPubKey  crypto.PublicKey
+}
+
+func newMessageVerifier(evidence *flow.SlashingEvidence) (*messageVerifier, error) {
+	if strings.HasPrefix(evidence.ChainID.String(), clusterChainIDPrefix) {
+		return &messageVerifier{
+			evidence:      evidence,
+			cluster:       true,
+			voteHasher:    msig.NewBLSHasher(msig.CollectorVoteTag),
+			timeoutHasher: msig.NewBLSHasher(msig.CollectorTimeoutTag),
+		}, nil
+	}
+
+	v := &messageVerifier{
+		evidence:      evidence,
+		voteHasher:    msig.NewBLSHasher(msig.ConsensusVoteTag),
+		timeoutHasher: msig.NewBLSHasher(msig.ConsensusTimeoutTag),
+		beaconHasher:  msig.NewBLSHasher(msig.RandomBeaconTag),
+	}
+	if len(evidence.RandomBeaconPubKey) > 0 {
+		beaconPubKey, err := crypto.DecodePublicKey(crypto.BLSBLS12381, evidence.RandomBeaconPubKey)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode random beacon key: %w", err)
+		}
+		v.beaconPubKey = beaconPubKey
+	}
+	return v, nil
+}
+
+// verifyEquivocation verifies that the evidence contains two validly signed messages of the
+// given type, for the same view, with different signed content.
+func (v *messageVerifier) verifyEquivocation(messageType flow.SignedMessageType) error {
+	err := v.verifyMessages(2, messageType)
+	if err != nil {
+		return err
+	}
+
+	m1, m2 := v.evidence.Messages[0], v.evidence.Messages[1]
+	switch messageType {
+	case flow.SignedMessageTimeout:
+		// timeouts are signed over the view and the view of the newest QC, other fields of
+		// the timeout are not signed
+		if m1.NewestQCView == m2.NewestQCView {
+			return fmt.Errorf("timeouts for view %d sign the same newest QC view %d", m1.View, m1.NewestQCView)
+		}
+	default:
+		if m1.BlockID == m2.BlockID {
+			return fmt.Errorf("messages for view %d are for the same block %v", m1.View, m1.BlockID)
+		}
+	}
+
+	return nil
+}
+
+// verifyInvalidMessage verifies that the invalid message is attributable to the offender. As the
+// message is invalid, its signature might be invalid too, in which case the message could have
+// been created by anyone, and ErrUnverifiableEvidence is returned.
+func (v *messageVerifier) verifyInvalidMessage(messageType flow.SignedMessageType) error {
+	err := v.verifyMessages(1, messageType)
+	if err != nil {
+		return fmt.Errorf("%v: %w", err, ErrUnverifiableEvidence)
+	}
+	return nil
+}
+
+// verifyVoteForInvalidBlock verifies that the offender validly signed a vote for the proposal
+// included in the evidence.
+func (v *messageVerifier) verifyVoteForInvalidBlock() error {
+	messages := v.evidence.Messages
+	if len(messages) != 2 {
+		return fmt.Errorf("expected a vote and a proposal, got %d messages", len(messages))
+	}
+
+	vote, proposal := messages[0], messages[1]
+	if vote.Type != flow.SignedMessageVote || proposal.Type != flow.SignedMessageProposal {
+		return fmt.Errorf("expected a vote and a proposal, got %s and %s", vote.Type, proposal.Type)
+	}
+	if vote.BlockID != proposal.BlockID || vote.View != proposal.View {
+		return fmt.Errorf("vote for block %v (view %d) is not for proposed block %v (view %d)",
+			vote.BlockID, vote.View, proposal.BlockID, proposal.View)
+	}
+	if proposal.Header != nil && proposal.Header.ID() != proposal.BlockID {
+		return fmt.Errorf("proposal header does not match proposed block %v", proposal.BlockID)
+	}
+
+	return v.verifyMessage(&vote)
+}
+
+// verifyMessages verifies that the evidence contains the given number of messages of the given
+// type, all for the view of the evidence and validly signed by the offender.
+func (v *messageVerifier) verifyMessages(count int, messageType flow.SignedMessageType) error {
+	messages := v.evidence.Messages
+	if len(messages) != count {
+		return fmt.Errorf("expected %d messages, got %d", count, len(messages))
+	}
+
+	for i := range messages {
+		if messages[i].Type != messageType {
+			return fmt.Errorf("expected %s message, got %s", messageType, messages[i].Type)
+		}
+		if messages[i].View != v.evidence.View {
+			return fmt.Errorf("message for view %d in evidence for view %d", messages[i].View, v.evidence.View)
+		}
+
+		err := v.verifyMessage(&messages[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// verifyMessage verifies that the message is validly signed by the offender.
+func (v *messageVerifier) verifyMessage(message *flow.SignedConsensusMessage) error {
+	if message.SignerID != v.evidence.OffenderID {
+		return fmt.Errorf("message signed by %v, not by offender %v", message.SignerID, v.evidence.OffenderID)
+	}
+
+	switch message.Type {
+	case flow.SignedMessageVote:
+		return v.verifyVoteSignature(message.SigData, message.View, message.BlockID)
+
+	case flow.SignedMessageProposal:
+		// proposals are signed with the proposer's vote for the proposed block
+		if message.Header == nil {
+			return fmt.Errorf("header of proposed block %v is unknown", message.BlockID)
+		}
+		if message.Header.ID() != message.BlockID ||
+			message.Header.View != message.View ||
+			message.Header.ProposerID != message.SignerID {
+			return fmt.Errorf("header does not match proposal of block %v", message.BlockID)
+		}
+		return v.verifyVoteSignature(message.SigData, message.View, message.BlockID)
+
+	case flow.SignedMessageTimeout:
+		msg := verification.MakeTimeoutMessage(message.View, message.NewestQCView)
+		return v.verifySignature(v.evidence.Offender.StakingPubKey, message.SigData, msg, v.timeoutHasher)
+
+	default:
+		return fmt.Errorf("unknown message type %s", message.Type)
+	}
+}
+
+// verifyVoteSignature verifies a vote signature. In the main consensus, votes are signed either
+// with the staking key, or with the random beacon key, see verification.CombinedVerifierV3.
+func (v *messageVerifier) verifyVoteSignature(sigData []byte, view uint64, blockID flow.Identifier) error {
+	msg := verification.MakeVoteMessage(view, blockID)
+
+	if v.cluster {
+		return v.verifySignature(v.evidence.Offender.StakingPubKey, sigData, msg, v.voteHasher)
+	}
+
+	sigType, sig, err := msig.DecodeSingleSig(sigData)
+	if err != nil {
+		return fmt.Errorf("could not decode vote signature: %w", err)
+	}
+
+	switch sigType {
+	case encoding.SigTypeStaking:
+		return v.verifySignature(v.evidence.Offender.StakingPubKey, sig, msg, v.voteHasher)
+	case encoding.SigTypeRandomBeacon:
+		if v.beaconPubKey == nil {
+			return fmt.Errorf("vote is signed with the random beacon key, which is unknown")
+		}
+		return v.verifySignature(v.beaconPubKey, sig, msg, v.beaconHasher)
+	default:
+		return fmt.Errorf("invalid signature type %d", sigType)
+	}
+}
+
+func (v *messageVerifier) verifySignature(key crypto.PublicKey, sig crypto.Signature, msg []byte, hasher hash.Hasher) error {
+	if key == nil {
+		return fmt.Errorf("key of offender is unknown")
+	}
+
+	valid, err := key.Verify(sig, msg, hasher)
+	if err != nil {
+		return fmt.Errorf("could not verify signature: %w", err)
+	}
+	if !valid {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
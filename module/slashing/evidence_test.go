@@ -0,0 +1,119 @@
+package slashing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/verification"
+	"github.com/onflow/flow-go/model/encoding"
+	"github.com/onflow/flow-go/model/flow"
+	msig "github.com/onflow/flow-go/module/signature"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestVerifyEvidence(t *testing.T) {
+	stakingKey := unittest.StakingPrivKeyFixture()
+	offender := unittest.IdentityFixture(func(identity *flow.Identity) {
+		identity.StakingPubKey = stakingKey.PublicKey()
+	})
+	view := uint64(42)
+
+	vote := func(t *testing.T, blockID flow.Identifier) flow.SignedConsensusMessage {
+		sig, err := stakingKey.Sign(verification.MakeVoteMessage(view, blockID), msig.NewBLSHasher(msig.ConsensusVoteTag))
+		require.NoError(t, err)
+		return flow.SignedConsensusMessage{
+			Type:     flow.SignedMessageVote,
+			SignerID: offender.NodeID,
+			View:     view,
+			BlockID:  blockID,
+			SigData:  msig.EncodeSingleSig(encoding.SigTypeStaking, sig),
+		}
+	}
+
+	timeout := func(t *testing.T, newestQCView uint64) flow.SignedConsensusMessage {
+		sig, err := stakingKey.Sign(verification.MakeTimeoutMessage(view, newestQCView), msig.NewBLSHasher(msig.ConsensusTimeoutTag))
+		require.NoError(t, err)
+		return flow.SignedConsensusMessage{
+			Type:         flow.SignedMessageTimeout,
+			SignerID:     offender.NodeID,
+			View:         view,
+			NewestQCView: newestQCView,
+			SigData:      sig,
+		}
+	}
+
+	evidenceFixture := func(offense flow.SlashableOffense, messages ...flow.SignedConsensusMessage) *flow.SlashingEvidence {
+		return unittest.SlashingEvidenceFixture(func(evidence *flow.SlashingEvidence) {
+			evidence.Offense = offense
+			evidence.OffenderID = offender.NodeID
+			evidence.Offender = offender
+			evidence.View = view
+			evidence.Messages = messages
+		})
+	}
+
+	t.Run("double vote", func(t *testing.T) {
+		evidence := evidenceFixture(flow.OffenseDoubleVote,
+			vote(t, unittest.IdentifierFixture()), vote(t, unittest.IdentifierFixture()))
+		require.NoError(t, VerifyEvidence(evidence))
+	})
+
+	t.Run("double vote for the same block", func(t *testing.T) {
+		blockID := unittest.IdentifierFixture()
+		evidence := evidenceFixture(flow.OffenseDoubleVote, vote(t, blockID), vote(t, blockID))
+		err := VerifyEvidence(evidence)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrUnverifiableEvidence)
+	})
+
+	t.Run("double vote with invalid signature", func(t *testing.T) {
+		vote2 := vote(t, unittest.IdentifierFixture())
+		vote2.BlockID = unittest.IdentifierFixture()
+		evidence := evidenceFixture(flow.OffenseDoubleVote, vote(t, unittest.IdentifierFixture()), vote2)
+		err := VerifyEvidence(evidence)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrUnverifiableEvidence)
+	})
+
+	t.Run("double vote of another node", func(t *testing.T) {
+		evidence := evidenceFixture(flow.OffenseDoubleVote,
+			vote(t, unittest.IdentifierFixture()), vote(t, unittest.IdentifierFixture()))
+		evidence.Offender = unittest.IdentityFixture(func(identity *flow.Identity) {
+			identity.NodeID = offender.NodeID
+			identity.StakingPubKey = unittest.StakingPrivKeyFixture().PublicKey()
+		})
+		require.Error(t, VerifyEvidence(evidence))
+	})
+
+	t.Run("double timeout", func(t *testing.T) {
+		evidence := evidenceFixture(flow.OffenseDoubleTimeout, timeout(t, view-1), timeout(t, view-2))
+		require.NoError(t, VerifyEvidence(evidence))
+	})
+
+	t.Run("double timeout for the same QC", func(t *testing.T) {
+		evidence := evidenceFixture(flow.OffenseDoubleTimeout, timeout(t, view-1), timeout(t, view-1))
+		require.Error(t, VerifyEvidence(evidence))
+	})
+
+	t.Run("invalid vote with invalid signature", func(t *testing.T) {
+		invalid := vote(t, unittest.IdentifierFixture())
+		invalid.SigData = msig.EncodeSingleSig(encoding.SigTypeStaking, unittest.SignatureFixture())
+		evidence := evidenceFixture(flow.OffenseInvalidVote, invalid)
+		require.ErrorIs(t, VerifyEvidence(evidence), ErrUnverifiableEvidence)
+	})
+
+	t.Run("network violation", func(t *testing.T) {
+		evidence := evidenceFixture(flow.OffenseNetworkViolation)
+		evidence.Network = &flow.NetworkViolation{}
+		require.ErrorIs(t, VerifyEvidence(evidence), ErrUnverifiableEvidence)
+	})
+
+	t.Run("unknown offender", func(t *testing.T) {
+		evidence := evidenceFixture(flow.OffenseDoubleVote,
+			vote(t, unittest.IdentifierFixture()), vote(t, unittest.IdentifierFixture()))
+		evidence.Offender = nil
+		require.ErrorIs(t, VerifyEvidence(evidence), ErrUnverifiableEvidence)
+	})
+}
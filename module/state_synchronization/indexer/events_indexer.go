@@ -0,0 +1,223 @@
+package indexer
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/executiondatasync/execution_data"
+	"github.com/onflow/flow-go/storage"
+	bstorage "github.com/onflow/flow-go/storage/badger"
+)
+
+// EventsIndexer stores the events contained in downloaded execution data into the local events
+// storage, so they can be served without querying execution nodes.
+//
+// The indexer keeps track of the range of consecutive heights for which events were indexed. Only
+// heights within this range are guaranteed to have complete events in storage. The range is
+// persisted, so indexing resumes where it left off after a restart.
+//
+// OnExecutionData must be called with execution data in consecutive height order, which is
+// guaranteed by the ExecutionDataRequester. Execution data for heights which were already indexed
+// may be delivered again, and is re-indexed without changing the indexed range.
+type EventsIndexer struct {
+	log     zerolog.Logger
+	db      *badger.DB
+	headers storage.Headers
+	events  storage.Events
+
+	// lowestHeight and highestHeight persist the bounds of the indexed height range
+	lowestHeight  storage.ConsumerProgress
+	highestHeight storage.ConsumerProgress
+
+	mu      sync.RWMutex
+	indexed bool // true if at least one height was indexed
+	lowest  uint64
+	highest uint64
+}
+
+// NewEventsIndexer returns a new EventsIndexer, restoring the indexed height range from the
+// provided consumer progress entries.
+//
+// No errors are expected during normal operation.
+func NewEventsIndexer(
+	log zerolog.Logger,
+	db *badger.DB,
+	headers storage.Headers,
+	events storage.Events,
+	lowestHeight storage.ConsumerProgress,
+	highestHeight storage.ConsumerProgress,
+) (*EventsIndexer, error) {
+	i := &EventsIndexer{
+		log:           log.With().Str("module", "events_indexer").Logger(),
+		db:            db,
+		headers:       headers,
+		events:        events,
+		lowestHeight:  lowestHeight,
+		highestHeight: highestHeight,
+	}
+
+	lowest, err := lowestHeight.ProcessedIndex()
+	if errors.Is(err, storage.ErrNotFound) {
+		// nothing was indexed yet
+		return i, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not get lowest indexed height: %w", err)
+	}
+
+	highest, err := highestHeight.ProcessedIndex()
+	if errors.Is(err, storage.ErrNotFound) {
+		// the node stopped before the first indexed height was fully persisted
+		return i, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not get highest indexed height: %w", err)
+	}
+
+	// a range with lowest > highest is the result of an interrupted restart of the range, and
+	// does not contain any indexed heights
+	i.indexed = lowest <= highest
+	i.lowest = lowest
+	i.highest = highest
+
+	i.log.Info().
+		Uint64("lowest_height", lowest).
+		Uint64("highest_height", highest).
+		Msg("restored indexed height range")
+
+	return i, nil
+}
+
+// IndexedHeightRange returns the range of consecutive heights for which events are indexed
+// (inclusive on both ends). ok is false if no height was indexed yet.
+func (i *EventsIndexer) IndexedHeightRange() (lowest uint64, highest uint64, ok bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	return i.lowest, i.highest, i.indexed
+}
+
+// ByBlockIDEventType returns the indexed events of the given type for the given block.
+// Callers should check that the block's height is within IndexedHeightRange, since blocks outside
+// of the range may have no or incomplete events in storage.
+//
+// No errors are expected during normal operation.
+func (i *EventsIndexer) ByBlockIDEventType(blockID flow.Identifier, eventType flow.EventType) ([]flow.Event, error) {
+	events, err := i.events.ByBlockIDEventType(blockID, eventType)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, err
+	}
+	// blocks without events are indexed as empty
+	return events, nil
+}
+
+// OnExecutionData indexes the events contained in the given execution data.
+// It is intended to be registered as a consumer of the ExecutionDataRequester.
+func (i *EventsIndexer) OnExecutionData(executionData *execution_data.BlockExecutionDataEntity) {
+	lg := i.log.With().Hex("block_id", executionData.BlockID[:]).Logger()
+
+	err := i.index(executionData)
+	if err != nil {
+		// the indexed range is not advanced, so this height is served by execution nodes. since
+		// the next height creates a gap, the indexed range restarts from there.
+		lg.Error().Err(err).Msg("failed to index events from execution data")
+		return
+	}
+
+	lg.Trace().Msg("indexed events from execution data")
+}
+
+// index stores the events from the execution data and updates the indexed height range.
+// No errors are expected during normal operation.
+func (i *EventsIndexer) index(executionData *execution_data.BlockExecutionDataEntity) error {
+	header, err := i.headers.ByBlockID(executionData.BlockID)
+	if err != nil {
+		return fmt.Errorf("could not get header for block %v: %w", executionData.BlockID, err)
+	}
+
+	blockEvents := make([]flow.EventsList, 0, len(executionData.ChunkExecutionDatas))
+	for _, chunk := range executionData.ChunkExecutionDatas {
+		blockEvents = append(blockEvents, chunk.Events)
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	batch := bstorage.NewBatch(i.db)
+	err = i.events.BatchStore(executionData.BlockID, blockEvents, batch)
+	if err != nil {
+		return fmt.Errorf("could not add events to batch: %w", err)
+	}
+
+	err = batch.Flush()
+	if err != nil {
+		return fmt.Errorf("could not store events: %w", err)
+	}
+
+	height := header.Height
+	switch {
+	case i.indexed && height >= i.lowest && height <= i.highest:
+		// execution data was delivered again, the range is unchanged
+		return nil
+
+	case i.indexed && height == i.highest+1:
+		err = i.highestHeight.SetProcessedIndex(height)
+		if err != nil {
+			return fmt.Errorf("could not update highest indexed height: %w", err)
+		}
+		i.highest = height
+		return nil
+	}
+
+	// either this is the first height indexed, or there is a gap in the indexed heights. in both
+	// cases, the indexed range starts over from this height.
+	err = i.initRange(height)
+	if err != nil {
+		return err
+	}
+
+	if i.indexed {
+		i.log.Warn().
+			Uint64("previous_lowest_height", i.lowest).
+			Uint64("previous_highest_height", i.highest).
+			Uint64("height", height).
+			Msg("non-consecutive execution data height, restarting indexed height range")
+	}
+
+	i.indexed = true
+	i.lowest = height
+	i.highest = height
+
+	return nil
+}
+
+// initRange persists a new indexed height range containing only the given height.
+// The lowest height is updated first, so that a partially persisted range never covers heights
+// which were not indexed.
+// No errors are expected during normal operation.
+func (i *EventsIndexer) initRange(height uint64) error {
+	err := setOrInitProcessedIndex(i.lowestHeight, height)
+	if err != nil {
+		return fmt.Errorf("could not update lowest indexed height: %w", err)
+	}
+	err = setOrInitProcessedIndex(i.highestHeight, height)
+	if err != nil {
+		return fmt.Errorf("could not update highest indexed height: %w", err)
+	}
+	return nil
+}
+
+// setOrInitProcessedIndex sets the processed index, initializing it if it does not exist yet.
+// No errors are expected during normal operation.
+func setOrInitProcessedIndex(progress storage.ConsumerProgress, height uint64) error {
+	err := progress.InitProcessedIndex(height)
+	if errors.Is(err, storage.ErrAlreadyExists) {
+		return progress.SetProcessedIndex(height)
+	}
+	return err
+}
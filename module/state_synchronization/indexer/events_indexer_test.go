@@ -0,0 +1,142 @@
+package indexer_test
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/executiondatasync/execution_data"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/module/state_synchronization/indexer"
+	bstorage "github.com/onflow/flow-go/storage/badger"
+	storagemock "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// blockFixtures returns n blocks with consecutive heights, and a mock headers storage returning them.
+func blockFixtures(t *testing.T, n int) ([]*flow.Header, *storagemock.Headers) {
+	headers := storagemock.NewHeaders(t)
+	blocks := make([]*flow.Header, n)
+	for i := range blocks {
+		header := unittest.BlockHeaderFixture()
+		header.Height = uint64(100 + i)
+		blocks[i] = header
+		headers.On("ByBlockID", header.ID()).Return(header, nil).Maybe()
+	}
+	return blocks, headers
+}
+
+// executionDataFixture returns execution data for the given block with one event per chunk.
+func executionDataFixture(header *flow.Header, chunks int) *execution_data.BlockExecutionDataEntity {
+	chunkDatas := make([]*execution_data.ChunkExecutionData, chunks)
+	for i := range chunkDatas {
+		chunkDatas[i] = &execution_data.ChunkExecutionData{
+			Events: flow.EventsList{
+				unittest.EventFixture(flow.EventAccountCreated, uint32(i), 0, unittest.IdentifierFixture(), 0),
+			},
+		}
+	}
+
+	return execution_data.NewBlockExecutionDataEntity(unittest.IdentifierFixture(), &execution_data.BlockExecutionData{
+		BlockID:             header.ID(),
+		ChunkExecutionDatas: chunkDatas,
+	})
+}
+
+func newIndexer(t *testing.T, db *badger.DB, headers *storagemock.Headers) *indexer.EventsIndexer {
+	eventsIndexer, err := indexer.NewEventsIndexer(
+		zerolog.Nop(),
+		db,
+		headers,
+		bstorage.NewEvents(metrics.NewNoopCollector(), db),
+		bstorage.NewConsumerProgress(db, module.ConsumeProgressEventsIndexerLowestHeight),
+		bstorage.NewConsumerProgress(db, module.ConsumeProgressEventsIndexerHighestHeight),
+	)
+	require.NoError(t, err)
+	return eventsIndexer
+}
+
+// TestEventsIndexer_ConsecutiveHeights tests that events are indexed and the indexed height range
+// advances as consecutive execution data is received.
+func TestEventsIndexer_ConsecutiveHeights(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		blocks, headers := blockFixtures(t, 3)
+		eventsIndexer := newIndexer(t, db, headers)
+
+		_, _, ok := eventsIndexer.IndexedHeightRange()
+		assert.False(t, ok)
+
+		for _, header := range blocks {
+			eventsIndexer.OnExecutionData(executionDataFixture(header, 2))
+		}
+
+		lowest, highest, ok := eventsIndexer.IndexedHeightRange()
+		require.True(t, ok)
+		assert.Equal(t, blocks[0].Height, lowest)
+		assert.Equal(t, blocks[2].Height, highest)
+
+		for _, header := range blocks {
+			events, err := eventsIndexer.ByBlockIDEventType(header.ID(), flow.EventAccountCreated)
+			require.NoError(t, err)
+			assert.Len(t, events, 2)
+
+			events, err = eventsIndexer.ByBlockIDEventType(header.ID(), flow.EventAccountUpdated)
+			require.NoError(t, err)
+			assert.Empty(t, events)
+		}
+
+		// execution data delivered again does not change the range
+		eventsIndexer.OnExecutionData(executionDataFixture(blocks[1], 2))
+
+		lowest, highest, ok = eventsIndexer.IndexedHeightRange()
+		require.True(t, ok)
+		assert.Equal(t, blocks[0].Height, lowest)
+		assert.Equal(t, blocks[2].Height, highest)
+	})
+}
+
+// TestEventsIndexer_Gap tests that the indexed height range restarts when a height is skipped.
+func TestEventsIndexer_Gap(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		blocks, headers := blockFixtures(t, 4)
+		eventsIndexer := newIndexer(t, db, headers)
+
+		eventsIndexer.OnExecutionData(executionDataFixture(blocks[0], 1))
+		eventsIndexer.OnExecutionData(executionDataFixture(blocks[1], 1))
+		eventsIndexer.OnExecutionData(executionDataFixture(blocks[3], 1))
+
+		lowest, highest, ok := eventsIndexer.IndexedHeightRange()
+		require.True(t, ok)
+		assert.Equal(t, blocks[3].Height, lowest)
+		assert.Equal(t, blocks[3].Height, highest)
+	})
+}
+
+// TestEventsIndexer_Restart tests that the indexed height range is restored after a restart.
+func TestEventsIndexer_Restart(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		blocks, headers := blockFixtures(t, 3)
+
+		eventsIndexer := newIndexer(t, db, headers)
+		eventsIndexer.OnExecutionData(executionDataFixture(blocks[0], 1))
+		eventsIndexer.OnExecutionData(executionDataFixture(blocks[1], 1))
+
+		eventsIndexer = newIndexer(t, db, headers)
+		lowest, highest, ok := eventsIndexer.IndexedHeightRange()
+		require.True(t, ok)
+		assert.Equal(t, blocks[0].Height, lowest)
+		assert.Equal(t, blocks[1].Height, highest)
+
+		// indexing continues from the restored range
+		eventsIndexer.OnExecutionData(executionDataFixture(blocks[2], 1))
+		lowest, highest, ok = eventsIndexer.IndexedHeightRange()
+		require.True(t, ok)
+		assert.Equal(t, blocks[0].Height, lowest)
+		assert.Equal(t, blocks[2].Height, highest)
+	})
+}
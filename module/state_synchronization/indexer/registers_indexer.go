@@ -0,0 +1,301 @@
+package indexer
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/executiondatasync/execution_data"
+	"github.com/onflow/flow-go/storage"
+	bstorage "github.com/onflow/flow-go/storage/badger"
+)
+
+// RegistersIndexer maintains an index of register values by height, built from the trie updates
+// contained in downloaded execution data. The index allows reading the execution state at any
+// indexed height, e.g. to execute scripts locally.
+//
+// Since trie updates only contain the registers modified in a block, the index must be
+// bootstrapped with the complete execution state at its first height (see Bootstrap). Afterwards,
+// execution data must be received for every consecutive height. Unlike the EventsIndexer, the
+// indexed range can not restart after a gap, since the registers updated in the missing heights
+// are unknown. In that case, indexing stops and the index must be bootstrapped again.
+type RegistersIndexer struct {
+	log       zerolog.Logger
+	db        *badger.DB
+	headers   storage.Headers
+	registers storage.Registers
+
+	// lowestHeight and highestHeight persist the bounds of the indexed height range
+	lowestHeight  storage.ConsumerProgress
+	highestHeight storage.ConsumerProgress
+
+	mu      sync.RWMutex
+	indexed bool // true if the index was bootstrapped
+	lowest  uint64
+	highest uint64
+}
+
+// NewRegistersIndexer returns a new RegistersIndexer, restoring the indexed height range from the
+// provided consumer progress entries.
+//
+// No errors are expected during normal operation.
+func NewRegistersIndexer(
+	log zerolog.Logger,
+	db *badger.DB,
+	headers storage.Headers,
+	registers storage.Registers,
+	lowestHeight storage.ConsumerProgress,
+	highestHeight storage.ConsumerProgress,
+) (*RegistersIndexer, error) {
+	i := &RegistersIndexer{
+		log:           log.With().Str("module", "registers_indexer").Logger(),
+		db:            db,
+		headers:       headers,
+		registers:     registers,
+		lowestHeight:  lowestHeight,
+		highestHeight: highestHeight,
+	}
+
+	// the highest height is initialized last during bootstrapping, so the index is only
+	// bootstrapped if it exists
+	highest, err := highestHeight.ProcessedIndex()
+	if errors.Is(err, storage.ErrNotFound) {
+		return i, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not get highest indexed height: %w", err)
+	}
+
+	lowest, err := lowestHeight.ProcessedIndex()
+	if err != nil {
+		return nil, fmt.Errorf("could not get lowest indexed height: %w", err)
+	}
+
+	i.indexed = true
+	i.lowest = lowest
+	i.highest = highest
+
+	i.log.Info().
+		Uint64("lowest_height", lowest).
+		Uint64("highest_height", highest).
+		Msg("restored indexed height range")
+
+	return i, nil
+}
+
+// BootstrapBatchSize is the number of registers stored per batch while bootstrapping the index,
+// which bounds the memory used to bootstrap from large execution states
+const BootstrapBatchSize = 100_000
+
+// Bootstrap initializes the index with the complete execution state at the given height, read with
+// readPayloads, which must pass each payload of the state to processPayload and return the first
+// error returned by it. The registers are stored in batches of BootstrapBatchSize, so the execution
+// state is never held in memory. It is a no-op if the index was already bootstrapped.
+//
+// The index is only marked as bootstrapped once all registers are stored. If bootstrapping fails,
+// for example if readPayloads finds the execution state to be invalid, the index can be
+// bootstrapped again, overwriting the registers stored by the failed attempt.
+//
+// No errors are expected during normal operation.
+func (i *RegistersIndexer) Bootstrap(height uint64, readPayloads func(processPayload func(*ledger.Payload) error) error) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.indexed {
+		i.log.Debug().Msg("register index already bootstrapped, skipping")
+		return nil
+	}
+
+	entries := make(flow.RegisterEntries, 0, BootstrapBatchSize)
+	count := 0
+	storeBatch := func() error {
+		batch := bstorage.NewBatch(i.db)
+		err := i.registers.BatchStore(height, entries, batch)
+		if err != nil {
+			return fmt.Errorf("could not add registers to batch: %w", err)
+		}
+
+		err = batch.Flush()
+		if err != nil {
+			return fmt.Errorf("could not store registers: %w", err)
+		}
+
+		count += len(entries)
+		entries = entries[:0]
+		i.log.Debug().Uint64("height", height).Int("registers", count).Msg("stored batch of bootstrapped registers")
+		return nil
+	}
+
+	err := readPayloads(func(payload *ledger.Payload) error {
+		entry, err := payloadToRegisterEntry(payload)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+		if len(entries) < BootstrapBatchSize {
+			return nil
+		}
+		return storeBatch()
+	})
+	if err != nil {
+		return fmt.Errorf("could not read registers to bootstrap the index: %w", err)
+	}
+
+	if len(entries) > 0 {
+		err = storeBatch()
+		if err != nil {
+			return err
+		}
+	}
+
+	err = setOrInitProcessedIndex(i.lowestHeight, height)
+	if err != nil {
+		return fmt.Errorf("could not initialize lowest indexed height: %w", err)
+	}
+
+	err = setOrInitProcessedIndex(i.highestHeight, height)
+	if err != nil {
+		return fmt.Errorf("could not initialize highest indexed height: %w", err)
+	}
+
+	i.indexed = true
+	i.lowest = height
+	i.highest = height
+
+	i.log.Info().
+		Uint64("height", height).
+		Int("registers", count).
+		Msg("bootstrapped register index")
+
+	return nil
+}
+
+// IndexedHeightRange returns the range of consecutive heights for which registers are indexed
+// (inclusive on both ends). ok is false if the index was not bootstrapped yet.
+func (i *RegistersIndexer) IndexedHeightRange() (lowest uint64, highest uint64, ok bool) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	return i.lowest, i.highest, i.indexed
+}
+
+// RegisterValue returns the value of the register at the given height, or an empty value if the
+// register is not set. Callers must check that the height is within IndexedHeightRange, since
+// values outside of the range are incomplete.
+//
+// No errors are expected during normal operation.
+func (i *RegistersIndexer) RegisterValue(id flow.RegisterID, height uint64) (flow.RegisterValue, error) {
+	value, err := i.registers.Get(id, height)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil
+	}
+	return value, err
+}
+
+// OnExecutionData indexes the registers updated in the given execution data.
+// It is intended to be registered as a consumer of the ExecutionDataRequester.
+func (i *RegistersIndexer) OnExecutionData(executionData *execution_data.BlockExecutionDataEntity) {
+	lg := i.log.With().Hex("block_id", executionData.BlockID[:]).Logger()
+
+	err := i.index(executionData)
+	if err != nil {
+		lg.Error().Err(err).Msg("failed to index registers from execution data")
+		return
+	}
+
+	lg.Trace().Msg("indexed registers from execution data")
+}
+
+// index stores the register updates from the execution data and advances the indexed height range.
+// No errors are expected during normal operation.
+func (i *RegistersIndexer) index(executionData *execution_data.BlockExecutionDataEntity) error {
+	header, err := i.headers.ByBlockID(executionData.BlockID)
+	if err != nil {
+		return fmt.Errorf("could not get header for block %v: %w", executionData.BlockID, err)
+	}
+	height := header.Height
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if !i.indexed {
+		// nothing can be indexed until the index is bootstrapped
+		return nil
+	}
+
+	if height <= i.highest {
+		// execution data was delivered again
+		return nil
+	}
+
+	if height != i.highest+1 {
+		return fmt.Errorf("non-consecutive execution data height %d (highest indexed height: %d), the register index must be bootstrapped again",
+			height, i.highest)
+	}
+
+	// chunks are applied in order, so later chunks overwrite the updates of earlier ones
+	updates := make(map[flow.RegisterID]flow.RegisterValue)
+	for _, chunk := range executionData.ChunkExecutionDatas {
+		if chunk.TrieUpdate == nil {
+			continue
+		}
+		for _, payload := range chunk.TrieUpdate.Payloads {
+			entry, err := payloadToRegisterEntry(payload)
+			if err != nil {
+				return err
+			}
+			updates[entry.Key] = entry.Value
+		}
+	}
+
+	entries := make(flow.RegisterEntries, 0, len(updates))
+	for id, value := range updates {
+		entries = append(entries, flow.RegisterEntry{Key: id, Value: value})
+	}
+
+	batch := bstorage.NewBatch(i.db)
+	err = i.registers.BatchStore(height, entries, batch)
+	if err != nil {
+		return fmt.Errorf("could not add registers to batch: %w", err)
+	}
+
+	err = batch.Flush()
+	if err != nil {
+		return fmt.Errorf("could not store registers: %w", err)
+	}
+
+	err = i.highestHeight.SetProcessedIndex(height)
+	if err != nil {
+		return fmt.Errorf("could not update highest indexed height: %w", err)
+	}
+	i.highest = height
+
+	return nil
+}
+
+// payloadToRegisterEntry converts a ledger payload into a register entry.
+// No errors are expected during normal operation.
+func payloadToRegisterEntry(payload *ledger.Payload) (flow.RegisterEntry, error) {
+	key, err := payload.Key()
+	if err != nil {
+		return flow.RegisterEntry{}, fmt.Errorf("could not decode payload key: %w", err)
+	}
+
+	// register keys consist of the owner and the key part, see state.RegisterIDToKey
+	if len(key.KeyParts) != 2 {
+		return flow.RegisterEntry{}, fmt.Errorf("invalid register key with %d parts", len(key.KeyParts))
+	}
+
+	return flow.RegisterEntry{
+		Key: flow.RegisterID{
+			Owner: string(key.KeyParts[0].Value),
+			Key:   string(key.KeyParts[1].Value),
+		},
+		Value: payload.Value(),
+	}, nil
+}
@@ -0,0 +1,239 @@
+package indexer_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/executiondatasync/execution_data"
+	"github.com/onflow/flow-go/module/state_synchronization/indexer"
+	bstorage "github.com/onflow/flow-go/storage/badger"
+	storagemock "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func payloadFixture(id flow.RegisterID, value flow.RegisterValue) *ledger.Payload {
+	return ledger.NewPayload(state.RegisterIDToKey(id), value)
+}
+
+// registersExecutionDataFixture returns execution data for the given block with one chunk per
+// list of payloads.
+func registersExecutionDataFixture(header *flow.Header, chunks ...[]*ledger.Payload) *execution_data.BlockExecutionDataEntity {
+	chunkDatas := make([]*execution_data.ChunkExecutionData, len(chunks))
+	for i, payloads := range chunks {
+		chunkDatas[i] = &execution_data.ChunkExecutionData{
+			TrieUpdate: &ledger.TrieUpdate{Payloads: payloads},
+		}
+	}
+
+	return execution_data.NewBlockExecutionDataEntity(unittest.IdentifierFixture(), &execution_data.BlockExecutionData{
+		BlockID:             header.ID(),
+		ChunkExecutionDatas: chunkDatas,
+	})
+}
+
+// payloadsReader returns a function reading the given payloads to bootstrap the index.
+func payloadsReader(payloads ...*ledger.Payload) func(func(*ledger.Payload) error) error {
+	return func(processPayload func(*ledger.Payload) error) error {
+		for _, payload := range payloads {
+			err := processPayload(payload)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func newRegistersIndexer(t *testing.T, db *badger.DB, headers *storagemock.Headers) *indexer.RegistersIndexer {
+	registersIndexer, err := indexer.NewRegistersIndexer(
+		zerolog.Nop(),
+		db,
+		headers,
+		bstorage.NewRegisters(db),
+		bstorage.NewConsumerProgress(db, module.ConsumeProgressRegistersIndexerLowestHeight),
+		bstorage.NewConsumerProgress(db, module.ConsumeProgressRegistersIndexerHighestHeight),
+	)
+	require.NoError(t, err)
+	return registersIndexer
+}
+
+// TestRegistersIndexer_Index tests that registers are indexed at each height after bootstrapping,
+// and that the value of a register at a height is the last value set at or below it.
+func TestRegistersIndexer_Index(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		blocks, headers := blockFixtures(t, 3)
+		registersIndexer := newRegistersIndexer(t, db, headers)
+
+		owner := unittest.RandomAddressFixture()
+		reg1 := flow.NewRegisterID(string(owner[:]), "1")
+		reg2 := flow.NewRegisterID(string(owner[:]), "2")
+		reg3 := flow.NewRegisterID("", "3")
+
+		// execution data received before bootstrapping is not indexed
+		registersIndexer.OnExecutionData(registersExecutionDataFixture(blocks[1], []*ledger.Payload{payloadFixture(reg1, []byte("a"))}))
+		_, _, ok := registersIndexer.IndexedHeightRange()
+		assert.False(t, ok)
+
+		err := registersIndexer.Bootstrap(blocks[0].Height, payloadsReader(
+			payloadFixture(reg1, []byte("1")),
+			payloadFixture(reg2, []byte("2")),
+		))
+		require.NoError(t, err)
+
+		lowest, highest, ok := registersIndexer.IndexedHeightRange()
+		require.True(t, ok)
+		assert.Equal(t, blocks[0].Height, lowest)
+		assert.Equal(t, blocks[0].Height, highest)
+
+		// later chunks overwrite the updates of earlier ones
+		registersIndexer.OnExecutionData(registersExecutionDataFixture(blocks[1],
+			[]*ledger.Payload{payloadFixture(reg1, []byte("a")), payloadFixture(reg3, []byte("3"))},
+			[]*ledger.Payload{payloadFixture(reg1, []byte("b"))},
+		))
+		registersIndexer.OnExecutionData(registersExecutionDataFixture(blocks[2],
+			[]*ledger.Payload{payloadFixture(reg2, []byte{})},
+		))
+
+		lowest, highest, ok = registersIndexer.IndexedHeightRange()
+		require.True(t, ok)
+		assert.Equal(t, blocks[0].Height, lowest)
+		assert.Equal(t, blocks[2].Height, highest)
+
+		expected := []map[flow.RegisterID]flow.RegisterValue{
+			{reg1: []byte("1"), reg2: []byte("2"), reg3: nil},
+			{reg1: []byte("b"), reg2: []byte("2"), reg3: []byte("3")},
+			{reg1: []byte("b"), reg2: []byte{}, reg3: []byte("3")},
+		}
+		for i, values := range expected {
+			for id, value := range values {
+				actual, err := registersIndexer.RegisterValue(id, blocks[i].Height)
+				require.NoError(t, err)
+				assert.Equal(t, value, actual, "register %s at height %d", id, blocks[i].Height)
+			}
+		}
+
+		// bootstrapping again is a no-op
+		err = registersIndexer.Bootstrap(blocks[2].Height, payloadsReader())
+		require.NoError(t, err)
+
+		lowest, highest, ok = registersIndexer.IndexedHeightRange()
+		require.True(t, ok)
+		assert.Equal(t, blocks[0].Height, lowest)
+		assert.Equal(t, blocks[2].Height, highest)
+	})
+}
+
+// TestRegistersIndexer_Gap tests that indexing stops when a height is skipped.
+func TestRegistersIndexer_Gap(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		blocks, headers := blockFixtures(t, 3)
+		registersIndexer := newRegistersIndexer(t, db, headers)
+
+		reg := flow.NewRegisterID("", "1")
+		err := registersIndexer.Bootstrap(blocks[0].Height, payloadsReader(payloadFixture(reg, []byte("1"))))
+		require.NoError(t, err)
+
+		registersIndexer.OnExecutionData(registersExecutionDataFixture(blocks[2],
+			[]*ledger.Payload{payloadFixture(reg, []byte("2"))},
+		))
+
+		lowest, highest, ok := registersIndexer.IndexedHeightRange()
+		require.True(t, ok)
+		assert.Equal(t, blocks[0].Height, lowest)
+		assert.Equal(t, blocks[0].Height, highest)
+
+		value, err := registersIndexer.RegisterValue(reg, blocks[2].Height)
+		require.NoError(t, err)
+		assert.Equal(t, flow.RegisterValue("1"), value)
+	})
+}
+
+// TestRegistersIndexer_Restart tests that the indexed height range is restored after a restart.
+func TestRegistersIndexer_Restart(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		blocks, headers := blockFixtures(t, 3)
+		reg := flow.NewRegisterID("", "1")
+
+		registersIndexer := newRegistersIndexer(t, db, headers)
+		err := registersIndexer.Bootstrap(blocks[0].Height, payloadsReader(payloadFixture(reg, []byte("1"))))
+		require.NoError(t, err)
+		registersIndexer.OnExecutionData(registersExecutionDataFixture(blocks[1],
+			[]*ledger.Payload{payloadFixture(reg, []byte("2"))},
+		))
+
+		registersIndexer = newRegistersIndexer(t, db, headers)
+		lowest, highest, ok := registersIndexer.IndexedHeightRange()
+		require.True(t, ok)
+		assert.Equal(t, blocks[0].Height, lowest)
+		assert.Equal(t, blocks[1].Height, highest)
+
+		// indexing continues from the restored range
+		registersIndexer.OnExecutionData(registersExecutionDataFixture(blocks[2],
+			[]*ledger.Payload{payloadFixture(reg, []byte("3"))},
+		))
+		lowest, highest, ok = registersIndexer.IndexedHeightRange()
+		require.True(t, ok)
+		assert.Equal(t, blocks[0].Height, lowest)
+		assert.Equal(t, blocks[2].Height, highest)
+
+		value, err := registersIndexer.RegisterValue(reg, blocks[1].Height)
+		require.NoError(t, err)
+		assert.Equal(t, flow.RegisterValue("2"), value)
+	})
+}
+
+// TestRegistersIndexer_Bootstrap tests that the index is bootstrapped from execution states larger
+// than a batch, and only marked as bootstrapped once all registers are stored.
+func TestRegistersIndexer_Bootstrap(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		blocks, headers := blockFixtures(t, 1)
+		registersIndexer := newRegistersIndexer(t, db, headers)
+
+		payloads := make([]*ledger.Payload, indexer.BootstrapBatchSize+1)
+		for i := range payloads {
+			payloads[i] = payloadFixture(flow.NewRegisterID("", fmt.Sprint(i)), []byte(fmt.Sprint(i)))
+		}
+
+		// a failure while reading the execution state leaves the index unbootstrapped
+		readErr := errors.New("invalid execution state")
+		err := registersIndexer.Bootstrap(blocks[0].Height, func(processPayload func(*ledger.Payload) error) error {
+			err := payloadsReader(payloads...)(processPayload)
+			if err != nil {
+				return err
+			}
+			return readErr
+		})
+		require.ErrorIs(t, err, readErr)
+		_, _, ok := registersIndexer.IndexedHeightRange()
+		require.False(t, ok)
+
+		// and does not survive a restart
+		registersIndexer = newRegistersIndexer(t, db, headers)
+		_, _, ok = registersIndexer.IndexedHeightRange()
+		require.False(t, ok)
+
+		// bootstrapping again stores all registers
+		err = registersIndexer.Bootstrap(blocks[0].Height, payloadsReader(payloads...))
+		require.NoError(t, err)
+		lowest, highest, ok := registersIndexer.IndexedHeightRange()
+		require.True(t, ok)
+		assert.Equal(t, blocks[0].Height, lowest)
+		assert.Equal(t, blocks[0].Height, highest)
+
+		for _, i := range []int{0, indexer.BootstrapBatchSize - 1, indexer.BootstrapBatchSize} {
+			value, err := registersIndexer.RegisterValue(flow.NewRegisterID("", fmt.Sprint(i)), blocks[0].Height)
+			require.NoError(t, err)
+			assert.Equal(t, flow.RegisterValue(fmt.Sprint(i)), value)
+		}
+	})
+}
@@ -52,6 +52,7 @@ type (
 	// Returns ValidationError if the new config value is invalid.
 
 	SetUintConfigFunc           func(uint) error
+	SetFloatConfigFunc          func(float64) error
 	SetBoolConfigFunc           func(bool) error
 	SetDurationConfigFunc       func(time.Duration) error
 	SetIdentifierListConfigFunc func(flow.IdentifierList) error
@@ -59,6 +60,7 @@ type (
 	// Get*ConfigFunc is a getter function for a single updatable config field.
 
 	GetUintConfigFunc           func() uint
+	GetFloatConfigFunc          func() float64
 	GetBoolConfigFunc           func() bool
 	GetDurationConfigFunc       func() time.Duration
 	GetIdentifierListConfigFunc func() flow.IdentifierList
@@ -133,6 +135,9 @@ type Registrar interface {
 	// RegisterUintConfig registers a new uint config.
 	// Returns ErrAlreadyRegistered if a config is already registered with name.
 	RegisterUintConfig(name string, get GetUintConfigFunc, set SetUintConfigFunc) error
+	// RegisterFloatConfig registers a new float config.
+	// Returns ErrAlreadyRegistered if a config is already registered with name.
+	RegisterFloatConfig(name string, get GetFloatConfigFunc, set SetFloatConfigFunc) error
 	// RegisterDurationConfig registers a new duration config.
 	// Returns ErrAlreadyRegistered if a config is already registered with name.
 	RegisterDurationConfig(name string, get GetDurationConfigFunc, set SetDurationConfigFunc) error
@@ -199,6 +204,35 @@ func (m *Manager) RegisterUintConfig(name string, get GetUintConfigFunc, set Set
 	return nil
 }
 
+// RegisterFloatConfig registers a new float config.
+// Setter inputs must be float64-typed values.
+// Returns ErrAlreadyRegistered if a config is already registered with name.
+func (m *Manager) RegisterFloatConfig(name string, get GetFloatConfigFunc, set SetFloatConfigFunc) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.fields[name]; exists {
+		return fmt.Errorf("can't register config %s: %w", name, ErrAlreadyRegistered)
+	}
+
+	field := Field{
+		Name:     name,
+		TypeName: "float",
+		Get: func() any {
+			return get()
+		},
+		Set: func(val any) error {
+			fval, ok := val.(float64) // JSON numbers always parse to float64
+			if !ok {
+				return NewValidationErrorf("invalid type for float config: %T", val)
+			}
+			return set(fval)
+		},
+	}
+	m.fields[field.Name] = field
+	return nil
+}
+
 // RegisterDurationConfig registers a new duration config.
 // Setter inputs must be duration-parseable string-typed values.
 // Returns ErrAlreadyRegistered if a config is already registered with name.
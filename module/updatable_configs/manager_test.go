@@ -86,6 +86,34 @@ func TestManager_RegisterUintConfig(t *testing.T) {
 	assert.True(t, util.CheckClosed(fieldSet))
 }
 
+func TestManager_RegisterFloatConfig(t *testing.T) {
+	mgr := updatable_configs.NewManager()
+
+	// should be able to register config
+	fieldSet := make(chan struct{}) // closed when field is successfully set
+	err := mgr.RegisterFloatConfig("field",
+		func() float64 { return 1.5 },
+		func(_ float64) error { close(fieldSet); return nil })
+	require.NoError(t, err)
+
+	// should be able to get the field
+	field, ok := mgr.GetField("field")
+	assert.True(t, ok)
+	// field must be parseable by structpb (otherwise admin server will error)
+	_, err = structpb.NewValue(field.Get())
+	require.NoError(t, err)
+
+	// should fail to set incorrect type
+	err = field.Set("1.5")
+	assert.Error(t, err)
+	assert.True(t, updatable_configs.IsValidationError(err))
+
+	// should succeed setting correct type
+	err = field.Set(1.5)
+	assert.NoError(t, err)
+	assert.True(t, util.CheckClosed(fieldSet))
+}
+
 func TestManager_RegisterDurationConfig(t *testing.T) {
 	mgr := updatable_configs.NewManager()
 
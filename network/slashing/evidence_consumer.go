@@ -0,0 +1,123 @@
+package slashing
+
+import (
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/logging"
+)
+
+// EvidenceConsumer is a ViolationsConsumer which persists the evidence of violations committed
+// by staked nodes, see storage.SlashingEvidence, before passing them on to the wrapped consumer.
+// Violations of unknown peers are not recorded, as they cannot be attributed to a node.
+//
+// Recording evidence is best-effort: failures are logged, but do not interrupt message processing.
+type EvidenceConsumer struct {
+	ViolationsConsumer
+	log      zerolog.Logger
+	state    protocol.State
+	evidence storage.SlashingEvidence
+}
+
+var _ ViolationsConsumer = (*EvidenceConsumer)(nil)
+
+// NewEvidenceConsumer returns a new EvidenceConsumer wrapping the given consumer.
+func NewEvidenceConsumer(
+	log zerolog.Logger,
+	consumer ViolationsConsumer,
+	state protocol.State,
+	evidence storage.SlashingEvidence,
+) *EvidenceConsumer {
+	return &EvidenceConsumer{
+		ViolationsConsumer: consumer,
+		log:                log.With().Str("module", "network_slashing_evidence").Logger(),
+		state:              state,
+		evidence:           evidence,
+	}
+}
+
+// OnUnAuthorizedSenderError records the evidence of an unauthorized sender error.
+func (c *EvidenceConsumer) OnUnAuthorizedSenderError(violation *Violation) {
+	c.record(unAuthorizedSenderViolation, violation)
+	c.ViolationsConsumer.OnUnAuthorizedSenderError(violation)
+}
+
+// OnUnknownMsgTypeError records the evidence of an unknown message type error.
+func (c *EvidenceConsumer) OnUnknownMsgTypeError(violation *Violation) {
+	c.record(unknownMsgTypeViolation, violation)
+	c.ViolationsConsumer.OnUnknownMsgTypeError(violation)
+}
+
+// OnInvalidMsgError records the evidence of a message that could not be unmarshalled.
+func (c *EvidenceConsumer) OnInvalidMsgError(violation *Violation) {
+	c.record(invalidMsgViolation, violation)
+	c.ViolationsConsumer.OnInvalidMsgError(violation)
+}
+
+// OnSenderEjectedError records the evidence of a message sent by an ejected node.
+func (c *EvidenceConsumer) OnSenderEjectedError(violation *Violation) {
+	c.record(senderEjectedViolation, violation)
+	c.ViolationsConsumer.OnSenderEjectedError(violation)
+}
+
+// OnUnauthorizedUnicastOnChannel records the evidence of a message unauthorized to be sent via unicast.
+func (c *EvidenceConsumer) OnUnauthorizedUnicastOnChannel(violation *Violation) {
+	c.record(unauthorizedUnicastOnChannel, violation)
+	c.ViolationsConsumer.OnUnauthorizedUnicastOnChannel(violation)
+}
+
+// OnUnexpectedError records the evidence of a message that failed validation for an unknown reason.
+func (c *EvidenceConsumer) OnUnexpectedError(violation *Violation) {
+	c.record(unExpectedValidationError, violation)
+	c.ViolationsConsumer.OnUnexpectedError(violation)
+}
+
+func (c *EvidenceConsumer) record(networkOffense string, violation *Violation) {
+	if violation.Identity == nil {
+		return
+	}
+
+	evidence := &flow.SlashingEvidence{
+		Offense:    flow.OffenseNetworkViolation,
+		OffenderID: violation.Identity.NodeID,
+		Network: &flow.NetworkViolation{
+			PeerID:      violation.PeerID,
+			Channel:     violation.Channel.String(),
+			Protocol:    violation.Protocol.String(),
+			MessageType: violation.MsgType,
+		},
+		Offender:   violation.Identity,
+		Reason:     networkOffense,
+		DetectedAt: time.Now().UTC(),
+	}
+	if violation.Err != nil {
+		evidence.Reason = networkOffense + ": " + violation.Err.Error()
+	}
+
+	log := c.log.With().
+		Str("networking_offense", networkOffense).
+		Hex("offender_id", logging.ID(evidence.OffenderID)).
+		Logger()
+
+	epochCounter, err := c.state.Final().Epochs().Current().Counter()
+	if err != nil {
+		log.Warn().Err(err).Msg("could not determine current epoch")
+	} else {
+		evidence.EpochCounter = epochCounter
+	}
+
+	stored, err := c.evidence.Store(evidence)
+	if err != nil {
+		log.Error().Err(err).Msg("could not store slashing evidence")
+		return
+	}
+	if stored {
+		log.Info().
+			Hex("evidence_id", logging.ID(evidence.ID())).
+			Msg("recorded slashing evidence")
+	}
+}
@@ -84,6 +84,16 @@ const (
 	codeJobQueue             = 71
 	codeJobQueuePointer      = 72
 
+	// register values indexed by height, used for local script execution on access nodes
+	codeRegister = 80
+
+	// transactions an access node resubmits to collection nodes, indexed by reference block height
+	codeTransactionRetry = 81
+
+	// evidence of slashable offenses, keyed by evidence ID and indexed by offender
+	codeSlashingEvidence           = 82
+	codeSlashingEvidenceByOffender = 83
+
 	// legacy codes (should be cleaned up)
 	codeChunkDataPack                = 100
 	codeCommit                       = 101
@@ -102,6 +112,7 @@ const (
 	blockedNodeIDs = 205 // manual override for adding node IDs to list of ejected nodes, applies to networking layer only
 
 	// internal failure information that should be preserved across restarts
+	codeStopBoundary                    = 253 // version boundary at which the execution node stops
 	codeExecutionFork                   = 254
 	codeEpochEmergencyFallbackTriggered = 255
 )
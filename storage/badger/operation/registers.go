@@ -0,0 +1,63 @@
+package operation
+
+import (
+	"github.com/dgraph-io/badger/v2"
+	"github.com/vmihailenco/msgpack/v4"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/irrecoverable"
+	"github.com/onflow/flow-go/storage"
+)
+
+// registerPrefix returns the prefix shared by all values of the given register. Owner and key are
+// length-prefixed, so that the prefix of one register is never a prefix of another register.
+func registerPrefix(id flow.RegisterID) []byte {
+	return makePrefix(codeRegister, uint32(len(id.Owner)), id.Owner, uint32(len(id.Key)), id.Key)
+}
+
+// registerKey returns the key of the register value at the given height. The height is stored
+// inverted, so that iterating the register's prefix returns the values from the highest to the
+// lowest height.
+func registerKey(id flow.RegisterID, height uint64) []byte {
+	return append(registerPrefix(id), b(^height)...)
+}
+
+// BatchInsertRegister stores the value the register was set to at the given height.
+// No errors are expected during normal operation.
+func BatchInsertRegister(id flow.RegisterID, height uint64, value flow.RegisterValue) func(*badger.WriteBatch) error {
+	return batchWrite(registerKey(id, height), value)
+}
+
+// LookupRegisterAtHeight retrieves the value of the register at the given height, which is the
+// value stored at the highest height lower than or equal to the given height.
+// Error returns:
+//   - storage.ErrNotFound if no value was stored for the register at or below the height
+//   - generic error in case of unexpected failure from the database layer, or failure
+//     to decode an existing database value
+func LookupRegisterAtHeight(id flow.RegisterID, height uint64, value *flow.RegisterValue) func(*badger.Txn) error {
+	return func(tx *badger.Txn) error {
+		prefix := registerPrefix(id)
+
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = prefix
+		opts.PrefetchValues = false
+
+		it := tx.NewIterator(opts)
+		defer it.Close()
+
+		// the first key at or after the seek key is the value at the highest height <= height
+		it.Seek(registerKey(id, height))
+		if !it.ValidForPrefix(prefix) {
+			return storage.ErrNotFound
+		}
+
+		err := it.Item().Value(func(val []byte) error {
+			return msgpack.Unmarshal(val, value)
+		})
+		if err != nil {
+			return irrecoverable.NewExceptionf("could not decode register value: %w", err)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,49 @@
+package operation
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestLookupRegisterAtHeight tests that register lookups return the value stored at the highest
+// height lower than or equal to the requested height.
+func TestLookupRegisterAtHeight(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		owner := string(unittest.RandomAddressFixture().Bytes())
+		id := flow.NewRegisterID(owner, "key")
+
+		// a register whose key has the key of the first register as prefix
+		other := flow.NewRegisterID(owner, "key2")
+
+		writeBatch := db.NewWriteBatch()
+		require.NoError(t, BatchInsertRegister(id, 10, []byte("v10"))(writeBatch))
+		require.NoError(t, BatchInsertRegister(id, 20, []byte("v20"))(writeBatch))
+		require.NoError(t, BatchInsertRegister(other, 5, []byte("other"))(writeBatch))
+		require.NoError(t, writeBatch.Flush())
+
+		var value flow.RegisterValue
+
+		err := db.View(LookupRegisterAtHeight(id, 9, &value))
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+
+		expected := map[uint64]string{10: "v10", 15: "v10", 20: "v20", 100: "v20"}
+		for height, v := range expected {
+			err = db.View(LookupRegisterAtHeight(id, height, &value))
+			require.NoError(t, err)
+			assert.Equal(t, []byte(v), value, "height %d", height)
+		}
+
+		err = db.View(LookupRegisterAtHeight(other, 4, &value))
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+
+		err = db.View(LookupRegisterAtHeight(flow.NewRegisterID(owner, "unknown"), 20, &value))
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+	})
+}
@@ -0,0 +1,57 @@
+package operation
+
+import (
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// InsertSlashingEvidence inserts the evidence of a slashable offense, keyed by its ID.
+// Expected errors during normal operations:
+//   - storage.ErrAlreadyExists if evidence with the given ID is already stored
+func InsertSlashingEvidence(evidenceID flow.Identifier, evidence *flow.SlashingEvidence) func(*badger.Txn) error {
+	return insert(makePrefix(codeSlashingEvidence, evidenceID), evidence)
+}
+
+// RetrieveSlashingEvidence retrieves the evidence of a slashable offense by its ID.
+// Expected errors during normal operations:
+//   - storage.ErrNotFound if no evidence with the given ID is stored
+func RetrieveSlashingEvidence(evidenceID flow.Identifier, evidence *flow.SlashingEvidence) func(*badger.Txn) error {
+	return retrieve(makePrefix(codeSlashingEvidence, evidenceID), evidence)
+}
+
+// IndexSlashingEvidenceByOffender indexes the evidence with the given ID by its offender.
+// The key looks like: <prefix 0:1><offender_id 1:33><evidence_id 33:65>
+// Expected errors during normal operations:
+//   - storage.ErrAlreadyExists if the evidence is already indexed
+func IndexSlashingEvidenceByOffender(offenderID flow.Identifier, evidenceID flow.Identifier) func(*badger.Txn) error {
+	return insert(makePrefix(codeSlashingEvidenceByOffender, offenderID, evidenceID), evidenceID)
+}
+
+// LookupSlashingEvidenceByOffender retrieves the IDs of all evidence recorded for the given offender.
+// No errors are expected during normal operations.
+func LookupSlashingEvidenceByOffender(offenderID flow.Identifier, evidenceIDs *[]flow.Identifier) func(*badger.Txn) error {
+	return traverse(makePrefix(codeSlashingEvidenceByOffender, offenderID), lookup(evidenceIDs))
+}
+
+// RetrieveAllSlashingEvidence retrieves all stored evidence of slashable offenses, ordered by ID.
+// No errors are expected during normal operations.
+func RetrieveAllSlashingEvidence(evidence *[]*flow.SlashingEvidence) func(*badger.Txn) error {
+	return traverse(makePrefix(codeSlashingEvidence), func() (checkFunc, createFunc, handleFunc) {
+		check := func(key []byte) bool {
+			return true
+		}
+
+		var current *flow.SlashingEvidence
+		create := func() interface{} {
+			current = &flow.SlashingEvidence{}
+			return current
+		}
+
+		handle := func() error {
+			*evidence = append(*evidence, current)
+			return nil
+		}
+		return check, create, handle
+	})
+}
@@ -0,0 +1,60 @@
+package operation
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+// stopBoundary is the stored version boundary, together with whether the node crashes at the
+// boundary instead of pausing.
+type stopBoundary struct {
+	BlockHeight uint64
+	Version     string
+	Crash       bool
+}
+
+// UpsertStopBoundary writes the version boundary at which an execution node stops, and whether it
+// crashes at the boundary, into the data base.
+// If an entry already exists, it is overwritten; otherwise a new entry is created.
+// No errors are expected during normal operations.
+func UpsertStopBoundary(boundary flow.VersionBoundary, crash bool) func(*badger.Txn) error {
+	return upsert(makePrefix(codeStopBoundary), stopBoundary{
+		BlockHeight: boundary.BlockHeight,
+		Version:     boundary.Version,
+		Crash:       crash,
+	})
+}
+
+// RetrieveStopBoundary reads the version boundary at which an execution node stops, and whether it
+// crashes at the boundary, from the data base.
+// Returns `storage.ErrNotFound` error in case no respective data base entry is present.
+func RetrieveStopBoundary(boundary *flow.VersionBoundary, crash *bool) func(*badger.Txn) error {
+	return func(tx *badger.Txn) error {
+		var stored stopBoundary
+		err := retrieve(makePrefix(codeStopBoundary), &stored)(tx)
+		if err != nil {
+			return err
+		}
+		*boundary = flow.VersionBoundary{BlockHeight: stored.BlockHeight, Version: stored.Version}
+		*crash = stored.Crash
+		return nil
+	}
+}
+
+// RemoveStopBoundary removes the version boundary at which an execution node stops from the data base.
+// If no corresponding entry exists, this function is a no-op.
+// No errors are expected during normal operations.
+func RemoveStopBoundary() func(*badger.Txn) error {
+	return func(tx *badger.Txn) error {
+		err := remove(makePrefix(codeStopBoundary))(tx)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return fmt.Errorf("unexpected error while removing stop boundary: %w", err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,65 @@
+package operation
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// Test_StopBoundary tests the operations:
+//   - UpsertStopBoundary(boundary flow.VersionBoundary, crash bool)
+//   - RetrieveStopBoundary(boundary *flow.VersionBoundary, crash *bool)
+//   - RemoveStopBoundary()
+func Test_StopBoundary(t *testing.T) {
+	t.Run("Retrieving non-existing boundary should return 'storage.ErrNotFound'", func(t *testing.T) {
+		unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+			var boundary flow.VersionBoundary
+			var crash bool
+			err := db.View(RetrieveStopBoundary(&boundary, &crash))
+			require.ErrorIs(t, err, storage.ErrNotFound)
+		})
+	})
+
+	t.Run("Upsert and retrieve boundary", func(t *testing.T) {
+		unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+			expected := flow.VersionBoundary{BlockHeight: 100, Version: "v0.31.0"}
+			err := db.Update(UpsertStopBoundary(expected, false))
+			require.NoError(t, err)
+
+			updated := flow.VersionBoundary{BlockHeight: 200, Version: "v0.32.0"}
+			err = db.Update(UpsertStopBoundary(updated, true))
+			require.NoError(t, err)
+
+			var boundary flow.VersionBoundary
+			var crash bool
+			err = db.View(RetrieveStopBoundary(&boundary, &crash))
+			require.NoError(t, err)
+			require.Equal(t, updated, boundary)
+			require.True(t, crash)
+		})
+	})
+
+	t.Run("Remove boundary", func(t *testing.T) {
+		unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+			// removing a non-existing boundary is a no-op
+			err := db.Update(RemoveStopBoundary())
+			require.NoError(t, err)
+
+			err = db.Update(UpsertStopBoundary(flow.VersionBoundary{BlockHeight: 100, Version: "v0.31.0"}, false))
+			require.NoError(t, err)
+
+			err = db.Update(RemoveStopBoundary())
+			require.NoError(t, err)
+
+			var boundary flow.VersionBoundary
+			var crash bool
+			err = db.View(RetrieveStopBoundary(&boundary, &crash))
+			require.ErrorIs(t, err, storage.ErrNotFound)
+		})
+	})
+}
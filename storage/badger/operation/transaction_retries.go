@@ -0,0 +1,51 @@
+package operation
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+// IndexTransactionRetry adds the transaction with the given ID to the transactions an access node
+// resubmits, indexed by the height of its reference block. Indexing a transaction twice is a no-op.
+// The key looks like: <prefix 0:1><ref_height 1:9><tx_id 9:41>
+// No errors are expected during normal operations.
+func IndexTransactionRetry(refHeight uint64, txID flow.Identifier) func(*badger.Txn) error {
+	return upsert(makePrefix(codeTransactionRetry, refHeight, txID), nil)
+}
+
+// RemoveTransactionRetry removes the transaction with the given ID from the transactions an access
+// node resubmits. If no corresponding entry exists, this function is a no-op.
+// No errors are expected during normal operations.
+func RemoveTransactionRetry(refHeight uint64, txID flow.Identifier) func(*badger.Txn) error {
+	return func(tx *badger.Txn) error {
+		err := remove(makePrefix(codeTransactionRetry, refHeight, txID))(tx)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return fmt.Errorf("unexpected error while removing transaction retry: %w", err)
+		}
+		return nil
+	}
+}
+
+// LookupTransactionRetries retrieves the IDs of all transactions an access node resubmits, by the
+// height of their reference block.
+// No errors are expected during normal operations.
+func LookupTransactionRetries(txIDs map[uint64][]flow.Identifier) func(*badger.Txn) error {
+	return traverse(makePrefix(codeTransactionRetry), func() (checkFunc, createFunc, handleFunc) {
+		check := func(key []byte) bool {
+			refHeight := binary.BigEndian.Uint64(key[1:9])
+			var txID flow.Identifier
+			copy(txID[:], key[9:])
+			txIDs[refHeight] = append(txIDs[refHeight], txID)
+
+			// the info we need is stored in the key, never process the value
+			return false
+		}
+		return check, nil, nil
+	})
+}
@@ -0,0 +1,51 @@
+package badger
+
+import (
+	"fmt"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+// Registers stores register values by the height at which they were updated.
+// Values are not cached, since the same register is rarely read at the same height repeatedly.
+type Registers struct {
+	db *badger.DB
+}
+
+var _ storage.Registers = (*Registers)(nil)
+
+func NewRegisters(db *badger.DB) *Registers {
+	return &Registers{
+		db: db,
+	}
+}
+
+// BatchStore stores the register values which were updated at the given height in provided batch
+// No errors are expected during normal operation, but it may return generic error
+// if badger fails to process request
+func (r *Registers) BatchStore(height uint64, entries flow.RegisterEntries, batch storage.BatchStorage) error {
+	writeBatch := batch.GetWriter()
+	for _, entry := range entries {
+		err := operation.BatchInsertRegister(entry.Key, height, entry.Value)(writeBatch)
+		if err != nil {
+			return fmt.Errorf("cannot batch insert register: %w", err)
+		}
+	}
+	return nil
+}
+
+// Get returns the value of the register at the given height.
+// Expected errors:
+//   - storage.ErrNotFound if the register was not updated at or below the height
+func (r *Registers) Get(id flow.RegisterID, height uint64) (flow.RegisterValue, error) {
+	var value flow.RegisterValue
+	err := r.db.View(operation.LookupRegisterAtHeight(id, height, &value))
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
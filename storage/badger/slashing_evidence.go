@@ -0,0 +1,98 @@
+package badger
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+var _ storage.SlashingEvidence = (*SlashingEvidence)(nil)
+
+type SlashingEvidence struct {
+	db *badger.DB
+}
+
+func NewSlashingEvidence(db *badger.DB) *SlashingEvidence {
+	return &SlashingEvidence{
+		db: db,
+	}
+}
+
+func (s *SlashingEvidence) Store(evidence *flow.SlashingEvidence) (bool, error) {
+	evidenceID := evidence.ID()
+
+	var stored bool
+	err := operation.RetryOnConflict(s.db.Update, func(tx *badger.Txn) error {
+		stored = false
+
+		err := operation.InsertSlashingEvidence(evidenceID, evidence)(tx)
+		if errors.Is(err, storage.ErrAlreadyExists) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("could not insert slashing evidence: %w", err)
+		}
+
+		err = operation.IndexSlashingEvidenceByOffender(evidence.OffenderID, evidenceID)(tx)
+		if err != nil {
+			return fmt.Errorf("could not index slashing evidence by offender: %w", err)
+		}
+
+		stored = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return stored, nil
+}
+
+func (s *SlashingEvidence) ByID(evidenceID flow.Identifier) (*flow.SlashingEvidence, error) {
+	var evidence flow.SlashingEvidence
+	err := s.db.View(operation.RetrieveSlashingEvidence(evidenceID, &evidence))
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve slashing evidence %v: %w", evidenceID, err)
+	}
+	return &evidence, nil
+}
+
+func (s *SlashingEvidence) ByOffender(offenderID flow.Identifier) ([]*flow.SlashingEvidence, error) {
+	result := make([]*flow.SlashingEvidence, 0)
+	err := s.db.View(func(tx *badger.Txn) error {
+		var evidenceIDs []flow.Identifier
+		err := operation.LookupSlashingEvidenceByOffender(offenderID, &evidenceIDs)(tx)
+		if err != nil {
+			return fmt.Errorf("could not look up slashing evidence by offender: %w", err)
+		}
+
+		for _, evidenceID := range evidenceIDs {
+			var evidence flow.SlashingEvidence
+			err = operation.RetrieveSlashingEvidence(evidenceID, &evidence)(tx)
+			if err != nil {
+				return fmt.Errorf("could not retrieve slashing evidence %v: %w", evidenceID, err)
+			}
+			result = append(result, &evidence)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (s *SlashingEvidence) All() ([]*flow.SlashingEvidence, error) {
+	result := make([]*flow.SlashingEvidence, 0)
+	err := s.db.View(operation.RetrieveAllSlashingEvidence(&result))
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve slashing evidence: %w", err)
+	}
+	return result, nil
+}
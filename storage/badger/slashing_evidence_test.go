@@ -0,0 +1,111 @@
+package badger_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/unittest"
+
+	badgerstorage "github.com/onflow/flow-go/storage/badger"
+)
+
+func TestSlashingEvidenceStoreAndRetrieve(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		store := badgerstorage.NewSlashingEvidence(db)
+
+		evidence := unittest.SlashingEvidenceFixture()
+		stored, err := store.Store(evidence)
+		require.NoError(t, err)
+		assert.True(t, stored)
+
+		actual, err := store.ByID(evidence.ID())
+		require.NoError(t, err)
+		assert.Equal(t, evidence.ID(), actual.ID())
+		assert.Equal(t, evidence.Messages, actual.Messages)
+		assert.True(t, evidence.DetectedAt.Equal(actual.DetectedAt))
+
+		_, err = store.ByID(unittest.IdentifierFixture())
+		assert.True(t, errors.Is(err, storage.ErrNotFound))
+	})
+}
+
+func TestSlashingEvidenceDeduplication(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		store := badgerstorage.NewSlashingEvidence(db)
+
+		evidence := unittest.SlashingEvidenceFixture()
+		stored, err := store.Store(evidence)
+		require.NoError(t, err)
+		assert.True(t, stored)
+
+		// the same offense detected again later, with the conflicting messages in the
+		// reverse order, is the same evidence
+		duplicate := *evidence
+		duplicate.Messages = []flow.SignedConsensusMessage{evidence.Messages[1], evidence.Messages[0]}
+		duplicate.DetectedAt = evidence.DetectedAt.Add(time.Minute)
+		require.Equal(t, evidence.ID(), duplicate.ID())
+
+		stored, err = store.Store(&duplicate)
+		require.NoError(t, err)
+		assert.False(t, stored)
+
+		all, err := store.All()
+		require.NoError(t, err)
+		require.Len(t, all, 1)
+		assert.True(t, evidence.DetectedAt.Equal(all[0].DetectedAt))
+
+		byOffender, err := store.ByOffender(evidence.OffenderID)
+		require.NoError(t, err)
+		require.Len(t, byOffender, 1)
+	})
+}
+
+func TestSlashingEvidenceByOffender(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		store := badgerstorage.NewSlashingEvidence(db)
+
+		offenderID := unittest.IdentifierFixture()
+		withOffender := func(evidence *flow.SlashingEvidence) {
+			evidence.OffenderID = offenderID
+		}
+
+		evidence1 := unittest.SlashingEvidenceFixture(withOffender)
+		evidence2 := unittest.SlashingEvidenceFixture(withOffender, func(evidence *flow.SlashingEvidence) {
+			evidence.Offense = flow.OffenseNetworkViolation
+			evidence.Messages = nil
+			evidence.Network = &flow.NetworkViolation{
+				PeerID:      "peer",
+				Channel:     "test-channel",
+				MessageType: "Proposal",
+			}
+		})
+		other := unittest.SlashingEvidenceFixture()
+
+		for _, evidence := range []*flow.SlashingEvidence{evidence1, evidence2, other} {
+			_, err := store.Store(evidence)
+			require.NoError(t, err)
+		}
+
+		byOffender, err := store.ByOffender(offenderID)
+		require.NoError(t, err)
+		require.Len(t, byOffender, 2)
+		assert.ElementsMatch(t,
+			[]flow.Identifier{evidence1.ID(), evidence2.ID()},
+			[]flow.Identifier{byOffender[0].ID(), byOffender[1].ID()})
+
+		byOffender, err = store.ByOffender(unittest.IdentifierFixture())
+		require.NoError(t, err)
+		assert.Empty(t, byOffender)
+
+		all, err := store.All()
+		require.NoError(t, err)
+		assert.Len(t, all, 3)
+	})
+}
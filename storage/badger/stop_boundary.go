@@ -0,0 +1,36 @@
+package badger
+
+import (
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+var _ storage.StopBoundary = (*StopBoundary)(nil)
+
+type StopBoundary struct {
+	db *badger.DB
+}
+
+func NewStopBoundary(db *badger.DB) *StopBoundary {
+	return &StopBoundary{
+		db: db,
+	}
+}
+
+func (s *StopBoundary) Store(boundary flow.VersionBoundary, crash bool) error {
+	return operation.RetryOnConflict(s.db.Update, operation.UpsertStopBoundary(boundary, crash))
+}
+
+func (s *StopBoundary) Retrieve() (flow.VersionBoundary, bool, error) {
+	var boundary flow.VersionBoundary
+	var crash bool
+	err := s.db.View(operation.RetrieveStopBoundary(&boundary, &crash))
+	return boundary, crash, err
+}
+
+func (s *StopBoundary) Remove() error {
+	return operation.RetryOnConflict(s.db.Update, operation.RemoveStopBoundary())
+}
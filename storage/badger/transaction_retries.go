@@ -0,0 +1,35 @@
+package badger
+
+import (
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+var _ storage.TransactionRetries = (*TransactionRetries)(nil)
+
+type TransactionRetries struct {
+	db *badger.DB
+}
+
+func NewTransactionRetries(db *badger.DB) *TransactionRetries {
+	return &TransactionRetries{
+		db: db,
+	}
+}
+
+func (t *TransactionRetries) Add(refHeight uint64, txID flow.Identifier) error {
+	return operation.RetryOnConflict(t.db.Update, operation.IndexTransactionRetry(refHeight, txID))
+}
+
+func (t *TransactionRetries) Remove(refHeight uint64, txID flow.Identifier) error {
+	return operation.RetryOnConflict(t.db.Update, operation.RemoveTransactionRetry(refHeight, txID))
+}
+
+func (t *TransactionRetries) All() (map[uint64][]flow.Identifier, error) {
+	txIDs := make(map[uint64][]flow.Identifier)
+	err := t.db.View(operation.LookupTransactionRetries(txIDs))
+	return txIDs, err
+}
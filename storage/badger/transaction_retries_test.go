@@ -0,0 +1,57 @@
+package badger_test
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+
+	badgerstorage "github.com/onflow/flow-go/storage/badger"
+)
+
+func TestTransactionRetriesAddRemove(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		store := badgerstorage.NewTransactionRetries(db)
+
+		txID1 := unittest.IdentifierFixture()
+		txID2 := unittest.IdentifierFixture()
+		txID3 := unittest.IdentifierFixture()
+
+		require.NoError(t, store.Add(10, txID1))
+		require.NoError(t, store.Add(10, txID2))
+		require.NoError(t, store.Add(11, txID3))
+
+		// re-adding a transaction should be idempotent
+		require.NoError(t, store.Add(10, txID1))
+
+		all, err := store.All()
+		require.NoError(t, err)
+		require.Len(t, all, 2)
+		assert.ElementsMatch(t, []flow.Identifier{txID1, txID2}, all[10])
+		assert.Equal(t, []flow.Identifier{txID3}, all[11])
+
+		require.NoError(t, store.Remove(10, txID1))
+		require.NoError(t, store.Remove(11, txID3))
+
+		// removing a transaction which is not stored should be a no-op
+		require.NoError(t, store.Remove(12, txID1))
+
+		all, err = store.All()
+		require.NoError(t, err)
+		assert.Equal(t, map[uint64][]flow.Identifier{10: {txID2}}, all)
+	})
+}
+
+func TestTransactionRetriesEmpty(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		store := badgerstorage.NewTransactionRetries(db)
+
+		all, err := store.All()
+		require.NoError(t, err)
+		assert.Empty(t, all)
+	})
+}
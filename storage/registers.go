@@ -0,0 +1,18 @@
+package storage
+
+import (
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// Registers represents persistent storage for register values indexed by block height.
+type Registers interface {
+
+	// BatchStore will store the register values which were updated at the given height in a given batch
+	BatchStore(height uint64, entries flow.RegisterEntries, batch BatchStorage) error
+
+	// Get returns the value of the register at the given height, which is the value it was last
+	// updated to at or below the height.
+	// Expected errors:
+	//   - storage.ErrNotFound if the register was not updated at or below the height
+	Get(id flow.RegisterID, height uint64) (flow.RegisterValue, error)
+}
@@ -0,0 +1,27 @@
+package storage
+
+import (
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// SlashingEvidence persists the evidence of slashable offenses detected by a node, so it can be
+// exported after an incident. Evidence is deduplicated by its ID.
+type SlashingEvidence interface {
+	// Store stores the evidence, and indexes it by its offender. Storing evidence with the ID of
+	// already stored evidence is a no-op, and returns false.
+	// No errors are expected during normal operations.
+	Store(evidence *flow.SlashingEvidence) (bool, error)
+
+	// ByID returns the evidence with the given ID.
+	// Expected errors during normal operations:
+	//   - storage.ErrNotFound if no evidence with the given ID is stored
+	ByID(evidenceID flow.Identifier) (*flow.SlashingEvidence, error)
+
+	// ByOffender returns all evidence recorded for the given offender.
+	// No errors are expected during normal operations.
+	ByOffender(offenderID flow.Identifier) ([]*flow.SlashingEvidence, error)
+
+	// All returns all stored evidence.
+	// No errors are expected during normal operations.
+	All() ([]*flow.SlashingEvidence, error)
+}
@@ -0,0 +1,22 @@
+package storage
+
+import (
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// StopBoundary persists the version boundary at which an execution node stops executing blocks,
+// and whether the node crashes or pauses at the boundary, so that the node does not execute beyond
+// the boundary after a restart.
+type StopBoundary interface {
+	// Store stores the boundary and crash mode, replacing the previously stored boundary if any.
+	// No errors are expected during normal operations.
+	Store(boundary flow.VersionBoundary, crash bool) error
+
+	// Retrieve returns the stored boundary and crash mode.
+	// Returns storage.ErrNotFound if no boundary is stored.
+	Retrieve() (flow.VersionBoundary, bool, error)
+
+	// Remove removes the stored boundary. It is a no-op if no boundary is stored.
+	// No errors are expected during normal operations.
+	Remove() error
+}
@@ -0,0 +1,25 @@
+package storage
+
+import (
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// TransactionRetries persists the transactions an access node resubmits to collection nodes until
+// they are included in a block or expire, so that they are still resubmitted after a restart. The
+// transactions are indexed by the height of their reference block. The transaction bodies are
+// stored separately, see Transactions.
+type TransactionRetries interface {
+	// Add adds the transaction with the given ID and reference block height. Adding a transaction
+	// twice is a no-op.
+	// No errors are expected during normal operations.
+	Add(refHeight uint64, txID flow.Identifier) error
+
+	// Remove removes the transaction with the given ID and reference block height. It is a no-op
+	// if the transaction is not stored.
+	// No errors are expected during normal operations.
+	Remove(refHeight uint64, txID flow.Identifier) error
+
+	// All returns the IDs of all stored transactions, by reference block height.
+	// No errors are expected during normal operations.
+	All() (map[uint64][]flow.Identifier, error)
+}
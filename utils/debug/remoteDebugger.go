@@ -2,9 +2,12 @@ package debug
 
 import (
 	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime"
 	"github.com/rs/zerolog"
 
 	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/environment"
+	reusableRuntime "github.com/onflow/flow-go/fvm/runtime"
 	"github.com/onflow/flow-go/model/flow"
 )
 
@@ -90,6 +93,54 @@ func (d *RemoteDebugger) RunTransactionAtBlockID(
 	return output.Err, nil
 }
 
+// RunTransactionWithTrace runs the transaction like RunTransactionAtBlockID,
+// and returns the trace of its execution: every register read and written,
+// every function called, every event emitted and the meter usage at each of
+// these steps. If blockID is flow.ZeroID, the latest sealed block is used.
+func (d *RemoteDebugger) RunTransactionWithTrace(
+	txBody *flow.TransactionBody,
+	blockID flow.Identifier,
+	regCachePath string,
+) (
+	trace *environment.ExecutionTrace,
+	txErr error,
+	processError error,
+) {
+	var opts []RemoteStorageSnapshotOption
+	if blockID != flow.ZeroID {
+		opts = append(opts, WithBlockID(blockID))
+	}
+	snapshot := NewRemoteStorageSnapshot(d.grpcAddress, opts...)
+	defer snapshot.Close()
+
+	if len(regCachePath) > 0 {
+		snapshot.Cache = newFileRegisterCache(regCachePath)
+	}
+
+	trace = environment.NewExecutionTrace()
+	blockCtx := fvm.NewContextFromParent(
+		d.ctx,
+		fvm.WithBlockHeader(d.ctx.BlockHeader),
+		fvm.WithReusableCadenceRuntimePool(
+			reusableRuntime.NewReusableCadenceRuntimePool(
+				0,
+				runtime.Config{
+					// needed to trace function calls
+					TracingEnabled: true,
+				})),
+		fvm.WithExecutionTraceCollector(trace))
+	tx := fvm.Transaction(txBody, 0)
+	_, output, err := d.vm.Run(blockCtx, tx, snapshot)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = snapshot.Cache.Persist()
+	if err != nil {
+		return nil, nil, err
+	}
+	return trace, output.Err, nil
+}
+
 func (d *RemoteDebugger) RunScript(
 	code []byte,
 	arguments [][]byte,
@@ -2186,3 +2186,38 @@ func GetFlowProtocolEventID(t *testing.T, channel channels.Channel, event interf
 	require.NoError(t, err)
 	return flow.HashToID(eventIDHash)
 }
+
+// SlashingEvidenceFixture returns evidence of a double vote, without the identity and keys of
+// the offender.
+func SlashingEvidenceFixture(opts ...func(*flow.SlashingEvidence)) *flow.SlashingEvidence {
+	offenderID := IdentifierFixture()
+	view := uint64(rand.Uint32())
+	evidence := &flow.SlashingEvidence{
+		Offense:      flow.OffenseDoubleVote,
+		OffenderID:   offenderID,
+		ChainID:      flow.Emulator,
+		EpochCounter: rand.Uint64(),
+		View:         view,
+		Messages: []flow.SignedConsensusMessage{
+			{
+				Type:     flow.SignedMessageVote,
+				SignerID: offenderID,
+				View:     view,
+				BlockID:  IdentifierFixture(),
+				SigData:  SignatureFixture(),
+			},
+			{
+				Type:     flow.SignedMessageVote,
+				SignerID: offenderID,
+				View:     view,
+				BlockID:  IdentifierFixture(),
+				SigData:  SignatureFixture(),
+			},
+		},
+		DetectedAt: time.Now().UTC(),
+	}
+	for _, apply := range opts {
+		apply(evidence)
+	}
+	return evidence
+}